@@ -15,18 +15,22 @@ type Service struct {
 type PlaidService interface {
 	GetSandboxToken(ctx context.Context) (plaid.ItemPublicTokenExchangeResponse, error)
 	CreateSandboxTokenWithCustomUser(ctx context.Context) (plaid.ItemPublicTokenExchangeResponse, error)
-	GetLinkToken(ctx context.Context, userID, webhookURL string) (string, error)
-	GetLinkTokenForUpdateMode(ctx context.Context, userID, accessToken, webhookURL string) (string, error)
-	GetAccessToken(ctx context.Context, publicToken string) (models.AccessResponse, error)
+	GetLinkToken(ctx context.Context, userID, webhookURL string, options LinkOptions) (string, error)
+	GetLinkTokenForUpdateMode(ctx context.Context, userID, accessToken, webhookURL string, options LinkOptions) (string, error)
+	GetAccessToken(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error)
 	InvalidateAccessToken(ctx context.Context, accessToken models.AccessResponse) (models.AccessResponse, error)
 	GetAccounts(ctx context.Context, accessToken string) ([]plaid.AccountBase, string, error)
-	GetItemInstitution(ctx context.Context, accessToken string) (string, error)
+	GetItemInstitution(ctx context.Context, accessToken string, countryCodes []plaid.CountryCode) (name, countryCode string, err error)
 	GetBalances(ctx context.Context, accessToken string) (plaid.AccountsGetResponse, string, error)
 	GetTransactions(ctx context.Context, accessToken, cursor string) (
 		added, modified []plaid.Transaction, removed []plaid.RemovedTransaction, nextCursor, reqID string, err error)
 	GetWebhookVerificationKey(ctx context.Context, keyID string) (plaid.JWKPublicKey, error)
 	RemoveItem(ctx context.Context, accessToken string) error
 	GetRecurring(ctx context.Context, accessToken string) (plaid.TransactionsRecurringGetResponse, error)
+	GetInvestmentHoldings(ctx context.Context, accessToken string) (plaid.InvestmentsHoldingsGetResponse, string, error)
+	GetInvestmentTransactions(ctx context.Context, accessToken, startDate, endDate string) (
+		txns []plaid.InvestmentTransaction, securities []plaid.Security, reqID string, err error)
+	GetLiabilities(ctx context.Context, accessToken string) (plaid.LiabilitiesObject, string, error)
 }
 
 // Creates a new APIClient for Plaid requests
@@ -48,3 +52,14 @@ func NewPlaidSandboxService(clientID, secret string) *Service {
 	client := plaid.NewAPIClient(config)
 	return &Service{Client: client}
 }
+
+// Creates a API Client that sends requests to baseURL instead of a Plaid environment,
+// so the server can be run against a local Plaid stand-in
+func NewPlaidServiceWithURL(clientID, secret, baseURL string) *Service {
+	config := plaid.NewConfiguration()
+	config.AddDefaultHeader("PLAID-CLIENT-ID", clientID)
+	config.AddDefaultHeader("PLAID-SECRET", secret)
+	config.UseEnvironment(plaid.Environment(baseURL))
+	client := plaid.NewAPIClient(config)
+	return &Service{Client: client}
+}
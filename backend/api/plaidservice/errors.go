@@ -0,0 +1,44 @@
+package plaidservice
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Plaid error codes and types the server reacts to
+const (
+	ErrorCodeItemLoginRequired         = "ITEM_LOGIN_REQUIRED"
+	ErrorCodeNoInvestmentAccounts      = "NO_INVESTMENT_ACCOUNTS"
+	ErrorCodeNoLiabilityAccounts       = "NO_LIABILITY_ACCOUNTS"
+	ErrorCodeProductsNotSupported      = "PRODUCTS_NOT_SUPPORTED"
+	ErrorCodeAdditionalConsentRequired = "ADDITIONAL_CONSENT_REQUIRED"
+	ErrorTypeRateLimitExceeded         = "RATE_LIMIT_EXCEEDED"
+	ErrorTypeItemError                 = "ITEM_ERROR"
+)
+
+// Details of an error returned by the Plaid API
+type APIError struct {
+	ErrorType    string `json:"error_type"`
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+	RequestID    string `json:"request_id"`
+}
+
+// Extracts Plaid's error details from an error returned by the Plaid client. Returns false if
+// err did not come from the Plaid API
+func ParseError(err error) (APIError, bool) {
+	var apiErr APIError
+
+	var genericErr plaid.GenericOpenAPIError
+	if !errors.As(err, &genericErr) {
+		return apiErr, false
+	}
+
+	if jsonErr := json.Unmarshal(genericErr.Body(), &apiErr); jsonErr != nil {
+		return apiErr, false
+	}
+
+	return apiErr, true
+}
@@ -0,0 +1,60 @@
+package plaidservice
+
+import (
+	"context"
+
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Page size for investments/transactions/get, which is the maximum Plaid allows
+const investmentTransactionsPageSize = 500
+
+// Gets the current holdings, and the securities they refer to, for an item's investment accounts
+func (p *Service) GetInvestmentHoldings(ctx context.Context, accessToken string) (plaid.InvestmentsHoldingsGetResponse, string, error) {
+	request := plaid.NewInvestmentsHoldingsGetRequest(accessToken)
+	resp, httpResp, err := p.Client.PlaidApi.InvestmentsHoldingsGet(ctx).InvestmentsHoldingsGetRequest(*request).Execute()
+
+	reqID := ""
+	if httpResp != nil {
+		reqID = httpResp.Header.Get("X-Request-Id")
+	}
+	if err != nil {
+		return plaid.InvestmentsHoldingsGetResponse{}, reqID, err
+	}
+
+	return resp, reqID, nil
+}
+
+// Gets investment transactions between two dates (YYYY-MM-DD), paging through investments/transactions/get
+// until every transaction has been fetched. Securities from every page are returned together.
+// Returns last Plaid request ID in loop
+func (p *Service) GetInvestmentTransactions(ctx context.Context, accessToken, startDate, endDate string) (
+	txns []plaid.InvestmentTransaction, securities []plaid.Security, reqID string, err error,
+) {
+	offset := int32(0)
+	for {
+		options := plaid.NewInvestmentsTransactionsGetRequestOptions()
+		options.SetCount(investmentTransactionsPageSize)
+		options.SetOffset(offset)
+
+		request := plaid.NewInvestmentsTransactionsGetRequest(accessToken, startDate, endDate)
+		request.SetOptions(*options)
+
+		resp, httpResp, err := p.Client.PlaidApi.InvestmentsTransactionsGet(ctx).InvestmentsTransactionsGetRequest(*request).Execute()
+		if httpResp != nil {
+			reqID = httpResp.Header.Get("X-Request-Id")
+		}
+		if err != nil {
+			return txns, securities, reqID, err
+		}
+
+		page := resp.GetInvestmentTransactions()
+		txns = append(txns, page...)
+		securities = append(securities, resp.GetSecurities()...)
+
+		offset += int32(len(page))
+		if len(page) == 0 || offset >= resp.GetTotalInvestmentTransactions() {
+			return txns, securities, reqID, nil
+		}
+	}
+}
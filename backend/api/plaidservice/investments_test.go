@@ -0,0 +1,148 @@
+package plaidservice_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jms-guy/greed/backend/api/plaidservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Stand-in for the Plaid API, serving the investments endpoints from canned data
+func newPlaidStandIn(t *testing.T, totalTxns int) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/investments/holdings/get", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			AccessToken string `json:"access_token"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "holdings-request")
+		if req.AccessToken != "access-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error_type":"ITEM_ERROR","error_code":"NO_INVESTMENT_ACCOUNTS","error_message":"no investment accounts","request_id":"holdings-request"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{
+			"accounts": [],
+			"holdings": [{"account_id":"acc-1","security_id":"sec-1","institution_price":12.5,"institution_value":125,"cost_basis":100,"quantity":10,"iso_currency_code":"CAD","unofficial_currency_code":null}],
+			"securities": [{"security_id":"sec-1","name":"Vanguard S&P 500","ticker_symbol":"VFV","type":"etf","close_price":12.5,"iso_currency_code":"CAD","unofficial_currency_code":null,"isin":null,"cusip":null,"sedol":null,"institution_security_id":null,"institution_id":null,"proxy_security_id":null,"is_cash_equivalent":false,"close_price_as_of":null,"market_identifier_code":null}],
+			"item": {"item_id":"item-1","webhook":null,"error":null,"available_products":[],"billed_products":[],"consent_expiration_time":null,"update_type":"background"},
+			"request_id": "holdings-request"
+		}`))
+	})
+	mux.HandleFunc("/investments/transactions/get", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			StartDate string `json:"start_date"`
+			EndDate   string `json:"end_date"`
+			Options   struct {
+				Count  int `json:"count"`
+				Offset int `json:"offset"`
+			} `json:"options"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "2024-01-01", req.StartDate)
+		assert.Equal(t, "2025-12-31", req.EndDate)
+
+		var txns []map[string]any
+		for i := req.Options.Offset; i < totalTxns && i < req.Options.Offset+req.Options.Count; i++ {
+			txns = append(txns, map[string]any{
+				"investment_transaction_id": fmt.Sprintf("itxn-%d", i),
+				"account_id":                "acc-1",
+				"security_id":               "sec-1",
+				"date":                      "2025-01-02",
+				"name":                      "BUY VFV",
+				"quantity":                  1,
+				"amount":                    12.5,
+				"price":                     12.5,
+				"fees":                      0,
+				"type":                      "buy",
+				"subtype":                   "buy",
+				"iso_currency_code":         "CAD",
+				"unofficial_currency_code":  nil,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", fmt.Sprintf("transactions-request-%d", req.Options.Offset))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"item":                          map[string]any{"item_id": "item-1", "webhook": nil, "error": nil, "available_products": []string{}, "billed_products": []string{}, "consent_expiration_time": nil, "update_type": "background"},
+			"accounts":                      []any{},
+			"securities":                    []any{},
+			"investment_transactions":       txns,
+			"total_investment_transactions": totalTxns,
+			"request_id":                    "transactions-request",
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetInvestmentHoldings(t *testing.T) {
+	server := newPlaidStandIn(t, 0)
+	service := plaidservice.NewPlaidServiceWithURL("client-id", "secret", server.URL)
+
+	resp, reqID, err := service.GetInvestmentHoldings(context.Background(), "access-token")
+	require.NoError(t, err)
+	assert.Equal(t, "holdings-request", reqID)
+
+	holdings := resp.GetHoldings()
+	require.Len(t, holdings, 1)
+	assert.Equal(t, "sec-1", holdings[0].SecurityId)
+	assert.Equal(t, 100.0, holdings[0].GetCostBasis())
+
+	securities := resp.GetSecurities()
+	require.Len(t, securities, 1)
+	assert.Equal(t, "VFV", securities[0].GetTickerSymbol())
+}
+
+func TestGetInvestmentHoldingsPlaidError(t *testing.T) {
+	server := newPlaidStandIn(t, 0)
+	service := plaidservice.NewPlaidServiceWithURL("client-id", "secret", server.URL)
+
+	_, reqID, err := service.GetInvestmentHoldings(context.Background(), "other-token")
+	require.Error(t, err)
+	assert.Equal(t, "holdings-request", reqID)
+
+	plaidErr, ok := plaidservice.ParseError(err)
+	require.True(t, ok)
+	assert.Equal(t, plaidservice.ErrorCodeNoInvestmentAccounts, plaidErr.ErrorCode)
+}
+
+func TestGetInvestmentTransactionsPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int
+		expectedReqID string
+	}{
+		{name: "no transactions", total: 0, expectedReqID: "transactions-request-0"},
+		{name: "single page", total: 3, expectedReqID: "transactions-request-0"},
+		{name: "several pages", total: 1234, expectedReqID: "transactions-request-1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPlaidStandIn(t, tt.total)
+			service := plaidservice.NewPlaidServiceWithURL("client-id", "secret", server.URL)
+
+			txns, _, reqID, err := service.GetInvestmentTransactions(context.Background(), "access-token", "2024-01-01", "2025-12-31")
+			require.NoError(t, err)
+			assert.Len(t, txns, tt.total)
+			assert.Equal(t, tt.expectedReqID, reqID)
+			if tt.total > 0 {
+				assert.Equal(t, fmt.Sprintf("itxn-%d", tt.total-1), txns[tt.total-1].InvestmentTransactionId)
+			}
+		})
+	}
+}
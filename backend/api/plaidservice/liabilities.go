@@ -0,0 +1,23 @@
+package plaidservice
+
+import (
+	"context"
+
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Gets payment and interest details for an item's credit card, student loan and mortgage accounts
+func (p *Service) GetLiabilities(ctx context.Context, accessToken string) (plaid.LiabilitiesObject, string, error) {
+	request := plaid.NewLiabilitiesGetRequest(accessToken)
+	resp, httpResp, err := p.Client.PlaidApi.LiabilitiesGet(ctx).LiabilitiesGetRequest(*request).Execute()
+
+	reqID := ""
+	if httpResp != nil {
+		reqID = httpResp.Header.Get("X-Request-Id")
+	}
+	if err != nil {
+		return plaid.LiabilitiesObject{}, reqID, err
+	}
+
+	return resp.GetLiabilities(), reqID, nil
+}
@@ -0,0 +1,46 @@
+package plaidservice_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jms-guy/greed/backend/api/plaidservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLiabilities(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/liabilities/get", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "liabilities-request")
+		_, _ = w.Write([]byte(`{
+			"accounts": [],
+			"item": {"item_id":"item-1","webhook":null,"error":null,"available_products":[],"billed_products":[],"consent_expiration_time":null,"update_type":"background"},
+			"liabilities": {
+				"credit": [{"account_id":"acc-1","aprs":[{"apr_percentage":19.99,"apr_type":"purchase_apr","balance_subject_to_apr":1250,"interest_charge_amount":20.5}],"is_overdue":false,"last_payment_amount":100,"last_payment_date":"2025-01-10","last_statement_issue_date":"2025-01-01","last_statement_balance":1250,"minimum_payment_amount":25,"next_payment_due_date":"2025-02-15"}],
+				"mortgage": [],
+				"student": []
+			},
+			"request_id": "liabilities-request"
+		}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	service := plaidservice.NewPlaidServiceWithURL("client-id", "secret", server.URL)
+
+	liabilities, reqID, err := service.GetLiabilities(context.Background(), "access-token")
+	require.NoError(t, err)
+	assert.Equal(t, "liabilities-request", reqID)
+
+	require.Len(t, liabilities.Credit, 1)
+	credit := liabilities.Credit[0]
+	assert.Equal(t, "acc-1", credit.GetAccountId())
+	assert.Equal(t, 25.0, credit.GetMinimumPaymentAmount())
+	require.Len(t, credit.Aprs, 1)
+	assert.Equal(t, 19.99, credit.Aprs[0].AprPercentage)
+	assert.Empty(t, liabilities.Mortgage)
+}
@@ -0,0 +1,201 @@
+package plaidservice
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Country used when an item or request doesn't name one, matching items linked before countries were configurable
+const DefaultCountryCode = "CA"
+
+// Settings applied to every Link token the server creates
+type LinkOptions struct {
+	CountryCodes     []plaid.CountryCode
+	Products         []plaid.Products
+	OptionalProducts []plaid.Products
+	DaysRequested    int32
+	AccountFilters   plaid.LinkTokenAccountFilters
+}
+
+// Builds Link options from the server's Plaid configuration values, rejecting any value Plaid wouldn't accept
+func NewLinkOptions(countryCodes, products, optionalProducts, accountFilters string, daysRequested int) (LinkOptions, error) {
+	countries, err := ParseCountryCodes(countryCodes)
+	if err != nil {
+		return LinkOptions{}, err
+	}
+	if len(countries) == 0 {
+		return LinkOptions{}, fmt.Errorf("at least one country code is required")
+	}
+
+	required, err := ParseProducts(products)
+	if err != nil {
+		return LinkOptions{}, err
+	}
+	if len(required) == 0 {
+		return LinkOptions{}, fmt.Errorf("at least one product is required")
+	}
+
+	optional, err := ParseProducts(optionalProducts)
+	if err != nil {
+		return LinkOptions{}, err
+	}
+
+	filters, err := ParseAccountFilters(accountFilters)
+	if err != nil {
+		return LinkOptions{}, err
+	}
+
+	if daysRequested < 1 || daysRequested > 730 {
+		return LinkOptions{}, fmt.Errorf("days requested must be between 1 and 730, got %d", daysRequested)
+	}
+
+	return LinkOptions{
+		CountryCodes:     countries,
+		Products:         required,
+		OptionalProducts: optional,
+		DaysRequested:    int32(daysRequested),
+		AccountFilters:   filters,
+	}, nil
+}
+
+// Returns the first configured country, which new items are assumed to belong to when Plaid doesn't say otherwise
+func (o LinkOptions) DefaultCountry() string {
+	if len(o.CountryCodes) == 0 {
+		return DefaultCountryCode
+	}
+	return string(o.CountryCodes[0])
+}
+
+// Returns a copy of the options narrowed to a request's countries and products.
+// Countries must be a subset of the configured ones, empty lists keep the configured values
+func (o LinkOptions) WithRequest(countryCodes []plaid.CountryCode, products []plaid.Products) (LinkOptions, error) {
+	narrowed := o
+
+	if len(countryCodes) > 0 {
+		for _, code := range countryCodes {
+			if !slices.Contains(o.CountryCodes, code) {
+				return LinkOptions{}, fmt.Errorf("country code %s is not enabled on this server", code)
+			}
+		}
+		narrowed.CountryCodes = countryCodes
+	}
+
+	if len(products) > 0 {
+		narrowed.Products = products
+		// Plaid rejects a product listed as both required and optional
+		narrowed.OptionalProducts = slices.DeleteFunc(slices.Clone(o.OptionalProducts), func(p plaid.Products) bool {
+			return slices.Contains(products, p)
+		})
+	}
+
+	return narrowed, nil
+}
+
+// Parses a comma-separated list of ISO country codes, such as "US,CA"
+func ParseCountryCodes(value string) ([]plaid.CountryCode, error) {
+	codes := []plaid.CountryCode{}
+	for _, part := range splitList(value, ",") {
+		code := plaid.CountryCode(strings.ToUpper(part))
+		if !code.IsValid() {
+			return nil, fmt.Errorf("invalid country code: %s", part)
+		}
+		if !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+// Parses a comma-separated list of Plaid products, such as "transactions,liabilities"
+func ParseProducts(value string) ([]plaid.Products, error) {
+	products := []plaid.Products{}
+	for _, part := range splitList(value, ",") {
+		product := plaid.Products(strings.ToLower(part))
+		if !product.IsValid() {
+			return nil, fmt.Errorf("invalid Plaid product: %s", part)
+		}
+		if !slices.Contains(products, product) {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+// Parses account filters written as semicolon-separated "type:subtype,subtype" groups,
+// such as "depository:checking,savings;loan:all". Types left out are not shown in Link
+func ParseAccountFilters(value string) (plaid.LinkTokenAccountFilters, error) {
+	filters := plaid.LinkTokenAccountFilters{}
+
+	for _, group := range splitList(value, ";") {
+		accountType, subtypeList, found := strings.Cut(group, ":")
+		if !found {
+			return plaid.LinkTokenAccountFilters{}, fmt.Errorf("account filter %q must be written as type:subtypes", group)
+		}
+		subtypes := splitList(strings.ToLower(subtypeList), ",")
+		if len(subtypes) == 0 {
+			return plaid.LinkTokenAccountFilters{}, fmt.Errorf("account filter %q has no subtypes", group)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(accountType)) {
+		case "depository":
+			filter := plaid.DepositoryFilter{}
+			for _, s := range subtypes {
+				subtype := plaid.DepositoryAccountSubtype(s)
+				if !subtype.IsValid() {
+					return plaid.LinkTokenAccountFilters{}, fmt.Errorf("invalid depository subtype: %s", s)
+				}
+				filter.AccountSubtypes = append(filter.AccountSubtypes, subtype)
+			}
+			filters.Depository = &filter
+		case "credit":
+			filter := plaid.CreditFilter{}
+			for _, s := range subtypes {
+				subtype := plaid.CreditAccountSubtype(s)
+				if !subtype.IsValid() {
+					return plaid.LinkTokenAccountFilters{}, fmt.Errorf("invalid credit subtype: %s", s)
+				}
+				filter.AccountSubtypes = append(filter.AccountSubtypes, subtype)
+			}
+			filters.Credit = &filter
+		case "loan":
+			filter := plaid.LoanFilter{}
+			for _, s := range subtypes {
+				subtype := plaid.LoanAccountSubtype(s)
+				if !subtype.IsValid() {
+					return plaid.LinkTokenAccountFilters{}, fmt.Errorf("invalid loan subtype: %s", s)
+				}
+				filter.AccountSubtypes = append(filter.AccountSubtypes, subtype)
+			}
+			filters.Loan = &filter
+		case "investment":
+			filter := plaid.InvestmentFilter{}
+			for _, s := range subtypes {
+				subtype := plaid.InvestmentAccountSubtype(s)
+				if !subtype.IsValid() {
+					return plaid.LinkTokenAccountFilters{}, fmt.Errorf("invalid investment subtype: %s", s)
+				}
+				filter.AccountSubtypes = append(filter.AccountSubtypes, subtype)
+			}
+			filters.Investment = &filter
+		default:
+			return plaid.LinkTokenAccountFilters{}, fmt.Errorf("invalid account type: %s", accountType)
+		}
+	}
+
+	return filters, nil
+}
+
+// Splits a list on sep, trimming whitespace and dropping empty entries
+func splitList(value, sep string) []string {
+	parts := []string{}
+	for _, part := range strings.Split(value, sep) {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,79 @@
+package plaidservice_test
+
+import (
+	"testing"
+
+	"github.com/jms-guy/greed/backend/api/plaidservice"
+	"github.com/plaid/plaid-go/v36/plaid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLinkOptions(t *testing.T) {
+	options, err := plaidservice.NewLinkOptions(" ca, us ,CA", "transactions", "investments,liabilities", "depository:checking,savings;credit:credit card;loan:all", 365)
+	require.NoError(t, err)
+
+	assert.Equal(t, []plaid.CountryCode{plaid.COUNTRYCODE_CA, plaid.COUNTRYCODE_US}, options.CountryCodes)
+	assert.Equal(t, []plaid.Products{plaid.PRODUCTS_TRANSACTIONS}, options.Products)
+	assert.Equal(t, []plaid.Products{plaid.PRODUCTS_INVESTMENTS, plaid.PRODUCTS_LIABILITIES}, options.OptionalProducts)
+	assert.Equal(t, int32(365), options.DaysRequested)
+	assert.Equal(t, "CA", options.DefaultCountry())
+
+	require.NotNil(t, options.AccountFilters.Depository)
+	assert.Equal(t, []plaid.DepositoryAccountSubtype{plaid.DEPOSITORYACCOUNTSUBTYPE_CHECKING, plaid.DEPOSITORYACCOUNTSUBTYPE_SAVINGS}, options.AccountFilters.Depository.AccountSubtypes)
+	require.NotNil(t, options.AccountFilters.Credit)
+	assert.Equal(t, []plaid.CreditAccountSubtype{plaid.CREDITACCOUNTSUBTYPE_CREDIT_CARD}, options.AccountFilters.Credit.AccountSubtypes)
+	require.NotNil(t, options.AccountFilters.Loan)
+	assert.Equal(t, []plaid.LoanAccountSubtype{plaid.LOANACCOUNTSUBTYPE_ALL}, options.AccountFilters.Loan.AccountSubtypes)
+	assert.Nil(t, options.AccountFilters.Investment)
+}
+
+func TestNewLinkOptionsInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		countries   string
+		products    string
+		optional    string
+		filters     string
+		days        int
+		expectedErr string
+	}{
+		{"no countries", "", "transactions", "", "", 730, "at least one country code is required"},
+		{"unknown country", "CA,XX", "transactions", "", "", 730, "invalid country code: XX"},
+		{"no products", "CA", " , ", "", "", 730, "at least one product is required"},
+		{"unknown product", "CA", "transactions,mortgages", "", "", 730, "invalid Plaid product: mortgages"},
+		{"unknown optional product", "CA", "transactions", "crypto", "", 730, "invalid Plaid product: crypto"},
+		{"filter without subtypes", "CA", "transactions", "", "loan", 730, "must be written as type:subtypes"},
+		{"unknown account type", "CA", "transactions", "", "brokerage:all", 730, "invalid account type: brokerage"},
+		{"unknown subtype", "CA", "transactions", "", "credit:store card", 730, "invalid credit subtype: store card"},
+		{"too many days", "CA", "transactions", "", "", 731, "days requested must be between 1 and 730"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := plaidservice.NewLinkOptions(tt.countries, tt.products, tt.optional, tt.filters, tt.days)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestLinkOptionsWithRequest(t *testing.T) {
+	options, err := plaidservice.NewLinkOptions("CA,US", "transactions", "investments,liabilities", "", 730)
+	require.NoError(t, err)
+
+	narrowed, err := options.WithRequest([]plaid.CountryCode{plaid.COUNTRYCODE_US}, []plaid.Products{plaid.PRODUCTS_TRANSACTIONS, plaid.PRODUCTS_LIABILITIES})
+	require.NoError(t, err)
+	assert.Equal(t, []plaid.CountryCode{plaid.COUNTRYCODE_US}, narrowed.CountryCodes)
+	assert.Equal(t, []plaid.Products{plaid.PRODUCTS_TRANSACTIONS, plaid.PRODUCTS_LIABILITIES}, narrowed.Products)
+	assert.Equal(t, []plaid.Products{plaid.PRODUCTS_INVESTMENTS}, narrowed.OptionalProducts)
+	// The server's options are left untouched
+	assert.Equal(t, []plaid.Products{plaid.PRODUCTS_INVESTMENTS, plaid.PRODUCTS_LIABILITIES}, options.OptionalProducts)
+
+	unchanged, err := options.WithRequest(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, options, unchanged)
+
+	_, err = options.WithRequest([]plaid.CountryCode{plaid.COUNTRYCODE_GB}, nil)
+	assert.EqualError(t, err, "country code GB is not enabled on this server")
+}
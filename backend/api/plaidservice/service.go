@@ -2,6 +2,7 @@ package plaidservice
 
 import (
 	"context"
+	"slices"
 
 	"github.com/plaid/plaid-go/v36/plaid"
 )
@@ -22,26 +23,39 @@ func (p *Service) GetAccounts(ctx context.Context, accessToken string) ([]plaid.
 	return accounts, httpResp.Header.Get("X-Request-Id"), nil
 }
 
-// Gets institution name for an item
-func (p *Service) GetItemInstitution(ctx context.Context, accessToken string) (string, error) {
+// Gets institution name for an item, along with the country it was linked in.
+// The institution is looked up in countryCodes, and its country is the first of those it operates in
+func (p *Service) GetItemInstitution(ctx context.Context, accessToken string, countryCodes []plaid.CountryCode) (string, string, error) {
 	itemGetReq := plaid.NewItemGetRequest(accessToken)
 	itemResp, _, err := p.Client.PlaidApi.ItemGet(ctx).ItemGetRequest(*itemGetReq).Execute()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	item := itemResp.GetItem()
 	institutionID := item.GetInstitutionId()
 
-	instReq := plaid.NewInstitutionsGetByIdRequest(institutionID, []plaid.CountryCode{plaid.COUNTRYCODE_CA})
+	if len(countryCodes) == 0 {
+		countryCodes = []plaid.CountryCode{plaid.CountryCode(DefaultCountryCode)}
+	}
+
+	instReq := plaid.NewInstitutionsGetByIdRequest(institutionID, countryCodes)
 	instResp, _, err := p.Client.PlaidApi.InstitutionsGetById(ctx).InstitutionsGetByIdRequest(*instReq).Execute()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	inst := instResp.GetInstitution()
 
-	return inst.GetName(), nil
+	country := string(countryCodes[0])
+	for _, code := range countryCodes {
+		if slices.Contains(inst.GetCountryCodes(), code) {
+			country = string(code)
+			break
+		}
+	}
+
+	return inst.GetName(), country, nil
 }
 
 // Updates account balances for item
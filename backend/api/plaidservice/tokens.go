@@ -11,7 +11,7 @@ import (
 func (p *Service) CreateSandboxTokenWithCustomUser(ctx context.Context) (plaid.ItemPublicTokenExchangeResponse, error) {
 	request := plaid.NewSandboxPublicTokenCreateRequest(
 		"ins_109508",
-		[]plaid.Products{plaid.PRODUCTS_TRANSACTIONS},
+		[]plaid.Products{plaid.PRODUCTS_TRANSACTIONS, plaid.PRODUCTS_INVESTMENTS},
 	)
 
 	opt := plaid.NewSandboxPublicTokenCreateRequestOptions()
@@ -45,7 +45,7 @@ func (p *Service) GetSandboxToken(ctx context.Context) (plaid.ItemPublicTokenExc
 	sandboxPublicTokenResp, _, err := p.Client.PlaidApi.SandboxPublicTokenCreate(ctx).SandboxPublicTokenCreateRequest(
 		*plaid.NewSandboxPublicTokenCreateRequest(
 			"ins_109508",
-			[]plaid.Products{plaid.PRODUCTS_TRANSACTIONS},
+			[]plaid.Products{plaid.PRODUCTS_TRANSACTIONS, plaid.PRODUCTS_INVESTMENTS},
 		),
 	).Execute()
 	if err != nil {
@@ -68,33 +68,26 @@ func (p *Service) GetSandboxToken(ctx context.Context) (plaid.ItemPublicTokenExc
 }
 
 // Requests Plaid API for a Link token for p.Client use
-func (p *Service) GetLinkToken(ctx context.Context, userID, webhookURL string) (string, error) {
-	user := plaid.LinkTokenCreateRequestUser{
-		ClientUserId: userID,
+func (p *Service) GetLinkToken(ctx context.Context, userID, webhookURL string, options LinkOptions) (string, error) {
+	request := newLinkTokenRequest(userID, webhookURL, options)
+	request.SetOptionalProducts(options.OptionalProducts)
+
+	resp, _, err := p.Client.PlaidApi.LinkTokenCreate(ctx).LinkTokenCreateRequest(*request).Execute()
+	if err != nil {
+		return "", err
 	}
 
-	request := plaid.NewLinkTokenCreateRequest(
-		"Greed-CLI",
-		"en",
-		[]plaid.CountryCode{plaid.COUNTRYCODE_CA},
-		user,
-	)
+	linkToken := resp.GetLinkToken()
 
-	transactions := plaid.LinkTokenTransactions{
-		DaysRequested: plaid.PtrInt32(730),
-	}
+	return linkToken, nil
+}
 
-	request.SetProducts([]plaid.Products{plaid.PRODUCTS_TRANSACTIONS})
-	request.SetTransactions(transactions)
-	request.SetAccountFilters(plaid.LinkTokenAccountFilters{
-		Depository: &plaid.DepositoryFilter{
-			AccountSubtypes: []plaid.DepositoryAccountSubtype{plaid.DEPOSITORYACCOUNTSUBTYPE_CHECKING, plaid.DEPOSITORYACCOUNTSUBTYPE_SAVINGS},
-		},
-		Credit: &plaid.CreditFilter{
-			AccountSubtypes: []plaid.CreditAccountSubtype{plaid.CREDITACCOUNTSUBTYPE_CREDIT_CARD},
-		},
-	})
-	request.SetWebhook(webhookURL)
+// Requests Plaid API for a Link token configured with a user's access token, to initiate Update mode
+func (p *Service) GetLinkTokenForUpdateMode(ctx context.Context, userID, accessToken, webhookURL string, options LinkOptions) (string, error) {
+	request := newLinkTokenRequest(userID, webhookURL, options)
+	// Update mode ignores optional products, so items linked before a product was offered consent to it here
+	request.SetAdditionalConsentedProducts(options.OptionalProducts)
+	request.SetAccessToken(accessToken)
 
 	resp, _, err := p.Client.PlaidApi.LinkTokenCreate(ctx).LinkTokenCreateRequest(*request).Execute()
 	if err != nil {
@@ -106,8 +99,8 @@ func (p *Service) GetLinkToken(ctx context.Context, userID, webhookURL string) (
 	return linkToken, nil
 }
 
-// Requests Plaid API for a Link token configured with a user's access token, to initiate Update mode
-func (p *Service) GetLinkTokenForUpdateMode(ctx context.Context, userID, accessToken, webhookURL string) (string, error) {
+// Builds the parts of a Link token request shared by new links and Update mode
+func newLinkTokenRequest(userID, webhookURL string, options LinkOptions) *plaid.LinkTokenCreateRequest {
 	user := plaid.LinkTokenCreateRequestUser{
 		ClientUserId: userID,
 	}
@@ -115,39 +108,24 @@ func (p *Service) GetLinkTokenForUpdateMode(ctx context.Context, userID, accessT
 	request := plaid.NewLinkTokenCreateRequest(
 		"Greed-CLI",
 		"en",
-		[]plaid.CountryCode{plaid.COUNTRYCODE_CA},
+		options.CountryCodes,
 		user,
 	)
 
 	transactions := plaid.LinkTokenTransactions{
-		DaysRequested: plaid.PtrInt32(730),
+		DaysRequested: plaid.PtrInt32(options.DaysRequested),
 	}
 
-	request.SetProducts([]plaid.Products{plaid.PRODUCTS_TRANSACTIONS})
+	request.SetProducts(options.Products)
 	request.SetTransactions(transactions)
-	request.SetAccountFilters(plaid.LinkTokenAccountFilters{
-		Depository: &plaid.DepositoryFilter{
-			AccountSubtypes: []plaid.DepositoryAccountSubtype{plaid.DEPOSITORYACCOUNTSUBTYPE_CHECKING, plaid.DEPOSITORYACCOUNTSUBTYPE_SAVINGS},
-		},
-		Credit: &plaid.CreditFilter{
-			AccountSubtypes: []plaid.CreditAccountSubtype{plaid.CREDITACCOUNTSUBTYPE_CREDIT_CARD},
-		},
-	})
+	request.SetAccountFilters(options.AccountFilters)
 	request.SetWebhook(webhookURL)
-	request.SetAccessToken(accessToken)
 
-	resp, _, err := p.Client.PlaidApi.LinkTokenCreate(ctx).LinkTokenCreateRequest(*request).Execute()
-	if err != nil {
-		return "", err
-	}
-
-	linkToken := resp.GetLinkToken()
-
-	return linkToken, nil
+	return request
 }
 
 // Exchanges a public token received from client for a permanent access token for item from Plaid API
-func (p *Service) GetAccessToken(ctx context.Context, publicToken string) (models.AccessResponse, error) {
+func (p *Service) GetAccessToken(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
 	exchangePublicTokenReq := plaid.NewItemPublicTokenExchangeRequest(publicToken)
 
 	exchangePublicTokenResp, httpResp, err := p.Client.PlaidApi.ItemPublicTokenExchange(ctx).ItemPublicTokenExchangeRequest(
@@ -159,7 +137,7 @@ func (p *Service) GetAccessToken(ctx context.Context, publicToken string) (model
 
 	accessToken := exchangePublicTokenResp.GetAccessToken()
 	itemID := exchangePublicTokenResp.ItemId
-	instName, err := p.GetItemInstitution(ctx, accessToken)
+	instName, countryCode, err := p.GetItemInstitution(ctx, accessToken, countryCodes)
 	if err != nil {
 		return models.AccessResponse{}, err
 	}
@@ -170,6 +148,7 @@ func (p *Service) GetAccessToken(ctx context.Context, publicToken string) (model
 		AccessToken:     accessToken,
 		ItemID:          itemID,
 		InstitutionName: instName,
+		CountryCode:     countryCode,
 		RequestID:       reqID,
 	}
 
@@ -0,0 +1,253 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+	This package scores newly synced transactions against a user's transaction history, flagging
+	ones that look unusual: amounts far outside what is normally spent at a merchant or in a category,
+	first purchases from a merchant, repeated charges of the same amount, and foreign currency charges.
+	It has no dependencies on the database or Plaid, the server converts records into Transactions.
+*/
+
+// Kinds of anomaly
+const (
+	KindUnusualAmount   = "unusual_amount"
+	KindNewMerchant     = "new_merchant"
+	KindDuplicateCharge = "duplicate_charge"
+	KindForeignCurrency = "foreign_currency"
+)
+
+// Transaction as seen by the analyzer. Positive amounts are money leaving the account, as with Plaid
+type Transaction struct {
+	ID        string
+	AccountID string
+	Amount    float64
+	Currency  string
+	Date      time.Time
+	Merchant  string
+	Category  string
+}
+
+// An unusual transaction, with the reason it was flagged. Score is the z-score of unusual amounts,
+// and 1 for other kinds
+type Anomaly struct {
+	TransactionID string
+	Kind          string
+	Reason        string
+	Score         float64
+}
+
+// Thresholds used when scoring transactions
+type Config struct {
+	ZScoreThreshold float64       // Z-score above which an amount is unusual
+	MinSamples      int           // Transactions needed in a merchant or category before amounts are scored against it
+	MinHistory      int           // Transactions a user needs before new merchants are flagged
+	DuplicateWindow time.Duration // Window in which a repeated charge counts as a duplicate
+	MinAmount       float64       // Charges below this amount are never flagged
+}
+
+// Thresholds used by the server
+func DefaultConfig() Config {
+	return Config{
+		ZScoreThreshold: 3,
+		MinSamples:      5,
+		MinHistory:      30,
+		DuplicateWindow: 3 * 24 * time.Hour,
+		MinAmount:       1,
+	}
+}
+
+// Amount statistics of a group of transactions
+type stats struct {
+	count int
+	mean  float64
+	std   float64
+}
+
+// Scores new transactions against the user's history. History may include the new transactions
+// themselves, they are left out of the statistics each is scored against. accountCurrencies maps account
+// IDs to the account's currency, and transactions in any other currency are flagged
+func Detect(newTxns, history []Transaction, accountCurrencies map[string]string, cfg Config) []Anomaly {
+	newIDs := make(map[string]bool, len(newTxns))
+	for _, txn := range newTxns {
+		newIDs[txn.ID] = true
+	}
+
+	var past []Transaction
+	for _, txn := range history {
+		if !newIDs[txn.ID] {
+			past = append(past, txn)
+		}
+	}
+
+	byMerchant := map[string][]float64{}
+	byCategory := map[string][]float64{}
+	for _, txn := range past {
+		if txn.Amount <= 0 {
+			continue
+		}
+		if m := normalize(txn.Merchant); m != "" {
+			byMerchant[m] = append(byMerchant[m], txn.Amount)
+		}
+		if txn.Category != "" {
+			byCategory[txn.Category] = append(byCategory[txn.Category], txn.Amount)
+		}
+	}
+	merchantStats := make(map[string]stats, len(byMerchant))
+	for m, amounts := range byMerchant {
+		merchantStats[m] = computeStats(amounts)
+	}
+	categoryStats := make(map[string]stats, len(byCategory))
+	for c, amounts := range byCategory {
+		categoryStats[c] = computeStats(amounts)
+	}
+
+	// New merchants are only meaningful once there is some history to compare against
+	flagNewMerchants := len(past) >= cfg.MinHistory
+	seenMerchants := map[string]bool{}
+	for _, txn := range past {
+		seenMerchants[normalize(txn.Merchant)] = true
+	}
+
+	// Oldest first, so the earlier of two duplicate charges is the one left unflagged
+	sorted := append([]Transaction{}, newTxns...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	var anomalies []Anomaly
+	for i, txn := range sorted {
+		if txn.Amount < cfg.MinAmount {
+			continue
+		}
+		merchant := normalize(txn.Merchant)
+
+		if a, ok := unusualAmount(txn, merchantStats[merchant], categoryStats[txn.Category], cfg); ok {
+			anomalies = append(anomalies, a)
+		}
+
+		if merchant != "" && flagNewMerchants && !seenMerchants[merchant] {
+			anomalies = append(anomalies, Anomaly{
+				TransactionID: txn.ID,
+				Kind:          KindNewMerchant,
+				Reason:        fmt.Sprintf("First charge from %s", txn.Merchant),
+				Score:         1,
+			})
+		}
+
+		if dup, ok := findDuplicate(txn, past, sorted[:i], cfg.DuplicateWindow); ok {
+			anomalies = append(anomalies, Anomaly{
+				TransactionID: txn.ID,
+				Kind:          KindDuplicateCharge,
+				Reason:        fmt.Sprintf("Same charge of %.2f at %s on %s", txn.Amount, displayMerchant(txn), dup.Date.Format("2006-01-02")),
+				Score:         1,
+			})
+		}
+
+		accountCurrency := accountCurrencies[txn.AccountID]
+		if txn.Currency != "" && accountCurrency != "" && !strings.EqualFold(txn.Currency, accountCurrency) {
+			anomalies = append(anomalies, Anomaly{
+				TransactionID: txn.ID,
+				Kind:          KindForeignCurrency,
+				Reason:        fmt.Sprintf("Charged in %s, account is in %s", strings.ToUpper(txn.Currency), strings.ToUpper(accountCurrency)),
+				Score:         1,
+			})
+		}
+
+		// Later transactions in the batch count earlier ones as seen
+		seenMerchants[merchant] = true
+	}
+
+	return anomalies
+}
+
+// Scores an amount against the merchant's history, falling back to the category's when the merchant
+// has too few transactions
+func unusualAmount(txn Transaction, merchant, category stats, cfg Config) (Anomaly, bool) {
+	group, name := merchant, displayMerchant(txn)
+	if group.count < cfg.MinSamples {
+		group, name = category, txn.Category
+	}
+	if group.count < cfg.MinSamples {
+		return Anomaly{}, false
+	}
+
+	// Merchants charging the same amount every time have no spread, so a floor keeps small changes
+	// from scoring as extreme, while large ones still stand out
+	std := math.Max(group.std, group.mean*0.1)
+	z := (txn.Amount - group.mean) / std
+	if z < cfg.ZScoreThreshold {
+		return Anomaly{}, false
+	}
+
+	return Anomaly{
+		TransactionID: txn.ID,
+		Kind:          KindUnusualAmount,
+		Reason:        fmt.Sprintf("%.2f is %.1f standard deviations above the usual %.2f for %s", txn.Amount, z, group.mean, name),
+		Score:         math.Round(z*100) / 100,
+	}, true
+}
+
+// Finds an earlier charge of the same amount from the same merchant on the same account within the window
+func findDuplicate(txn Transaction, past, earlierNew []Transaction, window time.Duration) (Transaction, bool) {
+	merchant := normalize(txn.Merchant)
+	if merchant == "" {
+		return Transaction{}, false
+	}
+
+	for _, candidates := range [][]Transaction{earlierNew, past} {
+		for _, other := range candidates {
+			if other.ID == txn.ID || other.AccountID != txn.AccountID || normalize(other.Merchant) != merchant {
+				continue
+			}
+			if math.Abs(other.Amount-txn.Amount) >= 0.005 {
+				continue
+			}
+			gap := txn.Date.Sub(other.Date)
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap <= window {
+				return other, true
+			}
+		}
+	}
+
+	return Transaction{}, false
+}
+
+func computeStats(amounts []float64) stats {
+	s := stats{count: len(amounts)}
+	if s.count == 0 {
+		return s
+	}
+
+	for _, a := range amounts {
+		s.mean += a
+	}
+	s.mean /= float64(s.count)
+
+	for _, a := range amounts {
+		s.std += (a - s.mean) * (a - s.mean)
+	}
+	s.std = math.Sqrt(s.std / float64(s.count))
+
+	return s
+}
+
+func normalize(merchant string) string {
+	return strings.ToLower(strings.TrimSpace(merchant))
+}
+
+func displayMerchant(txn Transaction) string {
+	if txn.Merchant == "" {
+		return "an unknown merchant"
+	}
+	return txn.Merchant
+}
@@ -0,0 +1,107 @@
+package anomaly_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jms-guy/greed/backend/internal/anomaly"
+	"github.com/stretchr/testify/assert"
+)
+
+var day = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// History of 40 small grocery charges, with some spread
+func groceryHistory() []anomaly.Transaction {
+	var history []anomaly.Transaction
+	for i := range 40 {
+		history = append(history, anomaly.Transaction{
+			ID:        fmt.Sprintf("hist-%d", i),
+			AccountID: "acc",
+			Amount:    float64(40 + i%10),
+			Currency:  "CAD",
+			Date:      day.AddDate(0, 0, -60+i),
+			Merchant:  "Grocer",
+			Category:  "FOOD_AND_DRINK",
+		})
+	}
+	return history
+}
+
+func kinds(anomalies []anomaly.Anomaly) []string {
+	var k []string
+	for _, a := range anomalies {
+		k = append(k, a.Kind)
+	}
+	return k
+}
+
+func TestDetect(t *testing.T) {
+	currencies := map[string]string{"acc": "CAD"}
+
+	tests := []struct {
+		name          string
+		newTxns       []anomaly.Transaction
+		history       []anomaly.Transaction
+		expectedKinds []string
+	}{
+		{
+			name:          "ordinary charge is not flagged",
+			newTxns:       []anomaly.Transaction{{ID: "new", AccountID: "acc", Amount: 45, Currency: "CAD", Date: day, Merchant: "Grocer", Category: "FOOD_AND_DRINK"}},
+			history:       groceryHistory(),
+			expectedKinds: nil,
+		},
+		{
+			name:          "large charge at a known merchant is unusual",
+			newTxns:       []anomaly.Transaction{{ID: "new", AccountID: "acc", Amount: 400, Currency: "CAD", Date: day, Merchant: "Grocer", Category: "FOOD_AND_DRINK"}},
+			history:       groceryHistory(),
+			expectedKinds: []string{anomaly.KindUnusualAmount},
+		},
+		{
+			name:          "first charge from a merchant falls back to category statistics",
+			newTxns:       []anomaly.Transaction{{ID: "new", AccountID: "acc", Amount: 300, Currency: "CAD", Date: day, Merchant: "Bistro", Category: "FOOD_AND_DRINK"}},
+			history:       groceryHistory(),
+			expectedKinds: []string{anomaly.KindUnusualAmount, anomaly.KindNewMerchant},
+		},
+		{
+			name:          "new merchants are not flagged without enough history",
+			newTxns:       []anomaly.Transaction{{ID: "new", AccountID: "acc", Amount: 20, Currency: "CAD", Date: day, Merchant: "Bistro", Category: "FOOD_AND_DRINK"}},
+			history:       groceryHistory()[:10],
+			expectedKinds: nil,
+		},
+		{
+			name: "repeated charge within the window is a duplicate",
+			newTxns: []anomaly.Transaction{
+				{ID: "a", AccountID: "acc", Amount: 45, Currency: "CAD", Date: day.AddDate(0, 0, 1), Merchant: "Grocer", Category: "FOOD_AND_DRINK"},
+				{ID: "b", AccountID: "acc", Amount: 45, Currency: "CAD", Date: day, Merchant: "Grocer", Category: "FOOD_AND_DRINK"},
+			},
+			history:       groceryHistory(),
+			expectedKinds: []string{anomaly.KindDuplicateCharge},
+		},
+		{
+			name:          "charge in another currency is flagged",
+			newTxns:       []anomaly.Transaction{{ID: "new", AccountID: "acc", Amount: 45, Currency: "usd", Date: day, Merchant: "Grocer", Category: "FOOD_AND_DRINK"}},
+			history:       groceryHistory(),
+			expectedKinds: []string{anomaly.KindForeignCurrency},
+		},
+		{
+			name:          "refunds are never flagged",
+			newTxns:       []anomaly.Transaction{{ID: "new", AccountID: "acc", Amount: -400, Currency: "USD", Date: day, Merchant: "Bistro", Category: "FOOD_AND_DRINK"}},
+			history:       groceryHistory(),
+			expectedKinds: nil,
+		},
+		{
+			name:          "new transactions in history are left out of statistics",
+			newTxns:       []anomaly.Transaction{{ID: "hist-39", AccountID: "acc", Amount: 49, Currency: "CAD", Date: day, Merchant: "Grocer", Category: "FOOD_AND_DRINK"}},
+			history:       groceryHistory(),
+			expectedKinds: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := anomaly.Detect(tt.newTxns, tt.history, currencies, anomaly.DefaultConfig())
+			assert.Equal(t, tt.expectedKinds, kinds(got))
+		})
+	}
+}
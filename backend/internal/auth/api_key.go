@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+	API keys are long-lived bearer credentials created by users for scripts and scheduled jobs. They are
+	sent in the Authorization header like a JWT, and told apart from one by their prefix. Only a hash
+	of each key is stored, so a key is shown to the user once, when it is created.
+*/
+
+// Prefix of every API key
+const APIKeyPrefix = "greed_"
+
+// API key scopes
+const (
+	ScopeRead  = "read"  // Only safe requests (GET, HEAD, OPTIONS)
+	ScopeWrite = "write" // Any request
+)
+
+// Number of characters of a key stored in plain text, so users can tell their keys apart
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// Creates a new API key, returning the key along with the prefix shown to identify it
+func NewAPIKey() (key, prefix string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("error creating API key: %w", err)
+	}
+
+	key = APIKeyPrefix + hex.EncodeToString(b)
+	return key, key[:apiKeyDisplayLength], nil
+}
+
+// Reports whether a bearer token is an API key, rather than a JWT
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// Hash of an API key, as stored in the database. Keys are random, so an unsalted hash is enough
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// Reports whether a scope is one of the API key scopes
+func ValidScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeWrite
+}
+
+// Reports whether a key of the given scope may make a request with the given method
+func ScopeAllows(scope, method string) bool {
+	switch scope {
+	case ScopeWrite:
+		return true
+	case ScopeRead:
+		return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+	default:
+		return false
+	}
+}
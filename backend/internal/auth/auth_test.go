@@ -340,3 +340,39 @@ func TestValidateJWT(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAPIKey(t *testing.T) {
+	key, prefix, err := auth.NewAPIKey()
+	assert.NoError(t, err)
+
+	assert.True(t, auth.IsAPIKey(key))
+	assert.True(t, strings.HasPrefix(key, prefix))
+	assert.Len(t, prefix, len(auth.APIKeyPrefix)+8)
+	assert.Len(t, auth.HashAPIKey(key), 64)
+
+	other, _, err := auth.NewAPIKey()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, auth.HashAPIKey(key), auth.HashAPIKey(other))
+}
+
+func TestScopeAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    string
+		method   string
+		expected bool
+	}{
+		{name: "read key may get", scope: auth.ScopeRead, method: http.MethodGet, expected: true},
+		{name: "read key may not post", scope: auth.ScopeRead, method: http.MethodPost, expected: false},
+		{name: "read key may not delete", scope: auth.ScopeRead, method: http.MethodDelete, expected: false},
+		{name: "write key may delete", scope: auth.ScopeWrite, method: http.MethodDelete, expected: true},
+		{name: "unknown scope may not get", scope: "admin", method: http.MethodGet, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, auth.ScopeAllows(tt.scope, tt.method))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+/*
+	This package stores the contents of files users upload, such as receipts attached to transactions.
+	Files are written and read through the Store interface by key, so the local filesystem store used by
+	default can be replaced by another backend, such as cloud object storage, without changing handlers.
+	Metadata about a file, such as its name, type and checksum, is kept in the database, not here.
+*/
+
+// Returned when no blob is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// Returned for keys that would be stored outside of the store
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// Stores blobs of data by key
+type Store interface {
+	// Stores everything read from r under key, replacing any blob already stored there, returning the number of bytes written
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Opens the blob stored under key. The caller must close it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Deletes the blob stored under key. Deleting a key with nothing stored is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Stores blobs as files in a directory on the local filesystem. Keys are paths relative to the directory,
+// and may contain slashes to group blobs into subdirectories
+type LocalStore struct {
+	root string
+}
+
+// Creates a LocalStore in the given directory, creating the directory if it doesn't exist
+func NewLocalStore(root string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating blob directory: %w", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return 0, fmt.Errorf("error creating blob directory: %w", err)
+	}
+
+	// Written to a temporary file first, so a failed upload never leaves a partial blob under the key
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("error creating blob file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		_ = tmp.Close()
+		return n, fmt.Errorf("error writing blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return n, fmt.Errorf("error writing blob: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return n, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return n, fmt.Errorf("error storing blob: %w", err)
+	}
+
+	return n, nil
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening blob: %w", err)
+	}
+
+	return f, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting blob: %w", err)
+	}
+
+	return nil
+}
+
+// Path of the file a key is stored in, refusing keys that would escape the store's directory
+func (s *LocalStore) path(key string) (string, error) {
+	rel := filepath.FromSlash(key)
+	if key == "" || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(s.root, rel), nil
+}
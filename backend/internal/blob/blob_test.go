@@ -0,0 +1,81 @@
+package blob_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jms-guy/greed/backend/internal/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocalStore(filepath.Join(t.TempDir(), "blobs"))
+	require.NoError(t, err)
+
+	n, err := store.Put(ctx, "user/receipt", strings.NewReader("receipt contents"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(16), n)
+
+	r, err := store.Get(ctx, "user/receipt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, r.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "receipt contents", string(data))
+
+	_, err = store.Put(ctx, "user/receipt", strings.NewReader("replaced"))
+	require.NoError(t, err)
+	r, err = store.Get(ctx, "user/receipt")
+	require.NoError(t, err)
+	data, _ = io.ReadAll(r)
+	_ = r.Close()
+	assert.Equal(t, "replaced", string(data))
+
+	require.NoError(t, store.Delete(ctx, "user/receipt"))
+	_, err = store.Get(ctx, "user/receipt")
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+
+	assert.NoError(t, store.Delete(ctx, "user/receipt"), "deleting a missing blob should not fail")
+}
+
+func TestLocalStoreRejectsKeysOutsideStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := blob.NewLocalStore(filepath.Join(dir, "blobs"))
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../escaped", "user/../../escaped", "/etc/passwd"} {
+		_, err := store.Put(ctx, key, strings.NewReader("data"))
+		assert.ErrorIs(t, err, blob.ErrInvalidKey, "key: %q", key)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "escaped"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, io.ErrUnexpectedEOF }
+
+func TestLocalStoreFailedPutLeavesNothing(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := blob.NewLocalStore(root)
+	require.NoError(t, err)
+
+	_, err = store.Put(ctx, "user/receipt", failingReader{})
+	assert.Error(t, err)
+
+	_, err = store.Get(ctx, "user/receipt")
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+
+	entries, err := os.ReadDir(filepath.Join(root, "user"))
+	require.NoError(t, err)
+	assert.Empty(t, entries, "temporary upload file should be removed")
+}
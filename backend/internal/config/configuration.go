@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 // Configuration struct holding all .env variables for server
@@ -25,7 +26,16 @@ type Config struct {
 	PlaidSecret       string
 	PlaidSbSecret     string
 	PlaidWebhookURL   string
+	PlaidBaseURL      string // Overrides the Plaid environment, for running against a local stand-in
+	PlaidCountries    string // Comma-separated country codes members may link institutions in, the first is the default
+	PlaidProducts     string // Comma-separated products required of every new item
+	PlaidOptional     string // Comma-separated products requested when an institution supports them
+	PlaidFilters      string // Account types and subtypes shown in Link, as "type:subtype,subtype;type:all"
+	PlaidDays         int    // Days of transaction history requested when linking
 	AESKey            string
+	ShutdownTimeout   time.Duration // Time allowed for in-flight requests to drain on shutdown
+	AttachmentsPath   string        // Directory transaction attachments are stored in
+	AttachmentMaxSize int64         // Largest transaction attachment accepted, in bytes
 }
 
 func LoadConfig() (*Config, error) {
@@ -121,11 +131,65 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("PLAID_WEBHOOK_URL environment variable not set")
 	}
 
+	plaidBaseURL := os.Getenv("PLAID_BASE_URL")
+
+	plaidCountries := os.Getenv("PLAID_COUNTRY_CODES")
+	if plaidCountries == "" {
+		plaidCountries = "CA"
+	}
+
+	plaidProducts := os.Getenv("PLAID_PRODUCTS")
+	if plaidProducts == "" {
+		plaidProducts = "transactions"
+	}
+
+	plaidOptional, ok := os.LookupEnv("PLAID_OPTIONAL_PRODUCTS")
+	if !ok {
+		plaidOptional = "investments,liabilities"
+	}
+
+	plaidFilters := os.Getenv("PLAID_ACCOUNT_FILTERS")
+	if plaidFilters == "" {
+		plaidFilters = "depository:checking,savings;credit:credit card;loan:all;investment:all"
+	}
+
+	plaidDays := 730
+	if days := os.Getenv("PLAID_DAYS_REQUESTED"); days != "" {
+		plaidDays, err = strconv.Atoi(days)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing PLAID_DAYS_REQUESTED variable to integer: %s", days)
+		}
+	}
+
 	aesKey := os.Getenv("AES_KEY")
 	if aesKey == "" {
 		return nil, fmt.Errorf("AES_KEY environment variable not set")
 	}
 
+	// Cloud Run allows 10 seconds between SIGTERM and SIGKILL. Draining is given less than that, so
+	// background workers still have time to stop and the database to close before the process is killed
+	shutdownTimeout := 8 * time.Second
+	if timeout := os.Getenv("SHUTDOWN_TIMEOUT"); timeout != "" {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("error parsing SHUTDOWN_TIMEOUT variable to positive integer: %s", timeout)
+		}
+		shutdownTimeout = time.Duration(seconds) * time.Second
+	}
+
+	attachmentsPath := os.Getenv("ATTACHMENTS_PATH")
+	if attachmentsPath == "" {
+		attachmentsPath = "attachments"
+	}
+
+	attachmentMaxSize := int64(10 << 20)
+	if size := os.Getenv("ATTACHMENT_MAX_BYTES"); size != "" {
+		attachmentMaxSize, err = strconv.ParseInt(size, 10, 64)
+		if err != nil || attachmentMaxSize <= 0 {
+			return nil, fmt.Errorf("error parsing ATTACHMENT_MAX_BYTES variable to positive integer: %s", size)
+		}
+	}
+
 	config := Config{
 		Port:              port,
 		Environment:       environment,
@@ -144,7 +208,16 @@ func LoadConfig() (*Config, error) {
 		PlaidSecret:       plaidSecret,
 		PlaidSbSecret:     plaidsbSecret,
 		PlaidWebhookURL:   plaidWebhookURL,
+		PlaidBaseURL:      plaidBaseURL,
+		PlaidCountries:    plaidCountries,
+		PlaidProducts:     plaidProducts,
+		PlaidOptional:     plaidOptional,
+		PlaidFilters:      plaidFilters,
+		PlaidDays:         plaidDays,
 		AESKey:            aesKey,
+		ShutdownTimeout:   shutdownTimeout,
+		AttachmentsPath:   attachmentsPath,
+		AttachmentMaxSize: attachmentMaxSize,
 	}
 
 	return &config, nil
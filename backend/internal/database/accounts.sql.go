@@ -47,7 +47,7 @@ ON CONFLICT (id) DO UPDATE SET
     current_balance = EXCLUDED.current_balance,
     available_balance = EXCLUDED.available_balance,
     updated_at = NOW()
-RETURNING id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id
+RETURNING id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id, is_manual, opening_balance
 `
 
 type CreateAccountParams struct {
@@ -60,7 +60,7 @@ type CreateAccountParams struct {
 	AvailableBalance sql.NullString
 	CurrentBalance   sql.NullString
 	IsoCurrencyCode  sql.NullString
-	ItemID           string
+	ItemID           sql.NullString
 	UserID           uuid.UUID
 }
 
@@ -93,11 +93,92 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.IsoCurrencyCode,
 		&i.ItemID,
 		&i.UserID,
+		&i.IsManual,
+		&i.OpeningBalance,
+	)
+	return i, err
+}
+
+const createManualAccount = `-- name: CreateManualAccount :one
+INSERT INTO accounts(
+    id,
+    created_at,
+    updated_at,
+    name,
+    type,
+    subtype,
+    iso_currency_code,
+    opening_balance,
+    current_balance,
+    available_balance,
+    is_manual,
+    user_id)
+VALUES (
+    $1,
+    NOW(),
+    NOW(),
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $6,
+    CASE WHEN $3 IN ('credit', 'loan') THEN NULL ELSE $6 END,
+    TRUE,
+    $7
+)
+RETURNING id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id, is_manual, opening_balance
+`
+
+type CreateManualAccountParams struct {
+	ID              string
+	Name            string
+	Type            string
+	Subtype         sql.NullString
+	IsoCurrencyCode sql.NullString
+	OpeningBalance  sql.NullString
+	UserID          uuid.UUID
+}
+
+func (q *Queries) CreateManualAccount(ctx context.Context, arg CreateManualAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, createManualAccount,
+		arg.ID,
+		arg.Name,
+		arg.Type,
+		arg.Subtype,
+		arg.IsoCurrencyCode,
+		arg.OpeningBalance,
+		arg.UserID,
+	)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Type,
+		&i.Subtype,
+		&i.Mask,
+		&i.OfficialName,
+		&i.AvailableBalance,
+		&i.CurrentBalance,
+		&i.IsoCurrencyCode,
+		&i.ItemID,
+		&i.UserID,
+		&i.IsManual,
+		&i.OpeningBalance,
 	)
 	return i, err
 }
 
 const deleteAccount = `-- name: DeleteAccount :exec
+WITH removed AS (
+    INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type, created_at)
+    SELECT a.user_id, t.id, t.account_id, 'removed', NOW()
+    FROM transactions AS t
+    INNER JOIN accounts AS a ON t.account_id = a.id
+    WHERE a.id = $1 AND a.user_id = $2
+)
 DELETE FROM accounts
 WHERE id = $1 AND user_id = $2
 `
@@ -113,7 +194,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, arg DeleteAccountParams) er
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id FROM accounts
+SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id, is_manual, opening_balance FROM accounts
 WHERE name = $1
 `
 
@@ -134,12 +215,14 @@ func (q *Queries) GetAccount(ctx context.Context, name string) (Account, error)
 		&i.IsoCurrencyCode,
 		&i.ItemID,
 		&i.UserID,
+		&i.IsManual,
+		&i.OpeningBalance,
 	)
 	return i, err
 }
 
 const getAccountById = `-- name: GetAccountById :one
-SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id FROM accounts
+SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id, is_manual, opening_balance FROM accounts
 WHERE id = $1 AND user_id = $2
 `
 
@@ -165,13 +248,15 @@ func (q *Queries) GetAccountById(ctx context.Context, arg GetAccountByIdParams)
 		&i.IsoCurrencyCode,
 		&i.ItemID,
 		&i.UserID,
+		&i.IsManual,
+		&i.OpeningBalance,
 	)
 	return i, err
 }
 
 const getAccountsForItem = `-- name: GetAccountsForItem :many
-SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id FROM accounts
-WHERE item_id = $1
+SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id, is_manual, opening_balance FROM accounts
+WHERE item_id = $1::text
 `
 
 func (q *Queries) GetAccountsForItem(ctx context.Context, itemID string) ([]Account, error) {
@@ -197,6 +282,8 @@ func (q *Queries) GetAccountsForItem(ctx context.Context, itemID string) ([]Acco
 			&i.IsoCurrencyCode,
 			&i.ItemID,
 			&i.UserID,
+			&i.IsManual,
+			&i.OpeningBalance,
 		); err != nil {
 			return nil, err
 		}
@@ -212,7 +299,7 @@ func (q *Queries) GetAccountsForItem(ctx context.Context, itemID string) ([]Acco
 }
 
 const getAllAccountsForUser = `-- name: GetAllAccountsForUser :many
-SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id FROM accounts
+SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id, is_manual, opening_balance FROM accounts
 WHERE user_id = $1
 `
 
@@ -239,6 +326,8 @@ func (q *Queries) GetAllAccountsForUser(ctx context.Context, userID uuid.UUID) (
 			&i.IsoCurrencyCode,
 			&i.ItemID,
 			&i.UserID,
+			&i.IsManual,
+			&i.OpeningBalance,
 		); err != nil {
 			return nil, err
 		}
@@ -253,6 +342,44 @@ func (q *Queries) GetAllAccountsForUser(ctx context.Context, userID uuid.UUID) (
 	return items, nil
 }
 
+const recalculateManualBalance = `-- name: RecalculateManualBalance :one
+WITH total AS (
+    SELECT COALESCE(SUM(amount), 0) AS amount
+    FROM transactions
+    WHERE account_id = $1
+)
+UPDATE accounts
+SET current_balance = COALESCE(opening_balance, 0) + CASE WHEN type IN ('credit', 'loan') THEN total.amount ELSE -total.amount END,
+    available_balance = CASE WHEN type IN ('credit', 'loan') THEN NULL ELSE COALESCE(opening_balance, 0) - total.amount END,
+    updated_at = NOW()
+FROM total
+WHERE id = $1 AND is_manual
+RETURNING accounts.id, accounts.created_at, accounts.updated_at, accounts.name, accounts.type, accounts.subtype, accounts.mask, accounts.official_name, accounts.available_balance, accounts.current_balance, accounts.iso_currency_code, accounts.item_id, accounts.user_id, accounts.is_manual, accounts.opening_balance
+`
+
+func (q *Queries) RecalculateManualBalance(ctx context.Context, accountID string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, recalculateManualBalance, accountID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Type,
+		&i.Subtype,
+		&i.Mask,
+		&i.OfficialName,
+		&i.AvailableBalance,
+		&i.CurrentBalance,
+		&i.IsoCurrencyCode,
+		&i.ItemID,
+		&i.UserID,
+		&i.IsManual,
+		&i.OpeningBalance,
+	)
+	return i, err
+}
+
 const resetAccounts = `-- name: ResetAccounts :exec
 DELETE FROM accounts
 `
@@ -265,8 +392,8 @@ func (q *Queries) ResetAccounts(ctx context.Context) error {
 const updateBalances = `-- name: UpdateBalances :one
 UPDATE accounts
 SET available_balance = $1, current_balance = $2, updated_at = NOW()
-WHERE id = $3 AND item_id = $4
-RETURNING id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id
+WHERE id = $3 AND item_id = $4::text
+RETURNING id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, item_id, user_id, is_manual, opening_balance
 `
 
 type UpdateBalancesParams struct {
@@ -298,6 +425,8 @@ func (q *Queries) UpdateBalances(ctx context.Context, arg UpdateBalancesParams)
 		&i.IsoCurrencyCode,
 		&i.ItemID,
 		&i.UserID,
+		&i.IsManual,
+		&i.OpeningBalance,
 	)
 	return i, err
 }
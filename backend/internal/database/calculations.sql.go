@@ -8,11 +8,13 @@ package database
 import (
 	"context"
 	"database/sql"
+
+	"github.com/google/uuid"
 )
 
 const getMerchantSummary = `-- name: GetMerchantSummary :many
 SELECT
-  merchant_name AS merchant,
+  COALESCE(canonical_merchant, NULLIF(merchant_name, ''), name, '')::text AS merchant,
   COUNT(*) AS txn_count,
   personal_finance_category AS category,
   SUM(amount)::float AS total_amount,
@@ -24,7 +26,7 @@ ORDER BY month DESC, txn_count DESC
 `
 
 type GetMerchantSummaryRow struct {
-	Merchant    sql.NullString
+	Merchant    string
 	TxnCount    int64
 	Category    string
 	TotalAmount float64
@@ -62,7 +64,7 @@ func (q *Queries) GetMerchantSummary(ctx context.Context, accountID string) ([]G
 
 const getMerchantSummaryByMonth = `-- name: GetMerchantSummaryByMonth :many
 SELECT
-  merchant_name AS merchant,
+  COALESCE(canonical_merchant, NULLIF(merchant_name, ''), name, '')::text AS merchant,
   COUNT(*) AS txn_count,
   personal_finance_category AS category,
   SUM(amount)::float AS total_amount,
@@ -82,7 +84,7 @@ type GetMerchantSummaryByMonthParams struct {
 }
 
 type GetMerchantSummaryByMonthRow struct {
-	Merchant    sql.NullString
+	Merchant    string
 	TxnCount    int64
 	Category    string
 	TotalAmount float64
@@ -197,3 +199,121 @@ func (q *Queries) GetMonetaryDataForMonth(ctx context.Context, arg GetMonetaryDa
 	err := row.Scan(&i.Income, &i.Expenses, &i.NetIncome)
 	return i, err
 }
+
+const getSpendingBreakdown = `-- name: GetSpendingBreakdown :many
+SELECT
+  personal_finance_category AS category,
+  COALESCE(canonical_merchant, NULLIF(merchant_name, ''), name, '')::text AS merchant,
+  COUNT(*) AS txn_count,
+  SUM(amount)::float AS total_amount
+FROM transactions
+WHERE account_id = $1
+  AND date >= $2
+  AND date < $3
+GROUP BY category, merchant
+`
+
+type GetSpendingBreakdownParams struct {
+	AccountID string
+	StartDate sql.NullTime
+	EndDate   sql.NullTime
+}
+
+type GetSpendingBreakdownRow struct {
+	Category    string
+	Merchant    string
+	TxnCount    int64
+	TotalAmount float64
+}
+
+func (q *Queries) GetSpendingBreakdown(ctx context.Context, arg GetSpendingBreakdownParams) ([]GetSpendingBreakdownRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSpendingBreakdown, arg.AccountID, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSpendingBreakdownRow
+	for rows.Next() {
+		var i GetSpendingBreakdownRow
+		if err := rows.Scan(
+			&i.Category,
+			&i.Merchant,
+			&i.TxnCount,
+			&i.TotalAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSpendingBreakdown = `-- name: GetUserSpendingBreakdown :many
+SELECT
+  t.personal_finance_category AS category,
+  COALESCE(t.canonical_merchant, NULLIF(t.merchant_name, ''), t.name, '')::text AS merchant,
+  COUNT(*) AS txn_count,
+  SUM(t.amount)::float AS total_amount
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+LEFT JOIN account_overrides o ON o.account_id = a.id
+WHERE a.user_id = $1
+  AND (a.type IN ('depository', 'credit') OR a.is_manual)
+  AND t.date >= $2
+  AND t.date < $3
+  AND ($4::boolean OR NOT COALESCE(o.hidden OR o.archived, FALSE))
+GROUP BY category, merchant
+`
+
+type GetUserSpendingBreakdownParams struct {
+	UserID        uuid.UUID
+	StartDate     sql.NullTime
+	EndDate       sql.NullTime
+	IncludeHidden bool
+}
+
+type GetUserSpendingBreakdownRow struct {
+	Category    string
+	Merchant    string
+	TxnCount    int64
+	TotalAmount float64
+}
+
+func (q *Queries) GetUserSpendingBreakdown(ctx context.Context, arg GetUserSpendingBreakdownParams) ([]GetUserSpendingBreakdownRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserSpendingBreakdown,
+		arg.UserID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.IncludeHidden,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserSpendingBreakdownRow
+	for rows.Next() {
+		var i GetUserSpendingBreakdownRow
+		if err := rows.Scan(
+			&i.Category,
+			&i.Merchant,
+			&i.TxnCount,
+			&i.TotalAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,39 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+)
+
+func TestEventsCommitInSeqOrder(t *testing.T) {
+	db := openTestDB(t)
+	userID := createTestUser(t, db)
+	ctx := context.Background()
+
+	write := func(q *database.Queries, label string) error {
+		return q.CreateEvent(ctx, database.CreateEventParams{
+			ID:      uuid.New(),
+			UserID:  userID,
+			Type:    label,
+			Payload: json.RawMessage(`{}`),
+		})
+	}
+	read := func() ([]string, error) {
+		events, err := database.New(db).GetEventsForUser(ctx, database.GetEventsForUserParams{
+			UserID:   userID,
+			AfterSeq: 0,
+			RowLimit: 10,
+		})
+		labels := []string{}
+		for _, e := range events {
+			labels = append(labels, e.Type)
+		}
+		return labels, err
+	}
+
+	testWritesCommitInSeqOrder(t, db, write, read)
+}
@@ -6,6 +6,7 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,8 +24,44 @@ type Account struct {
 	AvailableBalance sql.NullString
 	CurrentBalance   sql.NullString
 	IsoCurrencyCode  sql.NullString
-	ItemID           string
+	ItemID           sql.NullString
 	UserID           uuid.UUID
+	IsManual         bool
+	OpeningBalance   sql.NullString
+}
+
+type AccountOverride struct {
+	AccountID         string
+	UserID            uuid.UUID
+	Nickname          sql.NullString
+	Hidden            bool
+	Archived          bool
+	IncludeInNetWorth bool
+	SortOrder         int32
+	UpdatedAt         time.Time
+}
+
+type Anomaly struct {
+	ID             uuid.UUID
+	TransactionID  string
+	UserID         uuid.UUID
+	Kind           string
+	Reason         string
+	Score          float64
+	CreatedAt      time.Time
+	AcknowledgedAt sql.NullTime
+}
+
+type ApiKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	KeyHash    string
+	Prefix     string
+	Scope      string
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	CreatedAt  time.Time
 }
 
 type Delegation struct {
@@ -37,6 +74,148 @@ type Delegation struct {
 	LastUsed  time.Time
 }
 
+type Event struct {
+	ID           uuid.UUID
+	Seq          int64
+	UserID       uuid.UUID
+	Type         string
+	Payload      json.RawMessage
+	CreatedAt    time.Time
+	DispatchedAt sql.NullTime
+}
+
+type EventDelivery struct {
+	ID            uuid.UUID
+	EventID       uuid.UUID
+	EndpointID    uuid.UUID
+	Status        string
+	Attempts      int32
+	LastError     sql.NullString
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	DeliveredAt   sql.NullTime
+}
+
+type EventEndpoint struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Url        string
+	Secret     string
+	EventTypes []string
+	Enabled    bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type Holding struct {
+	AccountID        string
+	SecurityID       string
+	Quantity         string
+	InstitutionPrice string
+	InstitutionValue string
+	CostBasis        sql.NullString
+	IsoCurrencyCode  sql.NullString
+	UpdatedAt        time.Time
+}
+
+type InvestmentTransaction struct {
+	ID              string
+	AccountID       string
+	SecurityID      sql.NullString
+	Date            time.Time
+	Name            string
+	Type            string
+	Subtype         string
+	Quantity        string
+	Price           string
+	Amount          string
+	Fees            sql.NullString
+	IsoCurrencyCode sql.NullString
+	CreatedAt       time.Time
+}
+
+type ItemStatus struct {
+	ItemID           string
+	UserID           uuid.UUID
+	LastSyncedAt     sql.NullTime
+	LastErrorCode    sql.NullString
+	LastErrorAt      sql.NullTime
+	ConsentExpiresAt sql.NullTime
+	UpdatedAt        time.Time
+}
+
+type Liability struct {
+	AccountID            string
+	Type                 string
+	InterestRate         sql.NullString
+	InterestRateType     sql.NullString
+	MinimumPayment       sql.NullString
+	NextPaymentDueDate   sql.NullTime
+	LastStatementBalance sql.NullString
+	LastStatementDate    sql.NullTime
+	LastPaymentAmount    sql.NullString
+	LastPaymentDate      sql.NullTime
+	IsOverdue            sql.NullBool
+	OriginationPrincipal sql.NullString
+	OriginationDate      sql.NullTime
+	MaturityDate         sql.NullTime
+	LoanTerm             sql.NullString
+	LoanName             sql.NullString
+	UpdatedAt            time.Time
+}
+
+type LiabilityApr struct {
+	AccountID            string
+	AprType              string
+	AprPercentage        string
+	BalanceSubjectToApr  sql.NullString
+	InterestChargeAmount sql.NullString
+}
+
+type MerchantAlias struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	RawName   string
+	MatchKey  string
+	Merchant  string
+	CreatedAt time.Time
+}
+
+type NotificationDelivery struct {
+	ID            uuid.UUID
+	RuleID        uuid.UUID
+	UserID        uuid.UUID
+	EventKey      string
+	Subject       string
+	Message       string
+	Status        string
+	Attempts      int32
+	LastError     sql.NullString
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	DeliveredAt   sql.NullTime
+}
+
+type NotificationRule struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	Kind          string
+	Threshold     sql.NullString
+	Category      sql.NullString
+	AccountID     sql.NullString
+	Channel       string
+	WebhookUrl    sql.NullString
+	WebhookSecret sql.NullString
+	Enabled       bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type OrphanedAttachmentBlob struct {
+	StorageKey string
+	DeletedAt  time.Time
+}
+
 type PlaidItem struct {
 	ID                    string
 	UserID                uuid.UUID
@@ -46,6 +225,7 @@ type PlaidItem struct {
 	TransactionSyncCursor sql.NullString
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
+	CountryCode           string
 }
 
 type PlaidWebhookRecord struct {
@@ -82,6 +262,16 @@ type RefreshToken struct {
 	UsedAt       sql.NullTime
 }
 
+type Security struct {
+	ID              string
+	Name            sql.NullString
+	TickerSymbol    sql.NullString
+	Type            sql.NullString
+	ClosePrice      sql.NullString
+	IsoCurrencyCode sql.NullString
+	UpdatedAt       time.Time
+}
+
 type Transaction struct {
 	ID                      string
 	AccountID               string
@@ -93,6 +283,41 @@ type Transaction struct {
 	PersonalFinanceCategory string
 	CreatedAt               time.Time
 	UpdatedAt               time.Time
+	Name                    sql.NullString
+	CanonicalMerchant       sql.NullString
+}
+
+type TransactionChange struct {
+	Seq           int64
+	UserID        uuid.UUID
+	TransactionID string
+	AccountID     string
+	ChangeType    string
+	CreatedAt     time.Time
+}
+
+type TransactionAttachment struct {
+	ID            uuid.UUID
+	TransactionID string
+	UserID        uuid.UUID
+	FileName      string
+	ContentType   string
+	SizeBytes     int64
+	Sha256        string
+	StorageKey    string
+	CreatedAt     time.Time
+}
+
+type TransactionCategoryOverride struct {
+	TransactionID string
+	Category      string
+	UpdatedAt     time.Time
+}
+
+type TransactionNote struct {
+	TransactionID string
+	Note          string
+	UpdatedAt     time.Time
 }
 
 type TransactionTag struct {
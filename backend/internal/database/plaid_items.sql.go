@@ -13,7 +13,7 @@ import (
 )
 
 const createItem = `-- name: CreateItem :one
-INSERT INTO plaid_items(id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at)
+INSERT INTO plaid_items(id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, country_code, created_at, updated_at)
 VALUES (
     $1,
     $2,
@@ -21,10 +21,11 @@ VALUES (
     $4,
     $5,
     $6,
+    $7,
     NOW(),
     NOW()
 )
-RETURNING id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at
+RETURNING id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at, country_code
 `
 
 type CreateItemParams struct {
@@ -34,6 +35,7 @@ type CreateItemParams struct {
 	InstitutionName       string
 	Nickname              sql.NullString
 	TransactionSyncCursor sql.NullString
+	CountryCode           string
 }
 
 func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (PlaidItem, error) {
@@ -44,6 +46,7 @@ func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (PlaidIt
 		arg.InstitutionName,
 		arg.Nickname,
 		arg.TransactionSyncCursor,
+		arg.CountryCode,
 	)
 	var i PlaidItem
 	err := row.Scan(
@@ -55,11 +58,19 @@ func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (PlaidIt
 		&i.TransactionSyncCursor,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CountryCode,
 	)
 	return i, err
 }
 
 const deleteItem = `-- name: DeleteItem :exec
+WITH removed AS (
+    INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type, created_at)
+    SELECT a.user_id, t.id, t.account_id, 'removed', NOW()
+    FROM transactions AS t
+    INNER JOIN accounts AS a ON t.account_id = a.id
+    WHERE a.item_id = $1 AND a.user_id = $2
+)
 DELETE FROM plaid_items
 WHERE id = $1 AND user_id = $2
 `
@@ -75,7 +86,7 @@ func (q *Queries) DeleteItem(ctx context.Context, arg DeleteItemParams) error {
 }
 
 const getAccessToken = `-- name: GetAccessToken :one
-SELECT id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at FROM plaid_items
+SELECT id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at, country_code FROM plaid_items
 WHERE id = $1
 `
 
@@ -91,6 +102,7 @@ func (q *Queries) GetAccessToken(ctx context.Context, id string) (PlaidItem, err
 		&i.TransactionSyncCursor,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CountryCode,
 	)
 	return i, err
 }
@@ -113,7 +125,7 @@ func (q *Queries) GetCursor(ctx context.Context, arg GetCursorParams) (sql.NullS
 }
 
 const getItemByID = `-- name: GetItemByID :one
-SELECT id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at FROM plaid_items
+SELECT id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at, country_code FROM plaid_items
 WHERE id = $1
 `
 
@@ -129,12 +141,13 @@ func (q *Queries) GetItemByID(ctx context.Context, id string) (PlaidItem, error)
 		&i.TransactionSyncCursor,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CountryCode,
 	)
 	return i, err
 }
 
 const getItemByName = `-- name: GetItemByName :one
-SELECT id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at FROM plaid_items
+SELECT id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at, country_code FROM plaid_items
 WHERE nickname = $1
 `
 
@@ -150,12 +163,13 @@ func (q *Queries) GetItemByName(ctx context.Context, nickname sql.NullString) (P
 		&i.TransactionSyncCursor,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CountryCode,
 	)
 	return i, err
 }
 
 const getItemsByUser = `-- name: GetItemsByUser :many
-SELECT id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at FROM plaid_items
+SELECT id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at, country_code FROM plaid_items
 WHERE user_id = $1
 `
 
@@ -177,6 +191,7 @@ func (q *Queries) GetItemsByUser(ctx context.Context, userID uuid.UUID) ([]Plaid
 			&i.TransactionSyncCursor,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CountryCode,
 		); err != nil {
 			return nil, err
 		}
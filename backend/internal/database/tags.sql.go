@@ -126,3 +126,39 @@ func (q *Queries) GetTag(ctx context.Context, arg GetTagParams) (TransactionTag,
 	)
 	return i, err
 }
+
+const upsertTag = `-- name: UpsertTag :one
+INSERT INTO transaction_tags (
+    id,
+    name,
+    user_id,
+    created_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    NOW()
+)
+ON CONFLICT (name, user_id) DO UPDATE SET
+    name = EXCLUDED.name
+RETURNING id, name, user_id, created_at
+`
+
+type UpsertTagParams struct {
+	ID     uuid.UUID
+	Name   string
+	UserID uuid.UUID
+}
+
+func (q *Queries) UpsertTag(ctx context.Context, arg UpsertTagParams) (TransactionTag, error) {
+	row := q.db.QueryRowContext(ctx, upsertTag, arg.ID, arg.Name, arg.UserID)
+	var i TransactionTag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.UserID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,234 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	_ "github.com/lib/pq"
+)
+
+// Opens the migrated test database named by GREED_TEST_DATABASE_URL, skipping the test when it isn't set
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("GREED_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("GREED_TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("error opening test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// Creates a user removed again when the test ends, along with their feed rows
+func createTestUser(t *testing.T, db *sql.DB) uuid.UUID {
+	t.Helper()
+
+	id := uuid.New()
+	_, err := database.New(db).CreateUser(context.Background(), database.CreateUserParams{
+		ID:             id,
+		Name:           "feed-" + id.String()[:8],
+		HashedPassword: "x",
+		Email:          id.String() + "@example.com",
+	})
+	if err != nil {
+		t.Fatalf("error creating test user: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users WHERE id = $1", id)
+	})
+
+	return id
+}
+
+// Interleaves two writes to one of a user's feeds, the second starting after the first and trying to commit
+// before it, and checks the feed shows neither until the first commits, then both in the order they began.
+// write records a row labelled with the given name, and read returns the labels of the feed's rows in seq order
+func testWritesCommitInSeqOrder(t *testing.T, db *sql.DB, write func(q *database.Queries, label string) error, read func() ([]string, error)) {
+	t.Helper()
+	ctx := context.Background()
+
+	first, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("error beginning transaction: %v", err)
+	}
+	defer first.Rollback()
+	if err := write(database.New(db).WithTx(first), "first"); err != nil {
+		t.Fatalf("error in first write: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		second, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			done <- err
+			return
+		}
+		defer second.Rollback()
+		if err := write(database.New(db).WithTx(second), "second"); err != nil {
+			done <- err
+			return
+		}
+		done <- second.Commit()
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("second write committed while the first was open (err: %v)", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	labels, err := read()
+	if err != nil {
+		t.Fatalf("error reading feed: %v", err)
+	}
+	if len(labels) != 0 {
+		t.Fatalf("expected no visible rows while both writes are open, got %v", labels)
+	}
+
+	if err := first.Commit(); err != nil {
+		t.Fatalf("error committing first write: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("error in second write: %v", err)
+	}
+
+	labels, err = read()
+	if err != nil {
+		t.Fatalf("error reading feed: %v", err)
+	}
+	if len(labels) != 2 || labels[0] != "first" || labels[1] != "second" {
+		t.Errorf("expected the first commit to have the lower seq, got %v", labels)
+	}
+}
+
+func TestTransactionChangesCommitInSeqOrder(t *testing.T) {
+	db := openTestDB(t)
+	userID := createTestUser(t, db)
+	ctx := context.Background()
+
+	write := func(q *database.Queries, label string) error {
+		return q.RecordTransactionChanges(ctx, database.RecordTransactionChangesParams{
+			UserID:         userID,
+			TransactionIds: []string{label},
+			AccountIds:     []string{"acc"},
+			ChangeType:     "modified",
+		})
+	}
+	read := func() ([]string, error) {
+		changes, err := database.New(db).GetTransactionChanges(ctx, database.GetTransactionChangesParams{
+			UserID:   userID,
+			AfterSeq: 0,
+			RowLimit: 10,
+		})
+		labels := []string{}
+		for _, c := range changes {
+			labels = append(labels, c.TransactionID)
+		}
+		return labels, err
+	}
+
+	testWritesCommitInSeqOrder(t, db, write, read)
+}
+
+// Creates an item with an account holding one transaction for the user, returning their IDs
+func createTestTransaction(t *testing.T, q *database.Queries, userID uuid.UUID) (itemID, accountID, txnID string) {
+	t.Helper()
+	ctx := context.Background()
+
+	item, err := q.CreateItem(ctx, database.CreateItemParams{
+		ID:              "item-" + uuid.NewString(),
+		UserID:          userID,
+		AccessToken:     "access",
+		InstitutionName: "Test Bank",
+		CountryCode:     "CA",
+	})
+	if err != nil {
+		t.Fatalf("error creating item: %v", err)
+	}
+
+	acc, err := q.CreateAccount(ctx, database.CreateAccountParams{
+		ID:     "acc-" + uuid.NewString(),
+		Name:   "Chequing",
+		Type:   "depository",
+		ItemID: sql.NullString{String: item.ID, Valid: true},
+		UserID: userID,
+	})
+	if err != nil {
+		t.Fatalf("error creating account: %v", err)
+	}
+
+	txn, err := q.CreateTransaction(ctx, database.CreateTransactionParams{
+		ID:                      "txn-" + uuid.NewString(),
+		AccountID:               acc.ID,
+		Amount:                  "12.50",
+		PaymentChannel:          "in store",
+		PersonalFinanceCategory: "FOOD_AND_DRINK",
+	})
+	if err != nil {
+		t.Fatalf("error creating transaction: %v", err)
+	}
+
+	return item.ID, acc.ID, txn.ID
+}
+
+func TestDeletesRecordRemovedChanges(t *testing.T) {
+	db := openTestDB(t)
+	q := database.New(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		delete func(userID uuid.UUID, itemID, accountID string) error
+	}{
+		{
+			name: "delete account",
+			delete: func(userID uuid.UUID, _, accountID string) error {
+				return q.DeleteAccount(ctx, database.DeleteAccountParams{ID: accountID, UserID: userID})
+			},
+		},
+		{
+			name: "delete item",
+			delete: func(userID uuid.UUID, itemID, _ string) error {
+				return q.DeleteItem(ctx, database.DeleteItemParams{ID: itemID, UserID: userID})
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			userID := createTestUser(t, db)
+			itemID, accountID, txnID := createTestTransaction(t, q, userID)
+
+			if err := tc.delete(userID, itemID, accountID); err != nil {
+				t.Fatalf("error deleting: %v", err)
+			}
+
+			changes, err := q.GetTransactionChanges(ctx, database.GetTransactionChangesParams{
+				UserID:   userID,
+				AfterSeq: 0,
+				RowLimit: 10,
+			})
+			if err != nil {
+				t.Fatalf("error reading changes: %v", err)
+			}
+			if len(changes) != 1 {
+				t.Fatalf("expected 1 change, got %d", len(changes))
+			}
+			if changes[0].TransactionID != txnID || changes[0].AccountID != accountID || changes[0].ChangeType != "removed" {
+				t.Errorf("expected %s removed from %s, got %s %s from %s",
+					txnID, accountID, changes[0].TransactionID, changes[0].ChangeType, changes[0].AccountID)
+			}
+		})
+	}
+}
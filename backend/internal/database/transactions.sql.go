@@ -31,6 +31,7 @@ INSERT INTO transactions (
     merchant_name,
     payment_channel,
     personal_finance_category,
+    canonical_merchant,
     created_at,
     updated_at)
 VALUES (
@@ -42,10 +43,11 @@ VALUES (
     $6,
     $7,
     $8,
+    $9,
     NOW(),
     NOW()
 )
-RETURNING id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category, created_at, updated_at
+RETURNING id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category, created_at, updated_at, name, canonical_merchant
 `
 
 type CreateTransactionParams struct {
@@ -57,6 +59,7 @@ type CreateTransactionParams struct {
 	MerchantName            sql.NullString
 	PaymentChannel          string
 	PersonalFinanceCategory string
+	CanonicalMerchant       sql.NullString
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -69,6 +72,7 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.MerchantName,
 		arg.PaymentChannel,
 		arg.PersonalFinanceCategory,
+		arg.CanonicalMerchant,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -82,6 +86,8 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.PersonalFinanceCategory,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Name,
+		&i.CanonicalMerchant,
 	)
 	return i, err
 }
@@ -102,8 +108,15 @@ func (q *Queries) DeleteTransaction(ctx context.Context, arg DeleteTransactionPa
 }
 
 const deleteTransactionsForAccount = `-- name: DeleteTransactionsForAccount :exec
-DELETE FROM transactions
-WHERE account_id = $1
+WITH deleted AS (
+    DELETE FROM transactions
+    WHERE account_id = $1
+    RETURNING id, account_id
+)
+INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type, created_at)
+SELECT a.user_id, d.id, d.account_id, 'removed', NOW()
+FROM deleted AS d
+INNER JOIN accounts AS a ON d.account_id = a.id
 `
 
 func (q *Queries) DeleteTransactionsForAccount(ctx context.Context, accountID string) error {
@@ -111,8 +124,38 @@ func (q *Queries) DeleteTransactionsForAccount(ctx context.Context, accountID st
 	return err
 }
 
+const getTransactionForAccount = `-- name: GetTransactionForAccount :one
+SELECT id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category, created_at, updated_at, name, canonical_merchant FROM transactions
+WHERE id = $1 AND account_id = $2
+`
+
+type GetTransactionForAccountParams struct {
+	ID        string
+	AccountID string
+}
+
+func (q *Queries) GetTransactionForAccount(ctx context.Context, arg GetTransactionForAccountParams) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, getTransactionForAccount, arg.ID, arg.AccountID)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.IsoCurrencyCode,
+		&i.Date,
+		&i.MerchantName,
+		&i.PaymentChannel,
+		&i.PersonalFinanceCategory,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.CanonicalMerchant,
+	)
+	return i, err
+}
+
 const getTransactions = `-- name: GetTransactions :many
-SELECT id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category, created_at, updated_at FROM transactions
+SELECT id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category, created_at, updated_at, name, canonical_merchant FROM transactions
 WHERE account_id = $1
 `
 
@@ -136,6 +179,8 @@ func (q *Queries) GetTransactions(ctx context.Context, accountID string) ([]Tran
 			&i.PersonalFinanceCategory,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Name,
+			&i.CanonicalMerchant,
 		); err != nil {
 			return nil, err
 		}
@@ -151,7 +196,7 @@ func (q *Queries) GetTransactions(ctx context.Context, accountID string) ([]Tran
 }
 
 const getTransactionsForUser = `-- name: GetTransactionsForUser :many
-SELECT t.id, t.account_id, t.amount, t.iso_currency_code, t.date, t.merchant_name, t.payment_channel, t.personal_finance_category, t.created_at, t.updated_at 
+SELECT t.id, t.account_id, t.amount, t.iso_currency_code, t.date, t.merchant_name, t.payment_channel, t.personal_finance_category, t.created_at, t.updated_at, t.name, t.canonical_merchant 
 FROM transactions AS t
 INNER JOIN accounts AS a ON t.account_id = a.id
 WHERE a.user_id = $1
@@ -177,6 +222,8 @@ func (q *Queries) GetTransactionsForUser(ctx context.Context, userID uuid.UUID)
 			&i.PersonalFinanceCategory,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Name,
+			&i.CanonicalMerchant,
 		); err != nil {
 			return nil, err
 		}
@@ -190,3 +237,112 @@ func (q *Queries) GetTransactionsForUser(ctx context.Context, userID uuid.UUID)
 	}
 	return items, nil
 }
+
+const updateManualTransaction = `-- name: UpdateManualTransaction :one
+UPDATE transactions
+SET amount = $3,
+    iso_currency_code = $4,
+    date = $5,
+    merchant_name = $6,
+    payment_channel = $7,
+    personal_finance_category = $8,
+    canonical_merchant = $9,
+    updated_at = NOW()
+WHERE id = $1 AND account_id = $2
+RETURNING id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category, created_at, updated_at, name, canonical_merchant
+`
+
+type UpdateManualTransactionParams struct {
+	ID                      string
+	AccountID               string
+	Amount                  string
+	IsoCurrencyCode         sql.NullString
+	Date                    sql.NullTime
+	MerchantName            sql.NullString
+	PaymentChannel          string
+	PersonalFinanceCategory string
+	CanonicalMerchant       sql.NullString
+}
+
+func (q *Queries) UpdateManualTransaction(ctx context.Context, arg UpdateManualTransactionParams) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, updateManualTransaction,
+		arg.ID,
+		arg.AccountID,
+		arg.Amount,
+		arg.IsoCurrencyCode,
+		arg.Date,
+		arg.MerchantName,
+		arg.PaymentChannel,
+		arg.PersonalFinanceCategory,
+		arg.CanonicalMerchant,
+	)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.IsoCurrencyCode,
+		&i.Date,
+		&i.MerchantName,
+		&i.PaymentChannel,
+		&i.PersonalFinanceCategory,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.CanonicalMerchant,
+	)
+	return i, err
+}
+
+const updateTransactionCategory = `-- name: UpdateTransactionCategory :one
+WITH override AS (
+    INSERT INTO transaction_category_overrides (
+        transaction_id,
+        category,
+        updated_at
+    )
+    SELECT id, $2, NOW()
+    FROM transactions
+    WHERE id = $1 AND account_id = $3
+    ON CONFLICT (transaction_id) DO UPDATE SET
+        category = EXCLUDED.category,
+        updated_at = NOW()
+), change AS (
+    INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type, created_at)
+    SELECT a.user_id, t.id, t.account_id, 'modified', NOW()
+    FROM transactions AS t
+    INNER JOIN accounts AS a ON t.account_id = a.id
+    WHERE t.id = $1 AND t.account_id = $3
+)
+UPDATE transactions
+SET personal_finance_category = $2,
+    updated_at = NOW()
+WHERE id = $1 AND account_id = $3
+RETURNING id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category, created_at, updated_at, name, canonical_merchant
+`
+
+type UpdateTransactionCategoryParams struct {
+	ID                      string
+	PersonalFinanceCategory string
+	AccountID               string
+}
+
+func (q *Queries) UpdateTransactionCategory(ctx context.Context, arg UpdateTransactionCategoryParams) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, updateTransactionCategory, arg.ID, arg.PersonalFinanceCategory, arg.AccountID)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.IsoCurrencyCode,
+		&i.Date,
+		&i.MerchantName,
+		&i.PaymentChannel,
+		&i.PersonalFinanceCategory,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.CanonicalMerchant,
+	)
+	return i, err
+}
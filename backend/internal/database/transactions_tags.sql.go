@@ -20,6 +20,7 @@ VALUES (
     $1,
     $2
 )
+ON CONFLICT DO NOTHING
 `
 
 type CreateTransactionToTagRecordParams struct {
@@ -32,6 +33,37 @@ func (q *Queries) CreateTransactionToTagRecord(ctx context.Context, arg CreateTr
 	return err
 }
 
+const getTagNamesForTransaction = `-- name: GetTagNamesForTransaction :many
+SELECT tt.name
+FROM transaction_tags AS tt
+INNER JOIN transactions_to_tags AS ttt ON ttt.tag_id = tt.id
+WHERE ttt.transaction_id = $1
+ORDER BY tt.name
+`
+
+func (q *Queries) GetTagNamesForTransaction(ctx context.Context, transactionID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getTagNamesForTransaction, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTagsForTransaction = `-- name: GetTagsForTransaction :many
 SELECT transaction_id, tag_id FROM transactions_to_tags
 WHERE transaction_id = $1
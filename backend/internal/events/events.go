@@ -0,0 +1,55 @@
+package events
+
+import "strings"
+
+/*
+	This package defines the typed events the server records as a user's data changes, for delivery to
+	endpoints registered by the user and for polling through the events API. Events are written to an outbox
+	table alongside the change that raised them, and a background dispatcher fans them out to endpoints.
+*/
+
+// Event types
+const (
+	TransactionCreated  = "transaction.created"
+	TransactionModified = "transaction.modified"
+	TransactionRemoved  = "transaction.removed"
+	BalanceUpdated      = "balance.updated"
+	ItemError           = "item.error"
+)
+
+// All event types, in the order they are documented
+var Types = []string{
+	TransactionCreated,
+	TransactionModified,
+	TransactionRemoved,
+	BalanceUpdated,
+	ItemError,
+}
+
+// Data of a transaction.removed event
+type RemovedTransaction struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+}
+
+// Data of an item.error event
+type ItemErrorData struct {
+	ItemID      string `json:"item_id"`
+	WebhookCode string `json:"webhook_code"`
+	ErrorCode   string `json:"error_code,omitempty"`
+}
+
+// Reports whether an endpoint may subscribe to the given filter: an event type, or every type of a
+// resource with a wildcard, ex. "transaction.*"
+func ValidFilter(filter string) bool {
+	for _, t := range Types {
+		if filter == t {
+			return true
+		}
+		resource, _, _ := strings.Cut(t, ".")
+		if filter == resource+".*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/jms-guy/greed/backend/internal/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidFilter(t *testing.T) {
+	tests := []struct {
+		filter   string
+		expected bool
+	}{
+		{filter: events.TransactionCreated, expected: true},
+		{filter: events.ItemError, expected: true},
+		{filter: "transaction.*", expected: true},
+		{filter: "balance.*", expected: true},
+		{filter: "*", expected: false},
+		{filter: "transaction.deleted", expected: false},
+		{filter: "account.*", expected: false},
+		{filter: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, events.ValidFilter(tt.filter), "filter: %q", tt.filter)
+	}
+}
@@ -0,0 +1,131 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	kitlog "github.com/go-kit/log"
+)
+
+/*
+	This package manages the lifecycle of long-running background workers started alongside the HTTP server.
+	Workers are registered with the manager before (or after) it is started, and are all cancelled and drained
+	together when the server shuts down.
+*/
+
+// Background worker interface. Run should block until the given context is cancelled, or the work is complete
+type Worker interface {
+	Run(ctx context.Context) error
+}
+
+// Allows an ordinary function to be registered as a Worker
+type WorkerFunc func(ctx context.Context) error
+
+func (f WorkerFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Worker paired with the name it was registered under, used for logging
+type namedWorker struct {
+	name   string
+	worker Worker
+}
+
+// Holds registered workers, and tracks the goroutines running them
+type Manager struct {
+	Logger  kitlog.Logger
+	workers []namedWorker
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+}
+
+// Creates a new lifecycle manager with no registered workers
+func NewManager(logger kitlog.Logger) *Manager {
+	return &Manager{
+		Logger: logger,
+	}
+}
+
+// Registers a background worker. If the manager has already been started, the worker is started immediately
+func (m *Manager) Register(name string, worker Worker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nw := namedWorker{name: name, worker: worker}
+	m.workers = append(m.workers, nw)
+
+	if m.ctx != nil {
+		m.run(nw)
+	}
+}
+
+// Starts all registered workers. Workers are cancelled when the parent context is cancelled, or on Shutdown
+func (m *Manager) Start(parent context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ctx != nil {
+		return
+	}
+
+	m.ctx, m.cancel = context.WithCancel(parent)
+
+	for _, nw := range m.workers {
+		m.run(nw)
+	}
+}
+
+// Cancels all running workers, and waits for them to return. If the given context expires before
+// every worker has finished, the context's error is returned
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Runs a single worker in its own goroutine, logging any error it returns. Caller must hold m.mu
+func (m *Manager) run(nw namedWorker) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		_ = m.Logger.Log(
+			"msg", "starting background worker",
+			"worker", nw.name,
+		)
+
+		err := nw.worker.Run(m.ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			_ = m.Logger.Log(
+				"level", "error",
+				"msg", "background worker exited with error",
+				"worker", nw.name,
+				"err", err,
+			)
+			return
+		}
+
+		_ = m.Logger.Log(
+			"msg", "background worker stopped",
+			"worker", nw.name,
+		)
+	}()
+}
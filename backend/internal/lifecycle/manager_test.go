@@ -0,0 +1,79 @@
+package lifecycle_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/jms-guy/greed/backend/internal/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerShutdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		worker        lifecycle.WorkerFunc
+		registerAfter bool
+		timeout       time.Duration
+		expectedErr   error
+	}{
+		{
+			name: "worker stops on shutdown",
+			worker: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			timeout:     time.Second,
+			expectedErr: nil,
+		},
+		{
+			name: "worker registered after start is still drained",
+			worker: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+			registerAfter: true,
+			timeout:       time.Second,
+			expectedErr:   nil,
+		},
+		{
+			name: "shutdown times out on worker ignoring cancellation",
+			worker: func(ctx context.Context) error {
+				time.Sleep(500 * time.Millisecond)
+				return nil
+			},
+			timeout:     10 * time.Millisecond,
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := lifecycle.NewManager(kitlog.NewNopLogger())
+
+			var started atomic.Bool
+			worker := lifecycle.WorkerFunc(func(ctx context.Context) error {
+				started.Store(true)
+				return tt.worker(ctx)
+			})
+
+			if !tt.registerAfter {
+				m.Register("test", worker)
+			}
+			m.Start(context.Background())
+			if tt.registerAfter {
+				m.Register("test", worker)
+			}
+
+			assert.Eventually(t, started.Load, time.Second, time.Millisecond)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			err := m.Shutdown(ctx)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package merchant
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+	This package turns the raw merchant names banks report into canonical merchant names, so spending
+	at "AMZN Mktp CA*2K3L45", "Amazon.ca" and "AMAZON.COM*AB12" is summarized as one merchant, Amazon.
+	Built-in cleanup rules strip payment processor prefixes, reference codes, store numbers and web
+	domains, and recognize common brands. Users can add aliases on top of them, mapping any raw name
+	to the merchant they want it counted under. Transactions without a merchant name fall back to the
+	transaction's own name. Like the anomaly package, it has no dependencies on the database or Plaid.
+*/
+
+// Prefixes payment processors add in front of the merchant's name, in lowercase
+var processorPrefixes = []string{
+	"sq *", "sq*",
+	"tst* ", "tst*",
+	"paypal *", "paypal*", "pp*",
+	"sp * ", "sp *", "sp*",
+	"ic* ", "ic*",
+	"pos purchase ", "pos ",
+	"debit purchase ", "checkcard ", "purchase ",
+}
+
+// A brand recognized by the start of a cleaned up name
+type brand struct {
+	prefix string // Lowercase start of the name, matched on a word boundary
+	name   string
+}
+
+// Common brands, whose names banks report in many forms. Longer prefixes come first, so they win over
+// shorter ones they start with
+var brands = []brand{
+	{"amazon prime", "Amazon Prime"},
+	{"amzn mktp", "Amazon"},
+	{"amzn", "Amazon"},
+	{"amazon", "Amazon"},
+	{"uber eats", "Uber Eats"},
+	{"ubereats", "Uber Eats"},
+	{"uber", "Uber"},
+	{"lyft", "Lyft"},
+	{"doordash", "DoorDash"},
+	{"netflix", "Netflix"},
+	{"spotify", "Spotify"},
+	{"apple.com", "Apple"},
+	{"google", "Google"},
+	{"starbucks", "Starbucks"},
+	{"mcdonald's", "McDonald's"},
+	{"mcdonalds", "McDonald's"},
+	{"tim hortons", "Tim Hortons"},
+	{"wal-mart", "Walmart"},
+	{"walmart", "Walmart"},
+	{"wm supercenter", "Walmart"},
+	{"costco", "Costco"},
+}
+
+// Web domain endings stripped from the end of a word, in lowercase
+var domainSuffixes = []string{".co.uk", ".com", ".net", ".org", ".ca", ".io"}
+
+// Cleans up a raw merchant name using the built-in rules, returning an empty string for an empty name
+func Clean(raw string) string {
+	s := strings.Join(strings.Fields(raw), " ")
+	lower := strings.ToLower(s)
+
+	for _, prefix := range processorPrefixes {
+		if strings.HasPrefix(lower, prefix) && len(s) > len(prefix) {
+			s = strings.TrimSpace(s[len(prefix):])
+			lower = strings.ToLower(s)
+			break
+		}
+	}
+
+	// Brands are recognized with asterisks read as spaces, so "UBER *EATS" is found as Uber Eats
+	spaced := strings.Join(strings.Fields(strings.ReplaceAll(lower, "*", " ")), " ")
+	for _, b := range brands {
+		if hasWordPrefix(spaced, b.prefix) {
+			return b.name
+		}
+	}
+
+	// Reference codes follow an asterisk, as in "MERCHANT*2K3L45"
+	if i := strings.IndexByte(s, '*'); i > 0 {
+		s = s[:i]
+	}
+
+	var words []string
+	for _, word := range strings.Fields(s) {
+		if isStoreNumber(word) {
+			continue
+		}
+		if word = trimDomain(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	s = strings.Trim(strings.Join(words, " "), " -.,#")
+
+	if isShouting(s) {
+		s = titleCase(s)
+	}
+
+	return s
+}
+
+// Key a name is matched against aliases by. Names that clean up to the same name, ignoring case, share a key
+func Key(name string) string {
+	return strings.ToLower(Clean(name))
+}
+
+// Finds the canonical merchant of transactions, applying a user's aliases on top of the built-in rules
+type Normalizer struct {
+	aliases map[string]string
+}
+
+// Creates a Normalizer from a user's aliases, mapping raw names to the merchant they should be counted under
+func NewNormalizer(aliases map[string]string) *Normalizer {
+	n := &Normalizer{aliases: make(map[string]string, len(aliases))}
+	for raw, merchant := range aliases {
+		if key := Key(raw); key != "" {
+			n.aliases[key] = merchant
+		}
+	}
+	return n
+}
+
+// Canonical merchant of a transaction, from its merchant name or, if it has none, the transaction's name.
+// Returns an empty string if both are empty
+func (n *Normalizer) Canonical(merchantName, name string) string {
+	raw := strings.TrimSpace(merchantName)
+	if raw == "" {
+		raw = strings.TrimSpace(name)
+	}
+
+	cleaned := Clean(raw)
+	if n != nil {
+		if alias, ok := n.aliases[strings.ToLower(cleaned)]; ok {
+			return alias
+		}
+	}
+
+	return cleaned
+}
+
+// Reports whether s starts with prefix, followed by the end of s or a character that can't continue a word
+func hasWordPrefix(s, prefix string) bool {
+	if !strings.HasPrefix(s, prefix) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(s[len(prefix):])
+	return next == utf8.RuneError || !(unicode.IsLetter(next) || unicode.IsDigit(next))
+}
+
+// Reports whether a word is a store or terminal number, such as "#1234" or "01234"
+func isStoreNumber(word string) bool {
+	digits := strings.TrimPrefix(word, "#")
+	if digits == "" {
+		return word == "#"
+	}
+	for _, r := range digits {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return word != digits || len(digits) >= 3
+}
+
+// Strips a leading "www." and a trailing web domain ending from a word
+func trimDomain(word string) string {
+	lower := strings.ToLower(word)
+	if strings.HasPrefix(lower, "www.") {
+		word, lower = word[4:], lower[4:]
+	}
+	for _, suffix := range domainSuffixes {
+		if strings.HasSuffix(lower, suffix) && len(lower) > len(suffix) {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
+
+// Reports whether s has letters, all of them upper case
+func isShouting(s string) bool {
+	hasLetter := false
+	for _, r := range s {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	return hasLetter
+}
+
+// Upper cases the first letter of each word, and of each part of hyphenated words, lower casing the rest
+func titleCase(s string) string {
+	var b strings.Builder
+	prev := ' '
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) && !unicode.IsLetter(prev) && prev != '\'' {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
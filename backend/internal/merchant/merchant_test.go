@@ -0,0 +1,72 @@
+package merchant_test
+
+import (
+	"testing"
+
+	"github.com/jms-guy/greed/backend/internal/merchant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"AMZN Mktp CA*2K3L45", "Amazon"},
+		{"Amazon.ca", "Amazon"},
+		{"AMAZON.COM*AB12CD", "Amazon"},
+		{"Amazon Prime*XY12", "Amazon Prime"},
+		{"SQ *CORNER CAFE", "Corner Cafe"},
+		{"TST* Blue Door Bistro", "Blue Door Bistro"},
+		{"PAYPAL *NETFLIX", "Netflix"},
+		{"UBER   *EATS PENDING", "Uber Eats"},
+		{"UBER *TRIP", "Uber"},
+		{"STARBUCKS STORE 01234", "Starbucks"},
+		{"SHELL OIL #5721", "Shell Oil"},
+		{"7-ELEVEN 24", "7-Eleven 24"},
+		{"Applebee's Grill", "Applebee's Grill"},
+		{"www.etsy.com", "etsy"},
+		{"Joe's Pizza", "Joe's Pizza"},
+		{"   ", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			assert.Equal(t, tt.expected, merchant.Clean(tt.raw))
+		})
+	}
+}
+
+func TestNormalizerCanonical(t *testing.T) {
+	n := merchant.NewNormalizer(map[string]string{
+		"CORNER CAFE #12": "Corner Café",
+		"Shell Oil":       "Shell",
+	})
+
+	tests := []struct {
+		name         string
+		merchantName string
+		txnName      string
+		expected     string
+	}{
+		{"alias matches any raw name cleaning up the same", "SQ *Corner Cafe", "", "Corner Café"},
+		{"alias matches ignoring case", "SHELL OIL #5721", "", "Shell"},
+		{"falls back to transaction name", "", "AMZN Mktp CA*2K3L45 WWW.AMAZON.CA", "Amazon"},
+		{"aliases apply to transaction name", "", "corner cafe", "Corner Café"},
+		{"merchant name wins over transaction name", "Netflix", "PAYPAL *NETFLIX", "Netflix"},
+		{"unaliased names are cleaned up", "SP * LOCAL BAKERY", "", "Local Bakery"},
+		{"empty without any name", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, n.Canonical(tt.merchantName, tt.txnName))
+		})
+	}
+}
+
+func TestNilNormalizerUsesBuiltInRules(t *testing.T) {
+	var n *merchant.Normalizer
+	assert.Equal(t, "Amazon", n.Canonical("Amazon.ca", ""))
+}
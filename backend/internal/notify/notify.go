@@ -0,0 +1,243 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+/*
+	This package holds the pieces of user notification delivery that don't touch the database: rule kinds
+	and channels, the retry schedule of failed deliveries, and sending of HMAC signed webhooks to URLs
+	configured by users. The server evaluates rules and queues deliveries, a background dispatcher sends them.
+*/
+
+// Kinds of notification rule
+const (
+	KindLargeTransaction = "large_transaction" // A synced transaction is over the rule's threshold
+	KindLowBalance       = "low_balance"       // An account balance drops under the rule's threshold
+	KindItemReauth       = "item_reauth"       // Plaid reports an item needs the user to log in again
+	KindBudgetExceeded   = "budget_exceeded"   // Spending in a category this month is over the rule's threshold
+	KindNewRecurring     = "new_recurring"     // Plaid detects a new recurring charge
+)
+
+// Delivery channels
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Headers sent with webhook deliveries
+const (
+	SignatureHeader = "X-Greed-Signature"
+	TimestampHeader = "X-Greed-Timestamp"
+	DeliveryHeader  = "X-Greed-Delivery"
+)
+
+// Attempts made at a delivery before it is marked failed
+const MaxAttempts = 6
+
+// Reports whether the given kind is a known rule kind
+func ValidKind(kind string) bool {
+	switch kind {
+	case KindLargeTransaction, KindLowBalance, KindItemReauth, KindBudgetExceeded, KindNewRecurring:
+		return true
+	}
+	return false
+}
+
+// Reports whether a rule of the given kind needs a threshold amount
+func NeedsThreshold(kind string) bool {
+	return kind == KindLargeTransaction || kind == KindLowBalance || kind == KindBudgetExceeded
+}
+
+// Returns how long to wait before retrying a delivery that has failed the given number of times.
+// Starts at one minute and quadruples each attempt, capped at six hours
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := time.Minute
+	for i := 1; i < attempts; i++ {
+		delay *= 4
+		if delay >= 6*time.Hour {
+			return 6 * time.Hour
+		}
+	}
+	return delay
+}
+
+// Generates a random secret used to sign a rule's webhooks
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Signs a webhook body. The signature is a hex encoded HMAC-SHA256 of the timestamp, a period, and the body,
+// so receivers can reject replayed deliveries by checking the timestamp
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Body of a webhook delivery
+type Payload struct {
+	ID        string    `json:"id"`
+	RuleID    string    `json:"rule_id"`
+	Kind      string    `json:"kind"`
+	Subject   string    `json:"subject"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Returned when a webhook would be sent to an address that isn't public
+var ErrNonPublicAddress = errors.New("webhook address is not public")
+
+// Webhook sender interface
+type Sender interface {
+	Send(ctx context.Context, url string, secret []byte, payload Payload) error
+	Post(ctx context.Context, url string, secret []byte, deliveryID string, body []byte) error
+}
+
+// Sends webhooks over HTTP
+type WebhookSender struct {
+	Client *http.Client
+}
+
+// Returns a new WebhookSender with a short request timeout. Webhooks are user configured URLs, so they are
+// only sent to public addresses, checked after DNS resolution so a hostname can't be pointed at the
+// server's own network, and redirects aren't followed. allowPrivate lifts the address check, for local
+// development against webhooks on the same machine
+func NewWebhookSender(allowPrivate bool) *WebhookSender {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			if allowPrivate {
+				return nil
+			}
+			return checkDialAddress(address)
+		},
+	}
+
+	return &WebhookSender{
+		Client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				// No proxy, as the address check would only see the proxy's address
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConns:        20,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Address ranges that aren't reachable on the public internet, beyond those netip reports
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reserved, and broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which can map to any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // Local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),  // Documentation
+}
+
+// Reports whether an address is public, so webhooks may be sent to it. Loopback, private, link-local,
+// multicast and unspecified addresses are not, nor IPv4 addresses mapped into IPv6
+func PublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Refuses connections to addresses that aren't public. Called with the resolved address of each connection
+func checkDialAddress(address string) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook address %s: %w", address, err)
+	}
+	if !PublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// Posts the notification payload to the URL as JSON, signed with the secret
+func (s *WebhookSender) Send(ctx context.Context, url string, secret []byte, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshalling webhook payload: %w", err)
+	}
+
+	return s.Post(ctx, url, secret, payload.ID, body)
+}
+
+// Posts a JSON body to the URL, signed with the secret. Any response other than a 2xx is an error
+func (s *WebhookSender) Post(ctx context.Context, url string, secret []byte, deliveryID string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "greed-notifications")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	req.Header.Set(DeliveryHeader, deliveryID)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jms-guy/greed/backend/internal/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 0, expected: time.Minute},
+		{attempts: 1, expected: time.Minute},
+		{attempts: 2, expected: 4 * time.Minute},
+		{attempts: 3, expected: 16 * time.Minute},
+		{attempts: 5, expected: 256 * time.Minute},
+		{attempts: 6, expected: 6 * time.Hour},
+		{attempts: 50, expected: 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, notify.Backoff(tt.attempts), "attempts: %d", tt.attempts)
+	}
+}
+
+func TestSign(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"id":"1"}`)
+
+	sig := notify.Sign(secret, 1700000000, body)
+	assert.Equal(t, sig, notify.Sign(secret, 1700000000, body))
+	assert.Len(t, sig, len("sha256=")+64)
+	assert.NotEqual(t, sig, notify.Sign(secret, 1700000001, body))
+	assert.NotEqual(t, sig, notify.Sign([]byte("other"), 1700000000, body))
+}
+
+func TestWebhookSenderSend(t *testing.T) {
+	secret := []byte("secret")
+	payload := notify.Payload{ID: "delivery", RuleID: "rule", Kind: notify.KindLowBalance, Subject: "Low balance"}
+
+	tests := []struct {
+		name        string
+		status      int
+		expectedErr bool
+	}{
+		{name: "signed delivery is accepted", status: http.StatusNoContent},
+		{name: "error status fails the delivery", status: http.StatusInternalServerError, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				timestamp, err := strconv.ParseInt(r.Header.Get(notify.TimestampHeader), 10, 64)
+				assert.NoError(t, err)
+				assert.Equal(t, notify.Sign(secret, timestamp, body), r.Header.Get(notify.SignatureHeader))
+				assert.Equal(t, "delivery", r.Header.Get(notify.DeliveryHeader))
+
+				var got notify.Payload
+				assert.NoError(t, json.Unmarshal(body, &got))
+				assert.Equal(t, payload.Kind, got.Kind)
+
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			// The test server listens on loopback, so the address check is lifted
+			err := notify.NewWebhookSender(true).Send(context.Background(), server.URL, secret, payload)
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a9fe:a9fe", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.expected, notify.PublicAddress(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestWebhookSenderRefusesNonPublicAddresses(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	// Names are checked by the addresses they resolve to, so localhost is refused like 127.0.0.1
+	for _, url := range []string{server.URL, fmt.Sprintf("http://localhost:%d", port)} {
+		t.Run(url, func(t *testing.T) {
+			err := notify.NewWebhookSender(false).Post(context.Background(), url, []byte("secret"), "delivery", []byte("{}"))
+			assert.True(t, errors.Is(err, notify.ErrNonPublicAddress), "expected non-public address error, got %v", err)
+		})
+	}
+
+	assert.Zero(t, hits)
+}
+
+func TestWebhookSenderDoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	err := notify.NewWebhookSender(true).Post(context.Background(), server.URL, []byte("secret"), "delivery", []byte("{}"))
+
+	assert.Error(t, err)
+	assert.False(t, redirected)
+}
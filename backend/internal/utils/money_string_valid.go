@@ -2,6 +2,7 @@ package utils
 
 import (
 	"regexp"
+	"strings"
 )
 
 // Validation function that uses regex to make sure that a given string is in the format
@@ -10,3 +11,9 @@ func moneyStringValidation(s string) bool {
 	matched, _ := regexp.MatchString(`^(\d+)(\.\d{2})?$`, s)
 	return matched
 }
+
+// Validates a signed amount of money, in the format 'xxx.xx' or '-xxx.xx'. Transaction amounts
+// and balances can be negative, such as money coming into an account
+func ValidMoneyString(s string) bool {
+	return moneyStringValidation(strings.TrimPrefix(s, "-"))
+}
@@ -115,13 +115,14 @@ func (qv *Service) ValidateQuery(queries url.Values, rules map[string]string) (m
 
 // Builds an SQL query for transactions based on optional query arguments
 func (qv *Service) BuildSqlQuery(queries map[string]string, accountID string) (string, []any, error) {
-	query := "SELECT * FROM transactions WHERE account_id = $1"
+	query := "SELECT id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category, created_at, updated_at FROM transactions WHERE account_id = $1"
 	args := []any{accountID}
 	paramCount := 2
 
 	if val, ok := queries["merchant"]; ok {
 		if val != "" {
-			query += fmt.Sprintf(" AND merchant_name ILIKE $%d", paramCount)
+			// Matched against the canonical merchant, so every raw name summarized under a merchant is found
+			query += fmt.Sprintf(" AND COALESCE(canonical_merchant, NULLIF(merchant_name, ''), name, '') ILIKE $%d", paramCount)
 			args = append(args, "%"+val+"%")
 			paramCount++
 		}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+)
+
+// Longest nickname an account can be given
+const maxAccountNicknameLength = 100
+
+// Reports whether a nickname can be used in a file name, as the CLI names account exports after it. Path
+// separators, ".." and control characters are refused
+func validAccountNickname(nickname string) bool {
+	if nickname == "." || strings.Contains(nickname, "..") || strings.ContainsAny(nickname, `/\`) {
+		return false
+	}
+	return !strings.ContainsFunc(nickname, unicode.IsControl)
+}
+
+// Applies the user's overrides to an account. Accounts without an override record keep their name, are
+// shown, and count towards net worth
+func withAccountOverride(acc models.Account, override database.AccountOverride, found bool) models.Account {
+	acc.DisplayName = acc.Name
+	acc.IncludeInNetWorth = true
+	if !found {
+		return acc
+	}
+
+	if override.Nickname.Valid && override.Nickname.String != "" {
+		acc.Nickname = override.Nickname.String
+		acc.DisplayName = override.Nickname.String
+	}
+	acc.Hidden = override.Hidden
+	acc.Archived = override.Archived
+	acc.IncludeInNetWorth = override.IncludeInNetWorth
+	acc.SortOrder = int(override.SortOrder)
+
+	return acc
+}
+
+// Orders accounts by the user's sort order, then by display name
+func sortAccounts(accounts []models.Account) {
+	slices.SortStableFunc(accounts, func(a, b models.Account) int {
+		return cmp.Or(
+			cmp.Compare(a.SortOrder, b.SortOrder),
+			strings.Compare(strings.ToLower(a.DisplayName), strings.ToLower(b.DisplayName)),
+		)
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+	Attachments are deleted along with their transaction, account, item or user, mostly by cascades in the
+	database that the server never sees. A trigger queues the storage key of every deleted attachment, and the
+	sweeper worker removes the queued files from the blob store, so deleted users' files aren't kept.
+*/
+
+const (
+	attachmentSweepInterval  = time.Minute // How often the sweeper removes the files of deleted attachments
+	attachmentSweepBatchSize = 100         // Files removed at a time
+)
+
+// Background worker removing the files of deleted attachments until the context is cancelled
+func (app *AppServer) RunAttachmentSweeper(ctx context.Context) error {
+	ticker := time.NewTicker(attachmentSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		app.SweepAttachmentBlobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Removes the files of deleted attachments from the blob store, batch by batch until none are queued. Files
+// that fail to be removed stay queued, and are tried again on the next sweep
+func (app *AppServer) SweepAttachmentBlobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		keys, err := app.Db.GetOrphanedAttachmentBlobs(ctx, attachmentSweepBatchSize)
+		if err != nil {
+			app.logSweepError(fmt.Errorf("error getting deleted attachments: %w", err))
+			return
+		}
+
+		removed := 0
+		for _, key := range keys {
+			if err := app.Blobs.Delete(ctx, key); err != nil {
+				app.logSweepError(fmt.Errorf("error deleting attachment file %s: %w", key, err))
+				continue
+			}
+			if err := app.Db.DeleteOrphanedAttachmentBlob(ctx, key); err != nil {
+				app.logSweepError(fmt.Errorf("error dequeuing attachment file %s: %w", key, err))
+				continue
+			}
+			removed++
+		}
+
+		// A short or failing batch ends the sweep, so keys that keep failing aren't retried in a loop
+		if len(keys) < attachmentSweepBatchSize || removed < len(keys) {
+			return
+		}
+	}
+}
+
+func (app *AppServer) logSweepError(err error) {
+	_ = app.Logger.Log(
+		"level", "error",
+		"msg", "attachment sweep failed",
+		"err", err,
+	)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jms-guy/greed/backend/internal/database"
 	"github.com/plaid/plaid-go/v36/plaid"
 )
@@ -23,7 +24,7 @@ func NewDBTransactionUpdater(db *sql.DB, queries GreedDatabase) *DbTransactionUp
 
 // Transaction for expiring a delegation session, if refresh token given is expired
 func (updater *DbTransactionUpdater) ExpireDelegation(ctx context.Context, tokenHash string, token database.RefreshToken) error {
-	tx, err := updater.Db.Begin()
+	tx, err := updater.Db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error beginning database transaction: %w", err)
 	}
@@ -44,7 +45,7 @@ func (updater *DbTransactionUpdater) ExpireDelegation(ctx context.Context, token
 
 // Transaction for revoking a delegation session, if a token was used more than once
 func (updater *DbTransactionUpdater) RevokeDelegation(ctx context.Context, token database.RefreshToken) error {
-	tx, err := updater.Db.Begin()
+	tx, err := updater.Db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error beginning database transaction: %w", err)
 	}
@@ -74,6 +75,29 @@ func (updater *DbTransactionUpdater) ApplyTransactionUpdates(ctx context.Context
 
 	qtx := updater.Queries.WithTx(tx)
 
+	item, err := qtx.GetItemByID(ctx, itemID)
+	if err != nil {
+		return fmt.Errorf("error getting item record: %w", err)
+	}
+
+	// Events are written in the same database transaction as the changes they describe
+	eventRecords, err := transactionEvents(item.UserID, added, modified, removed)
+	if err != nil {
+		return err
+	}
+	for _, record := range eventRecords {
+		if err := qtx.CreateEvent(ctx, record); err != nil {
+			return fmt.Errorf("error recording transaction event: %w", err)
+		}
+	}
+
+	// The change feed is likewise kept in step with the transactions table
+	for _, params := range transactionChangeRecords(item.UserID, added, modified, removed) {
+		if err := qtx.RecordTransactionChanges(ctx, params); err != nil {
+			return fmt.Errorf("error recording transaction changes: %w", err)
+		}
+	}
+
 	var (
 		valueStrings []string
 		valueArgs    []any
@@ -81,6 +105,11 @@ func (updater *DbTransactionUpdater) ApplyTransactionUpdates(ctx context.Context
 
 	added = append(added, modified...)
 
+	normalizer, err := loadMerchantNormalizer(ctx, qtx, item.UserID)
+	if err != nil {
+		return err
+	}
+
 	// This loop handles creating the query arguments for upserting data
 	for i, txn := range added {
 		curCode := ""
@@ -98,17 +127,22 @@ func (updater *DbTransactionUpdater) ApplyTransactionUpdates(ctx context.Context
 			pfCategory = txn.PersonalFinanceCategory.Get().Primary
 		}
 
-		n := i * 8
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8))
+		canonical := normalizer.Canonical(merchant, txn.GetName())
+
+		n := i * 10
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10))
 		valueArgs = append(valueArgs,
-			txn.TransactionId, txn.AccountId, txn.Amount, curCode, txnDate, merchant, txn.PaymentChannel, pfCategory)
+			txn.TransactionId, txn.AccountId, txn.Amount, curCode, txnDate, merchant, txn.PaymentChannel, pfCategory,
+			txn.GetName(), canonical)
 	}
 
+	// Categories set by the user are kept over Plaid's category for modified transactions
 	// #nosec G201 - using parameterized placeholders, not user data
 	insertStmt := fmt.Sprintf(`
 		INSERT INTO transactions (
-			id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category 
+			id, account_id, amount, iso_currency_code, date, merchant_name, payment_channel, personal_finance_category,
+			name, canonical_merchant
 		) VALUES %s
 		ON CONFLICT (id) DO UPDATE SET
 			account_id = EXCLUDED.account_id,
@@ -117,7 +151,12 @@ func (updater *DbTransactionUpdater) ApplyTransactionUpdates(ctx context.Context
 			date = EXCLUDED.date,
 			merchant_name = EXCLUDED.merchant_name,
 			payment_channel = EXCLUDED.payment_channel,
-			personal_finance_category = EXCLUDED.personal_finance_category,
+			personal_finance_category = COALESCE(
+				(SELECT category FROM transaction_category_overrides WHERE transaction_id = EXCLUDED.id),
+				EXCLUDED.personal_finance_category
+			),
+			name = EXCLUDED.name,
+			canonical_merchant = EXCLUDED.canonical_merchant,
 			updated_at = NOW()
 	`, strings.Join(valueStrings, ","))
 
@@ -155,3 +194,219 @@ func (updater *DbTransactionUpdater) ApplyTransactionUpdates(ctx context.Context
 
 	return tx.Commit()
 }
+
+// Db transaction for storing an item's investment data. Securities are upserted first so holdings and
+// transactions can refer to them, and the item's holdings are replaced, so positions that have been
+// sold off are removed
+func (updater *DbTransactionUpdater) ApplyInvestmentUpdates(
+	ctx context.Context,
+	itemID string,
+	securities []plaid.Security,
+	holdings []plaid.Holding,
+	txns []plaid.InvestmentTransaction,
+) error {
+	tx, err := updater.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := updater.Queries.WithTx(tx)
+
+	for _, security := range securities {
+		if err := qtx.UpsertSecurity(ctx, securityParams(security)); err != nil {
+			return fmt.Errorf("error upserting security %s: %w", security.SecurityId, err)
+		}
+	}
+
+	if err := qtx.DeleteHoldingsForItem(ctx, itemID); err != nil {
+		return fmt.Errorf("error clearing item holdings: %w", err)
+	}
+	for _, holding := range holdings {
+		if err := qtx.UpsertHolding(ctx, holdingParams(holding)); err != nil {
+			return fmt.Errorf("error upserting holding: %w", err)
+		}
+	}
+
+	for _, txn := range txns {
+		params, err := investmentTransactionParams(txn)
+		if err != nil {
+			return err
+		}
+		if err := qtx.UpsertInvestmentTransaction(ctx, params); err != nil {
+			return fmt.Errorf("error upserting investment transaction %s: %w", txn.InvestmentTransactionId, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Db transaction for storing an item's liabilities. The item's liabilities are replaced, so accounts that
+// have been paid off and closed are removed, and each credit card's APRs are stored alongside it
+func (updater *DbTransactionUpdater) ApplyLiabilityUpdates(ctx context.Context, itemID string, liabilities plaid.LiabilitiesObject) error {
+	tx, err := updater.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := updater.Queries.WithTx(tx)
+
+	if err := qtx.DeleteLiabilitiesForItem(ctx, itemID); err != nil {
+		return fmt.Errorf("error clearing item liabilities: %w", err)
+	}
+
+	for _, credit := range liabilities.Credit {
+		params, err := creditLiabilityParams(credit)
+		if err != nil {
+			return err
+		}
+		if err := qtx.UpsertLiability(ctx, params); err != nil {
+			return fmt.Errorf("error upserting credit liability for account %s: %w", params.AccountID, err)
+		}
+		for _, apr := range credit.Aprs {
+			if err := qtx.UpsertLiabilityAPR(ctx, liabilityAPRParams(params.AccountID, apr)); err != nil {
+				return fmt.Errorf("error upserting APR for account %s: %w", params.AccountID, err)
+			}
+		}
+	}
+
+	for _, loan := range liabilities.Student {
+		params, err := studentLoanParams(loan)
+		if err != nil {
+			return err
+		}
+		if err := qtx.UpsertLiability(ctx, params); err != nil {
+			return fmt.Errorf("error upserting student loan for account %s: %w", params.AccountID, err)
+		}
+	}
+
+	for _, mortgage := range liabilities.Mortgage {
+		params, err := mortgageParams(mortgage)
+		if err != nil {
+			return err
+		}
+		if err := qtx.UpsertLiability(ctx, params); err != nil {
+			return fmt.Errorf("error upserting mortgage for account %s: %w", params.AccountID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Db transaction for adding a transaction to a manual account, recording it in the change feed and
+// recalculating the account's balance
+func (updater *DbTransactionUpdater) CreateManualTransaction(ctx context.Context, userID uuid.UUID, params database.CreateTransactionParams) (database.Transaction, error) {
+	tx, err := updater.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Transaction{}, fmt.Errorf("error beginning database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := updater.Queries.WithTx(tx)
+
+	txn, err := qtx.CreateTransaction(ctx, params)
+	if err != nil {
+		return database.Transaction{}, fmt.Errorf("error creating transaction record: %w", err)
+	}
+	if err := recordManualChange(ctx, qtx, userID, txn, changeAdded); err != nil {
+		return database.Transaction{}, err
+	}
+
+	return txn, tx.Commit()
+}
+
+// Db transaction for changing a manual account's transaction, recording it in the change feed and
+// recalculating the account's balance
+func (updater *DbTransactionUpdater) UpdateManualTransaction(ctx context.Context, userID uuid.UUID, params database.UpdateManualTransactionParams) (database.Transaction, error) {
+	tx, err := updater.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Transaction{}, fmt.Errorf("error beginning database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := updater.Queries.WithTx(tx)
+
+	txn, err := qtx.UpdateManualTransaction(ctx, params)
+	if err != nil {
+		return database.Transaction{}, fmt.Errorf("error updating transaction record: %w", err)
+	}
+	if err := recordManualChange(ctx, qtx, userID, txn, changeModified); err != nil {
+		return database.Transaction{}, err
+	}
+
+	return txn, tx.Commit()
+}
+
+// Db transaction for deleting a manual account's transaction, recording it in the change feed and
+// recalculating the account's balance
+func (updater *DbTransactionUpdater) DeleteManualTransaction(ctx context.Context, userID uuid.UUID, txn database.Transaction) error {
+	tx, err := updater.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := updater.Queries.WithTx(tx)
+
+	err = qtx.DeleteTransaction(ctx, database.DeleteTransactionParams{ID: txn.ID, AccountID: txn.AccountID})
+	if err != nil {
+		return fmt.Errorf("error deleting transaction record: %w", err)
+	}
+	if err := recordManualChange(ctx, qtx, userID, txn, changeRemoved); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Db transaction for deleting all of an account's transactions, recording them as removed in the change feed.
+// A manual account's balance is recalculated, as it follows its transactions
+func (updater *DbTransactionUpdater) DeleteTransactionsForAccount(ctx context.Context, acc database.Account) error {
+	tx, err := updater.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := updater.Queries.WithTx(tx)
+
+	if err := qtx.DeleteTransactionsForAccount(ctx, acc.ID); err != nil {
+		return fmt.Errorf("error deleting transaction records: %w", err)
+	}
+	if acc.IsManual {
+		if _, err := qtx.RecalculateManualBalance(ctx, acc.ID); err != nil {
+			return fmt.Errorf("error recalculating account balance: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Records a change to a manual account's transaction in the change feed and as an event, like the changes
+// of a sync, and brings the account's balance in line with its transactions
+func recordManualChange(ctx context.Context, qtx *database.Queries, userID uuid.UUID, txn database.Transaction, changeType string) error {
+	err := qtx.RecordTransactionChanges(ctx, database.RecordTransactionChangesParams{
+		UserID:         userID,
+		TransactionIds: []string{txn.ID},
+		AccountIds:     []string{txn.AccountID},
+		ChangeType:     changeType,
+	})
+	if err != nil {
+		return fmt.Errorf("error recording transaction change: %w", err)
+	}
+
+	record, err := manualTransactionEvent(userID, txn, changeType)
+	if err != nil {
+		return err
+	}
+	if err := qtx.CreateEvent(ctx, record); err != nil {
+		return fmt.Errorf("error recording transaction event: %w", err)
+	}
+
+	if _, err := qtx.RecalculateManualBalance(ctx, txn.AccountID); err != nil {
+		return fmt.Errorf("error recalculating account balance: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/events"
+	"github.com/jms-guy/greed/backend/internal/notify"
+	"github.com/jms-guy/greed/models"
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+/*
+	Events are written to the events outbox table as data changes, inside the same database transaction
+	where there is one. The dispatcher worker fans new events out into deliveries for each of the user's
+	endpoints subscribed to them, then sends due deliveries, retrying failures with the same backoff as
+	notifications. Events are kept for eventRetention, which bounds how far back they can be replayed.
+*/
+
+const (
+	eventPollInterval  = 10 * time.Second    // How often the dispatcher checks for new events and due deliveries
+	eventBatchSize     = 100                 // Events fanned out, and deliveries claimed, at a time
+	eventLease         = 5 * time.Minute     // Time a claimed delivery is hidden from other dispatchers
+	eventRetention     = 30 * 24 * time.Hour // Age after which events are deleted
+	eventPruneInterval = 6 * time.Hour       // How often events past retention are deleted
+)
+
+// Builds the record of an event, with its data as the payload
+func newEvent(userID uuid.UUID, eventType string, data any) (database.CreateEventParams, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return database.CreateEventParams{}, fmt.Errorf("error marshalling %s event: %w", eventType, err)
+	}
+
+	return database.CreateEventParams{
+		ID:      uuid.New(),
+		UserID:  userID,
+		Type:    eventType,
+		Payload: payload,
+	}, nil
+}
+
+// Builds the events of a transaction sync
+func transactionEvents(userID uuid.UUID, added, modified []plaid.Transaction, removed []plaid.RemovedTransaction) ([]database.CreateEventParams, error) {
+	var records []database.CreateEventParams
+
+	for _, group := range []struct {
+		eventType string
+		txns      []plaid.Transaction
+	}{
+		{events.TransactionCreated, added},
+		{events.TransactionModified, modified},
+	} {
+		for _, txn := range group.txns {
+			record, err := newEvent(userID, group.eventType, transactionEventData(txn))
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+	}
+
+	for _, txn := range removed {
+		record, err := newEvent(userID, events.TransactionRemoved, events.RemovedTransaction{
+			ID:        txn.TransactionId,
+			AccountID: txn.AccountId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// Builds the event of a change to a manual account's transaction, with the same payloads as a sync's events
+func manualTransactionEvent(userID uuid.UUID, txn database.Transaction, changeType string) (database.CreateEventParams, error) {
+	switch changeType {
+	case changeAdded:
+		return newEvent(userID, events.TransactionCreated, manualTransactionResponse(txn))
+	case changeModified:
+		return newEvent(userID, events.TransactionModified, manualTransactionResponse(txn))
+	default:
+		return newEvent(userID, events.TransactionRemoved, events.RemovedTransaction{
+			ID:        txn.ID,
+			AccountID: txn.AccountID,
+		})
+	}
+}
+
+// Converts a Plaid transaction into the shape transactions are returned in by the API
+func transactionEventData(txn plaid.Transaction) models.Transaction {
+	date, _ := time.Parse("2006-01-02", txn.Date)
+	t := models.Transaction{
+		Id:              txn.TransactionId,
+		AccountId:       txn.AccountId,
+		Amount:          fmt.Sprintf("%.2f", txn.Amount),
+		IsoCurrencyCode: txn.GetIsoCurrencyCode(),
+		Date:            date,
+		MerchantName:    txn.GetMerchantName(),
+		PaymentChannel:  txn.PaymentChannel,
+	}
+	if txn.PersonalFinanceCategory.IsSet() && txn.PersonalFinanceCategory.Get() != nil {
+		t.PersonalFinanceCategory = txn.PersonalFinanceCategory.Get().Primary
+	}
+	return t
+}
+
+// Records an event outside of a database transaction. Errors are logged, as the change raising the
+// event has already been made
+func (app *AppServer) recordEvent(ctx context.Context, userID uuid.UUID, eventType string, data any) {
+	record, err := newEvent(userID, eventType, data)
+	if err == nil {
+		err = app.Db.CreateEvent(ctx, record)
+	}
+	if err != nil {
+		app.logEventError(fmt.Errorf("error recording %s event: %w", eventType, err))
+	}
+}
+
+// Background worker delivering events to endpoints until the context is cancelled
+func (app *AppServer) RunEventDispatcher(ctx context.Context) error {
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+	for {
+		if time.Since(lastPrune) >= eventPruneInterval {
+			app.pruneEvents(ctx)
+			lastPrune = time.Now()
+		}
+		app.DispatchEvents(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Fans new events out into deliveries, then sends due deliveries, recording the outcome of each
+func (app *AppServer) DispatchEvents(ctx context.Context) {
+	if _, err := app.Db.FanOutEvents(ctx, eventBatchSize); err != nil {
+		app.logEventError(fmt.Errorf("error fanning out events: %w", err))
+	}
+
+	deliveries, err := app.Db.ClaimDueEventDeliveries(ctx, database.ClaimDueEventDeliveriesParams{
+		LeaseUntil: time.Now().Add(eventLease),
+		BatchSize:  eventBatchSize,
+	})
+	if err != nil {
+		app.logEventError(fmt.Errorf("error claiming event deliveries: %w", err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		// Unsent deliveries are claimed again once their lease runs out
+		if ctx.Err() != nil {
+			return
+		}
+
+		params := database.RecordEventDeliveryAttemptParams{
+			ID:            delivery.ID,
+			Status:        notify.StatusDelivered,
+			NextAttemptAt: time.Now(),
+		}
+
+		if err := app.deliverEvent(ctx, delivery); err != nil {
+			attempts := int(delivery.Attempts) + 1
+			params.LastError = sql.NullString{String: err.Error(), Valid: true}
+			if attempts >= notify.MaxAttempts {
+				params.Status = notify.StatusFailed
+			} else {
+				params.Status = notify.StatusPending
+				params.NextAttemptAt = time.Now().Add(notify.Backoff(attempts))
+			}
+			_ = app.Logger.Log(
+				"level", "warning",
+				"msg", "event delivery failed",
+				"delivery_id", delivery.ID,
+				"event_type", delivery.Type,
+				"attempts", attempts,
+				"err", err,
+			)
+		}
+
+		if err := app.Db.RecordEventDeliveryAttempt(ctx, params); err != nil {
+			app.logEventError(fmt.Errorf("error recording event delivery attempt: %w", err))
+		}
+	}
+}
+
+// Posts an event to its endpoint, signed with the endpoint's secret
+func (app *AppServer) deliverEvent(ctx context.Context, delivery database.ClaimDueEventDeliveriesRow) error {
+	secret, err := app.Encryptor.DecryptAccessToken(delivery.Secret, app.Config.AESKey)
+	if err != nil {
+		return fmt.Errorf("error decrypting endpoint secret: %w", err)
+	}
+
+	body, err := json.Marshal(models.Event{
+		ID:        delivery.EventID,
+		Seq:       delivery.Seq,
+		Type:      delivery.Type,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling event: %w", err)
+	}
+
+	return app.Webhooks.Post(ctx, delivery.Url, secret, delivery.ID.String(), body)
+}
+
+// Deletes events past retention, along with their deliveries
+func (app *AppServer) pruneEvents(ctx context.Context) {
+	count, err := app.Db.DeleteEventsBefore(ctx, time.Now().Add(-eventRetention))
+	if err != nil {
+		app.logEventError(fmt.Errorf("error deleting old events: %w", err))
+		return
+	}
+	if count > 0 {
+		_ = app.Logger.Log(
+			"msg", "deleted events past retention",
+			"count", count,
+		)
+	}
+}
+
+func (app *AppServer) logEventError(err error) {
+	_ = app.Logger.Log(
+		"level", "error",
+		"msg", "event processing failed",
+		"err", err,
+	)
+}
@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/jms-guy/greed/backend/internal/database"
@@ -31,10 +35,24 @@ func (app *AppServer) HandlerGetAccountsForUser(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	records, err := app.Db.GetAccountOverridesForUser(ctx, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error retrieving account overrides: %w", err))
+		return
+	}
+	overrides := make(map[string]database.AccountOverride, len(records))
+	for _, o := range records {
+		overrides[o.AccountID] = o
+	}
+
+	// Hidden and archived accounts are left out unless asked for
+	includeHidden := r.URL.Query().Get("include_hidden") == "true"
+
 	// Return slice of account structs
-	var accounts []models.Account
+	accounts := []models.Account{}
 	for _, account := range accs {
-		result := models.Account{
+		override, found := overrides[account.ID]
+		result := withAccountOverride(models.Account{
 			Id:               account.ID,
 			CreatedAt:        account.CreatedAt,
 			UpdatedAt:        account.UpdatedAt,
@@ -46,9 +64,15 @@ func (app *AppServer) HandlerGetAccountsForUser(w http.ResponseWriter, r *http.R
 			AvailableBalance: account.AvailableBalance.String,
 			CurrentBalance:   account.CurrentBalance.String,
 			IsoCurrencyCode:  account.IsoCurrencyCode.String,
+			ItemId:           account.ItemID.String,
+			Manual:           account.IsManual,
+		}, override, found)
+		if !includeHidden && (result.Hidden || result.Archived) {
+			continue
 		}
 		accounts = append(accounts, result)
 	}
+	sortAccounts(accounts)
 
 	app.respondWithJSON(w, 200, accounts)
 }
@@ -64,7 +88,13 @@ func (app *AppServer) HandlerGetAccountData(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	response := models.Account{
+	override, err := app.Db.GetAccountOverride(ctx, acc.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting account override: %w", err))
+		return
+	}
+
+	response := withAccountOverride(models.Account{
 		Id:               acc.ID,
 		CreatedAt:        acc.CreatedAt,
 		UpdatedAt:        acc.UpdatedAt,
@@ -76,9 +106,131 @@ func (app *AppServer) HandlerGetAccountData(w http.ResponseWriter, r *http.Reque
 		AvailableBalance: acc.AvailableBalance.String,
 		CurrentBalance:   acc.CurrentBalance.String,
 		IsoCurrencyCode:  acc.IsoCurrencyCode.String,
-		ItemId:           acc.ItemID,
+		ItemId:           acc.ItemID.String,
+		Manual:           acc.IsManual,
+	}, override, err == nil)
+
+	app.respondWithJSON(w, 200, response)
+}
+
+// Updates how an account is shown to the user: its nickname, whether it is hidden or archived, whether it
+// counts towards net worth, and where it is sorted. Fields left out of the request are kept
+func (app *AppServer) HandlerUpdateAccountOverrides(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	accValue := ctx.Value(accountKey)
+	acc, ok := accValue.(database.Account)
+	if !ok {
+		app.respondWithError(w, 400, "Bad account in context", nil)
+		return
+	}
+
+	request := models.UpdateAccountOverrides{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	current, err := app.Db.GetAccountOverride(ctx, acc.ID)
+	found := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting account override: %w", err))
+		return
 	}
 
+	params := database.UpsertAccountOverrideParams{
+		AccountID:         acc.ID,
+		UserID:            id,
+		Nickname:          current.Nickname,
+		Hidden:            current.Hidden,
+		Archived:          current.Archived,
+		IncludeInNetWorth: current.IncludeInNetWorth || !found,
+		SortOrder:         current.SortOrder,
+	}
+
+	if request.Nickname != nil {
+		nickname := strings.TrimSpace(*request.Nickname)
+		if utf8.RuneCountInString(nickname) > maxAccountNicknameLength {
+			app.respondWithError(w, 400, fmt.Sprintf("Nickname must be at most %d characters", maxAccountNicknameLength), nil)
+			return
+		}
+		if !validAccountNickname(nickname) {
+			app.respondWithError(w, 400, "Nickname must not contain path separators, '..' or control characters", nil)
+			return
+		}
+
+		// Commands find accounts by name, so names have to stay unique
+		if nickname != "" {
+			accounts, err := app.Db.GetAllAccountsForUser(ctx, id)
+			if err != nil {
+				app.respondWithError(w, 500, "Database error", fmt.Errorf("error retrieving accounts: %w", err))
+				return
+			}
+			overrides, err := app.Db.GetAccountOverridesForUser(ctx, id)
+			if err != nil {
+				app.respondWithError(w, 500, "Database error", fmt.Errorf("error retrieving account overrides: %w", err))
+				return
+			}
+			taken := map[string]bool{}
+			for _, a := range accounts {
+				if a.ID != acc.ID {
+					taken[a.Name] = true
+				}
+			}
+			for _, o := range overrides {
+				if o.AccountID != acc.ID && o.Nickname.Valid {
+					taken[o.Nickname.String] = true
+				}
+			}
+			if taken[nickname] {
+				app.respondWithError(w, 400, "Nickname is already used by another account", nil)
+				return
+			}
+		}
+
+		params.Nickname = sql.NullString{String: nickname, Valid: nickname != ""}
+	}
+	if request.Hidden != nil {
+		params.Hidden = *request.Hidden
+	}
+	if request.Archived != nil {
+		params.Archived = *request.Archived
+	}
+	if request.IncludeInNetWorth != nil {
+		params.IncludeInNetWorth = *request.IncludeInNetWorth
+	}
+	if request.SortOrder != nil {
+		params.SortOrder = int32(*request.SortOrder) // #nosec G115 - sort positions are small
+	}
+
+	override, err := app.Db.UpsertAccountOverride(ctx, params)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error updating account override: %w", err))
+		return
+	}
+
+	response := withAccountOverride(models.Account{
+		Id:               acc.ID,
+		CreatedAt:        acc.CreatedAt,
+		UpdatedAt:        acc.UpdatedAt,
+		Name:             acc.Name,
+		Type:             acc.Type,
+		Subtype:          acc.Subtype.String,
+		Mask:             acc.Mask.String,
+		OfficialName:     acc.OfficialName.String,
+		AvailableBalance: acc.AvailableBalance.String,
+		CurrentBalance:   acc.CurrentBalance.String,
+		IsoCurrencyCode:  acc.IsoCurrencyCode.String,
+		ItemId:           acc.ItemID.String,
+		Manual:           acc.IsManual,
+	}, override, true)
+
 	app.respondWithJSON(w, 200, response)
 }
 
@@ -18,15 +18,31 @@ import (
 )
 
 func TestHandlerGetAccountsForUser(t *testing.T) {
+	hiddenAccounts := func() *mockDatabaseService {
+		return &mockDatabaseService{
+			GetAllAccountsForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.Account, error) {
+				return []database.Account{{ID: testAccountID, Name: testAccountName}, {ID: "closed-id", Name: "Closed Account"}}, nil
+			},
+			GetAccountOverridesForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.AccountOverride, error) {
+				return []database.AccountOverride{
+					{AccountID: testAccountID, Nickname: sql.NullString{String: "Everyday", Valid: true}, IncludeInNetWorth: true},
+					{AccountID: "closed-id", Archived: true, IncludeInNetWorth: true},
+				}, nil
+			},
+		}
+	}
+
 	tests := []struct {
-		name            string
-		userIDInContext uuid.UUID
-		pathParams      map[string]string
-		requestBody     string
-		mockDb          *mockDatabaseService
-		mockAuth        *mockAuthService
-		expectedStatus  int
-		expectedBody    string
+		name             string
+		userIDInContext  uuid.UUID
+		query            string
+		pathParams       map[string]string
+		requestBody      string
+		mockDb           *mockDatabaseService
+		mockAuth         *mockAuthService
+		expectedStatus   int
+		expectedBody     string
+		unexpectedInBody string
 	}{
 		{
 			name:            "should successfully get account records for user",
@@ -39,6 +55,33 @@ func TestHandlerGetAccountsForUser(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   testAccountID,
 		},
+		{
+			name:             "should apply nickname and leave out archived accounts",
+			userIDInContext:  testUserID,
+			mockDb:           hiddenAccounts(),
+			expectedStatus:   http.StatusOK,
+			expectedBody:     `"display_name":"Everyday"`,
+			unexpectedInBody: "closed-id",
+		},
+		{
+			name:            "should include archived accounts when asked",
+			userIDInContext: testUserID,
+			query:           "?include_hidden=true",
+			mockDb:          hiddenAccounts(),
+			expectedStatus:  http.StatusOK,
+			expectedBody:    `"archived":true`,
+		},
+		{
+			name:            "should err on getting account overrides",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetAccountOverridesForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.AccountOverride, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
 		{
 			name:            "should err with bad userID in context",
 			userIDInContext: uuid.Nil,
@@ -77,7 +120,7 @@ func TestHandlerGetAccountsForUser(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req := httptest.NewRequest("GET", "/api/accounts", bytes.NewBufferString(tt.requestBody))
+			req := httptest.NewRequest("GET", "/api/accounts"+tt.query, bytes.NewBufferString(tt.requestBody))
 			req.Header.Set("Content-Type", "application/json")
 
 			rctx := chi.NewRouteContext()
@@ -105,6 +148,9 @@ func TestHandlerGetAccountsForUser(t *testing.T) {
 			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
 				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
 			}
+			if tt.unexpectedInBody != "" && strings.Contains(rr.Body.String(), tt.unexpectedInBody) {
+				t.Errorf("handler returned unexpected body: got %s want body not to contain %s", rr.Body.String(), tt.unexpectedInBody)
+			}
 		})
 	}
 }
@@ -272,3 +318,163 @@ func TestHandlerDeleteAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerUpdateAccountOverrides(t *testing.T) {
+	tests := []struct {
+		name             string
+		accountInContext any
+		requestBody      string
+		mockDb           *mockDatabaseService
+		expectedStatus   int
+		expectedBody     string
+		expectedParams   *database.UpsertAccountOverrideParams
+	}{
+		{
+			name:             "should rename account keeping defaults",
+			accountInContext: testAccount,
+			requestBody:      `{"nickname":"  Everyday  "}`,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusOK,
+			expectedParams: &database.UpsertAccountOverrideParams{
+				AccountID:         testAccountID,
+				UserID:            testUserID,
+				Nickname:          sql.NullString{String: "Everyday", Valid: true},
+				IncludeInNetWorth: true,
+			},
+		},
+		{
+			name:             "should keep fields left out of request",
+			accountInContext: testAccount,
+			requestBody:      `{"archived":true}`,
+			mockDb: &mockDatabaseService{
+				GetAccountOverrideFunc: func(ctx context.Context, accountID string) (database.AccountOverride, error) {
+					return database.AccountOverride{AccountID: testAccountID, Nickname: sql.NullString{String: "Old", Valid: true}, SortOrder: 2}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedParams: &database.UpsertAccountOverrideParams{
+				AccountID: testAccountID,
+				UserID:    testUserID,
+				Nickname:  sql.NullString{String: "Old", Valid: true},
+				Archived:  true,
+				SortOrder: 2,
+			},
+		},
+		{
+			name:             "should err with nickname containing a path separator",
+			accountInContext: testAccount,
+			requestBody:      `{"nickname":"a/b"}`,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Nickname must not contain path separators",
+		},
+		{
+			name:             "should err with nickname containing a parent directory",
+			accountInContext: testAccount,
+			requestBody:      `{"nickname":"../../x"}`,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Nickname must not contain path separators",
+		},
+		{
+			name:             "should err with nickname containing a backslash",
+			accountInContext: testAccount,
+			requestBody:      `{"nickname":"a\\b"}`,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Nickname must not contain path separators",
+		},
+		{
+			name:             "should err with nickname containing a control character",
+			accountInContext: testAccount,
+			requestBody:      `{"nickname":"a\tb"}`,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Nickname must not contain path separators",
+		},
+		{
+			name:             "should err with nickname taken by another account",
+			accountInContext: testAccount,
+			requestBody:      `{"nickname":"Savings"}`,
+			mockDb: &mockDatabaseService{
+				GetAllAccountsForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.Account, error) {
+					return []database.Account{{ID: testAccountID, Name: testAccountName}, {ID: "other-id", Name: "Savings"}}, nil
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Nickname is already used by another account",
+		},
+		{
+			name:             "should err with bad request data",
+			accountInContext: testAccount,
+			requestBody:      `{"hidden":"yes"}`,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Bad request data",
+		},
+		{
+			name:             "should err with bad account in context",
+			accountInContext: "",
+			requestBody:      `{"hidden":true}`,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Bad account in context",
+		},
+		{
+			name:             "should err updating override",
+			accountInContext: testAccount,
+			requestBody:      `{"hidden":true}`,
+			mockDb: &mockDatabaseService{
+				UpsertAccountOverrideFunc: func(ctx context.Context, arg database.UpsertAccountOverrideParams) (database.AccountOverride, error) {
+					return database.AccountOverride{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *database.UpsertAccountOverrideParams
+			if tt.mockDb.UpsertAccountOverrideFunc == nil {
+				tt.mockDb.UpsertAccountOverrideFunc = func(ctx context.Context, arg database.UpsertAccountOverrideParams) (database.AccountOverride, error) {
+					got = &arg
+					return database.AccountOverride{AccountID: arg.AccountID, Nickname: arg.Nickname, Hidden: arg.Hidden, Archived: arg.Archived, IncludeInNetWorth: arg.IncludeInNetWorth, SortOrder: arg.SortOrder}, nil
+				}
+			}
+
+			req := httptest.NewRequest("PUT", fmt.Sprintf("/api/accounts/%s/overrides", testAccountID), bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), testUserID)
+			ctx = context.WithValue(ctx, handlers.GetAccountKey(), tt.accountInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerUpdateAccountOverrides(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+			if tt.expectedParams != nil {
+				if got == nil {
+					t.Fatalf("override was not stored")
+				}
+				if *got != *tt.expectedParams {
+					t.Errorf("stored override: got %+v want %+v", *got, *tt.expectedParams)
+				}
+			}
+		})
+	}
+}
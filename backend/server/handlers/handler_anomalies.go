@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/anomaly"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Handler gets the user's unacknowledged anomalies. With the "all" query parameter set to true,
+// acknowledged anomalies are included
+func (app *AppServer) HandlerGetAnomalies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	includeAcknowledged := false
+	if all := r.URL.Query().Get("all"); all != "" {
+		var err error
+		includeAcknowledged, err = strconv.ParseBool(all)
+		if err != nil {
+			app.respondWithError(w, 400, "Bad query parameter: all must be true or false", nil)
+			return
+		}
+	}
+
+	rows, err := app.Db.GetAnomaliesForUser(ctx, database.GetAnomaliesForUserParams{
+		UserID:              id,
+		IncludeAcknowledged: includeAcknowledged,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting anomalies: %w", err))
+		return
+	}
+
+	anomalies := []models.Anomaly{}
+	for _, row := range rows {
+		a := models.Anomaly{
+			ID:              row.ID,
+			TransactionID:   row.TransactionID,
+			AccountID:       row.AccountID,
+			Kind:            row.Kind,
+			Reason:          row.Reason,
+			Score:           row.Score,
+			Amount:          row.Amount,
+			IsoCurrencyCode: row.IsoCurrencyCode.String,
+			Date:            row.Date.Time,
+			MerchantName:    row.MerchantName.String,
+			CreatedAt:       row.CreatedAt,
+		}
+		if row.AcknowledgedAt.Valid {
+			a.AcknowledgedAt = &row.AcknowledgedAt.Time
+		}
+		anomalies = append(anomalies, a)
+	}
+
+	app.respondWithJSON(w, 200, anomalies)
+}
+
+// Handler acknowledges a single anomaly, so it is no longer listed by default
+func (app *AppServer) HandlerAcknowledgeAnomaly(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	anomalyID, err := uuid.Parse(chi.URLParam(r, "anomaly-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid anomaly ID", nil)
+		return
+	}
+
+	_, err = app.Db.AcknowledgeAnomaly(ctx, database.AcknowledgeAnomalyParams{
+		ID:     anomalyID,
+		UserID: id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			app.respondWithError(w, 404, "Anomaly not found", nil)
+			return
+		}
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error acknowledging anomaly: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, "Anomaly acknowledged")
+}
+
+// Handler acknowledges all of the user's anomalies
+func (app *AppServer) HandlerAcknowledgeAllAnomalies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	count, err := app.Db.AcknowledgeAllAnomalies(ctx, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error acknowledging anomalies: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, fmt.Sprintf("%d anomalies acknowledged", count))
+}
+
+// Scores transactions added by a sync against the user's transaction history, storing any anomalies found.
+// Errors are logged rather than returned, so a failure here never fails the sync itself
+func (app *AppServer) detectAnomalies(ctx context.Context, item database.PlaidItem, added []plaid.Transaction, history []database.Transaction) {
+	if len(added) == 0 {
+		return
+	}
+
+	accounts, err := app.Db.GetAccountsForItem(ctx, item.ID)
+	if err != nil {
+		app.logAnomalyError(item.ID, fmt.Errorf("error getting item accounts: %w", err))
+		return
+	}
+	currencies := make(map[string]string, len(accounts))
+	for _, acc := range accounts {
+		currencies[acc.ID] = acc.IsoCurrencyCode.String
+	}
+
+	// New transactions are in the history, already given their canonical merchant
+	canonical := make(map[string]string, len(history))
+	for _, txn := range history {
+		if txn.CanonicalMerchant.Valid {
+			canonical[txn.ID] = txn.CanonicalMerchant.String
+		}
+	}
+
+	var newTxns []anomaly.Transaction
+	for _, txn := range added {
+		date, _ := time.Parse("2006-01-02", txn.Date)
+		t := anomaly.Transaction{
+			ID:        txn.TransactionId,
+			AccountID: txn.AccountId,
+			Amount:    txn.Amount,
+			Date:      date,
+			Merchant:  txn.GetMerchantName(),
+			Currency:  txn.GetIsoCurrencyCode(),
+		}
+		if merchant, ok := canonical[txn.TransactionId]; ok {
+			t.Merchant = merchant
+		}
+		if txn.PersonalFinanceCategory.IsSet() {
+			t.Category = txn.PersonalFinanceCategory.Get().Primary
+		}
+		newTxns = append(newTxns, t)
+	}
+
+	var past []anomaly.Transaction
+	for _, txn := range history {
+		amount, err := strconv.ParseFloat(txn.Amount, 64)
+		if err != nil {
+			continue
+		}
+		past = append(past, anomaly.Transaction{
+			ID:        txn.ID,
+			AccountID: txn.AccountID,
+			Amount:    amount,
+			Currency:  txn.IsoCurrencyCode.String,
+			Date:      txn.Date.Time,
+			Merchant:  canonicalMerchant(txn),
+			Category:  txn.PersonalFinanceCategory,
+		})
+	}
+
+	for _, a := range anomaly.Detect(newTxns, past, currencies, anomaly.DefaultConfig()) {
+		err := app.Db.CreateAnomaly(ctx, database.CreateAnomalyParams{
+			ID:            uuid.New(),
+			TransactionID: a.TransactionID,
+			UserID:        item.UserID,
+			Kind:          a.Kind,
+			Reason:        a.Reason,
+			Score:         a.Score,
+		})
+		if err != nil {
+			app.logAnomalyError(item.ID, fmt.Errorf("error creating anomaly record: %w", err))
+			return
+		}
+	}
+}
+
+func (app *AppServer) logAnomalyError(itemID string, err error) {
+	_ = app.Logger.Log(
+		"level", "error",
+		"msg", "anomaly detection failed",
+		"item_id", itemID,
+		"err", err,
+	)
+}
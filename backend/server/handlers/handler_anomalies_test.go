@@ -0,0 +1,294 @@
+package handlers_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+)
+
+var testAnomalyID = uuid.MustParse("0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0")
+
+func TestHandlerGetAnomalies(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		query           string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should get anomalies for user",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetAnomaliesForUserFunc: func(ctx context.Context, arg database.GetAnomaliesForUserParams) ([]database.GetAnomaliesForUserRow, error) {
+					if arg.IncludeAcknowledged {
+						return nil, fmt.Errorf("acknowledged anomalies should not be requested")
+					}
+					return []database.GetAnomaliesForUserRow{{
+						ID:            testAnomalyID,
+						TransactionID: "txn",
+						AccountID:     testAccountID,
+						Kind:          "duplicate_charge",
+						Reason:        "Same charge",
+						Amount:        "12.50",
+						Date:          sql.NullTime{Time: time.Now(), Valid: true},
+					}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"kind":"duplicate_charge"`,
+		},
+		{
+			name:            "should include acknowledged anomalies",
+			userIDInContext: testUserID,
+			query:           "?all=true",
+			mockDb: &mockDatabaseService{
+				GetAnomaliesForUserFunc: func(ctx context.Context, arg database.GetAnomaliesForUserParams) ([]database.GetAnomaliesForUserRow, error) {
+					if !arg.IncludeAcknowledged {
+						return nil, fmt.Errorf("acknowledged anomalies should be requested")
+					}
+					return []database.GetAnomaliesForUserRow{{
+						ID:             testAnomalyID,
+						Kind:           "new_merchant",
+						AcknowledgedAt: sql.NullTime{Time: time.Now(), Valid: true},
+					}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"acknowledged_at"`,
+		},
+		{
+			name:            "should return empty list with no anomalies",
+			userIDInContext: testUserID,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "[]",
+		},
+		{
+			name:            "should err with bad all parameter",
+			userIDInContext: testUserID,
+			query:           "?all=maybe",
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad query parameter",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err getting anomalies",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetAnomaliesForUserFunc: func(ctx context.Context, arg database.GetAnomaliesForUserParams) ([]database.GetAnomaliesForUserRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/anomalies"+tt.query, nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetAnomalies(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerAcknowledgeAnomaly(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		pathParams      map[string]string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should acknowledge anomaly",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"anomaly-id": testAnomalyID.String()},
+			mockDb: &mockDatabaseService{
+				AcknowledgeAnomalyFunc: func(ctx context.Context, arg database.AcknowledgeAnomalyParams) (database.Anomaly, error) {
+					return database.Anomaly{ID: arg.ID, UserID: arg.UserID}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Anomaly acknowledged",
+		},
+		{
+			name:            "should err with invalid anomaly ID",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"anomaly-id": "not-a-uuid"},
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Invalid anomaly ID",
+		},
+		{
+			name:            "should err with anomaly of another user",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"anomaly-id": testAnomalyID.String()},
+			mockDb: &mockDatabaseService{
+				AcknowledgeAnomalyFunc: func(ctx context.Context, arg database.AcknowledgeAnomalyParams) (database.Anomaly, error) {
+					return database.Anomaly{}, sql.ErrNoRows
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Anomaly not found",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: "",
+			pathParams:      map[string]string{"anomaly-id": testAnomalyID.String()},
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err acknowledging anomaly",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"anomaly-id": testAnomalyID.String()},
+			mockDb: &mockDatabaseService{
+				AcknowledgeAnomalyFunc: func(ctx context.Context, arg database.AcknowledgeAnomalyParams) (database.Anomaly, error) {
+					return database.Anomaly{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", fmt.Sprintf("/api/anomalies/%s/acknowledge", tt.pathParams["anomaly-id"]), nil)
+
+			rctx := chi.NewRouteContext()
+			for key, value := range tt.pathParams {
+				rctx.URLParams.Add(key, value)
+			}
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerAcknowledgeAnomaly(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerAcknowledgeAllAnomalies(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should acknowledge all anomalies",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				AcknowledgeAllAnomaliesFunc: func(ctx context.Context, userID uuid.UUID) (int64, error) {
+					return 3, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "3 anomalies acknowledged",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err acknowledging anomalies",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				AcknowledgeAllAnomaliesFunc: func(ctx context.Context, userID uuid.UUID) (int64, error) {
+					return 0, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/anomalies/acknowledge", nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerAcknowledgeAllAnomalies(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
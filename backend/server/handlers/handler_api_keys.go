@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/auth"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+)
+
+// Handler gets the user's API keys. Keys themselves are never returned, only their prefixes
+func (app *AppServer) HandlerGetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	keys, err := app.Db.GetAPIKeysForUser(ctx, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting API keys: %w", err))
+		return
+	}
+
+	response := []models.APIKey{}
+	for _, key := range keys {
+		response = append(response, apiKeyResponse(key))
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
+
+// Handler creates an API key. Only a hash of the key is stored, so it is returned in this response only
+func (app *AppServer) HandlerCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	request := models.CreateAPIKey{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" || len(name) > 100 {
+		app.respondWithError(w, 400, "Key name must be between 1 and 100 characters", nil)
+		return
+	}
+
+	scope := strings.ToLower(strings.TrimSpace(request.Scope))
+	if scope == "" {
+		scope = auth.ScopeRead
+	}
+	if !auth.ValidScope(scope) {
+		app.respondWithError(w, 400, "Scope must be read or write", nil)
+		return
+	}
+
+	expiresAt := sql.NullTime{}
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(time.Now()) {
+			app.respondWithError(w, 400, "Expiry must be in the future", nil)
+			return
+		}
+		expiresAt = sql.NullTime{Time: *request.ExpiresAt, Valid: true}
+	}
+
+	key, prefix, err := auth.NewAPIKey()
+	if err != nil {
+		app.respondWithError(w, 500, "Error generating API key", err)
+		return
+	}
+
+	record, err := app.Db.CreateAPIKey(ctx, database.CreateAPIKeyParams{
+		ID:        uuid.New(),
+		UserID:    id,
+		Name:      name,
+		KeyHash:   auth.HashAPIKey(key),
+		Prefix:    prefix,
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error creating API key: %w", err))
+		return
+	}
+
+	response := apiKeyResponse(record)
+	response.Key = key
+
+	app.respondWithJSON(w, 201, response)
+}
+
+// Handler revokes one of the user's API keys, deleting it
+func (app *AppServer) HandlerRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	keyID, err := uuid.Parse(chi.URLParam(r, "key-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid key ID", nil)
+		return
+	}
+
+	count, err := app.Db.DeleteAPIKey(ctx, database.DeleteAPIKeyParams{
+		ID:     keyID,
+		UserID: id,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error revoking API key: %w", err))
+		return
+	}
+	if count == 0 {
+		app.respondWithError(w, 404, "API key not found", nil)
+		return
+	}
+
+	app.respondWithJSON(w, 200, "API key revoked")
+}
+
+func apiKeyResponse(key database.ApiKey) models.APIKey {
+	k := models.APIKey{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scope:     key.Scope,
+		CreatedAt: key.CreatedAt,
+	}
+	if key.ExpiresAt.Valid {
+		k.ExpiresAt = &key.ExpiresAt.Time
+	}
+	if key.LastUsedAt.Valid {
+		k.LastUsedAt = &key.LastUsedAt.Time
+	}
+	return k
+}
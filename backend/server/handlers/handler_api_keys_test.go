@@ -0,0 +1,277 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/auth"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+)
+
+var testAPIKeyID = uuid.MustParse("1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d")
+
+func TestHandlerCreateAPIKey(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name            string
+		userIDInContext any
+		requestBody     string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should create key and return it once",
+			userIDInContext: testUserID,
+			requestBody:     fmt.Sprintf(`{"name":"cron","scope":"write","expires_at":"%s"}`, future),
+			mockDb: &mockDatabaseService{
+				CreateAPIKeyFunc: func(ctx context.Context, arg database.CreateAPIKeyParams) (database.ApiKey, error) {
+					if strings.HasPrefix(arg.KeyHash, auth.APIKeyPrefix) || !strings.HasPrefix(arg.Prefix, auth.APIKeyPrefix) {
+						return database.ApiKey{}, fmt.Errorf("key stored unhashed")
+					}
+					if !arg.ExpiresAt.Valid {
+						return database.ApiKey{}, fmt.Errorf("expiry not stored")
+					}
+					return database.ApiKey{ID: arg.ID, Name: arg.Name, Prefix: arg.Prefix, Scope: arg.Scope, ExpiresAt: arg.ExpiresAt}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"key":"greed_`,
+		},
+		{
+			name:            "should default to read scope",
+			userIDInContext: testUserID,
+			requestBody:     `{"name":"dashboard"}`,
+			mockDb: &mockDatabaseService{
+				CreateAPIKeyFunc: func(ctx context.Context, arg database.CreateAPIKeyParams) (database.ApiKey, error) {
+					return database.ApiKey{ID: arg.ID, Name: arg.Name, Scope: arg.Scope}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"scope":"read"`,
+		},
+		{
+			name:            "should err with missing name",
+			userIDInContext: testUserID,
+			requestBody:     `{"scope":"read"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Key name must be between 1 and 100 characters",
+		},
+		{
+			name:            "should err with unknown scope",
+			userIDInContext: testUserID,
+			requestBody:     `{"name":"cron","scope":"admin"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Scope must be read or write",
+		},
+		{
+			name:            "should err with expiry in the past",
+			userIDInContext: testUserID,
+			requestBody:     fmt.Sprintf(`{"name":"cron","expires_at":"%s"}`, past),
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Expiry must be in the future",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			requestBody:     `{"name":"cron"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err creating key",
+			userIDInContext: testUserID,
+			requestBody:     `{"name":"cron"}`,
+			mockDb: &mockDatabaseService{
+				CreateAPIKeyFunc: func(ctx context.Context, arg database.CreateAPIKeyParams) (database.ApiKey, error) {
+					return database.ApiKey{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/keys", bytes.NewBufferString(tt.requestBody))
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerCreateAPIKey(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerGetAPIKeys(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should get keys without their hashes",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetAPIKeysForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error) {
+					return []database.ApiKey{{ID: testAPIKeyID, Name: "cron", KeyHash: "secret-hash", Prefix: "greed_0123abcd", Scope: auth.ScopeRead}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"prefix":"greed_0123abcd"`,
+		},
+		{
+			name:            "should return empty list with no keys",
+			userIDInContext: testUserID,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "[]",
+		},
+		{
+			name:            "should err getting keys",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetAPIKeysForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/keys", nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetAPIKeys(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+			if strings.Contains(rr.Body.String(), "secret-hash") {
+				t.Errorf("handler returned key hash: %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerRevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		pathParams      map[string]string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should revoke key",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"key-id": testAPIKeyID.String()},
+			mockDb: &mockDatabaseService{
+				DeleteAPIKeyFunc: func(ctx context.Context, arg database.DeleteAPIKeyParams) (int64, error) {
+					return 1, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "API key revoked",
+		},
+		{
+			name:            "should err with key of another user",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"key-id": testAPIKeyID.String()},
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusNotFound,
+			expectedBody:    "API key not found",
+		},
+		{
+			name:            "should err with invalid key ID",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"key-id": "not-a-uuid"},
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Invalid key ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/keys/%s", tt.pathParams["key-id"]), nil)
+
+			rctx := chi.NewRouteContext()
+			for key, value := range tt.pathParams {
+				rctx.URLParams.Add(key, value)
+			}
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerRevokeAPIKey(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/blob"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+)
+
+// Longest note accepted for a transaction
+const maxNoteLength = 2000
+
+// Longest attachment file name kept, longer names are cut short
+const maxAttachmentNameLength = 255
+
+// Largest attachment accepted when the server isn't configured with a limit
+const defaultAttachmentMaxSize = 10 << 20
+
+// Allowance on top of the attachment size limit for the rest of a multipart request, such as part headers
+const multipartOverhead = 64 << 10
+
+// Time allowed to send or receive an attachment, in place of the server's shorter read and write timeouts
+const attachmentTransferTimeout = 5 * time.Minute
+
+// File types accepted as attachments, as detected from their contents. Receipts are usually photos or PDFs
+var attachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+}
+
+// Handler returns a transaction's note. Transactions without a note return an empty one
+func (app *AppServer) HandlerGetTransactionNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	txn, ok := ctx.Value(transactionKey).(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	note, err := app.Db.GetTransactionNote(ctx, txn.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		app.respondWithJSON(w, 200, models.TransactionNote{TransactionID: txn.ID})
+		return
+	}
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting transaction note: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, models.TransactionNote{TransactionID: note.TransactionID, Note: note.Note, UpdatedAt: note.UpdatedAt})
+}
+
+// Handler sets a transaction's note, removing it if the note is empty
+func (app *AppServer) HandlerUpdateTransactionNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	txn, ok := ctx.Value(transactionKey).(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	request := models.UpdateTransactionNote{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	text := strings.TrimSpace(request.Note)
+	if utf8.RuneCountInString(text) > maxNoteLength {
+		app.respondWithError(w, 400, fmt.Sprintf("Note must be at most %d characters", maxNoteLength), nil)
+		return
+	}
+
+	if text == "" {
+		if err := app.Db.DeleteTransactionNote(ctx, txn.ID); err != nil {
+			app.respondWithError(w, 500, "Database error", fmt.Errorf("error deleting transaction note: %w", err))
+			return
+		}
+		app.respondWithJSON(w, 200, models.TransactionNote{TransactionID: txn.ID})
+		return
+	}
+
+	note, err := app.Db.UpsertTransactionNote(ctx, database.UpsertTransactionNoteParams{TransactionID: txn.ID, Note: text})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error updating transaction note: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, models.TransactionNote{TransactionID: note.TransactionID, Note: note.Note, UpdatedAt: note.UpdatedAt})
+}
+
+// Handler lists the files attached to a transaction
+func (app *AppServer) HandlerGetTransactionAttachments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	txn, ok := ctx.Value(transactionKey).(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	attachments, err := app.Db.GetTransactionAttachments(ctx, txn.ID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting transaction attachments: %w", err))
+		return
+	}
+
+	response := []models.TransactionAttachment{}
+	for _, a := range attachments {
+		response = append(response, attachmentResponse(a))
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
+
+// Handler attaches a file to a transaction. The file is sent as the part named "file" of a multipart/form-data
+// request. Its type is detected from its contents rather than trusted from the client, and a SHA-256 checksum
+// is recorded as it is stored, so downloads can be verified
+func (app *AppServer) HandlerAddTransactionAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(userIDKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+	txn, ok := ctx.Value(transactionKey).(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	maxSize := app.attachmentMaxSize()
+	_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(attachmentTransferTimeout))
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		app.respondWithError(w, 400, "Request must be multipart/form-data, with the file in a part named file", err)
+		return
+	}
+
+	var part io.Reader
+	var fileName string
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			app.respondWithUploadError(w, err)
+			return
+		}
+		if p.FormName() == "file" {
+			part, fileName = p, p.FileName()
+			break
+		}
+	}
+	if part == nil {
+		app.respondWithError(w, 400, "Request must be multipart/form-data, with the file in a part named file", nil)
+		return
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		app.respondWithUploadError(w, err)
+		return
+	}
+	head = head[:n]
+	if n == 0 {
+		app.respondWithError(w, 400, "File is empty", nil)
+		return
+	}
+
+	contentType := http.DetectContentType(head)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !attachmentTypes[mediaType] {
+		app.respondWithError(w, 415, fmt.Sprintf("Files of type %s can't be attached, attach an image, PDF or text file", mediaType), nil)
+		return
+	}
+
+	id := uuid.New()
+	key := fmt.Sprintf("%s/%s", userID, id)
+	hasher := sha256.New()
+	body := &io.LimitedReader{R: io.MultiReader(bytes.NewReader(head), part), N: maxSize + 1}
+
+	size, err := app.Blobs.Put(ctx, key, io.TeeReader(body, hasher))
+	if err != nil || size > maxSize {
+		app.deleteBlob(key)
+		if err != nil {
+			app.respondWithUploadError(w, err)
+			return
+		}
+		app.respondWithError(w, 413, fmt.Sprintf("File is larger than the limit of %d bytes", maxSize), nil)
+		return
+	}
+
+	attachment, err := app.Db.CreateTransactionAttachment(ctx, database.CreateTransactionAttachmentParams{
+		ID:            id,
+		TransactionID: txn.ID,
+		UserID:        userID,
+		FileName:      cleanFileName(fileName),
+		ContentType:   contentType,
+		SizeBytes:     size,
+		Sha256:        hex.EncodeToString(hasher.Sum(nil)),
+		StorageKey:    key,
+	})
+	if err != nil {
+		app.deleteBlob(key)
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error creating transaction attachment: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 201, attachmentResponse(attachment))
+}
+
+// Handler sends the contents of a file attached to a transaction. The file's checksum is sent as its ETag
+func (app *AppServer) HandlerGetTransactionAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	attachment, ok := app.attachmentFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	file, err := app.Blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			app.respondWithError(w, 404, "Attachment file not found", err)
+			return
+		}
+		app.respondWithError(w, 500, "Storage error", fmt.Errorf("error opening attachment: %w", err))
+		return
+	}
+	defer file.Close()
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(attachmentTransferTimeout))
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("ETag", strconv.Quote(attachment.Sha256))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(200)
+
+	if _, err := io.Copy(w, file); err != nil {
+		_ = app.Logger.Log(
+			"level", "warning",
+			"msg", "error sending attachment",
+			"attachment_id", attachment.ID,
+			"err", err,
+		)
+	}
+}
+
+// Handler deletes a file attached to a transaction
+func (app *AppServer) HandlerDeleteTransactionAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	attachment, ok := app.attachmentFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := app.Db.DeleteTransactionAttachment(ctx, attachment.ID); err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error deleting transaction attachment: %w", err))
+		return
+	}
+	app.deleteBlob(attachment.StorageKey)
+
+	app.respondWithJSON(w, 200, "Attachment deleted successfully")
+}
+
+// Gets the attachment named in the request's URL, belonging to the transaction in the request context
+func (app *AppServer) attachmentFromRequest(w http.ResponseWriter, r *http.Request) (database.TransactionAttachment, bool) {
+	ctx := r.Context()
+	txn, ok := ctx.Value(transactionKey).(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return database.TransactionAttachment{}, false
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "attachment-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid attachment ID", nil)
+		return database.TransactionAttachment{}, false
+	}
+
+	attachment, err := app.Db.GetTransactionAttachment(ctx, database.GetTransactionAttachmentParams{ID: id, TransactionID: txn.ID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			app.respondWithError(w, 404, "Attachment not found", nil)
+			return database.TransactionAttachment{}, false
+		}
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting transaction attachment: %w", err))
+		return database.TransactionAttachment{}, false
+	}
+
+	return attachment, true
+}
+
+// Responds to an error reading an uploaded file, which is the client's fault when the upload is too large
+func (app *AppServer) respondWithUploadError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		app.respondWithError(w, 413, fmt.Sprintf("File is larger than the limit of %d bytes", app.attachmentMaxSize()), nil)
+		return
+	}
+	app.respondWithError(w, 400, "Error reading uploaded file", err)
+}
+
+// Deletes a stored attachment's contents, logging failures. A blob left behind only takes up space, so
+// failing to delete it doesn't fail the request
+func (app *AppServer) deleteBlob(key string) {
+	if err := app.Blobs.Delete(context.Background(), key); err != nil {
+		_ = app.Logger.Log(
+			"level", "warning",
+			"msg", "error deleting attachment file",
+			"key", key,
+			"err", err,
+		)
+	}
+}
+
+// Largest attachment accepted, in bytes
+func (app *AppServer) attachmentMaxSize() int64 {
+	if app.Config != nil && app.Config.AttachmentMaxSize > 0 {
+		return app.Config.AttachmentMaxSize
+	}
+	return defaultAttachmentMaxSize
+}
+
+// Name an uploaded file is kept under. Directories and control characters are removed, so the name is
+// safe to send back in a Content-Disposition header and to save to disk
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+
+	if name == "" || name == "." || name == "/" || name == ".." {
+		return "attachment"
+	}
+	if utf8.RuneCountInString(name) > maxAttachmentNameLength {
+		name = string([]rune(name)[:maxAttachmentNameLength])
+	}
+
+	return name
+}
+
+// Response for a transaction attachment
+func attachmentResponse(a database.TransactionAttachment) models.TransactionAttachment {
+	return models.TransactionAttachment{
+		ID:            a.ID.String(),
+		TransactionID: a.TransactionID,
+		FileName:      a.FileName,
+		ContentType:   a.ContentType,
+		SizeBytes:     a.SizeBytes,
+		SHA256:        a.Sha256,
+		CreatedAt:     a.CreatedAt,
+	}
+}
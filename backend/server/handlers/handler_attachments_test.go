@@ -0,0 +1,597 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/blob"
+	"github.com/jms-guy/greed/backend/internal/config"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Start of a PNG file, enough for its type to be detected
+var testPNG = append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 64)...)
+
+// Multipart request body holding a file in a part with the given name
+func multipartBody(t *testing.T, field, fileName string, contents []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, fileName)
+	require.NoError(t, err)
+	_, err = part.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestHandlerGetTransactionNote(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "should get note",
+			mockDb: &mockDatabaseService{
+				GetTransactionNoteFunc: func(ctx context.Context, transactionID string) (database.TransactionNote, error) {
+					return database.TransactionNote{TransactionID: transactionID, Note: "Lunch with Sam"}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"note":"Lunch with Sam"`,
+		},
+		{
+			name: "should return empty note for transaction without one",
+			mockDb: &mockDatabaseService{
+				GetTransactionNoteFunc: func(ctx context.Context, transactionID string) (database.TransactionNote, error) {
+					return database.TransactionNote{}, sql.ErrNoRows
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"note":""`,
+		},
+		{
+			name: "should err on database error",
+			mockDb: &mockDatabaseService{
+				GetTransactionNoteFunc: func(ctx context.Context, transactionID string) (database.TransactionNote, error) {
+					return database.TransactionNote{}, errors.New("database error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/accounts/"+testAccountID+"/transactions/"+testTransaction.ID+"/note", nil)
+			ctx := context.WithValue(req.Context(), handlers.GetTransactionKey(), testTransaction)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetTransactionNote(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestHandlerUpdateTransactionNote(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "should set trimmed note",
+			requestBody: `{"note":"  Lunch with Sam  "}`,
+			mockDb: &mockDatabaseService{
+				UpsertTransactionNoteFunc: func(ctx context.Context, arg database.UpsertTransactionNoteParams) (database.TransactionNote, error) {
+					assert.Equal(t, testTransaction.ID, arg.TransactionID)
+					assert.Equal(t, "Lunch with Sam", arg.Note)
+					return database.TransactionNote{TransactionID: arg.TransactionID, Note: arg.Note}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"note":"Lunch with Sam"`,
+		},
+		{
+			name:        "should delete empty note",
+			requestBody: `{"note":"   "}`,
+			mockDb: &mockDatabaseService{
+				UpsertTransactionNoteFunc: func(ctx context.Context, arg database.UpsertTransactionNoteParams) (database.TransactionNote, error) {
+					t.Error("empty note should not be stored")
+					return database.TransactionNote{}, nil
+				},
+				DeleteTransactionNoteFunc: func(ctx context.Context, transactionID string) error {
+					assert.Equal(t, testTransaction.ID, transactionID)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"note":""`,
+		},
+		{
+			name:           "should err on note that is too long",
+			requestBody:    `{"note":"` + strings.Repeat("a", 2001) + `"}`,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Note must be at most 2000 characters",
+		},
+		{
+			name:           "should err on bad request data",
+			requestBody:    `{"note":`,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad request data",
+		},
+		{
+			name:        "should err on database error",
+			requestBody: `{"note":"Lunch"}`,
+			mockDb: &mockDatabaseService{
+				UpsertTransactionNoteFunc: func(ctx context.Context, arg database.UpsertTransactionNoteParams) (database.TransactionNote, error) {
+					return database.TransactionNote{}, errors.New("database error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/accounts/"+testAccountID+"/transactions/"+testTransaction.ID+"/note", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), handlers.GetTransactionKey(), testTransaction)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerUpdateTransactionNote(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestHandlerAddTransactionAttachment(t *testing.T) {
+	tests := []struct {
+		name           string
+		field          string
+		fileName       string
+		contents       []byte
+		maxSize        int64
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+		expectStored   bool
+	}{
+		{
+			name:     "should attach file",
+			field:    "file",
+			fileName: "../../receipt.png",
+			contents: testPNG,
+			mockDb: &mockDatabaseService{
+				CreateTransactionAttachmentFunc: func(ctx context.Context, arg database.CreateTransactionAttachmentParams) (database.TransactionAttachment, error) {
+					sum := sha256.Sum256(testPNG)
+					assert.Equal(t, testTransaction.ID, arg.TransactionID)
+					assert.Equal(t, testUserID, arg.UserID)
+					assert.Equal(t, "receipt.png", arg.FileName)
+					assert.Equal(t, "image/png", arg.ContentType)
+					assert.Equal(t, int64(len(testPNG)), arg.SizeBytes)
+					assert.Equal(t, hex.EncodeToString(sum[:]), arg.Sha256)
+					assert.Equal(t, testUserID.String()+"/"+arg.ID.String(), arg.StorageKey)
+					return database.TransactionAttachment{
+						ID:            arg.ID,
+						TransactionID: arg.TransactionID,
+						FileName:      arg.FileName,
+						ContentType:   arg.ContentType,
+						SizeBytes:     arg.SizeBytes,
+						Sha256:        arg.Sha256,
+						StorageKey:    arg.StorageKey,
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"file_name":"receipt.png"`,
+			expectStored:   true,
+		},
+		{
+			name:           "should err on unsupported file type",
+			field:          "file",
+			fileName:       "tool.exe",
+			contents:       []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00"),
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   "unsupported_media_type",
+		},
+		{
+			name:           "should err on file over the size limit",
+			field:          "file",
+			fileName:       "receipt.png",
+			contents:       testPNG,
+			maxSize:        16,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "payload_too_large",
+		},
+		{
+			name:           "should err without file part",
+			field:          "upload",
+			fileName:       "receipt.png",
+			contents:       testPNG,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "part named file",
+		},
+		{
+			name:           "should err on empty file",
+			field:          "file",
+			fileName:       "receipt.png",
+			contents:       nil,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "File is empty",
+		},
+		{
+			name:     "should remove stored file on database error",
+			field:    "file",
+			fileName: "receipt.png",
+			contents: testPNG,
+			mockDb: &mockDatabaseService{
+				CreateTransactionAttachmentFunc: func(ctx context.Context, arg database.CreateTransactionAttachmentParams) (database.TransactionAttachment, error) {
+					return database.TransactionAttachment{}, errors.New("database error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			store, err := blob.NewLocalStore(root)
+			require.NoError(t, err)
+
+			body, contentType := multipartBody(t, tt.field, tt.fileName, tt.contents)
+			req := httptest.NewRequest("POST", "/api/accounts/"+testAccountID+"/transactions/"+testTransaction.ID+"/attachments/", body)
+			req.Header.Set("Content-Type", contentType)
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), testUserID)
+			ctx = context.WithValue(ctx, handlers.GetTransactionKey(), testTransaction)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Blobs:  store,
+				Config: &config.Config{AttachmentMaxSize: tt.maxSize},
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerAddTransactionAttachment(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+
+			stored, err := filepath.Glob(filepath.Join(root, testUserID.String(), "*"))
+			require.NoError(t, err)
+			if tt.expectStored {
+				assert.Len(t, stored, 1)
+			} else {
+				assert.Empty(t, stored, "no file should be left in storage")
+			}
+		})
+	}
+}
+
+func TestHandlerGetTransactionAttachment(t *testing.T) {
+	attachmentID := uuid.New()
+	key := testUserID.String() + "/" + attachmentID.String()
+	contents := []byte("Coffee 4.50")
+
+	tests := []struct {
+		name           string
+		attachmentID   string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:         "should download attachment",
+			attachmentID: attachmentID.String(),
+			mockDb: &mockDatabaseService{
+				GetTransactionAttachmentFunc: func(ctx context.Context, arg database.GetTransactionAttachmentParams) (database.TransactionAttachment, error) {
+					assert.Equal(t, attachmentID, arg.ID)
+					assert.Equal(t, testTransaction.ID, arg.TransactionID)
+					return database.TransactionAttachment{
+						ID:          attachmentID,
+						FileName:    "receipt.txt",
+						ContentType: "text/plain; charset=utf-8",
+						SizeBytes:   int64(len(contents)),
+						Sha256:      "abc123",
+						StorageKey:  key,
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   string(contents),
+		},
+		{
+			name:         "should err on attachment of another transaction",
+			attachmentID: attachmentID.String(),
+			mockDb: &mockDatabaseService{
+				GetTransactionAttachmentFunc: func(ctx context.Context, arg database.GetTransactionAttachmentParams) (database.TransactionAttachment, error) {
+					return database.TransactionAttachment{}, sql.ErrNoRows
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Attachment not found",
+		},
+		{
+			name:           "should err on invalid attachment ID",
+			attachmentID:   "not-an-id",
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid attachment ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := blob.NewLocalStore(t.TempDir())
+			require.NoError(t, err)
+			_, err = store.Put(context.Background(), key, bytes.NewReader(contents))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("GET", "/api/accounts/"+testAccountID+"/transactions/"+testTransaction.ID+"/attachments/"+tt.attachmentID, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("attachment-id", tt.attachmentID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetTransactionKey(), testTransaction)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Blobs:  store,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetTransactionAttachment(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename=receipt.txt`, rr.Header().Get("Content-Disposition"))
+				assert.Equal(t, `"abc123"`, rr.Header().Get("ETag"))
+				assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+			}
+		})
+	}
+}
+
+func TestHandlerDeleteTransactionAttachment(t *testing.T) {
+	attachmentID := uuid.New()
+	key := testUserID.String() + "/" + attachmentID.String()
+
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	_, err = store.Put(context.Background(), key, strings.NewReader("receipt"))
+	require.NoError(t, err)
+
+	deleted := false
+	mockDb := &mockDatabaseService{
+		GetTransactionAttachmentFunc: func(ctx context.Context, arg database.GetTransactionAttachmentParams) (database.TransactionAttachment, error) {
+			return database.TransactionAttachment{ID: attachmentID, TransactionID: arg.TransactionID, StorageKey: key}, nil
+		},
+		DeleteTransactionAttachmentFunc: func(ctx context.Context, id uuid.UUID) error {
+			assert.Equal(t, attachmentID, id)
+			deleted = true
+			return nil
+		},
+	}
+
+	req := httptest.NewRequest("DELETE", "/api/accounts/"+testAccountID+"/transactions/"+testTransaction.ID+"/attachments/"+attachmentID.String(), nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("attachment-id", attachmentID.String())
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, handlers.GetTransactionKey(), testTransaction)
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+
+	mockApp := &handlers.AppServer{
+		Db:     mockDb,
+		Blobs:  store,
+		Logger: kitlog.NewNopLogger(),
+	}
+
+	mockApp.HandlerDeleteTransactionAttachment(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.True(t, deleted)
+
+	_, err = store.Get(context.Background(), key)
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+}
+
+func TestSweepAttachmentBlobs(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	keys := []string{testUserID.String() + "/" + uuid.NewString(), testUserID.String() + "/" + uuid.NewString()}
+	for _, key := range keys {
+		_, err := store.Put(ctx, key, bytes.NewReader(testPNG))
+		require.NoError(t, err)
+	}
+	kept := testUserID.String() + "/" + uuid.NewString()
+	_, err = store.Put(ctx, kept, bytes.NewReader(testPNG))
+	require.NoError(t, err)
+
+	dequeued := []string{}
+	mockDb := &mockDatabaseService{
+		GetOrphanedAttachmentBlobsFunc: func(ctx context.Context, limit int32) ([]string, error) {
+			if len(dequeued) > 0 {
+				return nil, nil
+			}
+			return keys, nil
+		},
+		DeleteOrphanedAttachmentBlobFunc: func(ctx context.Context, storageKey string) error {
+			dequeued = append(dequeued, storageKey)
+			return nil
+		},
+	}
+
+	mockApp := &handlers.AppServer{
+		Db:     mockDb,
+		Blobs:  store,
+		Logger: kitlog.NewNopLogger(),
+	}
+
+	mockApp.SweepAttachmentBlobs(ctx)
+
+	assert.Equal(t, keys, dequeued)
+	for _, key := range keys {
+		_, err := store.Get(ctx, key)
+		assert.ErrorIs(t, err, blob.ErrNotFound)
+	}
+	r, err := store.Get(ctx, kept)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+}
+
+func TestSweepAttachmentBlobsKeepsFailedKeysQueued(t *testing.T) {
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	calls := 0
+	mockDb := &mockDatabaseService{
+		GetOrphanedAttachmentBlobsFunc: func(ctx context.Context, limit int32) ([]string, error) {
+			calls++
+			return []string{"../outside"}, nil
+		},
+		DeleteOrphanedAttachmentBlobFunc: func(ctx context.Context, storageKey string) error {
+			t.Errorf("key %s dequeued although its file wasn't deleted", storageKey)
+			return nil
+		},
+	}
+
+	mockApp := &handlers.AppServer{
+		Db:     mockDb,
+		Blobs:  store,
+		Logger: kitlog.NewNopLogger(),
+	}
+
+	mockApp.SweepAttachmentBlobs(context.Background())
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestDeleteAccountRemovesAttachmentBlobs(t *testing.T) {
+	db := openTestDB(t)
+	userID := createTestUser(t, db)
+
+	ctx := context.Background()
+	q := database.New(db)
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	item, err := q.CreateItem(ctx, database.CreateItemParams{
+		ID:              "item-" + uuid.NewString(),
+		UserID:          userID,
+		AccessToken:     "access",
+		InstitutionName: "Test Bank",
+		CountryCode:     "CA",
+	})
+	require.NoError(t, err)
+	acc, err := q.CreateAccount(ctx, database.CreateAccountParams{
+		ID:     "acc-" + uuid.NewString(),
+		Name:   "Chequing",
+		Type:   "depository",
+		ItemID: sql.NullString{String: item.ID, Valid: true},
+		UserID: userID,
+	})
+	require.NoError(t, err)
+	txn, err := q.CreateTransaction(ctx, database.CreateTransactionParams{
+		ID:                      "txn-" + uuid.NewString(),
+		AccountID:               acc.ID,
+		Amount:                  "12.50",
+		PaymentChannel:          "in store",
+		PersonalFinanceCategory: "FOOD_AND_DRINK",
+	})
+	require.NoError(t, err)
+
+	attachmentID := uuid.New()
+	key := userID.String() + "/" + attachmentID.String()
+	_, err = store.Put(ctx, key, bytes.NewReader(testPNG))
+	require.NoError(t, err)
+	_, err = q.CreateTransactionAttachment(ctx, database.CreateTransactionAttachmentParams{
+		ID:            attachmentID,
+		TransactionID: txn.ID,
+		UserID:        userID,
+		FileName:      "receipt.png",
+		ContentType:   "image/png",
+		SizeBytes:     int64(len(testPNG)),
+		Sha256:        "checksum",
+		StorageKey:    key,
+	})
+	require.NoError(t, err)
+
+	app := &handlers.AppServer{
+		Db:     q,
+		Blobs:  store,
+		Logger: kitlog.NewNopLogger(),
+	}
+
+	req := httptest.NewRequest("DELETE", "/api/accounts/"+acc.ID, nil)
+	reqCtx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), userID)
+	reqCtx = context.WithValue(reqCtx, handlers.GetAccountKey(), acc)
+	req = req.WithContext(reqCtx)
+	rr := httptest.NewRecorder()
+
+	app.HandlerDeleteAccount(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	app.SweepAttachmentBlobs(ctx)
+
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+}
@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/events"
+	"github.com/jms-guy/greed/backend/internal/notify"
+	"github.com/jms-guy/greed/models"
+)
+
+// Handler gets the user's events in the order they were recorded, for integrations polling instead of
+// receiving webhooks. The "after" query parameter gives the sequence number of the last event seen,
+// "type" filters to a single event type, and "limit" sets how many are returned, defaulting to 100
+func (app *AppServer) HandlerGetEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var after int64
+	if a := query.Get("after"); a != "" {
+		var err error
+		after, err = strconv.ParseInt(a, 10, 64)
+		if err != nil || after < 0 {
+			app.respondWithError(w, 400, "Bad query parameter: after must be a sequence number", nil)
+			return
+		}
+	}
+
+	eventType := query.Get("type")
+	if eventType != "" && !events.ValidFilter(eventType) {
+		app.respondWithError(w, 400, "Bad query parameter: unknown event type", nil)
+		return
+	}
+
+	limit := 100
+	if l := query.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > 1000 {
+			app.respondWithError(w, 400, "Bad query parameter: limit must be between 1 and 1000", nil)
+			return
+		}
+	}
+
+	rows, err := app.Db.GetEventsForUser(ctx, database.GetEventsForUserParams{
+		UserID:    id,
+		AfterSeq:  after,
+		EventType: eventType,
+		RowLimit:  int32(limit),
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting events: %w", err))
+		return
+	}
+
+	response := []models.Event{}
+	for _, row := range rows {
+		response = append(response, models.Event{
+			ID:        row.ID,
+			Seq:       row.Seq,
+			Type:      row.Type,
+			CreatedAt: row.CreatedAt,
+			Data:      row.Payload,
+		})
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
+
+// Handler gets the user's event endpoints
+func (app *AppServer) HandlerGetEventEndpoints(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	endpoints, err := app.Db.GetEventEndpointsForUser(ctx, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting event endpoints: %w", err))
+		return
+	}
+
+	response := []models.EventEndpoint{}
+	for _, endpoint := range endpoints {
+		response = append(response, eventEndpointResponse(endpoint))
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
+
+// Handler registers an endpoint to receive the user's events. With no event types given, the endpoint
+// receives every event. Its signing secret is encrypted in the database and only returned in this response
+func (app *AppServer) HandlerCreateEventEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	request := models.CreateEventEndpoint{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	if !app.validWebhookURL(request.URL) {
+		app.respondWithError(w, 400, "Endpoint URL must be an absolute https URL to a public address", nil)
+		return
+	}
+
+	eventTypes := []string{}
+	for _, t := range request.EventTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !events.ValidFilter(t) {
+			app.respondWithError(w, 400, fmt.Sprintf("Unknown event type: %s", t), nil)
+			return
+		}
+		eventTypes = append(eventTypes, t)
+	}
+
+	secret, err := notify.NewSecret()
+	if err != nil {
+		app.respondWithError(w, 500, "Error generating endpoint secret", err)
+		return
+	}
+	encryptedSecret, err := app.Encryptor.EncryptAccessToken([]byte(secret), app.Config.AESKey)
+	if err != nil {
+		app.respondWithError(w, 500, "Error encrypting endpoint secret", err)
+		return
+	}
+
+	endpoint, err := app.Db.CreateEventEndpoint(ctx, database.CreateEventEndpointParams{
+		ID:         uuid.New(),
+		UserID:     id,
+		Url:        request.URL,
+		Secret:     encryptedSecret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error creating event endpoint: %w", err))
+		return
+	}
+
+	response := eventEndpointResponse(endpoint)
+	response.Secret = secret
+
+	app.respondWithJSON(w, 201, response)
+}
+
+// Handler deletes one of the user's event endpoints, along with its deliveries
+func (app *AppServer) HandlerDeleteEventEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	endpointID, err := uuid.Parse(chi.URLParam(r, "endpoint-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid endpoint ID", nil)
+		return
+	}
+
+	count, err := app.Db.DeleteEventEndpoint(ctx, database.DeleteEventEndpointParams{
+		ID:     endpointID,
+		UserID: id,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error deleting event endpoint: %w", err))
+		return
+	}
+	if count == 0 {
+		app.respondWithError(w, 404, "Endpoint not found", nil)
+		return
+	}
+
+	app.respondWithJSON(w, 200, "Endpoint deleted")
+}
+
+// Handler gets the most recent deliveries to one of the user's event endpoints. The "limit" query
+// parameter sets how many are returned, defaulting to 50
+func (app *AppServer) HandlerGetEventDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	endpointID, err := uuid.Parse(chi.URLParam(r, "endpoint-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid endpoint ID", nil)
+		return
+	}
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > 500 {
+			app.respondWithError(w, 400, "Bad query parameter: limit must be between 1 and 500", nil)
+			return
+		}
+	}
+
+	found, err := app.userHasEventEndpoint(ctx, id, endpointID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", err)
+		return
+	}
+	if !found {
+		app.respondWithError(w, 404, "Endpoint not found", nil)
+		return
+	}
+
+	rows, err := app.Db.GetEventDeliveriesForEndpoint(ctx, database.GetEventDeliveriesForEndpointParams{
+		EndpointID: endpointID,
+		UserID:     id,
+		RowLimit:   int32(limit),
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting event deliveries: %w", err))
+		return
+	}
+
+	deliveries := []models.EventDelivery{}
+	for _, row := range rows {
+		d := models.EventDelivery{
+			ID:            row.ID,
+			EventID:       row.EventID,
+			Seq:           row.Seq,
+			Type:          row.Type,
+			Status:        row.Status,
+			Attempts:      int(row.Attempts),
+			LastError:     row.LastError.String,
+			NextAttemptAt: row.NextAttemptAt,
+			CreatedAt:     row.CreatedAt,
+		}
+		if row.DeliveredAt.Valid {
+			d.DeliveredAt = &row.DeliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	app.respondWithJSON(w, 200, deliveries)
+}
+
+// Handler queues the user's events recorded in a time window to be sent to an endpoint again, whether
+// or not they were delivered before. The window ends now when no "until" is given
+func (app *AppServer) HandlerReplayEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	endpointID, err := uuid.Parse(chi.URLParam(r, "endpoint-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid endpoint ID", nil)
+		return
+	}
+
+	request := models.ReplayEvents{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	if request.Since.IsZero() {
+		app.respondWithError(w, 400, "Replay requires a since time", nil)
+		return
+	}
+	until := time.Now()
+	if request.Until != nil {
+		until = *request.Until
+	}
+	if !until.After(request.Since) {
+		app.respondWithError(w, 400, "Until must be after since", nil)
+		return
+	}
+
+	found, err := app.userHasEventEndpoint(ctx, id, endpointID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", err)
+		return
+	}
+	if !found {
+		app.respondWithError(w, 404, "Endpoint not found", nil)
+		return
+	}
+
+	count, err := app.Db.ReplayEvents(ctx, database.ReplayEventsParams{
+		EndpointID: endpointID,
+		UserID:     id,
+		Since:      request.Since,
+		Until:      until,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error replaying events: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, fmt.Sprintf("%d events queued for replay", count))
+}
+
+// Reports whether the endpoint belongs to the user
+func (app *AppServer) userHasEventEndpoint(ctx context.Context, userID, endpointID uuid.UUID) (bool, error) {
+	endpoints, err := app.Db.GetEventEndpointsForUser(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("error getting event endpoints: %w", err)
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.ID == endpointID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func eventEndpointResponse(endpoint database.EventEndpoint) models.EventEndpoint {
+	eventTypes := endpoint.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	return models.EventEndpoint{
+		ID:         endpoint.ID,
+		URL:        endpoint.Url,
+		EventTypes: eventTypes,
+		Enabled:    endpoint.Enabled,
+		CreatedAt:  endpoint.CreatedAt,
+	}
+}
@@ -0,0 +1,614 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/auth"
+	"github.com/jms-guy/greed/backend/internal/config"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/events"
+	"github.com/jms-guy/greed/backend/internal/notify"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/jms-guy/greed/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testEndpointID      = uuid.MustParse("7f6e5d4c-3b2a-4f1e-8d9c-8b7a69584736")
+	testEventID         = uuid.MustParse("8a7f6e5d-4c3b-4a2f-9e1d-9c8b7a695847")
+	testEventDeliveryID = uuid.MustParse("9b8a7f6e-5d4c-4b3a-8f2e-ad9c8b7a6958")
+)
+
+func TestHandlerGetEvents(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		query           string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should get events after sequence number",
+			userIDInContext: testUserID,
+			query:           "?after=41&type=balance.updated&limit=10",
+			mockDb: &mockDatabaseService{
+				GetEventsForUserFunc: func(ctx context.Context, arg database.GetEventsForUserParams) ([]database.GetEventsForUserRow, error) {
+					if arg.AfterSeq != 41 || arg.EventType != events.BalanceUpdated || arg.RowLimit != 10 {
+						return nil, fmt.Errorf("unexpected params: %+v", arg)
+					}
+					return []database.GetEventsForUserRow{{
+						ID:      testEventID,
+						Seq:     42,
+						Type:    events.BalanceUpdated,
+						Payload: json.RawMessage(`{"account_id":"54321"}`),
+					}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"data":{"account_id":"54321"}`,
+		},
+		{
+			name:            "should return empty list with no events",
+			userIDInContext: testUserID,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "[]",
+		},
+		{
+			name:            "should err with bad after parameter",
+			userIDInContext: testUserID,
+			query:           "?after=latest",
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "after must be a sequence number",
+		},
+		{
+			name:            "should err with unknown event type",
+			userIDInContext: testUserID,
+			query:           "?type=account.opened",
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "unknown event type",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err getting events",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetEventsForUserFunc: func(ctx context.Context, arg database.GetEventsForUserParams) ([]database.GetEventsForUserRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/events"+tt.query, nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetEvents(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerCreateEventEndpoint(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		requestBody     string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should create endpoint and return secret once",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"https://example.com/events","event_types":["Transaction.*","balance.updated"]}`,
+			mockDb: &mockDatabaseService{
+				CreateEventEndpointFunc: func(ctx context.Context, arg database.CreateEventEndpointParams) (database.EventEndpoint, error) {
+					if arg.Secret != "encrypted" {
+						return database.EventEndpoint{}, fmt.Errorf("endpoint secret stored unencrypted")
+					}
+					return database.EventEndpoint{ID: arg.ID, Url: arg.Url, EventTypes: arg.EventTypes, Enabled: true}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"event_types":["transaction.*","balance.updated"],"secret":"`,
+		},
+		{
+			name:            "should subscribe to every event with no types given",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"https://example.com/events"}`,
+			mockDb: &mockDatabaseService{
+				CreateEventEndpointFunc: func(ctx context.Context, arg database.CreateEventEndpointParams) (database.EventEndpoint, error) {
+					return database.EventEndpoint{ID: arg.ID, Url: arg.Url, EventTypes: arg.EventTypes}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"event_types":[]`,
+		},
+		{
+			name:            "should err with plain http url",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"http://example.com/events"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Endpoint URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with private endpoint address",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"https://10.0.0.5/events"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Endpoint URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with loopback endpoint address",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"https://127.0.0.1:8443/events"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Endpoint URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with cloud metadata endpoint address",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"https://169.254.169.254/latest/meta-data"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Endpoint URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with localhost endpoint address",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"https://localhost/events"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Endpoint URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with unknown event type",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"https://example.com/events","event_types":["account.opened"]}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Unknown event type: account.opened",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			requestBody:     `{"url":"https://example.com/events"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err creating endpoint",
+			userIDInContext: testUserID,
+			requestBody:     `{"url":"https://example.com/events"}`,
+			mockDb: &mockDatabaseService{
+				CreateEventEndpointFunc: func(ctx context.Context, arg database.CreateEventEndpointParams) (database.EventEndpoint, error) {
+					return database.EventEndpoint{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/events/endpoints", bytes.NewBufferString(tt.requestBody))
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db: tt.mockDb,
+				Encryptor: &mockEncryptor{
+					EncryptAccessTokenFunc: func(plaintext []byte, keyString string) (string, error) {
+						return "encrypted", nil
+					},
+				},
+				Config: &config.Config{},
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerCreateEventEndpoint(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerDeleteEventEndpoint(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		pathParams      map[string]string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should delete endpoint",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			mockDb: &mockDatabaseService{
+				DeleteEventEndpointFunc: func(ctx context.Context, arg database.DeleteEventEndpointParams) (int64, error) {
+					return 1, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Endpoint deleted",
+		},
+		{
+			name:            "should err with endpoint of another user",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusNotFound,
+			expectedBody:    "Endpoint not found",
+		},
+		{
+			name:            "should err with invalid endpoint ID",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": "not-a-uuid"},
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Invalid endpoint ID",
+		},
+		{
+			name:            "should err deleting endpoint",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			mockDb: &mockDatabaseService{
+				DeleteEventEndpointFunc: func(ctx context.Context, arg database.DeleteEventEndpointParams) (int64, error) {
+					return 0, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/events/endpoints/%s", tt.pathParams["endpoint-id"]), nil)
+
+			rctx := chi.NewRouteContext()
+			for key, value := range tt.pathParams {
+				rctx.URLParams.Add(key, value)
+			}
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerDeleteEventEndpoint(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerReplayEvents(t *testing.T) {
+	ownEndpoint := func(ctx context.Context, userID uuid.UUID) ([]database.EventEndpoint, error) {
+		return []database.EventEndpoint{{ID: testEndpointID, UserID: userID}}, nil
+	}
+
+	tests := []struct {
+		name            string
+		userIDInContext any
+		pathParams      map[string]string
+		requestBody     string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should queue events in window",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			requestBody:     `{"since":"2025-03-01T00:00:00Z","until":"2025-03-02T00:00:00Z"}`,
+			mockDb: &mockDatabaseService{
+				GetEventEndpointsForUserFunc: ownEndpoint,
+				ReplayEventsFunc: func(ctx context.Context, arg database.ReplayEventsParams) (int64, error) {
+					if !arg.Until.Equal(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)) {
+						return 0, fmt.Errorf("unexpected until: %s", arg.Until)
+					}
+					return 7, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "7 events queued for replay",
+		},
+		{
+			name:            "should replay up to now with no until",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			requestBody:     `{"since":"2025-03-01T00:00:00Z"}`,
+			mockDb: &mockDatabaseService{
+				GetEventEndpointsForUserFunc: ownEndpoint,
+				ReplayEventsFunc: func(ctx context.Context, arg database.ReplayEventsParams) (int64, error) {
+					if time.Since(arg.Until) > time.Minute {
+						return 0, fmt.Errorf("until not defaulted to now: %s", arg.Until)
+					}
+					return 2, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "2 events queued for replay",
+		},
+		{
+			name:            "should err with missing since",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			requestBody:     `{}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Replay requires a since time",
+		},
+		{
+			name:            "should err with window ending before it starts",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			requestBody:     `{"since":"2025-03-02T00:00:00Z","until":"2025-03-01T00:00:00Z"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Until must be after since",
+		},
+		{
+			name:            "should err with endpoint of another user",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			requestBody:     `{"since":"2025-03-01T00:00:00Z"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusNotFound,
+			expectedBody:    "Endpoint not found",
+		},
+		{
+			name:            "should err replaying events",
+			userIDInContext: testUserID,
+			pathParams:      map[string]string{"endpoint-id": testEndpointID.String()},
+			requestBody:     `{"since":"2025-03-01T00:00:00Z"}`,
+			mockDb: &mockDatabaseService{
+				GetEventEndpointsForUserFunc: ownEndpoint,
+				ReplayEventsFunc: func(ctx context.Context, arg database.ReplayEventsParams) (int64, error) {
+					return 0, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/events/endpoints/%s/replay", tt.pathParams["endpoint-id"]), bytes.NewBufferString(tt.requestBody))
+
+			rctx := chi.NewRouteContext()
+			for key, value := range tt.pathParams {
+				rctx.URLParams.Add(key, value)
+			}
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerReplayEvents(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestDispatchEvents(t *testing.T) {
+	delivery := database.ClaimDueEventDeliveriesRow{
+		ID:        testEventDeliveryID,
+		EventID:   testEventID,
+		Seq:       42,
+		Type:      events.TransactionRemoved,
+		Payload:   json.RawMessage(`{"id":"txn","account_id":"54321"}`),
+		CreatedAt: time.Now(),
+		Url:       "https://example.com/events",
+		Secret:    "encrypted",
+	}
+
+	tests := []struct {
+		name           string
+		attempts       int32
+		postErr        error
+		expectedStatus string
+		expectedRetry  bool
+	}{
+		{
+			name:           "delivery is recorded as delivered",
+			expectedStatus: notify.StatusDelivered,
+		},
+		{
+			name:           "failed delivery is retried later",
+			attempts:       2,
+			postErr:        fmt.Errorf("connection refused"),
+			expectedStatus: notify.StatusPending,
+			expectedRetry:  true,
+		},
+		{
+			name:           "delivery failing its last attempt is marked failed",
+			attempts:       notify.MaxAttempts - 1,
+			postErr:        fmt.Errorf("endpoint returned 500"),
+			expectedStatus: notify.StatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				fannedOut bool
+				recorded  []database.RecordEventDeliveryAttemptParams
+			)
+
+			d := delivery
+			d.Attempts = tt.attempts
+			mockDb := &mockDatabaseService{
+				FanOutEventsFunc: func(ctx context.Context, batchSize int32) (int64, error) {
+					fannedOut = true
+					return 1, nil
+				},
+				ClaimDueEventDeliveriesFunc: func(ctx context.Context, arg database.ClaimDueEventDeliveriesParams) ([]database.ClaimDueEventDeliveriesRow, error) {
+					return []database.ClaimDueEventDeliveriesRow{d}, nil
+				},
+				RecordEventDeliveryAttemptFunc: func(ctx context.Context, arg database.RecordEventDeliveryAttemptParams) error {
+					recorded = append(recorded, arg)
+					return nil
+				},
+			}
+
+			var sent models.Event
+			mockApp := &handlers.AppServer{
+				Db:     mockDb,
+				Config: &config.Config{},
+				Logger: kitlog.NewNopLogger(),
+				Encryptor: &mockEncryptor{
+					DecryptAccessTokenFunc: func(ciphertext, keyString string) ([]byte, error) {
+						return []byte("secret"), nil
+					},
+				},
+				Webhooks: &mockWebhookSender{
+					PostFunc: func(ctx context.Context, url string, secret []byte, deliveryID string, body []byte) error {
+						assert.Equal(t, d.Url, url)
+						assert.Equal(t, "secret", string(secret))
+						assert.Equal(t, testEventDeliveryID.String(), deliveryID)
+						assert.NoError(t, json.Unmarshal(body, &sent))
+						return tt.postErr
+					},
+				},
+			}
+
+			mockApp.DispatchEvents(context.Background())
+
+			assert.True(t, fannedOut)
+			assert.Equal(t, testEventID, sent.ID)
+			assert.Equal(t, int64(42), sent.Seq)
+			assert.JSONEq(t, string(d.Payload), string(sent.Data))
+			if assert.Len(t, recorded, 1) {
+				assert.Equal(t, tt.expectedStatus, recorded[0].Status)
+				assert.Equal(t, tt.postErr != nil, recorded[0].LastError.Valid)
+				assert.Equal(t, tt.expectedRetry, recorded[0].NextAttemptAt.After(time.Now().Add(30*time.Second)))
+			}
+		})
+	}
+}
+
+func TestHandlerPlaidWebhookRecordsItemError(t *testing.T) {
+	var recorded []database.CreateEventParams
+
+	mockDb := &mockDatabaseService{
+		GetItemByIDFunc: func(ctx context.Context, id string) (database.PlaidItem, error) {
+			return database.PlaidItem{ID: id, UserID: testUserID}, nil
+		},
+		CreatePlaidWebhookRecordFunc: func(ctx context.Context, arg database.CreatePlaidWebhookRecordParams) (database.PlaidWebhookRecord, error) {
+			return database.PlaidWebhookRecord{}, nil
+		},
+		CreateEventFunc: func(ctx context.Context, arg database.CreateEventParams) error {
+			recorded = append(recorded, arg)
+			return nil
+		},
+	}
+
+	body := `{"webhook_type":"ITEM", "webhook_code":"ERROR", "item_id":"12345", "error":{"error_code":"ITEM_LOGIN_REQUIRED"}}`
+	req := httptest.NewRequest("POST", "/api/plaid-webhook", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+
+	mockApp := &handlers.AppServer{
+		Db: mockDb,
+		Auth: &mockAuthService{
+			VerifyPlaidJWTFunc: func(p auth.PlaidKeyFetcher, ctx context.Context, tokenString string) error {
+				return nil
+			},
+		},
+		Logger: kitlog.NewNopLogger(),
+	}
+
+	mockApp.HandlerPlaidWebhook(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	if assert.Len(t, recorded, 1) {
+		assert.Equal(t, events.ItemError, recorded[0].Type)
+		assert.Equal(t, testUserID, recorded[0].UserID)
+		assert.JSONEq(t, `{"item_id":"12345","webhook_code":"ERROR","error_code":"ITEM_LOGIN_REQUIRED"}`, string(recorded[0].Payload))
+	}
+}
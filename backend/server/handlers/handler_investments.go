@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Syncs an item's investment holdings, and the securities they refer to, along with the last two
+// years of investment transactions. Data for accounts without a record is skipped
+func (app *AppServer) HandlerSyncInvestments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tokenValue := ctx.Value(accessTokenKey)
+	accessToken, ok := tokenValue.(string)
+	if !ok {
+		app.respondWithError(w, 400, "Bad access token in context", nil)
+		return
+	}
+
+	itemID := chi.URLParam(r, "item-id")
+
+	holdingsResp, reqID, err := app.PService.GetInvestmentHoldings(ctx, accessToken)
+	if err != nil {
+		app.recordItemPlaidError(ctx, itemID, err)
+		app.respondWithPlaidProductError(w, investmentsProduct, "Service error", fmt.Errorf("plaid request id: %s, error getting investment holdings: %w", reqID, err))
+		return
+	}
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -investmentHistoryDays)
+	txns, txnSecurities, reqID, err := app.PService.GetInvestmentTransactions(ctx, accessToken, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		app.recordItemPlaidError(ctx, itemID, err)
+		app.respondWithPlaidProductError(w, investmentsProduct, "Service error", fmt.Errorf("plaid request id: %s, error getting investment transactions: %w", reqID, err))
+		return
+	}
+
+	accounts, err := app.Db.GetAccountsForItem(ctx, itemID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting item accounts: %w", err))
+		return
+	}
+	known := make(map[string]bool, len(accounts))
+	for _, acc := range accounts {
+		known[acc.ID] = true
+	}
+
+	// Both responses list the securities they refer to, so the same security often appears twice
+	seen := make(map[string]bool)
+	var securities []plaid.Security
+	for _, security := range append(holdingsResp.GetSecurities(), txnSecurities...) {
+		if seen[security.SecurityId] {
+			continue
+		}
+		seen[security.SecurityId] = true
+		securities = append(securities, security)
+	}
+
+	var holdings []plaid.Holding
+	for _, holding := range holdingsResp.GetHoldings() {
+		if known[holding.AccountId] {
+			holdings = append(holdings, holding)
+		}
+	}
+
+	var accTxns []plaid.InvestmentTransaction
+	for _, txn := range txns {
+		if known[txn.AccountId] {
+			accTxns = append(accTxns, txn)
+		}
+	}
+
+	err = app.TxnUpdater.ApplyInvestmentUpdates(ctx, itemID, securities, holdings, accTxns)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error completing database txn on investment data: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, models.InvestmentSync{
+		Securities:   len(securities),
+		Holdings:     len(holdings),
+		Transactions: len(accTxns),
+	})
+}
+
+// Returns an account's holdings with their cost basis and unrealized gain
+func (app *AppServer) HandlerGetHoldings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	accValue := ctx.Value(accountKey)
+	acc, ok := accValue.(database.Account)
+	if !ok {
+		app.respondWithError(w, 400, "Bad account in context", nil)
+		return
+	}
+
+	rows, err := app.Db.GetHoldingsForAccount(ctx, acc.ID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting holdings: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, holdingsResponse(acc.ID, rows))
+}
+
+// Returns an account's investment transactions, most recent first
+func (app *AppServer) HandlerGetInvestmentTransactions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	accValue := ctx.Value(accountKey)
+	acc, ok := accValue.(database.Account)
+	if !ok {
+		app.respondWithError(w, 400, "Bad account in context", nil)
+		return
+	}
+
+	rows, err := app.Db.GetInvestmentTransactionsForAccount(ctx, acc.ID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting investment transactions: %w", err))
+		return
+	}
+
+	response := []models.InvestmentTransaction{}
+	for _, row := range rows {
+		response = append(response, models.InvestmentTransaction{
+			ID:              row.ID,
+			AccountID:       row.AccountID,
+			SecurityID:      row.SecurityID.String,
+			TickerSymbol:    row.TickerSymbol.String,
+			Date:            row.Date,
+			Name:            row.Name,
+			Type:            row.Type,
+			Subtype:         row.Subtype,
+			Quantity:        row.Quantity,
+			Price:           row.Price,
+			Amount:          row.Amount,
+			Fees:            row.Fees.String,
+			IsoCurrencyCode: row.IsoCurrencyCode.String,
+		})
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
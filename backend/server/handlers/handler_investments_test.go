@@ -0,0 +1,368 @@
+package handlers_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/jms-guy/greed/models"
+	"github.com/plaid/plaid-go/v36/plaid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecurityID = "sec-1"
+
+func TestHandlerSyncInvestments(t *testing.T) {
+	holdingsResp := plaid.InvestmentsHoldingsGetResponse{
+		Holdings: []plaid.Holding{
+			{AccountId: testAccountID, SecurityId: testSecurityID, Quantity: 10, InstitutionPrice: 12.5, InstitutionValue: 125},
+			{AccountId: "unknown", SecurityId: testSecurityID, Quantity: 1, InstitutionPrice: 12.5, InstitutionValue: 12.5},
+		},
+		Securities: []plaid.Security{{SecurityId: testSecurityID}},
+	}
+	txns := []plaid.InvestmentTransaction{
+		{InvestmentTransactionId: "itxn-1", AccountId: testAccountID, Date: "2025-01-02", Type: plaid.INVESTMENTTRANSACTIONTYPE_BUY},
+		{InvestmentTransactionId: "itxn-2", AccountId: "unknown", Date: "2025-01-02", Type: plaid.INVESTMENTTRANSACTIONTYPE_BUY},
+	}
+
+	tests := []struct {
+		name           string
+		tokenInContext any
+		mockDb         *mockDatabaseService
+		mockPS         *mockPlaidService
+		mockTxnUpdater *mockTxnUpdaterService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "should store investment data for the item's known accounts",
+			tokenInContext: testAccessToken,
+			mockDb: &mockDatabaseService{
+				GetAccountsForItemFunc: func(ctx context.Context, itemID string) ([]database.Account, error) {
+					return []database.Account{testAccount}, nil
+				},
+			},
+			mockPS: &mockPlaidService{
+				GetInvestmentHoldingsFunc: func(ctx context.Context, accessToken string) (plaid.InvestmentsHoldingsGetResponse, string, error) {
+					return holdingsResp, "requestID", nil
+				},
+				GetInvestmentTransactionsFunc: func(ctx context.Context, accessToken, startDate, endDate string) ([]plaid.InvestmentTransaction, []plaid.Security, string, error) {
+					return txns, []plaid.Security{{SecurityId: testSecurityID}}, "requestID", nil
+				},
+			},
+			mockTxnUpdater: &mockTxnUpdaterService{
+				ApplyInvestmentUpdatesFunc: func(ctx context.Context, itemID string, securities []plaid.Security, holdings []plaid.Holding, txns []plaid.InvestmentTransaction) error {
+					assert.Equal(t, testItemID, itemID)
+					assert.Len(t, securities, 1)
+					assert.Len(t, holdings, 1)
+					assert.Len(t, txns, 1)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"securities":1,"holdings":1,"transactions":1}`,
+		},
+		{
+			name:           "should err with bad access token in context",
+			tokenInContext: nil,
+			mockPS: &mockPlaidService{
+				GetInvestmentHoldingsFunc: func(ctx context.Context, accessToken string) (plaid.InvestmentsHoldingsGetResponse, string, error) {
+					t.Fatalf("should not be called on err")
+					return plaid.InvestmentsHoldingsGetResponse{}, "", nil
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad access token in context",
+		},
+		{
+			name:           "should err on getting holdings",
+			tokenInContext: testAccessToken,
+			mockPS: &mockPlaidService{
+				GetInvestmentHoldingsFunc: func(ctx context.Context, accessToken string) (plaid.InvestmentsHoldingsGetResponse, string, error) {
+					return plaid.InvestmentsHoldingsGetResponse{}, "requestID", fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Service error",
+		},
+		{
+			name:           "should err on getting investment transactions",
+			tokenInContext: testAccessToken,
+			mockPS: &mockPlaidService{
+				GetInvestmentTransactionsFunc: func(ctx context.Context, accessToken, startDate, endDate string) ([]plaid.InvestmentTransaction, []plaid.Security, string, error) {
+					return nil, nil, "requestID", fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Service error",
+		},
+		{
+			name:           "should err on getting item accounts",
+			tokenInContext: testAccessToken,
+			mockDb: &mockDatabaseService{
+				GetAccountsForItemFunc: func(ctx context.Context, itemID string) ([]database.Account, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			mockPS:         &mockPlaidService{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+		{
+			name:           "should err on storing investment data",
+			tokenInContext: testAccessToken,
+			mockDb:         &mockDatabaseService{},
+			mockPS:         &mockPlaidService{},
+			mockTxnUpdater: &mockTxnUpdaterService{
+				ApplyInvestmentUpdatesFunc: func(ctx context.Context, itemID string, securities []plaid.Security, holdings []plaid.Holding, txns []plaid.InvestmentTransaction) error {
+					return fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/items/%s/access/investments", testItemID), nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("item-id", testItemID)
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetAccessTokenKey(), tt.tokenInContext)
+
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:         tt.mockDb,
+				PService:   tt.mockPS,
+				Logger:     kitlog.NewNopLogger(),
+				TxnUpdater: tt.mockTxnUpdater,
+			}
+
+			mockApp.HandlerSyncInvestments(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerGetHoldings(t *testing.T) {
+	updated := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		accountInContext any
+		mockDb           *mockDatabaseService
+		expectedStatus   int
+		expectedBody     string
+		expected         *models.Holdings
+	}{
+		{
+			name:             "should return holdings with unrealized gains",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetHoldingsForAccountFunc: func(ctx context.Context, accountID string) ([]database.GetHoldingsForAccountRow, error) {
+					return []database.GetHoldingsForAccountRow{
+						{
+							AccountID:        testAccountID,
+							SecurityID:       testSecurityID,
+							TickerSymbol:     sql.NullString{String: "VFV", Valid: true},
+							Quantity:         "10.00000000",
+							InstitutionPrice: "125.500000",
+							InstitutionValue: "1255.00",
+							CostBasis:        sql.NullString{String: "1000.00", Valid: true},
+							UpdatedAt:        updated,
+						},
+						{
+							AccountID:        testAccountID,
+							SecurityID:       "sec-2",
+							Quantity:         "100.00000000",
+							InstitutionPrice: "1.000000",
+							InstitutionValue: "100.00",
+							UpdatedAt:        updated,
+						},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expected: &models.Holdings{
+				AccountID: testAccountID,
+				Holdings: []models.Holding{
+					{
+						SecurityID:     testSecurityID,
+						TickerSymbol:   "VFV",
+						Quantity:       "10.00000000",
+						Price:          "125.500000",
+						Value:          "1255.00",
+						CostBasis:      "1000.00",
+						UnrealizedGain: "255.00",
+						UpdatedAt:      updated,
+					},
+					{
+						SecurityID: "sec-2",
+						Quantity:   "100.00000000",
+						Price:      "1.000000",
+						Value:      "100.00",
+						UpdatedAt:  updated,
+					},
+				},
+				TotalValue:     "1355.00",
+				TotalCostBasis: "1000.00",
+				UnrealizedGain: "255.00",
+			},
+		},
+		{
+			name:             "should return empty holdings for account without any",
+			accountInContext: testAccount,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     `"holdings":[]`,
+		},
+		{
+			name:             "should err with bad account in context",
+			accountInContext: nil,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Bad account in context",
+		},
+		{
+			name:             "should err on getting holdings",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetHoldingsForAccountFunc: func(ctx context.Context, accountID string) ([]database.GetHoldingsForAccountRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/accounts/%s/holdings", testAccountID), nil)
+			ctx := context.WithValue(req.Context(), handlers.GetAccountKey(), tt.accountInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetHoldings(rr, req)
+
+			// --- Assertions ---
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			if tt.expected != nil {
+				var got models.Holdings
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+				assert.Equal(t, *tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHandlerGetInvestmentTransactions(t *testing.T) {
+	tests := []struct {
+		name             string
+		accountInContext any
+		mockDb           *mockDatabaseService
+		expectedStatus   int
+		expectedBody     string
+	}{
+		{
+			name:             "should return investment transactions for account",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetInvestmentTransactionsForAccountFunc: func(ctx context.Context, accountID string) ([]database.GetInvestmentTransactionsForAccountRow, error) {
+					return []database.GetInvestmentTransactionsForAccountRow{
+						{
+							ID:           "itxn-1",
+							AccountID:    testAccountID,
+							SecurityID:   sql.NullString{String: testSecurityID, Valid: true},
+							TickerSymbol: sql.NullString{String: "VFV", Valid: true},
+							Name:         "BUY VFV",
+							Type:         "buy",
+							Subtype:      "buy",
+							Quantity:     "10.00000000",
+							Price:        "125.500000",
+							Amount:       "1255.00",
+						},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"ticker_symbol":"VFV"`,
+		},
+		{
+			name:             "should return empty list for account without any",
+			accountInContext: testAccount,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "[]",
+		},
+		{
+			name:             "should err with bad account in context",
+			accountInContext: nil,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Bad account in context",
+		},
+		{
+			name:             "should err on getting investment transactions",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetInvestmentTransactionsForAccountFunc: func(ctx context.Context, accountID string) ([]database.GetInvestmentTransactionsForAccountRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/accounts/%s/investments/transactions", testAccountID), nil)
+			ctx := context.WithValue(req.Context(), handlers.GetAccountKey(), tt.accountInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetInvestmentTransactions(rr, req)
+
+			// --- Assertions ---
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
+	}
+}
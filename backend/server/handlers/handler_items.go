@@ -13,7 +13,8 @@ import (
 	"github.com/jms-guy/greed/models"
 )
 
-// Grabs item records for a user from database, returning names and item IDs
+// Grabs item records for a user from database, returning names and item IDs along with each item's health:
+// when it last synced, its last error and when the user's consent for it runs out
 func (app *AppServer) HandlerGetItems(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -32,20 +33,44 @@ func (app *AppServer) HandlerGetItems(w http.ResponseWriter, r *http.Request) {
 		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting item records: %w", err))
 		return
 	}
-	response := struct {
-		Items []models.ItemName `json:"items"`
-	}{}
+
+	records, err := app.Db.GetItemStatusesForUser(ctx, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting item statuses: %w", err))
+		return
+	}
+	statuses := make(map[string]database.ItemStatus, len(records))
+	for _, s := range records {
+		statuses[s.ItemID] = s
+	}
+
+	response := models.Items{}
+	now := time.Now()
 
 	for _, item := range items {
 		nickname := ""
 		if item.Nickname.Valid {
 			nickname = item.Nickname.String
 		}
-		response.Items = append(response.Items, models.ItemName{
+		status, found := statuses[item.ID]
+
+		result := models.ItemName{
 			ItemId:          item.ID,
 			Nickname:        nickname,
 			InstitutionName: item.InstitutionName,
-		})
+			Health:          itemHealth(status, found, now),
+			LastErrorCode:   status.LastErrorCode.String,
+		}
+		if status.LastSyncedAt.Valid {
+			result.LastSyncedAt = &status.LastSyncedAt.Time
+		}
+		if status.LastErrorAt.Valid {
+			result.LastErrorAt = &status.LastErrorAt.Time
+		}
+		if status.ConsentExpiresAt.Valid {
+			result.ConsentExpiresAt = &status.ConsentExpiresAt.Time
+		}
+		response.Items = append(response.Items, result)
 	}
 	app.respondWithJSON(w, 200, response)
 }
@@ -117,7 +142,7 @@ func (app *AppServer) HandlerDeleteItem(w http.ResponseWriter, r *http.Request)
 
 	err = app.PService.RemoveItem(ctx, accessToken)
 	if err != nil {
-		app.respondWithError(w, 500, "Service error", fmt.Errorf("error removing item from plaid databases: %w", err))
+		app.respondWithPlaidError(w, "Service error", fmt.Errorf("error removing item from plaid databases: %w", err))
 	}
 
 	app.respondWithJSON(w, 200, "Item deleted successfully")
@@ -139,10 +164,23 @@ func (app *AppServer) HandlerGetAccountsForItem(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	overrides := map[string]database.AccountOverride{}
+	if userID, ok := ctx.Value(userIDKey).(uuid.UUID); ok {
+		records, err := app.Db.GetAccountOverridesForUser(ctx, userID)
+		if err != nil {
+			app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting account overrides: %w", err))
+			return
+		}
+		for _, o := range records {
+			overrides[o.AccountID] = o
+		}
+	}
+
 	// Return slice of account structs
 	var accounts []models.Account
 	for _, account := range accs {
-		result := models.Account{
+		override, found := overrides[account.ID]
+		result := withAccountOverride(models.Account{
 			Id:               account.ID,
 			CreatedAt:        account.CreatedAt,
 			UpdatedAt:        account.UpdatedAt,
@@ -154,9 +192,12 @@ func (app *AppServer) HandlerGetAccountsForItem(w http.ResponseWriter, r *http.R
 			AvailableBalance: account.AvailableBalance.String,
 			CurrentBalance:   account.CurrentBalance.String,
 			IsoCurrencyCode:  account.IsoCurrencyCode.String,
-		}
+			ItemId:           account.ItemID.String,
+			Manual:           account.IsManual,
+		}, override, found)
 		accounts = append(accounts, result)
 	}
+	sortAccounts(accounts)
 
 	app.respondWithJSON(w, 200, accounts)
 }
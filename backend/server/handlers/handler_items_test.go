@@ -39,6 +39,67 @@ func TestHandlerGetItems(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   testItemName,
 		},
+		{
+			name:            "should report item needing re-authentication",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetItemsByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.PlaidItem, error) {
+					return []database.PlaidItem{{ID: testItemID, InstitutionName: testItemName}}, nil
+				},
+				GetItemStatusesForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.ItemStatus, error) {
+					return []database.ItemStatus{{
+						ItemID:        testItemID,
+						LastSyncedAt:  sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+						LastErrorCode: sql.NullString{String: "ITEM_LOGIN_REQUIRED", Valid: true},
+					}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"health":"needs_reauth","last_synced_at"`,
+		},
+		{
+			name:            "should report item with consent running out",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetItemsByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.PlaidItem, error) {
+					return []database.PlaidItem{{ID: testItemID, InstitutionName: testItemName}}, nil
+				},
+				GetItemStatusesForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.ItemStatus, error) {
+					return []database.ItemStatus{{
+						ItemID:           testItemID,
+						LastSyncedAt:     sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+						ConsentExpiresAt: sql.NullTime{Time: time.Now().Add(48 * time.Hour), Valid: true},
+					}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"health":"expiring"`,
+		},
+		{
+			name:            "should report item never synced as unknown",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetItemsByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.PlaidItem, error) {
+					return []database.PlaidItem{{ID: testItemID, InstitutionName: testItemName}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"health":"unknown"`,
+		},
+		{
+			name:            "should err on getting item statuses",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetItemsByUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.PlaidItem, error) {
+					return []database.PlaidItem{{ID: testItemID, InstitutionName: testItemName}}, nil
+				},
+				GetItemStatusesForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.ItemStatus, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
 		{
 			name:            "should err with bad userID in context",
 			userIDInContext: uuid.Nil,
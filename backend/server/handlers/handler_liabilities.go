@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Syncs an item's credit card, student loan and mortgage details. Liabilities for accounts without a
+// record are skipped
+func (app *AppServer) HandlerSyncLiabilities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tokenValue := ctx.Value(accessTokenKey)
+	accessToken, ok := tokenValue.(string)
+	if !ok {
+		app.respondWithError(w, 400, "Bad access token in context", nil)
+		return
+	}
+
+	itemID := chi.URLParam(r, "item-id")
+
+	liabilities, reqID, err := app.PService.GetLiabilities(ctx, accessToken)
+	if err != nil {
+		app.recordItemPlaidError(ctx, itemID, err)
+		app.respondWithPlaidProductError(w, liabilitiesProduct, "Service error", fmt.Errorf("plaid request id: %s, error getting liabilities: %w", reqID, err))
+		return
+	}
+
+	accounts, err := app.Db.GetAccountsForItem(ctx, itemID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting item accounts: %w", err))
+		return
+	}
+	known := make(map[string]bool, len(accounts))
+	for _, acc := range accounts {
+		known[acc.ID] = true
+	}
+
+	stored := plaid.LiabilitiesObject{}
+	for _, credit := range liabilities.Credit {
+		if id := credit.AccountId.Get(); id != nil && known[*id] {
+			stored.Credit = append(stored.Credit, credit)
+		}
+	}
+	for _, loan := range liabilities.Student {
+		if id := loan.AccountId.Get(); id != nil && known[*id] {
+			stored.Student = append(stored.Student, loan)
+		}
+	}
+	for _, mortgage := range liabilities.Mortgage {
+		if known[mortgage.AccountId] {
+			stored.Mortgage = append(stored.Mortgage, mortgage)
+		}
+	}
+
+	err = app.TxnUpdater.ApplyLiabilityUpdates(ctx, itemID, stored)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error completing database txn on liability data: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, models.LiabilitySync{
+		Credit:   len(stored.Credit),
+		Student:  len(stored.Student),
+		Mortgage: len(stored.Mortgage),
+	})
+}
+
+// Returns an account's APRs, payments and loan terms
+func (app *AppServer) HandlerGetLiability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	accValue := ctx.Value(accountKey)
+	acc, ok := accValue.(database.Account)
+	if !ok {
+		app.respondWithError(w, 400, "Bad account in context", nil)
+		return
+	}
+
+	liability, err := app.Db.GetLiabilityForAccount(ctx, acc.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			app.respondWithError(w, 404, "No liability data found for account", nil)
+			return
+		}
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting liability: %w", err))
+		return
+	}
+
+	aprs, err := app.Db.GetLiabilityAPRsForAccount(ctx, acc.ID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting liability APRs: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, liabilityResponse(acc, liability, aprs))
+}
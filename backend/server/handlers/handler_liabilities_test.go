@@ -0,0 +1,274 @@
+package handlers_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/jms-guy/greed/models"
+	"github.com/plaid/plaid-go/v36/plaid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerSyncLiabilities(t *testing.T) {
+	liabilities := plaid.LiabilitiesObject{
+		Credit: []plaid.CreditCardLiability{
+			{AccountId: *plaid.NewNullableString(plaid.PtrString(testAccountID))},
+			{AccountId: *plaid.NewNullableString(plaid.PtrString("unknown"))},
+		},
+		Student: []plaid.StudentLoan{
+			{AccountId: *plaid.NewNullableString(nil)},
+		},
+		Mortgage: []plaid.MortgageLiability{
+			{AccountId: "unknown"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		tokenInContext any
+		mockDb         *mockDatabaseService
+		mockPS         *mockPlaidService
+		mockTxnUpdater *mockTxnUpdaterService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "should store liabilities for the item's known accounts",
+			tokenInContext: testAccessToken,
+			mockDb: &mockDatabaseService{
+				GetAccountsForItemFunc: func(ctx context.Context, itemID string) ([]database.Account, error) {
+					return []database.Account{testAccount}, nil
+				},
+			},
+			mockPS: &mockPlaidService{
+				GetLiabilitiesFunc: func(ctx context.Context, accessToken string) (plaid.LiabilitiesObject, string, error) {
+					return liabilities, "requestID", nil
+				},
+			},
+			mockTxnUpdater: &mockTxnUpdaterService{
+				ApplyLiabilityUpdatesFunc: func(ctx context.Context, itemID string, stored plaid.LiabilitiesObject) error {
+					assert.Equal(t, testItemID, itemID)
+					assert.Len(t, stored.Credit, 1)
+					assert.Empty(t, stored.Student)
+					assert.Empty(t, stored.Mortgage)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"credit":1,"student":0,"mortgage":0}`,
+		},
+		{
+			name:           "should err with bad access token in context",
+			tokenInContext: nil,
+			mockPS: &mockPlaidService{
+				GetLiabilitiesFunc: func(ctx context.Context, accessToken string) (plaid.LiabilitiesObject, string, error) {
+					t.Fatalf("should not be called on err")
+					return plaid.LiabilitiesObject{}, "", nil
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad access token in context",
+		},
+		{
+			name:           "should err on getting liabilities",
+			tokenInContext: testAccessToken,
+			mockPS: &mockPlaidService{
+				GetLiabilitiesFunc: func(ctx context.Context, accessToken string) (plaid.LiabilitiesObject, string, error) {
+					return plaid.LiabilitiesObject{}, "requestID", fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Service error",
+		},
+		{
+			name:           "should err on getting item accounts",
+			tokenInContext: testAccessToken,
+			mockDb: &mockDatabaseService{
+				GetAccountsForItemFunc: func(ctx context.Context, itemID string) ([]database.Account, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			mockPS:         &mockPlaidService{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+		{
+			name:           "should err on storing liability data",
+			tokenInContext: testAccessToken,
+			mockDb:         &mockDatabaseService{},
+			mockPS:         &mockPlaidService{},
+			mockTxnUpdater: &mockTxnUpdaterService{
+				ApplyLiabilityUpdatesFunc: func(ctx context.Context, itemID string, stored plaid.LiabilitiesObject) error {
+					return fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/items/%s/access/liabilities", testItemID), nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("item-id", testItemID)
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetAccessTokenKey(), tt.tokenInContext)
+
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:         tt.mockDb,
+				PService:   tt.mockPS,
+				Logger:     kitlog.NewNopLogger(),
+				TxnUpdater: tt.mockTxnUpdater,
+			}
+
+			mockApp.HandlerSyncLiabilities(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerGetLiability(t *testing.T) {
+	updated := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	acc := testAccount
+	acc.CurrentBalance = sql.NullString{String: "1250.00", Valid: true}
+
+	tests := []struct {
+		name             string
+		accountInContext any
+		mockDb           *mockDatabaseService
+		expectedStatus   int
+		expectedBody     string
+		expected         *models.Liability
+	}{
+		{
+			name:             "should return liability with APRs",
+			accountInContext: acc,
+			mockDb: &mockDatabaseService{
+				GetLiabilityForAccountFunc: func(ctx context.Context, accountID string) (database.Liability, error) {
+					return database.Liability{
+						AccountID:          testAccountID,
+						Type:               "credit",
+						InterestRate:       sql.NullString{String: "19.9900", Valid: true},
+						InterestRateType:   sql.NullString{String: "purchase_apr", Valid: true},
+						MinimumPayment:     sql.NullString{String: "25.00", Valid: true},
+						NextPaymentDueDate: sql.NullTime{Time: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC), Valid: true},
+						IsOverdue:          sql.NullBool{Bool: false, Valid: true},
+						UpdatedAt:          updated,
+					}, nil
+				},
+				GetLiabilityAPRsForAccountFunc: func(ctx context.Context, accountID string) ([]database.LiabilityApr, error) {
+					return []database.LiabilityApr{
+						{AccountID: testAccountID, AprType: "cash_apr", AprPercentage: "22.9900"},
+						{AccountID: testAccountID, AprType: "purchase_apr", AprPercentage: "19.9900", BalanceSubjectToApr: sql.NullString{String: "1250.00", Valid: true}},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expected: &models.Liability{
+				AccountID:          testAccountID,
+				Type:               "credit",
+				Balance:            "1250.00",
+				InterestRate:       "19.9900",
+				InterestRateType:   "purchase_apr",
+				MinimumPayment:     "25.00",
+				NextPaymentDueDate: "2025-02-15",
+				APRs: []models.LiabilityAPR{
+					{Type: "cash_apr", Percentage: "22.9900"},
+					{Type: "purchase_apr", Percentage: "19.9900", BalanceSubjectToAPR: "1250.00"},
+				},
+				UpdatedAt: updated,
+			},
+		},
+		{
+			name:             "should err with bad account in context",
+			accountInContext: nil,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Bad account in context",
+		},
+		{
+			name:             "should err on account without liability data",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetLiabilityForAccountFunc: func(ctx context.Context, accountID string) (database.Liability, error) {
+					return database.Liability{}, sql.ErrNoRows
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "No liability data found for account",
+		},
+		{
+			name:             "should err on getting liability",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetLiabilityForAccountFunc: func(ctx context.Context, accountID string) (database.Liability, error) {
+					return database.Liability{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+		{
+			name:             "should err on getting APRs",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetLiabilityAPRsForAccountFunc: func(ctx context.Context, accountID string) ([]database.LiabilityApr, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("/api/accounts/%s/liabilities", testAccountID), nil)
+			ctx := context.WithValue(req.Context(), handlers.GetAccountKey(), tt.accountInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetLiability(rr, req)
+
+			// --- Assertions ---
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+			if tt.expected != nil {
+				var got models.Liability
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+				assert.Equal(t, *tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/utils"
+	"github.com/jms-guy/greed/models"
+)
+
+// Account types a manual account can have, matching Plaid's account types
+var manualAccountTypes = []string{"depository", "credit", "loan", "investment", "other"}
+
+// Payment channels a manual transaction can have, matching Plaid's payment channels
+var manualPaymentChannels = []string{"online", "in store", "other"}
+
+// Longest name accepted for a manual account or merchant
+const maxManualNameLength = 100
+
+// Creates an account tracked by hand, with no Plaid item. Its balance starts at the given opening
+// balance, and follows the transactions entered on it
+func (app *AppServer) HandlerCreateManualAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	request := models.CreateManualAccount{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		app.respondWithError(w, 400, "Name is required", nil)
+		return
+	}
+	if utf8.RuneCountInString(name) > maxManualNameLength {
+		app.respondWithError(w, 400, fmt.Sprintf("Name must be at most %d characters", maxManualNameLength), nil)
+		return
+	}
+
+	accType := strings.ToLower(strings.TrimSpace(request.Type))
+	if accType == "" {
+		accType = "depository"
+	}
+	if !slices.Contains(manualAccountTypes, accType) {
+		app.respondWithError(w, 400, fmt.Sprintf("Type must be one of: %s", strings.Join(manualAccountTypes, ", ")), nil)
+		return
+	}
+
+	balance := request.Balance
+	if balance == "" {
+		balance = "0.00"
+	}
+	if !utils.ValidMoneyString(balance) {
+		app.respondWithError(w, 400, "Balance must be an amount such as 120.50", nil)
+		return
+	}
+
+	acc, err := app.Db.CreateManualAccount(ctx, database.CreateManualAccountParams{
+		ID:              uuid.NewString(),
+		Name:            name,
+		Type:            accType,
+		Subtype:         utils.CreateTextNullString(strings.TrimSpace(request.Subtype)),
+		IsoCurrencyCode: utils.CreateTextNullString(strings.ToUpper(strings.TrimSpace(request.IsoCurrencyCode))),
+		OpeningBalance:  sql.NullString{String: balance, Valid: true},
+		UserID:          id,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error creating manual account: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 201, withAccountOverride(models.Account{
+		Id:               acc.ID,
+		CreatedAt:        acc.CreatedAt,
+		UpdatedAt:        acc.UpdatedAt,
+		Name:             acc.Name,
+		Type:             acc.Type,
+		Subtype:          acc.Subtype.String,
+		AvailableBalance: acc.AvailableBalance.String,
+		CurrentBalance:   acc.CurrentBalance.String,
+		IsoCurrencyCode:  acc.IsoCurrencyCode.String,
+		Manual:           acc.IsManual,
+	}, database.AccountOverride{}, false))
+}
+
+// Adds a transaction to a manual account
+func (app *AppServer) HandlerCreateManualTransaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	acc, userID, ok := app.manualAccountFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	request := models.CreateManualTransaction{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	params := database.CreateTransactionParams{
+		ID:                      uuid.NewString(),
+		AccountID:               acc.ID,
+		IsoCurrencyCode:         acc.IsoCurrencyCode,
+		Date:                    sql.NullTime{Time: time.Now().UTC().Truncate(24 * time.Hour), Valid: true},
+		PaymentChannel:          "other",
+		PersonalFinanceCategory: "OTHER",
+	}
+	if request.IsoCurrencyCode != "" {
+		params.IsoCurrencyCode = utils.CreateTextNullString(strings.ToUpper(strings.TrimSpace(request.IsoCurrencyCode)))
+	}
+
+	fields := manualTransactionFields{
+		Amount:         &request.Amount,
+		Date:           &request.Date,
+		MerchantName:   &request.MerchantName,
+		Category:       &request.Category,
+		PaymentChannel: &request.PaymentChannel,
+	}
+	if request.Date == "" {
+		fields.Date = nil
+	}
+	if request.Category == "" {
+		fields.Category = nil
+	}
+	if request.PaymentChannel == "" {
+		fields.PaymentChannel = nil
+	}
+
+	update := database.UpdateManualTransactionParams(params)
+	if msg := fields.apply(&update); msg != "" {
+		app.respondWithError(w, 400, msg, nil)
+		return
+	}
+	if err := app.normalizeManualTransaction(ctx, userID, &update); err != nil {
+		app.respondWithError(w, 500, "Database error", err)
+		return
+	}
+
+	txn, err := app.TxnUpdater.CreateManualTransaction(ctx, userID, database.CreateTransactionParams(update))
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error creating manual transaction: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 201, manualTransactionResponse(txn))
+}
+
+// Changes a manual account's transaction. Fields left out of the request are kept
+func (app *AppServer) HandlerUpdateManualTransaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, userID, ok := app.manualAccountFromContext(w, r)
+	if !ok {
+		return
+	}
+	txn, ok := ctx.Value(transactionKey).(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	request := models.UpdateManualTransaction{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	params := database.UpdateManualTransactionParams{
+		ID:                      txn.ID,
+		AccountID:               txn.AccountID,
+		Amount:                  txn.Amount,
+		IsoCurrencyCode:         txn.IsoCurrencyCode,
+		Date:                    txn.Date,
+		MerchantName:            txn.MerchantName,
+		PaymentChannel:          txn.PaymentChannel,
+		PersonalFinanceCategory: txn.PersonalFinanceCategory,
+	}
+	fields := manualTransactionFields(request)
+	if msg := fields.apply(&params); msg != "" {
+		app.respondWithError(w, 400, msg, nil)
+		return
+	}
+	if err := app.normalizeManualTransaction(ctx, userID, &params); err != nil {
+		app.respondWithError(w, 500, "Database error", err)
+		return
+	}
+
+	updated, err := app.TxnUpdater.UpdateManualTransaction(ctx, userID, params)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error updating manual transaction: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, manualTransactionResponse(updated))
+}
+
+// Deletes a manual account's transaction
+func (app *AppServer) HandlerDeleteManualTransaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, userID, ok := app.manualAccountFromContext(w, r)
+	if !ok {
+		return
+	}
+	txn, ok := ctx.Value(transactionKey).(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	if err := app.TxnUpdater.DeleteManualTransaction(ctx, userID, txn); err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error deleting manual transaction: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, "Transaction deleted successfully")
+}
+
+// Gets the account and user from the request context, responding with an error if the account is linked
+// through Plaid, as its transactions come from the institution and would be overwritten by the next sync
+func (app *AppServer) manualAccountFromContext(w http.ResponseWriter, r *http.Request) (database.Account, uuid.UUID, bool) {
+	ctx := r.Context()
+	acc, ok := ctx.Value(accountKey).(database.Account)
+	if !ok {
+		app.respondWithError(w, 400, "Bad account in context", nil)
+		return database.Account{}, uuid.Nil, false
+	}
+	userID, ok := ctx.Value(userIDKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return database.Account{}, uuid.Nil, false
+	}
+
+	if !acc.IsManual {
+		app.respondWithErrorCode(w, 400, models.ErrCodeNotManualAccount, "Transactions can only be entered by hand on manual accounts", nil)
+		return database.Account{}, uuid.Nil, false
+	}
+
+	return acc, userID, true
+}
+
+// Transaction fields given in a request, nil fields being left as they are
+type manualTransactionFields struct {
+	Amount         *string
+	Date           *string
+	MerchantName   *string
+	Category       *string
+	PaymentChannel *string
+}
+
+// Validates the given fields and sets them on params, returning a message describing the first invalid
+// field, or an empty string if all are valid
+func (f manualTransactionFields) apply(params *database.UpdateManualTransactionParams) string {
+	if f.Amount != nil {
+		amount := strings.TrimSpace(*f.Amount)
+		if !utils.ValidMoneyString(amount) {
+			return "Amount must be an amount such as 12.50, negative for money coming into the account"
+		}
+		params.Amount = amount
+	}
+
+	if f.Date != nil {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(*f.Date))
+		if err != nil {
+			return "Date must be in the format YYYY-MM-DD"
+		}
+		params.Date = sql.NullTime{Time: date, Valid: true}
+	}
+
+	if f.MerchantName != nil {
+		merchant := strings.TrimSpace(*f.MerchantName)
+		if utf8.RuneCountInString(merchant) > maxManualNameLength {
+			return fmt.Sprintf("Merchant name must be at most %d characters", maxManualNameLength)
+		}
+		params.MerchantName = utils.CreateTextNullString(merchant)
+	}
+
+	if f.Category != nil {
+		category := strings.ToUpper(strings.TrimSpace(*f.Category))
+		if category == "" {
+			return "Category must not be empty"
+		}
+		params.PersonalFinanceCategory = category
+	}
+
+	if f.PaymentChannel != nil {
+		channel := strings.ToLower(strings.TrimSpace(*f.PaymentChannel))
+		if !slices.Contains(manualPaymentChannels, channel) {
+			return fmt.Sprintf("Payment channel must be one of: %s", strings.Join(manualPaymentChannels, ", "))
+		}
+		params.PaymentChannel = channel
+	}
+
+	return ""
+}
+
+// Response for a manual transaction
+func manualTransactionResponse(txn database.Transaction) models.Transaction {
+	return models.Transaction{
+		Id:                      txn.ID,
+		AccountId:               txn.AccountID,
+		Amount:                  txn.Amount,
+		IsoCurrencyCode:         txn.IsoCurrencyCode.String,
+		Date:                    txn.Date.Time,
+		MerchantName:            txn.MerchantName.String,
+		PaymentChannel:          txn.PaymentChannel,
+		PersonalFinanceCategory: txn.PersonalFinanceCategory,
+	}
+}
@@ -0,0 +1,532 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testManualAccount = database.Account{
+	ID:              testAccountID,
+	Name:            "Wallet",
+	Type:            "depository",
+	IsoCurrencyCode: sql.NullString{String: "CAD", Valid: true},
+	IsManual:        true,
+}
+
+// Transaction stored from the given params
+func manualTransaction(params database.UpdateManualTransactionParams) database.Transaction {
+	return database.Transaction{
+		ID:                      params.ID,
+		AccountID:               params.AccountID,
+		Amount:                  params.Amount,
+		IsoCurrencyCode:         params.IsoCurrencyCode,
+		Date:                    params.Date,
+		MerchantName:            params.MerchantName,
+		PaymentChannel:          params.PaymentChannel,
+		PersonalFinanceCategory: params.PersonalFinanceCategory,
+	}
+}
+
+func TestHandlerCreateManualAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		userIDInCtx    any
+		requestBody    string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "should create manual account",
+			userIDInCtx: testUserID,
+			requestBody: `{"name":" Wallet ","type":"Depository","iso_currency_code":"cad","balance":"120.50"}`,
+			mockDb: &mockDatabaseService{
+				CreateManualAccountFunc: func(ctx context.Context, arg database.CreateManualAccountParams) (database.Account, error) {
+					assert.Equal(t, "Wallet", arg.Name)
+					assert.Equal(t, "depository", arg.Type)
+					assert.Equal(t, "CAD", arg.IsoCurrencyCode.String)
+					assert.Equal(t, "120.50", arg.OpeningBalance.String)
+					assert.Equal(t, testUserID, arg.UserID)
+					return database.Account{
+						ID:             arg.ID,
+						Name:           arg.Name,
+						Type:           arg.Type,
+						CurrentBalance: arg.OpeningBalance,
+						IsManual:       true,
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"manual":true`,
+		},
+		{
+			name:        "should default type and balance",
+			userIDInCtx: testUserID,
+			requestBody: `{"name":"Wallet"}`,
+			mockDb: &mockDatabaseService{
+				CreateManualAccountFunc: func(ctx context.Context, arg database.CreateManualAccountParams) (database.Account, error) {
+					assert.Equal(t, "depository", arg.Type)
+					assert.Equal(t, "0.00", arg.OpeningBalance.String)
+					return database.Account{ID: arg.ID, Name: arg.Name, IsManual: true}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"display_name":"Wallet"`,
+		},
+		{
+			name:           "should err with bad userID in context",
+			userIDInCtx:    "not-a-uuid",
+			requestBody:    `{"name":"Wallet"}`,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad userID in context",
+		},
+		{
+			name:           "should err with missing name",
+			userIDInCtx:    testUserID,
+			requestBody:    `{"name":"  "}`,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Name is required",
+		},
+		{
+			name:           "should err with unknown type",
+			userIDInCtx:    testUserID,
+			requestBody:    `{"name":"Wallet","type":"savings"}`,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Type must be one of",
+		},
+		{
+			name:           "should err with invalid balance",
+			userIDInCtx:    testUserID,
+			requestBody:    `{"name":"Wallet","balance":"12.5"}`,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Balance must be an amount",
+		},
+		{
+			name:        "should err creating account",
+			userIDInCtx: testUserID,
+			requestBody: `{"name":"Wallet"}`,
+			mockDb: &mockDatabaseService{
+				CreateManualAccountFunc: func(ctx context.Context, arg database.CreateManualAccountParams) (database.Account, error) {
+					return database.Account{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/accounts", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInCtx)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerCreateManualAccount(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerCreateManualTransaction(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name           string
+		accountInCtx   any
+		requestBody    string
+		mockUpdater    *mockTxnUpdaterService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:         "should add transaction with defaults",
+			accountInCtx: testManualAccount,
+			requestBody:  `{"amount":"12.50","merchant_name":"Corner Store"}`,
+			mockUpdater: &mockTxnUpdaterService{
+				CreateManualTransactionFunc: func(ctx context.Context, userID uuid.UUID, params database.CreateTransactionParams) (database.Transaction, error) {
+					assert.Equal(t, testUserID, userID)
+					assert.Equal(t, testAccountID, params.AccountID)
+					assert.Equal(t, today, params.Date.Time)
+					assert.Equal(t, "CAD", params.IsoCurrencyCode.String)
+					assert.Equal(t, "other", params.PaymentChannel)
+					assert.Equal(t, "OTHER", params.PersonalFinanceCategory)
+					return manualTransaction(database.UpdateManualTransactionParams(params)), nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"merchant_name":"Corner Store"`,
+		},
+		{
+			name:         "should add income with given fields",
+			accountInCtx: testManualAccount,
+			requestBody:  `{"amount":"-500.00","date":"2025-03-01","category":"income","payment_channel":"In Store"}`,
+			mockUpdater: &mockTxnUpdaterService{
+				CreateManualTransactionFunc: func(ctx context.Context, userID uuid.UUID, params database.CreateTransactionParams) (database.Transaction, error) {
+					assert.Equal(t, "2025-03-01", params.Date.Time.Format("2006-01-02"))
+					assert.Equal(t, "in store", params.PaymentChannel)
+					return manualTransaction(database.UpdateManualTransactionParams(params)), nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"personal_finance_category":"INCOME"`,
+		},
+		{
+			name:           "should err with bad account in context",
+			accountInCtx:   1,
+			requestBody:    `{"amount":"12.50"}`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad account in context",
+		},
+		{
+			name:           "should err on linked account",
+			accountInCtx:   testAccount,
+			requestBody:    `{"amount":"12.50"}`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"not_manual_account"`,
+		},
+		{
+			name:           "should err with invalid amount",
+			accountInCtx:   testManualAccount,
+			requestBody:    `{"amount":"twelve"}`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Amount must be an amount",
+		},
+		{
+			name:           "should err with invalid date",
+			accountInCtx:   testManualAccount,
+			requestBody:    `{"amount":"12.50","date":"01/03/2025"}`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Date must be in the format YYYY-MM-DD",
+		},
+		{
+			name:           "should err with invalid payment channel",
+			accountInCtx:   testManualAccount,
+			requestBody:    `{"amount":"12.50","payment_channel":"mail"}`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Payment channel must be one of",
+		},
+		{
+			name:         "should err creating transaction",
+			accountInCtx: testManualAccount,
+			requestBody:  `{"amount":"12.50"}`,
+			mockUpdater: &mockTxnUpdaterService{
+				CreateManualTransactionFunc: func(ctx context.Context, userID uuid.UUID, params database.CreateTransactionParams) (database.Transaction, error) {
+					return database.Transaction{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqURL := fmt.Sprintf("/api/accounts/%s/transactions", testAccountID)
+
+			req := httptest.NewRequest("POST", reqURL, bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := context.WithValue(req.Context(), handlers.GetAccountKey(), tt.accountInCtx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), testUserID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:         &mockDatabaseService{},
+				TxnUpdater: tt.mockUpdater,
+				Logger:     kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerCreateManualTransaction(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerUpdateManualTransaction(t *testing.T) {
+	tests := []struct {
+		name           string
+		accountInCtx   any
+		txnInCtx       any
+		requestBody    string
+		mockUpdater    *mockTxnUpdaterService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:         "should update given fields and keep the rest",
+			accountInCtx: testManualAccount,
+			txnInCtx:     testTransaction,
+			requestBody:  `{"amount":"20.00","merchant_name":""}`,
+			mockUpdater: &mockTxnUpdaterService{
+				UpdateManualTransactionFunc: func(ctx context.Context, userID uuid.UUID, params database.UpdateManualTransactionParams) (database.Transaction, error) {
+					assert.Equal(t, testTransaction.ID, params.ID)
+					assert.Equal(t, "20.00", params.Amount)
+					assert.False(t, params.MerchantName.Valid)
+					assert.Equal(t, testTransaction.PersonalFinanceCategory, params.PersonalFinanceCategory)
+					return manualTransaction(params), nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"amount":"20.00"`,
+		},
+		{
+			name:           "should err on linked account",
+			accountInCtx:   testAccount,
+			txnInCtx:       testTransaction,
+			requestBody:    `{"amount":"20.00"}`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"not_manual_account"`,
+		},
+		{
+			name:           "should err with bad transaction in context",
+			accountInCtx:   testManualAccount,
+			txnInCtx:       1,
+			requestBody:    `{"amount":"20.00"}`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad transaction in context",
+		},
+		{
+			name:           "should err with empty category",
+			accountInCtx:   testManualAccount,
+			txnInCtx:       testTransaction,
+			requestBody:    `{"category":" "}`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Category must not be empty",
+		},
+		{
+			name:           "should err with bad request data",
+			accountInCtx:   testManualAccount,
+			txnInCtx:       testTransaction,
+			requestBody:    `{"amount":`,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad request data",
+		},
+		{
+			name:         "should err updating transaction",
+			accountInCtx: testManualAccount,
+			txnInCtx:     testTransaction,
+			requestBody:  `{"amount":"20.00"}`,
+			mockUpdater: &mockTxnUpdaterService{
+				UpdateManualTransactionFunc: func(ctx context.Context, userID uuid.UUID, params database.UpdateManualTransactionParams) (database.Transaction, error) {
+					return database.Transaction{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqURL := fmt.Sprintf("/api/accounts/%s/transactions/%s", testAccountID, testTxnID)
+
+			req := httptest.NewRequest("PUT", reqURL, bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := context.WithValue(req.Context(), handlers.GetAccountKey(), tt.accountInCtx)
+			ctx = context.WithValue(ctx, handlers.GetTransactionKey(), tt.txnInCtx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), testUserID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:         &mockDatabaseService{},
+				TxnUpdater: tt.mockUpdater,
+				Logger:     kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerUpdateManualTransaction(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerDeleteManualTransaction(t *testing.T) {
+	tests := []struct {
+		name           string
+		accountInCtx   any
+		mockUpdater    *mockTxnUpdaterService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:         "should delete transaction",
+			accountInCtx: testManualAccount,
+			mockUpdater: &mockTxnUpdaterService{
+				DeleteManualTransactionFunc: func(ctx context.Context, userID uuid.UUID, txn database.Transaction) error {
+					assert.Equal(t, testTransaction.ID, txn.ID)
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Transaction deleted successfully",
+		},
+		{
+			name:           "should err on linked account",
+			accountInCtx:   testAccount,
+			mockUpdater:    &mockTxnUpdaterService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"code":"not_manual_account"`,
+		},
+		{
+			name:         "should err deleting transaction",
+			accountInCtx: testManualAccount,
+			mockUpdater: &mockTxnUpdaterService{
+				DeleteManualTransactionFunc: func(ctx context.Context, userID uuid.UUID, txn database.Transaction) error {
+					return fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqURL := fmt.Sprintf("/api/accounts/%s/transactions/%s", testAccountID, testTxnID)
+
+			req := httptest.NewRequest("DELETE", reqURL, nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetAccountKey(), tt.accountInCtx)
+			ctx = context.WithValue(ctx, handlers.GetTransactionKey(), testTransaction)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), testUserID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:         &mockDatabaseService{},
+				TxnUpdater: tt.mockUpdater,
+				Logger:     kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerDeleteManualTransaction(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestManualTransactionsRecordEvents(t *testing.T) {
+	db := openTestDB(t)
+	userID := createTestUser(t, db)
+	ctx := context.Background()
+	q := database.New(db)
+
+	acc, err := q.CreateManualAccount(ctx, database.CreateManualAccountParams{
+		ID:              "manual-" + uuid.NewString(),
+		Name:            "Wallet",
+		Type:            "depository",
+		IsoCurrencyCode: sql.NullString{String: "CAD", Valid: true},
+		OpeningBalance:  sql.NullString{String: "100.00", Valid: true},
+		UserID:          userID,
+	})
+	require.NoError(t, err)
+
+	updater := handlers.NewDBTransactionUpdater(db, q)
+	txn, err := updater.CreateManualTransaction(ctx, userID, database.CreateTransactionParams{
+		ID:                      "txn-" + uuid.NewString(),
+		AccountID:               acc.ID,
+		Amount:                  "4.75",
+		Date:                    sql.NullTime{Time: time.Now(), Valid: true},
+		PaymentChannel:          "other",
+		PersonalFinanceCategory: "FOOD_AND_DRINK",
+	})
+	require.NoError(t, err)
+
+	txn, err = updater.UpdateManualTransaction(ctx, userID, database.UpdateManualTransactionParams{
+		ID:                      txn.ID,
+		AccountID:               acc.ID,
+		Amount:                  "5.25",
+		Date:                    txn.Date,
+		PaymentChannel:          txn.PaymentChannel,
+		PersonalFinanceCategory: txn.PersonalFinanceCategory,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, updater.DeleteManualTransaction(ctx, userID, txn))
+
+	recorded, err := q.GetEventsForUser(ctx, database.GetEventsForUserParams{
+		UserID:   userID,
+		AfterSeq: 0,
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+
+	types := []string{}
+	for _, e := range recorded {
+		types = append(types, e.Type)
+		assert.Contains(t, string(e.Payload), txn.ID)
+	}
+	assert.Equal(t, []string{"transaction.created", "transaction.modified", "transaction.removed"}, types)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/merchant"
+	"github.com/jms-guy/greed/models"
+)
+
+// Handler gets the canonical merchants of the user's transactions, with the raw names normalized to each,
+// most used merchants first
+func (app *AppServer) HandlerGetMerchants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	normalizer, err := loadMerchantNormalizer(ctx, app.Db, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", err)
+		return
+	}
+
+	rows, err := app.Db.GetUserMerchantNames(ctx, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting merchant names: %w", err))
+		return
+	}
+
+	byName := make(map[string]*models.Merchant)
+	for _, row := range rows {
+		// Transactions not normalized yet are listed as they will be once they are
+		name := row.CanonicalMerchant.String
+		if !row.CanonicalMerchant.Valid {
+			name = normalizer.Canonical(row.MerchantName.String, row.Name.String)
+		}
+		if name == "" {
+			continue
+		}
+
+		m, ok := byName[name]
+		if !ok {
+			m = &models.Merchant{Name: name, RawNames: []string{}}
+			byName[name] = m
+		}
+		m.TransactionCount += row.TxnCount
+
+		raw := strings.TrimSpace(row.MerchantName.String)
+		if raw == "" {
+			raw = strings.TrimSpace(row.Name.String)
+		}
+		if raw != "" && !slices.Contains(m.RawNames, raw) {
+			m.RawNames = append(m.RawNames, raw)
+		}
+	}
+
+	merchants := make([]models.Merchant, 0, len(byName))
+	for _, m := range byName {
+		sort.Strings(m.RawNames)
+		merchants = append(merchants, *m)
+	}
+	sort.Slice(merchants, func(i, j int) bool {
+		if merchants[i].TransactionCount != merchants[j].TransactionCount {
+			return merchants[i].TransactionCount > merchants[j].TransactionCount
+		}
+		return merchants[i].Name < merchants[j].Name
+	})
+
+	app.respondWithJSON(w, 200, merchants)
+}
+
+// Handler gets the user's merchant aliases
+func (app *AppServer) HandlerGetMerchantAliases(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	aliases, err := app.Db.GetMerchantAliases(ctx, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting merchant aliases: %w", err))
+		return
+	}
+
+	response := []models.MerchantAlias{}
+	for _, alias := range aliases {
+		response = append(response, merchantAliasResponse(alias))
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
+
+// Handler creates a merchant alias, counting transactions with the raw name under the given merchant. An alias
+// for a raw name that cleans up the same as an existing alias's replaces it. The user's transactions are
+// renormalized before responding, so summaries reflect the alias straight away
+func (app *AppServer) HandlerCreateMerchantAlias(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	request := models.CreateMerchantAlias{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	rawName := strings.TrimSpace(request.RawName)
+	matchKey := merchant.Key(rawName)
+	if matchKey == "" {
+		app.respondWithError(w, 400, "Raw name must not be empty", nil)
+		return
+	}
+
+	name := strings.TrimSpace(request.Merchant)
+	if name == "" {
+		app.respondWithError(w, 400, "Merchant must not be empty", nil)
+		return
+	}
+	if utf8.RuneCountInString(name) > maxManualNameLength {
+		app.respondWithError(w, 400, fmt.Sprintf("Merchant must be at most %d characters", maxManualNameLength), nil)
+		return
+	}
+
+	alias, err := app.Db.UpsertMerchantAlias(ctx, database.UpsertMerchantAliasParams{
+		ID:       uuid.New(),
+		UserID:   id,
+		RawName:  rawName,
+		MatchKey: matchKey,
+		Merchant: name,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error creating merchant alias: %w", err))
+		return
+	}
+
+	if _, err := app.normalizeUserMerchants(ctx, id); err != nil {
+		app.respondWithError(w, 500, "Database error", err)
+		return
+	}
+
+	app.respondWithJSON(w, 201, merchantAliasResponse(alias))
+}
+
+// Handler deletes a merchant alias, returning the transactions it applied to to their cleaned up merchant names
+func (app *AppServer) HandlerDeleteMerchantAlias(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	aliasID, err := uuid.Parse(chi.URLParam(r, "alias-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid alias ID", nil)
+		return
+	}
+
+	count, err := app.Db.DeleteMerchantAlias(ctx, database.DeleteMerchantAliasParams{
+		ID:     aliasID,
+		UserID: id,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error deleting merchant alias: %w", err))
+		return
+	}
+	if count == 0 {
+		app.respondWithError(w, 404, "Alias not found", nil)
+		return
+	}
+
+	if _, err := app.normalizeUserMerchants(ctx, id); err != nil {
+		app.respondWithError(w, 500, "Database error", err)
+		return
+	}
+
+	app.respondWithJSON(w, 200, "Alias deleted")
+}
+
+func merchantAliasResponse(alias database.MerchantAlias) models.MerchantAlias {
+	return models.MerchantAlias{
+		ID:        alias.ID,
+		RawName:   alias.RawName,
+		Merchant:  alias.Merchant,
+		CreatedAt: alias.CreatedAt,
+	}
+}
@@ -0,0 +1,361 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+var testAliasID = uuid.MustParse("7f6e5d4c-3b2a-4f1e-8d9c-8b7a69584736")
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func TestHandlerGetMerchants(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should group raw names under canonical merchant",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetUserMerchantNamesFunc: func(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error) {
+					return []database.GetUserMerchantNamesRow{
+						{MerchantName: nullString("Amazon.ca"), CanonicalMerchant: nullString("Amazon"), TxnCount: 2},
+						{MerchantName: nullString("AMZN Mktp CA*2K3L45"), CanonicalMerchant: nullString("Amazon"), TxnCount: 3},
+						{MerchantName: nullString("Netflix"), CanonicalMerchant: nullString("Netflix"), TxnCount: 1},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"Amazon","raw_names":["AMZN Mktp CA*2K3L45","Amazon.ca"],"transaction_count":5},{"name":"Netflix","raw_names":["Netflix"],"transaction_count":1}]`,
+		},
+		{
+			name:            "should normalize transactions not normalized yet",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetMerchantAliasesFunc: func(ctx context.Context, userID uuid.UUID) ([]database.MerchantAlias, error) {
+					return []database.MerchantAlias{{RawName: "SQ *CORNER CAFE", Merchant: "Corner Café"}}, nil
+				},
+				GetUserMerchantNamesFunc: func(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error) {
+					return []database.GetUserMerchantNamesRow{
+						{Name: nullString("CORNER CAFE #12"), TxnCount: 4},
+						{TxnCount: 1},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"Corner Café","raw_names":["CORNER CAFE #12"],"transaction_count":4}]`,
+		},
+		{
+			name:            "should return empty list with no transactions",
+			userIDInContext: testUserID,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "[]",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err getting merchant names",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetUserMerchantNamesFunc: func(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/merchants", nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetMerchants(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerCreateMerchantAlias(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "should create alias keyed on cleaned up name",
+			requestBody: `{"raw_name":"SQ *Corner Cafe #12","merchant":"Corner Café"}`,
+			mockDb: &mockDatabaseService{
+				UpsertMerchantAliasFunc: func(ctx context.Context, arg database.UpsertMerchantAliasParams) (database.MerchantAlias, error) {
+					if arg.MatchKey != "corner cafe" {
+						return database.MerchantAlias{}, fmt.Errorf("unexpected match key: %s", arg.MatchKey)
+					}
+					return database.MerchantAlias{ID: testAliasID, RawName: arg.RawName, Merchant: arg.Merchant}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"merchant":"Corner Café"`,
+		},
+		{
+			name:           "should err with empty raw name",
+			requestBody:    `{"raw_name":"  ","merchant":"Corner Café"}`,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Raw name must not be empty",
+		},
+		{
+			name:           "should err with empty merchant",
+			requestBody:    `{"raw_name":"CORNER CAFE","merchant":""}`,
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Merchant must not be empty",
+		},
+		{
+			name:           "should err with long merchant",
+			requestBody:    fmt.Sprintf(`{"raw_name":"CORNER CAFE","merchant":"%s"}`, strings.Repeat("a", 101)),
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Merchant must be at most 100 characters",
+		},
+		{
+			name:        "should err creating alias",
+			requestBody: `{"raw_name":"CORNER CAFE","merchant":"Corner Café"}`,
+			mockDb: &mockDatabaseService{
+				UpsertMerchantAliasFunc: func(ctx context.Context, arg database.UpsertMerchantAliasParams) (database.MerchantAlias, error) {
+					return database.MerchantAlias{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/merchants/aliases", bytes.NewBufferString(tt.requestBody))
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), testUserID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerCreateMerchantAlias(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerCreateMerchantAliasRenormalizes(t *testing.T) {
+	var aliases []database.MerchantAlias
+	var updates []database.SetCanonicalMerchantParams
+
+	mockDb := &mockDatabaseService{
+		UpsertMerchantAliasFunc: func(ctx context.Context, arg database.UpsertMerchantAliasParams) (database.MerchantAlias, error) {
+			alias := database.MerchantAlias{ID: arg.ID, UserID: arg.UserID, RawName: arg.RawName, MatchKey: arg.MatchKey, Merchant: arg.Merchant}
+			aliases = append(aliases, alias)
+			return alias, nil
+		},
+		GetMerchantAliasesFunc: func(ctx context.Context, userID uuid.UUID) ([]database.MerchantAlias, error) {
+			return aliases, nil
+		},
+		GetUserMerchantNamesFunc: func(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error) {
+			return []database.GetUserMerchantNamesRow{
+				{MerchantName: nullString("SHELL OIL #5721"), CanonicalMerchant: nullString("Shell Oil"), TxnCount: 3},
+				{MerchantName: nullString("Netflix"), CanonicalMerchant: nullString("Netflix"), TxnCount: 1},
+			}, nil
+		},
+		SetCanonicalMerchantFunc: func(ctx context.Context, arg database.SetCanonicalMerchantParams) (int64, error) {
+			updates = append(updates, arg)
+			return 3, nil
+		},
+	}
+
+	req := httptest.NewRequest("POST", "/api/merchants/aliases", bytes.NewBufferString(`{"raw_name":"Shell Oil","merchant":"Shell"}`))
+	req = req.WithContext(context.WithValue(req.Context(), handlers.GetUserIDContextKey(), testUserID))
+	rr := httptest.NewRecorder()
+
+	mockApp := &handlers.AppServer{
+		Db:     mockDb,
+		Logger: kitlog.NewNopLogger(),
+	}
+
+	mockApp.HandlerCreateMerchantAlias(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	// Only the transactions whose canonical merchant changed are updated
+	assert.Equal(t, []database.SetCanonicalMerchantParams{{
+		CanonicalMerchant: nullString("Shell"),
+		UserID:            testUserID,
+		MerchantName:      nullString("SHELL OIL #5721"),
+	}}, updates)
+}
+
+func TestHandlerDeleteMerchantAlias(t *testing.T) {
+	tests := []struct {
+		name           string
+		pathParams     map[string]string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "should delete alias",
+			pathParams: map[string]string{"alias-id": testAliasID.String()},
+			mockDb: &mockDatabaseService{
+				DeleteMerchantAliasFunc: func(ctx context.Context, arg database.DeleteMerchantAliasParams) (int64, error) {
+					return 1, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Alias deleted",
+		},
+		{
+			name:           "should err with alias of another user",
+			pathParams:     map[string]string{"alias-id": testAliasID.String()},
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Alias not found",
+		},
+		{
+			name:           "should err with invalid alias ID",
+			pathParams:     map[string]string{"alias-id": "not-a-uuid"},
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid alias ID",
+		},
+		{
+			name:       "should err renormalizing merchants",
+			pathParams: map[string]string{"alias-id": testAliasID.String()},
+			mockDb: &mockDatabaseService{
+				DeleteMerchantAliasFunc: func(ctx context.Context, arg database.DeleteMerchantAliasParams) (int64, error) {
+					return 1, nil
+				},
+				GetUserMerchantNamesFunc: func(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/merchants/aliases/%s", tt.pathParams["alias-id"]), nil)
+
+			rctx := chi.NewRouteContext()
+			for key, value := range tt.pathParams {
+				rctx.URLParams.Add(key, value)
+			}
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), testUserID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerDeleteMerchantAlias(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestRunMerchantNormalizer(t *testing.T) {
+	otherUserID := uuid.New()
+	normalized := map[uuid.UUID]string{}
+
+	mockDb := &mockDatabaseService{
+		GetUsersWithUnnormalizedMerchantsFunc: func(ctx context.Context) ([]uuid.UUID, error) {
+			return []uuid.UUID{testUserID, otherUserID}, nil
+		},
+		GetUserMerchantNamesFunc: func(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error) {
+			if userID == otherUserID {
+				return nil, fmt.Errorf("mock error")
+			}
+			return []database.GetUserMerchantNamesRow{{Name: nullString("AMZN Mktp CA*2K3L45"), TxnCount: 2}}, nil
+		},
+		SetCanonicalMerchantFunc: func(ctx context.Context, arg database.SetCanonicalMerchantParams) (int64, error) {
+			normalized[arg.UserID] = arg.CanonicalMerchant.String
+			return 2, nil
+		},
+	}
+
+	mockApp := &handlers.AppServer{
+		Db:     mockDb,
+		Logger: kitlog.NewNopLogger(),
+	}
+
+	// A failure for one user is logged, and doesn't stop the others being normalized
+	err := mockApp.RunMerchantNormalizer(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]string{testUserID: "Amazon"}, normalized)
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/notify"
+	"github.com/jms-guy/greed/models"
+)
+
+// Handler gets the user's notification rules
+func (app *AppServer) HandlerGetNotificationRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	rules, err := app.Db.GetNotificationRulesForUser(ctx, id)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting notification rules: %w", err))
+		return
+	}
+
+	response := []models.NotificationRule{}
+	for _, rule := range rules {
+		response = append(response, notificationRuleResponse(rule))
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
+
+// Handler creates a notification rule. Webhook rules are given a signing secret, which is encrypted in the
+// database and only returned in this response
+func (app *AppServer) HandlerCreateNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	request := models.CreateNotificationRule{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	kind := strings.ToLower(strings.TrimSpace(request.Kind))
+	if !notify.ValidKind(kind) {
+		app.respondWithError(w, 400, "Invalid rule kind", nil)
+		return
+	}
+
+	params := database.CreateNotificationRuleParams{
+		ID:     uuid.New(),
+		UserID: id,
+		Kind:   kind,
+	}
+
+	if notify.NeedsThreshold(kind) {
+		threshold, err := strconv.ParseFloat(request.Threshold, 64)
+		if err != nil || threshold <= 0 {
+			app.respondWithError(w, 400, "Threshold must be a positive amount", nil)
+			return
+		}
+		params.Threshold = sql.NullString{String: fmt.Sprintf("%.2f", threshold), Valid: true}
+	}
+
+	if kind == notify.KindBudgetExceeded {
+		category := strings.ToUpper(strings.TrimSpace(request.Category))
+		if category == "" {
+			app.respondWithError(w, 400, "Budget rules require a category", nil)
+			return
+		}
+		params.Category = sql.NullString{String: category, Valid: true}
+	}
+
+	if request.AccountID != "" {
+		_, err := app.Db.GetAccountById(ctx, database.GetAccountByIdParams{
+			ID:     request.AccountID,
+			UserID: id,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				app.respondWithError(w, 404, "Account not found", nil)
+				return
+			}
+			app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting account: %w", err))
+			return
+		}
+		params.AccountID = sql.NullString{String: request.AccountID, Valid: true}
+	}
+
+	var secret string
+	switch channel := strings.ToLower(strings.TrimSpace(request.Channel)); channel {
+	case notify.ChannelEmail:
+		params.Channel = channel
+	case notify.ChannelWebhook:
+		if !app.validWebhookURL(request.WebhookURL) {
+			app.respondWithError(w, 400, "Webhook URL must be an absolute https URL to a public address", nil)
+			return
+		}
+
+		var err error
+		secret, err = notify.NewSecret()
+		if err != nil {
+			app.respondWithError(w, 500, "Error generating webhook secret", err)
+			return
+		}
+		encryptedSecret, err := app.Encryptor.EncryptAccessToken([]byte(secret), app.Config.AESKey)
+		if err != nil {
+			app.respondWithError(w, 500, "Error encrypting webhook secret", err)
+			return
+		}
+
+		params.Channel = channel
+		params.WebhookUrl = sql.NullString{String: request.WebhookURL, Valid: true}
+		params.WebhookSecret = sql.NullString{String: encryptedSecret, Valid: true}
+	default:
+		app.respondWithError(w, 400, "Channel must be email or webhook", nil)
+		return
+	}
+
+	rule, err := app.Db.CreateNotificationRule(ctx, params)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error creating notification rule: %w", err))
+		return
+	}
+
+	response := notificationRuleResponse(rule)
+	response.WebhookSecret = secret
+
+	app.respondWithJSON(w, 201, response)
+}
+
+// Handler deletes one of the user's notification rules, along with its delivery log
+func (app *AppServer) HandlerDeleteNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	ruleID, err := uuid.Parse(chi.URLParam(r, "rule-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid rule ID", nil)
+		return
+	}
+
+	count, err := app.Db.DeleteNotificationRule(ctx, database.DeleteNotificationRuleParams{
+		ID:     ruleID,
+		UserID: id,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error deleting notification rule: %w", err))
+		return
+	}
+	if count == 0 {
+		app.respondWithError(w, 404, "Rule not found", nil)
+		return
+	}
+
+	app.respondWithJSON(w, 200, "Rule deleted")
+}
+
+// Handler gets the user's most recent notification deliveries. The "limit" query parameter sets how many
+// are returned, defaulting to 50
+func (app *AppServer) HandlerGetNotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > 500 {
+			app.respondWithError(w, 400, "Bad query parameter: limit must be between 1 and 500", nil)
+			return
+		}
+	}
+
+	rows, err := app.Db.GetNotificationDeliveriesForUser(ctx, database.GetNotificationDeliveriesForUserParams{
+		UserID:   id,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting notification deliveries: %w", err))
+		return
+	}
+
+	deliveries := []models.NotificationDelivery{}
+	for _, row := range rows {
+		d := models.NotificationDelivery{
+			ID:            row.ID,
+			RuleID:        row.RuleID,
+			Kind:          row.Kind,
+			Channel:       row.Channel,
+			Subject:       row.Subject,
+			Status:        row.Status,
+			Attempts:      int(row.Attempts),
+			LastError:     row.LastError.String,
+			NextAttemptAt: row.NextAttemptAt,
+			CreatedAt:     row.CreatedAt,
+		}
+		if row.DeliveredAt.Valid {
+			d.DeliveredAt = &row.DeliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	app.respondWithJSON(w, 200, deliveries)
+}
+
+// Handler queues a failed delivery to be sent again
+func (app *AppServer) HandlerRetryNotificationDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery-id"))
+	if err != nil {
+		app.respondWithError(w, 400, "Invalid delivery ID", nil)
+		return
+	}
+
+	_, err = app.Db.RetryNotificationDelivery(ctx, database.RetryNotificationDeliveryParams{
+		ID:     deliveryID,
+		UserID: id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			app.respondWithError(w, 404, "No failed delivery with that ID", nil)
+			return
+		}
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error retrying notification delivery: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, "Delivery queued for retry")
+}
+
+// Webhook URLs must be absolute, and use HTTPS outside of development. Outside of development they also
+// can't name a loopback, private or other non-public address directly. Hostnames are checked against the
+// addresses they resolve to when webhooks are sent, as what a name resolves to can change
+func (app *AppServer) validWebhookURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	isDev := app.Config != nil && app.Config.Environment == "dev"
+	if isDev {
+		return u.Scheme == "https" || u.Scheme == "http"
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !notify.PublicAddress(ip) {
+		return false
+	}
+
+	return u.Scheme == "https"
+}
+
+func notificationRuleResponse(rule database.NotificationRule) models.NotificationRule {
+	return models.NotificationRule{
+		ID:         rule.ID,
+		Kind:       rule.Kind,
+		Threshold:  rule.Threshold.String,
+		Category:   rule.Category.String,
+		AccountID:  rule.AccountID.String,
+		Channel:    rule.Channel,
+		WebhookURL: rule.WebhookUrl.String,
+		Enabled:    rule.Enabled,
+		CreatedAt:  rule.CreatedAt,
+	}
+}
@@ -0,0 +1,730 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/api/sgrid"
+	"github.com/jms-guy/greed/backend/internal/auth"
+	"github.com/jms-guy/greed/backend/internal/config"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/notify"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/plaid/plaid-go/v36/plaid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testRuleID     = uuid.MustParse("5d4c3b2a-1f0e-4d9c-8b7a-695847362514")
+	testDeliveryID = uuid.MustParse("6e5d4c3b-2a1f-4e0d-9c8b-7a6958473625")
+)
+
+func TestHandlerCreateNotificationRule(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		requestBody     string
+		mockDb          *mockDatabaseService
+		mockEncryptor   *mockEncryptor
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should create email rule",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"large_transaction","threshold":"500","channel":"email"}`,
+			mockDb: &mockDatabaseService{
+				CreateNotificationRuleFunc: func(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error) {
+					if arg.Threshold.String != "500.00" {
+						return database.NotificationRule{}, fmt.Errorf("threshold not normalized: %s", arg.Threshold.String)
+					}
+					return database.NotificationRule{ID: arg.ID, Kind: arg.Kind, Threshold: arg.Threshold, Channel: arg.Channel, Enabled: true}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"threshold":"500.00"`,
+		},
+		{
+			name:            "should create webhook rule and return secret once",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"webhook","webhook_url":"https://example.com/hook"}`,
+			mockDb: &mockDatabaseService{
+				CreateNotificationRuleFunc: func(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error) {
+					if arg.WebhookSecret.String != "encrypted" {
+						return database.NotificationRule{}, fmt.Errorf("webhook secret stored unencrypted")
+					}
+					return database.NotificationRule{ID: arg.ID, Kind: arg.Kind, Channel: arg.Channel, WebhookUrl: arg.WebhookUrl, Enabled: true}, nil
+				},
+			},
+			mockEncryptor: &mockEncryptor{
+				EncryptAccessTokenFunc: func(plaintext []byte, keyString string) (string, error) {
+					return "encrypted", nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"webhook_secret":"`,
+		},
+		{
+			name:            "should scope rule to one of the user's accounts",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"low_balance","threshold":"100.00","account_id":"54321","channel":"email"}`,
+			mockDb: &mockDatabaseService{
+				GetAccountByIdFunc: func(ctx context.Context, arg database.GetAccountByIdParams) (database.Account, error) {
+					return testAccount, nil
+				},
+				CreateNotificationRuleFunc: func(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error) {
+					return database.NotificationRule{ID: arg.ID, Kind: arg.Kind, AccountID: arg.AccountID, Channel: arg.Channel}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"account_id":"54321"`,
+		},
+		{
+			name:            "should err with unknown kind",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"lottery_win","channel":"email"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Invalid rule kind",
+		},
+		{
+			name:            "should err with missing threshold",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"low_balance","channel":"email"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Threshold must be a positive amount",
+		},
+		{
+			name:            "should err with budget rule missing category",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"budget_exceeded","threshold":"300","channel":"email"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Budget rules require a category",
+		},
+		{
+			name:            "should err with plain http webhook",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"webhook","webhook_url":"http://example.com/hook"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Webhook URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with loopback webhook address",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"webhook","webhook_url":"https://127.0.0.1/hook"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Webhook URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with private webhook address",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"webhook","webhook_url":"https://10.0.0.5/hook"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Webhook URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with cloud metadata webhook address",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"webhook","webhook_url":"https://169.254.169.254/latest/meta-data"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Webhook URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with IPv6 loopback webhook address",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"webhook","webhook_url":"https://[::1]/hook"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Webhook URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with localhost webhook address",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"webhook","webhook_url":"https://localhost:8443/hook"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Webhook URL must be an absolute https URL",
+		},
+		{
+			name:            "should err with unknown channel",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"pigeon"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Channel must be email or webhook",
+		},
+		{
+			name:            "should err with account of another user",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"new_recurring","account_id":"other","channel":"email"}`,
+			mockDb: &mockDatabaseService{
+				GetAccountByIdFunc: func(ctx context.Context, arg database.GetAccountByIdParams) (database.Account, error) {
+					return database.Account{}, sql.ErrNoRows
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Account not found",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			requestBody:     `{"kind":"item_reauth","channel":"email"}`,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err creating rule",
+			userIDInContext: testUserID,
+			requestBody:     `{"kind":"item_reauth","channel":"email"}`,
+			mockDb: &mockDatabaseService{
+				CreateNotificationRuleFunc: func(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error) {
+					return database.NotificationRule{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/notifications/rules", bytes.NewBufferString(tt.requestBody))
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:        tt.mockDb,
+				Encryptor: tt.mockEncryptor,
+				Config:    &config.Config{},
+				Logger:    kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerCreateNotificationRule(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerGetNotificationRules(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should get rules without secrets",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetNotificationRulesForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.NotificationRule, error) {
+					return []database.NotificationRule{{
+						ID:            testRuleID,
+						Kind:          notify.KindItemReauth,
+						Channel:       notify.ChannelWebhook,
+						WebhookUrl:    sql.NullString{String: "https://example.com/hook", Valid: true},
+						WebhookSecret: sql.NullString{String: "encrypted", Valid: true},
+					}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"webhook_url":"https://example.com/hook"`,
+		},
+		{
+			name:            "should return empty list with no rules",
+			userIDInContext: testUserID,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "[]",
+		},
+		{
+			name:            "should err getting rules",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetNotificationRulesForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.NotificationRule, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/notifications/rules", nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetNotificationRules(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+			if strings.Contains(rr.Body.String(), "webhook_secret") {
+				t.Errorf("handler returned webhook secret: %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlerDeleteNotificationRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		pathParams     map[string]string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "should delete rule",
+			pathParams: map[string]string{"rule-id": testRuleID.String()},
+			mockDb: &mockDatabaseService{
+				DeleteNotificationRuleFunc: func(ctx context.Context, arg database.DeleteNotificationRuleParams) (int64, error) {
+					return 1, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Rule deleted",
+		},
+		{
+			name:           "should err with rule of another user",
+			pathParams:     map[string]string{"rule-id": testRuleID.String()},
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Rule not found",
+		},
+		{
+			name:           "should err with invalid rule ID",
+			pathParams:     map[string]string{"rule-id": "not-a-uuid"},
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid rule ID",
+		},
+		{
+			name:       "should err deleting rule",
+			pathParams: map[string]string{"rule-id": testRuleID.String()},
+			mockDb: &mockDatabaseService{
+				DeleteNotificationRuleFunc: func(ctx context.Context, arg database.DeleteNotificationRuleParams) (int64, error) {
+					return 0, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/notifications/rules/%s", tt.pathParams["rule-id"]), nil)
+
+			rctx := chi.NewRouteContext()
+			for key, value := range tt.pathParams {
+				rctx.URLParams.Add(key, value)
+			}
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), testUserID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerDeleteNotificationRule(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerGetNotificationDeliveries(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "should get delivery log",
+			query: "?limit=10",
+			mockDb: &mockDatabaseService{
+				GetNotificationDeliveriesForUserFunc: func(ctx context.Context, arg database.GetNotificationDeliveriesForUserParams) ([]database.GetNotificationDeliveriesForUserRow, error) {
+					if arg.RowLimit != 10 {
+						return nil, fmt.Errorf("limit not applied")
+					}
+					return []database.GetNotificationDeliveriesForUserRow{{
+						ID:        testDeliveryID,
+						RuleID:    testRuleID,
+						Kind:      notify.KindLowBalance,
+						Channel:   notify.ChannelWebhook,
+						Status:    notify.StatusFailed,
+						Attempts:  6,
+						LastError: sql.NullString{String: "webhook endpoint responded with status 500", Valid: true},
+					}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"last_error":"webhook endpoint responded with status 500"`,
+		},
+		{
+			name:           "should err with bad limit",
+			query:          "?limit=0",
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad query parameter",
+		},
+		{
+			name:  "should err getting deliveries",
+			query: "",
+			mockDb: &mockDatabaseService{
+				GetNotificationDeliveriesForUserFunc: func(ctx context.Context, arg database.GetNotificationDeliveriesForUserParams) ([]database.GetNotificationDeliveriesForUserRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/notifications/deliveries"+tt.query, nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), testUserID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetNotificationDeliveries(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerRetryNotificationDelivery(t *testing.T) {
+	tests := []struct {
+		name           string
+		pathParams     map[string]string
+		mockDb         *mockDatabaseService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:       "should queue failed delivery",
+			pathParams: map[string]string{"delivery-id": testDeliveryID.String()},
+			mockDb: &mockDatabaseService{
+				RetryNotificationDeliveryFunc: func(ctx context.Context, arg database.RetryNotificationDeliveryParams) (database.NotificationDelivery, error) {
+					return database.NotificationDelivery{ID: arg.ID, Status: notify.StatusPending}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Delivery queued for retry",
+		},
+		{
+			name:       "should err with delivery that has not failed",
+			pathParams: map[string]string{"delivery-id": testDeliveryID.String()},
+			mockDb: &mockDatabaseService{
+				RetryNotificationDeliveryFunc: func(ctx context.Context, arg database.RetryNotificationDeliveryParams) (database.NotificationDelivery, error) {
+					return database.NotificationDelivery{}, sql.ErrNoRows
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "No failed delivery with that ID",
+		},
+		{
+			name:           "should err with invalid delivery ID",
+			pathParams:     map[string]string{"delivery-id": "not-a-uuid"},
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid delivery ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf("/api/notifications/deliveries/%s/retry", tt.pathParams["delivery-id"]), nil)
+
+			rctx := chi.NewRouteContext()
+			for key, value := range tt.pathParams {
+				rctx.URLParams.Add(key, value)
+			}
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetUserIDContextKey(), testUserID)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerRetryNotificationDelivery(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestDispatchNotifications(t *testing.T) {
+	delivery := database.NotificationDelivery{
+		ID:      testDeliveryID,
+		RuleID:  testRuleID,
+		UserID:  testUserID,
+		Subject: "Low balance in Chequing",
+		Message: "The balance of Chequing is 12.00 CAD",
+		Status:  notify.StatusPending,
+	}
+	webhookRule := database.NotificationRule{
+		ID:            testRuleID,
+		Kind:          notify.KindLowBalance,
+		Channel:       notify.ChannelWebhook,
+		WebhookUrl:    sql.NullString{String: "https://example.com/hook", Valid: true},
+		WebhookSecret: sql.NullString{String: "encrypted", Valid: true},
+	}
+	emailRule := database.NotificationRule{ID: testRuleID, Kind: notify.KindLowBalance, Channel: notify.ChannelEmail}
+
+	tests := []struct {
+		name           string
+		rule           database.NotificationRule
+		attempts       int32
+		sendErr        error
+		expectedStatus string
+		expectedRetry  bool
+	}{
+		{
+			name:           "webhook delivery is recorded as delivered",
+			rule:           webhookRule,
+			expectedStatus: notify.StatusDelivered,
+		},
+		{
+			name:           "email delivery is recorded as delivered",
+			rule:           emailRule,
+			expectedStatus: notify.StatusDelivered,
+		},
+		{
+			name:           "failed delivery is retried later",
+			rule:           webhookRule,
+			attempts:       1,
+			sendErr:        fmt.Errorf("connection refused"),
+			expectedStatus: notify.StatusPending,
+			expectedRetry:  true,
+		},
+		{
+			name:           "delivery failing its last attempt is marked failed",
+			rule:           emailRule,
+			attempts:       notify.MaxAttempts - 1,
+			sendErr:        fmt.Errorf("sendgrid unavailable"),
+			expectedStatus: notify.StatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recorded []database.RecordNotificationAttemptParams
+
+			d := delivery
+			d.Attempts = tt.attempts
+			mockDb := &mockDatabaseService{
+				ClaimDueNotificationDeliveriesFunc: func(ctx context.Context, arg database.ClaimDueNotificationDeliveriesParams) ([]database.NotificationDelivery, error) {
+					return []database.NotificationDelivery{d}, nil
+				},
+				GetNotificationRuleFunc: func(ctx context.Context, id uuid.UUID) (database.NotificationRule, error) {
+					return tt.rule, nil
+				},
+				GetUserFunc: func(ctx context.Context, id uuid.UUID) (database.User, error) {
+					return database.User{ID: id, Name: testName, Email: testEmail}, nil
+				},
+				RecordNotificationAttemptFunc: func(ctx context.Context, arg database.RecordNotificationAttemptParams) error {
+					recorded = append(recorded, arg)
+					return nil
+				},
+			}
+
+			var sentTo string
+			mockApp := &handlers.AppServer{
+				Db:     mockDb,
+				Config: &config.Config{GreedEmail: "greed@email.com"},
+				Logger: kitlog.NewNopLogger(),
+				SgMail: &mockMailService{
+					NewMailFunc: func(from, to, subject, body string, data *sgrid.MailData) *sgrid.Mail {
+						return &sgrid.Mail{From: from, To: to, Subject: subject, Body: body, Data: data}
+					},
+					SendMailFunc: func(mailreq *sgrid.Mail) error {
+						sentTo = mailreq.To
+						return tt.sendErr
+					},
+				},
+				Encryptor: &mockEncryptor{
+					DecryptAccessTokenFunc: func(ciphertext, keyString string) ([]byte, error) {
+						return []byte("secret"), nil
+					},
+				},
+				Webhooks: &mockWebhookSender{
+					SendFunc: func(ctx context.Context, url string, secret []byte, payload notify.Payload) error {
+						sentTo = url
+						assert.Equal(t, "secret", string(secret))
+						assert.Equal(t, testDeliveryID.String(), payload.ID)
+						return tt.sendErr
+					},
+				},
+			}
+
+			mockApp.DispatchNotifications(context.Background())
+
+			if assert.Len(t, recorded, 1) {
+				assert.Equal(t, tt.expectedStatus, recorded[0].Status)
+				assert.Equal(t, tt.sendErr != nil, recorded[0].LastError.Valid)
+				assert.Equal(t, tt.expectedRetry, recorded[0].NextAttemptAt.After(time.Now().Add(30*time.Second)))
+			}
+			if tt.rule.Channel == notify.ChannelEmail {
+				assert.Equal(t, testEmail, sentTo)
+			} else {
+				assert.Equal(t, tt.rule.WebhookUrl.String, sentTo)
+			}
+		})
+	}
+}
+
+func TestHandlerPlaidWebhookQueuesReauthNotification(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestBody   string
+		expectedQueue bool
+	}{
+		{
+			name:          "login required queues a notification",
+			requestBody:   `{"webhook_type":"ITEM", "webhook_code":"PENDING_EXPIRATION", "item_id":"12345"}`,
+			expectedQueue: true,
+		},
+		{
+			name:          "transaction updates do not",
+			requestBody:   `{"webhook_type":"TRANSACTIONS", "webhook_code":"SYNC_UPDATES_AVAILABLE", "item_id":"12345"}`,
+			expectedQueue: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queued []database.CreateNotificationDeliveryParams
+
+			mockDb := &mockDatabaseService{
+				GetItemByIDFunc: func(ctx context.Context, id string) (database.PlaidItem, error) {
+					return database.PlaidItem{ID: id, UserID: testUserID, InstitutionName: "Bank"}, nil
+				},
+				CreatePlaidWebhookRecordFunc: func(ctx context.Context, arg database.CreatePlaidWebhookRecordParams) (database.PlaidWebhookRecord, error) {
+					return database.PlaidWebhookRecord{}, nil
+				},
+				GetEnabledNotificationRulesFunc: func(ctx context.Context, arg database.GetEnabledNotificationRulesParams) ([]database.NotificationRule, error) {
+					if arg.Kind != notify.KindItemReauth {
+						return nil, nil
+					}
+					return []database.NotificationRule{{ID: testRuleID, UserID: testUserID, Kind: arg.Kind, Channel: notify.ChannelEmail}}, nil
+				},
+				CreateNotificationDeliveryFunc: func(ctx context.Context, arg database.CreateNotificationDeliveryParams) error {
+					queued = append(queued, arg)
+					return nil
+				},
+			}
+
+			req := httptest.NewRequest("POST", "/api/plaid-webhook", bytes.NewBufferString(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db: mockDb,
+				Auth: &mockAuthService{
+					VerifyPlaidJWTFunc: func(p auth.PlaidKeyFetcher, ctx context.Context, tokenString string) error {
+						return nil
+					},
+				},
+				PService: &mockPlaidService{
+					GetWebhookVerificationKeyFunc: func(ctx context.Context, keyID string) (plaid.JWKPublicKey, error) {
+						return plaid.JWKPublicKey{}, nil
+					},
+				},
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerPlaidWebhook(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			if tt.expectedQueue {
+				if assert.Len(t, queued, 1) {
+					assert.Equal(t, testRuleID, queued[0].RuleID)
+					assert.True(t, strings.HasPrefix(queued[0].EventKey, "item:12345:PENDING_EXPIRATION:"))
+				}
+			} else {
+				assert.Empty(t, queued)
+			}
+		})
+	}
+}
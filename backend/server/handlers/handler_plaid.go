@@ -6,12 +6,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/api/plaidservice"
 	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/events"
 	"github.com/jms-guy/greed/models"
 	"github.com/lib/pq"
 	"github.com/plaid/plaid-go/v36/plaid"
@@ -28,7 +32,7 @@ func (app *AppServer) HandlerGetSandboxToken(w http.ResponseWriter, r *http.Requ
 
 	accessToken, err := app.PService.CreateSandboxTokenWithCustomUser(ctx)
 	if err != nil {
-		app.respondWithError(w, 500, "Service error", fmt.Errorf("error getting sandbox token: %w", err))
+		app.respondWithPlaidError(w, "Service error", fmt.Errorf("error getting sandbox token: %w", err))
 		return
 	}
 
@@ -49,6 +53,7 @@ func (app *AppServer) HandlerGetSandboxToken(w http.ResponseWriter, r *http.Requ
 		InstitutionName:       "Plaid Banking",
 		Nickname:              nickName,
 		TransactionSyncCursor: cursor,
+		CountryCode:           app.Link.DefaultCountry(),
 	}
 
 	_, err = app.Db.CreateItem(ctx, params)
@@ -71,14 +76,22 @@ func (app *AppServer) HandlerGetLinkToken(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	linkToken, err := app.PService.GetLinkToken(ctx, id.String(), app.Config.PlaidWebhookURL)
+	// The body is optional, an empty one links with the server's configured countries and products
+	reqStruct := models.LinkTokenRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&reqStruct); err != nil && !errors.Is(err, io.EOF) {
+		app.respondWithError(w, 400, "Couldn't decode JSON data", err)
+		return
+	}
+
+	options, err := app.linkOptionsForRequest(reqStruct.CountryCodes, reqStruct.Products)
 	if err != nil {
-		if plaidErr, ok := err.(plaid.GenericOpenAPIError); ok {
-			fmt.Printf("Plaid error: %s\n", string(plaidErr.Body()))
-		} else {
-			fmt.Println("Error:", err.Error())
-		}
-		app.respondWithError(w, 500, "Error getting link token from Plaid", err)
+		app.respondWithError(w, 400, err.Error(), nil)
+		return
+	}
+
+	linkToken, err := app.PService.GetLinkToken(ctx, id.String(), app.Config.PlaidWebhookURL, options)
+	if err != nil {
+		app.respondWithPlaidError(w, "Error getting link token from Plaid", err)
 		return
 	}
 
@@ -107,14 +120,21 @@ func (app *AppServer) HandlerGetLinkTokenForUpdateMode(w http.ResponseWriter, r
 		return
 	}
 
-	linkToken, err := app.PService.GetLinkTokenForUpdateMode(ctx, id.String(), accessToken, app.Config.PlaidWebhookURL)
+	item, err := app.Db.GetItemByID(ctx, chi.URLParam(r, "item-id"))
 	if err != nil {
-		if plaidErr, ok := err.(plaid.GenericOpenAPIError); ok {
-			fmt.Printf("Plaid error: %s\n", string(plaidErr.Body()))
-		} else {
-			fmt.Println("Error:", err.Error())
-		}
-		app.respondWithError(w, 500, "Service error", fmt.Errorf("error getting link token: %w", err))
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting item record: %w", err))
+		return
+	}
+
+	// Update mode has to use the country the item was linked in, even if the server no longer offers it
+	options := app.Link
+	if item.CountryCode != "" {
+		options.CountryCodes = []plaid.CountryCode{plaid.CountryCode(item.CountryCode)}
+	}
+
+	linkToken, err := app.PService.GetLinkTokenForUpdateMode(ctx, id.String(), accessToken, app.Config.PlaidWebhookURL, options)
+	if err != nil {
+		app.respondWithPlaidError(w, "Service error", fmt.Errorf("error getting link token: %w", err))
 		return
 	}
 
@@ -125,6 +145,21 @@ func (app *AppServer) HandlerGetLinkTokenForUpdateMode(w http.ResponseWriter, r
 	app.respondWithJSON(w, 200, response)
 }
 
+// Narrows the server's Link options to the countries and products named in a request
+func (app *AppServer) linkOptionsForRequest(countryCodes, products []string) (plaidservice.LinkOptions, error) {
+	countries, err := plaidservice.ParseCountryCodes(strings.Join(countryCodes, ","))
+	if err != nil {
+		return plaidservice.LinkOptions{}, err
+	}
+
+	requested, err := plaidservice.ParseProducts(strings.Join(products, ","))
+	if err != nil {
+		return plaidservice.LinkOptions{}, err
+	}
+
+	return app.Link.WithRequest(countries, requested)
+}
+
 // Exchanges a received public token with an access token, and stores the Plaid item in database
 func (app *AppServer) HandlerGetAccessToken(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -142,9 +177,15 @@ func (app *AppServer) HandlerGetAccessToken(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	accessToken, err := app.PService.GetAccessToken(ctx, reqStruct.PublicToken)
+	options, err := app.linkOptionsForRequest(reqStruct.CountryCodes, nil)
 	if err != nil {
-		app.respondWithError(w, 500, fmt.Sprintf("Error getting access token, Plaid request ID: %s", accessToken.RequestID), fmt.Errorf("reqID: %s, err: %w", accessToken.RequestID, err))
+		app.respondWithError(w, 400, err.Error(), nil)
+		return
+	}
+
+	accessToken, err := app.PService.GetAccessToken(ctx, reqStruct.PublicToken, options.CountryCodes)
+	if err != nil {
+		app.respondWithPlaidError(w, fmt.Sprintf("Error getting access token, Plaid request ID: %s", accessToken.RequestID), fmt.Errorf("reqID: %s, err: %w", accessToken.RequestID, err))
 		return
 	}
 
@@ -169,6 +210,7 @@ func (app *AppServer) HandlerGetAccessToken(w http.ResponseWriter, r *http.Reque
 		InstitutionName:       accessToken.InstitutionName,
 		Nickname:              nickName,
 		TransactionSyncCursor: cursor,
+		CountryCode:           accessToken.CountryCode,
 	}
 	_, err = app.Db.CreateItem(ctx, params)
 	if err != nil {
@@ -200,7 +242,8 @@ func (app *AppServer) HandlerCreateAccounts(w http.ResponseWriter, r *http.Reque
 
 	accounts, reqID, err := app.PService.GetAccounts(ctx, accessToken)
 	if err != nil {
-		app.respondWithError(w, 500, "Service Error", fmt.Errorf("plaid request id: %s, error getting accounts from Plaid: %w", reqID, err))
+		app.recordItemPlaidError(ctx, itemID, err)
+		app.respondWithPlaidError(w, "Service Error", fmt.Errorf("plaid request id: %s, error getting accounts from Plaid: %w", reqID, err))
 		return
 	}
 
@@ -254,7 +297,7 @@ func (app *AppServer) HandlerCreateAccounts(w http.ResponseWriter, r *http.Reque
 			AvailableBalance: accBalAvail,
 			CurrentBalance:   accBalCur,
 			IsoCurrencyCode:  curCode,
-			ItemID:           itemID,
+			ItemID:           sql.NullString{String: itemID, Valid: true},
 			UserID:           id,
 		}
 
@@ -312,7 +355,8 @@ func (app *AppServer) HandlerSyncTransactions(w http.ResponseWriter, r *http.Req
 
 	added, modified, removed, nextCursor, reqID, err := app.PService.GetTransactions(ctx, accessToken, cursor.String)
 	if err != nil {
-		app.respondWithError(w, 500, "Service error", fmt.Errorf("plaid request id: %s, error getting transaction data: %w", reqID, err))
+		app.recordItemPlaidError(ctx, itemID, err)
+		app.respondWithPlaidError(w, "Service error", fmt.Errorf("plaid request id: %s, error getting transaction data: %w", reqID, err))
 		return
 	}
 
@@ -324,11 +368,12 @@ func (app *AppServer) HandlerSyncTransactions(w http.ResponseWriter, r *http.Req
 
 	recurring, err := app.PService.GetRecurring(ctx, accessToken)
 	if err != nil {
-		app.respondWithError(w, 500, "Service error", fmt.Errorf("error getting recurring transaction data: %w", err))
+		app.recordItemPlaidError(ctx, itemID, err)
+		app.respondWithPlaidError(w, "Service error", fmt.Errorf("error getting recurring transaction data: %w", err))
 		return
 	}
 
-	err = app.tagRecurringTransactions(ctx, recurring)
+	newStreams, err := app.tagRecurringTransactions(ctx, recurring)
 	if err != nil {
 		app.respondWithError(w, 500, "Database error", err)
 		return
@@ -346,6 +391,15 @@ func (app *AppServer) HandlerSyncTransactions(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Transactions from an item's first sync are its history, so only later syncs are scored
+	if cursor.String != "" {
+		app.detectAnomalies(ctx, item, added, txns)
+		app.notifyLargeTransactions(ctx, item.UserID, added)
+		app.notifyNewRecurring(ctx, item.UserID, newStreams)
+	}
+	app.notifyBudgets(ctx, item.UserID)
+	app.recordItemSynced(ctx, item.UserID, itemID)
+
 	var response []models.Transaction
 	for _, t := range txns {
 		newT := models.Transaction{
@@ -377,12 +431,8 @@ func (app *AppServer) HandlerUpdateBalances(w http.ResponseWriter, r *http.Reque
 
 	accs, reqID, err := app.PService.GetBalances(ctx, accessToken)
 	if err != nil {
-		if plaidErr, ok := err.(plaid.GenericOpenAPIError); ok {
-			fmt.Printf("Plaid error: %s\n", string(plaidErr.Body()))
-		} else {
-			fmt.Println("Error:", err.Error())
-		}
-		app.respondWithError(w, 500, "Service error", fmt.Errorf("plaid request id: %s, error getting updated account balances: %w", reqID, err))
+		app.recordItemPlaidError(ctx, chi.URLParam(r, "item-id"), err)
+		app.respondWithPlaidError(w, "Service error", fmt.Errorf("plaid request id: %s, error getting updated account balances: %w", reqID, err))
 		return
 	}
 
@@ -431,22 +481,30 @@ func (app *AppServer) HandlerUpdateBalances(w http.ResponseWriter, r *http.Reque
 		responseAccounts.Accounts = append(responseAccounts.Accounts, updatedRecord)
 	}
 
+	if userID, ok := ctx.Value(userIDKey).(uuid.UUID); ok {
+		for _, acc := range responseAccounts.Accounts {
+			app.recordEvent(ctx, userID, events.BalanceUpdated, acc)
+		}
+		app.notifyLowBalances(ctx, userID, accs.Accounts)
+		app.recordConsentExpiration(ctx, userID, accs.Item.ItemId, accs.Item.ConsentExpirationTime.Get())
+	}
+
 	app.respondWithJSON(w, 200, responseAccounts)
 }
 
-// Takes Plaid recurring transaction streams and tags the necessary transactions for user perusing
-func (app *AppServer) tagRecurringTransactions(ctx context.Context, recurring plaid.TransactionsRecurringGetResponse) error {
-	if err := app.processStreams(ctx, recurring.InflowStreams, "in"); err != nil {
-		return err
-	}
-	if err := app.processStreams(ctx, recurring.OutflowStreams, "out"); err != nil {
-		return err
+// Takes Plaid recurring transaction streams and tags the necessary transactions for user perusing.
+// Returns the outflow streams that had not been seen before
+func (app *AppServer) tagRecurringTransactions(ctx context.Context, recurring plaid.TransactionsRecurringGetResponse) ([]plaid.TransactionStream, error) {
+	if _, err := app.processStreams(ctx, recurring.InflowStreams, "in"); err != nil {
+		return nil, err
 	}
-	return nil
+	return app.processStreams(ctx, recurring.OutflowStreams, "out")
 }
 
-// Processes Plaid's recurring stream slices, creating proper database records
-func (app *AppServer) processStreams(ctx context.Context, streams []plaid.TransactionStream, streamType string) error {
+// Processes Plaid's recurring stream slices, creating proper database records. Returns the streams
+// that were newly created
+func (app *AppServer) processStreams(ctx context.Context, streams []plaid.TransactionStream, streamType string) ([]plaid.TransactionStream, error) {
+	var created []plaid.TransactionStream
 	for _, stream := range streams {
 		params := database.CreateStreamParams{
 			ID:                stream.StreamId,
@@ -465,9 +523,10 @@ func (app *AppServer) processStreams(ctx context.Context, streams []plaid.Transa
 				// Transaction already linked to this stream, skip
 				continue
 			} else {
-				return fmt.Errorf("error creating recurring stream record: %w", err)
+				return nil, fmt.Errorf("error creating recurring stream record: %w", err)
 			}
 		}
+		created = append(created, stream)
 
 		for _, transactionID := range stream.TransactionIds {
 			params := database.CreateTransactionToStreamRecordParams{
@@ -481,11 +540,11 @@ func (app *AppServer) processStreams(ctx context.Context, streams []plaid.Transa
 					// Transaction already linked to this stream, skip
 					continue
 				} else {
-					return fmt.Errorf("error creating transaction-to-stream record: %w", err)
+					return nil, fmt.Errorf("error creating transaction-to-stream record: %w", err)
 				}
 			}
 		}
 	}
 
-	return nil
+	return created, nil
 }
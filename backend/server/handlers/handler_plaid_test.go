@@ -13,6 +13,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/api/plaidservice"
 	"github.com/jms-guy/greed/backend/internal/config"
 	"github.com/jms-guy/greed/backend/internal/database"
 	"github.com/jms-guy/greed/backend/server/handlers"
@@ -36,7 +37,7 @@ func TestHandlerGetLinkToken(t *testing.T) {
 			userIDInContext: testUserID,
 			mockDb:          &mockDatabaseService{},
 			mockPS: &mockPlaidService{
-				GetLinkTokenFunc: func(ctx context.Context, userID, webhookURL string) (string, error) {
+				GetLinkTokenFunc: func(ctx context.Context, userID, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 					return "link_token", nil
 				},
 			},
@@ -48,7 +49,7 @@ func TestHandlerGetLinkToken(t *testing.T) {
 			userIDInContext: uuid.Nil,
 			mockDb:          &mockDatabaseService{},
 			mockPS: &mockPlaidService{
-				GetLinkTokenFunc: func(ctx context.Context, userID, webhookURL string) (string, error) {
+				GetLinkTokenFunc: func(ctx context.Context, userID, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 					return "link_token", nil
 				},
 			},
@@ -60,13 +61,59 @@ func TestHandlerGetLinkToken(t *testing.T) {
 			userIDInContext: testUserID,
 			mockDb:          &mockDatabaseService{},
 			mockPS: &mockPlaidService{
-				GetLinkTokenFunc: func(ctx context.Context, userID, webhookURL string) (string, error) {
+				GetLinkTokenFunc: func(ctx context.Context, userID, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 					return "link_token", fmt.Errorf("mock error")
 				},
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "Error getting link token from Plaid",
 		},
+		{
+			name:            "should narrow link options to requested country and products",
+			userIDInContext: testUserID,
+			requestBody:     `{"country_codes": ["us"], "products": ["transactions", "liabilities"]}`,
+			mockDb:          &mockDatabaseService{},
+			mockPS: &mockPlaidService{
+				GetLinkTokenFunc: func(ctx context.Context, userID, webhookURL string, options plaidservice.LinkOptions) (string, error) {
+					if len(options.CountryCodes) != 1 || options.CountryCodes[0] != plaid.COUNTRYCODE_US {
+						return "", fmt.Errorf("unexpected countries: %v", options.CountryCodes)
+					}
+					if len(options.Products) != 2 || options.Products[1] != plaid.PRODUCTS_LIABILITIES {
+						return "", fmt.Errorf("unexpected products: %v", options.Products)
+					}
+					return "link_token", nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "link_token",
+		},
+		{
+			name:            "should err on country not enabled on server",
+			userIDInContext: testUserID,
+			requestBody:     `{"country_codes": ["GB"]}`,
+			mockDb:          &mockDatabaseService{},
+			mockPS:          &mockPlaidService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "country code GB is not enabled on this server",
+		},
+		{
+			name:            "should err on invalid product",
+			userIDInContext: testUserID,
+			requestBody:     `{"products": ["mortgages"]}`,
+			mockDb:          &mockDatabaseService{},
+			mockPS:          &mockPlaidService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "invalid Plaid product: mortgages",
+		},
+		{
+			name:            "should err on decoding request",
+			userIDInContext: testUserID,
+			requestBody:     `{"country_codes": "US"`,
+			mockDb:          &mockDatabaseService{},
+			mockPS:          &mockPlaidService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Couldn't decode JSON data",
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,6 +132,7 @@ func TestHandlerGetLinkToken(t *testing.T) {
 				PService: tt.mockPS,
 				Logger:   kitlog.NewNopLogger(),
 				Config:   &config.Config{PlaidWebhookURL: ""},
+				Link:     testLinkOptions(t),
 			}
 
 			mockApp.HandlerGetLinkToken(rr, req)
@@ -118,7 +166,7 @@ func TestHandlerGetLinkTokenForUpdateMode(t *testing.T) {
 			accessTokenInContext: testAccessToken,
 			mockDb:               &mockDatabaseService{},
 			mockPS: &mockPlaidService{
-				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string) (string, error) {
+				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 					return "link_token", nil
 				},
 			},
@@ -131,7 +179,7 @@ func TestHandlerGetLinkTokenForUpdateMode(t *testing.T) {
 			accessTokenInContext: testAccessToken,
 			mockDb:               &mockDatabaseService{},
 			mockPS: &mockPlaidService{
-				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string) (string, error) {
+				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 					return "link_token", nil
 				},
 			},
@@ -144,7 +192,7 @@ func TestHandlerGetLinkTokenForUpdateMode(t *testing.T) {
 			accessTokenInContext: 1,
 			mockDb:               &mockDatabaseService{},
 			mockPS: &mockPlaidService{
-				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string) (string, error) {
+				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 					return "link_token", nil
 				},
 			},
@@ -157,13 +205,46 @@ func TestHandlerGetLinkTokenForUpdateMode(t *testing.T) {
 			accessTokenInContext: testAccessToken,
 			mockDb:               &mockDatabaseService{},
 			mockPS: &mockPlaidService{
-				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string) (string, error) {
+				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 					return "link_token", fmt.Errorf("mock error")
 				},
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "Service error",
 		},
+		{
+			name:                 "should use the country the item was linked in",
+			userIDInContext:      testUserID,
+			accessTokenInContext: testAccessToken,
+			mockDb: &mockDatabaseService{
+				GetItemByIDFunc: func(ctx context.Context, id string) (database.PlaidItem, error) {
+					return database.PlaidItem{ID: testItemID, CountryCode: "US"}, nil
+				},
+			},
+			mockPS: &mockPlaidService{
+				GetLinkTokenForUpdateModeFunc: func(ctx context.Context, userID, accessToken, webhookURL string, options plaidservice.LinkOptions) (string, error) {
+					if len(options.CountryCodes) != 1 || options.CountryCodes[0] != plaid.COUNTRYCODE_US {
+						return "", fmt.Errorf("unexpected countries: %v", options.CountryCodes)
+					}
+					return "link_token", nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "link_token",
+		},
+		{
+			name:                 "should err on getting item record",
+			userIDInContext:      testUserID,
+			accessTokenInContext: testAccessToken,
+			mockDb: &mockDatabaseService{
+				GetItemByIDFunc: func(ctx context.Context, id string) (database.PlaidItem, error) {
+					return database.PlaidItem{}, fmt.Errorf("mock error")
+				},
+			},
+			mockPS:         &mockPlaidService{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
 	}
 
 	for _, tt := range tests {
@@ -183,6 +264,7 @@ func TestHandlerGetLinkTokenForUpdateMode(t *testing.T) {
 				PService: tt.mockPS,
 				Logger:   kitlog.NewNopLogger(),
 				Config:   &config.Config{PlaidWebhookURL: ""},
+				Link:     testLinkOptions(t),
 			}
 
 			mockApp.HandlerGetLinkTokenForUpdateMode(rr, req)
@@ -220,7 +302,7 @@ func TestHandlerGetAccessToken(t *testing.T) {
 				},
 			},
 			mockPS: &mockPlaidService{
-				GetAccessTokenFunc: func(ctx context.Context, publicToken string) (models.AccessResponse, error) {
+				GetAccessTokenFunc: func(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
 					return models.AccessResponse{AccessToken: testAccessToken, ItemID: testItemID, InstitutionName: "testing", RequestID: "1"}, nil
 				},
 			},
@@ -242,7 +324,7 @@ func TestHandlerGetAccessToken(t *testing.T) {
 				},
 			},
 			mockPS: &mockPlaidService{
-				GetAccessTokenFunc: func(ctx context.Context, publicToken string) (models.AccessResponse, error) {
+				GetAccessTokenFunc: func(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
 					return models.AccessResponse{AccessToken: testAccessToken, ItemID: testItemID, InstitutionName: "testing", RequestID: "1"}, nil
 				},
 			},
@@ -254,6 +336,41 @@ func TestHandlerGetAccessToken(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "Bad userID in context",
 		},
+		{
+			name:            "should store the item's country",
+			userIDInContext: testUserID,
+			requestBody:     `{"public_token": "testToken", "nickname": "testName", "country_codes": ["US"]}`,
+			mockDb: &mockDatabaseService{
+				CreateItemFunc: func(ctx context.Context, arg database.CreateItemParams) (database.PlaidItem, error) {
+					if arg.CountryCode != "US" {
+						return database.PlaidItem{}, fmt.Errorf("unexpected country: %s", arg.CountryCode)
+					}
+					return database.PlaidItem{}, nil
+				},
+			},
+			mockPS: &mockPlaidService{
+				GetAccessTokenFunc: func(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
+					return models.AccessResponse{AccessToken: testAccessToken, ItemID: testItemID, InstitutionName: "testing", CountryCode: string(countryCodes[0]), RequestID: "1"}, nil
+				},
+			},
+			mockEncryptor: &mockEncryptor{
+				EncryptAccessTokenFunc: func(plaintext []byte, keyString string) (string, error) {
+					return "encrypted", nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "Item created",
+		},
+		{
+			name:            "should err on country not enabled on server",
+			userIDInContext: testUserID,
+			requestBody:     `{"public_token": "testToken", "country_codes": ["GB"]}`,
+			mockDb:          &mockDatabaseService{},
+			mockPS:          &mockPlaidService{},
+			mockEncryptor:   &mockEncryptor{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "country code GB is not enabled on this server",
+		},
 		{
 			name:            "should err on decoding request",
 			userIDInContext: testUserID,
@@ -264,7 +381,7 @@ func TestHandlerGetAccessToken(t *testing.T) {
 				},
 			},
 			mockPS: &mockPlaidService{
-				GetAccessTokenFunc: func(ctx context.Context, publicToken string) (models.AccessResponse, error) {
+				GetAccessTokenFunc: func(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
 					return models.AccessResponse{AccessToken: testAccessToken, ItemID: testItemID, InstitutionName: "testing", RequestID: "1"}, nil
 				},
 			},
@@ -286,7 +403,7 @@ func TestHandlerGetAccessToken(t *testing.T) {
 				},
 			},
 			mockPS: &mockPlaidService{
-				GetAccessTokenFunc: func(ctx context.Context, publicToken string) (models.AccessResponse, error) {
+				GetAccessTokenFunc: func(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
 					return models.AccessResponse{AccessToken: testAccessToken, ItemID: testItemID, InstitutionName: "testing", RequestID: "1"}, fmt.Errorf("mock error")
 				},
 			},
@@ -308,7 +425,7 @@ func TestHandlerGetAccessToken(t *testing.T) {
 				},
 			},
 			mockPS: &mockPlaidService{
-				GetAccessTokenFunc: func(ctx context.Context, publicToken string) (models.AccessResponse, error) {
+				GetAccessTokenFunc: func(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
 					return models.AccessResponse{AccessToken: testAccessToken, ItemID: testItemID, InstitutionName: "testing", RequestID: "1"}, nil
 				},
 			},
@@ -330,7 +447,7 @@ func TestHandlerGetAccessToken(t *testing.T) {
 				},
 			},
 			mockPS: &mockPlaidService{
-				GetAccessTokenFunc: func(ctx context.Context, publicToken string) (models.AccessResponse, error) {
+				GetAccessTokenFunc: func(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
 					return models.AccessResponse{AccessToken: testAccessToken, ItemID: testItemID, InstitutionName: "testing", RequestID: "1"}, nil
 				},
 			},
@@ -361,6 +478,7 @@ func TestHandlerGetAccessToken(t *testing.T) {
 				Config:    &config.Config{AESKey: "12345"},
 				Logger:    kitlog.NewNopLogger(),
 				Encryptor: tt.mockEncryptor,
+				Link:      testLinkOptions(t),
 			}
 
 			mockApp.HandlerGetAccessToken(rr, req)
@@ -558,6 +676,42 @@ func TestHandlerSyncTransactions(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   testAccountID,
 		},
+		{
+			name:           "should sync when recording anomalies fails",
+			tokenInContext: testAccessToken,
+			pathParams:     map[string]string{"item-id": testItemID},
+			mockDb: &mockDatabaseService{
+				GetCursorFunc: func(ctx context.Context, arg database.GetCursorParams) (sql.NullString, error) {
+					return sql.NullString{String: "cursor", Valid: true}, nil
+				},
+				GetItemByIDFunc: func(ctx context.Context, id string) (database.PlaidItem, error) {
+					return database.PlaidItem{ID: testItemID}, nil
+				},
+				GetTransactionsForUserFunc: func(ctx context.Context, userID uuid.UUID) ([]database.Transaction, error) {
+					return []database.Transaction{{AccountID: testAccountID}}, nil
+				},
+				GetAccountsForItemFunc: func(ctx context.Context, itemID string) ([]database.Account, error) {
+					return []database.Account{{ID: testAccountID, IsoCurrencyCode: sql.NullString{String: "CAD", Valid: true}}}, nil
+				},
+				CreateAnomalyFunc: func(ctx context.Context, arg database.CreateAnomalyParams) error {
+					return fmt.Errorf("mock error")
+				},
+			},
+			mockPS: &mockPlaidService{
+				GetTransactionsFunc: func(ctx context.Context, accessToken string, cursor string) (added []plaid.Transaction, modified []plaid.Transaction, removed []plaid.RemovedTransaction, nextCursor string, reqID string, err error) {
+					currency := "USD"
+					txn := plaid.Transaction{AccountId: testAccountID, Amount: 25, Date: "2025-03-01", IsoCurrencyCode: *plaid.NewNullableString(&currency)}
+					return []plaid.Transaction{txn}, []plaid.Transaction{}, []plaid.RemovedTransaction{}, "next_cursor", "requestID", nil
+				},
+			},
+			mockTxnUpdater: &mockTxnUpdaterService{
+				ApplyTransactionUpdatesFunc: func(ctx context.Context, added []plaid.Transaction, modified []plaid.Transaction, removed []plaid.RemovedTransaction, nextCursor string, itemID string) error {
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   testAccountID,
+		},
 		{
 			name:           "should err with bad access token in context",
 			tokenInContext: 1,
@@ -882,3 +1036,13 @@ func TestHandlerUpdateBalances(t *testing.T) {
 		})
 	}
 }
+
+// Link options matching a server configured for Canadian and US members
+func testLinkOptions(t *testing.T) plaidservice.LinkOptions {
+	t.Helper()
+	options, err := plaidservice.NewLinkOptions("CA,US", "transactions", "investments,liabilities", "depository:all;loan:all", 730)
+	if err != nil {
+		t.Fatalf("error building link options: %v", err)
+	}
+	return options
+}
@@ -38,7 +38,7 @@ func (app *AppServer) HandlerRefreshToken(w http.ResponseWriter, r *http.Request
 			app.respondWithError(w, 500, "Database error", fmt.Errorf("error expiring delegation token: %w", err))
 			return
 		}
-		app.respondWithError(w, 401, "Token is expired", nil)
+		app.respondWithErrorCode(w, 401, models.ErrCodeTokenExpired, "Token is expired", nil)
 		return
 	}
 	if token.IsUsed {
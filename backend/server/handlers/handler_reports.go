@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+)
+
+// Key used for transactions without a merchant name
+const unknownMerchant = "Unknown"
+
+// Range of dates, start inclusive and end exclusive
+type dateRange struct {
+	start time.Time
+	end   time.Time
+}
+
+// Net spending for a category and merchant pair within a date range
+type spendingRow struct {
+	category string
+	merchant string
+	total    float64
+}
+
+// Handler compares spending on an account between two periods, by category and by merchant
+func (app *AppServer) HandlerCompareAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accValue := ctx.Value(accountKey)
+	acc, ok := accValue.(database.Account)
+	if !ok {
+		app.respondWithError(w, 400, "Bad account in context", nil)
+		return
+	}
+
+	if acc.Type != "depository" && acc.Type != "credit" {
+		app.respondWithError(w, 400, "Endpoint only works with debit or credit accounts", nil)
+		return
+	}
+
+	current, against, err := parseComparisonRanges(r.URL.Query())
+	if err != nil {
+		app.respondWithError(w, 400, fmt.Sprintf("Bad query parameter: %s", err), nil)
+		return
+	}
+
+	getBreakdown := func(period dateRange) ([]spendingRow, error) {
+		rows, err := app.Db.GetSpendingBreakdown(ctx, database.GetSpendingBreakdownParams{
+			AccountID: acc.ID,
+			StartDate: sql.NullTime{Time: period.start, Valid: true},
+			EndDate:   sql.NullTime{Time: period.end, Valid: true},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var spending []spendingRow
+		for _, row := range rows {
+			spending = append(spending, spendingRow{category: row.Category, merchant: row.Merchant, total: row.TotalAmount})
+		}
+		return spending, nil
+	}
+
+	currentRows, err := getBreakdown(current)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting spending breakdown: %w", err))
+		return
+	}
+	againstRows, err := getBreakdown(against)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting spending breakdown: %w", err))
+		return
+	}
+
+	report := buildComparisonReport(current, against, currentRows, againstRows)
+	report.AccountID = acc.ID
+
+	app.respondWithJSON(w, 200, report)
+}
+
+// Handler compares spending across all of a user's credit, debit and manual accounts between two periods.
+// Like the account listing, hidden and archived accounts are left out unless include_hidden=true is given
+func (app *AppServer) HandlerCompareAccountsForUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	current, against, err := parseComparisonRanges(r.URL.Query())
+	if err != nil {
+		app.respondWithError(w, 400, fmt.Sprintf("Bad query parameter: %s", err), nil)
+		return
+	}
+
+	includeHidden := r.URL.Query().Get("include_hidden") == "true"
+
+	getBreakdown := func(period dateRange) ([]spendingRow, error) {
+		rows, err := app.Db.GetUserSpendingBreakdown(ctx, database.GetUserSpendingBreakdownParams{
+			UserID:        id,
+			StartDate:     sql.NullTime{Time: period.start, Valid: true},
+			EndDate:       sql.NullTime{Time: period.end, Valid: true},
+			IncludeHidden: includeHidden,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var spending []spendingRow
+		for _, row := range rows {
+			spending = append(spending, spendingRow{category: row.Category, merchant: row.Merchant, total: row.TotalAmount})
+		}
+		return spending, nil
+	}
+
+	currentRows, err := getBreakdown(current)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting spending breakdown: %w", err))
+		return
+	}
+	againstRows, err := getBreakdown(against)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting spending breakdown: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, buildComparisonReport(current, against, currentRows, againstRows))
+}
+
+// Parses the two periods to compare from query parameters. Either "period" is given as a month (2025-03)
+// or year (2025), with "against" optional and defaulting to the same period a year earlier, or all of
+// "start", "end", "against_start" and "against_end" are given as inclusive dates (2025-03-01)
+func parseComparisonRanges(query url.Values) (dateRange, dateRange, error) {
+	var current, against dateRange
+
+	if period := query.Get("period"); period != "" {
+		var err error
+		current, err = parsePeriod(period)
+		if err != nil {
+			return current, against, fmt.Errorf("period: %w", err)
+		}
+
+		if a := query.Get("against"); a != "" {
+			against, err = parsePeriod(a)
+			if err != nil {
+				return current, against, fmt.Errorf("against: %w", err)
+			}
+		} else {
+			against = dateRange{start: current.start.AddDate(-1, 0, 0), end: current.end.AddDate(-1, 0, 0)}
+		}
+
+		return current, against, nil
+	}
+
+	var err error
+	current, err = parseDates(query.Get("start"), query.Get("end"))
+	if err != nil {
+		return current, against, fmt.Errorf("start/end: %w", err)
+	}
+	against, err = parseDates(query.Get("against_start"), query.Get("against_end"))
+	if err != nil {
+		return current, against, fmt.Errorf("against_start/against_end: %w", err)
+	}
+
+	return current, against, nil
+}
+
+// Parses a month (2006-01) or a year (2006) into the range it covers
+func parsePeriod(value string) (dateRange, error) {
+	if t, err := time.Parse("2006-01", value); err == nil {
+		return dateRange{start: t, end: t.AddDate(0, 1, 0)}, nil
+	}
+	if len(value) == 4 {
+		if t, err := time.Parse("2006", value); err == nil {
+			return dateRange{start: t, end: t.AddDate(1, 0, 0)}, nil
+		}
+	}
+	return dateRange{}, fmt.Errorf("expected YYYY-MM or YYYY, got %q", value)
+}
+
+// Parses inclusive start and end dates (2006-01-02) into a range
+func parseDates(start, end string) (dateRange, error) {
+	if start == "" || end == "" {
+		return dateRange{}, fmt.Errorf("both dates are required when period is not given")
+	}
+
+	s, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return dateRange{}, fmt.Errorf("expected YYYY-MM-DD, got %q", start)
+	}
+	e, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return dateRange{}, fmt.Errorf("expected YYYY-MM-DD, got %q", end)
+	}
+	if e.Before(s) {
+		return dateRange{}, fmt.Errorf("end date is before start date")
+	}
+
+	return dateRange{start: s, end: e.AddDate(0, 0, 1)}, nil
+}
+
+// Builds a comparison report from the spending breakdowns of both periods
+func buildComparisonReport(current, against dateRange, currentRows, againstRows []spendingRow) models.ComparisonReport {
+	categories := map[string][2]float64{}
+	merchants := map[string][2]float64{}
+	var total [2]float64
+
+	add := func(rows []spendingRow, i int) {
+		for _, row := range rows {
+			merchant := row.merchant
+			if merchant == "" {
+				merchant = unknownMerchant
+			}
+
+			c := categories[row.category]
+			c[i] += row.total
+			categories[row.category] = c
+
+			m := merchants[merchant]
+			m[i] += row.total
+			merchants[merchant] = m
+
+			total[i] += row.total
+		}
+	}
+	add(currentRows, 0)
+	add(againstRows, 1)
+
+	return models.ComparisonReport{
+		Period:     formatDateRange(current),
+		Against:    formatDateRange(against),
+		Total:      comparisonRow("TOTAL", total),
+		Categories: comparisonRows(categories),
+		Merchants:  comparisonRows(merchants),
+	}
+}
+
+// Converts totals keyed by category or merchant into rows, largest change first
+func comparisonRows(totals map[string][2]float64) []models.ComparisonRow {
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		di := math.Abs(totals[keys[i]][0] - totals[keys[i]][1])
+		dj := math.Abs(totals[keys[j]][0] - totals[keys[j]][1])
+		if di != dj {
+			return di > dj
+		}
+		return keys[i] < keys[j]
+	})
+
+	rows := make([]models.ComparisonRow, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, comparisonRow(key, totals[key]))
+	}
+	return rows
+}
+
+// Builds a row from the current and previous totals
+func comparisonRow(key string, totals [2]float64) models.ComparisonRow {
+	totals = [2]float64{roundCents(totals[0]), roundCents(totals[1])}
+	delta := roundCents(totals[0] - totals[1])
+
+	row := models.ComparisonRow{
+		Key:      key,
+		Current:  strconv.FormatFloat(totals[0], 'f', 2, 64),
+		Previous: strconv.FormatFloat(totals[1], 'f', 2, 64),
+		Delta:    strconv.FormatFloat(delta, 'f', 2, 64),
+	}
+
+	if totals[1] != 0 {
+		pct := math.Round(delta/math.Abs(totals[1])*1000) / 10
+		row.DeltaPct = &pct
+	}
+
+	return row
+}
+
+// Rounds an amount to cents, without leaving a negative zero
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100)/100 + 0
+}
+
+// Formats a range with its inclusive end date
+func formatDateRange(period dateRange) models.ComparisonPeriod {
+	return models.ComparisonPeriod{
+		Start: period.start.Format("2006-01-02"),
+		End:   period.end.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+}
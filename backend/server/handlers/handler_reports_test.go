@@ -0,0 +1,274 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+)
+
+func TestHandlerCompareAccount(t *testing.T) {
+	creditAccount := database.Account{ID: testAccountID, Type: "credit"}
+
+	tests := []struct {
+		name             string
+		accountInContext any
+		queryParams      url.Values
+		mockDb           *mockDatabaseService
+		expectedStatus   int
+		expectedBody     string
+	}{
+		{
+			name:             "should compare a month against the same month a year earlier",
+			accountInContext: creditAccount,
+			queryParams:      url.Values{"period": {"2025-03"}},
+			mockDb: &mockDatabaseService{
+				GetSpendingBreakdownFunc: func(ctx context.Context, arg database.GetSpendingBreakdownParams) ([]database.GetSpendingBreakdownRow, error) {
+					if arg.StartDate.Time.Year() == 2025 {
+						return []database.GetSpendingBreakdownRow{{Category: "FOOD_AND_DRINK", Merchant: "Cafe", TxnCount: 2, TotalAmount: 150}}, nil
+					}
+					return []database.GetSpendingBreakdownRow{{Category: "FOOD_AND_DRINK", Merchant: "Cafe", TxnCount: 1, TotalAmount: 100}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"key":"FOOD_AND_DRINK","current":"150.00","previous":"100.00","delta":"50.00","delta_pct":50`,
+		},
+		{
+			name:             "should report the compared periods",
+			accountInContext: creditAccount,
+			queryParams:      url.Values{"period": {"2025-02"}, "against": {"2024-12"}},
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     `"period":{"start":"2025-02-01","end":"2025-02-28"},"against":{"start":"2024-12-01","end":"2024-12-31"}`,
+		},
+		{
+			name:             "should compare date ranges, leaving out percentage for new spending",
+			accountInContext: creditAccount,
+			queryParams: url.Values{
+				"start":         {"2025-01-01"},
+				"end":           {"2025-01-15"},
+				"against_start": {"2024-01-01"},
+				"against_end":   {"2024-01-15"},
+			},
+			mockDb: &mockDatabaseService{
+				GetSpendingBreakdownFunc: func(ctx context.Context, arg database.GetSpendingBreakdownParams) ([]database.GetSpendingBreakdownRow, error) {
+					if arg.StartDate.Time.Year() == 2025 {
+						return []database.GetSpendingBreakdownRow{{Category: "TRAVEL", TxnCount: 1, TotalAmount: 80.5}}, nil
+					}
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"merchants":[{"key":"Unknown","current":"80.50","previous":"0.00","delta":"80.50"}]`,
+		},
+		{
+			name:             "should err with bad account in context",
+			accountInContext: 1,
+			queryParams:      url.Values{"period": {"2025-03"}},
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Bad account in context",
+		},
+		{
+			name:             "should err with unsupported account type",
+			accountInContext: database.Account{ID: testAccountID, Type: "loan"},
+			queryParams:      url.Values{"period": {"2025-03"}},
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Endpoint only works",
+		},
+		{
+			name:             "should err with bad period",
+			accountInContext: creditAccount,
+			queryParams:      url.Values{"period": {"March"}},
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Bad query parameter",
+		},
+		{
+			name:             "should err with missing against dates",
+			accountInContext: creditAccount,
+			queryParams:      url.Values{"start": {"2025-01-01"}, "end": {"2025-01-31"}},
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "against_start/against_end",
+		},
+		{
+			name:             "should err with end before start",
+			accountInContext: creditAccount,
+			queryParams: url.Values{
+				"start":         {"2025-01-31"},
+				"end":           {"2025-01-01"},
+				"against_start": {"2024-01-01"},
+				"against_end":   {"2024-01-31"},
+			},
+			mockDb:         &mockDatabaseService{},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "end date is before start date",
+		},
+		{
+			name:             "should err getting breakdown from database",
+			accountInContext: creditAccount,
+			queryParams:      url.Values{"period": {"2025-03"}},
+			mockDb: &mockDatabaseService{
+				GetSpendingBreakdownFunc: func(ctx context.Context, arg database.GetSpendingBreakdownParams) ([]database.GetSpendingBreakdownRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqURL := fmt.Sprintf("/api/accounts/%s/compare?%s", testAccountID, tt.queryParams.Encode())
+
+			req := httptest.NewRequest("GET", reqURL, nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetAccountKey(), tt.accountInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerCompareAccount(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerCompareAccountsForUser(t *testing.T) {
+	tests := []struct {
+		name            string
+		userIDInContext any
+		queryParams     url.Values
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{
+			name:            "should compare spending across accounts",
+			userIDInContext: testUserID,
+			queryParams:     url.Values{"period": {"2025"}, "against": {"2024"}},
+			mockDb: &mockDatabaseService{
+				GetUserSpendingBreakdownFunc: func(ctx context.Context, arg database.GetUserSpendingBreakdownParams) ([]database.GetUserSpendingBreakdownRow, error) {
+					if arg.StartDate.Time.Year() == 2025 {
+						return []database.GetUserSpendingBreakdownRow{
+							{Category: "RENT", Merchant: "Landlord", TxnCount: 12, TotalAmount: 900},
+							{Category: "FOOD_AND_DRINK", Merchant: "Cafe", TxnCount: 3, TotalAmount: 30},
+						}, nil
+					}
+					return []database.GetUserSpendingBreakdownRow{
+						{Category: "RENT", Merchant: "Landlord", TxnCount: 12, TotalAmount: 1200},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"total":{"key":"TOTAL","current":"930.00","previous":"1200.00","delta":"-270.00","delta_pct":-22.5}`,
+		},
+		{
+			name:            "should leave out hidden accounts by default",
+			userIDInContext: testUserID,
+			queryParams:     url.Values{"period": {"2025"}},
+			mockDb: &mockDatabaseService{
+				GetUserSpendingBreakdownFunc: func(ctx context.Context, arg database.GetUserSpendingBreakdownParams) ([]database.GetUserSpendingBreakdownRow, error) {
+					if arg.IncludeHidden {
+						return nil, fmt.Errorf("hidden accounts included")
+					}
+					return []database.GetUserSpendingBreakdownRow{}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"total"`,
+		},
+		{
+			name:            "should include hidden accounts when asked",
+			userIDInContext: testUserID,
+			queryParams:     url.Values{"period": {"2025"}, "include_hidden": {"true"}},
+			mockDb: &mockDatabaseService{
+				GetUserSpendingBreakdownFunc: func(ctx context.Context, arg database.GetUserSpendingBreakdownParams) ([]database.GetUserSpendingBreakdownRow, error) {
+					if !arg.IncludeHidden {
+						return nil, fmt.Errorf("hidden accounts left out")
+					}
+					return []database.GetUserSpendingBreakdownRow{}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"total"`,
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			queryParams:     url.Values{"period": {"2025"}},
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err with bad against period",
+			userIDInContext: testUserID,
+			queryParams:     url.Values{"period": {"2025-03"}, "against": {"2024-13"}},
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "against",
+		},
+		{
+			name:            "should err getting breakdown from database",
+			userIDInContext: testUserID,
+			queryParams:     url.Values{"period": {"2025-03"}},
+			mockDb: &mockDatabaseService{
+				GetUserSpendingBreakdownFunc: func(ctx context.Context, arg database.GetUserSpendingBreakdownParams) ([]database.GetUserSpendingBreakdownRow, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/accounts/compare?"+tt.queryParams.Encode(), nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerCompareAccountsForUser(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+)
+
+// Handler gets changes to the user's transactions since a cursor, for clients keeping a local copy.
+// The "cursor" query parameter is the next_cursor of the last page applied, empty to read from the start,
+// and "count" sets how many change records a page covers, defaulting to 500
+func (app *AppServer) HandlerGetTransactionChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	id, ok := userIDValue.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var after int64
+	if c := query.Get("cursor"); c != "" {
+		var err error
+		after, err = strconv.ParseInt(c, 10, 64)
+		if err != nil || after < 0 {
+			app.respondWithError(w, 400, "Bad query parameter: cursor is not valid", nil)
+			return
+		}
+	}
+
+	count := 500
+	if c := query.Get("count"); c != "" {
+		var err error
+		count, err = strconv.Atoi(c)
+		if err != nil || count < 1 || count > 1000 {
+			app.respondWithError(w, 400, "Bad query parameter: count must be between 1 and 1000", nil)
+			return
+		}
+	}
+
+	// One record past the page tells whether there are more
+	changes, err := app.Db.GetTransactionChanges(ctx, database.GetTransactionChangesParams{
+		UserID:   id,
+		AfterSeq: after,
+		RowLimit: int32(count + 1),
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting transaction changes: %w", err))
+		return
+	}
+
+	response := models.TransactionChanges{
+		Added:      []models.Transaction{},
+		Modified:   []models.Transaction{},
+		Removed:    []models.RemovedTransaction{},
+		NextCursor: strconv.FormatInt(after, 10),
+	}
+	if len(changes) > count {
+		changes = changes[:count]
+		response.HasMore = true
+	}
+	if len(changes) == 0 {
+		app.respondWithJSON(w, 200, response)
+		return
+	}
+	response.NextCursor = strconv.FormatInt(changes[len(changes)-1].Seq, 10)
+
+	folded := foldTransactionChanges(changes)
+
+	ids := []string{}
+	for _, f := range folded {
+		if f.changeType != changeRemoved {
+			ids = append(ids, f.transactionID)
+		}
+	}
+
+	current := map[string]database.Transaction{}
+	if len(ids) > 0 {
+		txns, err := app.Db.GetTransactionsByIDs(ctx, database.GetTransactionsByIDsParams{UserID: id, Ids: ids})
+		if err != nil {
+			app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting changed transactions: %w", err))
+			return
+		}
+		for _, t := range txns {
+			current[t.ID] = t
+		}
+	}
+
+	for _, f := range folded {
+		t, exists := current[f.transactionID]
+		// Transactions deleted along with their account or item are no longer there to return
+		if f.changeType == changeRemoved || !exists {
+			response.Removed = append(response.Removed, models.RemovedTransaction{Id: f.transactionID, AccountId: f.accountID})
+			continue
+		}
+
+		txn := models.Transaction{
+			Id:                      t.ID,
+			AccountId:               t.AccountID,
+			Amount:                  t.Amount,
+			IsoCurrencyCode:         t.IsoCurrencyCode.String,
+			Date:                    t.Date.Time,
+			MerchantName:            t.MerchantName.String,
+			PaymentChannel:          t.PaymentChannel,
+			PersonalFinanceCategory: t.PersonalFinanceCategory,
+		}
+		if f.changeType == changeAdded {
+			response.Added = append(response.Added, txn)
+		} else {
+			response.Modified = append(response.Modified, txn)
+		}
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
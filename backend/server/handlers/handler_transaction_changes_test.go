@@ -0,0 +1,178 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/jms-guy/greed/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerGetTransactionChanges(t *testing.T) {
+	change := func(seq int64, txnID, changeType string) database.TransactionChange {
+		return database.TransactionChange{Seq: seq, UserID: testUserID, TransactionID: txnID, AccountID: testAccountID, ChangeType: changeType}
+	}
+	txn := func(id, category string) database.Transaction {
+		return database.Transaction{ID: id, AccountID: testAccountID, Amount: "12.50", PaymentChannel: "online", PersonalFinanceCategory: category}
+	}
+
+	tests := []struct {
+		name            string
+		userIDInContext any
+		query           string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+		expected        *models.TransactionChanges
+	}{
+		{
+			name:            "should fold changes into one per transaction",
+			userIDInContext: testUserID,
+			query:           "?cursor=10&count=5",
+			mockDb: &mockDatabaseService{
+				GetTransactionChangesFunc: func(ctx context.Context, arg database.GetTransactionChangesParams) ([]database.TransactionChange, error) {
+					if arg.AfterSeq != 10 || arg.RowLimit != 6 {
+						return nil, fmt.Errorf("unexpected params: %+v", arg)
+					}
+					return []database.TransactionChange{
+						change(11, "txn-a", "added"),
+						change(12, "txn-b", "modified"),
+						change(13, "txn-a", "modified"),
+						change(14, "txn-c", "added"),
+						change(15, "txn-c", "removed"),
+						change(16, "txn-d", "added"),
+					}, nil
+				},
+				GetTransactionsByIDsFunc: func(ctx context.Context, arg database.GetTransactionsByIDsParams) ([]database.Transaction, error) {
+					if arg.UserID != testUserID || len(arg.Ids) != 2 {
+						return nil, fmt.Errorf("unexpected params: %+v", arg)
+					}
+					return []database.Transaction{txn("txn-a", "FOOD_AND_DRINK"), txn("txn-b", "TRAVEL")}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expected: &models.TransactionChanges{
+				Added:      []models.Transaction{{Id: "txn-a", AccountId: testAccountID, Amount: "12.50", PaymentChannel: "online", PersonalFinanceCategory: "FOOD_AND_DRINK"}},
+				Modified:   []models.Transaction{{Id: "txn-b", AccountId: testAccountID, Amount: "12.50", PaymentChannel: "online", PersonalFinanceCategory: "TRAVEL"}},
+				Removed:    []models.RemovedTransaction{{Id: "txn-c", AccountId: testAccountID}},
+				NextCursor: "15",
+				HasMore:    true,
+			},
+		},
+		{
+			name:            "should report transactions no longer stored as removed",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetTransactionChangesFunc: func(ctx context.Context, arg database.GetTransactionChangesParams) ([]database.TransactionChange, error) {
+					return []database.TransactionChange{change(3, "txn-a", "modified")}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expected: &models.TransactionChanges{
+				Added:      []models.Transaction{},
+				Modified:   []models.Transaction{},
+				Removed:    []models.RemovedTransaction{{Id: "txn-a", AccountId: testAccountID}},
+				NextCursor: "3",
+			},
+		},
+		{
+			name:            "should return cursor unchanged with no changes",
+			userIDInContext: testUserID,
+			query:           "?cursor=42",
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusOK,
+			expected: &models.TransactionChanges{
+				Added:      []models.Transaction{},
+				Modified:   []models.Transaction{},
+				Removed:    []models.RemovedTransaction{},
+				NextCursor: "42",
+			},
+		},
+		{
+			name:            "should err with bad cursor",
+			userIDInContext: testUserID,
+			query:           "?cursor=abc",
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "cursor is not valid",
+		},
+		{
+			name:            "should err with bad count",
+			userIDInContext: testUserID,
+			query:           "?count=0",
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "count must be between 1 and 1000",
+		},
+		{
+			name:            "should err with bad userID in context",
+			userIDInContext: uuid.Nil,
+			mockDb:          &mockDatabaseService{},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Bad userID in context",
+		},
+		{
+			name:            "should err getting changed transactions",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetTransactionChangesFunc: func(ctx context.Context, arg database.GetTransactionChangesParams) ([]database.TransactionChange, error) {
+					return []database.TransactionChange{change(3, "txn-a", "added")}, nil
+				},
+				GetTransactionsByIDsFunc: func(ctx context.Context, arg database.GetTransactionsByIDsParams) ([]database.Transaction, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+		{
+			name:            "should err getting changes",
+			userIDInContext: testUserID,
+			mockDb: &mockDatabaseService{
+				GetTransactionChangesFunc: func(ctx context.Context, arg database.GetTransactionChangesParams) ([]database.TransactionChange, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/transactions/changes"+tt.query, nil)
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerGetTransactionChanges(rr, req)
+
+			// --- Assertions ---
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+			if tt.expected != nil {
+				var got models.TransactionChanges
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+				assert.Equal(t, *tt.expected, got)
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/jms-guy/greed/backend/internal/database"
 	"github.com/jms-guy/greed/models"
 )
@@ -82,7 +84,7 @@ func (app *AppServer) HandlerGetTransactionsForAccount(w http.ResponseWriter, r
 		for _, sum := range summaries {
 			total := strconv.FormatFloat(sum.TotalAmount, 'f', 2, 64)
 			s := models.MerchantSummary{
-				Merchant:    sum.Merchant.String,
+				Merchant:    sum.Merchant,
 				TxnCount:    sum.TxnCount,
 				Category:    sum.Category,
 				TotalAmount: total,
@@ -103,7 +105,7 @@ func (app *AppServer) HandlerGetTransactionsForAccount(w http.ResponseWriter, r
 		for _, sum := range summary {
 			total := strconv.FormatFloat(sum.TotalAmount, 'f', 2, 64)
 			s := models.MerchantSummary{
-				Merchant:    sum.Merchant.String,
+				Merchant:    sum.Merchant,
 				TxnCount:    sum.TxnCount,
 				Category:    sum.Category,
 				TotalAmount: total,
@@ -268,7 +270,7 @@ func (app *AppServer) HandlerGetMonetaryData(w http.ResponseWriter, r *http.Requ
 	app.respondWithJSON(w, 200, response)
 }
 
-// Deletes all transaction records for a given account ID
+// Deletes all transaction records for a given account ID, recalculating a manual account's balance
 func (app *AppServer) HandlerDeleteTransactionsForAccount(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	accValue := ctx.Value(accountKey)
@@ -278,9 +280,9 @@ func (app *AppServer) HandlerDeleteTransactionsForAccount(w http.ResponseWriter,
 		return
 	}
 
-	err := app.Db.DeleteTransactionsForAccount(ctx, acc.ID)
+	err := app.TxnUpdater.DeleteTransactionsForAccount(ctx, acc)
 	if err != nil {
-		app.respondWithError(w, 500, "Database error", fmt.Errorf("error deleting transaction records: %w", err))
+		app.respondWithError(w, 500, "Database error", err)
 		return
 	}
 
@@ -352,3 +354,125 @@ func (app *AppServer) HandlerGetRecurringData(w http.ResponseWriter, r *http.Req
 
 	app.respondWithJSON(w, 200, recurringData)
 }
+
+// Handler re-categorizes a single transaction. The category is kept over Plaid's category on later syncs
+func (app *AppServer) HandlerUpdateTransactionCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	txnValue := ctx.Value(transactionKey)
+	txn, ok := txnValue.(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	request := models.UpdateTransactionCategory{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	category := strings.TrimSpace(request.Category)
+	if category == "" {
+		app.respondWithError(w, 400, "Category is required", nil)
+		return
+	}
+
+	updated, err := app.Db.UpdateTransactionCategory(ctx, database.UpdateTransactionCategoryParams{
+		ID:                      txn.ID,
+		PersonalFinanceCategory: strings.ToUpper(category),
+		AccountID:               txn.AccountID,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error updating transaction category: %w", err))
+		return
+	}
+
+	response := models.Transaction{
+		Id:                      updated.ID,
+		AccountId:               updated.AccountID,
+		Amount:                  updated.Amount,
+		IsoCurrencyCode:         updated.IsoCurrencyCode.String,
+		Date:                    updated.Date.Time,
+		MerchantName:            updated.MerchantName.String,
+		PaymentChannel:          updated.PaymentChannel,
+		PersonalFinanceCategory: updated.PersonalFinanceCategory,
+	}
+
+	app.respondWithJSON(w, 200, response)
+}
+
+// Handler gets the names of tags attached to a single transaction
+func (app *AppServer) HandlerGetTransactionTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	txnValue := ctx.Value(transactionKey)
+	txn, ok := txnValue.(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	tags, err := app.Db.GetTagNamesForTransaction(ctx, txn.ID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting transaction tags: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 200, models.TransactionTags{TransactionID: txn.ID, Tags: append([]string{}, tags...)})
+}
+
+// Handler attaches a tag to a single transaction, creating the user's tag if it does not exist yet
+func (app *AppServer) HandlerAddTransactionTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userIDValue := ctx.Value(userIDKey)
+	userID, ok := userIDValue.(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		app.respondWithError(w, 400, "Bad userID in context", nil)
+		return
+	}
+
+	txnValue := ctx.Value(transactionKey)
+	txn, ok := txnValue.(database.Transaction)
+	if !ok {
+		app.respondWithError(w, 400, "Bad transaction in context", nil)
+		return
+	}
+
+	request := models.AddTransactionTag{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		app.respondWithError(w, 400, "Bad request data", err)
+		return
+	}
+
+	name := strings.ToLower(strings.TrimSpace(request.Tag))
+	if name == "" {
+		app.respondWithError(w, 400, "Tag is required", nil)
+		return
+	}
+
+	tag, err := app.Db.UpsertTag(ctx, database.UpsertTagParams{
+		ID:     uuid.New(),
+		Name:   name,
+		UserID: userID,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error creating tag: %w", err))
+		return
+	}
+
+	err = app.Db.CreateTransactionToTagRecord(ctx, database.CreateTransactionToTagRecordParams{
+		TransactionID: txn.ID,
+		TagID:         tag.ID,
+	})
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error tagging transaction: %w", err))
+		return
+	}
+
+	tags, err := app.Db.GetTagNamesForTransaction(ctx, txn.ID)
+	if err != nil {
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting transaction tags: %w", err))
+		return
+	}
+
+	app.respondWithJSON(w, 201, models.TransactionTags{TransactionID: txn.ID, Tags: tags})
+}
@@ -3,7 +3,6 @@ package handlers_test
 import (
 	"bytes"
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -277,7 +276,7 @@ func TestHandlerGetTransactionsForAccount(t *testing.T) {
 					return []database.GetMerchantSummaryByMonthRow{}, nil
 				},
 				GetMerchantSummaryFunc: func(ctx context.Context, accountID string) ([]database.GetMerchantSummaryRow, error) {
-					return []database.GetMerchantSummaryRow{{Merchant: sql.NullString{String: "test", Valid: true}, TxnCount: 5, Category: "transportation", TotalAmount: 2.00, Month: "2006-01-02"}}, nil
+					return []database.GetMerchantSummaryRow{{Merchant: "test", TxnCount: 5, Category: "transportation", TotalAmount: 2.00, Month: "2006-01-02"}}, nil
 				},
 			},
 			mockQuerier: &mockQuerier{
@@ -326,7 +325,7 @@ func TestHandlerGetTransactionsForAccount(t *testing.T) {
 			queryParams:      url.Values{"summary": []string{"true"}, "date": []string{"2006-01-02"}},
 			mockDb: &mockDatabaseService{
 				GetMerchantSummaryByMonthFunc: func(ctx context.Context, arg database.GetMerchantSummaryByMonthParams) ([]database.GetMerchantSummaryByMonthRow, error) {
-					return []database.GetMerchantSummaryByMonthRow{{Merchant: sql.NullString{String: "test", Valid: true}, TxnCount: 5, Category: "transportation", TotalAmount: 2.00, Month: "2006-01-02"}}, nil
+					return []database.GetMerchantSummaryByMonthRow{{Merchant: "test", TxnCount: 5, Category: "transportation", TotalAmount: 2.00, Month: "2006-01-02"}}, nil
 				},
 				GetMerchantSummaryFunc: func(ctx context.Context, accountID string) ([]database.GetMerchantSummaryRow, error) {
 					return []database.GetMerchantSummaryRow{}, nil
@@ -730,7 +729,7 @@ func TestHandlerDeleteTransactionsForAccount(t *testing.T) {
 		accountInContext any
 		pathParams       map[string]string
 		requestBody      string
-		mockDb           *mockDatabaseService
+		mockUpdater      *mockTxnUpdaterService
 		mockAuth         *mockAuthService
 		expectedStatus   int
 		expectedBody     string
@@ -739,8 +738,23 @@ func TestHandlerDeleteTransactionsForAccount(t *testing.T) {
 			name:             "should successfully delete transactions for account",
 			accountInContext: database.Account{ID: testAccountID},
 			pathParams:       map[string]string{"account-id": testAccountID},
-			mockDb: &mockDatabaseService{
-				DeleteTransactionsForAccountFunc: func(ctx context.Context, accountID string) error {
+			mockUpdater: &mockTxnUpdaterService{
+				DeleteTransactionsForAccountFunc: func(ctx context.Context, acc database.Account) error {
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Transactions deleted successfully",
+		},
+		{
+			name:             "should pass manual accounts on for their balance to be recalculated",
+			accountInContext: database.Account{ID: testAccountID, IsManual: true},
+			pathParams:       map[string]string{"account-id": testAccountID},
+			mockUpdater: &mockTxnUpdaterService{
+				DeleteTransactionsForAccountFunc: func(ctx context.Context, acc database.Account) error {
+					if acc.ID != testAccountID || !acc.IsManual {
+						return fmt.Errorf("unexpected account: %+v", acc)
+					}
 					return nil
 				},
 			},
@@ -751,8 +765,8 @@ func TestHandlerDeleteTransactionsForAccount(t *testing.T) {
 			name:             "should err with bad account in context",
 			accountInContext: 1,
 			pathParams:       map[string]string{"account-id": testAccountID},
-			mockDb: &mockDatabaseService{
-				DeleteTransactionsForAccountFunc: func(ctx context.Context, accountID string) error {
+			mockUpdater: &mockTxnUpdaterService{
+				DeleteTransactionsForAccountFunc: func(ctx context.Context, acc database.Account) error {
 					return nil
 				},
 			},
@@ -763,8 +777,8 @@ func TestHandlerDeleteTransactionsForAccount(t *testing.T) {
 			name:             "should err deleting transactions",
 			accountInContext: database.Account{ID: testAccountID},
 			pathParams:       map[string]string{"account-id": testAccountID},
-			mockDb: &mockDatabaseService{
-				DeleteTransactionsForAccountFunc: func(ctx context.Context, accountID string) error {
+			mockUpdater: &mockTxnUpdaterService{
+				DeleteTransactionsForAccountFunc: func(ctx context.Context, acc database.Account) error {
 					return fmt.Errorf("mock error")
 				},
 			},
@@ -790,8 +804,8 @@ func TestHandlerDeleteTransactionsForAccount(t *testing.T) {
 			rr := httptest.NewRecorder()
 
 			mockApp := &handlers.AppServer{
-				Db:     tt.mockDb,
-				Logger: kitlog.NewNopLogger(),
+				TxnUpdater: tt.mockUpdater,
+				Logger:     kitlog.NewNopLogger(),
 			}
 
 			mockApp.HandlerDeleteTransactionsForAccount(rr, req)
@@ -926,3 +940,202 @@ func TestHandlerGetRecurringData(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerUpdateTransactionCategory(t *testing.T) {
+	tests := []struct {
+		name                 string
+		transactionInContext any
+		requestBody          string
+		mockDb               *mockDatabaseService
+		expectedStatus       int
+		expectedBody         string
+	}{
+		{
+			name:                 "should successfully update transaction category",
+			transactionInContext: testTransaction,
+			requestBody:          `{"category":"entertainment"}`,
+			mockDb: &mockDatabaseService{
+				UpdateTransactionCategoryFunc: func(ctx context.Context, arg database.UpdateTransactionCategoryParams) (database.Transaction, error) {
+					updated := testTransaction
+					updated.PersonalFinanceCategory = arg.PersonalFinanceCategory
+					return updated, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"personal_finance_category":"ENTERTAINMENT"`,
+		},
+		{
+			name:                 "should err with bad transaction in context",
+			transactionInContext: 1,
+			requestBody:          `{"category":"entertainment"}`,
+			mockDb:               &mockDatabaseService{},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBody:         "Bad transaction in context",
+		},
+		{
+			name:                 "should err with empty category",
+			transactionInContext: testTransaction,
+			requestBody:          `{"category":"  "}`,
+			mockDb:               &mockDatabaseService{},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBody:         "Category is required",
+		},
+		{
+			name:                 "should err with bad request data",
+			transactionInContext: testTransaction,
+			requestBody:          `{"category":`,
+			mockDb:               &mockDatabaseService{},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBody:         "Bad request data",
+		},
+		{
+			name:                 "should err updating category",
+			transactionInContext: testTransaction,
+			requestBody:          `{"category":"entertainment"}`,
+			mockDb: &mockDatabaseService{
+				UpdateTransactionCategoryFunc: func(ctx context.Context, arg database.UpdateTransactionCategoryParams) (database.Transaction, error) {
+					return database.Transaction{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqURL := fmt.Sprintf("/api/accounts/%s/transactions/%s/category", testAccountID, testTxnID)
+
+			req := httptest.NewRequest("PUT", reqURL, bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := context.WithValue(req.Context(), handlers.GetTransactionKey(), tt.transactionInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerUpdateTransactionCategory(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandlerAddTransactionTag(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userIDInContext      any
+		transactionInContext any
+		requestBody          string
+		mockDb               *mockDatabaseService
+		expectedStatus       int
+		expectedBody         string
+	}{
+		{
+			name:                 "should successfully tag transaction",
+			userIDInContext:      testUserID,
+			transactionInContext: testTransaction,
+			requestBody:          `{"tag":" Vacation "}`,
+			mockDb: &mockDatabaseService{
+				UpsertTagFunc: func(ctx context.Context, arg database.UpsertTagParams) (database.TransactionTag, error) {
+					return database.TransactionTag{ID: arg.ID, Name: arg.Name, UserID: arg.UserID}, nil
+				},
+				CreateTransactionToTagRecordFunc: func(ctx context.Context, arg database.CreateTransactionToTagRecordParams) error {
+					return nil
+				},
+				GetTagNamesForTransactionFunc: func(ctx context.Context, transactionID string) ([]string, error) {
+					return []string{"vacation"}, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"tags":["vacation"]`,
+		},
+		{
+			name:                 "should err with bad userID in context",
+			userIDInContext:      nil,
+			transactionInContext: testTransaction,
+			requestBody:          `{"tag":"vacation"}`,
+			mockDb:               &mockDatabaseService{},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBody:         "Bad userID in context",
+		},
+		{
+			name:                 "should err with empty tag",
+			userIDInContext:      testUserID,
+			transactionInContext: testTransaction,
+			requestBody:          `{"tag":""}`,
+			mockDb:               &mockDatabaseService{},
+			expectedStatus:       http.StatusBadRequest,
+			expectedBody:         "Tag is required",
+		},
+		{
+			name:                 "should err creating tag",
+			userIDInContext:      testUserID,
+			transactionInContext: testTransaction,
+			requestBody:          `{"tag":"vacation"}`,
+			mockDb: &mockDatabaseService{
+				UpsertTagFunc: func(ctx context.Context, arg database.UpsertTagParams) (database.TransactionTag, error) {
+					return database.TransactionTag{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+		{
+			name:                 "should err tagging transaction",
+			userIDInContext:      testUserID,
+			transactionInContext: testTransaction,
+			requestBody:          `{"tag":"vacation"}`,
+			mockDb: &mockDatabaseService{
+				CreateTransactionToTagRecordFunc: func(ctx context.Context, arg database.CreateTransactionToTagRecordParams) error {
+					return fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqURL := fmt.Sprintf("/api/accounts/%s/transactions/%s/tags", testAccountID, testTxnID)
+
+			req := httptest.NewRequest("POST", reqURL, bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx := context.WithValue(req.Context(), handlers.GetUserIDContextKey(), tt.userIDInContext)
+			ctx = context.WithValue(ctx, handlers.GetTransactionKey(), tt.transactionInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerAddTransactionTag(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
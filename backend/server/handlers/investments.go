@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Days of investment transaction history requested from Plaid on each sync, matching the
+// transaction history requested when an item is linked
+const investmentHistoryDays = 730
+
+// Formats a Plaid amount for a NUMERIC column, without losing precision to a fixed number of decimals
+func formatDecimal(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Converts a nullable Plaid string to its database equivalent
+func nullableString(v plaid.NullableString) sql.NullString {
+	if !v.IsSet() || v.Get() == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *v.Get(), Valid: true}
+}
+
+// Converts a nullable Plaid amount to its database equivalent
+func nullableDecimal(v plaid.NullableFloat64) sql.NullString {
+	if !v.IsSet() || v.Get() == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatDecimal(*v.Get()), Valid: true}
+}
+
+func securityParams(security plaid.Security) database.UpsertSecurityParams {
+	return database.UpsertSecurityParams{
+		ID:              security.SecurityId,
+		Name:            nullableString(security.Name),
+		TickerSymbol:    nullableString(security.TickerSymbol),
+		Type:            nullableString(security.Type),
+		ClosePrice:      nullableDecimal(security.ClosePrice),
+		IsoCurrencyCode: nullableString(security.IsoCurrencyCode),
+	}
+}
+
+func holdingParams(holding plaid.Holding) database.UpsertHoldingParams {
+	return database.UpsertHoldingParams{
+		AccountID:        holding.AccountId,
+		SecurityID:       holding.SecurityId,
+		Quantity:         formatDecimal(holding.Quantity),
+		InstitutionPrice: formatDecimal(holding.InstitutionPrice),
+		InstitutionValue: formatDecimal(holding.InstitutionValue),
+		CostBasis:        nullableDecimal(holding.CostBasis),
+		IsoCurrencyCode:  nullableString(holding.IsoCurrencyCode),
+	}
+}
+
+func investmentTransactionParams(txn plaid.InvestmentTransaction) (database.UpsertInvestmentTransactionParams, error) {
+	date, err := time.Parse("2006-01-02", txn.Date)
+	if err != nil {
+		return database.UpsertInvestmentTransactionParams{}, fmt.Errorf("error parsing date of investment transaction %s: %w", txn.InvestmentTransactionId, err)
+	}
+
+	return database.UpsertInvestmentTransactionParams{
+		ID:              txn.InvestmentTransactionId,
+		AccountID:       txn.AccountId,
+		SecurityID:      nullableString(txn.SecurityId),
+		Date:            date,
+		Name:            txn.Name,
+		Type:            string(txn.Type),
+		Subtype:         string(txn.Subtype),
+		Quantity:        formatDecimal(txn.Quantity),
+		Price:           formatDecimal(txn.Price),
+		Amount:          formatDecimal(txn.Amount),
+		Fees:            nullableDecimal(txn.Fees),
+		IsoCurrencyCode: nullableString(txn.IsoCurrencyCode),
+	}, nil
+}
+
+// Builds an account's holdings response, working out unrealized gains from each holding's value
+// and cost basis
+func holdingsResponse(accountID string, rows []database.GetHoldingsForAccountRow) models.Holdings {
+	response := models.Holdings{
+		AccountID: accountID,
+		Holdings:  []models.Holding{},
+	}
+
+	var totalValue, totalCost, totalGain float64
+	for _, row := range rows {
+		holding := models.Holding{
+			SecurityID:      row.SecurityID,
+			Name:            row.Name.String,
+			TickerSymbol:    row.TickerSymbol.String,
+			Type:            row.Type.String,
+			Quantity:        row.Quantity,
+			Price:           row.InstitutionPrice,
+			Value:           row.InstitutionValue,
+			IsoCurrencyCode: row.IsoCurrencyCode.String,
+			UpdatedAt:       row.UpdatedAt,
+		}
+
+		value, _ := strconv.ParseFloat(row.InstitutionValue, 64)
+		totalValue += value
+
+		if row.CostBasis.Valid {
+			cost, _ := strconv.ParseFloat(row.CostBasis.String, 64)
+			holding.CostBasis = row.CostBasis.String
+			holding.UnrealizedGain = fmt.Sprintf("%.2f", roundCents(value-cost))
+			totalCost += cost
+			totalGain += value - cost
+		}
+
+		response.Holdings = append(response.Holdings, holding)
+	}
+
+	response.TotalValue = fmt.Sprintf("%.2f", roundCents(totalValue))
+	response.TotalCostBasis = fmt.Sprintf("%.2f", roundCents(totalCost))
+	response.UnrealizedGain = fmt.Sprintf("%.2f", roundCents(totalGain))
+
+	return response
+}
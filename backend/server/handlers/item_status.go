@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/api/plaidservice"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+)
+
+// How long before an item's consent runs out that it is reported as expiring
+const consentExpiryWarning = 7 * 24 * time.Hour
+
+// Plaid error and webhook codes after which the user has to log in to their institution again
+var reauthCodes = map[string]bool{
+	"ITEM_LOGIN_REQUIRED":      true,
+	"PENDING_DISCONNECT":       true,
+	"USER_PERMISSION_REVOKED":  true,
+	"USER_ACCOUNT_REVOKED":     true,
+	"INVALID_CREDENTIALS":      true,
+	"INVALID_MFA":              true,
+	"INVALID_UPDATED_USERNAME": true,
+	"ITEM_LOCKED":              true,
+	"USER_SETUP_REQUIRED":      true,
+}
+
+// Item errors that only mean the item can't provide a product, which say nothing about its connection
+var productUnavailableCodes = map[string]bool{
+	plaidservice.ErrorCodeNoInvestmentAccounts:      true,
+	plaidservice.ErrorCodeNoLiabilityAccounts:       true,
+	plaidservice.ErrorCodeProductsNotSupported:      true,
+	plaidservice.ErrorCodeAdditionalConsentRequired: true,
+	"PRODUCT_NOT_READY":                             true,
+}
+
+// Works out an item's health from its status record, if it has one
+func itemHealth(status database.ItemStatus, found bool, now time.Time) string {
+	if !found {
+		return models.ItemHealthUnknown
+	}
+
+	code := status.LastErrorCode.String
+	switch {
+	case status.LastErrorCode.Valid && reauthCodes[code]:
+		return models.ItemHealthNeedsReauth
+	case status.LastErrorCode.Valid && code == "PENDING_EXPIRATION":
+		return models.ItemHealthExpiring
+	case status.LastErrorCode.Valid:
+		return models.ItemHealthError
+	case status.ConsentExpiresAt.Valid && status.ConsentExpiresAt.Time.Sub(now) < consentExpiryWarning:
+		return models.ItemHealthExpiring
+	case !status.LastSyncedAt.Valid:
+		return models.ItemHealthUnknown
+	}
+	return models.ItemHealthOK
+}
+
+// Records that an item synced successfully, clearing any error it had
+func (app *AppServer) recordItemSynced(ctx context.Context, userID uuid.UUID, itemID string) {
+	err := app.Db.RecordItemSynced(ctx, database.RecordItemSyncedParams{ItemID: itemID, UserID: userID})
+	if err != nil {
+		app.logItemStatusError(fmt.Errorf("error recording item sync: %w", err))
+	}
+}
+
+// Records an error code against an item, from a Plaid error or webhook
+func (app *AppServer) recordItemError(ctx context.Context, userID uuid.UUID, itemID, code string) {
+	err := app.Db.RecordItemError(ctx, database.RecordItemErrorParams{
+		ItemID:        itemID,
+		UserID:        userID,
+		LastErrorCode: sql.NullString{String: code, Valid: true},
+	})
+	if err != nil {
+		app.logItemStatusError(fmt.Errorf("error recording item error: %w", err))
+	}
+}
+
+// Records an error returned by a Plaid call made with an item's access token, if the error is about the
+// item itself. The user is taken from the request context
+func (app *AppServer) recordItemPlaidError(ctx context.Context, itemID string, err error) {
+	plaidErr, ok := plaidservice.ParseError(err)
+	if !ok || plaidErr.ErrorType != plaidservice.ErrorTypeItemError || productUnavailableCodes[plaidErr.ErrorCode] {
+		return
+	}
+
+	userID, ok := ctx.Value(userIDKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return
+	}
+
+	app.recordItemError(ctx, userID, itemID, plaidErr.ErrorCode)
+}
+
+// Records when the user's consent for an item runs out, as reported by Plaid. A nil expiry, for items
+// whose consent doesn't expire, clears it
+func (app *AppServer) recordConsentExpiration(ctx context.Context, userID uuid.UUID, itemID string, expires *time.Time) {
+	expiresAt := sql.NullTime{}
+	if expires != nil {
+		expiresAt = sql.NullTime{Time: *expires, Valid: true}
+	}
+
+	err := app.Db.SetItemConsentExpiration(ctx, database.SetItemConsentExpirationParams{
+		ItemID:           itemID,
+		UserID:           userID,
+		ConsentExpiresAt: expiresAt,
+	})
+	if err != nil {
+		app.logItemStatusError(fmt.Errorf("error recording item consent expiration: %w", err))
+	}
+}
+
+// Errors recording item status are logged rather than failing the request, as the status only informs
+// the user of a problem the request itself reports
+func (app *AppServer) logItemStatusError(err error) {
+	_ = app.Logger.Log(
+		"level", "error",
+		"msg", "item status update failed",
+		"err", err,
+	)
+}
@@ -2,26 +2,141 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+
+	"github.com/jms-guy/greed/backend/api/plaidservice"
+	"github.com/jms-guy/greed/models"
 )
 
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
+// Base URI for problem types, each code is documented under its own heading
+const problemTypeBaseURL = "https://github.com/jms-guy/greed/blob/main/docs/errors.md#"
+
+// Error response body, in RFC 7807 problem details format
+type ErrorResponse = models.Problem
 
+// Responds with problem details, using the default error code for the status code
 func (app *AppServer) respondWithError(w http.ResponseWriter, code int, msg string, err error) {
+	app.respondWithErrorCode(w, code, defaultErrorCode(code), msg, err)
+}
+
+// Responds with problem details carrying a specific error code
+func (app *AppServer) respondWithErrorCode(w http.ResponseWriter, status int, errCode, msg string, err error) {
+	requestID := w.Header().Get(requestIDHeader)
+
 	if err != nil {
 		_ = app.Logger.Log(
 			"level", "error",
-			"status code", code,
+			"requestID", requestID,
+			"status code", status,
+			"code", errCode,
 			"msg", msg,
 			"err", err,
 		)
 	}
 
-	app.respondWithJSON(w, code, ErrorResponse{
-		Error: msg,
-	})
+	writeProblem(w, status, errCode, msg)
+}
+
+// Responds to an error returned from a Plaid API call, mapping Plaid's error to a stable error code
+func (app *AppServer) respondWithPlaidError(w http.ResponseWriter, msg string, err error) {
+	plaidErr, ok := plaidservice.ParseError(err)
+	if !ok {
+		app.respondWithError(w, 500, msg, err)
+		return
+	}
+
+	err = fmt.Errorf("plaid error %s/%s, plaid request id: %s: %w", plaidErr.ErrorType, plaidErr.ErrorCode, plaidErr.RequestID, err)
+	w.Header().Set(plaidRequestIDHeader, plaidErr.RequestID)
+
+	switch {
+	case plaidErr.ErrorCode == plaidservice.ErrorCodeItemLoginRequired:
+		app.respondWithErrorCode(w, 400, models.ErrCodeItemLoginRequired, "Financial institution requires re-authentication", err)
+	case plaidErr.ErrorType == plaidservice.ErrorTypeRateLimitExceeded:
+		app.respondWithErrorCode(w, 429, models.ErrCodePlaidRateLimited, "Plaid rate limit exceeded, please try again later", err)
+	default:
+		app.respondWithErrorCode(w, 502, models.ErrCodePlaidError, msg, err)
+	}
+}
+
+// How a Plaid product that an item can't provide is reported to clients
+type plaidProduct struct {
+	noAccountsCode string // Plaid error code for an item with no accounts the product covers
+	errCode        string
+	detail         string
+}
+
+var (
+	investmentsProduct = plaidProduct{plaidservice.ErrorCodeNoInvestmentAccounts, models.ErrCodeInvestmentsUnavailable, "Item has no investment data available"}
+	liabilitiesProduct = plaidProduct{plaidservice.ErrorCodeNoLiabilityAccounts, models.ErrCodeLiabilitiesUnavailable, "Item has no liability data available"}
+)
+
+// Responds to an error from a Plaid product endpoint. Errors meaning the item has no accounts the
+// product covers, or wasn't linked with it, are reported with the product's error code, and anything
+// else as in respondWithPlaidError
+func (app *AppServer) respondWithPlaidProductError(w http.ResponseWriter, product plaidProduct, msg string, err error) {
+	plaidErr, ok := plaidservice.ParseError(err)
+	if !ok {
+		app.respondWithPlaidError(w, msg, err)
+		return
+	}
+
+	switch plaidErr.ErrorCode {
+	case product.noAccountsCode, plaidservice.ErrorCodeProductsNotSupported, plaidservice.ErrorCodeAdditionalConsentRequired:
+		w.Header().Set(plaidRequestIDHeader, plaidErr.RequestID)
+		err = fmt.Errorf("plaid error %s/%s, plaid request id: %s: %w", plaidErr.ErrorType, plaidErr.ErrorCode, plaidErr.RequestID, err)
+		app.respondWithErrorCode(w, 400, product.errCode, product.detail, err)
+	default:
+		app.respondWithPlaidError(w, msg, err)
+	}
+}
+
+// Writes a problem details response. Used directly only where no AppServer is available
+func writeProblem(w http.ResponseWriter, status int, errCode, msg string) {
+	problem := models.Problem{
+		Type:      problemTypeBaseURL + errCode,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    msg,
+		Code:      errCode,
+		RequestID: w.Header().Get(requestIDHeader),
+		Error:     msg,
+
+		PlaidRequestID: w.Header().Get(plaidRequestIDHeader),
+	}
+
+	dat, err := json.Marshal(problem)
+	if err != nil {
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_, _ = w.Write(dat)
+}
+
+// Error code used when a handler does not give a more specific one
+func defaultErrorCode(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return models.ErrCodeUnauthorized
+	case http.StatusForbidden:
+		return models.ErrCodeForbidden
+	case http.StatusNotFound:
+		return models.ErrCodeNotFound
+	case http.StatusTooManyRequests:
+		return models.ErrCodeRateLimited
+	case http.StatusRequestEntityTooLarge:
+		return models.ErrCodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return models.ErrCodeUnsupportedMediaType
+	}
+
+	if status >= 500 {
+		return models.ErrCodeInternal
+	}
+	return models.ErrCodeBadRequest
 }
 
 func (app *AppServer) respondWithJSON(w http.ResponseWriter, code int, payload any) {
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Liability types, matching the lists Plaid returns them in
+const (
+	liabilityTypeCredit   = "credit"
+	liabilityTypeStudent  = "student"
+	liabilityTypeMortgage = "mortgage"
+)
+
+// Plaid's APR type for a credit card's purchase rate, the rate shown for the card when it has one
+const purchaseAPRType = "purchase_apr"
+
+// Converts a nullable Plaid date (YYYY-MM-DD) to its database equivalent
+func nullableDate(v plaid.NullableString) (sql.NullTime, error) {
+	if !v.IsSet() || v.Get() == nil || *v.Get() == "" {
+		return sql.NullTime{}, nil
+	}
+	date, err := time.Parse("2006-01-02", *v.Get())
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+	return sql.NullTime{Time: date, Valid: true}, nil
+}
+
+// Converts a nullable Plaid boolean to its database equivalent
+func nullableBool(v plaid.NullableBool) sql.NullBool {
+	if !v.IsSet() || v.Get() == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *v.Get(), Valid: true}
+}
+
+// Parses each of a liability's dates, stopping at the first that fails
+func liabilityDates(accountID string, dates map[string]plaid.NullableString) (map[string]sql.NullTime, error) {
+	parsed := make(map[string]sql.NullTime, len(dates))
+	for name, value := range dates {
+		date, err := nullableDate(value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s of liability for account %s: %w", name, accountID, err)
+		}
+		parsed[name] = date
+	}
+	return parsed, nil
+}
+
+func creditLiabilityParams(credit plaid.CreditCardLiability) (database.UpsertLiabilityParams, error) {
+	accountID := credit.AccountId.Get()
+	if accountID == nil {
+		return database.UpsertLiabilityParams{}, fmt.Errorf("credit liability is missing its account ID")
+	}
+
+	dates, err := liabilityDates(*accountID, map[string]plaid.NullableString{
+		"next payment due date": credit.NextPaymentDueDate,
+		"last statement date":   credit.LastStatementIssueDate,
+		"last payment date":     credit.LastPaymentDate,
+	})
+	if err != nil {
+		return database.UpsertLiabilityParams{}, err
+	}
+
+	// Cards list an APR per balance type, the purchase APR is the one that applies to everyday spending
+	// and otherwise the highest is shown
+	var shown *plaid.APR
+	for i, apr := range credit.Aprs {
+		if apr.AprType == purchaseAPRType {
+			shown = &credit.Aprs[i]
+			break
+		}
+		if shown == nil || apr.AprPercentage > shown.AprPercentage {
+			shown = &credit.Aprs[i]
+		}
+	}
+	rate, rateType := sql.NullString{}, sql.NullString{}
+	if shown != nil {
+		rate = sql.NullString{String: formatDecimal(shown.AprPercentage), Valid: true}
+		rateType = sql.NullString{String: shown.AprType, Valid: true}
+	}
+
+	return database.UpsertLiabilityParams{
+		AccountID:            *accountID,
+		Type:                 liabilityTypeCredit,
+		InterestRate:         rate,
+		InterestRateType:     rateType,
+		MinimumPayment:       nullableDecimal(credit.MinimumPaymentAmount),
+		NextPaymentDueDate:   dates["next payment due date"],
+		LastStatementBalance: nullableDecimal(credit.LastStatementBalance),
+		LastStatementDate:    dates["last statement date"],
+		LastPaymentAmount:    nullableDecimal(credit.LastPaymentAmount),
+		LastPaymentDate:      dates["last payment date"],
+		IsOverdue:            nullableBool(credit.IsOverdue),
+	}, nil
+}
+
+func liabilityAPRParams(accountID string, apr plaid.APR) database.UpsertLiabilityAPRParams {
+	return database.UpsertLiabilityAPRParams{
+		AccountID:            accountID,
+		AprType:              apr.AprType,
+		AprPercentage:        formatDecimal(apr.AprPercentage),
+		BalanceSubjectToApr:  nullableDecimal(apr.BalanceSubjectToApr),
+		InterestChargeAmount: nullableDecimal(apr.InterestChargeAmount),
+	}
+}
+
+func studentLoanParams(loan plaid.StudentLoan) (database.UpsertLiabilityParams, error) {
+	accountID := loan.AccountId.Get()
+	if accountID == nil {
+		return database.UpsertLiabilityParams{}, fmt.Errorf("student loan is missing its account ID")
+	}
+
+	dates, err := liabilityDates(*accountID, map[string]plaid.NullableString{
+		"next payment due date": loan.NextPaymentDueDate,
+		"last statement date":   loan.LastStatementIssueDate,
+		"last payment date":     loan.LastPaymentDate,
+		"origination date":      loan.OriginationDate,
+		"expected payoff date":  loan.ExpectedPayoffDate,
+	})
+	if err != nil {
+		return database.UpsertLiabilityParams{}, err
+	}
+
+	return database.UpsertLiabilityParams{
+		AccountID:            *accountID,
+		Type:                 liabilityTypeStudent,
+		InterestRate:         sql.NullString{String: formatDecimal(loan.InterestRatePercentage), Valid: true},
+		MinimumPayment:       nullableDecimal(loan.MinimumPaymentAmount),
+		NextPaymentDueDate:   dates["next payment due date"],
+		LastStatementBalance: nullableDecimal(loan.LastStatementBalance),
+		LastStatementDate:    dates["last statement date"],
+		LastPaymentAmount:    nullableDecimal(loan.LastPaymentAmount),
+		LastPaymentDate:      dates["last payment date"],
+		IsOverdue:            nullableBool(loan.IsOverdue),
+		OriginationPrincipal: nullableDecimal(loan.OriginationPrincipalAmount),
+		OriginationDate:      dates["origination date"],
+		MaturityDate:         dates["expected payoff date"],
+		LoanTerm:             nullableString(loan.RepaymentPlan.Description),
+		LoanName:             nullableString(loan.LoanName),
+	}, nil
+}
+
+func mortgageParams(mortgage plaid.MortgageLiability) (database.UpsertLiabilityParams, error) {
+	dates, err := liabilityDates(mortgage.AccountId, map[string]plaid.NullableString{
+		"next payment due date": mortgage.NextPaymentDueDate,
+		"last payment date":     mortgage.LastPaymentDate,
+		"origination date":      mortgage.OriginationDate,
+		"maturity date":         mortgage.MaturityDate,
+	})
+	if err != nil {
+		return database.UpsertLiabilityParams{}, err
+	}
+
+	// Mortgages have no statements, and are overdue when any amount is past due
+	overdue := sql.NullBool{}
+	if pastDue := mortgage.PastDueAmount.Get(); pastDue != nil {
+		overdue = sql.NullBool{Bool: *pastDue > 0, Valid: true}
+	}
+
+	return database.UpsertLiabilityParams{
+		AccountID:            mortgage.AccountId,
+		Type:                 liabilityTypeMortgage,
+		InterestRate:         nullableDecimal(mortgage.InterestRate.Percentage),
+		InterestRateType:     nullableString(mortgage.InterestRate.Type),
+		MinimumPayment:       nullableDecimal(mortgage.NextMonthlyPayment),
+		NextPaymentDueDate:   dates["next payment due date"],
+		LastPaymentAmount:    nullableDecimal(mortgage.LastPaymentAmount),
+		LastPaymentDate:      dates["last payment date"],
+		IsOverdue:            overdue,
+		OriginationPrincipal: nullableDecimal(mortgage.OriginationPrincipalAmount),
+		OriginationDate:      dates["origination date"],
+		MaturityDate:         dates["maturity date"],
+		LoanTerm:             nullableString(mortgage.LoanTerm),
+		LoanName:             nullableString(mortgage.LoanTypeDescription),
+	}, nil
+}
+
+// Formats a nullable database date for a response, empty when unknown
+func formatNullDate(v sql.NullTime) string {
+	if !v.Valid {
+		return ""
+	}
+	return v.Time.Format("2006-01-02")
+}
+
+// Builds an account's liability response from its database records
+func liabilityResponse(acc database.Account, liability database.Liability, aprs []database.LiabilityApr) models.Liability {
+	response := models.Liability{
+		AccountID:            liability.AccountID,
+		Type:                 liability.Type,
+		Balance:              acc.CurrentBalance.String,
+		InterestRate:         liability.InterestRate.String,
+		InterestRateType:     liability.InterestRateType.String,
+		MinimumPayment:       liability.MinimumPayment.String,
+		NextPaymentDueDate:   formatNullDate(liability.NextPaymentDueDate),
+		LastStatementBalance: liability.LastStatementBalance.String,
+		LastStatementDate:    formatNullDate(liability.LastStatementDate),
+		LastPaymentAmount:    liability.LastPaymentAmount.String,
+		LastPaymentDate:      formatNullDate(liability.LastPaymentDate),
+		IsOverdue:            liability.IsOverdue.Bool,
+		OriginationPrincipal: liability.OriginationPrincipal.String,
+		OriginationDate:      formatNullDate(liability.OriginationDate),
+		MaturityDate:         formatNullDate(liability.MaturityDate),
+		LoanTerm:             liability.LoanTerm.String,
+		LoanName:             liability.LoanName.String,
+		APRs:                 []models.LiabilityAPR{},
+		UpdatedAt:            liability.UpdatedAt,
+	}
+
+	for _, apr := range aprs {
+		response.APRs = append(response.APRs, models.LiabilityAPR{
+			Type:                apr.AprType,
+			Percentage:          apr.AprPercentage,
+			BalanceSubjectToAPR: apr.BalanceSubjectToApr.String,
+			InterestCharged:     apr.InterestChargeAmount.String,
+		})
+	}
+
+	return response
+}
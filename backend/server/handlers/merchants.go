@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/merchant"
+)
+
+// Source of a user's merchant aliases, met by both the database and queries within a database transaction
+type merchantAliasSource interface {
+	GetMerchantAliases(ctx context.Context, userID uuid.UUID) ([]database.MerchantAlias, error)
+}
+
+// Creates a normalizer applying the user's merchant aliases
+func loadMerchantNormalizer(ctx context.Context, db merchantAliasSource, userID uuid.UUID) (*merchant.Normalizer, error) {
+	aliases, err := db.GetMerchantAliases(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting merchant aliases: %w", err)
+	}
+
+	mapping := make(map[string]string, len(aliases))
+	for _, alias := range aliases {
+		mapping[alias.RawName] = alias.Merchant
+	}
+
+	return merchant.NewNormalizer(mapping), nil
+}
+
+// Canonical merchant of a stored transaction, falling back to its merchant name if it hasn't been normalized yet
+func canonicalMerchant(txn database.Transaction) string {
+	if txn.CanonicalMerchant.Valid {
+		return txn.CanonicalMerchant.String
+	}
+	return txn.MerchantName.String
+}
+
+// Sets the canonical merchant of a manual transaction from its merchant name and the user's aliases
+func (app *AppServer) normalizeManualTransaction(ctx context.Context, userID uuid.UUID, params *database.UpdateManualTransactionParams) error {
+	normalizer, err := loadMerchantNormalizer(ctx, app.Db, userID)
+	if err != nil {
+		return err
+	}
+
+	params.CanonicalMerchant = sql.NullString{String: normalizer.Canonical(params.MerchantName.String, ""), Valid: true}
+	return nil
+}
+
+// Recomputes the canonical merchant of the user's transactions, after their aliases change or for
+// transactions recorded before merchants were normalized. Returns the number of transactions changed
+func (app *AppServer) normalizeUserMerchants(ctx context.Context, userID uuid.UUID) (int64, error) {
+	normalizer, err := loadMerchantNormalizer(ctx, app.Db, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	names, err := app.Db.GetUserMerchantNames(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting merchant names: %w", err)
+	}
+
+	var changed int64
+	for _, row := range names {
+		canonical := normalizer.Canonical(row.MerchantName.String, row.Name.String)
+		if row.CanonicalMerchant.Valid && row.CanonicalMerchant.String == canonical {
+			continue
+		}
+
+		count, err := app.Db.SetCanonicalMerchant(ctx, database.SetCanonicalMerchantParams{
+			CanonicalMerchant: sql.NullString{String: canonical, Valid: true},
+			UserID:            userID,
+			MerchantName:      row.MerchantName,
+			Name:              row.Name,
+		})
+		if err != nil {
+			return changed, fmt.Errorf("error setting canonical merchant: %w", err)
+		}
+		changed += count
+	}
+
+	return changed, nil
+}
+
+// Background worker normalizing the merchants of transactions recorded before normalization existed.
+// It stops once every user's transactions are normalized, as new transactions are normalized when written
+func (app *AppServer) RunMerchantNormalizer(ctx context.Context) error {
+	users, err := app.Db.GetUsersWithUnnormalizedMerchants(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting users with unnormalized merchants: %w", err)
+	}
+
+	for _, userID := range users {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := app.normalizeUserMerchants(ctx, userID); err != nil {
+			app.logMerchantError(userID, err)
+		}
+	}
+
+	return nil
+}
+
+func (app *AppServer) logMerchantError(userID uuid.UUID, err error) {
+	_ = app.Logger.Log(
+		"level", "error",
+		"msg", "merchant normalization failed",
+		"user_id", userID,
+		"err", err,
+	)
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-kit/log"
 	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/auth"
 	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/models"
 )
 
 // Middleware function to handle user authorization.
@@ -26,6 +28,11 @@ func (app *AppServer) AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		if auth.IsAPIKey(token) {
+			app.authenticateAPIKey(w, r, next, token)
+			return
+		}
+
 		id, err := app.Auth.ValidateJWT(app.Config, token)
 		if err != nil {
 			app.respondWithError(w, 401, "Invalid JWT", err)
@@ -37,6 +44,58 @@ func (app *AppServer) AuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// Authenticates a request made with an API key, in place of a JWT. Keys scoped read-only may only make
+// safe requests. The key is placed in the context, so handlers can refuse requests not meant for keys
+func (app *AppServer) authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, key string) {
+	ctx := r.Context()
+
+	apiKey, err := app.Db.GetAPIKeyByHash(ctx, auth.HashAPIKey(key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			app.respondWithError(w, 401, "Invalid API key", nil)
+			return
+		}
+		app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting API key: %w", err))
+		return
+	}
+
+	if apiKey.ExpiresAt.Valid && time.Now().After(apiKey.ExpiresAt.Time) {
+		app.respondWithErrorCode(w, 401, models.ErrCodeAPIKeyExpired, "API key is expired", nil)
+		return
+	}
+
+	if !auth.ScopeAllows(apiKey.Scope, r.Method) {
+		app.respondWithErrorCode(w, 403, models.ErrCodeInsufficientScope, "API key is read-only", nil)
+		return
+	}
+
+	// Recording use is best effort, and only written once a minute for each key
+	if err := app.Db.TouchAPIKey(ctx, apiKey.ID); err != nil {
+		_ = app.Logger.Log(
+			"level", "error",
+			"msg", "error recording API key use",
+			"key_id", apiKey.ID,
+			"err", err,
+		)
+	}
+
+	ctx = context.WithValue(ctx, userIDKey, apiKey.UserID)
+	ctx = context.WithValue(ctx, apiKeyKey, apiKey)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Middleware function refusing requests made with an API key. Used on routes managing credentials,
+// which need a logged-in session
+func (app *AppServer) SessionOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(apiKeyKey).(database.ApiKey); ok {
+			app.respondWithErrorCode(w, 403, models.ErrCodeInsufficientScope, "API keys cannot be used for this request, log in instead", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // Middleware function to handle the Plaid access token.
 // Serves following handlers with Plaid Access token in context
 func (app *AppServer) AccessTokenMiddleware(next http.Handler) http.Handler {
@@ -106,7 +165,7 @@ func (app *AppServer) MemberMiddleware(next http.Handler) http.Handler {
 				}
 				next.ServeHTTP(w, r.WithContext(ctx))
 			} else {
-				app.respondWithError(w, 400, "Bad request", fmt.Errorf("user has no free calls left to access endpoint"))
+				app.respondWithErrorCode(w, 403, models.ErrCodeFreeCallsExhausted, "No free calls remaining for this endpoint, a membership is required to continue", fmt.Errorf("user has no free calls left to access endpoint"))
 				return
 			}
 		}
@@ -147,6 +206,38 @@ func (app *AppServer) AccountMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// Middleware function to handle transaction authorization, following AccountMiddleware.
+// Serves following handlers with a transaction struct in context
+func (app *AppServer) TransactionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		accValue := ctx.Value(accountKey)
+		acc, ok := accValue.(database.Account)
+		if !ok {
+			app.respondWithError(w, 400, "Bad account in context", nil)
+			return
+		}
+
+		txnID := chi.URLParam(r, "transaction-id")
+
+		txn, err := app.Db.GetTransactionForAccount(ctx, database.GetTransactionForAccountParams{
+			ID:        txnID,
+			AccountID: acc.ID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				app.respondWithError(w, 404, "Transaction not found", nil)
+				return
+			}
+			app.respondWithError(w, 500, "Database error", fmt.Errorf("error getting transaction: %w", err))
+			return
+		}
+
+		ctx = context.WithValue(ctx, transactionKey, txn)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // Below is logging middleware - includes panic recovery
 type responseWriter struct {
 	http.ResponseWriter
@@ -179,6 +270,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
 
+// Returns the wrapped writer, so http.ResponseController can reach it to change deadlines
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Logging middleware function.
 // Logs details of http request, and any errors
 func LoggingMiddleware(Logger log.Logger) func(http.Handler) http.Handler {
@@ -188,10 +284,11 @@ func LoggingMiddleware(Logger log.Logger) func(http.Handler) http.Handler {
 
 			ctx := context.WithValue(r.Context(), requestIDKey, requestID)
 			r = r.WithContext(ctx)
+			w.Header().Set(requestIDHeader, requestID)
 
 			defer func() {
 				if err := recover(); err != nil {
-					w.WriteHeader(http.StatusInternalServerError)
+					writeProblem(w, http.StatusInternalServerError, models.ErrCodeInternal, "Internal server error")
 					_ = Logger.Log(
 						"requestID", requestID,
 						"err", err,
@@ -228,7 +325,7 @@ func (app *AppServer) RateLimitMiddleware(next http.Handler) http.Handler {
 				"msg", "invalid IP",
 				"err", err,
 			)
-			http.Error(w, "Invalid IP", 400)
+			app.respondWithError(w, 400, "Invalid IP", nil)
 			return
 		}
 
@@ -241,7 +338,7 @@ func (app *AppServer) RateLimitMiddleware(next http.Handler) http.Handler {
 				"msg", "rate limit exceeded",
 				"ip", ip,
 			)
-			http.Error(w, "Rate Limit Exceeded", http.StatusTooManyRequests)
+			app.respondWithError(w, http.StatusTooManyRequests, "Rate limit exceeded", nil)
 		}
 	})
 }
@@ -259,7 +356,7 @@ func (app *AppServer) DevAuthMiddleware(next http.Handler) http.Handler {
 				"msg", "attempted access to admin route in non-dev environment",
 				"ip", r.RemoteAddr,
 			)
-			http.Error(w, "Not found", http.StatusForbidden)
+			app.respondWithError(w, http.StatusForbidden, "Not found", nil)
 		}
 	})
 }
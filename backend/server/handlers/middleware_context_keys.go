@@ -7,9 +7,17 @@ const (
 	userIDKey      contextKey = "userID"
 	accessTokenKey contextKey = "accessTokenKey"
 	accountKey     contextKey = "account"
+	transactionKey contextKey = "transaction"
 	requestIDKey   contextKey = "requestID"
+	apiKeyKey      contextKey = "apiKey"
 )
 
+// Response header carrying the request ID assigned by LoggingMiddleware
+const requestIDHeader = "X-Request-Id"
+
+// Response header carrying the request ID of a failed Plaid API call
+const plaidRequestIDHeader = "X-Plaid-Request-Id"
+
 // Export functions for use in handler testing
 func GetUserIDContextKey() any {
 	return userIDKey
@@ -23,6 +31,14 @@ func GetAccountKey() any {
 	return accountKey
 }
 
+func GetTransactionKey() any {
+	return transactionKey
+}
+
 func GetRequestIDKey() any {
 	return requestIDKey
 }
+
+func GetAPIKeyContextKey() any {
+	return apiKeyKey
+}
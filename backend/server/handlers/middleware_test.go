@@ -4,17 +4,22 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/auth"
 	"github.com/jms-guy/greed/backend/internal/config"
 	"github.com/jms-guy/greed/backend/internal/database"
 	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/jms-guy/greed/models"
 )
 
 func TestAuthMiddleware(t *testing.T) {
@@ -114,6 +119,168 @@ func TestAuthMiddleware(t *testing.T) {
 	}
 }
 
+func TestAuthMiddlewareAPIKey(t *testing.T) {
+	testKey := "greed_0123456789abcdef"
+	readKey := database.ApiKey{ID: uuid.New(), UserID: testUserID, Scope: auth.ScopeRead}
+
+	tests := []struct {
+		name            string
+		method          string
+		mockDb          *mockDatabaseService
+		expectedStatus  int
+		expectedBody    string
+		expectedContext uuid.UUID
+		expectedTouch   bool
+	}{
+		{
+			name:   "should place key's userID in context",
+			method: "GET",
+			mockDb: &mockDatabaseService{
+				GetAPIKeyByHashFunc: func(ctx context.Context, keyHash string) (database.ApiKey, error) {
+					if keyHash != auth.HashAPIKey(testKey) {
+						return database.ApiKey{}, sql.ErrNoRows
+					}
+					return readKey, nil
+				},
+			},
+			expectedStatus:  http.StatusOK,
+			expectedContext: testUserID,
+			expectedTouch:   true,
+		},
+		{
+			name:   "should err with read-only key on write request",
+			method: "POST",
+			mockDb: &mockDatabaseService{
+				GetAPIKeyByHashFunc: func(ctx context.Context, keyHash string) (database.ApiKey, error) {
+					return readKey, nil
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "insufficient_scope",
+		},
+		{
+			name:   "should err with expired key",
+			method: "GET",
+			mockDb: &mockDatabaseService{
+				GetAPIKeyByHashFunc: func(ctx context.Context, keyHash string) (database.ApiKey, error) {
+					k := readKey
+					k.ExpiresAt = sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}
+					return k, nil
+				},
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "api_key_expired",
+		},
+		{
+			name:   "should err with unknown key",
+			method: "GET",
+			mockDb: &mockDatabaseService{
+				GetAPIKeyByHashFunc: func(ctx context.Context, keyHash string) (database.ApiKey, error) {
+					return database.ApiKey{}, sql.ErrNoRows
+				},
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   "Invalid API key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			touched := false
+			tt.mockDb.TouchAPIKeyFunc = func(ctx context.Context, id uuid.UUID) error {
+				touched = true
+				return nil
+			}
+
+			req := httptest.NewRequest(tt.method, "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+testKey)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db: tt.mockDb,
+				Auth: &mockAuthService{
+					GetBearerTokenFunc: func(headers http.Header) (string, error) {
+						return testKey, nil
+					},
+					ValidateJWTFunc: func(cfg *config.Config, tokenString string) (uuid.UUID, error) {
+						return uuid.Nil, fmt.Errorf("API keys should not be validated as JWTs")
+					},
+				},
+				Config: &config.Config{},
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			var userIDFromContext uuid.UUID
+			dummyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if id, ok := r.Context().Value(handlers.GetUserIDContextKey()).(uuid.UUID); ok {
+					userIDFromContext = id
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			mockApp.AuthMiddleware(dummyHandler).ServeHTTP(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+			if userIDFromContext != tt.expectedContext {
+				t.Errorf("handler did not set expected userID in context: got %v want %v", userIDFromContext, tt.expectedContext)
+			}
+			if touched != tt.expectedTouch {
+				t.Errorf("key use recorded: got %v want %v", touched, tt.expectedTouch)
+			}
+		})
+	}
+}
+
+func TestSessionOnlyMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		apiKey         any
+		expectedStatus int
+	}{
+		{
+			name:           "should pass requests made with a JWT",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "should err with requests made with an API key",
+			apiKey:         database.ApiKey{ID: uuid.New(), Scope: auth.ScopeWrite},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/keys", nil)
+			if tt.apiKey != nil {
+				req = req.WithContext(context.WithValue(req.Context(), handlers.GetAPIKeyContextKey(), tt.apiKey))
+			}
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			dummyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			mockApp.SessionOnlyMiddleware(dummyHandler).ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+		})
+	}
+}
+
 func TestAccessTokenMiddleware(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -329,8 +496,8 @@ func TestMemberMiddleware(t *testing.T) {
 				},
 			},
 			mockAuth:       &mockAuthService{},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "Bad request",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "free_calls_exhausted",
 		},
 		{
 			name:            "should err on updating user's free calls",
@@ -528,3 +695,180 @@ func TestAccountMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggingMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        func(app *handlers.AppServer) http.Handler
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "should return problem details with request ID on error",
+			handler: func(app *handlers.AppServer) http.Handler {
+				app.Auth = &mockAuthService{
+					GetBearerTokenFunc: func(headers http.Header) (string, error) {
+						return "", fmt.Errorf("mock error")
+					},
+				}
+				return app.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   models.ErrCodeBadRequest,
+		},
+		{
+			name: "should recover from panic with problem details",
+			handler: func(app *handlers.AppServer) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					panic("mock panic")
+				})
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   models.ErrCodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Config: &config.Config{},
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			handler := handlers.LoggingMiddleware(mockApp.Logger)(tt.handler(mockApp))
+			handler.ServeHTTP(rr, req)
+
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+
+			if contentType := rr.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Errorf("handler returned wrong content type: got %s", contentType)
+			}
+
+			requestID := rr.Header().Get("X-Request-Id")
+			if requestID == "" {
+				t.Fatalf("handler did not set X-Request-Id header")
+			}
+
+			var problem handlers.ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("error decoding problem details: %v", err)
+			}
+
+			if problem.Code != tt.expectedCode {
+				t.Errorf("handler returned wrong error code: got %s want %s", problem.Code, tt.expectedCode)
+			}
+			if problem.Status != tt.expectedStatus {
+				t.Errorf("problem has wrong status: got %d want %d", problem.Status, tt.expectedStatus)
+			}
+			if problem.RequestID != requestID {
+				t.Errorf("problem request ID %s does not match header %s", problem.RequestID, requestID)
+			}
+		})
+	}
+}
+
+func TestTransactionMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		accountInContext any
+		mockDb           *mockDatabaseService
+		expectedStatus   int
+		expectedContext  database.Transaction
+		expectedBody     string
+	}{
+		{
+			name:             "should successfully place transaction in context",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetTransactionForAccountFunc: func(ctx context.Context, arg database.GetTransactionForAccountParams) (database.Transaction, error) {
+					if arg.ID != testTxnID.String() || arg.AccountID != testAccountID {
+						return database.Transaction{}, sql.ErrNoRows
+					}
+					return testTransaction, nil
+				},
+			},
+			expectedStatus:  http.StatusOK,
+			expectedContext: testTransaction,
+		},
+		{
+			name:             "should err with bad account in context",
+			accountInContext: 1,
+			mockDb:           &mockDatabaseService{},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBody:     "Bad account in context",
+		},
+		{
+			name:             "should err with transaction not found",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetTransactionForAccountFunc: func(ctx context.Context, arg database.GetTransactionForAccountParams) (database.Transaction, error) {
+					return database.Transaction{}, sql.ErrNoRows
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "Transaction not found",
+		},
+		{
+			name:             "should err on database error",
+			accountInContext: testAccount,
+			mockDb: &mockDatabaseService{
+				GetTransactionForAccountFunc: func(ctx context.Context, arg database.GetTransactionForAccountParams) (database.Transaction, error) {
+					return database.Transaction{}, fmt.Errorf("mock error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("transaction-id", testTxnID.String())
+
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, handlers.GetAccountKey(), tt.accountInContext)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:     tt.mockDb,
+				Config: &config.Config{},
+				Logger: kitlog.NewNopLogger(),
+			}
+
+			var txnFromContext database.Transaction
+
+			dummyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if txn, ok := r.Context().Value(handlers.GetTransactionKey()).(database.Transaction); ok {
+					txnFromContext = txn
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := mockApp.TransactionMiddleware(dummyHandler)
+			handler.ServeHTTP(rr, req)
+
+			// --- Assertions ---
+			if status := rr.Code; status != tt.expectedStatus {
+				t.Errorf("handler returned wrong status code: got %v want %v. Body: %s", status, tt.expectedStatus, rr.Body.String())
+			}
+
+			if txnFromContext != tt.expectedContext {
+				t.Errorf("handler did not set expected transaction in context: got %v want %v", txnFromContext, tt.expectedContext)
+			}
+
+			if !strings.Contains(rr.Body.String(), tt.expectedBody) {
+				t.Errorf("handler returned unexpected body: got %s want body to contain %s", rr.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"database/sql"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/api/plaidservice"
 	"github.com/jms-guy/greed/backend/api/sgrid"
 	"github.com/jms-guy/greed/backend/internal/auth"
 	"github.com/jms-guy/greed/backend/internal/config"
 	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/notify"
 	"github.com/jms-guy/greed/backend/internal/utils"
 	"github.com/jms-guy/greed/models"
 	"github.com/plaid/plaid-go/v36/plaid"
@@ -107,6 +110,141 @@ func (m *mockDatabaseService) GetAllAccountsForUser(ctx context.Context, userID
 	return nil, nil
 }
 
+func (m *mockDatabaseService) GetAccountOverride(ctx context.Context, accountID string) (database.AccountOverride, error) {
+	if m.GetAccountOverrideFunc != nil {
+		return m.GetAccountOverrideFunc(ctx, accountID)
+	}
+	return database.AccountOverride{}, sql.ErrNoRows
+}
+
+func (m *mockDatabaseService) GetAccountOverridesForUser(ctx context.Context, userID uuid.UUID) ([]database.AccountOverride, error) {
+	if m.GetAccountOverridesForUserFunc != nil {
+		return m.GetAccountOverridesForUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) UpsertAccountOverride(ctx context.Context, arg database.UpsertAccountOverrideParams) (database.AccountOverride, error) {
+	if m.UpsertAccountOverrideFunc != nil {
+		return m.UpsertAccountOverrideFunc(ctx, arg)
+	}
+	return database.AccountOverride{}, nil
+}
+
+func (m *mockDatabaseService) GetTransactionNote(ctx context.Context, transactionID string) (database.TransactionNote, error) {
+	if m.GetTransactionNoteFunc != nil {
+		return m.GetTransactionNoteFunc(ctx, transactionID)
+	}
+	return database.TransactionNote{}, nil
+}
+
+func (m *mockDatabaseService) UpsertTransactionNote(ctx context.Context, arg database.UpsertTransactionNoteParams) (database.TransactionNote, error) {
+	if m.UpsertTransactionNoteFunc != nil {
+		return m.UpsertTransactionNoteFunc(ctx, arg)
+	}
+	return database.TransactionNote{TransactionID: arg.TransactionID, Note: arg.Note}, nil
+}
+
+func (m *mockDatabaseService) DeleteTransactionNote(ctx context.Context, transactionID string) error {
+	if m.DeleteTransactionNoteFunc != nil {
+		return m.DeleteTransactionNoteFunc(ctx, transactionID)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) CreateTransactionAttachment(ctx context.Context, arg database.CreateTransactionAttachmentParams) (database.TransactionAttachment, error) {
+	if m.CreateTransactionAttachmentFunc != nil {
+		return m.CreateTransactionAttachmentFunc(ctx, arg)
+	}
+	return database.TransactionAttachment{
+		ID:            arg.ID,
+		TransactionID: arg.TransactionID,
+		UserID:        arg.UserID,
+		FileName:      arg.FileName,
+		ContentType:   arg.ContentType,
+		SizeBytes:     arg.SizeBytes,
+		Sha256:        arg.Sha256,
+		StorageKey:    arg.StorageKey,
+	}, nil
+}
+
+func (m *mockDatabaseService) GetTransactionAttachments(ctx context.Context, transactionID string) ([]database.TransactionAttachment, error) {
+	if m.GetTransactionAttachmentsFunc != nil {
+		return m.GetTransactionAttachmentsFunc(ctx, transactionID)
+	}
+	return []database.TransactionAttachment{}, nil
+}
+
+func (m *mockDatabaseService) GetTransactionAttachment(ctx context.Context, arg database.GetTransactionAttachmentParams) (database.TransactionAttachment, error) {
+	if m.GetTransactionAttachmentFunc != nil {
+		return m.GetTransactionAttachmentFunc(ctx, arg)
+	}
+	return database.TransactionAttachment{}, nil
+}
+
+func (m *mockDatabaseService) DeleteTransactionAttachment(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteTransactionAttachmentFunc != nil {
+		return m.DeleteTransactionAttachmentFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) GetOrphanedAttachmentBlobs(ctx context.Context, limit int32) ([]string, error) {
+	if m.GetOrphanedAttachmentBlobsFunc != nil {
+		return m.GetOrphanedAttachmentBlobsFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) DeleteOrphanedAttachmentBlob(ctx context.Context, storageKey string) error {
+	if m.DeleteOrphanedAttachmentBlobFunc != nil {
+		return m.DeleteOrphanedAttachmentBlobFunc(ctx, storageKey)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) GetMerchantAliases(ctx context.Context, userID uuid.UUID) ([]database.MerchantAlias, error) {
+	if m.GetMerchantAliasesFunc != nil {
+		return m.GetMerchantAliasesFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) UpsertMerchantAlias(ctx context.Context, arg database.UpsertMerchantAliasParams) (database.MerchantAlias, error) {
+	if m.UpsertMerchantAliasFunc != nil {
+		return m.UpsertMerchantAliasFunc(ctx, arg)
+	}
+	return database.MerchantAlias{}, nil
+}
+
+func (m *mockDatabaseService) DeleteMerchantAlias(ctx context.Context, arg database.DeleteMerchantAliasParams) (int64, error) {
+	if m.DeleteMerchantAliasFunc != nil {
+		return m.DeleteMerchantAliasFunc(ctx, arg)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) GetUserMerchantNames(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error) {
+	if m.GetUserMerchantNamesFunc != nil {
+		return m.GetUserMerchantNamesFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) SetCanonicalMerchant(ctx context.Context, arg database.SetCanonicalMerchantParams) (int64, error) {
+	if m.SetCanonicalMerchantFunc != nil {
+		return m.SetCanonicalMerchantFunc(ctx, arg)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) GetUsersWithUnnormalizedMerchants(ctx context.Context) ([]uuid.UUID, error) {
+	if m.GetUsersWithUnnormalizedMerchantsFunc != nil {
+		return m.GetUsersWithUnnormalizedMerchantsFunc(ctx)
+	}
+	return nil, nil
+}
+
 func (m *mockDatabaseService) CreateAccount(ctx context.Context, arg database.CreateAccountParams) (database.Account, error) {
 	if m.CreateAccountFunc != nil {
 		return m.CreateAccountFunc(ctx, arg)
@@ -114,6 +252,13 @@ func (m *mockDatabaseService) CreateAccount(ctx context.Context, arg database.Cr
 	return database.Account{}, nil
 }
 
+func (m *mockDatabaseService) CreateManualAccount(ctx context.Context, arg database.CreateManualAccountParams) (database.Account, error) {
+	if m.CreateManualAccountFunc != nil {
+		return m.CreateManualAccountFunc(ctx, arg)
+	}
+	return database.Account{}, nil
+}
+
 func (m *mockDatabaseService) DeleteAccount(ctx context.Context, arg database.DeleteAccountParams) error {
 	if m.DeleteAccountFunc != nil {
 		return m.DeleteAccountFunc(ctx, arg)
@@ -184,6 +329,20 @@ func (m *mockDatabaseService) GetMonetaryDataForMonth(ctx context.Context, arg d
 	return database.GetMonetaryDataForMonthRow{}, nil
 }
 
+func (m *mockDatabaseService) GetSpendingBreakdown(ctx context.Context, arg database.GetSpendingBreakdownParams) ([]database.GetSpendingBreakdownRow, error) {
+	if m.GetSpendingBreakdownFunc != nil {
+		return m.GetSpendingBreakdownFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) GetUserSpendingBreakdown(ctx context.Context, arg database.GetUserSpendingBreakdownParams) ([]database.GetUserSpendingBreakdownRow, error) {
+	if m.GetUserSpendingBreakdownFunc != nil {
+		return m.GetUserSpendingBreakdownFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
 func (m *mockDatabaseService) ValidateCurrency(ctx context.Context, code string) (bool, error) {
 	if m.ValidateCurrencyFunc != nil {
 		return m.ValidateCurrencyFunc(ctx, code)
@@ -303,6 +462,41 @@ func (m *mockDatabaseService) UpdateNickname(ctx context.Context, arg database.U
 	return nil
 }
 
+func (m *mockDatabaseService) ClearItemError(ctx context.Context, itemID string) error {
+	if m.ClearItemErrorFunc != nil {
+		return m.ClearItemErrorFunc(ctx, itemID)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) GetItemStatusesForUser(ctx context.Context, userID uuid.UUID) ([]database.ItemStatus, error) {
+	if m.GetItemStatusesForUserFunc != nil {
+		return m.GetItemStatusesForUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) RecordItemError(ctx context.Context, arg database.RecordItemErrorParams) error {
+	if m.RecordItemErrorFunc != nil {
+		return m.RecordItemErrorFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) RecordItemSynced(ctx context.Context, arg database.RecordItemSyncedParams) error {
+	if m.RecordItemSyncedFunc != nil {
+		return m.RecordItemSyncedFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) SetItemConsentExpiration(ctx context.Context, arg database.SetItemConsentExpirationParams) error {
+	if m.SetItemConsentExpirationFunc != nil {
+		return m.SetItemConsentExpirationFunc(ctx, arg)
+	}
+	return nil
+}
+
 func (m *mockDatabaseService) CreateToken(ctx context.Context, arg database.CreateTokenParams) (database.RefreshToken, error) {
 	if m.CreateTokenFunc != nil {
 		return m.CreateTokenFunc(ctx, arg)
@@ -373,6 +567,293 @@ func (m *mockDatabaseService) GetTransactionsForUser(ctx context.Context, userID
 	return nil, nil
 }
 
+func (m *mockDatabaseService) GetTransactionForAccount(ctx context.Context, arg database.GetTransactionForAccountParams) (database.Transaction, error) {
+	if m.GetTransactionForAccountFunc != nil {
+		return m.GetTransactionForAccountFunc(ctx, arg)
+	}
+	return database.Transaction{}, nil
+}
+
+func (m *mockDatabaseService) UpdateTransactionCategory(ctx context.Context, arg database.UpdateTransactionCategoryParams) (database.Transaction, error) {
+	if m.UpdateTransactionCategoryFunc != nil {
+		return m.UpdateTransactionCategoryFunc(ctx, arg)
+	}
+	return database.Transaction{}, nil
+}
+
+func (m *mockDatabaseService) GetTransactionsByIDs(ctx context.Context, arg database.GetTransactionsByIDsParams) ([]database.Transaction, error) {
+	if m.GetTransactionsByIDsFunc != nil {
+		return m.GetTransactionsByIDsFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) GetTransactionChanges(ctx context.Context, arg database.GetTransactionChangesParams) ([]database.TransactionChange, error) {
+	if m.GetTransactionChangesFunc != nil {
+		return m.GetTransactionChangesFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) RecordTransactionChanges(ctx context.Context, arg database.RecordTransactionChangesParams) error {
+	if m.RecordTransactionChangesFunc != nil {
+		return m.RecordTransactionChangesFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) UpsertTag(ctx context.Context, arg database.UpsertTagParams) (database.TransactionTag, error) {
+	if m.UpsertTagFunc != nil {
+		return m.UpsertTagFunc(ctx, arg)
+	}
+	return database.TransactionTag{}, nil
+}
+
+func (m *mockDatabaseService) GetTagNamesForTransaction(ctx context.Context, transactionID string) ([]string, error) {
+	if m.GetTagNamesForTransactionFunc != nil {
+		return m.GetTagNamesForTransactionFunc(ctx, transactionID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) CreateAnomaly(ctx context.Context, arg database.CreateAnomalyParams) error {
+	if m.CreateAnomalyFunc != nil {
+		return m.CreateAnomalyFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) GetAnomaliesForUser(ctx context.Context, arg database.GetAnomaliesForUserParams) ([]database.GetAnomaliesForUserRow, error) {
+	if m.GetAnomaliesForUserFunc != nil {
+		return m.GetAnomaliesForUserFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) AcknowledgeAnomaly(ctx context.Context, arg database.AcknowledgeAnomalyParams) (database.Anomaly, error) {
+	if m.AcknowledgeAnomalyFunc != nil {
+		return m.AcknowledgeAnomalyFunc(ctx, arg)
+	}
+	return database.Anomaly{}, nil
+}
+
+func (m *mockDatabaseService) AcknowledgeAllAnomalies(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if m.AcknowledgeAllAnomaliesFunc != nil {
+		return m.AcknowledgeAllAnomaliesFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) CreateNotificationRule(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error) {
+	if m.CreateNotificationRuleFunc != nil {
+		return m.CreateNotificationRuleFunc(ctx, arg)
+	}
+	return database.NotificationRule{}, nil
+}
+
+func (m *mockDatabaseService) GetNotificationRulesForUser(ctx context.Context, userID uuid.UUID) ([]database.NotificationRule, error) {
+	if m.GetNotificationRulesForUserFunc != nil {
+		return m.GetNotificationRulesForUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) GetEnabledNotificationRules(ctx context.Context, arg database.GetEnabledNotificationRulesParams) ([]database.NotificationRule, error) {
+	if m.GetEnabledNotificationRulesFunc != nil {
+		return m.GetEnabledNotificationRulesFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) GetNotificationRule(ctx context.Context, id uuid.UUID) (database.NotificationRule, error) {
+	if m.GetNotificationRuleFunc != nil {
+		return m.GetNotificationRuleFunc(ctx, id)
+	}
+	return database.NotificationRule{}, nil
+}
+
+func (m *mockDatabaseService) DeleteNotificationRule(ctx context.Context, arg database.DeleteNotificationRuleParams) (int64, error) {
+	if m.DeleteNotificationRuleFunc != nil {
+		return m.DeleteNotificationRuleFunc(ctx, arg)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) CreateNotificationDelivery(ctx context.Context, arg database.CreateNotificationDeliveryParams) error {
+	if m.CreateNotificationDeliveryFunc != nil {
+		return m.CreateNotificationDeliveryFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) ClaimDueNotificationDeliveries(ctx context.Context, arg database.ClaimDueNotificationDeliveriesParams) ([]database.NotificationDelivery, error) {
+	if m.ClaimDueNotificationDeliveriesFunc != nil {
+		return m.ClaimDueNotificationDeliveriesFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) RecordNotificationAttempt(ctx context.Context, arg database.RecordNotificationAttemptParams) error {
+	if m.RecordNotificationAttemptFunc != nil {
+		return m.RecordNotificationAttemptFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) GetNotificationDeliveriesForUser(ctx context.Context, arg database.GetNotificationDeliveriesForUserParams) ([]database.GetNotificationDeliveriesForUserRow, error) {
+	if m.GetNotificationDeliveriesForUserFunc != nil {
+		return m.GetNotificationDeliveriesForUserFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) RetryNotificationDelivery(ctx context.Context, arg database.RetryNotificationDeliveryParams) (database.NotificationDelivery, error) {
+	if m.RetryNotificationDeliveryFunc != nil {
+		return m.RetryNotificationDeliveryFunc(ctx, arg)
+	}
+	return database.NotificationDelivery{}, nil
+}
+
+func (m *mockDatabaseService) CreateEvent(ctx context.Context, arg database.CreateEventParams) error {
+	if m.CreateEventFunc != nil {
+		return m.CreateEventFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) CreateEventEndpoint(ctx context.Context, arg database.CreateEventEndpointParams) (database.EventEndpoint, error) {
+	if m.CreateEventEndpointFunc != nil {
+		return m.CreateEventEndpointFunc(ctx, arg)
+	}
+	return database.EventEndpoint{}, nil
+}
+
+func (m *mockDatabaseService) GetEventEndpointsForUser(ctx context.Context, userID uuid.UUID) ([]database.EventEndpoint, error) {
+	if m.GetEventEndpointsForUserFunc != nil {
+		return m.GetEventEndpointsForUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) DeleteEventEndpoint(ctx context.Context, arg database.DeleteEventEndpointParams) (int64, error) {
+	if m.DeleteEventEndpointFunc != nil {
+		return m.DeleteEventEndpointFunc(ctx, arg)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) GetEventsForUser(ctx context.Context, arg database.GetEventsForUserParams) ([]database.GetEventsForUserRow, error) {
+	if m.GetEventsForUserFunc != nil {
+		return m.GetEventsForUserFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) FanOutEvents(ctx context.Context, batchSize int32) (int64, error) {
+	if m.FanOutEventsFunc != nil {
+		return m.FanOutEventsFunc(ctx, batchSize)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) ClaimDueEventDeliveries(ctx context.Context, arg database.ClaimDueEventDeliveriesParams) ([]database.ClaimDueEventDeliveriesRow, error) {
+	if m.ClaimDueEventDeliveriesFunc != nil {
+		return m.ClaimDueEventDeliveriesFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) RecordEventDeliveryAttempt(ctx context.Context, arg database.RecordEventDeliveryAttemptParams) error {
+	if m.RecordEventDeliveryAttemptFunc != nil {
+		return m.RecordEventDeliveryAttemptFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) GetEventDeliveriesForEndpoint(ctx context.Context, arg database.GetEventDeliveriesForEndpointParams) ([]database.GetEventDeliveriesForEndpointRow, error) {
+	if m.GetEventDeliveriesForEndpointFunc != nil {
+		return m.GetEventDeliveriesForEndpointFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) ReplayEvents(ctx context.Context, arg database.ReplayEventsParams) (int64, error) {
+	if m.ReplayEventsFunc != nil {
+		return m.ReplayEventsFunc(ctx, arg)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) DeleteEventsBefore(ctx context.Context, createdAt time.Time) (int64, error) {
+	if m.DeleteEventsBeforeFunc != nil {
+		return m.DeleteEventsBeforeFunc(ctx, createdAt)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) CreateAPIKey(ctx context.Context, arg database.CreateAPIKeyParams) (database.ApiKey, error) {
+	if m.CreateAPIKeyFunc != nil {
+		return m.CreateAPIKeyFunc(ctx, arg)
+	}
+	return database.ApiKey{}, nil
+}
+
+func (m *mockDatabaseService) GetAPIKeysForUser(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error) {
+	if m.GetAPIKeysForUserFunc != nil {
+		return m.GetAPIKeysForUserFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) GetAPIKeyByHash(ctx context.Context, keyHash string) (database.ApiKey, error) {
+	if m.GetAPIKeyByHashFunc != nil {
+		return m.GetAPIKeyByHashFunc(ctx, keyHash)
+	}
+	return database.ApiKey{}, nil
+}
+
+func (m *mockDatabaseService) TouchAPIKey(ctx context.Context, id uuid.UUID) error {
+	if m.TouchAPIKeyFunc != nil {
+		return m.TouchAPIKeyFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockDatabaseService) DeleteAPIKey(ctx context.Context, arg database.DeleteAPIKeyParams) (int64, error) {
+	if m.DeleteAPIKeyFunc != nil {
+		return m.DeleteAPIKeyFunc(ctx, arg)
+	}
+	return 0, nil
+}
+
+func (m *mockDatabaseService) GetHoldingsForAccount(ctx context.Context, accountID string) ([]database.GetHoldingsForAccountRow, error) {
+	if m.GetHoldingsForAccountFunc != nil {
+		return m.GetHoldingsForAccountFunc(ctx, accountID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) GetInvestmentTransactionsForAccount(ctx context.Context, accountID string) ([]database.GetInvestmentTransactionsForAccountRow, error) {
+	if m.GetInvestmentTransactionsForAccountFunc != nil {
+		return m.GetInvestmentTransactionsForAccountFunc(ctx, accountID)
+	}
+	return nil, nil
+}
+
+func (m *mockDatabaseService) GetLiabilityForAccount(ctx context.Context, accountID string) (database.Liability, error) {
+	if m.GetLiabilityForAccountFunc != nil {
+		return m.GetLiabilityForAccountFunc(ctx, accountID)
+	}
+	return database.Liability{}, nil
+}
+
+func (m *mockDatabaseService) GetLiabilityAPRsForAccount(ctx context.Context, accountID string) ([]database.LiabilityApr, error) {
+	if m.GetLiabilityAPRsForAccountFunc != nil {
+		return m.GetLiabilityAPRsForAccountFunc(ctx, accountID)
+	}
+	return nil, nil
+}
+
 func (m *mockDatabaseService) CreateVerificationRecord(ctx context.Context, arg database.CreateVerificationRecordParams) (database.VerificationRecord, error) {
 	if m.CreateVerificationRecordFunc != nil {
 		return m.CreateVerificationRecordFunc(ctx, arg)
@@ -562,23 +1043,23 @@ func (p *mockPlaidService) CreateSandboxTokenWithCustomUser(ctx context.Context)
 	return plaid.ItemPublicTokenExchangeResponse{}, nil
 }
 
-func (p *mockPlaidService) GetLinkToken(ctx context.Context, userID, webhookURL string) (string, error) {
+func (p *mockPlaidService) GetLinkToken(ctx context.Context, userID, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 	if p.GetLinkTokenFunc != nil {
-		return p.GetLinkTokenFunc(ctx, userID, webhookURL)
+		return p.GetLinkTokenFunc(ctx, userID, webhookURL, options)
 	}
 	return "", nil
 }
 
-func (p *mockPlaidService) GetLinkTokenForUpdateMode(ctx context.Context, userID, accessToken, webhookURL string) (string, error) {
+func (p *mockPlaidService) GetLinkTokenForUpdateMode(ctx context.Context, userID, accessToken, webhookURL string, options plaidservice.LinkOptions) (string, error) {
 	if p.GetLinkTokenForUpdateModeFunc != nil {
-		return p.GetLinkTokenForUpdateModeFunc(ctx, userID, accessToken, webhookURL)
+		return p.GetLinkTokenForUpdateModeFunc(ctx, userID, accessToken, webhookURL, options)
 	}
 	return "", nil
 }
 
-func (p *mockPlaidService) GetAccessToken(ctx context.Context, publicToken string) (models.AccessResponse, error) {
+func (p *mockPlaidService) GetAccessToken(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error) {
 	if p.GetAccessTokenFunc != nil {
-		return p.GetAccessTokenFunc(ctx, publicToken)
+		return p.GetAccessTokenFunc(ctx, publicToken, countryCodes)
 	}
 	return models.AccessResponse{}, nil
 }
@@ -597,11 +1078,11 @@ func (p *mockPlaidService) GetAccounts(ctx context.Context, accessToken string)
 	return []plaid.AccountBase{}, "", nil
 }
 
-func (p *mockPlaidService) GetItemInstitution(ctx context.Context, accessToken string) (string, error) {
+func (p *mockPlaidService) GetItemInstitution(ctx context.Context, accessToken string, countryCodes []plaid.CountryCode) (string, string, error) {
 	if p.GetItemInstitutionFunc != nil {
-		return p.GetItemInstitutionFunc(ctx, accessToken)
+		return p.GetItemInstitutionFunc(ctx, accessToken, countryCodes)
 	}
-	return "TestInstitution", nil
+	return "TestInstitution", "CA", nil
 }
 
 func (p *mockPlaidService) GetBalances(ctx context.Context, accessToken string) (plaid.AccountsGetResponse, string, error) {
@@ -641,6 +1122,29 @@ func (p *mockPlaidService) GetRecurring(ctx context.Context, accessToken string)
 	return plaid.TransactionsRecurringGetResponse{}, nil
 }
 
+func (p *mockPlaidService) GetInvestmentHoldings(ctx context.Context, accessToken string) (plaid.InvestmentsHoldingsGetResponse, string, error) {
+	if p.GetInvestmentHoldingsFunc != nil {
+		return p.GetInvestmentHoldingsFunc(ctx, accessToken)
+	}
+	return plaid.InvestmentsHoldingsGetResponse{}, "", nil
+}
+
+func (p *mockPlaidService) GetInvestmentTransactions(ctx context.Context, accessToken, startDate, endDate string) (
+	txns []plaid.InvestmentTransaction, securities []plaid.Security, reqID string, err error,
+) {
+	if p.GetInvestmentTransactionsFunc != nil {
+		return p.GetInvestmentTransactionsFunc(ctx, accessToken, startDate, endDate)
+	}
+	return nil, nil, "", nil
+}
+
+func (p *mockPlaidService) GetLiabilities(ctx context.Context, accessToken string) (plaid.LiabilitiesObject, string, error) {
+	if p.GetLiabilitiesFunc != nil {
+		return p.GetLiabilitiesFunc(ctx, accessToken)
+	}
+	return plaid.LiabilitiesObject{}, "", nil
+}
+
 func (t *mockTxnUpdaterService) ExpireDelegation(ctx context.Context, tokenHash string, token database.RefreshToken) error {
 	if t.ExpireDelegationFunc != nil {
 		return t.ExpireDelegationFunc(ctx, tokenHash, token)
@@ -662,6 +1166,48 @@ func (t *mockTxnUpdaterService) ApplyTransactionUpdates(ctx context.Context, add
 	return nil
 }
 
+func (t *mockTxnUpdaterService) ApplyInvestmentUpdates(ctx context.Context, itemID string, securities []plaid.Security, holdings []plaid.Holding, txns []plaid.InvestmentTransaction) error {
+	if t.ApplyInvestmentUpdatesFunc != nil {
+		return t.ApplyInvestmentUpdatesFunc(ctx, itemID, securities, holdings, txns)
+	}
+	return nil
+}
+
+func (t *mockTxnUpdaterService) ApplyLiabilityUpdates(ctx context.Context, itemID string, liabilities plaid.LiabilitiesObject) error {
+	if t.ApplyLiabilityUpdatesFunc != nil {
+		return t.ApplyLiabilityUpdatesFunc(ctx, itemID, liabilities)
+	}
+	return nil
+}
+
+func (t *mockTxnUpdaterService) CreateManualTransaction(ctx context.Context, userID uuid.UUID, params database.CreateTransactionParams) (database.Transaction, error) {
+	if t.CreateManualTransactionFunc != nil {
+		return t.CreateManualTransactionFunc(ctx, userID, params)
+	}
+	return database.Transaction{ID: params.ID, AccountID: params.AccountID, Amount: params.Amount}, nil
+}
+
+func (t *mockTxnUpdaterService) UpdateManualTransaction(ctx context.Context, userID uuid.UUID, params database.UpdateManualTransactionParams) (database.Transaction, error) {
+	if t.UpdateManualTransactionFunc != nil {
+		return t.UpdateManualTransactionFunc(ctx, userID, params)
+	}
+	return database.Transaction{ID: params.ID, AccountID: params.AccountID, Amount: params.Amount}, nil
+}
+
+func (t *mockTxnUpdaterService) DeleteManualTransaction(ctx context.Context, userID uuid.UUID, txn database.Transaction) error {
+	if t.DeleteManualTransactionFunc != nil {
+		return t.DeleteManualTransactionFunc(ctx, userID, txn)
+	}
+	return nil
+}
+
+func (t *mockTxnUpdaterService) DeleteTransactionsForAccount(ctx context.Context, acc database.Account) error {
+	if t.DeleteTransactionsForAccountFunc != nil {
+		return t.DeleteTransactionsForAccountFunc(ctx, acc)
+	}
+	return nil
+}
+
 func (e *mockEncryptor) EncryptAccessToken(plaintext []byte, keyString string) (string, error) {
 	if e.EncryptAccessTokenFunc != nil {
 		return e.EncryptAccessTokenFunc(plaintext, keyString)
@@ -696,3 +1242,17 @@ func (q *mockQuerier) BuildSqlQuery(queries map[string]string, accountID string)
 	}
 	return "", nil, nil
 }
+
+func (m *mockWebhookSender) Send(ctx context.Context, url string, secret []byte, payload notify.Payload) error {
+	if m.SendFunc != nil {
+		return m.SendFunc(ctx, url, secret, payload)
+	}
+	return nil
+}
+
+func (m *mockWebhookSender) Post(ctx context.Context, url string, secret []byte, deliveryID string, body []byte) error {
+	if m.PostFunc != nil {
+		return m.PostFunc(ctx, url, secret, deliveryID, body)
+	}
+	return nil
+}
@@ -8,10 +8,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/api/plaidservice"
 	"github.com/jms-guy/greed/backend/api/sgrid"
 	"github.com/jms-guy/greed/backend/internal/auth"
 	"github.com/jms-guy/greed/backend/internal/config"
 	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/notify"
 	"github.com/jms-guy/greed/backend/internal/utils"
 	"github.com/jms-guy/greed/models"
 	"github.com/plaid/plaid-go/v36/plaid"
@@ -31,74 +33,142 @@ var (
 	testTxnID       = uuid.MustParse("1a2bc3d4-e5f6-7890-1234-567890abcfed")
 	testDate        = sql.NullTime{Time: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Valid: true}
 	testMerchant    = sql.NullString{String: "testMerchant", Valid: true}
+	testTransaction = database.Transaction{ID: testTxnID.String(), AccountID: testAccountID, Amount: "12.50", MerchantName: testMerchant, PersonalFinanceCategory: "FOOD_AND_DRINK"}
 )
 
 // Test database service
 type mockDatabaseService struct {
-	GetUserByNameFunc                      func(ctx context.Context, name string) (database.User, error)
-	CreateUserFunc                         func(ctx context.Context, params database.CreateUserParams) (database.User, error)
-	DeleteUserFunc                         func(ctx context.Context, id uuid.UUID) error
-	GetAllUsersFunc                        func(ctx context.Context) ([]string, error)
-	GetUserFunc                            func(ctx context.Context, id uuid.UUID) (database.User, error)
-	GetUserByEmailFunc                     func(ctx context.Context, email string) (database.User, error)
-	ResetUsersFunc                         func(ctx context.Context) error
-	UpdateFreeCallsFunc                    func(ctx context.Context, id uuid.UUID) error
-	UpdateMemberFunc                       func(ctx context.Context, id uuid.UUID) error
-	UpdatePasswordFunc                     func(ctx context.Context, arg database.UpdatePasswordParams) error
-	VerifyUserFunc                         func(ctx context.Context, id uuid.UUID) error
-	GetAllAccountsForUserFunc              func(ctx context.Context, userID uuid.UUID) ([]database.Account, error)
-	CreateAccountFunc                      func(ctx context.Context, arg database.CreateAccountParams) (database.Account, error)
-	DeleteAccountFunc                      func(ctx context.Context, arg database.DeleteAccountParams) error
-	GetAccountFunc                         func(ctx context.Context, name string) (database.Account, error)
-	GetAccountByIdFunc                     func(ctx context.Context, arg database.GetAccountByIdParams) (database.Account, error)
-	GetAccountsForItemFunc                 func(ctx context.Context, itemID string) ([]database.Account, error)
-	ResetAccountsFunc                      func(ctx context.Context) error
-	UpdateBalancesFunc                     func(ctx context.Context, arg database.UpdateBalancesParams) (database.Account, error)
-	GetMerchantSummaryFunc                 func(ctx context.Context, accountID string) ([]database.GetMerchantSummaryRow, error)
-	GetMerchantSummaryByMonthFunc          func(ctx context.Context, arg database.GetMerchantSummaryByMonthParams) ([]database.GetMerchantSummaryByMonthRow, error)
-	GetMonetaryDataForAllMonthsFunc        func(ctx context.Context, accountID string) ([]database.GetMonetaryDataForAllMonthsRow, error)
-	GetMonetaryDataForMonthFunc            func(ctx context.Context, arg database.GetMonetaryDataForMonthParams) (database.GetMonetaryDataForMonthRow, error)
-	ValidateCurrencyFunc                   func(ctx context.Context, code string) (bool, error)
-	CreateDelegationFunc                   func(ctx context.Context, arg database.CreateDelegationParams) (database.Delegation, error)
-	GetDelegationFunc                      func(ctx context.Context, id uuid.UUID) (database.Delegation, error)
-	RevokeDelegationByIDFunc               func(ctx context.Context, id uuid.UUID) error
-	RevokeDelegationByUserFunc             func(ctx context.Context, userID uuid.UUID) error
-	UpdateLastUsedFunc                     func(ctx context.Context, id uuid.UUID) error
-	CreateItemFunc                         func(ctx context.Context, arg database.CreateItemParams) (database.PlaidItem, error)
-	DeleteItemFunc                         func(ctx context.Context, arg database.DeleteItemParams) error
-	GetAccessTokenFunc                     func(ctx context.Context, id string) (database.PlaidItem, error)
-	GetCursorFunc                          func(ctx context.Context, arg database.GetCursorParams) (sql.NullString, error)
-	GetItemByIDFunc                        func(ctx context.Context, id string) (database.PlaidItem, error)
-	GetItemByNameFunc                      func(ctx context.Context, nickname sql.NullString) (database.PlaidItem, error)
-	GetItemsByUserFunc                     func(ctx context.Context, userID uuid.UUID) ([]database.PlaidItem, error)
-	GetLatestCursorOrNilFunc               func(ctx context.Context, id string) (sql.NullString, error)
-	ResetItemsFunc                         func(ctx context.Context) error
-	UpdateCursorFunc                       func(ctx context.Context, arg database.UpdateCursorParams) error
-	UpdateNicknameFunc                     func(ctx context.Context, arg database.UpdateNicknameParams) error
-	CreateTokenFunc                        func(ctx context.Context, arg database.CreateTokenParams) (database.RefreshToken, error)
-	ExpireAllDelegationTokensFunc          func(ctx context.Context, delegationID uuid.UUID) error
-	ExpireTokenFunc                        func(ctx context.Context, hashedToken string) error
-	GetTokenFunc                           func(ctx context.Context, hashedToken string) (database.RefreshToken, error)
-	ClearTransactionsTableFunc             func(ctx context.Context) error
-	CreateTransactionFunc                  func(ctx context.Context, arg database.CreateTransactionParams) (database.Transaction, error)
-	DeleteTransactionFunc                  func(ctx context.Context, arg database.DeleteTransactionParams) error
-	DeleteTransactionsForAccountFunc       func(ctx context.Context, accountID string) error
-	GetTransactionsFunc                    func(ctx context.Context, accountID string) ([]database.Transaction, error)
-	GetTransactionsForUserFunc             func(ctx context.Context, userID uuid.UUID) ([]database.Transaction, error)
-	CreateVerificationRecordFunc           func(ctx context.Context, arg database.CreateVerificationRecordParams) (database.VerificationRecord, error)
-	DeleteVerificationRecordFunc           func(ctx context.Context, verificationCode string) error
-	DeleteVerificationRecordByUserFunc     func(ctx context.Context, userID uuid.UUID) error
-	GetVerificationRecordFunc              func(ctx context.Context, verificationCode string) (database.VerificationRecord, error)
-	GetVerificationRecordByUserFunc        func(ctx context.Context, userID uuid.UUID) (database.VerificationRecord, error)
-	CreatePlaidWebhookRecordFunc           func(ctx context.Context, arg database.CreatePlaidWebhookRecordParams) (database.PlaidWebhookRecord, error)
-	ProcessWebhookRecordsByTypeFunc        func(ctx context.Context, arg database.ProcessWebhookRecordsByTypeParams) error
-	GetWebhookRecordsFunc                  func(ctx context.Context, userID uuid.UUID) ([]database.PlaidWebhookRecord, error)
-	CreateStreamFunc                       func(ctx context.Context, arg database.CreateStreamParams) error
-	CreateTransactionToStreamRecordFunc    func(ctx context.Context, arg database.CreateTransactionToStreamRecordParams) error
-	CreateTransactionToTagRecordFunc       func(ctx context.Context, arg database.CreateTransactionToTagRecordParams) error
-	GetStreamsForAccFunc                   func(ctx context.Context, accountID string) ([]database.RecurringStream, error)
-	GetTransactionsToStreamConnectionsFunc func(ctx context.Context, streamID string) ([]database.TransactionsToStream, error)
-	WithTxFunc                             func(tx *sql.Tx) *database.Queries
+	GetUserByNameFunc                       func(ctx context.Context, name string) (database.User, error)
+	CreateUserFunc                          func(ctx context.Context, params database.CreateUserParams) (database.User, error)
+	DeleteUserFunc                          func(ctx context.Context, id uuid.UUID) error
+	GetAllUsersFunc                         func(ctx context.Context) ([]string, error)
+	GetUserFunc                             func(ctx context.Context, id uuid.UUID) (database.User, error)
+	GetUserByEmailFunc                      func(ctx context.Context, email string) (database.User, error)
+	ResetUsersFunc                          func(ctx context.Context) error
+	UpdateFreeCallsFunc                     func(ctx context.Context, id uuid.UUID) error
+	UpdateMemberFunc                        func(ctx context.Context, id uuid.UUID) error
+	UpdatePasswordFunc                      func(ctx context.Context, arg database.UpdatePasswordParams) error
+	VerifyUserFunc                          func(ctx context.Context, id uuid.UUID) error
+	GetAllAccountsForUserFunc               func(ctx context.Context, userID uuid.UUID) ([]database.Account, error)
+	GetAccountOverrideFunc                  func(ctx context.Context, accountID string) (database.AccountOverride, error)
+	GetAccountOverridesForUserFunc          func(ctx context.Context, userID uuid.UUID) ([]database.AccountOverride, error)
+	UpsertAccountOverrideFunc               func(ctx context.Context, arg database.UpsertAccountOverrideParams) (database.AccountOverride, error)
+	GetTransactionNoteFunc                  func(ctx context.Context, transactionID string) (database.TransactionNote, error)
+	UpsertTransactionNoteFunc               func(ctx context.Context, arg database.UpsertTransactionNoteParams) (database.TransactionNote, error)
+	DeleteTransactionNoteFunc               func(ctx context.Context, transactionID string) error
+	CreateTransactionAttachmentFunc         func(ctx context.Context, arg database.CreateTransactionAttachmentParams) (database.TransactionAttachment, error)
+	GetTransactionAttachmentsFunc           func(ctx context.Context, transactionID string) ([]database.TransactionAttachment, error)
+	GetTransactionAttachmentFunc            func(ctx context.Context, arg database.GetTransactionAttachmentParams) (database.TransactionAttachment, error)
+	DeleteTransactionAttachmentFunc         func(ctx context.Context, id uuid.UUID) error
+	GetOrphanedAttachmentBlobsFunc          func(ctx context.Context, limit int32) ([]string, error)
+	DeleteOrphanedAttachmentBlobFunc        func(ctx context.Context, storageKey string) error
+	GetMerchantAliasesFunc                  func(ctx context.Context, userID uuid.UUID) ([]database.MerchantAlias, error)
+	UpsertMerchantAliasFunc                 func(ctx context.Context, arg database.UpsertMerchantAliasParams) (database.MerchantAlias, error)
+	DeleteMerchantAliasFunc                 func(ctx context.Context, arg database.DeleteMerchantAliasParams) (int64, error)
+	GetUserMerchantNamesFunc                func(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error)
+	SetCanonicalMerchantFunc                func(ctx context.Context, arg database.SetCanonicalMerchantParams) (int64, error)
+	GetUsersWithUnnormalizedMerchantsFunc   func(ctx context.Context) ([]uuid.UUID, error)
+	CreateAccountFunc                       func(ctx context.Context, arg database.CreateAccountParams) (database.Account, error)
+	CreateManualAccountFunc                 func(ctx context.Context, arg database.CreateManualAccountParams) (database.Account, error)
+	DeleteAccountFunc                       func(ctx context.Context, arg database.DeleteAccountParams) error
+	GetAccountFunc                          func(ctx context.Context, name string) (database.Account, error)
+	GetAccountByIdFunc                      func(ctx context.Context, arg database.GetAccountByIdParams) (database.Account, error)
+	GetAccountsForItemFunc                  func(ctx context.Context, itemID string) ([]database.Account, error)
+	ResetAccountsFunc                       func(ctx context.Context) error
+	UpdateBalancesFunc                      func(ctx context.Context, arg database.UpdateBalancesParams) (database.Account, error)
+	GetMerchantSummaryFunc                  func(ctx context.Context, accountID string) ([]database.GetMerchantSummaryRow, error)
+	GetMerchantSummaryByMonthFunc           func(ctx context.Context, arg database.GetMerchantSummaryByMonthParams) ([]database.GetMerchantSummaryByMonthRow, error)
+	GetMonetaryDataForAllMonthsFunc         func(ctx context.Context, accountID string) ([]database.GetMonetaryDataForAllMonthsRow, error)
+	GetMonetaryDataForMonthFunc             func(ctx context.Context, arg database.GetMonetaryDataForMonthParams) (database.GetMonetaryDataForMonthRow, error)
+	GetSpendingBreakdownFunc                func(ctx context.Context, arg database.GetSpendingBreakdownParams) ([]database.GetSpendingBreakdownRow, error)
+	GetUserSpendingBreakdownFunc            func(ctx context.Context, arg database.GetUserSpendingBreakdownParams) ([]database.GetUserSpendingBreakdownRow, error)
+	ValidateCurrencyFunc                    func(ctx context.Context, code string) (bool, error)
+	CreateDelegationFunc                    func(ctx context.Context, arg database.CreateDelegationParams) (database.Delegation, error)
+	GetDelegationFunc                       func(ctx context.Context, id uuid.UUID) (database.Delegation, error)
+	RevokeDelegationByIDFunc                func(ctx context.Context, id uuid.UUID) error
+	RevokeDelegationByUserFunc              func(ctx context.Context, userID uuid.UUID) error
+	UpdateLastUsedFunc                      func(ctx context.Context, id uuid.UUID) error
+	CreateItemFunc                          func(ctx context.Context, arg database.CreateItemParams) (database.PlaidItem, error)
+	DeleteItemFunc                          func(ctx context.Context, arg database.DeleteItemParams) error
+	GetAccessTokenFunc                      func(ctx context.Context, id string) (database.PlaidItem, error)
+	GetCursorFunc                           func(ctx context.Context, arg database.GetCursorParams) (sql.NullString, error)
+	GetItemByIDFunc                         func(ctx context.Context, id string) (database.PlaidItem, error)
+	GetItemByNameFunc                       func(ctx context.Context, nickname sql.NullString) (database.PlaidItem, error)
+	GetItemsByUserFunc                      func(ctx context.Context, userID uuid.UUID) ([]database.PlaidItem, error)
+	GetLatestCursorOrNilFunc                func(ctx context.Context, id string) (sql.NullString, error)
+	ResetItemsFunc                          func(ctx context.Context) error
+	UpdateCursorFunc                        func(ctx context.Context, arg database.UpdateCursorParams) error
+	UpdateNicknameFunc                      func(ctx context.Context, arg database.UpdateNicknameParams) error
+	ClearItemErrorFunc                      func(ctx context.Context, itemID string) error
+	GetItemStatusesForUserFunc              func(ctx context.Context, userID uuid.UUID) ([]database.ItemStatus, error)
+	RecordItemErrorFunc                     func(ctx context.Context, arg database.RecordItemErrorParams) error
+	RecordItemSyncedFunc                    func(ctx context.Context, arg database.RecordItemSyncedParams) error
+	SetItemConsentExpirationFunc            func(ctx context.Context, arg database.SetItemConsentExpirationParams) error
+	CreateTokenFunc                         func(ctx context.Context, arg database.CreateTokenParams) (database.RefreshToken, error)
+	ExpireAllDelegationTokensFunc           func(ctx context.Context, delegationID uuid.UUID) error
+	ExpireTokenFunc                         func(ctx context.Context, hashedToken string) error
+	GetTokenFunc                            func(ctx context.Context, hashedToken string) (database.RefreshToken, error)
+	ClearTransactionsTableFunc              func(ctx context.Context) error
+	CreateTransactionFunc                   func(ctx context.Context, arg database.CreateTransactionParams) (database.Transaction, error)
+	DeleteTransactionFunc                   func(ctx context.Context, arg database.DeleteTransactionParams) error
+	DeleteTransactionsForAccountFunc        func(ctx context.Context, accountID string) error
+	GetTransactionsFunc                     func(ctx context.Context, accountID string) ([]database.Transaction, error)
+	GetTransactionsForUserFunc              func(ctx context.Context, userID uuid.UUID) ([]database.Transaction, error)
+	GetTransactionForAccountFunc            func(ctx context.Context, arg database.GetTransactionForAccountParams) (database.Transaction, error)
+	UpdateTransactionCategoryFunc           func(ctx context.Context, arg database.UpdateTransactionCategoryParams) (database.Transaction, error)
+	GetTransactionsByIDsFunc                func(ctx context.Context, arg database.GetTransactionsByIDsParams) ([]database.Transaction, error)
+	GetTransactionChangesFunc               func(ctx context.Context, arg database.GetTransactionChangesParams) ([]database.TransactionChange, error)
+	RecordTransactionChangesFunc            func(ctx context.Context, arg database.RecordTransactionChangesParams) error
+	UpsertTagFunc                           func(ctx context.Context, arg database.UpsertTagParams) (database.TransactionTag, error)
+	GetTagNamesForTransactionFunc           func(ctx context.Context, transactionID string) ([]string, error)
+	CreateAnomalyFunc                       func(ctx context.Context, arg database.CreateAnomalyParams) error
+	GetAnomaliesForUserFunc                 func(ctx context.Context, arg database.GetAnomaliesForUserParams) ([]database.GetAnomaliesForUserRow, error)
+	AcknowledgeAnomalyFunc                  func(ctx context.Context, arg database.AcknowledgeAnomalyParams) (database.Anomaly, error)
+	AcknowledgeAllAnomaliesFunc             func(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateNotificationRuleFunc              func(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error)
+	GetNotificationRulesForUserFunc         func(ctx context.Context, userID uuid.UUID) ([]database.NotificationRule, error)
+	GetEnabledNotificationRulesFunc         func(ctx context.Context, arg database.GetEnabledNotificationRulesParams) ([]database.NotificationRule, error)
+	GetNotificationRuleFunc                 func(ctx context.Context, id uuid.UUID) (database.NotificationRule, error)
+	DeleteNotificationRuleFunc              func(ctx context.Context, arg database.DeleteNotificationRuleParams) (int64, error)
+	CreateNotificationDeliveryFunc          func(ctx context.Context, arg database.CreateNotificationDeliveryParams) error
+	ClaimDueNotificationDeliveriesFunc      func(ctx context.Context, arg database.ClaimDueNotificationDeliveriesParams) ([]database.NotificationDelivery, error)
+	RecordNotificationAttemptFunc           func(ctx context.Context, arg database.RecordNotificationAttemptParams) error
+	GetNotificationDeliveriesForUserFunc    func(ctx context.Context, arg database.GetNotificationDeliveriesForUserParams) ([]database.GetNotificationDeliveriesForUserRow, error)
+	RetryNotificationDeliveryFunc           func(ctx context.Context, arg database.RetryNotificationDeliveryParams) (database.NotificationDelivery, error)
+	CreateEventFunc                         func(ctx context.Context, arg database.CreateEventParams) error
+	CreateEventEndpointFunc                 func(ctx context.Context, arg database.CreateEventEndpointParams) (database.EventEndpoint, error)
+	GetEventEndpointsForUserFunc            func(ctx context.Context, userID uuid.UUID) ([]database.EventEndpoint, error)
+	DeleteEventEndpointFunc                 func(ctx context.Context, arg database.DeleteEventEndpointParams) (int64, error)
+	GetEventsForUserFunc                    func(ctx context.Context, arg database.GetEventsForUserParams) ([]database.GetEventsForUserRow, error)
+	FanOutEventsFunc                        func(ctx context.Context, batchSize int32) (int64, error)
+	ClaimDueEventDeliveriesFunc             func(ctx context.Context, arg database.ClaimDueEventDeliveriesParams) ([]database.ClaimDueEventDeliveriesRow, error)
+	RecordEventDeliveryAttemptFunc          func(ctx context.Context, arg database.RecordEventDeliveryAttemptParams) error
+	GetEventDeliveriesForEndpointFunc       func(ctx context.Context, arg database.GetEventDeliveriesForEndpointParams) ([]database.GetEventDeliveriesForEndpointRow, error)
+	ReplayEventsFunc                        func(ctx context.Context, arg database.ReplayEventsParams) (int64, error)
+	DeleteEventsBeforeFunc                  func(ctx context.Context, createdAt time.Time) (int64, error)
+	CreateAPIKeyFunc                        func(ctx context.Context, arg database.CreateAPIKeyParams) (database.ApiKey, error)
+	GetAPIKeysForUserFunc                   func(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error)
+	GetAPIKeyByHashFunc                     func(ctx context.Context, keyHash string) (database.ApiKey, error)
+	TouchAPIKeyFunc                         func(ctx context.Context, id uuid.UUID) error
+	DeleteAPIKeyFunc                        func(ctx context.Context, arg database.DeleteAPIKeyParams) (int64, error)
+	GetHoldingsForAccountFunc               func(ctx context.Context, accountID string) ([]database.GetHoldingsForAccountRow, error)
+	GetInvestmentTransactionsForAccountFunc func(ctx context.Context, accountID string) ([]database.GetInvestmentTransactionsForAccountRow, error)
+	GetLiabilityForAccountFunc              func(ctx context.Context, accountID string) (database.Liability, error)
+	GetLiabilityAPRsForAccountFunc          func(ctx context.Context, accountID string) ([]database.LiabilityApr, error)
+	CreateVerificationRecordFunc            func(ctx context.Context, arg database.CreateVerificationRecordParams) (database.VerificationRecord, error)
+	DeleteVerificationRecordFunc            func(ctx context.Context, verificationCode string) error
+	DeleteVerificationRecordByUserFunc      func(ctx context.Context, userID uuid.UUID) error
+	GetVerificationRecordFunc               func(ctx context.Context, verificationCode string) (database.VerificationRecord, error)
+	GetVerificationRecordByUserFunc         func(ctx context.Context, userID uuid.UUID) (database.VerificationRecord, error)
+	CreatePlaidWebhookRecordFunc            func(ctx context.Context, arg database.CreatePlaidWebhookRecordParams) (database.PlaidWebhookRecord, error)
+	ProcessWebhookRecordsByTypeFunc         func(ctx context.Context, arg database.ProcessWebhookRecordsByTypeParams) error
+	GetWebhookRecordsFunc                   func(ctx context.Context, userID uuid.UUID) ([]database.PlaidWebhookRecord, error)
+	CreateStreamFunc                        func(ctx context.Context, arg database.CreateStreamParams) error
+	CreateTransactionToStreamRecordFunc     func(ctx context.Context, arg database.CreateTransactionToStreamRecordParams) error
+	CreateTransactionToTagRecordFunc        func(ctx context.Context, arg database.CreateTransactionToTagRecordParams) error
+	GetStreamsForAccFunc                    func(ctx context.Context, accountID string) ([]database.RecurringStream, error)
+	GetTransactionsToStreamConnectionsFunc  func(ctx context.Context, streamID string) ([]database.TransactionsToStream, error)
+	WithTxFunc                              func(tx *sql.Tx) *database.Queries
 }
 
 // Test Auth service
@@ -125,18 +195,22 @@ type mockMailService struct {
 type mockPlaidService struct {
 	GetSandboxTokenFunc                  func(ctx context.Context) (plaid.ItemPublicTokenExchangeResponse, error)
 	CreateSandboxTokenWithCustomUserFunc func(ctx context.Context) (plaid.ItemPublicTokenExchangeResponse, error)
-	GetLinkTokenFunc                     func(ctx context.Context, userID, webhookURL string) (string, error)
-	GetLinkTokenForUpdateModeFunc        func(ctx context.Context, userID, accessToken, webhookURL string) (string, error)
-	GetAccessTokenFunc                   func(ctx context.Context, publicToken string) (models.AccessResponse, error)
+	GetLinkTokenFunc                     func(ctx context.Context, userID, webhookURL string, options plaidservice.LinkOptions) (string, error)
+	GetLinkTokenForUpdateModeFunc        func(ctx context.Context, userID, accessToken, webhookURL string, options plaidservice.LinkOptions) (string, error)
+	GetAccessTokenFunc                   func(ctx context.Context, publicToken string, countryCodes []plaid.CountryCode) (models.AccessResponse, error)
 	InvalidateAccessTokenFunc            func(ctx context.Context, accessToken models.AccessResponse) (models.AccessResponse, error)
 	GetAccountsFunc                      func(ctx context.Context, accessToken string) ([]plaid.AccountBase, string, error)
-	GetItemInstitutionFunc               func(ctx context.Context, accessToken string) (string, error)
+	GetItemInstitutionFunc               func(ctx context.Context, accessToken string, countryCodes []plaid.CountryCode) (string, string, error)
 	GetBalancesFunc                      func(ctx context.Context, accessToken string) (plaid.AccountsGetResponse, string, error)
 	GetTransactionsFunc                  func(ctx context.Context, accessToken, cursor string) (
 		added, modified []plaid.Transaction, removed []plaid.RemovedTransaction, nextCursor, reqID string, err error)
 	GetWebhookVerificationKeyFunc func(ctx context.Context, keyID string) (plaid.JWKPublicKey, error)
 	RemoveItemFunc                func(ctx context.Context, accessToken string) error
 	GetRecurringFunc              func(ctx context.Context, accessToken string) (plaid.TransactionsRecurringGetResponse, error)
+	GetInvestmentHoldingsFunc     func(ctx context.Context, accessToken string) (plaid.InvestmentsHoldingsGetResponse, string, error)
+	GetInvestmentTransactionsFunc func(ctx context.Context, accessToken, startDate, endDate string) (
+		txns []plaid.InvestmentTransaction, securities []plaid.Security, reqID string, err error)
+	GetLiabilitiesFunc func(ctx context.Context, accessToken string) (plaid.LiabilitiesObject, string, error)
 }
 
 // Test TxnUpdater service
@@ -151,6 +225,18 @@ type mockTxnUpdaterService struct {
 		nextCursor string,
 		itemID string,
 	) error
+	ApplyInvestmentUpdatesFunc func(
+		ctx context.Context,
+		itemID string,
+		securities []plaid.Security,
+		holdings []plaid.Holding,
+		txns []plaid.InvestmentTransaction,
+	) error
+	ApplyLiabilityUpdatesFunc        func(ctx context.Context, itemID string, liabilities plaid.LiabilitiesObject) error
+	CreateManualTransactionFunc      func(ctx context.Context, userID uuid.UUID, params database.CreateTransactionParams) (database.Transaction, error)
+	UpdateManualTransactionFunc      func(ctx context.Context, userID uuid.UUID, params database.UpdateManualTransactionParams) (database.Transaction, error)
+	DeleteManualTransactionFunc      func(ctx context.Context, userID uuid.UUID, txn database.Transaction) error
+	DeleteTransactionsForAccountFunc func(ctx context.Context, acc database.Account) error
 }
 
 // Test Encryptor service
@@ -159,6 +245,12 @@ type mockEncryptor struct {
 	DecryptAccessTokenFunc func(ciphertext, keyString string) ([]byte, error)
 }
 
+// Test webhook sender
+type mockWebhookSender struct {
+	SendFunc func(ctx context.Context, url string, secret []byte, payload notify.Payload) error
+	PostFunc func(ctx context.Context, url string, secret []byte, deliveryID string, body []byte) error
+}
+
 // Test Querier service
 type mockQuerier struct {
 	ValidateParamValueFunc func(value, expectedType string) (bool, error)
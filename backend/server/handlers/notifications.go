@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/api/sgrid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/notify"
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+/*
+	Notification rules are evaluated where the events they watch for happen: syncs, balance updates and Plaid
+	webhooks. A triggered rule queues a delivery, keyed on the event so the same rule is never notified of it
+	twice, and the dispatcher worker sends queued deliveries in the background, retrying failures with backoff.
+	Evaluation errors are logged rather than returned, so notifications never fail the request raising them.
+*/
+
+const (
+	notificationPollInterval = 30 * time.Second // How often the dispatcher checks for due deliveries
+	notificationBatchSize    = 20               // Deliveries claimed by the dispatcher at a time
+	notificationLease        = 5 * time.Minute  // Time a claimed delivery is hidden from other dispatchers
+)
+
+// Event that triggered a notification rule
+type notificationEvent struct {
+	key     string // Identifies the event, a rule is notified once per key
+	subject string
+	message string
+}
+
+// Queues deliveries for the user's enabled rules of the given kind. match is called for each rule, and
+// returns the events triggering it
+func (app *AppServer) queueNotifications(ctx context.Context, userID uuid.UUID, kind string, match func(rule database.NotificationRule) []notificationEvent) {
+	rules, err := app.Db.GetEnabledNotificationRules(ctx, database.GetEnabledNotificationRulesParams{
+		UserID: userID,
+		Kind:   kind,
+	})
+	if err != nil {
+		app.logNotificationError(kind, fmt.Errorf("error getting notification rules: %w", err))
+		return
+	}
+
+	for _, rule := range rules {
+		for _, event := range match(rule) {
+			err := app.Db.CreateNotificationDelivery(ctx, database.CreateNotificationDeliveryParams{
+				ID:       uuid.New(),
+				RuleID:   rule.ID,
+				UserID:   userID,
+				EventKey: event.key,
+				Subject:  event.subject,
+				Message:  event.message,
+			})
+			if err != nil {
+				app.logNotificationError(kind, fmt.Errorf("error queueing notification delivery: %w", err))
+				return
+			}
+		}
+	}
+}
+
+// Notifies of synced transactions over a rule's threshold
+func (app *AppServer) notifyLargeTransactions(ctx context.Context, userID uuid.UUID, added []plaid.Transaction) {
+	if len(added) == 0 {
+		return
+	}
+
+	app.queueNotifications(ctx, userID, notify.KindLargeTransaction, func(rule database.NotificationRule) []notificationEvent {
+		threshold, ok := ruleThreshold(rule)
+		if !ok {
+			return nil
+		}
+
+		var events []notificationEvent
+		for _, txn := range added {
+			if txn.Amount < threshold || !ruleCoversAccount(rule, txn.AccountId) {
+				continue
+			}
+			merchant := txn.GetMerchantName()
+			if merchant == "" {
+				merchant = txn.Name
+			}
+			events = append(events, notificationEvent{
+				key:     "txn:" + txn.TransactionId,
+				subject: fmt.Sprintf("Large transaction of %.2f %s", txn.Amount, txn.GetIsoCurrencyCode()),
+				message: fmt.Sprintf("A charge of %.2f %s from %s on %s is over your limit of %.2f.",
+					txn.Amount, txn.GetIsoCurrencyCode(), merchant, txn.Date, threshold),
+			})
+		}
+		return events
+	})
+}
+
+// Notifies of recurring charges Plaid has newly detected
+func (app *AppServer) notifyNewRecurring(ctx context.Context, userID uuid.UUID, streams []plaid.TransactionStream) {
+	if len(streams) == 0 {
+		return
+	}
+
+	app.queueNotifications(ctx, userID, notify.KindNewRecurring, func(rule database.NotificationRule) []notificationEvent {
+		var events []notificationEvent
+		for _, stream := range streams {
+			if !ruleCoversAccount(rule, stream.AccountId) {
+				continue
+			}
+			name := stream.GetMerchantName()
+			if name == "" {
+				name = stream.Description
+			}
+			events = append(events, notificationEvent{
+				key:     "stream:" + stream.StreamId,
+				subject: fmt.Sprintf("New recurring charge from %s", name),
+				message: fmt.Sprintf("A new %s recurring charge from %s was found, last charged %.2f.",
+					strings.ToLower(string(stream.Frequency)), name, stream.LastAmount.GetAmount()),
+			})
+		}
+		return events
+	})
+}
+
+// Notifies of categories whose spending this month is over a rule's threshold, once per month
+func (app *AppServer) notifyBudgets(ctx context.Context, userID uuid.UUID) {
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	startDate := sql.NullTime{Time: start, Valid: true}
+	endDate := sql.NullTime{Time: end, Valid: true}
+
+	app.queueNotifications(ctx, userID, notify.KindBudgetExceeded, func(rule database.NotificationRule) []notificationEvent {
+		threshold, ok := ruleThreshold(rule)
+		if !ok || !rule.Category.Valid {
+			return nil
+		}
+
+		spent := 0.0
+		if rule.AccountID.Valid {
+			rows, err := app.Db.GetSpendingBreakdown(ctx, database.GetSpendingBreakdownParams{
+				AccountID: rule.AccountID.String,
+				StartDate: startDate,
+				EndDate:   endDate,
+			})
+			if err != nil {
+				app.logNotificationError(rule.Kind, fmt.Errorf("error getting account spending: %w", err))
+				return nil
+			}
+			for _, row := range rows {
+				if row.Category == rule.Category.String {
+					spent += row.TotalAmount
+				}
+			}
+		} else {
+			// Budgets count all spending, including on accounts hidden from listings
+			rows, err := app.Db.GetUserSpendingBreakdown(ctx, database.GetUserSpendingBreakdownParams{
+				UserID:        userID,
+				StartDate:     startDate,
+				EndDate:       endDate,
+				IncludeHidden: true,
+			})
+			if err != nil {
+				app.logNotificationError(rule.Kind, fmt.Errorf("error getting user spending: %w", err))
+				return nil
+			}
+			for _, row := range rows {
+				if row.Category == rule.Category.String {
+					spent += row.TotalAmount
+				}
+			}
+		}
+
+		if spent <= threshold {
+			return nil
+		}
+
+		return []notificationEvent{{
+			key:     "budget:" + start.Format("2006-01"),
+			subject: fmt.Sprintf("%s budget exceeded", rule.Category.String),
+			message: fmt.Sprintf("You have spent %.2f on %s in %s, over your budget of %.2f.",
+				spent, rule.Category.String, start.Format("January 2006"), threshold),
+		}}
+	})
+}
+
+// Notifies of depository accounts whose balance is under a rule's threshold, at most once a day per account
+func (app *AppServer) notifyLowBalances(ctx context.Context, userID uuid.UUID, accounts []plaid.AccountBase) {
+	if len(accounts) == 0 {
+		return
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+
+	app.queueNotifications(ctx, userID, notify.KindLowBalance, func(rule database.NotificationRule) []notificationEvent {
+		threshold, ok := ruleThreshold(rule)
+		if !ok {
+			return nil
+		}
+
+		var events []notificationEvent
+		for _, acc := range accounts {
+			if acc.Type != plaid.ACCOUNTTYPE_DEPOSITORY || !ruleCoversAccount(rule, acc.AccountId) {
+				continue
+			}
+
+			var balance float64
+			switch {
+			case acc.Balances.Available.IsSet() && acc.Balances.Available.Get() != nil:
+				balance = *acc.Balances.Available.Get()
+			case acc.Balances.Current.IsSet() && acc.Balances.Current.Get() != nil:
+				balance = *acc.Balances.Current.Get()
+			default:
+				continue
+			}
+			if balance >= threshold {
+				continue
+			}
+
+			events = append(events, notificationEvent{
+				key:     fmt.Sprintf("balance:%s:%s", acc.AccountId, today),
+				subject: fmt.Sprintf("Low balance in %s", acc.Name),
+				message: fmt.Sprintf("The balance of %s is %.2f %s, under your limit of %.2f.",
+					acc.Name, balance, acc.Balances.GetIsoCurrencyCode(), threshold),
+			})
+		}
+		return events
+	})
+}
+
+// Notifies that an item needs the user to log in to their institution again
+func (app *AppServer) notifyItemReauth(ctx context.Context, item database.PlaidItem, code string) {
+	name := item.InstitutionName
+	if item.Nickname.Valid && item.Nickname.String != "" {
+		name = item.Nickname.String
+	}
+
+	app.queueNotifications(ctx, item.UserID, notify.KindItemReauth, func(rule database.NotificationRule) []notificationEvent {
+		return []notificationEvent{{
+			key:     fmt.Sprintf("item:%s:%s:%s", item.ID, code, time.Now().UTC().Format("2006-01-02")),
+			subject: fmt.Sprintf("%s needs to be reconnected", name),
+			message: fmt.Sprintf("Your connection to %s needs attention (%s). Run 'greed update %s' to log in again.", name, code, name),
+		}}
+	})
+}
+
+// Background worker sending queued notification deliveries until the context is cancelled
+func (app *AppServer) RunNotificationDispatcher(ctx context.Context) error {
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		app.DispatchNotifications(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Claims due deliveries and sends them, recording the outcome of each. Failed deliveries are retried
+// with backoff, and marked failed after notify.MaxAttempts
+func (app *AppServer) DispatchNotifications(ctx context.Context) {
+	deliveries, err := app.Db.ClaimDueNotificationDeliveries(ctx, database.ClaimDueNotificationDeliveriesParams{
+		LeaseUntil: time.Now().Add(notificationLease),
+		BatchSize:  notificationBatchSize,
+	})
+	if err != nil {
+		app.logNotificationError("", fmt.Errorf("error claiming notification deliveries: %w", err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		// Unsent deliveries are claimed again once their lease runs out
+		if ctx.Err() != nil {
+			return
+		}
+
+		params := database.RecordNotificationAttemptParams{
+			ID:            delivery.ID,
+			Status:        notify.StatusDelivered,
+			NextAttemptAt: time.Now(),
+		}
+
+		if err := app.deliverNotification(ctx, delivery); err != nil {
+			attempts := int(delivery.Attempts) + 1
+			params.LastError = sql.NullString{String: err.Error(), Valid: true}
+			if attempts >= notify.MaxAttempts {
+				params.Status = notify.StatusFailed
+			} else {
+				params.Status = notify.StatusPending
+				params.NextAttemptAt = time.Now().Add(notify.Backoff(attempts))
+			}
+			_ = app.Logger.Log(
+				"level", "warning",
+				"msg", "notification delivery failed",
+				"delivery_id", delivery.ID,
+				"attempts", attempts,
+				"err", err,
+			)
+		}
+
+		if err := app.Db.RecordNotificationAttempt(ctx, params); err != nil {
+			app.logNotificationError("", fmt.Errorf("error recording notification attempt: %w", err))
+		}
+	}
+}
+
+// Sends a delivery through its rule's channel
+func (app *AppServer) deliverNotification(ctx context.Context, delivery database.NotificationDelivery) error {
+	rule, err := app.Db.GetNotificationRule(ctx, delivery.RuleID)
+	if err != nil {
+		return fmt.Errorf("error getting notification rule: %w", err)
+	}
+
+	switch rule.Channel {
+	case notify.ChannelEmail:
+		user, err := app.Db.GetUser(ctx, delivery.UserID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %w", err)
+		}
+		mail := app.SgMail.NewMail(app.Config.GreedEmail, user.Email, delivery.Subject, delivery.Message, &sgrid.MailData{Username: user.Name})
+		return app.SgMail.SendMail(mail)
+
+	case notify.ChannelWebhook:
+		secret, err := app.Encryptor.DecryptAccessToken(rule.WebhookSecret.String, app.Config.AESKey)
+		if err != nil {
+			return fmt.Errorf("error decrypting webhook secret: %w", err)
+		}
+		return app.Webhooks.Send(ctx, rule.WebhookUrl.String, secret, notify.Payload{
+			ID:        delivery.ID.String(),
+			RuleID:    rule.ID.String(),
+			Kind:      rule.Kind,
+			Subject:   delivery.Subject,
+			Message:   delivery.Message,
+			CreatedAt: delivery.CreatedAt,
+		})
+	}
+
+	return fmt.Errorf("unknown notification channel: %s", rule.Channel)
+}
+
+// Parses a rule's threshold amount
+func ruleThreshold(rule database.NotificationRule) (float64, bool) {
+	if !rule.Threshold.Valid {
+		return 0, false
+	}
+	threshold, err := strconv.ParseFloat(rule.Threshold.String, 64)
+	return threshold, err == nil
+}
+
+// Rules without an account cover all of the user's accounts
+func ruleCoversAccount(rule database.NotificationRule, accountID string) bool {
+	return !rule.AccountID.Valid || rule.AccountID.String == accountID
+}
+
+func (app *AppServer) logNotificationError(kind string, err error) {
+	_ = app.Logger.Log(
+		"level", "error",
+		"msg", "notification processing failed",
+		"kind", kind,
+		"err", err,
+	)
+}
@@ -0,0 +1,91 @@
+package handlers_test
+
+import (
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	kitlog "github.com/go-kit/log"
+	"github.com/jms-guy/greed/backend/internal/config"
+	"github.com/jms-guy/greed/backend/server/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const openAPISpecPath = "../../../docs/openapi.yaml"
+
+// Subset of an OpenAPI document needed to compare its operations against the router
+type openAPISpec struct {
+	OpenAPI string                          `yaml:"openapi"`
+	Paths   map[string]map[string]yaml.Node `yaml:"paths"`
+}
+
+func TestOpenAPISpecMatchesRouter(t *testing.T) {
+	data, err := os.ReadFile(openAPISpecPath)
+	require.NoError(t, err)
+
+	var spec openAPISpec
+	require.NoError(t, yaml.Unmarshal(data, &spec))
+	require.True(t, strings.HasPrefix(spec.OpenAPI, "3."), "spec must be an OpenAPI 3 document")
+
+	specOps := map[string]bool{}
+	for path, item := range spec.Paths {
+		for key := range item {
+			method := strings.ToUpper(key)
+			if !isHTTPMethod(method) {
+				continue // Path-level fields such as "parameters"
+			}
+			specOps[method+" "+normalizeRoute(path)] = true
+		}
+	}
+
+	app := &handlers.AppServer{
+		Config: &config.Config{Environment: "dev"},
+		Logger: kitlog.NewNopLogger(),
+	}
+	router, ok := app.Router().(chi.Routes)
+	require.True(t, ok, "router does not implement chi.Routes")
+
+	routerOps := map[string]bool{}
+	err = chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		routerOps[method+" "+normalizeRoute(route)] = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, missingOps(routerOps, specOps), "routes registered in the router but missing from docs/openapi.yaml")
+	assert.Empty(t, missingOps(specOps, routerOps), "operations in docs/openapi.yaml with no matching route")
+}
+
+// Trims the trailing slash chi adds to "/" routes mounted under a sub-router
+func normalizeRoute(route string) string {
+	if len(route) > 1 {
+		return strings.TrimSuffix(route, "/")
+	}
+	return route
+}
+
+// Reports whether a path item key is an operation
+func isHTTPMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// Returns the operations found in want, but not in have
+func missingOps(want, have map[string]bool) []string {
+	var missing []string
+	for op := range want {
+		if !have[op] {
+			missing = append(missing, op)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -1,23 +1,33 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/jms-guy/greed/backend/internal/events"
 )
 
+// Error reported by a Plaid webhook
+type webhookError struct {
+	ErrorCode string `json:"error_code"`
+}
+
 // Handler accepts and verifies webhooks from Plaid. Creates database records on what and who the webhook is for.
 func (app *AppServer) HandlerPlaidWebhook(w http.ResponseWriter, r *http.Request) {
 	type Webhook struct {
-		WebhookType              string `json:"webhook_type"`
-		WebhookCode              string `json:"webhook_code"`
-		ItemID                   string `json:"item_id"`
-		InitialUpdateComplete    bool   `json:"initial_update_complete"`
-		HistoricalUpdateComplete bool   `json:"historical_update_complete"`
+		WebhookType              string        `json:"webhook_type"`
+		WebhookCode              string        `json:"webhook_code"`
+		ItemID                   string        `json:"item_id"`
+		InitialUpdateComplete    bool          `json:"initial_update_complete"`
+		HistoricalUpdateComplete bool          `json:"historical_update_complete"`
+		Error                    *webhookError `json:"error"`
+		ConsentExpirationTime    *time.Time    `json:"consent_expiration_time"`
 	}
 
 	ctx := r.Context()
@@ -61,5 +71,54 @@ func (app *AppServer) HandlerPlaidWebhook(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if request.WebhookType == "ITEM" && itemNeedsReauth(request.WebhookCode) {
+		data := events.ItemErrorData{
+			ItemID:      item.ID,
+			WebhookCode: request.WebhookCode,
+		}
+		if request.Error != nil {
+			data.ErrorCode = request.Error.ErrorCode
+		}
+		app.recordEvent(ctx, item.UserID, events.ItemError, data)
+		app.notifyItemReauth(ctx, item, request.WebhookCode)
+	}
+
+	if request.WebhookType == "ITEM" {
+		app.recordItemWebhook(ctx, item, request.WebhookCode, request.Error, request.ConsentExpirationTime)
+	}
+
 	app.respondWithJSON(w, 200, "")
 }
+
+// Updates an item's status from an ITEM webhook. Errors are recorded by their Plaid error code, falling
+// back to the webhook code, and a repaired login clears the item's error
+func (app *AppServer) recordItemWebhook(ctx context.Context, item database.PlaidItem, code string, plaidErr *webhookError, consentExpiration *time.Time) {
+	switch code {
+	case "LOGIN_REPAIRED":
+		if err := app.Db.ClearItemError(ctx, item.ID); err != nil {
+			app.logItemStatusError(fmt.Errorf("error clearing item error: %w", err))
+		}
+	case "ERROR":
+		errCode := code
+		if plaidErr != nil && plaidErr.ErrorCode != "" {
+			errCode = plaidErr.ErrorCode
+		}
+		app.recordItemError(ctx, item.UserID, item.ID, errCode)
+	case "PENDING_EXPIRATION":
+		if consentExpiration != nil {
+			app.recordConsentExpiration(ctx, item.UserID, item.ID, consentExpiration)
+		}
+		app.recordItemError(ctx, item.UserID, item.ID, code)
+	case "PENDING_DISCONNECT", "USER_PERMISSION_REVOKED", "USER_ACCOUNT_REVOKED":
+		app.recordItemError(ctx, item.UserID, item.ID, code)
+	}
+}
+
+// Item webhook codes after which the user has to log in to their institution again
+func itemNeedsReauth(code string) bool {
+	switch code {
+	case "ERROR", "ITEM_LOGIN_REQUIRED", "PENDING_EXPIRATION", "PENDING_DISCONNECT":
+		return true
+	}
+	return false
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	kitlog "github.com/go-kit/log"
 	"github.com/jms-guy/greed/backend/internal/auth"
@@ -198,3 +199,88 @@ func TestHandlerPlaidWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerPlaidWebhookItemStatus(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestBody       string
+		expectedError     string
+		expectedCleared   bool
+		expectedExpiresAt string
+	}{
+		{
+			name:          "should record plaid error code from error webhook",
+			requestBody:   `{"webhook_type":"ITEM", "webhook_code":"ERROR", "item_id":"12345", "error":{"error_code":"ITEM_LOGIN_REQUIRED"}}`,
+			expectedError: "ITEM_LOGIN_REQUIRED",
+		},
+		{
+			name:              "should record consent expiration",
+			requestBody:       `{"webhook_type":"ITEM", "webhook_code":"PENDING_EXPIRATION", "item_id":"12345", "consent_expiration_time":"2026-11-01T00:00:00Z"}`,
+			expectedError:     "PENDING_EXPIRATION",
+			expectedExpiresAt: "2026-11-01T00:00:00Z",
+		},
+		{
+			name:            "should clear error when login is repaired",
+			requestBody:     `{"webhook_type":"ITEM", "webhook_code":"LOGIN_REPAIRED", "item_id":"12345"}`,
+			expectedCleared: true,
+		},
+		{
+			name:        "should leave status alone for transaction webhooks",
+			requestBody: `{"webhook_type":"TRANSACTIONS", "webhook_code":"SYNC_UPDATES_AVAILABLE", "item_id":"12345"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recordedError := ""
+			cleared := false
+			expiresAt := ""
+
+			mockDb := &mockDatabaseService{
+				GetItemByIDFunc: func(ctx context.Context, id string) (database.PlaidItem, error) {
+					return database.PlaidItem{ID: "12345", UserID: testUserID}, nil
+				},
+				RecordItemErrorFunc: func(ctx context.Context, arg database.RecordItemErrorParams) error {
+					recordedError = arg.LastErrorCode.String
+					return nil
+				},
+				ClearItemErrorFunc: func(ctx context.Context, itemID string) error {
+					cleared = true
+					return nil
+				},
+				SetItemConsentExpirationFunc: func(ctx context.Context, arg database.SetItemConsentExpirationParams) error {
+					expiresAt = arg.ConsentExpiresAt.Time.Format(time.RFC3339)
+					return nil
+				},
+			}
+
+			req := httptest.NewRequest("POST", "/api/plaid-webhook", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("plaid-verification", "testToken")
+
+			rr := httptest.NewRecorder()
+
+			mockApp := &handlers.AppServer{
+				Db:       mockDb,
+				Auth:     &mockAuthService{},
+				PService: &mockPlaidService{},
+				Logger:   kitlog.NewNopLogger(),
+			}
+
+			mockApp.HandlerPlaidWebhook(rr, req)
+
+			// --- Assertions ---
+			if rr.Code != http.StatusOK {
+				t.Fatalf("handler returned wrong status code: got %v. Body: %s", rr.Code, rr.Body.String())
+			}
+			if recordedError != tt.expectedError {
+				t.Errorf("recorded error code: got %q want %q", recordedError, tt.expectedError)
+			}
+			if cleared != tt.expectedCleared {
+				t.Errorf("error cleared: got %v want %v", cleared, tt.expectedCleared)
+			}
+			if expiresAt != tt.expectedExpiresAt {
+				t.Errorf("consent expiration: got %q want %q", expiresAt, tt.expectedExpiresAt)
+			}
+		})
+	}
+}
@@ -83,13 +83,23 @@ func (app *AppServer) Router() http.Handler {
 		r.Use(app.AuthMiddleware)
 
 		r.Route("/api/users", func(r chi.Router) {
-			r.Get("/me", app.HandlerGetCurrentUser) // Return a single user record
-			r.Delete("/me", app.HandlerDeleteUser)  // Delete an entire user
+			r.Get("/me", app.HandlerGetCurrentUser)                                // Return a single user record
+			r.With(app.SessionOnlyMiddleware).Delete("/me", app.HandlerDeleteUser) // Delete an entire user
 
-			r.Put("/update-password", app.HandlerUpdatePassword) // Updates a user's password - requires an email code
+			r.With(app.SessionOnlyMiddleware).Put("/update-password", app.HandlerUpdatePassword) // Updates a user's password - requires an email code
 		})
 	})
 
+	// API key operations
+	r.Group(func(r chi.Router) {
+		r.Use(app.AuthMiddleware)
+		r.Use(app.SessionOnlyMiddleware)
+
+		r.Get("/api/keys", app.HandlerGetAPIKeys)               // Get user's API keys
+		r.Post("/api/keys", app.HandlerCreateAPIKey)            // Create an API key
+		r.Delete("/api/keys/{key-id}", app.HandlerRevokeAPIKey) // Revoke an API key
+	})
+
 	// Plaid operations
 	r.Group(func(r chi.Router) {
 		r.Use(app.AuthMiddleware)
@@ -118,33 +128,124 @@ func (app *AppServer) Router() http.Handler {
 				r.With(app.MemberMiddleware).Post("/accounts", app.HandlerCreateAccounts)       // Creates account records for Plaid item
 				r.With(app.MemberMiddleware).Put("/balances", app.HandlerUpdateBalances)        // Update accounts database records with real-time balances
 				r.With(app.MemberMiddleware).Post("/transactions", app.HandlerSyncTransactions) // Sync database transaction records for item with Plaid
+				r.With(app.MemberMiddleware).Post("/investments", app.HandlerSyncInvestments)   // Sync database investment records for item with Plaid
+				r.With(app.MemberMiddleware).Post("/liabilities", app.HandlerSyncLiabilities)   // Sync database liability records for item with Plaid
 			})
 		})
 	})
 
+	// Anomaly operations
+	r.Group(func(r chi.Router) {
+		r.Use(app.AuthMiddleware)
+
+		r.Get("/api/anomalies", app.HandlerGetAnomalies)                                // Get unusual transactions flagged for user
+		r.Put("/api/anomalies/acknowledge", app.HandlerAcknowledgeAllAnomalies)         // Acknowledge all of user's anomalies
+		r.Put("/api/anomalies/{anomaly-id}/acknowledge", app.HandlerAcknowledgeAnomaly) // Acknowledge a single anomaly
+	})
+
+	// Merchant operations
+	r.Group(func(r chi.Router) {
+		r.Use(app.AuthMiddleware)
+
+		r.Route("/api/merchants", func(r chi.Router) {
+			r.Get("/", app.HandlerGetMerchants)                             // Get canonical merchants of user's transactions
+			r.Get("/aliases", app.HandlerGetMerchantAliases)                // Get user's merchant aliases
+			r.Post("/aliases", app.HandlerCreateMerchantAlias)              // Count a raw merchant name under another merchant
+			r.Delete("/aliases/{alias-id}", app.HandlerDeleteMerchantAlias) // Delete a merchant alias
+		})
+	})
+
+	// Notification operations
+	r.Group(func(r chi.Router) {
+		r.Use(app.AuthMiddleware)
+
+		r.Route("/api/notifications", func(r chi.Router) {
+			r.Get("/rules", app.HandlerGetNotificationRules)                                // Get user's notification rules
+			r.Post("/rules", app.HandlerCreateNotificationRule)                             // Create a notification rule
+			r.Delete("/rules/{rule-id}", app.HandlerDeleteNotificationRule)                 // Delete a notification rule
+			r.Get("/deliveries", app.HandlerGetNotificationDeliveries)                      // Get log of user's notification deliveries
+			r.Post("/deliveries/{delivery-id}/retry", app.HandlerRetryNotificationDelivery) // Queue a failed delivery to be sent again
+		})
+	})
+
+	// Event operations
+	r.Group(func(r chi.Router) {
+		r.Use(app.AuthMiddleware)
+
+		r.Get("/api/events", app.HandlerGetEvents) // Get user's events in the order they were recorded
+
+		r.Route("/api/events/endpoints", func(r chi.Router) {
+			r.Get("/", app.HandlerGetEventEndpoints)                          // Get user's event endpoints
+			r.Post("/", app.HandlerCreateEventEndpoint)                       // Register an endpoint to receive events
+			r.Delete("/{endpoint-id}", app.HandlerDeleteEventEndpoint)        // Delete an event endpoint
+			r.Get("/{endpoint-id}/deliveries", app.HandlerGetEventDeliveries) // Get log of deliveries to an endpoint
+			r.Post("/{endpoint-id}/replay", app.HandlerReplayEvents)          // Queue past events to be sent to an endpoint again
+		})
+	})
+
+	// Transaction change feed operations
+	r.Group(func(r chi.Router) {
+		r.Use(app.AuthMiddleware)
+
+		r.Get("/api/transactions/changes", app.HandlerGetTransactionChanges) // Get changes to user's transactions since a cursor
+	})
+
 	// Account operations
 	r.Group(func(r chi.Router) {
 		r.Use(app.AuthMiddleware)
 
 		// Retrieving accounts
-		r.Get("/api/accounts", app.HandlerGetAccountsForUser) // Get list of all accounts for user
+		r.Get("/api/accounts", app.HandlerGetAccountsForUser)             // Get list of all accounts for user
+		r.Get("/api/accounts/compare", app.HandlerCompareAccountsForUser) // Compare spending across all accounts between two periods
+		r.Post("/api/accounts", app.HandlerCreateManualAccount)           // Create a manual account, with no Plaid item
 
 		// Account-specific routes that need AccountMiddleware
 		r.Route("/api/accounts/{accountid}", func(r chi.Router) {
 			r.Use(app.AccountMiddleware)
 
-			r.Get("/data", app.HandlerGetAccountData) // Return a single account record for user
-			r.Delete("/", app.HandlerDeleteAccount)   // Delete account
+			r.Get("/data", app.HandlerGetAccountData)              // Return a single account record for user
+			r.Get("/compare", app.HandlerCompareAccount)           // Compare account spending between two periods
+			r.Delete("/", app.HandlerDeleteAccount)                // Delete account
+			r.Put("/overrides", app.HandlerUpdateAccountOverrides) // Rename, hide, archive or reorder account
+
+			// Investment routes - for brokerage/retirement type accounts
+			r.Get("/holdings", app.HandlerGetHoldings)                               // Get account holdings with cost basis and unrealized gain
+			r.Get("/investments/transactions", app.HandlerGetInvestmentTransactions) // Get account investment transactions
+
+			// Liability routes - for credit card and loan type accounts
+			r.Get("/liabilities", app.HandlerGetLiability) // Get account APRs, payment due dates and loan terms
 
 			// Transaction routes as a sub-resource of accounts
 			r.Route("/transactions", func(r chi.Router) {
 				r.Get("/", app.HandlerGetTransactionsForAccount)       // Get transaction records for account
 				r.Delete("/", app.HandlerDeleteTransactionsForAccount) // Delete all transactions for account
+				r.Post("/", app.HandlerCreateManualTransaction)        // Add a transaction to a manual account
 
 				// Monetary reporting - for credit/debit type accounts
 				r.Get("/monetary", app.HandlerGetMonetaryData)                        // Get monetary data for history of account
 				r.Get("/monetary/{year}-{month}", app.HandlerGetMonetaryDataForMonth) // Get monetary data for given month
 				r.Get("/recurring", app.HandlerGetRecurringData)                      // Gets relevant data for an account's recurring transaction streams
+
+				// Transaction-specific routes that need TransactionMiddleware
+				r.Route("/{transaction-id}", func(r chi.Router) {
+					r.Use(app.TransactionMiddleware)
+
+					r.Put("/", app.HandlerUpdateManualTransaction)           // Change a manual account's transaction
+					r.Delete("/", app.HandlerDeleteManualTransaction)        // Delete a manual account's transaction
+					r.Put("/category", app.HandlerUpdateTransactionCategory) // Re-categorize a transaction
+					r.Get("/tags", app.HandlerGetTransactionTags)            // Get tags attached to a transaction
+					r.Post("/tags", app.HandlerAddTransactionTag)            // Attach a tag to a transaction
+					r.Get("/note", app.HandlerGetTransactionNote)            // Get a transaction's note
+					r.Put("/note", app.HandlerUpdateTransactionNote)         // Set or remove a transaction's note
+
+					// Files attached to a transaction, such as receipts
+					r.Route("/attachments", func(r chi.Router) {
+						r.Get("/", app.HandlerGetTransactionAttachments)                     // List a transaction's attachments
+						r.Post("/", app.HandlerAddTransactionAttachment)                     // Attach a file to a transaction
+						r.Get("/{attachment-id}", app.HandlerGetTransactionAttachment)       // Download an attachment's contents
+						r.Delete("/{attachment-id}", app.HandlerDeleteTransactionAttachment) // Delete an attachment
+					})
+				})
 			})
 		})
 	})
@@ -4,16 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"os"
+	"time"
 
 	kitlog "github.com/go-kit/log"
 	"github.com/google/uuid"
 	"github.com/jms-guy/greed/backend/api/plaidservice"
 	"github.com/jms-guy/greed/backend/api/sgrid"
 	auth_pkg "github.com/jms-guy/greed/backend/internal/auth"
+	"github.com/jms-guy/greed/backend/internal/blob"
 	"github.com/jms-guy/greed/backend/internal/config"
 	"github.com/jms-guy/greed/backend/internal/database"
 	"github.com/jms-guy/greed/backend/internal/encrypt"
+	"github.com/jms-guy/greed/backend/internal/lifecycle"
 	"github.com/jms-guy/greed/backend/internal/limiter"
+	"github.com/jms-guy/greed/backend/internal/notify"
 	"github.com/jms-guy/greed/backend/internal/utils"
 	"github.com/joho/godotenv"
 
@@ -30,9 +34,13 @@ type AppServer struct {
 	SgMail     sgrid.MailService         // SendGrid mail service
 	Limiter    *limiter.IPRateLimiter    // Rate limiter
 	PService   plaidservice.PlaidService // Client for Plaid integration
+	Link       plaidservice.LinkOptions  // Countries, products and account filters used for Plaid Link
 	TxnUpdater TxnUpdater                // Used for Db transactions
 	Encryptor  encrypt.EncryptorService  // Used for encryption and decryption methods
 	Querier    utils.QueryService        // Used for parsing URL queries
+	Lifecycle  *lifecycle.Manager        // Background workers started and drained alongside the server
+	Webhooks   notify.Sender             // Sends signed notification webhooks to user endpoints
+	Blobs      blob.Store                // Stores the contents of transaction attachments
 }
 
 // Creates a new AppServer struct with all necessary fields
@@ -95,10 +103,37 @@ func NewAppServer() (*AppServer, error) {
 
 	// Create Plaid client
 	plaidServiceStruct := plaidservice.NewPlaidProductionService(config.PlaidClientID, config.PlaidSecret)
+	if config.PlaidBaseURL != "" {
+		plaidServiceStruct = plaidservice.NewPlaidServiceWithURL(config.PlaidClientID, config.PlaidSecret, config.PlaidBaseURL)
+	}
+
+	linkOptions, err := plaidservice.NewLinkOptions(config.PlaidCountries, config.PlaidProducts, config.PlaidOptional, config.PlaidFilters, config.PlaidDays)
+	if err != nil {
+		_ = kitLogger.Log(
+			"level", "error",
+			"msg", "invalid Plaid Link configuration",
+			"err", err,
+		)
+		return app, err
+	}
 
 	// Create rate limiter
 	limiter := limiter.NewIPRateLimiter()
 
+	// Background worker manager
+	lifecycleManager := lifecycle.NewManager(kitLogger)
+
+	// Local filesystem store for transaction attachments
+	blobStore, err := blob.NewLocalStore(config.AttachmentsPath)
+	if err != nil {
+		_ = kitLogger.Log(
+			"level", "error",
+			"msg", "failed to create attachment store",
+			"err", err,
+		)
+		return app, err
+	}
+
 	// Initialize the server struct
 	app = &AppServer{
 		Db:         dbQueries,
@@ -109,9 +144,21 @@ func NewAppServer() (*AppServer, error) {
 		SgMail:     mailService,
 		Limiter:    limiter,
 		PService:   plaidServiceStruct,
+		Link:       linkOptions,
 		TxnUpdater: updater,
 		Encryptor:  encryptor,
 		Querier:    querier,
+		Lifecycle:  lifecycleManager,
+		Webhooks:   notify.NewWebhookSender(config.Environment == "dev"),
+		Blobs:      blobStore,
+	}
+
+	// Background workers take their work from the database, so there is nothing for them to do without one
+	if dbQueries != nil {
+		lifecycleManager.Register("notification-dispatcher", lifecycle.WorkerFunc(app.RunNotificationDispatcher))
+		lifecycleManager.Register("event-dispatcher", lifecycle.WorkerFunc(app.RunEventDispatcher))
+		lifecycleManager.Register("merchant-normalizer", lifecycle.WorkerFunc(app.RunMerchantNormalizer))
+		lifecycleManager.Register("attachment-sweeper", lifecycle.WorkerFunc(app.RunAttachmentSweeper))
 	}
 
 	return app, nil
@@ -133,7 +180,26 @@ type GreedDatabase interface {
 	UpdatePassword(ctx context.Context, arg database.UpdatePasswordParams) error
 	VerifyUser(ctx context.Context, id uuid.UUID) error
 	GetAllAccountsForUser(ctx context.Context, userID uuid.UUID) ([]database.Account, error)
+	GetAccountOverride(ctx context.Context, accountID string) (database.AccountOverride, error)
+	GetAccountOverridesForUser(ctx context.Context, userID uuid.UUID) ([]database.AccountOverride, error)
+	UpsertAccountOverride(ctx context.Context, arg database.UpsertAccountOverrideParams) (database.AccountOverride, error)
+	GetTransactionNote(ctx context.Context, transactionID string) (database.TransactionNote, error)
+	UpsertTransactionNote(ctx context.Context, arg database.UpsertTransactionNoteParams) (database.TransactionNote, error)
+	DeleteTransactionNote(ctx context.Context, transactionID string) error
+	CreateTransactionAttachment(ctx context.Context, arg database.CreateTransactionAttachmentParams) (database.TransactionAttachment, error)
+	GetTransactionAttachments(ctx context.Context, transactionID string) ([]database.TransactionAttachment, error)
+	GetTransactionAttachment(ctx context.Context, arg database.GetTransactionAttachmentParams) (database.TransactionAttachment, error)
+	DeleteTransactionAttachment(ctx context.Context, id uuid.UUID) error
+	GetOrphanedAttachmentBlobs(ctx context.Context, limit int32) ([]string, error)
+	DeleteOrphanedAttachmentBlob(ctx context.Context, storageKey string) error
+	GetMerchantAliases(ctx context.Context, userID uuid.UUID) ([]database.MerchantAlias, error)
+	UpsertMerchantAlias(ctx context.Context, arg database.UpsertMerchantAliasParams) (database.MerchantAlias, error)
+	DeleteMerchantAlias(ctx context.Context, arg database.DeleteMerchantAliasParams) (int64, error)
+	GetUserMerchantNames(ctx context.Context, userID uuid.UUID) ([]database.GetUserMerchantNamesRow, error)
+	SetCanonicalMerchant(ctx context.Context, arg database.SetCanonicalMerchantParams) (int64, error)
+	GetUsersWithUnnormalizedMerchants(ctx context.Context) ([]uuid.UUID, error)
 	CreateAccount(ctx context.Context, arg database.CreateAccountParams) (database.Account, error)
+	CreateManualAccount(ctx context.Context, arg database.CreateManualAccountParams) (database.Account, error)
 	DeleteAccount(ctx context.Context, arg database.DeleteAccountParams) error
 	GetAccount(ctx context.Context, name string) (database.Account, error)
 	GetAccountById(ctx context.Context, arg database.GetAccountByIdParams) (database.Account, error)
@@ -144,6 +210,8 @@ type GreedDatabase interface {
 	GetMerchantSummaryByMonth(ctx context.Context, arg database.GetMerchantSummaryByMonthParams) ([]database.GetMerchantSummaryByMonthRow, error)
 	GetMonetaryDataForAllMonths(ctx context.Context, accountID string) ([]database.GetMonetaryDataForAllMonthsRow, error)
 	GetMonetaryDataForMonth(ctx context.Context, arg database.GetMonetaryDataForMonthParams) (database.GetMonetaryDataForMonthRow, error)
+	GetSpendingBreakdown(ctx context.Context, arg database.GetSpendingBreakdownParams) ([]database.GetSpendingBreakdownRow, error)
+	GetUserSpendingBreakdown(ctx context.Context, arg database.GetUserSpendingBreakdownParams) ([]database.GetUserSpendingBreakdownRow, error)
 	ValidateCurrency(ctx context.Context, code string) (bool, error)
 	CreateDelegation(ctx context.Context, arg database.CreateDelegationParams) (database.Delegation, error)
 	GetDelegation(ctx context.Context, id uuid.UUID) (database.Delegation, error)
@@ -161,6 +229,11 @@ type GreedDatabase interface {
 	ResetItems(ctx context.Context) error
 	UpdateCursor(ctx context.Context, arg database.UpdateCursorParams) error
 	UpdateNickname(ctx context.Context, arg database.UpdateNicknameParams) error
+	ClearItemError(ctx context.Context, itemID string) error
+	GetItemStatusesForUser(ctx context.Context, userID uuid.UUID) ([]database.ItemStatus, error)
+	RecordItemError(ctx context.Context, arg database.RecordItemErrorParams) error
+	RecordItemSynced(ctx context.Context, arg database.RecordItemSyncedParams) error
+	SetItemConsentExpiration(ctx context.Context, arg database.SetItemConsentExpirationParams) error
 	CreateToken(ctx context.Context, arg database.CreateTokenParams) (database.RefreshToken, error)
 	ExpireAllDelegationTokens(ctx context.Context, delegationID uuid.UUID) error
 	ExpireToken(ctx context.Context, hashedToken string) error
@@ -171,6 +244,47 @@ type GreedDatabase interface {
 	DeleteTransactionsForAccount(ctx context.Context, accountID string) error
 	GetTransactions(ctx context.Context, accountID string) ([]database.Transaction, error)
 	GetTransactionsForUser(ctx context.Context, userID uuid.UUID) ([]database.Transaction, error)
+	GetTransactionForAccount(ctx context.Context, arg database.GetTransactionForAccountParams) (database.Transaction, error)
+	UpdateTransactionCategory(ctx context.Context, arg database.UpdateTransactionCategoryParams) (database.Transaction, error)
+	GetTransactionsByIDs(ctx context.Context, arg database.GetTransactionsByIDsParams) ([]database.Transaction, error)
+	GetTransactionChanges(ctx context.Context, arg database.GetTransactionChangesParams) ([]database.TransactionChange, error)
+	RecordTransactionChanges(ctx context.Context, arg database.RecordTransactionChangesParams) error
+	UpsertTag(ctx context.Context, arg database.UpsertTagParams) (database.TransactionTag, error)
+	GetTagNamesForTransaction(ctx context.Context, transactionID string) ([]string, error)
+	CreateAnomaly(ctx context.Context, arg database.CreateAnomalyParams) error
+	GetAnomaliesForUser(ctx context.Context, arg database.GetAnomaliesForUserParams) ([]database.GetAnomaliesForUserRow, error)
+	AcknowledgeAnomaly(ctx context.Context, arg database.AcknowledgeAnomalyParams) (database.Anomaly, error)
+	AcknowledgeAllAnomalies(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateNotificationRule(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error)
+	GetNotificationRulesForUser(ctx context.Context, userID uuid.UUID) ([]database.NotificationRule, error)
+	GetEnabledNotificationRules(ctx context.Context, arg database.GetEnabledNotificationRulesParams) ([]database.NotificationRule, error)
+	GetNotificationRule(ctx context.Context, id uuid.UUID) (database.NotificationRule, error)
+	DeleteNotificationRule(ctx context.Context, arg database.DeleteNotificationRuleParams) (int64, error)
+	CreateNotificationDelivery(ctx context.Context, arg database.CreateNotificationDeliveryParams) error
+	ClaimDueNotificationDeliveries(ctx context.Context, arg database.ClaimDueNotificationDeliveriesParams) ([]database.NotificationDelivery, error)
+	RecordNotificationAttempt(ctx context.Context, arg database.RecordNotificationAttemptParams) error
+	GetNotificationDeliveriesForUser(ctx context.Context, arg database.GetNotificationDeliveriesForUserParams) ([]database.GetNotificationDeliveriesForUserRow, error)
+	RetryNotificationDelivery(ctx context.Context, arg database.RetryNotificationDeliveryParams) (database.NotificationDelivery, error)
+	CreateEvent(ctx context.Context, arg database.CreateEventParams) error
+	CreateEventEndpoint(ctx context.Context, arg database.CreateEventEndpointParams) (database.EventEndpoint, error)
+	GetEventEndpointsForUser(ctx context.Context, userID uuid.UUID) ([]database.EventEndpoint, error)
+	DeleteEventEndpoint(ctx context.Context, arg database.DeleteEventEndpointParams) (int64, error)
+	GetEventsForUser(ctx context.Context, arg database.GetEventsForUserParams) ([]database.GetEventsForUserRow, error)
+	FanOutEvents(ctx context.Context, batchSize int32) (int64, error)
+	ClaimDueEventDeliveries(ctx context.Context, arg database.ClaimDueEventDeliveriesParams) ([]database.ClaimDueEventDeliveriesRow, error)
+	RecordEventDeliveryAttempt(ctx context.Context, arg database.RecordEventDeliveryAttemptParams) error
+	GetEventDeliveriesForEndpoint(ctx context.Context, arg database.GetEventDeliveriesForEndpointParams) ([]database.GetEventDeliveriesForEndpointRow, error)
+	ReplayEvents(ctx context.Context, arg database.ReplayEventsParams) (int64, error)
+	DeleteEventsBefore(ctx context.Context, createdAt time.Time) (int64, error)
+	CreateAPIKey(ctx context.Context, arg database.CreateAPIKeyParams) (database.ApiKey, error)
+	GetAPIKeysForUser(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (database.ApiKey, error)
+	TouchAPIKey(ctx context.Context, id uuid.UUID) error
+	DeleteAPIKey(ctx context.Context, arg database.DeleteAPIKeyParams) (int64, error)
+	GetHoldingsForAccount(ctx context.Context, accountID string) ([]database.GetHoldingsForAccountRow, error)
+	GetInvestmentTransactionsForAccount(ctx context.Context, accountID string) ([]database.GetInvestmentTransactionsForAccountRow, error)
+	GetLiabilityForAccount(ctx context.Context, accountID string) (database.Liability, error)
+	GetLiabilityAPRsForAccount(ctx context.Context, accountID string) ([]database.LiabilityApr, error)
 	CreateVerificationRecord(ctx context.Context, arg database.CreateVerificationRecordParams) (database.VerificationRecord, error)
 	DeleteVerificationRecord(ctx context.Context, verificationCode string) error
 	DeleteVerificationRecordByUser(ctx context.Context, userID uuid.UUID) error
@@ -199,4 +313,16 @@ type TxnUpdater interface {
 		nextCursor string,
 		itemID string,
 	) error
+	ApplyInvestmentUpdates(
+		ctx context.Context,
+		itemID string,
+		securities []plaid.Security,
+		holdings []plaid.Holding,
+		txns []plaid.InvestmentTransaction,
+	) error
+	ApplyLiabilityUpdates(ctx context.Context, itemID string, liabilities plaid.LiabilitiesObject) error
+	CreateManualTransaction(ctx context.Context, userID uuid.UUID, params database.CreateTransactionParams) (database.Transaction, error)
+	UpdateManualTransaction(ctx context.Context, userID uuid.UUID, params database.UpdateManualTransactionParams) (database.Transaction, error)
+	DeleteManualTransaction(ctx context.Context, userID uuid.UUID, txn database.Transaction) error
+	DeleteTransactionsForAccount(ctx context.Context, acc database.Account) error
 }
@@ -0,0 +1,47 @@
+package handlers_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// Opens the migrated test database named by GREED_TEST_DATABASE_URL, skipping the test when it isn't set
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("GREED_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("GREED_TEST_DATABASE_URL not set")
+	}
+	db, err := sql.Open("postgres", url)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// Creates a user in the test database, deleted along with everything they own when the test ends
+func createTestUser(t *testing.T, db *sql.DB) uuid.UUID {
+	t.Helper()
+
+	userID := uuid.New()
+	_, err := database.New(db).CreateUser(context.Background(), database.CreateUserParams{
+		ID:             userID,
+		Name:           "test-" + userID.String()[:8],
+		HashedPassword: "x",
+		Email:          userID.String() + "@example.com",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM users WHERE id = $1", userID)
+	})
+
+	return userID
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/backend/internal/database"
+	"github.com/plaid/plaid-go/v36/plaid"
+)
+
+// Kinds of change recorded in a user's transaction change feed, matching Plaid's sync model
+const (
+	changeAdded    = "added"
+	changeModified = "modified"
+	changeRemoved  = "removed"
+)
+
+// Builds the change feed records of a transaction sync, one batch per kind of change
+func transactionChangeRecords(userID uuid.UUID, added, modified []plaid.Transaction, removed []plaid.RemovedTransaction) []database.RecordTransactionChangesParams {
+	var records []database.RecordTransactionChangesParams
+
+	for _, group := range []struct {
+		changeType string
+		txns       []plaid.Transaction
+	}{
+		{changeAdded, added},
+		{changeModified, modified},
+	} {
+		if len(group.txns) == 0 {
+			continue
+		}
+		params := database.RecordTransactionChangesParams{UserID: userID, ChangeType: group.changeType}
+		for _, txn := range group.txns {
+			params.TransactionIds = append(params.TransactionIds, txn.TransactionId)
+			params.AccountIds = append(params.AccountIds, txn.AccountId)
+		}
+		records = append(records, params)
+	}
+
+	if len(removed) > 0 {
+		params := database.RecordTransactionChangesParams{UserID: userID, ChangeType: changeRemoved}
+		for _, txn := range removed {
+			params.TransactionIds = append(params.TransactionIds, txn.TransactionId)
+			params.AccountIds = append(params.AccountIds, txn.AccountId)
+		}
+		records = append(records, params)
+	}
+
+	return records
+}
+
+// A transaction's net change over a page of the feed
+type foldedChange struct {
+	transactionID string
+	accountID     string
+	changeType    string
+}
+
+// Folds a page of change records into one change per transaction, in the order transactions first appear.
+// A transaction removed by its last change is removed, one first added within the page is added, and any
+// other is modified
+func foldTransactionChanges(changes []database.TransactionChange) []foldedChange {
+	folded := []foldedChange{}
+	index := map[string]int{}
+
+	for _, c := range changes {
+		i, seen := index[c.TransactionID]
+		if !seen {
+			index[c.TransactionID] = len(folded)
+			folded = append(folded, foldedChange{transactionID: c.TransactionID, accountID: c.AccountID, changeType: c.ChangeType})
+			continue
+		}
+
+		f := &folded[i]
+		f.accountID = c.AccountID
+		switch {
+		case c.ChangeType == changeRemoved:
+			f.changeType = changeRemoved
+		case f.changeType == changeRemoved:
+			// Removed then seen again, so the client may hold a stale copy
+			f.changeType = changeModified
+		}
+	}
+
+	return folded
+}
@@ -1,7 +1,13 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/jms-guy/greed/backend/server/handlers"
@@ -15,6 +21,15 @@ func Run() error {
 		return err
 	}
 
+	// Cancelled when the process receives SIGINT or SIGTERM (Cloud Run sends SIGTERM on redeploys)
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Parent context of every request and background worker. It is only cancelled once the drain timeout
+	// has been exceeded, so in-flight Plaid calls and database transactions are given the chance to finish
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
 	// Initialize server router
 	r := app.Router()
 
@@ -24,8 +39,13 @@ func Run() error {
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
 	}
 
+	app.Lifecycle.Start(baseCtx)
+
 	/////Start server/////
 	_ = app.Logger.Log(
 		"transport", "HTTP",
@@ -33,13 +53,68 @@ func Run() error {
 		"msg", "listening",
 	)
 
-	err = server.ListenAndServe()
-	if err != nil {
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_ = app.Logger.Log(
+				"level", "error",
+				"err", err)
+			_ = shutdown(app, server, cancelBase)
+			return err
+		}
+	case <-sigCtx.Done():
+		_ = app.Logger.Log(
+			"msg", "shutdown signal received, draining connections",
+			"timeout", app.Config.ShutdownTimeout,
+		)
+	}
+
+	return shutdown(app, server, cancelBase)
+}
+
+// Stops accepting new connections and waits for in-flight requests and background workers to finish.
+// If the drain timeout is exceeded, the base context is cancelled so that remaining Plaid calls are aborted
+// and open database transactions are rolled back, rather than being killed mid-write
+func shutdown(app *handlers.AppServer, server *http.Server, cancelBase context.CancelFunc) error {
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.ShutdownTimeout)
+	defer cancel()
+
+	var shutdownErr error
+
+	if err := server.Shutdown(ctx); err != nil {
 		_ = app.Logger.Log(
 			"level", "error",
-			"err", err)
-		return err
+			"msg", "server did not drain before timeout, cancelling in-flight requests",
+			"err", err,
+		)
+		cancelBase()
+		shutdownErr = server.Close()
+	}
+
+	cancelBase()
+	if err := app.Lifecycle.Shutdown(ctx); err != nil {
+		_ = app.Logger.Log(
+			"level", "error",
+			"msg", "background workers did not stop before timeout",
+			"err", err,
+		)
+		shutdownErr = errors.Join(shutdownErr, err)
 	}
 
-	return nil
+	if app.Database != nil {
+		if err := app.Database.Close(); err != nil {
+			shutdownErr = errors.Join(shutdownErr, err)
+		}
+	}
+
+	_ = app.Logger.Log(
+		"msg", "server stopped",
+	)
+
+	return shutdownErr
 }
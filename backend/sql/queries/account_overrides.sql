@@ -0,0 +1,19 @@
+-- name: GetAccountOverridesForUser :many
+SELECT * FROM account_overrides
+WHERE user_id = $1;
+
+-- name: GetAccountOverride :one
+SELECT * FROM account_overrides
+WHERE account_id = $1;
+
+-- name: UpsertAccountOverride :one
+INSERT INTO account_overrides (account_id, user_id, nickname, hidden, archived, include_in_net_worth, sort_order, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+ON CONFLICT (account_id) DO UPDATE
+SET nickname = EXCLUDED.nickname,
+    hidden = EXCLUDED.hidden,
+    archived = EXCLUDED.archived,
+    include_in_net_worth = EXCLUDED.include_in_net_worth,
+    sort_order = EXCLUDED.sort_order,
+    updated_at = NOW()
+RETURNING *;
@@ -52,14 +52,65 @@ WHERE user_id = $1;
 
 -- name: GetAccountsForItem :many
 SELECT * FROM accounts
-WHERE item_id = $1;
+WHERE item_id = sqlc.arg(item_id)::text;
 
 -- name: UpdateBalances :one
 UPDATE accounts
-SET available_balance = $1, current_balance = $2, updated_at = NOW()
-WHERE id = $3 AND item_id = $4
+SET available_balance = sqlc.arg(available_balance), current_balance = sqlc.arg(current_balance), updated_at = NOW()
+WHERE id = sqlc.arg(id) AND item_id = sqlc.arg(item_id)::text
 RETURNING *;
 
 -- name: DeleteAccount :exec
+WITH removed AS (
+    INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type, created_at)
+    SELECT a.user_id, t.id, t.account_id, 'removed', NOW()
+    FROM transactions AS t
+    INNER JOIN accounts AS a ON t.account_id = a.id
+    WHERE a.id = $1 AND a.user_id = $2
+)
 DELETE FROM accounts
-WHERE id = $1 AND user_id = $2;
\ No newline at end of file
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateManualAccount :one
+INSERT INTO accounts(
+    id,
+    created_at,
+    updated_at,
+    name,
+    type,
+    subtype,
+    iso_currency_code,
+    opening_balance,
+    current_balance,
+    available_balance,
+    is_manual,
+    user_id)
+VALUES (
+    sqlc.arg(id),
+    NOW(),
+    NOW(),
+    sqlc.arg(name),
+    sqlc.arg(type),
+    sqlc.arg(subtype),
+    sqlc.arg(iso_currency_code),
+    sqlc.arg(opening_balance),
+    sqlc.arg(opening_balance),
+    CASE WHEN sqlc.arg(type) IN ('credit', 'loan') THEN NULL ELSE sqlc.arg(opening_balance) END,
+    TRUE,
+    sqlc.arg(user_id)
+)
+RETURNING *;
+
+-- name: RecalculateManualBalance :one
+WITH total AS (
+    SELECT COALESCE(SUM(amount), 0) AS amount
+    FROM transactions
+    WHERE account_id = $1
+)
+UPDATE accounts
+SET current_balance = COALESCE(opening_balance, 0) + CASE WHEN type IN ('credit', 'loan') THEN total.amount ELSE -total.amount END,
+    available_balance = CASE WHEN type IN ('credit', 'loan') THEN NULL ELSE COALESCE(opening_balance, 0) - total.amount END,
+    updated_at = NOW()
+FROM total
+WHERE id = $1 AND is_manual
+RETURNING accounts.*;
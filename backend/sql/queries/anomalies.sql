@@ -0,0 +1,51 @@
+-- name: CreateAnomaly :exec
+INSERT INTO anomalies (
+    id,
+    transaction_id,
+    user_id,
+    kind,
+    reason,
+    score,
+    created_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    NOW()
+)
+ON CONFLICT (transaction_id, kind) DO NOTHING;
+
+-- name: GetAnomaliesForUser :many
+SELECT
+    an.id,
+    an.transaction_id,
+    t.account_id,
+    an.kind,
+    an.reason,
+    an.score,
+    t.amount,
+    t.iso_currency_code,
+    t.date,
+    t.merchant_name,
+    an.created_at,
+    an.acknowledged_at
+FROM anomalies AS an
+INNER JOIN transactions AS t ON an.transaction_id = t.id
+WHERE an.user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(include_acknowledged)::boolean OR an.acknowledged_at IS NULL)
+ORDER BY t.date DESC, an.created_at DESC;
+
+-- name: AcknowledgeAnomaly :one
+UPDATE anomalies
+SET acknowledged_at = COALESCE(acknowledged_at, NOW())
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: AcknowledgeAllAnomalies :execrows
+UPDATE anomalies
+SET acknowledged_at = NOW()
+WHERE user_id = $1 AND acknowledged_at IS NULL;
@@ -0,0 +1,41 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    id,
+    user_id,
+    name,
+    key_hash,
+    prefix,
+    scope,
+    expires_at,
+    created_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    NOW()
+)
+RETURNING *;
+
+-- name: GetAPIKeysForUser :many
+SELECT * FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: GetAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE key_hash = $1;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1
+AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: DeleteAPIKey :execrows
+DELETE FROM api_keys
+WHERE id = $1 AND user_id = $2;
@@ -22,7 +22,7 @@ ORDER BY year DESC, month DESC;
 
 -- name: GetMerchantSummary :many
 SELECT
-  merchant_name AS merchant,
+  COALESCE(canonical_merchant, NULLIF(merchant_name, ''), name, '')::text AS merchant,
   COUNT(*) AS txn_count,
   personal_finance_category AS category,
   SUM(amount)::float AS total_amount,
@@ -34,7 +34,7 @@ ORDER BY month DESC, txn_count DESC;
 
 -- name: GetMerchantSummaryByMonth :many
 SELECT
-  merchant_name AS merchant,
+  COALESCE(canonical_merchant, NULLIF(merchant_name, ''), name, '')::text AS merchant,
   COUNT(*) AS txn_count,
   personal_finance_category AS category,
   SUM(amount)::float AS total_amount,
@@ -44,4 +44,32 @@ WHERE account_id = $1
   AND date >= make_date($2, $3, 1)
   AND date < (make_date($2, $3, 1) + interval '1 month')
 GROUP BY merchant, category, month
-ORDER BY month DESC, txn_count DESC;
\ No newline at end of file
+ORDER BY month DESC, txn_count DESC;
+
+-- name: GetSpendingBreakdown :many
+SELECT
+  personal_finance_category AS category,
+  COALESCE(canonical_merchant, NULLIF(merchant_name, ''), name, '')::text AS merchant,
+  COUNT(*) AS txn_count,
+  SUM(amount)::float AS total_amount
+FROM transactions
+WHERE account_id = sqlc.arg(account_id)
+  AND date >= sqlc.arg(start_date)
+  AND date < sqlc.arg(end_date)
+GROUP BY category, merchant;
+
+-- name: GetUserSpendingBreakdown :many
+SELECT
+  t.personal_finance_category AS category,
+  COALESCE(t.canonical_merchant, NULLIF(t.merchant_name, ''), t.name, '')::text AS merchant,
+  COUNT(*) AS txn_count,
+  SUM(t.amount)::float AS total_amount
+FROM transactions t
+JOIN accounts a ON a.id = t.account_id
+LEFT JOIN account_overrides o ON o.account_id = a.id
+WHERE a.user_id = sqlc.arg(user_id)
+  AND (a.type IN ('depository', 'credit') OR a.is_manual)
+  AND t.date >= sqlc.arg(start_date)
+  AND t.date < sqlc.arg(end_date)
+  AND (sqlc.arg(include_hidden)::boolean OR NOT COALESCE(o.hidden OR o.archived, FALSE))
+GROUP BY category, merchant;
@@ -0,0 +1,159 @@
+-- name: CreateEvent :exec
+INSERT INTO events (
+    id,
+    user_id,
+    type,
+    payload,
+    created_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    NOW()
+);
+
+-- name: GetEventsForUser :many
+SELECT id, seq, type, payload, created_at FROM events
+WHERE user_id = sqlc.arg(user_id)
+  AND seq > sqlc.arg(after_seq)
+  AND (sqlc.arg(event_type)::text = '' OR type = sqlc.arg(event_type))
+ORDER BY seq
+LIMIT sqlc.arg(row_limit);
+
+-- name: DeleteEventsBefore :execrows
+DELETE FROM events
+WHERE created_at < $1;
+
+-- name: FanOutEvents :execrows
+WITH claimed AS (
+    UPDATE events
+    SET dispatched_at = NOW()
+    WHERE id IN (
+        SELECT e.id FROM events AS e
+        WHERE e.dispatched_at IS NULL
+        ORDER BY e.seq
+        LIMIT sqlc.arg(batch_size)
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, user_id, type
+)
+INSERT INTO event_deliveries (id, event_id, endpoint_id, created_at, next_attempt_at)
+SELECT gen_random_uuid(), c.id, ep.id, NOW(), NOW()
+FROM claimed AS c
+INNER JOIN event_endpoints AS ep ON ep.user_id = c.user_id
+WHERE ep.enabled = TRUE
+  AND (
+    cardinality(ep.event_types) = 0
+    OR c.type = ANY(ep.event_types)
+    OR split_part(c.type, '.', 1) || '.*' = ANY(ep.event_types)
+  )
+ON CONFLICT (event_id, endpoint_id) DO NOTHING;
+
+-- name: CreateEventEndpoint :one
+INSERT INTO event_endpoints (
+    id,
+    user_id,
+    url,
+    secret,
+    event_types,
+    created_at,
+    updated_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    NOW(),
+    NOW()
+)
+RETURNING *;
+
+-- name: GetEventEndpointsForUser :many
+SELECT * FROM event_endpoints
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: DeleteEventEndpoint :execrows
+DELETE FROM event_endpoints
+WHERE id = $1 AND user_id = $2;
+
+-- name: ClaimDueEventDeliveries :many
+WITH claimed AS (
+    UPDATE event_deliveries
+    SET next_attempt_at = sqlc.arg(lease_until)
+    WHERE event_deliveries.id IN (
+        SELECT ed.id FROM event_deliveries AS ed
+        WHERE ed.status = 'pending' AND ed.next_attempt_at <= NOW()
+        ORDER BY ed.next_attempt_at
+        LIMIT sqlc.arg(batch_size)
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING event_deliveries.id, event_deliveries.event_id, event_deliveries.endpoint_id, event_deliveries.attempts
+)
+SELECT
+    c.id,
+    c.attempts,
+    e.id AS event_id,
+    e.seq,
+    e.type,
+    e.payload,
+    e.created_at,
+    ep.url,
+    ep.secret
+FROM claimed AS c
+INNER JOIN events AS e ON c.event_id = e.id
+INNER JOIN event_endpoints AS ep ON c.endpoint_id = ep.id
+ORDER BY e.seq;
+
+-- name: RecordEventDeliveryAttempt :exec
+UPDATE event_deliveries
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    delivered_at = CASE WHEN sqlc.arg(status)::text = 'delivered' THEN NOW() ELSE delivered_at END
+WHERE id = sqlc.arg(id);
+
+-- name: GetEventDeliveriesForEndpoint :many
+SELECT
+    ed.id,
+    ed.event_id,
+    e.seq,
+    e.type,
+    ed.status,
+    ed.attempts,
+    ed.last_error,
+    ed.next_attempt_at,
+    ed.created_at,
+    ed.delivered_at
+FROM event_deliveries AS ed
+INNER JOIN events AS e ON ed.event_id = e.id
+INNER JOIN event_endpoints AS ep ON ed.endpoint_id = ep.id
+WHERE ed.endpoint_id = sqlc.arg(endpoint_id) AND ep.user_id = sqlc.arg(user_id)
+ORDER BY ed.created_at DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ReplayEvents :execrows
+INSERT INTO event_deliveries (id, event_id, endpoint_id, created_at, next_attempt_at)
+SELECT gen_random_uuid(), e.id, ep.id, NOW(), NOW()
+FROM events AS e
+INNER JOIN event_endpoints AS ep ON ep.user_id = e.user_id
+WHERE ep.id = sqlc.arg(endpoint_id)
+  AND ep.user_id = sqlc.arg(user_id)
+  AND e.created_at >= sqlc.arg(since)
+  AND e.created_at < sqlc.arg(until)
+  AND (
+    cardinality(ep.event_types) = 0
+    OR e.type = ANY(ep.event_types)
+    OR split_part(e.type, '.', 1) || '.*' = ANY(ep.event_types)
+  )
+ON CONFLICT (event_id, endpoint_id) DO UPDATE
+SET status = 'pending',
+    attempts = 0,
+    last_error = NULL,
+    next_attempt_at = NOW(),
+    delivered_at = NULL;
@@ -0,0 +1,143 @@
+-- name: UpsertSecurity :exec
+INSERT INTO securities (
+    id,
+    name,
+    ticker_symbol,
+    type,
+    close_price,
+    iso_currency_code,
+    updated_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    NOW()
+)
+ON CONFLICT (id) DO UPDATE SET
+    name = EXCLUDED.name,
+    ticker_symbol = EXCLUDED.ticker_symbol,
+    type = EXCLUDED.type,
+    close_price = EXCLUDED.close_price,
+    iso_currency_code = EXCLUDED.iso_currency_code,
+    updated_at = NOW();
+
+-- name: DeleteHoldingsForItem :exec
+DELETE FROM holdings
+WHERE account_id IN (
+    SELECT id FROM accounts
+    WHERE item_id = sqlc.arg(item_id)::text
+);
+
+-- name: UpsertHolding :exec
+INSERT INTO holdings (
+    account_id,
+    security_id,
+    quantity,
+    institution_price,
+    institution_value,
+    cost_basis,
+    iso_currency_code,
+    updated_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    NOW()
+)
+ON CONFLICT (account_id, security_id) DO UPDATE SET
+    quantity = EXCLUDED.quantity,
+    institution_price = EXCLUDED.institution_price,
+    institution_value = EXCLUDED.institution_value,
+    cost_basis = EXCLUDED.cost_basis,
+    iso_currency_code = EXCLUDED.iso_currency_code,
+    updated_at = NOW();
+
+-- name: GetHoldingsForAccount :many
+SELECT
+    h.account_id,
+    h.security_id,
+    s.name,
+    s.ticker_symbol,
+    s.type,
+    h.quantity,
+    h.institution_price,
+    h.institution_value,
+    h.cost_basis,
+    h.iso_currency_code,
+    h.updated_at
+FROM holdings AS h
+INNER JOIN securities AS s ON h.security_id = s.id
+WHERE h.account_id = $1
+ORDER BY h.institution_value DESC;
+
+-- name: UpsertInvestmentTransaction :exec
+INSERT INTO investment_transactions (
+    id,
+    account_id,
+    security_id,
+    date,
+    name,
+    type,
+    subtype,
+    quantity,
+    price,
+    amount,
+    fees,
+    iso_currency_code,
+    created_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    NOW()
+)
+ON CONFLICT (id) DO UPDATE SET
+    security_id = EXCLUDED.security_id,
+    date = EXCLUDED.date,
+    name = EXCLUDED.name,
+    type = EXCLUDED.type,
+    subtype = EXCLUDED.subtype,
+    quantity = EXCLUDED.quantity,
+    price = EXCLUDED.price,
+    amount = EXCLUDED.amount,
+    fees = EXCLUDED.fees,
+    iso_currency_code = EXCLUDED.iso_currency_code;
+
+-- name: GetInvestmentTransactionsForAccount :many
+SELECT
+    it.id,
+    it.account_id,
+    it.security_id,
+    s.ticker_symbol,
+    it.date,
+    it.name,
+    it.type,
+    it.subtype,
+    it.quantity,
+    it.price,
+    it.amount,
+    it.fees,
+    it.iso_currency_code
+FROM investment_transactions AS it
+LEFT JOIN securities AS s ON it.security_id = s.id
+WHERE it.account_id = $1
+ORDER BY it.date DESC, it.id;
@@ -0,0 +1,32 @@
+-- name: GetItemStatusesForUser :many
+SELECT * FROM item_status
+WHERE user_id = $1;
+
+-- name: RecordItemSynced :exec
+INSERT INTO item_status (item_id, user_id, last_synced_at, updated_at)
+VALUES ($1, $2, NOW(), NOW())
+ON CONFLICT (item_id) DO UPDATE
+SET last_synced_at = NOW(),
+    last_error_code = NULL,
+    last_error_at = NULL,
+    updated_at = NOW();
+
+-- name: RecordItemError :exec
+INSERT INTO item_status (item_id, user_id, last_error_code, last_error_at, updated_at)
+VALUES ($1, $2, $3, NOW(), NOW())
+ON CONFLICT (item_id) DO UPDATE
+SET last_error_code = EXCLUDED.last_error_code,
+    last_error_at = NOW(),
+    updated_at = NOW();
+
+-- name: ClearItemError :exec
+UPDATE item_status
+SET last_error_code = NULL, last_error_at = NULL, updated_at = NOW()
+WHERE item_id = $1;
+
+-- name: SetItemConsentExpiration :exec
+INSERT INTO item_status (item_id, user_id, consent_expires_at, updated_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (item_id) DO UPDATE
+SET consent_expires_at = EXCLUDED.consent_expires_at,
+    updated_at = NOW();
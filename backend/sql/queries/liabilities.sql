@@ -0,0 +1,92 @@
+-- name: DeleteLiabilitiesForItem :exec
+DELETE FROM liabilities
+WHERE account_id IN (
+    SELECT id FROM accounts
+    WHERE item_id = sqlc.arg(item_id)::text
+);
+
+-- name: UpsertLiability :exec
+INSERT INTO liabilities (
+    account_id,
+    type,
+    interest_rate,
+    interest_rate_type,
+    minimum_payment,
+    next_payment_due_date,
+    last_statement_balance,
+    last_statement_date,
+    last_payment_amount,
+    last_payment_date,
+    is_overdue,
+    origination_principal,
+    origination_date,
+    maturity_date,
+    loan_term,
+    loan_name,
+    updated_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14,
+    $15,
+    $16,
+    NOW()
+)
+ON CONFLICT (account_id) DO UPDATE SET
+    type = EXCLUDED.type,
+    interest_rate = EXCLUDED.interest_rate,
+    interest_rate_type = EXCLUDED.interest_rate_type,
+    minimum_payment = EXCLUDED.minimum_payment,
+    next_payment_due_date = EXCLUDED.next_payment_due_date,
+    last_statement_balance = EXCLUDED.last_statement_balance,
+    last_statement_date = EXCLUDED.last_statement_date,
+    last_payment_amount = EXCLUDED.last_payment_amount,
+    last_payment_date = EXCLUDED.last_payment_date,
+    is_overdue = EXCLUDED.is_overdue,
+    origination_principal = EXCLUDED.origination_principal,
+    origination_date = EXCLUDED.origination_date,
+    maturity_date = EXCLUDED.maturity_date,
+    loan_term = EXCLUDED.loan_term,
+    loan_name = EXCLUDED.loan_name,
+    updated_at = NOW();
+
+-- name: UpsertLiabilityAPR :exec
+INSERT INTO liability_aprs (
+    account_id,
+    apr_type,
+    apr_percentage,
+    balance_subject_to_apr,
+    interest_charge_amount
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (account_id, apr_type) DO UPDATE SET
+    apr_percentage = EXCLUDED.apr_percentage,
+    balance_subject_to_apr = EXCLUDED.balance_subject_to_apr,
+    interest_charge_amount = EXCLUDED.interest_charge_amount;
+
+-- name: GetLiabilityForAccount :one
+SELECT * FROM liabilities
+WHERE account_id = $1;
+
+-- name: GetLiabilityAPRsForAccount :many
+SELECT * FROM liability_aprs
+WHERE account_id = $1
+ORDER BY apr_percentage DESC;
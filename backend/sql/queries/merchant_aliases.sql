@@ -0,0 +1,42 @@
+-- name: GetMerchantAliases :many
+SELECT * FROM merchant_aliases
+WHERE user_id = $1
+ORDER BY merchant, raw_name;
+
+-- name: UpsertMerchantAlias :one
+INSERT INTO merchant_aliases (id, user_id, raw_name, match_key, merchant, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+ON CONFLICT (user_id, match_key) DO UPDATE
+SET raw_name = EXCLUDED.raw_name,
+    merchant = EXCLUDED.merchant
+RETURNING *;
+
+-- name: DeleteMerchantAlias :execrows
+DELETE FROM merchant_aliases
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetUserMerchantNames :many
+SELECT
+    t.merchant_name,
+    t.name,
+    t.canonical_merchant,
+    COUNT(*) AS txn_count
+FROM transactions AS t
+INNER JOIN accounts AS a ON t.account_id = a.id
+WHERE a.user_id = $1
+GROUP BY t.merchant_name, t.name, t.canonical_merchant;
+
+-- name: SetCanonicalMerchant :execrows
+UPDATE transactions AS t
+SET canonical_merchant = sqlc.arg(canonical_merchant)
+FROM accounts AS a
+WHERE t.account_id = a.id
+  AND a.user_id = sqlc.arg(user_id)
+  AND t.merchant_name IS NOT DISTINCT FROM sqlc.arg(merchant_name)
+  AND t.name IS NOT DISTINCT FROM sqlc.arg(name);
+
+-- name: GetUsersWithUnnormalizedMerchants :many
+SELECT DISTINCT a.user_id
+FROM transactions AS t
+INNER JOIN accounts AS a ON t.account_id = a.id
+WHERE t.canonical_merchant IS NULL;
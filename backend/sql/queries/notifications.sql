@@ -0,0 +1,115 @@
+-- name: CreateNotificationRule :one
+INSERT INTO notification_rules (
+    id,
+    user_id,
+    kind,
+    threshold,
+    category,
+    account_id,
+    channel,
+    webhook_url,
+    webhook_secret,
+    created_at,
+    updated_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    NOW(),
+    NOW()
+)
+RETURNING *;
+
+-- name: GetNotificationRulesForUser :many
+SELECT * FROM notification_rules
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: GetEnabledNotificationRules :many
+SELECT * FROM notification_rules
+WHERE user_id = $1 AND kind = $2 AND enabled = TRUE;
+
+-- name: GetNotificationRule :one
+SELECT * FROM notification_rules
+WHERE id = $1;
+
+-- name: DeleteNotificationRule :execrows
+DELETE FROM notification_rules
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateNotificationDelivery :exec
+INSERT INTO notification_deliveries (
+    id,
+    rule_id,
+    user_id,
+    event_key,
+    subject,
+    message,
+    created_at,
+    next_attempt_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    NOW(),
+    NOW()
+)
+ON CONFLICT (rule_id, event_key) DO NOTHING;
+
+-- name: ClaimDueNotificationDeliveries :many
+UPDATE notification_deliveries
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+    SELECT nd.id FROM notification_deliveries AS nd
+    WHERE nd.status = 'pending' AND nd.next_attempt_at <= NOW()
+    ORDER BY nd.next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RecordNotificationAttempt :exec
+UPDATE notification_deliveries
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    delivered_at = CASE WHEN sqlc.arg(status)::text = 'delivered' THEN NOW() ELSE delivered_at END
+WHERE id = sqlc.arg(id);
+
+-- name: GetNotificationDeliveriesForUser :many
+SELECT
+    nd.id,
+    nd.rule_id,
+    nr.kind,
+    nr.channel,
+    nd.subject,
+    nd.status,
+    nd.attempts,
+    nd.last_error,
+    nd.next_attempt_at,
+    nd.created_at,
+    nd.delivered_at
+FROM notification_deliveries AS nd
+INNER JOIN notification_rules AS nr ON nd.rule_id = nr.id
+WHERE nd.user_id = sqlc.arg(user_id)
+ORDER BY nd.created_at DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: RetryNotificationDelivery :one
+UPDATE notification_deliveries
+SET status = 'pending',
+    next_attempt_at = NOW()
+WHERE id = $1 AND user_id = $2 AND status = 'failed'
+RETURNING *;
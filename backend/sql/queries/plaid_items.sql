@@ -1,5 +1,5 @@
 -- name: CreateItem :one
-INSERT INTO plaid_items(id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, created_at, updated_at)
+INSERT INTO plaid_items(id, user_id, access_token, institution_name, nickname, transaction_sync_cursor, country_code, created_at, updated_at)
 VALUES (
     $1,
     $2,
@@ -7,6 +7,7 @@ VALUES (
     $4,
     $5,
     $6,
+    $7,
     NOW(),
     NOW()
 )
@@ -34,6 +35,13 @@ SET nickname = $1, updated_at = NOW()
 WHERE id = $2 AND user_id = $3;
 
 -- name: DeleteItem :exec
+WITH removed AS (
+    INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type, created_at)
+    SELECT a.user_id, t.id, t.account_id, 'removed', NOW()
+    FROM transactions AS t
+    INNER JOIN accounts AS a ON t.account_id = a.id
+    WHERE a.item_id = $1 AND a.user_id = $2
+)
 DELETE FROM plaid_items
 WHERE id = $1 AND user_id = $2;
 
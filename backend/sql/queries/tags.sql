@@ -29,4 +29,20 @@ AND user_id = $2;
 
 -- name: DeleteAllTagsForUser :exec
 DELETE FROM transaction_tags
-WHERE user_id = $1;
\ No newline at end of file
+WHERE user_id = $1;
+-- name: UpsertTag :one
+INSERT INTO transaction_tags (
+    id,
+    name,
+    user_id,
+    created_at
+)
+VALUES (
+    $1,
+    $2,
+    $3,
+    NOW()
+)
+ON CONFLICT (name, user_id) DO UPDATE SET
+    name = EXCLUDED.name
+RETURNING *;
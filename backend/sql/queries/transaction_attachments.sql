@@ -0,0 +1,37 @@
+-- name: CreateTransactionAttachment :one
+INSERT INTO transaction_attachments (
+    id,
+    transaction_id,
+    user_id,
+    file_name,
+    content_type,
+    size_bytes,
+    sha256,
+    storage_key,
+    created_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+RETURNING *;
+
+-- name: GetTransactionAttachments :many
+SELECT * FROM transaction_attachments
+WHERE transaction_id = $1
+ORDER BY created_at;
+
+-- name: GetTransactionAttachment :one
+SELECT * FROM transaction_attachments
+WHERE id = $1
+AND transaction_id = $2;
+
+-- name: DeleteTransactionAttachment :exec
+DELETE FROM transaction_attachments
+WHERE id = $1;
+
+-- name: GetOrphanedAttachmentBlobs :many
+SELECT storage_key FROM orphaned_attachment_blobs
+ORDER BY deleted_at
+LIMIT $1;
+
+-- name: DeleteOrphanedAttachmentBlob :exec
+DELETE FROM orphaned_attachment_blobs
+WHERE storage_key = $1;
@@ -0,0 +1,28 @@
+-- name: RecordTransactionChanges :exec
+INSERT INTO transaction_changes (
+    user_id,
+    transaction_id,
+    account_id,
+    change_type,
+    created_at
+)
+SELECT
+    sqlc.arg(user_id),
+    unnest(sqlc.arg(transaction_ids)::text[]),
+    unnest(sqlc.arg(account_ids)::text[]),
+    sqlc.arg(change_type),
+    NOW();
+
+-- name: GetTransactionChanges :many
+SELECT * FROM transaction_changes
+WHERE user_id = sqlc.arg(user_id)
+  AND seq > sqlc.arg(after_seq)
+ORDER BY seq
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetTransactionsByIDs :many
+SELECT t.*
+FROM transactions AS t
+INNER JOIN accounts AS a ON t.account_id = a.id
+WHERE a.user_id = sqlc.arg(user_id)
+  AND t.id = ANY(sqlc.arg(ids)::text[]);
@@ -0,0 +1,15 @@
+-- name: GetTransactionNote :one
+SELECT * FROM transaction_notes
+WHERE transaction_id = $1;
+
+-- name: UpsertTransactionNote :one
+INSERT INTO transaction_notes (transaction_id, note, updated_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (transaction_id) DO UPDATE
+SET note = EXCLUDED.note,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteTransactionNote :exec
+DELETE FROM transaction_notes
+WHERE transaction_id = $1;
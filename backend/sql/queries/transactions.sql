@@ -8,6 +8,7 @@ INSERT INTO transactions (
     merchant_name,
     payment_channel,
     personal_finance_category,
+    canonical_merchant,
     created_at,
     updated_at)
 VALUES (
@@ -19,6 +20,7 @@ VALUES (
     $6,
     $7,
     $8,
+    $9,
     NOW(),
     NOW()
 )
@@ -36,12 +38,61 @@ DELETE FROM transactions
 WHERE id = $1 AND account_id = $2;
 
 -- name: DeleteTransactionsForAccount :exec
-DELETE FROM transactions
-WHERE account_id = $1;
+WITH deleted AS (
+    DELETE FROM transactions
+    WHERE account_id = $1
+    RETURNING id, account_id
+)
+INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type, created_at)
+SELECT a.user_id, d.id, d.account_id, 'removed', NOW()
+FROM deleted AS d
+INNER JOIN accounts AS a ON d.account_id = a.id;
 
 
 -- name: GetTransactionsForUser :many
 SELECT t.* 
 FROM transactions AS t
 INNER JOIN accounts AS a ON t.account_id = a.id
-WHERE a.user_id = $1;
\ No newline at end of file
+WHERE a.user_id = $1;
+-- name: GetTransactionForAccount :one
+SELECT * FROM transactions
+WHERE id = $1 AND account_id = $2;
+
+-- name: UpdateTransactionCategory :one
+WITH override AS (
+    INSERT INTO transaction_category_overrides (
+        transaction_id,
+        category,
+        updated_at
+    )
+    SELECT id, $2, NOW()
+    FROM transactions
+    WHERE id = $1 AND account_id = $3
+    ON CONFLICT (transaction_id) DO UPDATE SET
+        category = EXCLUDED.category,
+        updated_at = NOW()
+), change AS (
+    INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type, created_at)
+    SELECT a.user_id, t.id, t.account_id, 'modified', NOW()
+    FROM transactions AS t
+    INNER JOIN accounts AS a ON t.account_id = a.id
+    WHERE t.id = $1 AND t.account_id = $3
+)
+UPDATE transactions
+SET personal_finance_category = $2,
+    updated_at = NOW()
+WHERE id = $1 AND account_id = $3
+RETURNING *;
+
+-- name: UpdateManualTransaction :one
+UPDATE transactions
+SET amount = $3,
+    iso_currency_code = $4,
+    date = $5,
+    merchant_name = $6,
+    payment_channel = $7,
+    personal_finance_category = $8,
+    canonical_merchant = $9,
+    updated_at = NOW()
+WHERE id = $1 AND account_id = $2
+RETURNING *;
@@ -6,7 +6,8 @@ INSERT INTO transactions_to_tags (
 VALUES (
     $1,
     $2
-);
+)
+ON CONFLICT DO NOTHING;
 
 -- name: GetTransactionsWithTag :many
 SELECT * FROM transactions_to_tags
@@ -14,4 +15,11 @@ WHERE tag_id = $1;
 
 -- name: GetTagsForTransaction :many
 SELECT * FROM transactions_to_tags
-WHERE transaction_id = $1;
\ No newline at end of file
+WHERE transaction_id = $1;
+
+-- name: GetTagNamesForTransaction :many
+SELECT tt.name
+FROM transaction_tags AS tt
+INNER JOIN transactions_to_tags AS ttt ON ttt.tag_id = tt.id
+WHERE ttt.transaction_id = $1
+ORDER BY tt.name;
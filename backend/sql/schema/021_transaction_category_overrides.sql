@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE transaction_category_overrides (
+    transaction_id TEXT PRIMARY KEY REFERENCES transactions(id)
+    ON DELETE CASCADE,
+    category TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE transaction_category_overrides;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE anomalies (
+    id UUID PRIMARY KEY,
+    transaction_id TEXT NOT NULL REFERENCES transactions(id)
+    ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    acknowledged_at TIMESTAMPTZ,
+    UNIQUE (transaction_id, kind)
+);
+
+CREATE INDEX anomalies_user_id_idx ON anomalies (user_id, acknowledged_at);
+
+-- +goose Down
+DROP TABLE anomalies;
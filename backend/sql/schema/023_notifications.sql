@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE TABLE notification_rules (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    threshold TEXT,
+    category TEXT,
+    account_id TEXT REFERENCES accounts(id)
+    ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    webhook_url TEXT,
+    webhook_secret TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX notification_rules_user_id_idx ON notification_rules (user_id, kind);
+
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY,
+    rule_id UUID NOT NULL REFERENCES notification_rules(id)
+    ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    event_key TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    message TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    UNIQUE (rule_id, event_key)
+);
+
+CREATE INDEX notification_deliveries_user_id_idx ON notification_deliveries (user_id, created_at);
+CREATE INDEX notification_deliveries_due_idx ON notification_deliveries (status, next_attempt_at);
+
+-- +goose Down
+DROP TABLE notification_deliveries;
+DROP TABLE notification_rules;
@@ -0,0 +1,51 @@
+-- +goose Up
+CREATE TABLE events (
+    id UUID PRIMARY KEY,
+    seq BIGSERIAL NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMPTZ
+);
+
+CREATE INDEX events_user_id_idx ON events (user_id, seq);
+CREATE INDEX events_undispatched_idx ON events (seq) WHERE dispatched_at IS NULL;
+
+CREATE TABLE event_endpoints (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX event_endpoints_user_id_idx ON event_endpoints (user_id);
+
+CREATE TABLE event_deliveries (
+    id UUID PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id)
+    ON DELETE CASCADE,
+    endpoint_id UUID NOT NULL REFERENCES event_endpoints(id)
+    ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    UNIQUE (event_id, endpoint_id)
+);
+
+CREATE INDEX event_deliveries_endpoint_id_idx ON event_deliveries (endpoint_id, created_at);
+CREATE INDEX event_deliveries_due_idx ON event_deliveries (status, next_attempt_at);
+
+-- +goose Down
+DROP TABLE event_deliveries;
+DROP TABLE event_endpoints;
+DROP TABLE events;
@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE TABLE securities (
+    id TEXT PRIMARY KEY,
+    name TEXT,
+    ticker_symbol TEXT,
+    type TEXT,
+    close_price NUMERIC(20, 6),
+    iso_currency_code TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE holdings (
+    account_id TEXT NOT NULL REFERENCES accounts(id)
+    ON DELETE CASCADE,
+    security_id TEXT NOT NULL REFERENCES securities(id),
+    quantity NUMERIC(24, 8) NOT NULL,
+    institution_price NUMERIC(20, 6) NOT NULL,
+    institution_value NUMERIC(16, 2) NOT NULL,
+    cost_basis NUMERIC(16, 2),
+    iso_currency_code TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, security_id)
+);
+
+CREATE TABLE investment_transactions (
+    id TEXT PRIMARY KEY,
+    account_id TEXT NOT NULL REFERENCES accounts(id)
+    ON DELETE CASCADE,
+    security_id TEXT REFERENCES securities(id),
+    date DATE NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    subtype TEXT NOT NULL,
+    quantity NUMERIC(24, 8) NOT NULL,
+    price NUMERIC(20, 6) NOT NULL,
+    amount NUMERIC(16, 2) NOT NULL,
+    fees NUMERIC(16, 2),
+    iso_currency_code TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX investment_transactions_account_id_idx ON investment_transactions (account_id, date);
+
+-- +goose Down
+DROP TABLE investment_transactions;
+DROP TABLE holdings;
+DROP TABLE securities;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE plaid_items
+ADD country_code TEXT NOT NULL DEFAULT 'CA';
+
+-- +goose Down
+ALTER TABLE plaid_items
+DROP COLUMN country_code;
@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE liabilities (
+    account_id TEXT PRIMARY KEY REFERENCES accounts(id)
+    ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    interest_rate NUMERIC(8, 4),
+    interest_rate_type TEXT,
+    minimum_payment NUMERIC(16, 2),
+    next_payment_due_date DATE,
+    last_statement_balance NUMERIC(16, 2),
+    last_statement_date DATE,
+    last_payment_amount NUMERIC(16, 2),
+    last_payment_date DATE,
+    is_overdue BOOLEAN,
+    origination_principal NUMERIC(16, 2),
+    origination_date DATE,
+    maturity_date DATE,
+    loan_term TEXT,
+    loan_name TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE liability_aprs (
+    account_id TEXT NOT NULL REFERENCES liabilities(account_id)
+    ON DELETE CASCADE,
+    apr_type TEXT NOT NULL,
+    apr_percentage NUMERIC(8, 4) NOT NULL,
+    balance_subject_to_apr NUMERIC(16, 2),
+    interest_charge_amount NUMERIC(16, 2),
+    PRIMARY KEY (account_id, apr_type)
+);
+
+-- +goose Down
+DROP TABLE liability_aprs;
+DROP TABLE liabilities;
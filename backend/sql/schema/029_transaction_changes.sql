@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE transaction_changes (
+    seq BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    transaction_id TEXT NOT NULL,
+    account_id TEXT NOT NULL,
+    change_type TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX transaction_changes_user_id_idx ON transaction_changes (user_id, seq);
+
+-- Existing transactions are recorded as added, so clients reading the feed from the start receive all of them
+INSERT INTO transaction_changes (user_id, transaction_id, account_id, change_type)
+SELECT a.user_id, t.id, t.account_id, 'added'
+FROM transactions AS t
+INNER JOIN accounts AS a ON t.account_id = a.id
+ORDER BY t.date, t.id;
+
+-- +goose Down
+DROP TABLE transaction_changes;
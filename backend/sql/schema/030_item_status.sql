@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE item_status (
+    item_id TEXT PRIMARY KEY REFERENCES plaid_items(id)
+    ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    last_synced_at TIMESTAMPTZ,
+    last_error_code TEXT,
+    last_error_at TIMESTAMPTZ,
+    consent_expires_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX item_status_user_id_idx ON item_status (user_id);
+
+-- +goose Down
+DROP TABLE item_status;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE account_overrides (
+    account_id TEXT PRIMARY KEY REFERENCES accounts(id)
+    ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    nickname TEXT,
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    include_in_net_worth BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX account_overrides_user_id_idx ON account_overrides (user_id);
+
+-- +goose Down
+DROP TABLE account_overrides;
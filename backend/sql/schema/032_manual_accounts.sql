@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE accounts
+ALTER COLUMN item_id DROP NOT NULL;
+ALTER TABLE accounts
+ADD is_manual BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE accounts
+ADD opening_balance NUMERIC (16, 2);
+
+-- Only manual accounts exist without a Plaid item
+ALTER TABLE accounts
+ADD CONSTRAINT accounts_item_or_manual CHECK (is_manual OR item_id IS NOT NULL);
+
+-- +goose Down
+DELETE FROM accounts
+WHERE is_manual;
+ALTER TABLE accounts
+DROP CONSTRAINT accounts_item_or_manual;
+ALTER TABLE accounts
+DROP COLUMN opening_balance;
+ALTER TABLE accounts
+DROP COLUMN is_manual;
+ALTER TABLE accounts
+ALTER COLUMN item_id SET NOT NULL;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE transaction_notes (
+    transaction_id TEXT PRIMARY KEY REFERENCES transactions(id)
+    ON DELETE CASCADE,
+    note TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE transaction_attachments (
+    id UUID PRIMARY KEY,
+    transaction_id TEXT NOT NULL REFERENCES transactions(id)
+    ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    sha256 TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX transaction_attachments_transaction_id_idx ON transaction_attachments (transaction_id);
+
+-- +goose Down
+DROP TABLE transaction_attachments;
+DROP TABLE transaction_notes;
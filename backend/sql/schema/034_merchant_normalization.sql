@@ -0,0 +1,27 @@
+-- +goose Up
+ALTER TABLE transactions
+ADD name TEXT;
+
+-- Merchant the transaction is summarized under, found by the server from merchant_name, or name when there
+-- is no merchant name, with its cleanup rules and the user's aliases. Transactions still NULL here are
+-- normalized when the server starts
+ALTER TABLE transactions
+ADD canonical_merchant TEXT;
+
+CREATE TABLE merchant_aliases (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    raw_name TEXT NOT NULL,
+    match_key TEXT NOT NULL,
+    merchant TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, match_key)
+);
+
+-- +goose Down
+DROP TABLE merchant_aliases;
+ALTER TABLE transactions
+DROP COLUMN canonical_merchant;
+ALTER TABLE transactions
+DROP COLUMN name;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Sequence numbers of a user's feed rows are taken under a lock on the user held until the writing transaction
+-- commits, so they become visible in order, and a client paging on seq can't pass a row that commits later.
+-- The lock is shared by all of a user's feeds, so transactions writing to more than one can't deadlock. seq has
+-- been given a value from its default by the time this runs, so gaps in it are expected
+-- +goose StatementBegin
+CREATE FUNCTION assign_feed_seq() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtextextended('greed_feed:' || NEW.user_id::text, 0));
+    NEW.seq := nextval(pg_get_serial_sequence(format('%I.%I', TG_TABLE_SCHEMA, TG_TABLE_NAME), 'seq'));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER transaction_changes_assign_seq
+BEFORE INSERT ON transaction_changes
+FOR EACH ROW EXECUTE FUNCTION assign_feed_seq();
+
+-- +goose Down
+DROP TRIGGER transaction_changes_assign_seq ON transaction_changes;
+DROP FUNCTION assign_feed_seq();
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Events take their seq under the same per-user lock as transaction changes, so event polling can't pass an
+-- event committed after its cursor
+CREATE TRIGGER events_assign_seq
+BEFORE INSERT ON events
+FOR EACH ROW EXECUTE FUNCTION assign_feed_seq();
+
+-- +goose Down
+DROP TRIGGER events_assign_seq ON events;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Storage keys of deleted attachments, whose files are removed from the blob store by the server's sweeper.
+-- Attachments are deleted with their transaction, account, item or user, so rows are queued by a trigger,
+-- catching every path that deletes them, including cascades
+CREATE TABLE orphaned_attachment_blobs (
+    storage_key TEXT PRIMARY KEY,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION queue_attachment_blob() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO orphaned_attachment_blobs (storage_key)
+    VALUES (OLD.storage_key)
+    ON CONFLICT (storage_key) DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER transaction_attachments_queue_blob
+AFTER DELETE ON transaction_attachments
+FOR EACH ROW EXECUTE FUNCTION queue_attachment_blob();
+
+-- +goose Down
+DROP TRIGGER transaction_attachments_queue_blob ON transaction_attachments;
+DROP FUNCTION queue_attachment_blob();
+DROP TABLE orphaned_attachment_blobs;
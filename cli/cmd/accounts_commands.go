@@ -2,9 +2,10 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/jms-guy/greed/cli/internal/auth"
 	"github.com/jms-guy/greed/cli/internal/database"
@@ -13,8 +14,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// List accounts for a given item name
-func (app *CLIApp) commandListAccounts(cmd *cobra.Command, args []string) error {
+// List accounts for a given item name. Hidden and archived accounts are left out unless all is set
+func (app *CLIApp) commandListAccounts(cmd *cobra.Command, args []string, all bool) error {
 	itemName := args[0]
 
 	item, err := getItemFromServer(app, itemName)
@@ -23,27 +24,20 @@ func (app *CLIApp) commandListAccounts(cmd *cobra.Command, args []string) error
 		return err
 	}
 
-	accountsURL := app.Config.Client.BaseURL + "/api/items/" + item.ItemId + "/accounts"
-
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("GET", accountsURL, token, nil)
-	})
+	response, err := app.Config.Client.GetAccountsForItem(context.Background(), item.ItemId)
 	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
-	defer resp.Body.Close()
 
-	err = checkResponseStatus(resp)
-	if err != nil {
-		LogError(app.Config.Db, cmd, err, "Error contacting server")
-		return err
+	if !all {
+		response = slices.DeleteFunc(response, func(acc models.Account) bool {
+			return acc.Hidden || acc.Archived
+		})
 	}
 
-	var response []models.Account
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
-		return err
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, response)
 	}
 
 	tbl := tables.MakeAccountsTable(response, item.InstitutionName)
@@ -52,8 +46,9 @@ func (app *CLIApp) commandListAccounts(cmd *cobra.Command, args []string) error
 	return nil
 }
 
-// List all accounts for user
-func (app *CLIApp) commandListAllAccounts(cmd *cobra.Command) error {
+// List all accounts for user, with their net worth. Hidden and archived accounts are left out of the list
+// unless all is set, but are still counted towards net worth unless excluded from it
+func (app *CLIApp) commandListAllAccounts(cmd *cobra.Command, all bool) error {
 	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error getting credentials")
@@ -66,8 +61,24 @@ func (app *CLIApp) commandListAllAccounts(cmd *cobra.Command) error {
 		return err
 	}
 
+	netWorth := tables.NetWorth(accounts)
+	if !all {
+		accounts = slices.DeleteFunc(accounts, func(acc database.Account) bool {
+			return acc.Hidden || acc.Archived
+		})
+	}
+
+	if app.Output.IsMachine() {
+		response := []models.Account{}
+		for _, acc := range accounts {
+			response = append(response, accountToModel(acc))
+		}
+		return app.writeOutput(cmd, response)
+	}
+
 	tbl := tables.MakeAccountsTableAllItems(accounts)
 	tbl.Print()
+	fmt.Printf("\n < Net worth: %.2f > \n", netWorth)
 
 	return nil
 }
@@ -90,13 +101,9 @@ func (app *CLIApp) commandAccountInfo(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		params := database.GetAccountParams{
-			Name:   accountName,
-			UserID: creds.User.ID.String(),
-		}
-		account, err = app.Config.Db.GetAccount(context.Background(), params)
+		account, err = getLocalAccount(app, creds.User.ID.String(), accountName)
 		if err != nil {
-			LogError(app.Config.Db, cmd, fmt.Errorf("error getting local account: %w", err), "Local database error")
+			LogError(app.Config.Db, cmd, err, "Error finding account")
 			return err
 		}
 
@@ -104,8 +111,142 @@ func (app *CLIApp) commandAccountInfo(cmd *cobra.Command, args []string) error {
 		account = app.Config.Settings.DefaultAccount
 	}
 
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, accountToModel(account))
+	}
+
 	tbl := tables.MakeSingleAccountTable(account)
 	tbl.Print()
 
 	return nil
 }
+
+// Renames an account. Without a nickname, the account goes back to its name from the institution
+func (app *CLIApp) commandRenameAccount(cmd *cobra.Command, args []string) error {
+	nickname := ""
+	if len(args) == 2 {
+		nickname = args[1]
+	}
+
+	account, err := app.updateAccountOverrides(cmd, args[0], models.UpdateAccountOverrides{Nickname: &nickname})
+	if err != nil {
+		return err
+	}
+
+	if nickname == "" {
+		fmt.Printf(" > Nickname removed, account is shown as %s.\n", account.DisplayName)
+	} else {
+		fmt.Printf(" > Account %s renamed to %s.\n", account.Name, account.DisplayName)
+	}
+
+	return nil
+}
+
+// Hides an account from account lists, or shows it again with undo
+func (app *CLIApp) commandHideAccount(cmd *cobra.Command, args []string, undo bool) error {
+	hidden := !undo
+	account, err := app.updateAccountOverrides(cmd, args[0], models.UpdateAccountOverrides{Hidden: &hidden})
+	if err != nil {
+		return err
+	}
+
+	if hidden {
+		fmt.Printf(" > Account %s hidden, list it with `greed get accounts --all`.\n", account.DisplayName)
+	} else {
+		fmt.Printf(" > Account %s shown again.\n", account.DisplayName)
+	}
+
+	return nil
+}
+
+// Archives an account, such as one that has been closed, or restores it with undo. Archived accounts
+// keep their transactions, unlike deleted ones
+func (app *CLIApp) commandArchiveAccount(cmd *cobra.Command, args []string, undo bool) error {
+	archived := !undo
+	account, err := app.updateAccountOverrides(cmd, args[0], models.UpdateAccountOverrides{Archived: &archived})
+	if err != nil {
+		return err
+	}
+
+	if archived {
+		fmt.Printf(" > Account %s archived.\n", account.DisplayName)
+	} else {
+		fmt.Printf(" > Account %s restored.\n", account.DisplayName)
+	}
+
+	return nil
+}
+
+// Sets whether an account counts towards net worth
+func (app *CLIApp) commandAccountNetWorth(cmd *cobra.Command, args []string) error {
+	var include bool
+	switch strings.ToLower(args[1]) {
+	case "include":
+		include = true
+	case "exclude":
+		include = false
+	default:
+		err := fmt.Errorf("expected include or exclude, got %s", args[1])
+		LogError(app.Config.Db, cmd, err, "Invalid argument")
+		return err
+	}
+
+	account, err := app.updateAccountOverrides(cmd, args[0], models.UpdateAccountOverrides{IncludeInNetWorth: &include})
+	if err != nil {
+		return err
+	}
+
+	if include {
+		fmt.Printf(" > Account %s is counted towards net worth.\n", account.DisplayName)
+	} else {
+		fmt.Printf(" > Account %s is no longer counted towards net worth.\n", account.DisplayName)
+	}
+
+	return nil
+}
+
+// Sets where an account is sorted in account lists, lower positions first
+func (app *CLIApp) commandOrderAccount(cmd *cobra.Command, args []string) error {
+	position, err := strconv.Atoi(args[1])
+	if err != nil {
+		LogError(app.Config.Db, cmd, fmt.Errorf("position must be a whole number, got %s", args[1]), "Invalid argument")
+		return err
+	}
+
+	account, err := app.updateAccountOverrides(cmd, args[0], models.UpdateAccountOverrides{SortOrder: &position})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(" > Account %s moved to position %d.\n", account.DisplayName, account.SortOrder)
+
+	return nil
+}
+
+// Finds a local account by name or nickname, updates its overrides on the server, and stores the result locally
+func (app *CLIApp) updateAccountOverrides(cmd *cobra.Command, accountName string, request models.UpdateAccountOverrides) (models.Account, error) {
+	creds, err := auth.GetCreds(app.Config.ConfigFP)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error getting credentials")
+		return models.Account{}, err
+	}
+
+	local, err := getLocalAccount(app, creds.User.ID.String(), accountName)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding account")
+		return models.Account{}, err
+	}
+
+	account, err := app.Config.Client.UpdateAccountOverrides(context.Background(), local.ID, request)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return models.Account{}, err
+	}
+
+	if err := storeAccountOverrides(app, creds.User.ID.String(), account); err != nil {
+		LogError(app.Config.Db, cmd, err, "Local database error")
+		return models.Account{}, err
+	}
+
+	return account, nil
+}
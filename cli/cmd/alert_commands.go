@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/spf13/cobra"
+)
+
+// Lists anomalies flagged by the server, with account names from the local database
+func (app *CLIApp) commandListAlerts(cmd *cobra.Command, all bool) error {
+	anomalies, err := app.Config.Client.GetAnomalies(context.Background(), all)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, anomalies)
+	}
+
+	if len(anomalies) == 0 {
+		fmt.Println(" < No alerts > ")
+		return nil
+	}
+
+	creds, err := auth.GetCreds(app.Config.ConfigFP)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error getting credentials")
+		return err
+	}
+
+	accounts, err := app.Config.Db.GetAllAccounts(context.Background(), creds.User.ID.String())
+	if err != nil {
+		LogError(app.Config.Db, cmd, fmt.Errorf("error getting local account records: %w", err), "Local database error")
+		return err
+	}
+	accountNames := make(map[string]string, len(accounts))
+	for _, acc := range accounts {
+		accountNames[acc.ID] = tables.AccountDisplayName(acc)
+	}
+
+	tables.MakeAlertsTable(anomalies, accountNames).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Acknowledges anomalies by ID, or unique prefix of an ID, or all of them
+func (app *CLIApp) commandAckAlerts(cmd *cobra.Command, args []string, all bool) error {
+	ctx := context.Background()
+
+	if all {
+		if err := app.Config.Client.AcknowledgeAllAnomalies(ctx); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+		fmt.Println(" < All alerts acknowledged > ")
+		return nil
+	}
+
+	if len(args) == 0 {
+		LogError(app.Config.Db, cmd, fmt.Errorf("no alert given"), "Missing alert ID, or use --all")
+		return nil
+	}
+
+	anomalies, err := app.Config.Client.GetAnomalies(ctx, false)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(anomalies))
+	for _, a := range anomalies {
+		ids = append(ids, a.ID)
+	}
+
+	for _, arg := range args {
+		id, err := resolveIDPrefix(ids, arg, "unacknowledged alert")
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, err.Error())
+			return nil
+		}
+
+		if err := app.Config.Client.AcknowledgeAnomaly(ctx, id); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+		fmt.Printf(" < Alert %s acknowledged > \n", id.String()[:8])
+	}
+
+	return nil
+}
+
+// Finds the ID starting with the given prefix, as shown shortened in tables. what names the
+// kind of record in errors
+func resolveIDPrefix(ids []uuid.UUID, prefix, what string) (uuid.UUID, error) {
+	prefix = strings.ToLower(prefix)
+
+	var matches []uuid.UUID
+	for _, id := range ids {
+		if strings.HasPrefix(id.String(), prefix) {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("no %s with ID %s", what, prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return uuid.Nil, fmt.Errorf("ID %s is ambiguous, give more characters", prefix)
+	}
+}
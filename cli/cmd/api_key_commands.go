@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/jms-guy/greed/models"
+	"github.com/spf13/cobra"
+)
+
+// Creates an API key, printing the key once
+func (app *CLIApp) commandCreateAPIKey(cmd *cobra.Command, name, scope, expires string) error {
+	request := models.CreateAPIKey{
+		Name:  name,
+		Scope: scope,
+	}
+
+	if expires != "" {
+		expiresAt, err := parseExpiry(expires, time.Now())
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, err.Error())
+			return nil
+		}
+		request.ExpiresAt = &expiresAt
+	}
+
+	created, err := app.Config.Client.CreateAPIKey(context.Background(), request)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, created)
+	}
+
+	fmt.Printf(" < API key %s created, scope %s > \n", created.ID.String()[:8], created.Scope)
+	fmt.Println("")
+	fmt.Println("API key, it will not be shown again:")
+	fmt.Printf("  %s\n", created.Key)
+	fmt.Println("Send it in place of a login token, as an `Authorization: Bearer <key>` header")
+
+	return nil
+}
+
+// Lists the user's API keys
+func (app *CLIApp) commandListAPIKeys(cmd *cobra.Command) error {
+	keys, err := app.Config.Client.GetAPIKeys(context.Background())
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, keys)
+	}
+
+	if len(keys) == 0 {
+		fmt.Println(" < No API keys > ")
+		return nil
+	}
+
+	tables.MakeAPIKeysTable(keys).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Revokes API keys by ID, or unique prefix of an ID
+func (app *CLIApp) commandRevokeAPIKeys(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	keys, err := app.Config.Client.GetAPIKeys(ctx)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(keys))
+	for _, k := range keys {
+		ids = append(ids, k.ID)
+	}
+
+	for _, arg := range args {
+		id, err := resolveIDPrefix(ids, arg, "API key")
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, err.Error())
+			return nil
+		}
+
+		if err := app.Config.Client.RevokeAPIKey(ctx, id); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+		fmt.Printf(" < API key %s revoked > \n", id.String()[:8])
+	}
+
+	return nil
+}
+
+// Parses an --expires value: a duration from now (ex. 12h, 90d), or a date (2006-01-02) or timestamp (RFC 3339)
+func parseExpiry(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return now.AddDate(0, 0, n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid expires value %q, expected a duration (12h, 90d), a date (2006-01-02) or a timestamp (RFC 3339)", value)
+}
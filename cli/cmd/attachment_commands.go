@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jms-guy/greed/cli/internal/resolve"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/jms-guy/greed/cli/internal/utils"
+	"github.com/jms-guy/greed/models"
+	"github.com/spf13/cobra"
+)
+
+// Shows a transaction's note, or sets it if a note is given. An empty note removes it
+func (app *CLIApp) commandTransactionNote(cmd *cobra.Command, accountName, txnID string, note *string) error {
+	_, account, err := app.manualCommandAccount(cmd, accountName)
+	if err != nil {
+		return err
+	}
+
+	txn, err := getLocalTransaction(app, account.ID, txnID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding transaction")
+		return err
+	}
+
+	var result models.TransactionNote
+	if note == nil {
+		result, err = app.Config.Client.GetTransactionNote(context.Background(), account.ID, txn.ID)
+	} else {
+		result, err = app.Config.Client.UpdateTransactionNote(context.Background(), account.ID, txn.ID, *note)
+	}
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, result)
+	}
+
+	switch {
+	case note != nil && result.Note == "":
+		fmt.Printf(" > Note removed from transaction %s.\n", txn.ID)
+	case note != nil:
+		fmt.Printf(" > Note saved on transaction %s.\n", txn.ID)
+	case result.Note == "":
+		fmt.Println(" < No note > ")
+	default:
+		fmt.Println(result.Note)
+	}
+
+	return nil
+}
+
+// Attaches a file, such as a photo of a receipt, to a transaction
+func (app *CLIApp) commandAttachFile(cmd *cobra.Command, accountName, txnID, filePath string) error {
+	_, account, err := app.manualCommandAccount(cmd, accountName)
+	if err != nil {
+		return err
+	}
+
+	txn, err := getLocalTransaction(app, account.ID, txnID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding transaction")
+		return err
+	}
+
+	file, err := os.Open(filepath.Clean(filePath))
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error opening file")
+		return err
+	}
+	defer file.Close()
+
+	attachment, err := app.Config.Client.AddTransactionAttachment(context.Background(), account.ID, txn.ID, filepath.Base(filePath), file)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error attaching file")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, attachment)
+	}
+
+	fmt.Printf(" > %s attached to transaction %s, as attachment %s.\n", attachment.FileName, txn.ID, attachment.ID[:8])
+
+	return nil
+}
+
+// Lists the files attached to a transaction
+func (app *CLIApp) commandListAttachments(cmd *cobra.Command, accountName, txnID string) error {
+	_, account, err := app.manualCommandAccount(cmd, accountName)
+	if err != nil {
+		return err
+	}
+
+	txn, err := getLocalTransaction(app, account.ID, txnID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding transaction")
+		return err
+	}
+
+	attachments, err := app.Config.Client.GetTransactionAttachments(context.Background(), account.ID, txn.ID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, attachments)
+	}
+
+	if len(attachments) == 0 {
+		fmt.Println(" < No attachments > ")
+		return nil
+	}
+
+	tables.MakeAttachmentsTable(attachments).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Downloads a transaction's attachment, checking its contents against the checksum the server recorded
+// when it was uploaded. Files are saved to out, or the export directory if out is empty, and opened
+// with the system's default program if open is set
+func (app *CLIApp) commandDownloadAttachment(cmd *cobra.Command, accountName, txnID, attachmentID, out string, open bool) error {
+	_, account, err := app.manualCommandAccount(cmd, accountName)
+	if err != nil {
+		return err
+	}
+
+	txn, err := getLocalTransaction(app, account.ID, txnID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding transaction")
+		return err
+	}
+
+	attachment, err := app.findAttachment(account.ID, txn.ID, attachmentID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding attachment")
+		return err
+	}
+
+	path, err := app.attachmentFilePath(attachment, out)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error creating download directory")
+		return err
+	}
+
+	if err := app.downloadAttachment(account.ID, txn.ID, attachment, path); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error downloading attachment")
+		return err
+	}
+
+	if app.Output.IsMachine() && !open {
+		return app.writeOutput(cmd, attachment)
+	}
+
+	fmt.Printf(" > %s saved to %s.\n", attachment.FileName, path)
+
+	if open {
+		if err := utils.OpenLink(app.Config.OperatingSystem, path); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error opening file")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deletes a transaction's attachment
+func (app *CLIApp) commandRemoveAttachment(cmd *cobra.Command, accountName, txnID, attachmentID string) error {
+	_, account, err := app.manualCommandAccount(cmd, accountName)
+	if err != nil {
+		return err
+	}
+
+	txn, err := getLocalTransaction(app, account.ID, txnID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding transaction")
+		return err
+	}
+
+	attachment, err := app.findAttachment(account.ID, txn.ID, attachmentID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding attachment")
+		return err
+	}
+
+	confirmed, err := confirmResolvedDeletion(attachmentID, attachment.ID == attachmentID, "attachment", attachment.FileName)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, err.Error())
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Attachment deletion aborted.")
+		return nil
+	}
+
+	if err := app.Config.Client.DeleteTransactionAttachment(context.Background(), account.ID, txn.ID, attachment.ID); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error deleting attachment")
+		return err
+	}
+
+	fmt.Printf(" > %s removed from transaction %s.\n", attachment.FileName, txn.ID)
+
+	return nil
+}
+
+// Finds a transaction's attachment by its ID, or the start of its ID
+func (app *CLIApp) findAttachment(accountID, txnID, attachmentID string) (models.TransactionAttachment, error) {
+	attachments, err := app.Config.Client.GetTransactionAttachments(context.Background(), accountID, txnID)
+	if err != nil {
+		return models.TransactionAttachment{}, err
+	}
+
+	candidates := make([]resolve.Candidate, len(attachments))
+	for i, a := range attachments {
+		candidates[i] = resolve.Candidate{
+			ID:    a.ID,
+			Names: []string{a.ID},
+			Label: fmt.Sprintf("%s %s", a.ID[:8], a.FileName),
+		}
+	}
+
+	match, err := resolveCandidate(attachmentID, candidates)
+	if err != nil {
+		return models.TransactionAttachment{}, fmt.Errorf("no attachment found: %w", err)
+	}
+
+	for _, a := range attachments {
+		if a.ID == match.ID {
+			return a, nil
+		}
+	}
+
+	return models.TransactionAttachment{}, fmt.Errorf("no attachment found: %w", resolve.ErrNoMatch)
+}
+
+// Path a downloaded attachment is saved to. An empty out saves it to the attachments folder of the export
+// directory, and an out naming a directory saves it there, both under the attachment's file name
+func (app *CLIApp) attachmentFilePath(attachment models.TransactionAttachment, out string) (string, error) {
+	name := fmt.Sprintf("%s-%s", attachment.ID[:8], filepath.Base(attachment.FileName))
+
+	if out == "" {
+		dir := filepath.Join(app.getExportDirectory(), "attachments")
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, name), nil
+	}
+
+	if info, err := os.Stat(out); err == nil && info.IsDir() {
+		return filepath.Join(out, name), nil
+	}
+
+	return out, nil
+}
+
+// Downloads an attachment to path. The file is written beside path first, and only moved into place once
+// its checksum matches, so a failed or corrupted download never replaces an existing file
+func (app *CLIApp) downloadAttachment(accountID, txnID string, attachment models.TransactionAttachment, path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	_, err = app.Config.Client.DownloadTransactionAttachment(context.Background(), accountID, txnID, attachment.ID, io.MultiWriter(tmp, hasher))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if sum := hex.EncodeToString(hasher.Sum(nil)); attachment.SHA256 != "" && sum != attachment.SHA256 {
+		return fmt.Errorf("downloaded file doesn't match its checksum, expected %s but got %s", attachment.SHA256, sum)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error saving file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/config"
+	"github.com/jms-guy/greed/cli/internal/resolve"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/spf13/cobra"
+)
+
+// Completes account names and nicknames for a command's first argument, from the local database
+func (app *CLIApp) completeAccountNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return app.accountNameCompletions(cmd, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// Completes item names for a command's first argument, from the items cached in the local database
+func (app *CLIApp) completeItemNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return app.itemNameCompletions(cmd, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// Completes an --account flag with account names and nicknames
+func (app *CLIApp) completeAccountFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return app.accountNameCompletions(cmd, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// Completes a transaction ID for a command's second argument, from the transactions of the account named
+// by the first. Each ID is described by the transaction's date, amount and merchant
+func (app *CLIApp) completeTransactionIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return app.accountNameCompletions(cmd, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, userID := app.completionConfig(cmd)
+	if cfg == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	accounts, err := cfg.Db.GetAllAccounts(context.Background(), userID)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	candidates := make([]resolve.Candidate, len(accounts))
+	for i, acc := range accounts {
+		candidates[i] = resolve.Candidate{ID: acc.ID, Names: []string{tables.AccountDisplayName(acc), acc.Name}, Mask: acc.Mask.String}
+	}
+	match, err := resolve.Match(args[0], candidates)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	txns, err := cfg.Db.GetTransactions(context.Background(), match.ID)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, txn := range txns {
+		if resolve.HasPrefixFold(txn.ID, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t%s %.2f %s", txn.ID, txn.Date.String, txn.Amount, txn.MerchantName.String))
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Completes the account and transaction of attach, then the path of the file to attach
+func (app *CLIApp) completeAttachmentFile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 2 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return app.completeTransactionIDs(cmd, args, toComplete)
+}
+
+// Names and nicknames of the logged in user's accounts starting with toComplete, described by institution
+func (app *CLIApp) accountNameCompletions(cmd *cobra.Command, toComplete string) []string {
+	cfg, userID := app.completionConfig(cmd)
+	if cfg == nil {
+		return nil
+	}
+
+	accounts, err := cfg.Db.GetAllAccounts(context.Background(), userID)
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, acc := range accounts {
+		names := []string{acc.Name}
+		if display := tables.AccountDisplayName(acc); display != acc.Name {
+			names = append([]string{display}, names...)
+		}
+		for _, name := range names {
+			if resolve.HasPrefixFold(name, toComplete) {
+				completions = append(completions, fmt.Sprintf("%s\t%s", name, acc.InstitutionName.String))
+			}
+		}
+	}
+
+	return completions
+}
+
+// Names of the logged in user's items starting with toComplete, described by institution. Items are cached
+// whenever a command looks one up on the server, so completion doesn't have to contact it
+func (app *CLIApp) itemNameCompletions(cmd *cobra.Command, toComplete string) []string {
+	cfg, userID := app.completionConfig(cmd)
+	if cfg == nil {
+		return nil
+	}
+
+	items, err := cfg.Db.GetItems(context.Background(), userID)
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, item := range items {
+		if resolve.HasPrefixFold(item.Nickname, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t%s", item.Nickname, item.InstitutionName))
+		}
+	}
+
+	return completions
+}
+
+// Configuration and logged in user to complete arguments for, without unlocking credentials. Completion
+// can run before the root command loads configuration, and must not exit on errors, so the profile given
+// on the command line is loaded here if needed. Returns a nil config if completion isn't possible
+func (app *CLIApp) completionConfig(cmd *cobra.Command) (*config.Config, string) {
+	cfg := app.Config
+	profile, _ := cmd.Flags().GetString("profile")
+	if cfg == nil || profile != "" {
+		loaded, err := config.LoadConfig(profile)
+		if err != nil {
+			return nil, ""
+		}
+		cfg = loaded
+	}
+
+	userID, err := auth.CurrentUserID(cfg.ConfigFP)
+	if err != nil || userID == "" {
+		return nil, ""
+	}
+
+	return cfg, userID
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jms-guy/greed/cli/internal/output"
+	"github.com/jms-guy/greed/cli/internal/payoff"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/jms-guy/greed/client"
+	"github.com/jms-guy/greed/models"
+	"github.com/spf13/cobra"
+)
+
+// Debts and their payoff plans, for JSON output
+type debtsOutput struct {
+	Debts []models.Liability `json:"debts"`
+	Plans []payoff.Plan      `json:"plans"`
+}
+
+// Shows the user's credit card and loan balances with their rates and payments, followed by payoff
+// schedules simulated with the avalanche and snowball strategies. With --sync, each item with debts
+// is synced with Plaid first
+func (app *CLIApp) commandDebts(cmd *cobra.Command, args []string) error {
+	sync, _ := cmd.Flags().GetBool("sync")
+	extra, _ := cmd.Flags().GetFloat64("extra")
+	strategy, _ := cmd.Flags().GetString("strategy")
+	ctx := context.Background()
+
+	if extra < 0 {
+		LogError(app.Config.Db, cmd, fmt.Errorf("extra payment must not be negative, got %.2f", extra), "Invalid flag")
+		return nil
+	}
+	strategies, err := payoff.ParseStrategies(strategy)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Invalid flag")
+		return nil
+	}
+
+	accounts, err := app.Config.Client.GetAccounts(ctx)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	names := map[string]string{}
+	items := []string{}
+	for _, acc := range accounts {
+		if acc.Type != "credit" && acc.Type != "loan" {
+			continue
+		}
+		names[acc.Id] = acc.DisplayName
+		if !slices.Contains(items, acc.ItemId) {
+			items = append(items, acc.ItemId)
+		}
+	}
+
+	if sync {
+		for _, itemID := range items {
+			result, err := app.Config.Client.SyncLiabilities(ctx, itemID)
+			if apiErr, ok := client.AsAPIError(err); ok && apiErr.Code == models.ErrCodeLiabilitiesUnavailable {
+				if !app.Output.IsMachine() {
+					fmt.Println(" < No liability data available for an item, run `greed update <item-name>` to grant access > ")
+				}
+				continue
+			}
+			if err != nil {
+				LogError(app.Config.Db, cmd, err, "Error syncing liability data")
+				return err
+			}
+			if !app.Output.IsMachine() {
+				fmt.Printf(" > Synced %d credit cards, %d student loans and %d mortgages\n", result.Credit, result.Student, result.Mortgage)
+			}
+		}
+	}
+
+	liabilities := []models.Liability{}
+	debts := []payoff.Debt{}
+	for _, acc := range accounts {
+		if _, ok := names[acc.Id]; !ok {
+			continue
+		}
+
+		liability, err := app.Config.Client.GetLiability(ctx, acc.Id)
+		if apiErr, ok := client.AsAPIError(err); ok && apiErr.Code == models.ErrCodeNotFound {
+			continue
+		}
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+		liabilities = append(liabilities, liability)
+
+		debt, err := liabilityToDebt(acc.DisplayName, liability)
+		if err != nil {
+			LogError(app.Config.Db, cmd, fmt.Errorf("error converting string value: %w", err), "Data error")
+			return err
+		}
+		debts = append(debts, debt)
+	}
+
+	plans := []payoff.Plan{}
+	for _, s := range strategies {
+		plans = append(plans, payoff.Simulate(debts, extra, s))
+	}
+
+	if app.Output == output.JSON {
+		return app.writeOutput(cmd, debtsOutput{Debts: liabilities, Plans: plans})
+	}
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, liabilities)
+	}
+
+	if len(liabilities) == 0 {
+		fmt.Println(" < No debt data found, sync your credit and loan accounts with `greed debts --sync` > ")
+		return nil
+	}
+
+	fmt.Printf(" < Debts > \n\n")
+	tables.MakeTableForDebts(liabilities, names).Print()
+	fmt.Println("")
+
+	start := time.Now()
+	for _, plan := range plans {
+		fmt.Printf(" < %s: %.2f a month > \n\n", strategyTitle(plan.Strategy), plan.MonthlyBudget)
+		tables.MakeTableForPayoffPlan(plan, start).Print()
+		fmt.Println("")
+
+		if !plan.Complete {
+			fmt.Printf(" < Payments don't cover interest, some debts would not be paid off within %d years. Raise the budget with --extra > \n\n", payoff.MaxMonths/12)
+			continue
+		}
+		fmt.Printf(" > Debt free in %d months, by %s, paying %.2f in interest\n\n", plan.Months, start.AddDate(0, plan.Months, 0).Format("Jan 2006"), plan.TotalInterest)
+	}
+
+	return nil
+}
+
+// Converts a liability into a debt for payoff simulation. Missing rates and payments count as zero
+func liabilityToDebt(name string, liability models.Liability) (payoff.Debt, error) {
+	debt := payoff.Debt{ID: liability.AccountID, Name: name}
+
+	values := []struct {
+		amount string
+		dest   *float64
+	}{
+		{liability.Balance, &debt.Balance},
+		{liability.InterestRate, &debt.APR},
+		{liability.MinimumPayment, &debt.MinimumPayment},
+	}
+	for _, v := range values {
+		if v.amount == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(v.amount, 64)
+		if err != nil {
+			return payoff.Debt{}, err
+		}
+		*v.dest = parsed
+	}
+
+	return debt, nil
+}
+
+func strategyTitle(strategy payoff.Strategy) string {
+	switch strategy {
+	case payoff.Snowball:
+		return "Snowball, smallest balance first"
+	default:
+		return "Avalanche, highest rate first"
+	}
+}
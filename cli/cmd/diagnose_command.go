@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/config"
+	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/diagnose"
+	"github.com/spf13/cobra"
+)
+
+// Number of the most recent error logs included in a diagnostics bundle
+const diagnoseLogLimit = 200
+
+// Environment and configuration summary written into diagnostics bundles
+type diagnoseInfo struct {
+	CLIVersion        string `json:"cli_version"`
+	GoVersion         string `json:"go_version"`
+	OS                string `json:"os"`
+	Arch              string `json:"arch"`
+	Profile           string `json:"profile"`
+	ServerURL         string `json:"server_url"`
+	ServerReachable   bool   `json:"server_reachable"`
+	ServerError       string `json:"server_error,omitempty"`
+	CredentialStore   string `json:"credential_store"`
+	LoggedIn          bool   `json:"logged_in"`
+	DefaultItemSet    bool   `json:"default_item_set"`
+	DefaultAccountSet bool   `json:"default_account_set"`
+	GeneratedAt       string `json:"generated_at"`
+}
+
+// Writes a redacted diagnostics bundle of error logs, config and version info, for attaching to bug reports
+func (app *CLIApp) commandDiagnose(cmd *cobra.Command) error {
+	out, _ := cmd.Flags().GetString("out")
+	now := time.Now().UTC()
+	if out == "" {
+		out = fmt.Sprintf("greed-diagnose-%s.tar.gz", now.Format("20060102T150405Z"))
+	}
+
+	info := diagnoseInfo{
+		CLIVersion:        cliVersion(),
+		GoVersion:         runtime.Version(),
+		OS:                runtime.GOOS,
+		Arch:              runtime.GOARCH,
+		Profile:           app.Config.Profile.Name,
+		ServerURL:         app.Config.Profile.URL,
+		DefaultItemSet:    app.Config.Settings.DefaultItem.Nickname != "",
+		DefaultAccountSet: app.Config.Settings.DefaultAccount.ID != "",
+		GeneratedAt:       now.Format(time.RFC3339),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := app.Config.Client.Health(ctx); err != nil {
+		info.ServerError = err.Error()
+	} else {
+		info.ServerReachable = true
+	}
+
+	if store, err := auth.CurrentStore(app.Config.ConfigFP); err == nil {
+		info.CredentialStore = store
+	} else {
+		info.CredentialStore = err.Error()
+	}
+	if userID, err := auth.CurrentUserID(app.Config.ConfigFP); err == nil {
+		info.LoggedIn = userID != ""
+	}
+
+	logs, err := app.Config.Db.ListErrorLogs(context.Background(), database.ListErrorLogsParams{
+		Since:   time.Time{}.Format(time.RFC3339),
+		Command: "",
+		Pattern: "",
+		Limit:   diagnoseLogLimit,
+	})
+	if err != nil {
+		LogError(app.Config.Db, cmd, fmt.Errorf("error getting log records: %w", err), "Local database error")
+		return err
+	}
+	entries := make([]errorLogEntry, 0, len(logs))
+	for _, l := range logs {
+		entries = append(entries, errorLogToEntry(l))
+	}
+
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error loading profiles")
+		return err
+	}
+
+	files := map[string]any{
+		"info.json":     info,
+		"logs.json":     entries,
+		"profiles.json": profiles.List(),
+	}
+
+	contents := make(map[string][]byte, len(files))
+	for name, data := range files {
+		encoded, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			LogError(app.Config.Db, cmd, fmt.Errorf("error encoding %s: %w", name, err), "Error building diagnostics bundle")
+			return err
+		}
+		contents[name] = encoded
+	}
+
+	if err := diagnose.WriteBundle(out, contents); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error writing diagnostics bundle")
+		return err
+	}
+
+	fmt.Printf("Diagnostics bundle written to %s\n", out)
+	fmt.Println(" < Credentials are never included, and tokens and emails are redacted. Please review the bundle before sharing it > ")
+	return nil
+}
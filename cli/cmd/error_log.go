@@ -3,50 +3,218 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/output"
+	"github.com/jms-guy/greed/client"
+	"github.com/jms-guy/greed/models"
 	"github.com/spf13/cobra"
 )
 
+// Error logs older than this, or beyond the newest errorLogMaxEntries, are deleted as new errors are logged
+const (
+	errorLogRetention  = 90 * 24 * time.Hour
+	errorLogMaxEntries = 1000
+)
+
+// Error log record, for machine-readable output and diagnostics bundles
+type errorLogEntry struct {
+	Timestamp      string `json:"timestamp"`
+	Command        string `json:"command"`
+	CommandLine    string `json:"command_line"`
+	Message        string `json:"message"`
+	Error          string `json:"error"`
+	HTTPStatus     int64  `json:"http_status,omitempty"`
+	RequestID      string `json:"request_id,omitempty"`
+	PlaidRequestID string `json:"plaid_request_id,omitempty"`
+	CLIVersion     string `json:"cli_version"`
+}
+
 // Logs an error message in client database, to be accessible for viewing by user later
 func LogError(db *database.Queries, cmd *cobra.Command, err error, userMsg string) {
 	fmt.Println(userMsg)
+	if hint := errorHint(err); hint != "" {
+		fmt.Printf(" < %s > \n", hint)
+	}
 
 	timestamp := time.Now().UTC().Truncate(time.Second)
-	command := cmd.Use
-	errMsg := err.Error()
 
 	params := database.CreateErrorLogParams{
 		Timestamp:    timestamp.Format(time.RFC3339),
-		Command:      command,
-		ErrorMessage: errMsg,
+		Command:      cmd.Use,
+		ErrorMessage: err.Error(),
+		CommandLine:  "greed " + strings.Join(os.Args[1:], " "),
+		UserMessage:  userMsg,
+		CliVersion:   cliVersion(),
+	}
+	if apiErr, ok := client.AsAPIError(err); ok {
+		params.HttpStatus = int64(apiErr.StatusCode)
+		params.RequestID = apiErr.RequestID
+		params.PlaidRequestID = apiErr.PlaidRequestID
 	}
 
 	dbErr := db.CreateErrorLog(context.Background(), params)
 
 	if dbErr != nil {
 		fmt.Println("Local database encountered error while logging error")
+		return
 	}
+
+	pruneErrorLogs(db, timestamp)
+}
+
+// Deletes error logs past the retention period, and the oldest logs beyond the maximum kept
+func pruneErrorLogs(db *database.Queries, now time.Time) {
+	ctx := context.Background()
+	_ = db.DeleteErrorLogsBefore(ctx, now.Add(-errorLogRetention).Format(time.RFC3339))
+	_ = db.TrimErrorLogs(ctx, errorLogMaxEntries)
 }
 
-// Function prints the latest 5 commands encounted on machine and logged in database
+// Function prints the errors logged on machine, newest first, filtered by the command's flags
 func (app *CLIApp) commandReadLogs(cmd *cobra.Command) error {
-	logs, err := app.Config.Db.GetLogs(context.Background())
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	commandFlag, _ := cmd.Flags().GetString("command")
+	grepFlag, _ := cmd.Flags().GetString("grep")
+	limit, _ := cmd.Flags().GetInt64("limit")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	since := time.Time{}
+	if sinceFlag != "" {
+		var err error
+		since, err = parseSince(sinceFlag, time.Now().UTC())
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Invalid --since value")
+			return err
+		}
+	}
+
+	logs, err := app.Config.Db.ListErrorLogs(context.Background(), database.ListErrorLogsParams{
+		Since:   since.UTC().Format(time.RFC3339),
+		Command: commandFlag,
+		Pattern: grepFlag,
+		Limit:   limit,
+	})
 	if err != nil {
 		LogError(app.Config.Db, cmd, fmt.Errorf("error getting log records: %w", err), "Local database error")
 		return err
 	}
 
-	if len(logs) == 0 {
+	entries := make([]errorLogEntry, 0, len(logs))
+	for _, l := range logs {
+		entries = append(entries, errorLogToEntry(l))
+	}
+
+	if asJSON {
+		return output.Write(os.Stdout, output.JSON, entries)
+	}
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, entries)
+	}
+
+	if len(entries) == 0 {
 		fmt.Println("No error logs found.")
 		return nil
 	}
 
-	fmt.Println("Last 5 errors logged by machine:")
-	for _, log := range logs {
-		fmt.Printf("Time: %s Command: %s Message: %s\n", log.Timestamp, log.Command, log.ErrorMessage)
+	fmt.Printf("Errors logged by machine, newest first (%d shown):\n", len(entries))
+	for _, e := range entries {
+		fmt.Printf("[%s] %s\n", e.Timestamp, e.CommandLine)
+		fmt.Printf("    %s: %s\n", e.Message, e.Error)
+
+		details := []string{}
+		if e.HTTPStatus != 0 {
+			details = append(details, fmt.Sprintf("status %d", e.HTTPStatus))
+		}
+		if e.RequestID != "" {
+			details = append(details, "request id "+e.RequestID)
+		}
+		if e.PlaidRequestID != "" {
+			details = append(details, "plaid request id "+e.PlaidRequestID)
+		}
+		if e.CLIVersion != "" {
+			details = append(details, "cli "+e.CLIVersion)
+		}
+		if len(details) != 0 {
+			fmt.Printf("    %s\n", strings.Join(details, " | "))
+		}
 	}
 
 	return nil
 }
+
+// Converts a local error log record, filling in the command line for records logged before it was stored
+func errorLogToEntry(l database.ErrorLog) errorLogEntry {
+	commandLine := l.CommandLine
+	if commandLine == "" {
+		commandLine = l.Command
+	}
+
+	return errorLogEntry{
+		Timestamp:      l.Timestamp,
+		Command:        l.Command,
+		CommandLine:    commandLine,
+		Message:        l.UserMessage,
+		Error:          l.ErrorMessage,
+		HTTPStatus:     l.HttpStatus,
+		RequestID:      l.RequestID,
+		PlaidRequestID: l.PlaidRequestID,
+		CLIVersion:     l.CliVersion,
+	}
+}
+
+// Parses a --since value: a duration back from now (ex. 90m, 24h, 7d), or a date (2006-01-02) or timestamp (RFC 3339)
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid since value %q, expected a duration (24h, 7d), a date (2006-01-02) or a timestamp (RFC 3339)", value)
+}
+
+// Returns an actionable message for errors the server reports with a known error code
+func errorHint(err error) string {
+	apiErr, ok := client.AsAPIError(err)
+	if !ok {
+		return ""
+	}
+
+	switch apiErr.Code {
+	case models.ErrCodeItemLoginRequired:
+		return "Your bank requires you to log in again, run 'greed update <item-name>' to re-authenticate the item"
+	case models.ErrCodeInvestmentsUnavailable:
+		return "No investment data is available for this item, run 'greed update <item-name>' to grant investments access"
+	case models.ErrCodeLiabilitiesUnavailable:
+		return "No liability data is available for this item, run 'greed update <item-name>' to grant liabilities access"
+	case models.ErrCodeFreeCallsExhausted:
+		return "You have used all free calls for this command, a membership is required to continue"
+	case models.ErrCodePlaidRateLimited:
+		return "Plaid is rate limiting requests for this item, please wait a few minutes and try again"
+	case models.ErrCodeRateLimited:
+		return "Too many requests sent to the server, please wait a moment and try again"
+	case models.ErrCodeTokenExpired:
+		return "Your session has expired, run 'greed login <name>' to log in again"
+	case models.ErrCodeNotManualAccount:
+		return "This account is linked through your bank, transactions can only be entered by hand on accounts made with 'greed account create --manual'"
+	case models.ErrCodePayloadTooLarge:
+		return "The file is larger than the server allows, try a smaller or compressed copy"
+	case models.ErrCodeUnsupportedMediaType:
+		return "Only images (JPEG, PNG, GIF, WebP), PDFs and plain text files can be attached"
+	}
+
+	return ""
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/jms-guy/greed/models"
+	"github.com/spf13/cobra"
+)
+
+// Lists the user's events after a sequence number, oldest first
+func (app *CLIApp) commandListEvents(cmd *cobra.Command, after int64, eventType string, limit int) error {
+	events, err := app.Config.Client.GetEvents(context.Background(), after, eventType, limit)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, events)
+	}
+
+	if len(events) == 0 {
+		fmt.Println(" < No events > ")
+		return nil
+	}
+
+	tables.MakeEventsTable(events).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Registers an endpoint to receive events, printing its signing secret once
+func (app *CLIApp) commandSubscribeEvents(cmd *cobra.Command, url string, eventTypes []string) error {
+	created, err := app.Config.Client.CreateEventEndpoint(context.Background(), models.CreateEventEndpoint{
+		URL:        url,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, created)
+	}
+
+	fmt.Printf(" < Endpoint %s created > \n", created.ID.String()[:8])
+	fmt.Println("")
+	fmt.Println("Endpoint signing secret, it will not be shown again:")
+	fmt.Printf("  %s\n", created.Secret)
+	fmt.Println("Deliveries carry an X-Greed-Signature header of sha256=<hex HMAC-SHA256 of \"<X-Greed-Timestamp>.<body>\">")
+
+	return nil
+}
+
+// Lists the user's event endpoints
+func (app *CLIApp) commandListEventEndpoints(cmd *cobra.Command) error {
+	endpoints, err := app.Config.Client.GetEventEndpoints(context.Background())
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, endpoints)
+	}
+
+	if len(endpoints) == 0 {
+		fmt.Println(" < No event endpoints > ")
+		return nil
+	}
+
+	tables.MakeEventEndpointsTable(endpoints).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Deletes event endpoints by ID, or unique prefix of an ID
+func (app *CLIApp) commandUnsubscribeEvents(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	ids, err := app.eventEndpointIDs(ctx)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	for _, arg := range args {
+		id, err := resolveIDPrefix(ids, arg, "event endpoint")
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, err.Error())
+			return nil
+		}
+
+		if err := app.Config.Client.DeleteEventEndpoint(ctx, id); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+		fmt.Printf(" < Endpoint %s removed > \n", id.String()[:8])
+	}
+
+	return nil
+}
+
+// Lists recent deliveries to an event endpoint, with their status
+func (app *CLIApp) commandEventDeliveries(cmd *cobra.Command, endpoint string, limit int) error {
+	ctx := context.Background()
+
+	ids, err := app.eventEndpointIDs(ctx)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	id, err := resolveIDPrefix(ids, endpoint, "event endpoint")
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, err.Error())
+		return nil
+	}
+
+	deliveries, err := app.Config.Client.GetEventDeliveries(ctx, id, limit)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, deliveries)
+	}
+
+	if len(deliveries) == 0 {
+		fmt.Println(" < No events sent > ")
+		return nil
+	}
+
+	tables.MakeEventDeliveriesTable(deliveries).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Queues events recorded in a time window to be sent to an endpoint again. The window ends now
+// when until is empty
+func (app *CLIApp) commandReplayEvents(cmd *cobra.Command, endpoint, since, until string) error {
+	ctx := context.Background()
+	now := time.Now()
+
+	replay := models.ReplayEvents{}
+	start, err := parseSince(since, now)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, err.Error())
+		return nil
+	}
+	replay.Since = start
+
+	if until != "" {
+		end, err := parseSince(until, now)
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, err.Error())
+			return nil
+		}
+		replay.Until = &end
+	}
+
+	ids, err := app.eventEndpointIDs(ctx)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	id, err := resolveIDPrefix(ids, endpoint, "event endpoint")
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, err.Error())
+		return nil
+	}
+
+	message, err := app.Config.Client.ReplayEvents(ctx, id, replay)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+	fmt.Printf(" < %s > \n", message)
+
+	return nil
+}
+
+func (app *CLIApp) eventEndpointIDs(ctx context.Context) ([]uuid.UUID, error) {
+	endpoints, err := app.Config.Client.GetEventEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(endpoints))
+	for _, e := range endpoints {
+		ids = append(ids, e.ID)
+	}
+	return ids, nil
+}
@@ -7,10 +7,15 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/jms-guy/greed/cli/internal/auth"
 	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/tables"
 	"github.com/jms-guy/greed/cli/internal/utils"
+	"github.com/jms-guy/greed/models"
 	"github.com/spf13/cobra"
 )
 
@@ -33,13 +38,9 @@ func (app *CLIApp) commandExportData(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		params := database.GetAccountParams{
-			Name:   accountName,
-			UserID: creds.User.ID.String(),
-		}
-		account, err = app.Config.Db.GetAccount(context.Background(), params)
+		account, err = getLocalAccount(app, creds.User.ID.String(), accountName)
 		if err != nil {
-			LogError(app.Config.Db, cmd, fmt.Errorf("error getting local account: %w", err), "Local database error")
+			LogError(app.Config.Db, cmd, err, "Error finding account")
 			return err
 		}
 
@@ -56,11 +57,11 @@ func (app *CLIApp) commandExportData(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(txns) == 0 {
-		fmt.Printf("No transaction records found for account %s\n", account.Name)
+		fmt.Printf("No transaction records found for account %s\n", tables.AccountDisplayName(account))
 		return nil
 	}
 
-	filename := fmt.Sprintf("%s.csv", account.Name)
+	filename := fmt.Sprintf("%s.csv", exportFileName(tables.AccountDisplayName(account)))
 	exportFile := filepath.Join(exportDirectory, filename)
 
 	err = os.MkdirAll(exportDirectory, 0o750)
@@ -103,6 +104,62 @@ func (app *CLIApp) commandExportData(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Exports a set of transactions, such as those shown in the transactions pager, into a timestamped .csv file in the export directory
+func (app *CLIApp) exportTransactions(accountName string, txns []models.Transaction) (string, error) {
+	exportDirectory := app.getExportDirectory()
+	if err := os.MkdirAll(exportDirectory, 0o750); err != nil {
+		return "", fmt.Errorf("error making directory: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s-%s.csv", exportFileName(accountName), time.Now().Format("20060102-150405"))
+	exportFile := filepath.Join(exportDirectory, filename)
+
+	// #nosec G304 - file variables are controlled, no user input
+	file, err := os.Create(exportFile)
+	if err != nil {
+		return "", fmt.Errorf("error creating export file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+
+	headers := []string{"Amount", "CurrencyCode", "Date", "Merchant", "Payment Channel", "Category"}
+	if err := writer.Write(headers); err != nil {
+		return "", fmt.Errorf("error writing csv headers: %w", err)
+	}
+
+	for _, txn := range txns {
+		toWrite := []string{txn.Amount, txn.IsoCurrencyCode, txn.Date.Format("2006-01-02"), txn.MerchantName, txn.PaymentChannel, txn.PersonalFinanceCategory}
+		if err := writer.Write(toWrite); err != nil {
+			return "", fmt.Errorf("error writing csv line: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("error writing export file: %w", err)
+	}
+
+	return exportFile, nil
+}
+
+// Turns an account name into a file name for its export. Path separators, characters Windows doesn't allow
+// in file names and control characters are replaced, and leading and trailing dots and spaces are dropped,
+// so the export is always written inside the export directory
+func exportFileName(name string) string {
+	clean := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	clean = strings.Trim(clean, ". ")
+	if clean == "" {
+		return "account"
+	}
+	return clean
+}
+
 // Gets the base export directory to send exported .csv files to. Directory is based on operating system
 func (app *CLIApp) getExportDirectory() string {
 	var baseDir string
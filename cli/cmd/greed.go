@@ -4,6 +4,7 @@ import (
 	"math"
 	"strings"
 
+	"github.com/jms-guy/greed/cli/internal/auth"
 	"github.com/spf13/cobra"
 )
 
@@ -82,9 +83,10 @@ func (app *CLIApp) deleteCmd() *cobra.Command {
 */
 func (app *CLIApp) deleteItemCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "item <item-name>",
-		Short: "Delete an item",
-		Args:  cobra.ExactArgs(1),
+		Use:               "item <item-name>",
+		Short:             "Delete an item",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: app.completeItemNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandDeleteItem(cmd, args)
 		},
@@ -105,16 +107,20 @@ func (app *CLIApp) verifyCmd() *cobra.Command {
 }
 
 func (app *CLIApp) itemsCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "items",
+	cmd := &cobra.Command{
+		Use:     "items [--fix]",
 		Aliases: []string{"Items", "ITEMS"},
 		Short:   "Lists a user's item records",
-		Long:    "Lists a user's item records. Items are financial institution connections, with each institution being one item",
+		Long:    "Lists a user's item records with the health of each. Items are financial institution connections, with each institution being one item. Use --fix to log in again to every item that needs re-authentication or whose consent is running out",
 		Args:    cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandUserItems(cmd)
 		},
 	}
+
+	cmd.Flags().Bool("fix", false, "Re-authenticate items that need it, syncing each afterwards")
+
+	return cmd
 }
 
 func (app *CLIApp) changepwCmd() *cobra.Command {
@@ -143,11 +149,12 @@ func (app *CLIApp) resetpwCmd() *cobra.Command {
 
 func (app *CLIApp) fetchCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "fetch <item-name>",
-		Aliases: []string{"Fetch", "FETCH"},
-		Short:   "Fetchs account and transaction data for an item",
-		Long:    "Retrieves all account and transaction data for item from third party, populating database. Should only be used on a new item, afterwards use sync command",
-		Args:    cobra.ExactArgs(1),
+		Use:               "fetch <item-name>",
+		Aliases:           []string{"Fetch", "FETCH"},
+		Short:             "Fetchs account and transaction data for an item",
+		Long:              "Retrieves all account and transaction data for item from third party, populating database. Should only be used on a new item, afterwards use sync command",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: app.completeItemNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			err := app.commandGetAccounts(cmd, args)
 			if err != nil {
@@ -160,23 +167,32 @@ func (app *CLIApp) fetchCmd() *cobra.Command {
 }
 
 func (app *CLIApp) syncCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "sync <item-name>",
-		Aliases: []string{"Sync", "SYNC"},
-		Short:   "Updates account and transaction data for an item, providing real-time data",
-		Args:    cobra.ExactArgs(1),
+	cmd := &cobra.Command{
+		Use:               "sync [item-name] [--all]",
+		Aliases:           []string{"Sync", "SYNC"},
+		Short:             "Updates account and transaction data for an item, providing real-time data",
+		Long:              "Updates account balances and transactions for an item. Only the transactions added, modified or removed since the last sync are downloaded and applied to the local database. Use --full to rebuild local transaction records from scratch instead. Use --all to sync every item at once, showing each item's progress and a summary of the transactions changed",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: app.completeItemNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandSync(cmd, args)
 		},
 	}
+
+	cmd.Flags().Bool("full", false, "Rebuild local transaction records from the server, instead of applying changes since the last sync")
+	cmd.Flags().Bool("all", false, "Sync every item")
+	cmd.Flags().Int("workers", 4, "Number of items synced at once with --all")
+
+	return cmd
 }
 
 func (app *CLIApp) updateCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "update <item-name>",
-		Aliases: []string{"Update", "UPDATE"},
-		Short:   "Re-authenticates user's financial institution through Plaid",
-		Args:    cobra.ExactArgs(1),
+		Use:               "update <item-name>",
+		Aliases:           []string{"Update", "UPDATE"},
+		Short:             "Re-authenticates user's financial institution through Plaid",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: app.completeItemNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandUpdate(cmd, args)
 		},
@@ -185,10 +201,11 @@ func (app *CLIApp) updateCmd() *cobra.Command {
 
 func (app *CLIApp) renameCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "rename <current-item-name> <new-item-name>",
-		Aliases: []string{"Rename", "RENAME"},
-		Short:   "Rename an item",
-		Args:    cobra.ExactArgs(2),
+		Use:               "rename <current-item-name> <new-item-name>",
+		Aliases:           []string{"Rename", "RENAME"},
+		Short:             "Rename an item",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: app.completeItemNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandRenameItem(cmd, args)
 		},
@@ -197,16 +214,274 @@ func (app *CLIApp) renameCmd() *cobra.Command {
 
 func (app *CLIApp) infoCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "info <account-name>",
-		Aliases: []string{"Info", "INFO"},
-		Short:   "Lists extended information for a given account",
-		Args:    cobra.RangeArgs(0, 1),
+		Use:               "info <account-name-or-nickname>",
+		Aliases:           []string{"Info", "INFO"},
+		Short:             "Lists extended information for a given account",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: app.completeAccountNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandAccountInfo(cmd, args)
 		},
 	}
 }
 
+func (app *CLIApp) accountCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "account",
+		Aliases: []string{"Account", "ACCOUNT"},
+		Short:   "Creates manual accounts, and changes how accounts are shown",
+		Long:    "Creates manual accounts, such as cash or a loan from a friend, whose transactions are entered by hand with the txn command. Gives accounts nicknames that can be used in place of their names in any command, hides accounts from lists, archives closed accounts without deleting their transactions, and sets which accounts count towards net worth and the order accounts are listed in. Changes are kept on the server, and reach other devices when they sync",
+	}
+}
+
+func (app *CLIApp) accountCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create <account-name> --manual",
+		Aliases: []string{"Create", "CREATE"},
+		Short:   "Creates a manual account, tracked by hand",
+		Long:    "Creates an account with no bank behind it, such as a cash wallet. Its balance starts at --balance, and follows the transactions added with 'greed txn add'. For credit and loan accounts the balance is the amount owed",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manual, _ := cmd.Flags().GetBool("manual")
+			accType, _ := cmd.Flags().GetString("type")
+			subtype, _ := cmd.Flags().GetString("subtype")
+			balance, _ := cmd.Flags().GetString("balance")
+			currency, _ := cmd.Flags().GetString("currency")
+			return app.commandCreateAccount(cmd, args[0], manual, accType, subtype, balance, currency)
+		},
+	}
+	cmd.Flags().Bool("manual", false, "Create a manual account, with transactions entered by hand")
+	cmd.Flags().String("type", "depository", "Account type: depository, credit, loan, investment or other")
+	cmd.Flags().String("subtype", "", "Account subtype, ex. cash")
+	cmd.Flags().String("balance", "0.00", "Opening balance, ex. 120.50")
+	cmd.Flags().String("currency", "", "Currency code, ex. USD")
+	return cmd
+}
+
+func (app *CLIApp) accountRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "rename <account-name> [nickname]",
+		Aliases:           []string{"Rename", "RENAME"},
+		Short:             "Gives an account a nickname, or removes it if none is given",
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: app.completeAccountNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandRenameAccount(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) accountHideCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "hide <account-name>",
+		Aliases:           []string{"Hide", "HIDE"},
+		Short:             "Hides an account from account lists",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: app.completeAccountNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			undo, _ := cmd.Flags().GetBool("undo")
+			return app.commandHideAccount(cmd, args, undo)
+		},
+	}
+	cmd.Flags().Bool("undo", false, "Show the account again")
+	return cmd
+}
+
+func (app *CLIApp) accountArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "archive <account-name>",
+		Aliases:           []string{"Archive", "ARCHIVE"},
+		Short:             "Archives an account, keeping its transactions",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: app.completeAccountNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			undo, _ := cmd.Flags().GetBool("undo")
+			return app.commandArchiveAccount(cmd, args, undo)
+		},
+	}
+	cmd.Flags().Bool("undo", false, "Restore the account")
+	return cmd
+}
+
+func (app *CLIApp) accountNetWorthCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "net-worth <account-name> <include|exclude>",
+		Aliases:           []string{"Net-Worth", "NET-WORTH"},
+		Short:             "Sets whether an account counts towards net worth",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: app.completeAccountNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandAccountNetWorth(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) accountOrderCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "order <account-name> <position>",
+		Aliases:           []string{"Order", "ORDER"},
+		Short:             "Sets where an account is listed, lower positions first",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: app.completeAccountNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandOrderAccount(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) txnCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "txn",
+		Aliases: []string{"Txn", "TXN"},
+		Short:   "Works with single transactions, their notes and attached receipts",
+		Long:    "Enters transactions by hand on accounts made with 'greed account create --manual'. Amounts are positive for money leaving the account and negative for money coming in, and the account's balance follows them. Any transaction can be given a note and have files, such as photos of receipts, attached to it. Transactions are named by their ID, or the start of it, as shown by 'greed get transactions <account-name> -o json'",
+	}
+}
+
+func (app *CLIApp) txnAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "add <account-name> <amount>",
+		Aliases:           []string{"Add", "ADD"},
+		Short:             "Adds a transaction to a manual account",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: app.completeAccountNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			date, _ := cmd.Flags().GetString("date")
+			merchant, _ := cmd.Flags().GetString("merchant")
+			category, _ := cmd.Flags().GetString("category")
+			channel, _ := cmd.Flags().GetString("channel")
+			return app.commandAddTransaction(cmd, args[0], args[1], date, merchant, category, channel)
+		},
+	}
+	cmd.Flags().String("date", "", "Date of the transaction, YYYY-MM-DD. Today by default")
+	cmd.Flags().String("merchant", "", "Merchant name")
+	cmd.Flags().String("category", "", "Category, ex. FOOD_AND_DRINK. OTHER by default")
+	cmd.Flags().String("channel", "", "Payment channel: online, in store or other. other by default")
+	return cmd
+}
+
+func (app *CLIApp) txnEditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "edit <account-name> <txn-id>",
+		Aliases:           []string{"Edit", "EDIT"},
+		Short:             "Changes a manual account's transaction",
+		Long:              "Changes the given fields of a manual account's transaction, keeping the rest. An empty --merchant removes the merchant",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: app.completeTransactionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandEditTransaction(cmd, args[0], args[1])
+		},
+	}
+	cmd.Flags().String("amount", "", "Amount, ex. 12.50")
+	cmd.Flags().String("date", "", "Date of the transaction, YYYY-MM-DD")
+	cmd.Flags().String("merchant", "", "Merchant name")
+	cmd.Flags().String("category", "", "Category, ex. FOOD_AND_DRINK")
+	cmd.Flags().String("channel", "", "Payment channel: online, in store or other")
+	return cmd
+}
+
+func (app *CLIApp) txnRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "rm <account-name> <txn-id>",
+		Aliases:           []string{"Rm", "RM", "remove"},
+		Short:             "Deletes a manual account's transaction",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: app.completeTransactionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandRemoveTransaction(cmd, args[0], args[1])
+		},
+	}
+}
+
+func (app *CLIApp) txnNoteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "note <account-name> <txn-id> [note]",
+		Aliases:           []string{"Note", "NOTE"},
+		Short:             "Shows or sets a transaction's note",
+		Long:              "Shows a transaction's note, or replaces it with the given note. An empty note, given as \"\", removes it",
+		Args:              cobra.RangeArgs(2, 3),
+		ValidArgsFunction: app.completeTransactionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var note *string
+			if len(args) == 3 {
+				note = &args[2]
+			}
+			return app.commandTransactionNote(cmd, args[0], args[1], note)
+		},
+	}
+}
+
+func (app *CLIApp) txnAttachCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "attach <account-name> <txn-id> <file>",
+		Aliases:           []string{"Attach", "ATTACH"},
+		Short:             "Attaches a file, such as a receipt, to a transaction",
+		Long:              "Uploads a file to the server and attaches it to a transaction. Images (JPEG, PNG, GIF, WebP), PDFs and plain text files can be attached, up to the server's size limit, 10 MB by default",
+		Args:              cobra.ExactArgs(3),
+		ValidArgsFunction: app.completeAttachmentFile,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandAttachFile(cmd, args[0], args[1], args[2])
+		},
+	}
+}
+
+func (app *CLIApp) txnAttachmentsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "attachments <account-name> <txn-id>",
+		Aliases:           []string{"Attachments", "ATTACHMENTS"},
+		Short:             "Lists the files attached to a transaction",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: app.completeTransactionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandListAttachments(cmd, args[0], args[1])
+		},
+	}
+}
+
+func (app *CLIApp) txnDownloadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "download <account-name> <txn-id> <attachment-id>",
+		Aliases:           []string{"Download", "DOWNLOAD"},
+		Short:             "Downloads a transaction's attachment",
+		Long:              "Downloads a file attached to a transaction, named by its ID or the start of it, as shown by 'greed txn attachments'. The file is saved to the attachments folder of the export directory unless --out is given, and is checked against the checksum recorded when it was uploaded",
+		Args:              cobra.ExactArgs(3),
+		ValidArgsFunction: app.completeTransactionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, _ := cmd.Flags().GetString("out")
+			return app.commandDownloadAttachment(cmd, args[0], args[1], args[2], out, false)
+		},
+	}
+	cmd.Flags().String("out", "", "File or directory to save the attachment to")
+	return cmd
+}
+
+func (app *CLIApp) txnOpenCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "open <account-name> <txn-id> <attachment-id>",
+		Aliases:           []string{"Open", "OPEN"},
+		Short:             "Downloads a transaction's attachment and opens it",
+		Long:              "Downloads a file attached to a transaction to the attachments folder of the export directory, and opens it with the system's default program",
+		Args:              cobra.ExactArgs(3),
+		ValidArgsFunction: app.completeTransactionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandDownloadAttachment(cmd, args[0], args[1], args[2], "", true)
+		},
+	}
+}
+
+func (app *CLIApp) txnDetachCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "detach <account-name> <txn-id> <attachment-id>",
+		Aliases:           []string{"Detach", "DETACH"},
+		Short:             "Deletes a transaction's attachment",
+		Args:              cobra.ExactArgs(3),
+		ValidArgsFunction: app.completeTransactionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandRemoveAttachment(cmd, args[0], args[1], args[2])
+		},
+	}
+}
+
 func (app *CLIApp) getCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:     "get",
@@ -217,29 +492,34 @@ func (app *CLIApp) getCmd() *cobra.Command {
 }
 
 func (app *CLIApp) getAccountsCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "accounts [item-name]",
-		Aliases: []string{"Accounts", "ACCOUNTS"},
-		Short:   "Returns a list of accounts",
-		Long:    "Returns a list of accounts. If an item name is specified, it will return accounts only for that item. Otherwise it will return all accounts for user",
-		Args:    cobra.RangeArgs(0, 1),
+	cmd := &cobra.Command{
+		Use:               "accounts [item-name]",
+		Aliases:           []string{"Accounts", "ACCOUNTS"},
+		Short:             "Returns a list of accounts",
+		Long:              "Returns a list of accounts. If an item name is specified, it will return accounts only for that item. Otherwise it will return all accounts for user, with their net worth. Hidden and archived accounts are left out unless --all is given",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: app.completeItemNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool("all")
 			if len(args) == 1 {
-				return app.commandListAccounts(cmd, args)
+				return app.commandListAccounts(cmd, args, all)
 			} else {
-				return app.commandListAllAccounts(cmd)
+				return app.commandListAllAccounts(cmd, all)
 			}
 		},
 	}
+	cmd.Flags().Bool("all", false, "Include hidden and archived accounts")
+	return cmd
 }
 
 func (app *CLIApp) getTransactionsCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "transactions <account-name>",
-		Aliases: []string{"Transactions", "TRANSACTIONS", "txns", "Txns", "TXNS"},
-		Short:   "Returns a list of transactions for a given account",
-		Long:    "Returns transactions for an account, takes many optional flags that are used to build a query string",
-		Args:    cobra.RangeArgs(0, 1),
+		Use:               "transactions <account-name>",
+		Aliases:           []string{"Transactions", "TRANSACTIONS", "txns", "Txns", "TXNS"},
+		Short:             "Returns a list of transactions for a given account",
+		Long:              "Returns transactions for an account, takes many optional flags that are used to build a query string",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: app.completeAccountNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			merchant, _ := cmd.Flags().GetString("merchant")
 			category, _ := cmd.Flags().GetString("category")
@@ -276,11 +556,12 @@ func (app *CLIApp) getTransactionsCmd() *cobra.Command {
 
 func (app *CLIApp) getIncomeDataCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "income <account-name>",
-		Aliases: []string{"Income", "INCOME", "inc", "INC"},
-		Short:   "Returns aggregate income/expenses data for account history",
-		Long:    "Returns aggregate income/expenses data for account history. Can display data in table, or chart mode. To display properly in graph mode, a terminal screen with a height:width of at least 50:210 is required, else the graph will distort.",
-		Args:    cobra.RangeArgs(0, 1),
+		Use:               "income <account-name>",
+		Aliases:           []string{"Income", "INCOME", "inc", "INC"},
+		Short:             "Returns aggregate income/expenses data for account history",
+		Long:              "Returns aggregate income/expenses data for account history. Can display data in table, or chart mode. To display properly in graph mode, a terminal screen with a height:width of at least 50:210 is required, else the graph will distort.",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: app.completeAccountNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			mode, _ := cmd.Flags().GetString("mode")
 
@@ -295,38 +576,555 @@ func (app *CLIApp) getIncomeDataCmd() *cobra.Command {
 
 func (app *CLIApp) exportDataCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "export <account-name>",
-		Aliases: []string{"Export", "EXPORT"},
-		Short:   "Export an account's transaction data into a .csv file",
-		Args:    cobra.ExactArgs(1),
+		Use:               "export <account-name>",
+		Aliases:           []string{"Export", "EXPORT"},
+		Short:             "Export an account's transaction data into a .csv file",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: app.completeAccountNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandExportData(cmd, args)
 		},
 	}
 }
 
-func (app *CLIApp) addItemCmd() *cobra.Command {
+func (app *CLIApp) compareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "compare <account-name> --period <YYYY-MM> [--against <YYYY-MM>]",
+		Aliases:           []string{"Compare", "COMPARE", "cmp"},
+		Short:             "Compares spending between two periods, by category and merchant",
+		Long:              "Compares an account's spending in one period against another, by category and by merchant. Periods are months (2025-03) or years (2025), --against defaulting to the same period a year earlier. Arbitrary date ranges can be compared with --start, --end, --against-start and --against-end instead. Increases in spending are shown in red, decreases in green. Use --all to compare across all of your accounts",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: app.completeAccountNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandCompare(cmd, args)
+		},
+	}
+
+	cmd.Flags().String("period", "", "Month or year to report on [YYYY-MM | YYYY]")
+	cmd.Flags().String("against", "", "Month or year to compare against, defaults to the same period a year earlier")
+	cmd.Flags().String("start", "", "First date of the period to report on [YYYY-MM-DD]")
+	cmd.Flags().String("end", "", "Last date of the period to report on [YYYY-MM-DD]")
+	cmd.Flags().String("against-start", "", "First date of the period to compare against [YYYY-MM-DD]")
+	cmd.Flags().String("against-end", "", "Last date of the period to compare against [YYYY-MM-DD]")
+	cmd.Flags().String("by", "both", "Breakdown to show [category | merchant | both]")
+	cmd.Flags().Int("limit", 15, "Number of rows to show in each table, largest changes first. 0 shows all")
+	cmd.Flags().Bool("all", false, "Compare spending across all accounts")
+	cmd.MarkFlagsMutuallyExclusive("period", "start")
+
+	return cmd
+}
+
+func (app *CLIApp) alertsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "alerts",
+		Aliases: []string{"Alerts", "ALERTS", "anomalies"},
+		Short:   "Lists unusual transactions flagged after syncs",
+		Long:    "Lists transactions the server flagged as unusual when syncing: amounts far above what is usual for the merchant or category, first charges from a merchant, repeated charges of the same amount within a few days, and charges in a foreign currency. Acknowledge alerts with `alerts ack` to hide them",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool("all")
+			return app.commandListAlerts(cmd, all)
+		},
+	}
+	cmd.Flags().Bool("all", false, "Include acknowledged alerts")
+	return cmd
+}
+
+func (app *CLIApp) alertsAckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "ack <alert-id>...",
+		Aliases: []string{"Ack", "ACK", "acknowledge"},
+		Short:   "Acknowledges alerts, by the ID shown in `alerts`",
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, _ := cmd.Flags().GetBool("all")
+			return app.commandAckAlerts(cmd, args, all)
+		},
+	}
+	cmd.Flags().Bool("all", false, "Acknowledge all alerts")
+	return cmd
+}
+
+func (app *CLIApp) merchantsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "merchants",
+		Aliases: []string{"Merchants", "MERCHANTS"},
+		Short:   "Lists the merchants transactions are summarized under",
+		Long:    "Lists the merchants your transactions are summarized and filtered under, with the names banks reported for each. The server cleans up reported names, so \"AMZN Mktp CA*2K3L45\" and \"Amazon.ca\" are both counted under Amazon, and falls back to a transaction's own name when the bank reports no merchant. Use `merchants alias` to count other names under a merchant of your choosing",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandListMerchants(cmd)
+		},
+	}
+}
+
+func (app *CLIApp) merchantsAliasesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "aliases",
+		Aliases: []string{"Aliases", "ALIASES"},
+		Short:   "Lists merchant aliases",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandListMerchantAliases(cmd)
+		},
+	}
+}
+
+func (app *CLIApp) merchantsAliasCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "alias <raw-name> <merchant>",
+		Aliases: []string{"Alias", "ALIAS"},
+		Short:   "Counts transactions with a reported name under a merchant",
+		Long:    "Counts transactions the bank reported with the given name under a merchant of your choosing. The alias also applies to names that clean up the same, ignoring case, so an alias for \"SQ *CORNER CAFE\" covers \"Corner Cafe #12\". Adding an alias for a name that already has one replaces it",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandAddMerchantAlias(cmd, args[0], args[1])
+		},
+	}
+}
+
+func (app *CLIApp) merchantsUnaliasCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "unalias <alias-id>...",
+		Aliases: []string{"Unalias", "UNALIAS"},
+		Short:   "Removes merchant aliases, by the ID shown in `merchants aliases`",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandRemoveMerchantAliases(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) notifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "notify",
+		Aliases: []string{"Notify", "NOTIFY", "notifications"},
+		Short:   "Manages notification rules, delivered by email or webhook",
+		Long:    "Notification rules are checked by the server as your data changes, and delivered by email or to a webhook of your own. Rules can watch for transactions over an amount (large_transaction), balances under an amount (low_balance), connections needing you to log in again (item_reauth), monthly spending in a category over a budget (budget_exceeded), and new recurring charges (new_recurring)",
+	}
+}
+
+func (app *CLIApp) notifyAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add <kind>",
+		Aliases: []string{"Add", "ADD"},
+		Short:   "Adds a notification rule",
+		Long:    "Adds a notification rule of the given kind: large_transaction, low_balance, item_reauth, budget_exceeded or new_recurring. large_transaction, low_balance and budget_exceeded rules need --threshold, and budget_exceeded rules need --category. Rules apply to all accounts unless --account is given. Webhook rules print a signing secret once, used to verify deliveries",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandAddNotificationRule(cmd, args)
+		},
+	}
+	cmd.Flags().String("threshold", "", "Amount the rule is triggered at")
+	cmd.Flags().String("category", "", "Category of a budget rule, ex. FOOD_AND_DRINK")
+	cmd.Flags().String("account", "", "Limit the rule to one account, by name")
+	_ = cmd.RegisterFlagCompletionFunc("account", app.completeAccountFlag)
+	cmd.Flags().String("channel", "email", "Delivery channel: email or webhook")
+	cmd.Flags().String("url", "", "HTTPS endpoint of a webhook rule")
+	return cmd
+}
+
+func (app *CLIApp) notifyListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"List", "LIST", "ls"},
+		Short:   "Lists notification rules",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandListNotificationRules(cmd)
+		},
+	}
+}
+
+func (app *CLIApp) notifyRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <rule-id>...",
+		Aliases: []string{"Remove", "REMOVE", "rm"},
+		Short:   "Removes notification rules, by the ID shown in `notify list`",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandRemoveNotificationRules(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) notifyLogCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "log",
+		Aliases: []string{"Log", "LOG"},
+		Short:   "Lists recent notification deliveries and their status",
+		Long:    "Lists recent notification deliveries. Failed deliveries are retried with increasing delays, and marked failed after several attempts. Use `notify retry` to send a failed delivery again",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			limit, _ := cmd.Flags().GetInt("limit")
+			return app.commandNotificationLog(cmd, limit)
+		},
+	}
+	cmd.Flags().Int("limit", 20, "Number of deliveries to show")
+	return cmd
+}
+
+func (app *CLIApp) notifyRetryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "retry <delivery-id>...",
+		Aliases: []string{"Retry", "RETRY"},
+		Short:   "Sends failed notifications again, by the ID shown in `notify log`",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandRetryNotifications(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) eventsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "events",
+		Aliases: []string{"Events", "EVENTS"},
+		Short:   "Manages the event stream sent to your own integrations",
+		Long:    "The server records an event as your data changes: transaction.created, transaction.modified and transaction.removed on syncs, balance.updated on balance updates, and item.error when a connection runs into trouble. Events are posted as signed webhooks to endpoints you subscribe, retried when delivery fails, and kept for 30 days",
+	}
+}
+
+func (app *CLIApp) eventsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"List", "LIST", "ls"},
+		Short:   "Lists recorded events, oldest first",
+		Long:    "Lists recorded events, oldest first. Use --after with the Seq of the last event seen to only list later events",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			after, _ := cmd.Flags().GetInt64("after")
+			eventType, _ := cmd.Flags().GetString("type")
+			limit, _ := cmd.Flags().GetInt("limit")
+			return app.commandListEvents(cmd, after, eventType, limit)
+		},
+	}
+	cmd.Flags().Int64("after", 0, "Only list events after this sequence number")
+	cmd.Flags().String("type", "", "Only list events of a type, ex. transaction.created or transaction.*")
+	cmd.Flags().Int("limit", 50, "Number of events to show")
+	return cmd
+}
+
+func (app *CLIApp) eventsSubscribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "subscribe <url>",
+		Aliases: []string{"Subscribe", "SUBSCRIBE", "sub"},
+		Short:   "Registers an HTTPS endpoint to receive events",
+		Long:    "Registers an HTTPS endpoint to receive events. Endpoints receive every event unless --type is given, which may be repeated and accepts wildcards like transaction.*. A signing secret is printed once, used to verify deliveries",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			eventTypes, _ := cmd.Flags().GetStringSlice("type")
+			return app.commandSubscribeEvents(cmd, args[0], eventTypes)
+		},
+	}
+	cmd.Flags().StringSlice("type", nil, "Event type to receive, ex. balance.updated or transaction.*")
+	return cmd
+}
+
+func (app *CLIApp) eventsEndpointsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "endpoints",
+		Aliases: []string{"Endpoints", "ENDPOINTS"},
+		Short:   "Lists endpoints subscribed to events",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandListEventEndpoints(cmd)
+		},
+	}
+}
+
+func (app *CLIApp) eventsUnsubscribeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "unsubscribe <endpoint-id>...",
+		Aliases: []string{"Unsubscribe", "UNSUBSCRIBE", "unsub"},
+		Short:   "Removes event endpoints, by the ID shown in `events endpoints`",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandUnsubscribeEvents(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) eventsDeliveriesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "deliveries <endpoint-id>",
+		Aliases: []string{"Deliveries", "DELIVERIES", "log"},
+		Short:   "Lists recent deliveries to an endpoint and their status",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			limit, _ := cmd.Flags().GetInt("limit")
+			return app.commandEventDeliveries(cmd, args[0], limit)
+		},
+	}
+	cmd.Flags().Int("limit", 20, "Number of deliveries to show")
+	return cmd
+}
+
+func (app *CLIApp) eventsReplayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "replay <endpoint-id>",
+		Aliases: []string{"Replay", "REPLAY"},
+		Short:   "Sends past events to an endpoint again",
+		Long:    "Sends events recorded since --since to an endpoint again, whether or not they were delivered before. --since and --until take a duration back from now (24h, 7d), a date (2006-01-02) or a timestamp (RFC 3339)",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			since, _ := cmd.Flags().GetString("since")
+			until, _ := cmd.Flags().GetString("until")
+			return app.commandReplayEvents(cmd, args[0], since, until)
+		},
+	}
+	cmd.Flags().String("since", "", "Start of the window to replay")
+	cmd.Flags().String("until", "", "End of the window to replay, now by default")
+	_ = cmd.MarkFlagRequired("since")
+	return cmd
+}
+
+func (app *CLIApp) apiKeyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "apikey",
+		Aliases: []string{"APIKey", "APIKEY", "apikeys"},
+		Short:   "Manages API keys, for scripts and scheduled jobs",
+		Long:    "API keys let scripts and scheduled jobs call the server without logging in. Keys are sent as an `Authorization: Bearer <key>` header, and are scoped read-only (read) or read-write (write). Keys cannot create other keys, delete your account or change your password",
+	}
+}
+
+func (app *CLIApp) apiKeyCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create <name>",
+		Aliases: []string{"Create", "CREATE", "add"},
+		Short:   "Creates an API key, printing it once",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scope, _ := cmd.Flags().GetString("scope")
+			expires, _ := cmd.Flags().GetString("expires")
+			return app.commandCreateAPIKey(cmd, args[0], scope, expires)
+		},
+	}
+	cmd.Flags().String("scope", "read", "Key scope: read or write")
+	cmd.Flags().String("expires", "", "When the key expires, as a duration (90d), date or timestamp. Never by default")
+	return cmd
+}
+
+func (app *CLIApp) apiKeyListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"Ls", "LS", "list"},
+		Short:   "Lists API keys, with when each was last used",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandListAPIKeys(cmd)
+		},
+	}
+}
+
+func (app *CLIApp) apiKeyRevokeCmd() *cobra.Command {
 	return &cobra.Command{
+		Use:     "revoke <key-id>...",
+		Aliases: []string{"Revoke", "REVOKE", "rm"},
+		Short:   "Revokes API keys, by the ID shown in `apikey ls`",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandRevokeAPIKeys(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) holdingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "holdings <account-name>",
+		Aliases:           []string{"Holdings", "HOLDINGS", "investments"},
+		Short:             "Shows an investment account's holdings, with cost basis and unrealized gain",
+		Long:              "Shows the securities held in a brokerage or retirement account, with their quantity, price, value, cost basis and unrealized gain. Gains are shown in green and losses in red. Use --sync to fetch the latest holdings from Plaid first, and --transactions to list the account's buys, sells, dividends and fees instead",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: app.completeAccountNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandHoldings(cmd, args)
+		},
+	}
+
+	cmd.Flags().Bool("sync", false, "Sync the account's holdings and investment transactions with Plaid first")
+	cmd.Flags().Bool("transactions", false, "List investment transactions instead of holdings")
+
+	return cmd
+}
+
+func (app *CLIApp) debtsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "debts",
+		Aliases: []string{"Debts", "DEBTS", "liabilities"},
+		Short:   "Shows credit card and loan balances, with avalanche and snowball payoff schedules",
+		Long:    "Shows the balance, APR, minimum payment and next due date of each credit card, student loan and mortgage, with overdue payments in red. Below them are payoff schedules for the avalanche (highest rate first) and snowball (smallest balance first) strategies, paying the minimums plus any --extra each month. Use --sync to fetch the latest liability data from Plaid first",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandDebts(cmd, args)
+		},
+	}
+
+	cmd.Flags().Bool("sync", false, "Sync liability data for items with credit or loan accounts with Plaid first")
+	cmd.Flags().Float64("extra", 0, "Amount paid each month on top of the minimum payments")
+	cmd.Flags().String("strategy", "", "Only show one payoff strategy: avalanche or snowball")
+
+	return cmd
+}
+
+func (app *CLIApp) addItemCmd() *cobra.Command {
+	cmd := &cobra.Command{
 		Use:     "add-item",
 		Aliases: []string{"Add-Item", "ADD-ITEM"},
 		Short:   "Connect a financial institution to your account",
+		Long:    "Opens Plaid Link to connect a financial institution. By default the server's configured countries and products are offered, --country and --products narrow them, for example to link a US bank for its loans with --country US --products transactions,liabilities",
 		Args:    cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return app.commandAddItem(cmd)
+			countries, _ := cmd.Flags().GetStringSlice("country")
+			products, _ := cmd.Flags().GetStringSlice("products")
+			return app.commandAddItem(cmd, countries, products)
 		},
 	}
+
+	cmd.Flags().StringSlice("country", nil, "Country codes of the institution, such as US or CA (defaults to the server's countries)")
+	cmd.Flags().StringSlice("products", nil, "Plaid products to request, such as transactions,liabilities (defaults to the server's products)")
+
+	return cmd
 }
 
-func (app *CLIApp) logsCmd() *cobra.Command {
+func (app *CLIApp) uiCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "ui",
+		Aliases: []string{"Ui", "UI", "dashboard"},
+		Short:   "Opens an interactive dashboard of items, accounts and transactions",
+		Long:    "Opens a full-screen dashboard with an items/accounts sidebar, a filterable transaction list, a monthly income/expense chart and recurring streams. Transactions can be re-categorized and tagged, and items synced, from within the dashboard",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandUI(cmd)
+		},
+	}
+}
+
+func (app *CLIApp) profileCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "profile",
+		Aliases: []string{"Profile", "PROFILE"},
+		Short:   "Manages server profiles",
+		Long:    "Profiles point the CLI at different servers, such as the hosted server, a self-hosted instance or a sandbox. Each profile has its own credentials, settings and local database. Use --profile or GREED_PROFILE to pick a profile for a single command",
+	}
+}
+
+func (app *CLIApp) profileAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add <profile-name> --url <server-url>",
+		Aliases: []string{"Add", "ADD"},
+		Short:   "Adds a profile for a server",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandProfileAdd(cmd, args)
+		},
+	}
+	cmd.Flags().String("url", "", "Address of the server, ex. http://localhost:3333")
+	cmd.Flags().Bool("use", false, "Switch to the profile once added")
+	_ = cmd.MarkFlagRequired("url")
+	return cmd
+}
+
+func (app *CLIApp) profileUseCmd() *cobra.Command {
 	return &cobra.Command{
+		Use:     "use <profile-name>",
+		Aliases: []string{"Use", "USE"},
+		Short:   "Switches the profile used by commands",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandProfileUse(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) profileListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"List", "LIST", "ls"},
+		Short:   "Lists profiles, marking the one in use",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandProfileList(cmd)
+		},
+	}
+}
+
+func (app *CLIApp) profileRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <profile-name>",
+		Aliases: []string{"Remove", "REMOVE", "rm"},
+		Short:   "Removes a logged out profile, with its settings and local database",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandProfileRemove(cmd, args)
+		},
+	}
+}
+
+func (app *CLIApp) lockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "lock",
+		Aliases: []string{"Lock", "LOCK"},
+		Short:   "Forgets the credential passphrase cached for this shell session",
+		Long:    "When credentials are stored in the encrypted file, the passphrase is cached for the rest of the shell session. Lock forgets it, so the next command asks for the passphrase again",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandLock(cmd)
+		},
+	}
+}
+
+func (app *CLIApp) agentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "agent",
+		Short:  "Runs the credential passphrase agent for a shell session",
+		Hidden: true,
+		Args:   cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell, _ := cmd.Flags().GetInt("shell")
+			dir, _ := cmd.Flags().GetString("dir")
+			return auth.RunAgent(dir, shell)
+		},
+	}
+	cmd.Flags().Int("shell", 0, "Process ID of the shell the agent belongs to")
+	cmd.Flags().String("dir", "", "Config directory of the profile the agent holds the key for")
+	_ = cmd.MarkFlagRequired("shell")
+	_ = cmd.MarkFlagRequired("dir")
+	return cmd
+}
+
+func (app *CLIApp) logsCmd() *cobra.Command {
+	cmd := &cobra.Command{
 		Use:     "logs",
 		Aliases: []string{"Logs", "LOGS"},
 		Short:   "View error logs in more depth",
+		Long:    "View errors logged by commands on this machine, newest first. Logs are kept for 90 days, up to the latest 1000 errors",
 		Args:    cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandReadLogs(cmd)
 		},
 	}
+	cmd.Flags().String("since", "", "Show errors logged since a duration ago (24h, 7d), a date (2006-01-02) or a timestamp")
+	cmd.Flags().String("command", "", "Show errors logged by a command, ex. sync")
+	cmd.Flags().String("grep", "", "Show errors containing text in their message or command line")
+	cmd.Flags().Int64("limit", 5, "Maximum number of errors shown")
+	cmd.Flags().Bool("json", false, "Print errors as JSON")
+	return cmd
+}
+
+func (app *CLIApp) diagnoseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "diagnose",
+		Aliases: []string{"Diagnose", "DIAGNOSE"},
+		Short:   "Bundles redacted error logs, config and version info for bug reports",
+		Long:    "Writes a .tar.gz bundle of recent error logs, settings, profiles and version info, for attaching to bug reports. Credentials are never included, and tokens, emails and IDs are redacted",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.commandDiagnose(cmd)
+		},
+	}
+	cmd.Flags().String("out", "", "Path of the bundle to write (default greed-diagnose-<timestamp>.tar.gz in the current directory)")
+	return cmd
 }
 
 func (app *CLIApp) setDefaultsCmd() *cobra.Command {
@@ -340,10 +1138,11 @@ func (app *CLIApp) setDefaultsCmd() *cobra.Command {
 
 func (app *CLIApp) defaultItemCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "item <item-name>",
-		Aliases: []string{"Item", "ITEM"},
-		Short:   "Set a default item to be used in command arguments",
-		Args:    cobra.ExactArgs(1),
+		Use:               "item <item-name>",
+		Aliases:           []string{"Item", "ITEM"},
+		Short:             "Set a default item to be used in command arguments",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: app.completeItemNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandSetDefaultItem(cmd, args)
 		},
@@ -352,10 +1151,11 @@ func (app *CLIApp) defaultItemCmd() *cobra.Command {
 
 func (app *CLIApp) defaultAccountCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "account <account-name>",
-		Aliases: []string{"Account", "ACCOUNT"},
-		Short:   "Set a default account to be used in command arguments",
-		Args:    cobra.ExactArgs(1),
+		Use:               "account <account-name>",
+		Aliases:           []string{"Account", "ACCOUNT"},
+		Short:             "Set a default account to be used in command arguments",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: app.completeAccountNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return app.commandSetDefaultAccount(cmd, args)
 		},
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/output"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/spf13/cobra"
+)
+
+// Shows an investment account's holdings, or its investment transactions with --transactions.
+// With --sync, the account's item is synced with Plaid first
+func (app *CLIApp) commandHoldings(cmd *cobra.Command, args []string) error {
+	sync, _ := cmd.Flags().GetBool("sync")
+	showTxns, _ := cmd.Flags().GetBool("transactions")
+	ctx := context.Background()
+
+	var account database.Account
+	if len(args) == 0 && app.Config.Settings.DefaultAccount.ID == "" {
+		LogError(app.Config.Db, cmd, fmt.Errorf("no account given"), "Missing argument")
+		return nil
+
+	} else if len(args) == 1 {
+		creds, err := auth.GetCreds(app.Config.ConfigFP)
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error getting credentials")
+			return err
+		}
+
+		account, err = getLocalAccount(app, creds.User.ID.String(), args[0])
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error finding account")
+			return err
+		}
+
+	} else {
+		account = app.Config.Settings.DefaultAccount
+	}
+
+	if sync {
+		remote, err := app.Config.Client.GetAccount(ctx, account.ID)
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+
+		result, err := app.Config.Client.SyncInvestments(ctx, remote.ItemId)
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error syncing investment data")
+			return err
+		}
+		if !app.Output.IsMachine() {
+			fmt.Printf(" > Synced %d holdings and %d investment transactions\n", result.Holdings, result.Transactions)
+		}
+	}
+
+	if showTxns {
+		txns, err := app.Config.Client.GetInvestmentTransactions(ctx, account.ID)
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+
+		if app.Output.IsMachine() {
+			return app.writeOutput(cmd, txns)
+		}
+
+		if len(txns) == 0 {
+			fmt.Printf(" < No investment transactions for %s > \n", tables.AccountDisplayName(account))
+			return nil
+		}
+
+		fmt.Printf(" < %s: investment transactions > \n\n", tables.AccountDisplayName(account))
+		tables.MakeTableForInvestmentTransactions(txns).Print()
+		fmt.Println("")
+		return nil
+	}
+
+	holdings, err := app.Config.Client.GetHoldings(ctx, account.ID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output == output.JSON {
+		return app.writeOutput(cmd, holdings)
+	}
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, holdings.Holdings)
+	}
+
+	if len(holdings.Holdings) == 0 {
+		fmt.Printf(" < No holdings for %s, sync the account with `greed holdings %s --sync` > \n", tables.AccountDisplayName(account), tables.AccountDisplayName(account))
+		return nil
+	}
+
+	fmt.Printf(" < %s: holdings > \n\n", tables.AccountDisplayName(account))
+	tables.MakeTableForHoldings(holdings).Print()
+	fmt.Println("")
+
+	return nil
+}
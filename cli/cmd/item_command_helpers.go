@@ -1,48 +1,172 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"os"
+	"time"
 
+	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/resolve"
 	"github.com/jms-guy/greed/models"
+	"golang.org/x/term"
 )
 
-// Retrieves an item record from server database based on given item name
+// Retrieves an item record from the server, by its name, the start of its name or its ID. The list of items
+// is kept in the local database for shell completion. If the name matches several items, the user is asked
+// to choose one when running in a terminal
 func getItemFromServer(app *CLIApp, itemName string) (models.ItemName, error) {
-	var itemFound models.ItemName
-	itemsURL := app.Config.Client.BaseURL + "/api/items"
+	items, err := app.Config.Client.GetItems(context.Background())
+	if err != nil {
+		return models.ItemName{}, fmt.Errorf("error making http request: %w", err)
+	}
+
+	cacheItems(app, items)
+
+	candidates := make([]resolve.Candidate, len(items))
+	for i, item := range items {
+		candidates[i] = resolve.Candidate{
+			ID:    item.ItemId,
+			Names: []string{item.Nickname},
+			Label: fmt.Sprintf("%s (%s)", item.Nickname, item.InstitutionName),
+		}
+	}
 
-	res, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("GET", itemsURL, token, nil)
-	})
+	match, err := resolveCandidate(itemName, candidates)
 	if err != nil {
-		return itemFound, fmt.Errorf("error making http request: %w", err)
+		return models.ItemName{}, fmt.Errorf("no item found: %w", err)
+	}
+
+	for _, item := range items {
+		if item.ItemId == match.ID {
+			return item, nil
+		}
+	}
+
+	return models.ItemName{}, fmt.Errorf("no item found: %w", resolve.ErrNoMatch)
+}
+
+// Finds a local account by its name, nickname, the start of either, its mask as *1234, or its ID. If the
+// name matches several accounts, the user is asked to choose one when running in a terminal
+func getLocalAccount(app *CLIApp, userID, accountName string) (database.Account, error) {
+	accounts, err := app.Config.Db.GetAllAccounts(context.Background(), userID)
+	if err != nil {
+		return database.Account{}, fmt.Errorf("error getting local accounts: %w", err)
+	}
+
+	candidates := make([]resolve.Candidate, len(accounts))
+	for i, acc := range accounts {
+		names := []string{acc.Name}
+		if acc.Nickname.Valid && acc.Nickname.String != "" {
+			names = append([]string{acc.Nickname.String}, names...)
+		}
+		label := acc.Name
+		if acc.Mask.String != "" {
+			label = fmt.Sprintf("%s *%s", label, acc.Mask.String)
+		}
+		if acc.Nickname.Valid && acc.Nickname.String != "" {
+			label = fmt.Sprintf("%s [%s]", acc.Nickname.String, label)
+		}
+		if acc.InstitutionName.String != "" {
+			label = fmt.Sprintf("%s (%s)", label, acc.InstitutionName.String)
+		}
+
+		candidates[i] = resolve.Candidate{
+			ID:    acc.ID,
+			Names: names,
+			Mask:  acc.Mask.String,
+			Label: label,
+		}
 	}
-	defer res.Body.Close()
 
-	err = checkResponseStatus(res)
+	match, err := resolveCandidate(accountName, candidates)
 	if err != nil {
-		return itemFound, err
+		return database.Account{}, fmt.Errorf("no account found: %w", err)
+	}
+
+	for _, acc := range accounts {
+		if acc.ID == match.ID {
+			return acc, nil
+		}
 	}
 
-	var itemsResp struct {
-		Items []models.ItemName `json:"items"`
+	return database.Account{}, fmt.Errorf("no account found: %w", resolve.ErrNoMatch)
+}
+
+// Matches a query against candidates, asking the user to choose between several matches when stdin
+// and stdout are a terminal, and returning the ambiguity as an error otherwise
+func resolveCandidate(query string, candidates []resolve.Candidate) (resolve.Candidate, error) {
+	match, err := resolve.Match(query, candidates)
+
+	var amb *resolve.AmbiguousError
+	if errors.As(err, &amb) && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) { // #nosec G115 - file descriptors fit in an int
+		return resolve.Choose(os.Stdin, os.Stdout, amb)
+	}
+
+	return match, err
+}
+
+// Asks the user to confirm deleting something found from the start of its name or ID, showing what it
+// resolved to. Exact matches are deleted without asking. Outside of a terminal nothing can be confirmed,
+// so the full name or ID has to be given
+func confirmResolvedDeletion(query string, exact bool, what, label string) (bool, error) {
+	if exact {
+		return true, nil
 	}
-	if err = json.NewDecoder(res.Body).Decode(&itemsResp); err != nil {
-		return itemFound, fmt.Errorf("decoding err: %w", err)
+	if !term.IsTerminal(int(os.Stdin.Fd())) { // #nosec G115 - file descriptors fit in an int
+		return false, fmt.Errorf("%q is only the start of %s %s, give it in full to delete it", query, what, label)
 	}
 
-	for _, i := range itemsResp.Items {
-		if i.Nickname == itemName {
-			itemFound = i
-			break
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Printf(" < Delete %s - %s? (y/n) > \n", what, label)
+	for {
+		fmt.Print(" > ")
+		if !scanner.Scan() {
+			return false, nil
 		}
+		if scanner.Text() == "n" {
+			return false, nil
+		} else if scanner.Text() == "y" {
+			return true, nil
+		}
+		fmt.Println(" < Please enter either 'y' or 'n' > ")
+	}
+}
+
+// Replaces the logged in user's cached items in the local database, used to complete item names without
+// contacting the server. The cache is only a convenience, so failing to update it is not an error
+func cacheItems(app *CLIApp, items []models.ItemName) {
+	userID, err := auth.CurrentUserID(app.Config.ConfigFP)
+	if err != nil || userID == "" {
+		return
+	}
+
+	ctx := context.Background()
+	tx, err := app.Config.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return
 	}
+	defer tx.Rollback()
 
-	if itemFound.ItemId == "" {
-		return itemFound, fmt.Errorf("no item found")
+	qtx := app.Config.Db.WithTx(tx)
+	if err := qtx.DeleteItemsForUser(ctx, userID); err != nil {
+		return
+	}
+	for _, item := range items {
+		params := database.UpsertItemParams{
+			ID:              item.ItemId,
+			UserID:          userID,
+			Nickname:        item.Nickname,
+			InstitutionName: item.InstitutionName,
+			UpdatedAt:       time.Now().Format("2006-01-02"),
+		}
+		if err := qtx.UpsertItem(ctx, params); err != nil {
+			return
+		}
 	}
 
-	return itemFound, nil
+	_ = tx.Commit()
 }
@@ -4,9 +4,7 @@ import (
 	"bufio"
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"strconv"
 	"time"
@@ -17,7 +15,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// Fetches all transaction records for accounts attached to given item
+// Has the server sync transaction records for the given item, then applies changes to the user's
+// transactions since the last fetch or sync to the local database
 func (app *CLIApp) commandGetTransactions(cmd *cobra.Command, args []string) error {
 	var item models.ItemName
 	var err error
@@ -36,59 +35,27 @@ func (app *CLIApp) commandGetTransactions(cmd *cobra.Command, args []string) err
 		}
 	}
 
-	txnsURL := app.Config.Client.BaseURL + "/api/items/" + item.ItemId + "/access/transactions"
-
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("POST", txnsURL, token, nil)
-	})
+	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
+		LogError(app.Config.Db, cmd, err, "Error getting credentials")
 		return err
 	}
-	defer resp.Body.Close()
 
-	err = checkResponseStatus(resp)
+	_, err = app.Config.Client.SyncTransactions(context.Background(), item.ItemId)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	var txns []models.Transaction
-	if err = json.NewDecoder(resp.Body).Decode(&txns); err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
-		return err
-	}
-
-	fmt.Println("Creating local records...")
-
-	for _, t := range txns {
-		a, err := strconv.ParseFloat(t.Amount, 64)
-		if err != nil {
-			LogError(app.Config.Db, cmd, fmt.Errorf("error converting string value: %w", err), "Data error")
-			return err
-		}
-
-		params := database.CreateTransactionParams{
-			ID:                      t.Id,
-			AccountID:               t.AccountId,
-			Amount:                  a,
-			IsoCurrencyCode:         sql.NullString{String: t.IsoCurrencyCode, Valid: true},
-			Date:                    sql.NullString{String: t.Date.Format("2006-01-02"), Valid: true},
-			MerchantName:            sql.NullString{String: t.MerchantName, Valid: true},
-			PaymentChannel:          t.PaymentChannel,
-			PersonalFinanceCategory: t.PersonalFinanceCategory,
-		}
-
-		_, err = app.Config.Db.CreateTransaction(context.Background(), params)
-		if err != nil {
-			LogError(app.Config.Db, cmd, fmt.Errorf("error creating transaction record: %w", err), "Local database error")
-			return err
-		}
+	fmt.Println("Updating local records...")
 
-		fmt.Printf("\r%v", t.Id)
+	_, err = applyTransactionChanges(app, creds.User.ID.String())
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error updating local records")
+		return err
 	}
 
-	fmt.Println("\r > Transaction data fetched successfully.")
+	fmt.Println(" > Transaction data fetched successfully.")
 
 	return nil
 }
@@ -109,30 +76,12 @@ func (app *CLIApp) commandGetAccounts(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	accountsURL := app.Config.Client.BaseURL + "/api/items/" + item.ItemId + "/access/accounts"
-
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("POST", accountsURL, token, nil)
-	})
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http request: %w", err), "Error contacting server")
-		return err
-	}
-	defer resp.Body.Close()
-
-	err = checkResponseStatus(resp)
+	response, err := app.Config.Client.CreateAccounts(context.Background(), item.ItemId)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	var response models.Accounts
-
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
-		return err
-	}
-
 	if len(response.Accounts) == 0 {
 		fmt.Printf("No accounts found for item: %s\n", itemName)
 		return nil
@@ -185,6 +134,12 @@ func (app *CLIApp) commandGetAccounts(cmd *cobra.Command, args []string) error {
 
 	}
 
+	err = syncAccountOverrides(app, creds)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error syncing account settings")
+		return err
+	}
+
 	fmt.Println(" > Account data fetched successfully.")
 
 	return nil
@@ -201,22 +156,11 @@ func (app *CLIApp) commandRenameItem(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	renameURL := app.Config.Client.BaseURL + "/api/items/" + item.ItemId + "/name"
-
 	request := models.UpdateItemName{
 		Nickname: itemRename,
 	}
 
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("PUT", renameURL, token, request)
-	})
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http request: %w", err), "Error contacting server")
-		return nil
-	}
-	defer resp.Body.Close()
-
-	err = checkResponseStatus(resp)
+	err = app.Config.Client.UpdateItemName(context.Background(), item.ItemId, request)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return nil
@@ -264,7 +208,7 @@ func (app *CLIApp) commandDeleteItem(cmd *cobra.Command, args []string) error {
 
 	fmt.Println(" < Upon completion, all records for this item will be permanently deleted. > ")
 	fmt.Println(" < If you wish to access this item's data in the future, you will have to link it once again.")
-	fmt.Printf(" < Are you sure you want to delete item - %s? (y/n) > \n", itemName)
+	fmt.Printf(" < Are you sure you want to delete item - %s (%s)? (y/n) > \n", item.Nickname, item.InstitutionName)
 	for {
 		fmt.Print(" > ")
 		scanner.Scan()
@@ -278,18 +222,7 @@ func (app *CLIApp) commandDeleteItem(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	deleteURL := app.Config.Client.BaseURL + "/api/items/" + item.ItemId
-
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("DELETE", deleteURL, token, nil)
-	})
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http request: %w", err), "Error contacting server")
-		return nil
-	}
-	defer resp.Body.Close()
-
-	err = checkResponseStatus(resp)
+	err = app.Config.Client.DeleteItem(context.Background(), item.ItemId)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return nil
@@ -333,16 +266,19 @@ func (app *CLIApp) commandUpdate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func (app *CLIApp) commandAddItem(cmd *cobra.Command) error {
-	itemsURL := app.Config.Client.BaseURL + "/api/items"
-
+func (app *CLIApp) commandAddItem(cmd *cobra.Command, countries, products []string) error {
 	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error getting credentials")
 		return nil
 	}
 
-	linked, err := userFirstTimePlaidLinkHelper(app, creds, itemsURL)
+	linkReq := models.LinkTokenRequest{
+		CountryCodes: countries,
+		Products:     products,
+	}
+
+	linked, err := userFirstTimePlaidLinkHelper(app, creds, linkReq)
 	if err != nil {
 		LogError(app.Config.Db, cmd, fmt.Errorf("error linking: %w", err), "Error connecting financial institution")
 		return nil
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/resolve"
+	"github.com/jms-guy/greed/models"
+	"github.com/spf13/cobra"
+)
+
+// Institution name stored on local records of manual accounts, which have no item
+const manualInstitutionName = "Manual"
+
+// Creates a manual account, tracked by hand with no Plaid item. Accounts at a bank come from linking
+// an item, so manual must be set
+func (app *CLIApp) commandCreateAccount(cmd *cobra.Command, name string, manual bool, accType, subtype, balance, currency string) error {
+	if !manual {
+		err := fmt.Errorf("only manual accounts can be created, accounts at a bank are added with 'greed add-item'")
+		LogError(app.Config.Db, cmd, err, "Missing --manual flag")
+		return err
+	}
+
+	creds, err := auth.GetCreds(app.Config.ConfigFP)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error getting credentials")
+		return err
+	}
+
+	request := models.CreateManualAccount{
+		Name:            name,
+		Type:            accType,
+		Subtype:         subtype,
+		IsoCurrencyCode: currency,
+		Balance:         balance,
+	}
+
+	account, err := app.Config.Client.CreateManualAccount(context.Background(), request)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error creating account")
+		return err
+	}
+
+	if err := storeAccountBalances(app, creds, models.Accounts{Accounts: []models.Account{account}}, manualInstitutionName); err != nil {
+		LogError(app.Config.Db, cmd, err, "Local database error")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, account)
+	}
+
+	fmt.Printf(" > Manual account %s created, with a balance of %s.\n", account.Name, account.CurrentBalance)
+
+	return nil
+}
+
+// Adds a transaction to a manual account
+func (app *CLIApp) commandAddTransaction(cmd *cobra.Command, accountName, amount, date, merchant, category, channel string) error {
+	creds, account, err := app.manualCommandAccount(cmd, accountName)
+	if err != nil {
+		return err
+	}
+
+	request := models.CreateManualTransaction{
+		Amount:         amount,
+		Date:           date,
+		MerchantName:   merchant,
+		Category:       category,
+		PaymentChannel: channel,
+	}
+
+	txn, err := app.Config.Client.CreateManualTransaction(context.Background(), account.ID, request)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error adding transaction")
+		return err
+	}
+
+	if err := app.refreshManualAccount(cmd, creds, account.ID); err != nil {
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, txn)
+	}
+
+	fmt.Printf(" > Transaction %s of %s added to %s.\n", txn.Id, txn.Amount, account.Name)
+
+	return nil
+}
+
+// Changes a manual account's transaction. Only the fields whose flags were given are changed
+func (app *CLIApp) commandEditTransaction(cmd *cobra.Command, accountName, txnID string) error {
+	request := models.UpdateManualTransaction{
+		Amount:         changedFlag(cmd, "amount"),
+		Date:           changedFlag(cmd, "date"),
+		MerchantName:   changedFlag(cmd, "merchant"),
+		Category:       changedFlag(cmd, "category"),
+		PaymentChannel: changedFlag(cmd, "channel"),
+	}
+	if request == (models.UpdateManualTransaction{}) {
+		err := fmt.Errorf("no changes given")
+		LogError(app.Config.Db, cmd, err, "Nothing to change, give at least one of --amount, --date, --merchant, --category or --channel")
+		return err
+	}
+
+	creds, account, err := app.manualCommandAccount(cmd, accountName)
+	if err != nil {
+		return err
+	}
+
+	txn, err := getLocalTransaction(app, account.ID, txnID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding transaction")
+		return err
+	}
+
+	updated, err := app.Config.Client.UpdateManualTransaction(context.Background(), account.ID, txn.ID, request)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error changing transaction")
+		return err
+	}
+
+	if err := app.refreshManualAccount(cmd, creds, account.ID); err != nil {
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, updated)
+	}
+
+	fmt.Printf(" > Transaction %s changed.\n", updated.Id)
+
+	return nil
+}
+
+// Deletes a manual account's transaction
+func (app *CLIApp) commandRemoveTransaction(cmd *cobra.Command, accountName, txnID string) error {
+	creds, account, err := app.manualCommandAccount(cmd, accountName)
+	if err != nil {
+		return err
+	}
+
+	txn, err := getLocalTransaction(app, account.ID, txnID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding transaction")
+		return err
+	}
+
+	confirmed, err := confirmResolvedDeletion(txnID, txn.ID == txnID, "transaction", transactionLabel(txn))
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, err.Error())
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Transaction deletion aborted.")
+		return nil
+	}
+
+	if err := app.Config.Client.DeleteManualTransaction(context.Background(), account.ID, txn.ID); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error deleting transaction")
+		return err
+	}
+
+	if err := app.refreshManualAccount(cmd, creds, account.ID); err != nil {
+		return err
+	}
+
+	fmt.Printf(" > Transaction %s deleted.\n", txn.ID)
+
+	return nil
+}
+
+// Value of a string flag if it was given on the command line, otherwise nil
+func changedFlag(cmd *cobra.Command, name string) *string {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	value, _ := cmd.Flags().GetString(name)
+	return &value
+}
+
+// Gets the credentials and local account a transaction command works on
+func (app *CLIApp) manualCommandAccount(cmd *cobra.Command, accountName string) (models.Credentials, database.Account, error) {
+	creds, err := auth.GetCreds(app.Config.ConfigFP)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error getting credentials")
+		return models.Credentials{}, database.Account{}, err
+	}
+
+	account, err := getLocalAccount(app, creds.User.ID.String(), accountName)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error finding account")
+		return models.Credentials{}, database.Account{}, err
+	}
+
+	return creds, account, nil
+}
+
+// Brings local records up to date after a manual account's transactions change, reading the change from
+// the transaction change feed and the account's new balance from the server
+func (app *CLIApp) refreshManualAccount(cmd *cobra.Command, creds models.Credentials, accountID string) error {
+	if _, err := applyTransactionChanges(app, creds.User.ID.String()); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error updating local records")
+		return err
+	}
+
+	account, err := app.Config.Client.GetAccount(context.Background(), accountID)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if err := storeAccountBalances(app, creds, models.Accounts{Accounts: []models.Account{account}}, manualInstitutionName); err != nil {
+		LogError(app.Config.Db, cmd, err, "Local database error")
+		return err
+	}
+
+	return nil
+}
+
+// Finds a local transaction of an account by its ID, or the start of its ID
+func getLocalTransaction(app *CLIApp, accountID, txnID string) (database.Transaction, error) {
+	txns, err := app.Config.Db.GetTransactions(context.Background(), accountID)
+	if err != nil {
+		return database.Transaction{}, fmt.Errorf("error getting local transactions: %w", err)
+	}
+
+	candidates := make([]resolve.Candidate, len(txns))
+	for i, txn := range txns {
+		candidates[i] = resolve.Candidate{
+			ID:    txn.ID,
+			Names: []string{txn.ID},
+			Label: transactionLabel(txn),
+		}
+	}
+
+	match, err := resolveCandidate(txnID, candidates)
+	if err != nil {
+		return database.Transaction{}, fmt.Errorf("no transaction found: %w", err)
+	}
+
+	for _, txn := range txns {
+		if txn.ID == match.ID {
+			return txn, nil
+		}
+	}
+
+	return database.Transaction{}, fmt.Errorf("no transaction found: %w", resolve.ErrNoMatch)
+}
+
+// Describes a transaction by its date, amount and merchant
+func transactionLabel(txn database.Transaction) string {
+	label := fmt.Sprintf("%s %s %.2f", txn.ID, txn.Date.String, txn.Amount)
+	if txn.MerchantName.String != "" {
+		label = fmt.Sprintf("%s %s", label, txn.MerchantName.String)
+	}
+	return label
+}
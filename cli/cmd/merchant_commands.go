@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/spf13/cobra"
+)
+
+// Lists the merchants transactions are summarized under, with the names banks reported for each
+func (app *CLIApp) commandListMerchants(cmd *cobra.Command) error {
+	merchants, err := app.Config.Client.GetMerchants(context.Background())
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, merchants)
+	}
+
+	if len(merchants) == 0 {
+		fmt.Println(" < No merchants > ")
+		return nil
+	}
+
+	tables.MakeMerchantsTable(merchants).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Lists the user's merchant aliases
+func (app *CLIApp) commandListMerchantAliases(cmd *cobra.Command) error {
+	aliases, err := app.Config.Client.GetMerchantAliases(context.Background())
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, aliases)
+	}
+
+	if len(aliases) == 0 {
+		fmt.Println(" < No merchant aliases > ")
+		return nil
+	}
+
+	tables.MakeMerchantAliasesTable(aliases).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Counts transactions with a raw merchant name under another merchant
+func (app *CLIApp) commandAddMerchantAlias(cmd *cobra.Command, rawName, merchant string) error {
+	alias, err := app.Config.Client.CreateMerchantAlias(context.Background(), rawName, merchant)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error creating merchant alias")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, alias)
+	}
+
+	fmt.Printf(" > Transactions from %s are now counted under %s.\n", alias.RawName, alias.Merchant)
+
+	return nil
+}
+
+// Deletes merchant aliases by ID, or unique prefix of an ID
+func (app *CLIApp) commandRemoveMerchantAliases(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	aliases, err := app.Config.Client.GetMerchantAliases(ctx)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(aliases))
+	for _, a := range aliases {
+		ids = append(ids, a.ID)
+	}
+
+	for _, arg := range args {
+		id, err := resolveIDPrefix(ids, arg, "merchant alias")
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, err.Error())
+			return nil
+		}
+
+		if err := app.Config.Client.DeleteMerchantAlias(ctx, id); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+		fmt.Printf(" < Alias %s removed > \n", id.String()[:8])
+	}
+
+	return nil
+}
@@ -2,13 +2,14 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/jms-guy/greed/cli/internal/auth"
 	"github.com/jms-guy/greed/cli/internal/charts"
 	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/output"
 	"github.com/jms-guy/greed/cli/internal/tables"
 	"github.com/jms-guy/greed/models"
 	"github.com/spf13/cobra"
@@ -33,13 +34,9 @@ func (app *CLIApp) commandGetIncome(cmd *cobra.Command, args []string, mode stri
 			return err
 		}
 
-		params := database.GetAccountParams{
-			Name:   accountName,
-			UserID: creds.User.ID.String(),
-		}
-		account, err = app.Config.Db.GetAccount(context.Background(), params)
+		account, err = getLocalAccount(app, creds.User.ID.String(), accountName)
 		if err != nil {
-			LogError(app.Config.Db, cmd, fmt.Errorf("error getting local account: %w", err), "Local database error")
+			LogError(app.Config.Db, cmd, err, "Error finding account")
 			return err
 		}
 
@@ -47,34 +44,21 @@ func (app *CLIApp) commandGetIncome(cmd *cobra.Command, args []string, mode stri
 		account = app.Config.Settings.DefaultAccount
 	}
 
-	incURL := app.Config.Client.BaseURL + "/api/accounts/" + account.ID + "/transactions/monetary"
-
-	res, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("GET", incURL, token, nil)
-	})
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer res.Body.Close()
-
-	err = checkResponseStatus(res)
+	response, err := app.Config.Client.GetMonetaryData(context.Background(), account.ID)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	var response []models.MonetaryData
-	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
-		return err
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, response)
 	}
 
 	if mode == "graph" {
 		charts.MakeIncomeChart(response)
 	}
 
-	tbl, err := tables.MakeTableForMonetaryAggregate(response, account.Name)
+	tbl, err := tables.MakeTableForMonetaryAggregate(response, tables.AccountDisplayName(account))
 	if err != nil {
 		LogError(app.Config.Db, cmd, fmt.Errorf("error building table: %w", err), "Data error")
 		return err
@@ -84,3 +68,122 @@ func (app *CLIApp) commandGetIncome(cmd *cobra.Command, args []string, mode stri
 
 	return nil
 }
+
+// A row of a comparison report, flattened for csv/tsv output
+type comparisonLine struct {
+	Breakdown string `json:"breakdown"`
+	Key       string `json:"key"`
+	Current   string `json:"current"`
+	Previous  string `json:"previous"`
+	Delta     string `json:"delta"`
+	DeltaPct  string `json:"delta_pct"`
+}
+
+// Compares spending between two periods for an account, or across all accounts, and displays
+// the changes by category and merchant
+func (app *CLIApp) commandCompare(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	by, _ := cmd.Flags().GetString("by")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	by = strings.ToLower(by)
+	if by != "category" && by != "merchant" && by != "both" {
+		LogError(app.Config.Db, cmd, fmt.Errorf("invalid breakdown: %s", by), "Breakdown must be one of [category | merchant | both]")
+		return nil
+	}
+
+	query := url.Values{}
+	for flag, param := range map[string]string{
+		"period":        "period",
+		"against":       "against",
+		"start":         "start",
+		"end":           "end",
+		"against-start": "against_start",
+		"against-end":   "against_end",
+	} {
+		if val, _ := cmd.Flags().GetString(flag); val != "" {
+			query.Set(param, val)
+		}
+	}
+	if query.Get("period") == "" && query.Get("start") == "" {
+		LogError(app.Config.Db, cmd, fmt.Errorf("no period given"), "Missing --period, or --start and --end")
+		return nil
+	}
+
+	var report models.ComparisonReport
+	var err error
+	name := "All accounts"
+
+	if all {
+		report, err = app.Config.Client.CompareAccounts(context.Background(), query)
+	} else {
+		var account database.Account
+
+		if len(args) == 0 && app.Config.Settings.DefaultAccount.ID == "" {
+			LogError(app.Config.Db, cmd, fmt.Errorf("no account given"), "Missing argument, or use --all")
+			return nil
+
+		} else if len(args) == 1 {
+			creds, err := auth.GetCreds(app.Config.ConfigFP)
+			if err != nil {
+				LogError(app.Config.Db, cmd, err, "Error getting credentials")
+				return err
+			}
+
+			account, err = getLocalAccount(app, creds.User.ID.String(), args[0])
+			if err != nil {
+				LogError(app.Config.Db, cmd, err, "Error finding account")
+				return err
+			}
+
+		} else {
+			account = app.Config.Settings.DefaultAccount
+		}
+
+		name = tables.AccountDisplayName(account)
+		report, err = app.Config.Client.CompareAccount(context.Background(), account.ID, query)
+	}
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output == output.JSON {
+		return app.writeOutput(cmd, report)
+	}
+	if app.Output.IsMachine() {
+		var lines []comparisonLine
+		add := func(breakdown string, rows []models.ComparisonRow) {
+			for _, r := range rows {
+				pct := ""
+				if r.DeltaPct != nil {
+					pct = fmt.Sprintf("%.1f", *r.DeltaPct)
+				}
+				lines = append(lines, comparisonLine{breakdown, r.Key, r.Current, r.Previous, r.Delta, pct})
+			}
+		}
+		if by != "merchant" {
+			add("category", report.Categories)
+		}
+		if by != "category" {
+			add("merchant", report.Merchants)
+		}
+		add("total", []models.ComparisonRow{report.Total})
+		return app.writeOutput(cmd, lines)
+	}
+
+	period := fmt.Sprintf("%s to %s", report.Period.Start, report.Period.End)
+	against := fmt.Sprintf("%s to %s", report.Against.Start, report.Against.End)
+	fmt.Printf(" < %s: spending %s compared against %s > \n\n", name, period, against)
+
+	if by != "merchant" {
+		tables.MakeTableForComparison(report.Categories, report.Total, "Category", period, against, limit).Print()
+		fmt.Println("")
+	}
+	if by != "category" {
+		tables.MakeTableForComparison(report.Merchants, report.Total, "Merchant", period, against, limit).Print()
+		fmt.Println("")
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/jms-guy/greed/models"
+	"github.com/spf13/cobra"
+)
+
+// Creates a notification rule on the server. An account given by name is looked up in the local database
+func (app *CLIApp) commandAddNotificationRule(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	threshold, _ := cmd.Flags().GetString("threshold")
+	category, _ := cmd.Flags().GetString("category")
+	accountName, _ := cmd.Flags().GetString("account")
+	channel, _ := cmd.Flags().GetString("channel")
+	webhookURL, _ := cmd.Flags().GetString("url")
+
+	if webhookURL != "" && !cmd.Flags().Changed("channel") {
+		channel = "webhook"
+	}
+
+	rule := models.CreateNotificationRule{
+		Kind:       strings.ToLower(args[0]),
+		Threshold:  threshold,
+		Category:   category,
+		Channel:    channel,
+		WebhookURL: webhookURL,
+	}
+
+	if accountName != "" {
+		creds, err := auth.GetCreds(app.Config.ConfigFP)
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error getting credentials")
+			return err
+		}
+
+		account, err := getLocalAccount(app, creds.User.ID.String(), accountName)
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error finding account")
+			return err
+		}
+		rule.AccountID = account.ID
+	}
+
+	created, err := app.Config.Client.CreateNotificationRule(ctx, rule)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, created)
+	}
+
+	fmt.Printf(" < Rule %s created > \n", created.ID.String()[:8])
+	if created.WebhookSecret != "" {
+		fmt.Println("")
+		fmt.Println("Webhook signing secret, it will not be shown again:")
+		fmt.Printf("  %s\n", created.WebhookSecret)
+		fmt.Println("Deliveries carry an X-Greed-Signature header of sha256=<hex HMAC-SHA256 of \"<X-Greed-Timestamp>.<body>\">")
+	}
+
+	return nil
+}
+
+// Lists the user's notification rules, with account names from the local database
+func (app *CLIApp) commandListNotificationRules(cmd *cobra.Command) error {
+	ctx := context.Background()
+
+	rules, err := app.Config.Client.GetNotificationRules(ctx)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, rules)
+	}
+
+	if len(rules) == 0 {
+		fmt.Println(" < No notification rules > ")
+		return nil
+	}
+
+	creds, err := auth.GetCreds(app.Config.ConfigFP)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error getting credentials")
+		return err
+	}
+
+	accounts, err := app.Config.Db.GetAllAccounts(ctx, creds.User.ID.String())
+	if err != nil {
+		LogError(app.Config.Db, cmd, fmt.Errorf("error getting local account records: %w", err), "Local database error")
+		return err
+	}
+	accountNames := make(map[string]string, len(accounts))
+	for _, acc := range accounts {
+		accountNames[acc.ID] = tables.AccountDisplayName(acc)
+	}
+
+	tables.MakeNotificationRulesTable(rules, accountNames).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Deletes notification rules by ID, or unique prefix of an ID
+func (app *CLIApp) commandRemoveNotificationRules(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	rules, err := app.Config.Client.GetNotificationRules(ctx)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(rules))
+	for _, r := range rules {
+		ids = append(ids, r.ID)
+	}
+
+	for _, arg := range args {
+		id, err := resolveIDPrefix(ids, arg, "notification rule")
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, err.Error())
+			return nil
+		}
+
+		if err := app.Config.Client.DeleteNotificationRule(ctx, id); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+		fmt.Printf(" < Rule %s removed > \n", id.String()[:8])
+	}
+
+	return nil
+}
+
+// Lists recent notification deliveries, with their status
+func (app *CLIApp) commandNotificationLog(cmd *cobra.Command, limit int) error {
+	deliveries, err := app.Config.Client.GetNotificationDeliveries(context.Background(), limit)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, deliveries)
+	}
+
+	if len(deliveries) == 0 {
+		fmt.Println(" < No notifications sent > ")
+		return nil
+	}
+
+	tables.MakeNotificationDeliveriesTable(deliveries).Print()
+	fmt.Println("")
+
+	return nil
+}
+
+// Queues failed deliveries to be sent again, by ID or unique prefix of an ID
+func (app *CLIApp) commandRetryNotifications(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	deliveries, err := app.Config.Client.GetNotificationDeliveries(ctx, 500)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+
+	var ids []uuid.UUID
+	for _, d := range deliveries {
+		if d.Status == "failed" {
+			ids = append(ids, d.ID)
+		}
+	}
+
+	for _, arg := range args {
+		id, err := resolveIDPrefix(ids, arg, "failed notification")
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, err.Error())
+			return nil
+		}
+
+		if err := app.Config.Client.RetryNotificationDelivery(ctx, id); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			return err
+		}
+		fmt.Printf(" < Notification %s queued for retry > \n", id.String()[:8])
+	}
+
+	return nil
+}
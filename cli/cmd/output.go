@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/output"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/jms-guy/greed/models"
+	"github.com/spf13/cobra"
+)
+
+// Writes command results to stdout in the format selected with the --output flag
+func (app *CLIApp) writeOutput(cmd *cobra.Command, data any) error {
+	if err := output.Write(os.Stdout, app.Output, data); err != nil {
+		LogError(app.Config.Db, cmd, fmt.Errorf("error writing %s output: %w", app.Output, err), "Output error")
+		return err
+	}
+	return nil
+}
+
+// Returns true if results should be drawn in an interactive tcell pager, rather than printed
+func (app *CLIApp) usePager() bool {
+	return app.Output == output.Table && output.IsTerminal()
+}
+
+// Converts a local account record into the shared models struct, for machine-readable output
+func accountToModel(acc database.Account) models.Account {
+	createdAt, _ := time.Parse("2006-01-02", acc.CreatedAt)
+	updatedAt, _ := time.Parse("2006-01-02", acc.UpdatedAt)
+
+	return models.Account{
+		Id:                acc.ID,
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
+		Name:              acc.Name,
+		Nickname:          acc.Nickname.String,
+		DisplayName:       tables.AccountDisplayName(acc),
+		Type:              acc.Type,
+		Subtype:           acc.Subtype.String,
+		Mask:              acc.Mask.String,
+		OfficialName:      acc.OfficialName.String,
+		AvailableBalance:  fmt.Sprintf("%.2f", acc.AvailableBalance.Float64),
+		CurrentBalance:    fmt.Sprintf("%.2f", acc.CurrentBalance.Float64),
+		IsoCurrencyCode:   acc.IsoCurrencyCode.String,
+		Hidden:            acc.Hidden,
+		Archived:          acc.Archived,
+		IncludeInNetWorth: acc.IncludeInNetWorth,
+		SortOrder:         int(acc.SortOrder),
+	}
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Profile listing, for machine-readable output
+type profileRow struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Current  bool   `json:"current"`
+	LoggedIn bool   `json:"logged_in"`
+}
+
+// Adds a named profile pointing at a server
+func (app *CLIApp) commandProfileAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	serverURL, _ := cmd.Flags().GetString("url")
+	use, _ := cmd.Flags().GetBool("use")
+
+	if err := config.ValidateProfileName(name); err != nil {
+		LogError(app.Config.Db, cmd, err, "Invalid profile name")
+		return err
+	}
+
+	parsed, err := url.Parse(serverURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		err = fmt.Errorf("invalid server url %q, expected an http(s) address such as http://localhost:3333", serverURL)
+		LogError(app.Config.Db, cmd, err, "Invalid server url")
+		return err
+	}
+
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error loading profiles")
+		return err
+	}
+
+	if _, exists := profiles.Profiles[name]; exists {
+		err = fmt.Errorf("profile %q already exists", name)
+		LogError(app.Config.Db, cmd, err, "Error adding profile")
+		return err
+	}
+
+	profiles.Profiles[name] = config.Profile{Name: name, URL: serverURL}
+	if use {
+		profiles.Current = name
+	}
+
+	if err := config.SaveProfiles(profiles); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error saving profiles")
+		return err
+	}
+
+	if use {
+		fmt.Printf("Profile '%s' added and now in use\n", name)
+	} else {
+		fmt.Printf("Profile '%s' added, switch to it with `greed profile use %s`\n", name, name)
+	}
+	return nil
+}
+
+// Sets the profile used by commands
+func (app *CLIApp) commandProfileUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error loading profiles")
+		return err
+	}
+
+	if _, exists := profiles.Profiles[name]; !exists {
+		err = fmt.Errorf("no profile named %q", name)
+		LogError(app.Config.Db, cmd, err, "Error switching profile")
+		return err
+	}
+
+	profiles.Current = name
+	if err := config.SaveProfiles(profiles); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error saving profiles")
+		return err
+	}
+
+	fmt.Printf("Now using profile '%s'\n", name)
+	return nil
+}
+
+// Lists profiles, marking the one in use
+func (app *CLIApp) commandProfileList(cmd *cobra.Command) error {
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error loading profiles")
+		return err
+	}
+
+	rows := []profileRow{}
+	for _, p := range profiles.List() {
+		userID, _ := auth.CurrentUserID(config.ProfileConfigPath(p.Name))
+		rows = append(rows, profileRow{
+			Name:     p.Name,
+			URL:      p.URL,
+			Current:  p.Name == app.Config.Profile.Name,
+			LoggedIn: userID != "",
+		})
+	}
+
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, rows)
+	}
+
+	for _, r := range rows {
+		marker := " "
+		if r.Current {
+			marker = "*"
+		}
+		status := "logged out"
+		if r.LoggedIn {
+			status = "logged in"
+		}
+		fmt.Printf(" %s %-20s %-50s %s\n", marker, r.Name, r.URL, status)
+	}
+	return nil
+}
+
+// Removes a profile, along with its settings and local database
+func (app *CLIApp) commandProfileRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if name == config.DefaultProfile {
+		err := fmt.Errorf("the default profile can't be removed")
+		LogError(app.Config.Db, cmd, err, "Error removing profile")
+		return err
+	}
+
+	profiles, err := config.LoadProfiles()
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error loading profiles")
+		return err
+	}
+
+	if _, exists := profiles.Profiles[name]; !exists {
+		err = fmt.Errorf("no profile named %q", name)
+		LogError(app.Config.Db, cmd, err, "Error removing profile")
+		return err
+	}
+
+	profilePath := config.ProfileConfigPath(name)
+	userID, err := auth.CurrentUserID(profilePath)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error reading profile session")
+		return err
+	}
+	if userID != "" {
+		err = fmt.Errorf("profile %q is logged in, log out first with `greed --profile %s logout`", name, name)
+		LogError(app.Config.Db, cmd, err, "Error removing profile")
+		return err
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+
+	fmt.Println(" < The profile's settings and local database will be permanently deleted. > ")
+	fmt.Printf(" < Are you sure you want to remove profile - %s? (y/n) > \n", name)
+	for {
+		fmt.Print(" > ")
+		scanner.Scan()
+		if scanner.Text() == "n" {
+			fmt.Println("Profile removal aborted.")
+			return nil
+		} else if scanner.Text() == "y" {
+			break
+		} else {
+			fmt.Println(" < Please enter either 'y' or 'n' > ")
+		}
+	}
+
+	delete(profiles.Profiles, name)
+	if profiles.Current == name {
+		profiles.Current = config.DefaultProfile
+	}
+
+	if err := config.SaveProfiles(profiles); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error saving profiles")
+		return err
+	}
+
+	base, err := auth.GetBaseConfigPath(profilePath)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error getting config directory")
+		return err
+	}
+	if err := os.RemoveAll(filepath.Clean(base)); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error removing profile directory")
+		return err
+	}
+
+	fmt.Printf("Profile '%s' removed\n", name)
+	return nil
+}
@@ -1,39 +1,86 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
+	"sync"
 
 	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/client"
 	"github.com/jms-guy/greed/models"
 	"github.com/spf13/cobra"
 )
 
-// Wrapper function for refreshing JWT token logic
-func DoWithAutoRefresh(app *CLIApp, doRequest func(string) (*http.Response, error)) (*http.Response, error) {
-	creds, _ := auth.GetCreds(app.Config.ConfigFP)
-	resp, err := doRequest(creds.AccessToken)
-	if err != nil {
-		return nil, err
+// Serializes token refreshes, so requests rejected together while syncing several items at once don't
+// each spend the same refresh token
+var refreshMu sync.Mutex
+
+// Access token requests are made with. Credentials are loaded from the store once, instead of on every
+// request, and the token is replaced when tokens are stored or removed. Guarded by tokenMu, as requests
+// are made from several goroutines while syncing
+var (
+	tokenMu     sync.Mutex
+	accessToken string
+	tokenLoaded bool
+)
+
+// Hooks the API client up to the user's stored credentials, refreshing the JWT when the server rejects it
+func (app *CLIApp) configureClientAuth() {
+	app.Config.Client.Token = func() string {
+		return app.loadAccessToken()
 	}
+	app.Config.Client.OnUnauthorized = func() error {
+		refreshMu.Lock()
+		defer refreshMu.Unlock()
+		return refreshCreds(app)
+	}
+}
 
-	if resp.StatusCode == 401 {
-		_ = resp.Body.Close()
-		if refreshErr := refreshCreds(app); refreshErr != nil {
-			return nil, refreshErr
+// Returns the access token, loading the stored credentials the first time. Loading is tried again on the
+// next call if there are no credentials yet
+func (app *CLIApp) loadAccessToken() string {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
+	if !tokenLoaded {
+		creds, err := auth.GetCreds(app.Config.ConfigFP)
+		if err != nil {
+			return ""
 		}
-		creds, _ = auth.GetCreds(app.Config.ConfigFP)
-		resp, err = doRequest(creds.AccessToken)
+		accessToken = creds.AccessToken
+		tokenLoaded = true
+	}
+
+	return accessToken
+}
+
+func setAccessToken(token string) {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+	accessToken = token
+	tokenLoaded = token != ""
+}
+
+// Stores the user's tokens, and makes requests with the new access token
+func (app *CLIApp) storeTokens(creds models.Credentials) error {
+	if err := auth.StoreTokens(creds, app.Config.ConfigFP); err != nil {
+		return err
+	}
+	setAccessToken(creds.AccessToken)
+	return nil
+}
+
+// Removes the user's stored credentials, and stops sending their access token
+func (app *CLIApp) removeCreds() error {
+	if err := auth.RemoveCreds(app.Config.ConfigFP); err != nil {
+		return err
 	}
-	return resp, err
+	setAccessToken("")
+	return nil
 }
 
 // Refreshs JWT and refresh token for user - logs user out automatically if session is expired
 func refreshCreds(app *CLIApp) error {
-	refreshURL := app.Config.Client.BaseURL + "/api/auth/refresh"
-
 	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
 		return err
@@ -43,43 +90,31 @@ func refreshCreds(app *CLIApp) error {
 		RefreshToken: creds.RefreshToken,
 	}
 
-	resp, err := app.Config.MakeBasicRequest("POST", refreshURL, "", request)
+	response, err := app.Config.Client.Refresh(context.Background(), request)
 	if err != nil {
-		return fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 500 {
-		fmt.Println("Server error")
-		return nil
-	}
+		apiErr, ok := client.AsAPIError(err)
+		if !ok {
+			return fmt.Errorf("error making request: %w", err)
+		}
 
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		var errResp struct {
-			Error string `json:"error"`
+		if apiErr.StatusCode >= 500 {
+			fmt.Println("Server error")
+			return nil
 		}
-		if err = json.Unmarshal(body, &errResp); err == nil {
-			if errResp.Error == "Token is expired" {
-				fmt.Println(" < User's session is expired, please re-login. > ")
-				if err = app.commandUserLogout(&cobra.Command{Use: "auto-logout"}); err != nil {
-					return fmt.Errorf("error logging user out: %w", err)
-				}
+
+		if apiErr.Code == models.ErrCodeTokenExpired || apiErr.Message == "Token is expired" {
+			fmt.Println(" < User's session is expired, please re-login. > ")
+			if err = app.commandUserLogout(&cobra.Command{Use: "auto-logout"}); err != nil {
+				return fmt.Errorf("error logging user out: %w", err)
 			}
 		}
 		return nil
 	}
 
-	var response models.RefreshResponse
-
-	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return fmt.Errorf("error decoding response data: %w", err)
-	}
-
 	creds.AccessToken = response.AccessToken
 	creds.RefreshToken = response.RefreshToken
 
-	err = auth.StoreTokens(creds, app.Config.ConfigFP)
+	err = app.storeTokens(creds)
 	if err != nil {
 		return fmt.Errorf("error storing new tokens: %w", err)
 	}
@@ -4,36 +4,61 @@ import (
 	"log"
 
 	"github.com/jms-guy/greed/cli/internal/config"
+	"github.com/jms-guy/greed/cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 // App struct holding CLI config, as well as all command methods
 type CLIApp struct {
 	Config *config.Config
+	Output output.Format // Output format for listing commands, set by the global --output flag
 }
 
-// Initializes a new app struct
+// Initializes a new app struct. Configuration is loaded once flags are parsed, as it depends on the --profile flag
 func NewCLIApp() *CLIApp {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		log.Fatalf("Error loading configuration: %s", err)
-	}
 	app := CLIApp{
-		Config: cfg,
+		Output: output.Table,
 	}
 
 	return &app
 }
 
+// Loads configuration for the selected profile
+func (app *CLIApp) loadConfig(profile string) {
+	cfg, err := config.LoadConfig(profile)
+	if err != nil {
+		log.Fatalf("Error loading configuration: %s", err)
+	}
+	app.Config = cfg
+	app.configureClientAuth()
+}
+
 // Initializes cobra commands
 func (app *CLIApp) RootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "greed",
 		Short: "Greed is a CLI tool for tracking user's financial data",
 		Long:  "Greed is a CLI tool for tracking user's financial data, through accessing financial institutions",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString("profile")
+			app.loadConfig(profile)
+
+			format, _ := cmd.Flags().GetString("output")
+			parsed, err := output.ParseFormat(format)
+			if err != nil {
+				LogError(app.Config.Db, cmd, err, "Invalid output format")
+				return err
+			}
+			app.Output = parsed
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 		},
+		Version: cliVersion(),
 	}
+	rootCmd.PersistentFlags().String("profile", "", "Profile to use for this command, overriding the current profile and "+config.ProfileEnv)
+	rootCmd.PersistentFlags().StringP("output", "o", string(output.Table), "Output format for listing commands [table | json | csv | tsv]")
+
 	dCmd := app.deleteCmd()
 	// dCmd.AddCommand(app.deleteUserCmd())
 	dCmd.AddCommand(app.deleteItemCmd())
@@ -48,9 +73,70 @@ func (app *CLIApp) RootCmd() *cobra.Command {
 	sdCmd.AddCommand(app.defaultAccountCmd())
 	sdCmd.AddCommand(app.clearDefaultsCmd())
 
+	pCmd := app.profileCmd()
+	pCmd.AddCommand(app.profileAddCmd())
+	pCmd.AddCommand(app.profileUseCmd())
+	pCmd.AddCommand(app.profileListCmd())
+	pCmd.AddCommand(app.profileRemoveCmd())
+
+	aCmd := app.alertsCmd()
+	aCmd.AddCommand(app.alertsAckCmd())
+
+	mCmd := app.merchantsCmd()
+	mCmd.AddCommand(app.merchantsAliasesCmd())
+	mCmd.AddCommand(app.merchantsAliasCmd())
+	mCmd.AddCommand(app.merchantsUnaliasCmd())
+
+	nCmd := app.notifyCmd()
+	nCmd.AddCommand(app.notifyAddCmd())
+	nCmd.AddCommand(app.notifyListCmd())
+	nCmd.AddCommand(app.notifyRemoveCmd())
+	nCmd.AddCommand(app.notifyLogCmd())
+	nCmd.AddCommand(app.notifyRetryCmd())
+
+	eCmd := app.eventsCmd()
+	eCmd.AddCommand(app.eventsListCmd())
+	eCmd.AddCommand(app.eventsSubscribeCmd())
+	eCmd.AddCommand(app.eventsEndpointsCmd())
+	eCmd.AddCommand(app.eventsUnsubscribeCmd())
+	eCmd.AddCommand(app.eventsDeliveriesCmd())
+	eCmd.AddCommand(app.eventsReplayCmd())
+
+	acCmd := app.accountCmd()
+	acCmd.AddCommand(app.accountCreateCmd())
+	acCmd.AddCommand(app.accountRenameCmd())
+	acCmd.AddCommand(app.accountHideCmd())
+	acCmd.AddCommand(app.accountArchiveCmd())
+	acCmd.AddCommand(app.accountNetWorthCmd())
+	acCmd.AddCommand(app.accountOrderCmd())
+
+	tCmd := app.txnCmd()
+	tCmd.AddCommand(app.txnAddCmd())
+	tCmd.AddCommand(app.txnEditCmd())
+	tCmd.AddCommand(app.txnRemoveCmd())
+	tCmd.AddCommand(app.txnNoteCmd())
+	tCmd.AddCommand(app.txnAttachCmd())
+	tCmd.AddCommand(app.txnAttachmentsCmd())
+	tCmd.AddCommand(app.txnDownloadCmd())
+	tCmd.AddCommand(app.txnOpenCmd())
+	tCmd.AddCommand(app.txnDetachCmd())
+
+	kCmd := app.apiKeyCmd()
+	kCmd.AddCommand(app.apiKeyCreateCmd())
+	kCmd.AddCommand(app.apiKeyListCmd())
+	kCmd.AddCommand(app.apiKeyRevokeCmd())
+
 	rootCmd.AddCommand(dCmd)
 	rootCmd.AddCommand(gCmd)
 	rootCmd.AddCommand(sdCmd)
+	rootCmd.AddCommand(pCmd)
+	rootCmd.AddCommand(aCmd)
+	rootCmd.AddCommand(mCmd)
+	rootCmd.AddCommand(nCmd)
+	rootCmd.AddCommand(eCmd)
+	rootCmd.AddCommand(kCmd)
+	rootCmd.AddCommand(acCmd)
+	rootCmd.AddCommand(tCmd)
 	rootCmd.AddCommand(app.pingCmd())
 	rootCmd.AddCommand(app.registerCmd())
 	rootCmd.AddCommand(app.loginCmd())
@@ -65,8 +151,15 @@ func (app *CLIApp) RootCmd() *cobra.Command {
 	rootCmd.AddCommand(app.renameCmd())
 	rootCmd.AddCommand(app.infoCmd())
 	rootCmd.AddCommand(app.exportDataCmd())
+	rootCmd.AddCommand(app.compareCmd())
+	rootCmd.AddCommand(app.holdingsCmd())
+	rootCmd.AddCommand(app.debtsCmd())
 	rootCmd.AddCommand(app.addItemCmd())
 	rootCmd.AddCommand(app.logsCmd())
+	rootCmd.AddCommand(app.diagnoseCmd())
+	rootCmd.AddCommand(app.uiCmd())
+	rootCmd.AddCommand(app.lockCmd())
+	rootCmd.AddCommand(app.agentCmd())
 
 	// Error handling is being done by custom LogErrors function in commands
 	rootCmd.SilenceErrors = true
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/jms-guy/greed/cli/internal/auth"
@@ -40,13 +39,9 @@ func (app *CLIApp) commandSetDefaultAccount(cmd *cobra.Command, args []string) e
 		return err
 	}
 
-	params := database.GetAccountParams{
-		Name:   accountName,
-		UserID: creds.User.ID.String(),
-	}
-	account, err := app.Config.Db.GetAccount(context.Background(), params)
+	account, err := getLocalAccount(app, creds.User.ID.String(), accountName)
 	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error getting local account: %w", err), "Local database error")
+		LogError(app.Config.Db, cmd, err, "Error finding account")
 		return err
 	}
 
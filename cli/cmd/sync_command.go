@@ -1,19 +1,40 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"os"
+	"sync"
 
 	"github.com/jms-guy/greed/cli/internal/auth"
+	"github.com/jms-guy/greed/cli/internal/output"
+	"github.com/jms-guy/greed/cli/internal/progress"
+	"github.com/jms-guy/greed/cli/internal/resolve"
+	"github.com/jms-guy/greed/cli/internal/tables"
+	"github.com/jms-guy/greed/client"
+	"github.com/jms-guy/greed/models"
 	"github.com/spf13/cobra"
 )
 
 // Syncs database with updated account balances, and transaction records
-// Updates account balances, and deletes local transaction records before replacing them with
-// updated records from server database.
+// Updates account balances, and applies the transactions added, modified and removed since the last sync
+// from the server's change feed. With --full, local transaction records are rebuilt from the start of the feed.
+// With --all, every item is synced at once
 func (app *CLIApp) commandSync(cmd *cobra.Command, args []string) error {
-	itemName := args[0]
-	itemsURL := app.Config.Client.BaseURL + "/api/items"
+	full, _ := cmd.Flags().GetBool("full")
+	all, _ := cmd.Flags().GetBool("all")
+
+	if all && len(args) > 0 {
+		err := fmt.Errorf("an item name can't be given with --all")
+		LogError(app.Config.Db, cmd, err, "Invalid arguments")
+		return err
+	}
+	if !all && len(args) == 0 {
+		err := fmt.Errorf("an item name or --all is required")
+		LogError(app.Config.Db, cmd, err, "Invalid arguments")
+		return err
+	}
 
 	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
@@ -21,47 +42,213 @@ func (app *CLIApp) commandSync(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Get item ID and institution
-	itemID, itemInst, err := findItemHelper(app, itemName, itemsURL)
+	if all {
+		workers, _ := cmd.Flags().GetInt("workers")
+		return app.commandSyncAll(cmd, creds, workers, full)
+	}
+
+	item, err := getItemFromServer(app, args[0])
 	if err != nil {
-		if strings.Contains(err.Error(), "no item found") {
+		if errors.Is(err, resolve.ErrNoMatch) {
 			LogError(app.Config.Db, cmd, err, "No item found")
 			return err
 		} else {
-			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			LogError(app.Config.Db, cmd, err, "Error getting item")
 			return err
 		}
 	}
 
-	err = syncAccountBalances(app, creds, itemID, itemInst)
+	accounts, err := syncItemRemote(app, item.ItemId, printReporter{})
 	if err != nil {
-		LogError(app.Config.Db, cmd, err, "Error syncing account data")
+		LogError(app.Config.Db, cmd, err, "Error syncing item")
 		return err
 	}
 
-	fmt.Println(" > Fetching transaction data...")
+	err = storeAccountBalances(app, creds, accounts, item.InstitutionName)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error syncing account data")
+		return err
+	}
 
-	err = processWebhookRecords(app, itemID, "ITEM", "DEFAULT_UPDATE")
+	err = syncAccountOverrides(app, creds)
 	if err != nil {
-		LogError(app.Config.Db, cmd, err, "Error processing webhooks")
+		LogError(app.Config.Db, cmd, err, "Error syncing account settings")
 		return err
 	}
 
-	err = syncTransactionData(app, creds, itemID)
+	fmt.Println(" > Account balances synced successfully.")
+	fmt.Println(" > Syncing transaction records...")
+
+	counts, err := syncTransactionData(app, creds, full)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error syncing transaction data")
 		return err
 	}
 
-	webhookCodes := []string{"TRANSACTIONS_UPDATES_AVAILABLE", "TRANSACTIONS_REMOVED", "DEFAULT_UPDATE", "INITIAL_UPDATE", "HISTORICAL_UPDATE", "SYNC_UPDATES_AVAILABLE", "RECURRING_TRANSACTIONS_UPDATE"}
+	total := totalChanges(counts)
+	fmt.Printf(" > Transaction records synced successfully: %d added, %d modified, %d removed.\n", total.Added, total.Modified, total.Removed)
+
+	printAlertsHint(app)
+
+	return nil
+}
+
+// Reports an item's sync progress on its line of a progress board, keeping notes for after the board is done
+type boardReporter struct {
+	board *progress.Board
+	index int
+	notes []string
+}
+
+func (r *boardReporter) Step(msg string) { r.board.Update(r.index, msg+"...") }
+func (r *boardReporter) Note(msg string) { r.notes = append(r.notes, msg) }
+
+// Syncs every item of the user's. Items are synced with Plaid on the server by a pool of workers, with
+// progress shown for each item, and an item failing doesn't stop the others. Local records are then
+// updated, and a summary of the transactions changed for each item is printed
+func (app *CLIApp) commandSyncAll(cmd *cobra.Command, creds models.Credentials, workers int, full bool) error {
+	if workers < 1 {
+		err := fmt.Errorf("workers must be at least 1, got %d", workers)
+		LogError(app.Config.Db, cmd, err, "Invalid flag")
+		return err
+	}
+
+	items, err := app.Config.Client.GetItems(context.Background())
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		return err
+	}
+	cacheItems(app, items)
+	if len(items) == 0 {
+		fmt.Println(" < No items to sync > ")
+		return nil
+	}
+
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Nickname
+	}
+
+	board := progress.NewBoard(os.Stdout, output.IsTerminal(), names)
+	reporters := make([]*boardReporter, len(items))
+	accounts := make([]models.Accounts, len(items))
+	errs := make([]error, len(items))
+
+	// Credentials are loaded before the workers start, so they share the loaded access token
+	app.loadAccessToken()
 
-	for _, code := range webhookCodes {
-		err = processWebhookRecords(app, itemID, code, "TRANSACTIONS")
+	// Workers only talk to the server, local records are written below once they are done
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				reporters[i] = &boardReporter{board: board, index: i}
+				accounts[i], errs[i] = syncItemRemote(app, items[i].ItemId, reporters[i])
+				if errs[i] != nil {
+					board.Fail(i, syncFailureStatus(errs[i]))
+					continue
+				}
+				board.Done(i, "synced")
+			}
+		}()
+	}
+	for i := range items {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	board.Stop()
+
+	results := make([]tables.SyncResult, len(items))
+	itemByAccount := map[string]int{}
+	synced := 0
+	for i, item := range items {
+		results[i] = tables.SyncResult{Item: item.Nickname, Err: errs[i]}
+		if errs[i] != nil {
+			continue
+		}
+		if err := storeAccountBalances(app, creds, accounts[i], item.InstitutionName); err != nil {
+			results[i].Err = err
+			continue
+		}
+		for _, acc := range accounts[i].Accounts {
+			itemByAccount[acc.Id] = i
+		}
+		synced++
+	}
+
+	// Transaction changes are read for the user as a whole, so they are applied once every item has synced
+	if synced > 0 {
+		if err := syncAccountOverrides(app, creds); err != nil {
+			LogError(app.Config.Db, cmd, err, "Error syncing account settings")
+			return err
+		}
+
+		fmt.Println(" > Syncing transaction records...")
+
+		counts, err := syncTransactionData(app, creds, full)
 		if err != nil {
-			LogError(app.Config.Db, cmd, err, "Error contacting server")
+			LogError(app.Config.Db, cmd, err, "Error syncing transaction data")
 			return err
 		}
+
+		for accountID, c := range counts {
+			i, ok := itemByAccount[accountID]
+			if !ok {
+				continue
+			}
+			results[i].Added += c.Added
+			results[i].Modified += c.Modified
+			results[i].Removed += c.Removed
+		}
+	}
+
+	fmt.Println("")
+	tables.MakeTableForSyncSummary(results).Print()
+	fmt.Println("")
+
+	for i, r := range reporters {
+		if r == nil {
+			continue
+		}
+		for _, note := range r.notes {
+			fmt.Printf(" < %s: %s > \n", items[i].Nickname, note)
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		failed++
+		LogError(app.Config.Db, cmd, r.Err, fmt.Sprintf("Error syncing %s", r.Item))
+	}
+
+	printAlertsHint(app)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d items failed to sync", failed, len(items))
 	}
 
 	return nil
 }
+
+// Short description of why an item failed to sync, for its progress line
+func syncFailureStatus(err error) string {
+	if apiErr, ok := client.AsAPIError(err); ok {
+		return apiErr.Message
+	}
+	return "failed"
+}
+
+// Points out unusual transactions turned up by a sync, without failing it if they can't be fetched
+func printAlertsHint(app *CLIApp) {
+	anomalies, err := app.Config.Client.GetAnomalies(context.Background(), false)
+	if err == nil && len(anomalies) > 0 {
+		fmt.Printf(" < %d unacknowledged alerts, see `greed alerts` > \n", len(anomalies))
+	}
+}
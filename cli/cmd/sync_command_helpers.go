@@ -3,124 +3,89 @@ package cmd
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/jms-guy/greed/cli/internal/auth"
 	"github.com/jms-guy/greed/cli/internal/database"
 	"github.com/jms-guy/greed/cli/internal/utils"
+	"github.com/jms-guy/greed/client"
 	"github.com/jms-guy/greed/models"
 )
 
-// Gets list of items from server, finds a specific item based on name and returns the ID and institution
-func findItemHelper(app *CLIApp, itemName, itemsURL string) (string, string, error) {
-	res, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("GET", itemsURL, token, nil)
-	})
-	if err != nil {
-		return "", "", fmt.Errorf("error making http request: %w", err)
-	}
-	defer res.Body.Close()
-
-	err = checkResponseStatus(res)
-	if err != nil {
-		return "", "", err
-	}
-
-	var itemsResp struct {
-		Items []models.ItemName `json:"items"`
-	}
-	if err = json.NewDecoder(res.Body).Decode(&itemsResp); err != nil {
-		return "", "", fmt.Errorf("error decoding response data: %w", err)
-	}
-
-	var itemID string
-	var itemInst string
-	for _, i := range itemsResp.Items {
-		if i.Nickname == itemName {
-			itemID = i.ItemId
-			itemInst = i.InstitutionName
-			break
-		}
-	}
-
-	if itemID == "" {
-		return "", "", fmt.Errorf("no item found with name: %s", itemName)
-	}
-
-	return itemID, itemInst, nil
-}
-
-// Slightly more in depth status code handling for sync command http responses
-func parseAndReturnServerError(resp *http.Response) error {
-	if resp.StatusCode != http.StatusOK {
-		var serverErr map[string]string
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&serverErr); decodeErr == nil && serverErr["error"] != "" {
-			return fmt.Errorf("server error syncing transactions (status %d): %s", resp.StatusCode, serverErr["error"])
-		}
-		return fmt.Errorf("server returned non-OK status for transactions: %s", resp.Status)
-	}
-
-	return nil
-}
-
 // Makes server request to process webhooks of a certain type
 func processWebhookRecords(app *CLIApp, itemID, webhookCode, webhookType string) error {
-	webhookURL := app.Config.Client.BaseURL + "/api/items/webhook-records"
-
 	request := models.ProcessWebhook{
 		ItemID:      itemID,
 		WebhookCode: webhookCode,
 		WebhookType: webhookType,
 	}
 
-	res, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("PUT", webhookURL, token, request)
-	})
+	err := app.Config.Client.ProcessWebhookRecords(context.Background(), request)
 	if err != nil {
 		return fmt.Errorf("error making http request: %w", err)
 	}
-	defer res.Body.Close()
-
-	err = checkResponseStatus(res)
-	if err != nil {
-		return err
-	}
 
 	return nil
 }
 
-// Function updates user's account records with fresh balance data obtained from Plaid
-func syncAccountBalances(app *CLIApp, creds models.Credentials, itemID, itemInst string) error {
-	updateBalanceURL := app.Config.Client.BaseURL + "/api/items/" + itemID + "/access/balances"
+// Receives progress from an item's sync. Steps describe what the sync is doing, notes are things the user
+// should know once it is over, such as data the item wasn't linked with access to
+type syncReporter interface {
+	Step(msg string)
+	Note(msg string)
+}
 
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("PUT", updateBalanceURL, token, nil)
-	})
+// Reports an item's sync progress by printing it as it happens
+type printReporter struct{}
+
+func (printReporter) Step(msg string) { fmt.Printf(" > %s...\n", msg) }
+func (printReporter) Note(msg string) { fmt.Printf(" < %s > \n", msg) }
+
+// Webhook codes cleared once an item's transactions have been synced
+var transactionWebhookCodes = []string{"TRANSACTIONS_UPDATES_AVAILABLE", "TRANSACTIONS_REMOVED", "DEFAULT_UPDATE", "INITIAL_UPDATE", "HISTORICAL_UPDATE", "SYNC_UPDATES_AVAILABLE", "RECURRING_TRANSACTIONS_UPDATE"}
+
+// Has the server sync an item with Plaid: refreshing its balances, pulling its transactions, investments and
+// liabilities, and clearing its webhook records. Returns the refreshed accounts. Nothing is written to the
+// local database, so several items can sync at once
+func syncItemRemote(app *CLIApp, itemID string, report syncReporter) (models.Accounts, error) {
+	ctx := context.Background()
+
+	report.Step("Syncing account balances")
+	accounts, err := app.Config.Client.UpdateBalances(ctx, itemID)
 	if err != nil {
-		return fmt.Errorf("error making http request: %w", err)
+		return models.Accounts{}, fmt.Errorf("error syncing account balances: %w", err)
 	}
-	defer resp.Body.Close()
 
-	var accUpdates models.Accounts
+	report.Step("Fetching transaction data")
+	if err := processWebhookRecords(app, itemID, "ITEM", "DEFAULT_UPDATE"); err != nil {
+		return models.Accounts{}, fmt.Errorf("error processing webhooks: %w", err)
+	}
 
-	serverErr := parseAndReturnServerError(resp)
-	if serverErr != nil {
-		return serverErr
+	if _, err := app.Config.Client.SyncTransactions(ctx, itemID); err != nil {
+		return models.Accounts{}, fmt.Errorf("error syncing transactions: %w", err)
 	}
-	if err = json.NewDecoder(resp.Body).Decode(&accUpdates); err != nil {
-		if err == io.EOF {
-			return fmt.Errorf("decoding err: %w", err)
+
+	for _, code := range transactionWebhookCodes {
+		if err := processWebhookRecords(app, itemID, code, "TRANSACTIONS"); err != nil {
+			return models.Accounts{}, fmt.Errorf("error processing webhooks: %w", err)
 		}
-		return fmt.Errorf("decoding err: %w", err)
 	}
 
-	fmt.Println(" > Syncing account balances...")
+	if err := syncInvestmentData(app, itemID, report); err != nil {
+		return models.Accounts{}, err
+	}
 
+	if err := syncLiabilityData(app, itemID, report); err != nil {
+		return models.Accounts{}, err
+	}
+
+	return accounts, nil
+}
+
+// Function updates user's account records with fresh balance data obtained from Plaid
+func storeAccountBalances(app *CLIApp, creds models.Credentials, accUpdates models.Accounts, itemInst string) error {
 	for _, acc := range accUpdates.Accounts {
 		avBalance := sql.NullFloat64{}
 		if acc.AvailableBalance != "" {
@@ -156,56 +121,173 @@ func syncAccountBalances(app *CLIApp, creds models.Credentials, itemID, itemInst
 			InstitutionName:  sql.NullString{String: itemInst, Valid: true},
 			UserID:           creds.User.ID.String(),
 		}
-		_, err = app.Config.Db.UpsertAccount(context.Background(), params)
+		_, err := app.Config.Db.UpsertAccount(context.Background(), params)
 		if err != nil {
 			return fmt.Errorf("error upserting account record: %w", err)
 		}
 	}
 
-	fmt.Println(" > Account balances synced successfully.")
 	return nil
 }
 
-// Function deletes local transaction records for an item, replacing them with updated data fetched
-// from Plaid and updated server-side
-func syncTransactionData(app *CLIApp, creds models.Credentials, itemID string) error {
-	syncTxnsURL := app.Config.Client.BaseURL + "/api/items/" + itemID + "/access/transactions"
-
-	response, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("POST", syncTxnsURL, token, nil)
-	})
+// Copies the nicknames, visibility and ordering set for the user's accounts on the server into local records,
+// so renaming or hiding an account from another device shows up after a sync. Manual accounts belong to no
+// item, so their records and balances are stored here too
+func syncAccountOverrides(app *CLIApp, creds models.Credentials) error {
+	accounts, err := app.Config.Client.GetAllAccounts(context.Background())
 	if err != nil {
-		return fmt.Errorf("error making http request: %w", err)
+		return fmt.Errorf("error getting accounts: %w", err)
+	}
+
+	for _, acc := range accounts {
+		if acc.Manual {
+			if err := storeAccountBalances(app, creds, models.Accounts{Accounts: []models.Account{acc}}, manualInstitutionName); err != nil {
+				return err
+			}
+		}
+		if err := storeAccountOverrides(app, creds.User.ID.String(), acc); err != nil {
+			return err
+		}
 	}
-	defer response.Body.Close()
 
-	serverErr := parseAndReturnServerError(response)
-	if serverErr != nil {
-		return serverErr
+	return nil
+}
+
+// Stores an account's overrides, as returned by the server, in its local record
+func storeAccountOverrides(app *CLIApp, userID string, acc models.Account) error {
+	params := database.SetAccountOverridesParams{
+		Nickname:          sql.NullString{String: acc.Nickname, Valid: acc.Nickname != ""},
+		Hidden:            acc.Hidden,
+		Archived:          acc.Archived,
+		IncludeInNetWorth: acc.IncludeInNetWorth,
+		SortOrder:         int64(acc.SortOrder),
+		ID:                acc.Id,
+		UserID:            userID,
+	}
+	if err := app.Config.Db.SetAccountOverrides(context.Background(), params); err != nil {
+		return fmt.Errorf("error updating account overrides: %w", err)
 	}
 
-	var txns []models.Transaction
-	if err = json.NewDecoder(response.Body).Decode(&txns); err != nil {
-		if err == io.EOF {
-			return fmt.Errorf("decoding err: %w", err)
+	return nil
+}
+
+// Function applies the changes made to the user's transactions since the last sync to the local database,
+// returning how many transactions of each account changed. A full sync clears local transaction records
+// and reads the change feed from the start
+func syncTransactionData(app *CLIApp, creds models.Credentials, full bool) (map[string]changeCounts, error) {
+	if full {
+		if err := resetTransactionSync(app, creds.User.ID.String()); err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("decoding err: %w", err)
 	}
 
-	fmt.Println(" > Syncing transaction records...")
+	return applyTransactionChanges(app, creds.User.ID.String())
+}
+
+// Deletes the user's local transaction records and sync cursor together, so the next sync reads every change
+func resetTransactionSync(app *CLIApp, userID string) error {
+	ctx := context.Background()
 
-	err = app.Config.Db.DeleteTransactions(context.Background(), creds.User.ID.String())
+	tx, err := app.Config.Conn.BeginTx(ctx, nil)
 	if err != nil {
+		return fmt.Errorf("error beginning local database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := app.Config.Db.WithTx(tx)
+
+	if err := qtx.DeleteTransactions(ctx, userID); err != nil {
 		return fmt.Errorf("error clearing local records: %w", err)
 	}
+	if err := qtx.DeleteSyncCursor(ctx, userID); err != nil {
+		return fmt.Errorf("error clearing sync cursor: %w", err)
+	}
 
-	for _, t := range txns {
+	return tx.Commit()
+}
+
+// Numbers of transactions added, modified and removed by a sync
+type changeCounts struct {
+	Added    int
+	Modified int
+	Removed  int
+}
+
+// Sums change counts across accounts
+func totalChanges(counts map[string]changeCounts) changeCounts {
+	var total changeCounts
+	for _, c := range counts {
+		total.Added += c.Added
+		total.Modified += c.Modified
+		total.Removed += c.Removed
+	}
+	return total
+}
+
+// Reads the server's transaction change feed from the stored cursor, returning change counts by account ID.
+// Each page is applied together with its cursor in one local database transaction, so an interrupted sync
+// resumes after the last page applied
+func applyTransactionChanges(app *CLIApp, userID string) (map[string]changeCounts, error) {
+	ctx := context.Background()
+	counts := map[string]changeCounts{}
+
+	cursor, err := app.Config.Db.GetSyncCursor(ctx, userID)
+	if err != nil && err != sql.ErrNoRows {
+		return counts, fmt.Errorf("error getting sync cursor: %w", err)
+	}
+
+	for {
+		changes, err := app.Config.Client.GetTransactionChanges(ctx, cursor, 0)
+		if err != nil {
+			return counts, fmt.Errorf("error getting transaction changes: %w", err)
+		}
+
+		if err := applyTransactionPage(app, userID, changes); err != nil {
+			return counts, err
+		}
+
+		for _, t := range changes.Added {
+			c := counts[t.AccountId]
+			c.Added++
+			counts[t.AccountId] = c
+		}
+		for _, t := range changes.Modified {
+			c := counts[t.AccountId]
+			c.Modified++
+			counts[t.AccountId] = c
+		}
+		for _, t := range changes.Removed {
+			c := counts[t.AccountId]
+			c.Removed++
+			counts[t.AccountId] = c
+		}
+		cursor = changes.NextCursor
+
+		if !changes.HasMore {
+			return counts, nil
+		}
+	}
+}
+
+// Applies a page of transaction changes and stores its cursor, in a single local database transaction
+func applyTransactionPage(app *CLIApp, userID string, changes models.TransactionChanges) error {
+	ctx := context.Background()
+
+	tx, err := app.Config.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning local database transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := app.Config.Db.WithTx(tx)
+
+	for _, t := range append(changes.Added, changes.Modified...) {
 		a, err := strconv.ParseFloat(t.Amount, 64)
 		if err != nil {
 			return fmt.Errorf("error converting string value: %w", err)
 		}
 
-		params := database.CreateTransactionParams{
+		params := database.UpsertTransactionParams{
 			ID:                      t.Id,
 			AccountID:               t.AccountId,
 			Amount:                  a,
@@ -216,46 +298,41 @@ func syncTransactionData(app *CLIApp, creds models.Credentials, itemID string) e
 			PersonalFinanceCategory: t.PersonalFinanceCategory,
 		}
 
-		_, err = app.Config.Db.CreateTransaction(context.Background(), params)
-		if err != nil {
-			return fmt.Errorf("error creating local records: %w", err)
+		if err := qtx.UpsertTransaction(ctx, params); err != nil {
+			return fmt.Errorf("error updating local records: %w", err)
 		}
-
-		fmt.Printf("\r > %v", t.Id)
 	}
 
-	fmt.Println("\r > Transaction records synced successfully.")
-	return nil
-}
-
-// Goes through Plaid's Link update mode
-func linkUpdateModeFlow(app *CLIApp, itemID string) error {
-	linkURL := app.Config.Client.BaseURL + "/plaid/get-link-token-update/" + itemID
-	redirectURL := app.Config.Client.BaseURL + "/link-update-mode"
+	for _, t := range changes.Removed {
+		if err := qtx.DeleteTransaction(ctx, t.Id); err != nil {
+			return fmt.Errorf("error deleting local record: %w", err)
+		}
+	}
 
-	linkRes, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("POST", linkURL, token, nil)
+	err = qtx.UpsertSyncCursor(ctx, database.UpsertSyncCursorParams{
+		UserID:    userID,
+		Cursor:    changes.NextCursor,
+		UpdatedAt: time.Now().Format(time.RFC3339),
 	})
 	if err != nil {
-		return fmt.Errorf("error making http request: %w", err)
+		return fmt.Errorf("error storing sync cursor: %w", err)
 	}
-	defer linkRes.Body.Close()
 
-	serverErr := parseAndReturnServerError(linkRes)
-	if serverErr != nil {
-		return serverErr
-	}
+	return tx.Commit()
+}
 
-	var response models.LinkResponse
-	if err = json.NewDecoder(linkRes.Body).Decode(&response); err != nil {
-		return fmt.Errorf("decoding err: %w", err)
+// Goes through Plaid's Link update mode
+func linkUpdateModeFlow(app *CLIApp, itemID string) error {
+	response, err := app.Config.Client.GetLinkTokenForUpdateMode(context.Background(), itemID)
+	if err != nil {
+		return fmt.Errorf("error getting link token: %w", err)
 	}
 
 	if response.LinkToken == "" {
 		return fmt.Errorf("backend did not return a link token for item update")
 	}
 
-	fullBrowserURL := fmt.Sprintf("%s?token=%s", redirectURL, response.LinkToken)
+	fullBrowserURL := app.Config.Client.LinkUpdateModeURL(response.LinkToken)
 	fmt.Printf("Opening Plaid Link in your browser to update item with ID '%s'. Please complete the flow.\n", itemID)
 	fmt.Printf("If the browser does not open automatically, please navigate to: %s\n", fullBrowserURL)
 
@@ -280,3 +357,72 @@ func linkUpdateModeFlow(app *CLIApp, itemID string) error {
 
 	return nil
 }
+
+// Syncs holdings and investment transactions for an item with investment accounts. Items linked without
+// investments access are skipped with a note, rather than failing the sync
+func syncInvestmentData(app *CLIApp, itemID string, report syncReporter) error {
+	ctx := context.Background()
+
+	accounts, err := app.Config.Client.GetAccountsForItem(ctx, itemID)
+	if err != nil {
+		return fmt.Errorf("error getting item accounts: %w", err)
+	}
+
+	hasInvestments := false
+	for _, acc := range accounts {
+		if acc.Type == "investment" {
+			hasInvestments = true
+			break
+		}
+	}
+	if !hasInvestments {
+		return nil
+	}
+
+	report.Step("Syncing investment holdings")
+
+	_, err = app.Config.Client.SyncInvestments(ctx, itemID)
+	if apiErr, ok := client.AsAPIError(err); ok && apiErr.Code == models.ErrCodeInvestmentsUnavailable {
+		report.Note("No investment data available for this item, run `greed update <item-name>` to grant access")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error syncing investment data: %w", err)
+	}
+
+	return nil
+}
+
+// Syncs an item's credit card and loan details, if it has any credit or loan accounts
+func syncLiabilityData(app *CLIApp, itemID string, report syncReporter) error {
+	ctx := context.Background()
+
+	accounts, err := app.Config.Client.GetAccountsForItem(ctx, itemID)
+	if err != nil {
+		return fmt.Errorf("error getting item accounts: %w", err)
+	}
+
+	hasDebts := false
+	for _, acc := range accounts {
+		if acc.Type == "credit" || acc.Type == "loan" {
+			hasDebts = true
+			break
+		}
+	}
+	if !hasDebts {
+		return nil
+	}
+
+	report.Step("Syncing credit card and loan details")
+
+	_, err = app.Config.Client.SyncLiabilities(ctx, itemID)
+	if apiErr, ok := client.AsAPIError(err); ok && apiErr.Code == models.ErrCodeLiabilitiesUnavailable {
+		report.Note("No liability data available for this item, run `greed update <item-name>` to grant access")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error syncing liability data: %w", err)
+	}
+
+	return nil
+}
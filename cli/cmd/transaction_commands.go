@@ -2,10 +2,8 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"math"
-	"net/http"
 	"slices"
 	"strconv"
 
@@ -22,7 +20,7 @@ import (
 // If summary flag is present, overrides most other flags, and returns a transaction summary instead
 func (app *CLIApp) commandGetTxnsAccount(cmd *cobra.Command, args []string, merchant, category, channel, date, start, end, order string, min, max, limit, pageSize int, summary bool) error {
 	var err error
-	queryString := utils.BuildQueries(merchant, category, channel, date, start, end, min, max, limit, summary)
+	query := utils.BuildQueries(merchant, category, channel, date, start, end, min, max, limit, summary)
 
 	var account database.Account
 
@@ -41,13 +39,9 @@ func (app *CLIApp) commandGetTxnsAccount(cmd *cobra.Command, args []string, merc
 			return err
 		}
 
-		params := database.GetAccountParams{
-			Name:   accountName,
-			UserID: creds.User.ID.String(),
-		}
-		account, err = app.Config.Db.GetAccount(context.Background(), params)
+		account, err = getLocalAccount(app, creds.User.ID.String(), accountName)
 		if err != nil {
-			LogError(app.Config.Db, cmd, fmt.Errorf("error getting local account: %w", err), "Local database error")
+			LogError(app.Config.Db, cmd, err, "Error finding account")
 			return err
 		}
 
@@ -55,36 +49,24 @@ func (app *CLIApp) commandGetTxnsAccount(cmd *cobra.Command, args []string, merc
 		account = app.Config.Settings.DefaultAccount
 	}
 
-	// Get queried transactions from server
-	txnsURL := app.Config.Client.BaseURL + "/api/accounts/" + account.ID + "/transactions"
-	if queryString != "?" {
-		txnsURL = txnsURL + queryString
-	}
-
-	res, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("GET", txnsURL, token, nil)
-	})
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http request: %w", err), "Error contacting server")
-		return err
-	}
-	defer res.Body.Close()
-
-	err = checkResponseStatus(res)
-	if err != nil {
-		LogError(app.Config.Db, cmd, err, "Error contacting server")
-		return err
-	}
-
-	// If summary flag is present, decode into summary struct and print table
+	// If summary flag is present, get merchant summaries and print table
 	if summary {
-		var summaries []models.MerchantSummary
-		if err = json.NewDecoder(res.Body).Decode(&summaries); err != nil {
-			LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
+		summaries, err := app.Config.Client.GetMerchantSummaries(context.Background(), account.ID, query)
+		if err != nil {
+			LogError(app.Config.Db, cmd, err, "Error contacting server")
 			return err
 		}
 
-		err = tables.PaginateSummariesTable(summaries, account.Name, merchant, pageSize)
+		if app.Output.IsMachine() {
+			return app.writeOutput(cmd, summaries)
+		}
+
+		if !app.usePager() {
+			tables.MakeTableForSummaries(summaries, tables.AccountDisplayName(account)).Print()
+			return nil
+		}
+
+		err = tables.PaginateSummariesTable(summaries, tables.AccountDisplayName(account), merchant, pageSize)
 		if err != nil {
 			LogError(app.Config.Db, cmd, fmt.Errorf("error creating transactions table: %w", err), "Error drawing table")
 			return err
@@ -93,13 +75,20 @@ func (app *CLIApp) commandGetTxnsAccount(cmd *cobra.Command, args []string, merc
 		return nil
 	}
 
-	// Else decode into regular transaction struct, calculate balance and determine filters
-	var txns []models.Transaction
-	if err = json.NewDecoder(res.Body).Decode(&txns); err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
+	// Else get queried transactions from server, calculate balance and determine filters
+	txns, err := app.Config.Client.GetTransactions(context.Background(), account.ID, query)
+	if err != nil {
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
+	if app.Output.IsMachine() {
+		if order == "ASC" {
+			slices.Reverse(txns)
+		}
+		return app.writeOutput(cmd, txns)
+	}
+
 	currentBalance := account.CurrentBalance.Float64
 	var historicalBalances []float64
 
@@ -130,8 +119,23 @@ func (app *CLIApp) commandGetTxnsAccount(cmd *cobra.Command, args []string, merc
 		isFiltered = false
 	}
 
+	if !app.usePager() {
+		tables.MakeTransactionsTable(txns, tables.AccountDisplayName(account), historicalBalances, isFiltered, recurring).Print()
+		return nil
+	}
+
 	// Draw paginated transactions table
-	err = tables.PaginateTransactionsTable(txns, account.Name, historicalBalances, pageSize, isFiltered, recurring)
+	actions := tables.PagerActions{
+		Tags: func(txnID string) ([]string, error) {
+			tags, err := app.Config.Client.GetTransactionTags(context.Background(), account.ID, txnID)
+			return tags.Tags, err
+		},
+		Export: func(txns []models.Transaction) (string, error) {
+			return app.exportTransactions(tables.AccountDisplayName(account), txns)
+		},
+	}
+
+	err = tables.PaginateTransactionsTable(txns, tables.AccountDisplayName(account), historicalBalances, pageSize, isFiltered, recurring, actions)
 	if err != nil {
 		LogError(app.Config.Db, cmd, fmt.Errorf("error creating transactions table: %w", err), "Error drawing table")
 		return err
@@ -142,26 +146,10 @@ func (app *CLIApp) commandGetTxnsAccount(cmd *cobra.Command, args []string, merc
 
 // Function gets recurring transaction data from server for account
 func (app *CLIApp) GetRecurringData(accountID string) (models.RecurringData, error) {
-	var recurringData models.RecurringData
-
-	recurringURL := app.Config.Client.BaseURL + "/api/accounts/" + accountID + "/transactions/recurring"
-
-	res, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("GET", recurringURL, token, nil)
-	})
+	recurringData, err := app.Config.Client.GetRecurringData(context.Background(), accountID)
 	if err != nil {
 		return recurringData, fmt.Errorf("error making http request: %w", err)
 	}
-	defer res.Body.Close()
-
-	err = checkResponseStatus(res)
-	if err != nil {
-		return recurringData, err
-	}
-
-	if err = json.NewDecoder(res.Body).Decode(&recurringData); err != nil {
-		return recurringData, fmt.Errorf("decoding err: %w", err)
-	}
 
 	return recurringData, nil
 }
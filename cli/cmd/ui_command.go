@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jms-guy/greed/cli/internal/output"
+	"github.com/jms-guy/greed/cli/internal/ui"
+	"github.com/jms-guy/greed/models"
+	"github.com/spf13/cobra"
+)
+
+// Opens the interactive dashboard, backed by the server API
+func (app *CLIApp) commandUI(cmd *cobra.Command) error {
+	if !output.IsTerminal() {
+		err := fmt.Errorf("stdout is not a terminal")
+		LogError(app.Config.Db, cmd, err, "The dashboard requires an interactive terminal")
+		return err
+	}
+
+	ctx := context.Background()
+	client := app.Config.Client
+
+	src := ui.Source{
+		Items: func() ([]models.ItemName, error) {
+			return client.GetItems(ctx)
+		},
+		Accounts: func(itemID string) ([]models.Account, error) {
+			return client.GetAccountsForItem(ctx, itemID)
+		},
+		Transactions: func(accountID string) ([]models.Transaction, error) {
+			return client.GetTransactions(ctx, accountID, nil)
+		},
+		Monetary: func(accountID string) ([]models.MonetaryData, error) {
+			return client.GetMonetaryData(ctx, accountID)
+		},
+		Recurring: func(accountID string) (models.RecurringData, error) {
+			return client.GetRecurringData(ctx, accountID)
+		},
+		SetCategory: func(accountID, transactionID, category string) (models.Transaction, error) {
+			return client.UpdateTransactionCategory(ctx, accountID, transactionID, category)
+		},
+		AddTag: func(accountID, transactionID, tag string) (models.TransactionTags, error) {
+			return client.AddTransactionTag(ctx, accountID, transactionID, tag)
+		},
+		Sync: func(item models.ItemName) error {
+			return app.commandSync(&cobra.Command{Use: "ui-sync"}, []string{item.Nickname})
+		},
+	}
+
+	if err := ui.Run(src); err != nil {
+		LogError(app.Config.Db, cmd, err, "Error running dashboard")
+		return err
+	}
+
+	return nil
+}
@@ -4,9 +4,7 @@ import (
 	"bufio"
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 
 	"github.com/jms-guy/greed/cli/internal/auth"
@@ -86,7 +84,7 @@ func registerEmailHelper() (string, error) {
 }
 
 // Gets email code from user
-func getEmailCodeHelper(app *CLIApp, userEmail, sendURL string, emailData models.EmailVerification) (string, error) {
+func getEmailCodeHelper(app *CLIApp, userEmail string, emailData models.EmailVerification) (string, error) {
 	scanner := bufio.NewScanner(os.Stdin)
 	var code string
 
@@ -107,16 +105,10 @@ func getEmailCodeHelper(app *CLIApp, userEmail, sendURL string, emailData models
 		}
 
 		if code == "resend" {
-			emailRes, err := app.Config.MakeBasicRequest("POST", sendURL, "", emailData)
+			err := app.Config.Client.SendEmailCode(context.Background(), emailData)
 			if err != nil {
 				return "", fmt.Errorf("error making http request: %w", err)
 			}
-			defer emailRes.Body.Close()
-
-			err = checkResponseStatus(emailRes)
-			if err != nil {
-				return "", err
-			}
 
 			fmt.Println(" > Please note, that some email providers (such as Outlook) may decline to accept emails from my domain.")
 			fmt.Println(" > If you are running into this issue, please try a Gmail account if possible, or continue unverified.")
@@ -130,10 +122,10 @@ func getEmailCodeHelper(app *CLIApp, userEmail, sendURL string, emailData models
 }
 
 // Helper for performing email verification flow in registering a new user
-func verifyEmailHelper(app *CLIApp, sendURL, verifyURL string, user models.User, emailData models.EmailVerification) (bool, error) {
+func verifyEmailHelper(app *CLIApp, user models.User, emailData models.EmailVerification) (bool, error) {
 	fmt.Println(" > It can take a couple of minutes for the email to be received ")
 
-	code, err := getEmailCodeHelper(app, user.Email, sendURL, emailData)
+	code, err := getEmailCodeHelper(app, user.Email, emailData)
 	if err != nil {
 		return false, err
 	}
@@ -167,22 +159,16 @@ func verifyEmailHelper(app *CLIApp, sendURL, verifyURL string, user models.User,
 		Code:   code,
 	}
 
-	verifyRes, err := app.Config.MakeBasicRequest("POST", verifyURL, "", verifyData)
+	err = app.Config.Client.VerifyEmail(context.Background(), verifyData)
 	if err != nil {
 		return false, fmt.Errorf("error making http request: %w", err)
 	}
-	defer verifyRes.Body.Close()
-
-	err = checkResponseStatus(verifyRes)
-	if err != nil {
-		return false, err
-	}
 
 	return true, nil
 }
 
 // Helper for fetching user credentials on login
-func userLoginHelper(app *CLIApp, username, loginURL string) (models.Credentials, error) {
+func userLoginHelper(app *CLIApp, username string) (models.Credentials, error) {
 	pw, err := auth.ReadPassword("Please enter your password > ")
 	if err != nil {
 		return models.Credentials{}, fmt.Errorf("error getting password: %w", err)
@@ -193,65 +179,32 @@ func userLoginHelper(app *CLIApp, username, loginURL string) (models.Credentials
 		Password: pw,
 	}
 
-	res, err := app.Config.MakeBasicRequest("POST", loginURL, "", req)
+	login, err := app.Config.Client.Login(context.Background(), req)
 	if err != nil {
 		return models.Credentials{}, fmt.Errorf("error making request: %w", err)
 	}
-	defer res.Body.Close()
-
-	err = checkResponseStatus(res)
-	if err != nil {
-		return models.Credentials{}, err
-	}
-
-	var login models.Credentials
-
-	if err = json.NewDecoder(res.Body).Decode(&login); err != nil {
-		return models.Credentials{}, fmt.Errorf("error decoding response data: %w", err)
-	}
 
 	return login, nil
 }
 
 // On user login, checks for existence of items, to know if this is a first login or not
-func userCheckItemsHelper(app *CLIApp, login models.Credentials, itemsURL string) ([]models.ItemName, error) {
-	var items []models.ItemName
-
-	itemsRes, err := app.Config.MakeBasicRequest("GET", itemsURL, login.AccessToken, nil)
-	if err != nil {
-		return items, fmt.Errorf("error making request: %w", err)
-	}
-	defer itemsRes.Body.Close()
-
-	err = checkResponseStatus(itemsRes)
+func userCheckItemsHelper(app *CLIApp, login models.Credentials) ([]models.ItemName, error) {
+	items, err := app.Config.Client.WithToken(login.AccessToken).GetItems(context.Background())
 	if err != nil {
-		return items, err
+		return nil, fmt.Errorf("error making request: %w", err)
 	}
 
-	if itemsRes.StatusCode == 200 {
-		var itemsResp struct {
-			Items []models.ItemName `json:"items"`
+	if len(items) != 0 {
+		fmt.Printf(" > Available items for user: %s\n", login.User.Name)
+		fmt.Println(" ~~~~~")
+		for _, i := range items {
+			fmt.Printf(" Institution: %s || Item Name: %s || ItemID: %s\n", i.InstitutionName, i.Nickname, i.ItemId)
 		}
-		if err = json.NewDecoder(itemsRes.Body).Decode(&itemsResp); err != nil {
-			return items, fmt.Errorf("error decoding response data: %w", err)
-		}
-
-		if len(itemsResp.Items) != 0 {
-			items = append(items, itemsResp.Items...)
+		fmt.Println("")
 
-			fmt.Printf(" > Available items for user: %s\n", login.User.Name)
-			fmt.Println(" ~~~~~")
-			for _, i := range itemsResp.Items {
-				fmt.Printf(" Institution: %s || Item Name: %s || ItemID: %s\n", i.InstitutionName, i.Nickname, i.ItemId)
-			}
-			fmt.Println("")
-
-			err = auth.StoreTokens(login, app.Config.ConfigFP)
-			if err != nil {
-				return items, fmt.Errorf("error storing auth tokens: %w", err)
-			}
-
-			return items, nil
+		err = app.storeTokens(login)
+		if err != nil {
+			return items, fmt.Errorf("error storing auth tokens: %w", err)
 		}
 	}
 
@@ -260,29 +213,18 @@ func userCheckItemsHelper(app *CLIApp, login models.Credentials, itemsURL string
 
 // On user's first time loggin in, they will go through Plaid's Link flow, consisting of: asking server for
 // Link token, opening browser link with token, getting a Public token from Plaid, and exchanging that public
-// token with the server for a permanent access token
-func userFirstTimePlaidLinkHelper(app *CLIApp, login models.Credentials, itemsURL string) (bool, error) {
-	linkURL := app.Config.Client.BaseURL + "/plaid/get-link-token"
-	redirectURL := app.Config.Client.BaseURL + "/link"
-	accessURL := app.Config.Client.BaseURL + "/plaid/get-access-token"
+// token with the server for a permanent access token. linkReq optionally narrows the countries and products
+// offered in Link
+func userFirstTimePlaidLinkHelper(app *CLIApp, login models.Credentials, linkReq models.LinkTokenRequest) (bool, error) {
+	// The following login.AccessTokens are app JWT's, not to be mistaken for Plaid's Access Tokens
+	apiClient := app.Config.Client.WithToken(login.AccessToken)
 
-	linkRes, err := app.Config.MakeBasicRequest("POST", linkURL, login.AccessToken, nil)
+	link, err := apiClient.GetLinkToken(context.Background(), linkReq)
 	if err != nil {
 		return false, fmt.Errorf("error making request: %w", err)
 	}
-	defer linkRes.Body.Close()
-
-	err = checkResponseStatus(linkRes)
-	if err != nil {
-		return false, err
-	}
-
-	var link models.LinkResponse
-	if err = json.NewDecoder(linkRes.Body).Decode(&link); err != nil {
-		return false, fmt.Errorf("error decoding response data: %w", err)
-	}
 
-	redirectURL = redirectURL + "?token=" + link.LinkToken
+	redirectURL := apiClient.LinkURL(link.LinkToken)
 	err = utils.OpenLink(app.Config.OperatingSystem, redirectURL)
 	if err != nil {
 		return false, fmt.Errorf("error opening redirect link: %w", err)
@@ -300,58 +242,21 @@ func userFirstTimePlaidLinkHelper(app *CLIApp, login models.Credentials, itemsUR
 	name := promptForItemName()
 
 	request := models.AccessTokenRequest{
-		PublicToken: token,
-		Nickname:    name,
+		PublicToken:  token,
+		Nickname:     name,
+		CountryCodes: linkReq.CountryCodes,
 	}
 
-	// The following login.AccessTokens are app JWT's, not to be mistaken for Plaid's Access Tokens
-	accessResponse, err := app.Config.MakeBasicRequest("POST", accessURL, login.AccessToken, request)
+	err = apiClient.GetAccessToken(context.Background(), request)
 	if err != nil {
 		return false, fmt.Errorf("error making request: %w", err)
 	}
-	defer accessResponse.Body.Close()
 
-	err = checkResponseStatus(accessResponse)
+	_, err = userCheckItemsHelper(app, login)
 	if err != nil {
 		return false, err
 	}
 
-	itemsResp, err := app.Config.MakeBasicRequest("GET", itemsURL, login.AccessToken, nil)
-	if err != nil {
-		return false, fmt.Errorf("error making request: %w", err)
-	}
-	defer itemsResp.Body.Close()
-
-	err = checkResponseStatus(itemsResp)
-	if err != nil {
-		return false, err
-	}
-
-	if itemsResp.StatusCode == 200 {
-		var itemsResponse struct {
-			Items []models.ItemName `json:"items"`
-		}
-		if err = json.NewDecoder(itemsResp.Body).Decode(&itemsResponse); err != nil {
-			return false, fmt.Errorf("error decoding response data: %w", err)
-		}
-
-		if len(itemsResponse.Items) != 0 {
-			fmt.Printf(" > Available items for user: %s\n", login.User.Name)
-			fmt.Println(" ~~~~~")
-			for _, i := range itemsResponse.Items {
-				fmt.Printf(" Institution: %s || Item Name: %s || ItemID: %s\n", i.InstitutionName, i.Nickname, i.ItemId)
-			}
-			fmt.Println("")
-
-			err = auth.StoreTokens(login, app.Config.ConfigFP)
-			if err != nil {
-				return false, fmt.Errorf("error storing auth tokens: %w", err)
-			}
-
-			return true, nil
-		}
-	}
-
 	return true, nil
 }
 
@@ -393,25 +298,10 @@ Loop:
 
 // Fetches webhook records from server, and searches for actions that user must take to resolve them
 func checkForWebhookRecords(app *CLIApp, items []models.ItemName) error {
-	webhookURL := app.Config.Client.BaseURL + "/api/items/webhook-records"
-
-	res, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("GET", webhookURL, token, nil)
-	})
+	webhookRecords, err := app.Config.Client.GetWebhookRecords(context.Background())
 	if err != nil {
 		return fmt.Errorf("error making http request: %w", err)
 	}
-	defer res.Body.Close()
-
-	err = checkResponseStatus(res)
-	if err != nil {
-		return err
-	}
-
-	var webhookRecords []models.WebhookRecord
-	if err = json.NewDecoder(res.Body).Decode(&webhookRecords); err != nil {
-		return fmt.Errorf("decoding error: %w", err)
-	}
 
 	if len(webhookRecords) == 0 {
 		return nil
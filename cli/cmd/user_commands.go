@@ -3,14 +3,13 @@ package cmd
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jms-guy/greed/cli/internal/auth"
 	"github.com/jms-guy/greed/cli/internal/database"
+	"github.com/jms-guy/greed/cli/internal/tables"
 	"github.com/jms-guy/greed/models"
 	"github.com/spf13/cobra"
 )
@@ -19,10 +18,6 @@ import (
 func (app *CLIApp) commandRegisterUser(cmd *cobra.Command, args []string) error {
 	username := args[0]
 
-	registerURL := app.Config.Client.BaseURL + "/api/auth/register"
-	sendURL := app.Config.Client.BaseURL + "/api/auth/email/send"
-	verifyURL := app.Config.Client.BaseURL + "/api/auth/email/verify"
-
 	// Get user password
 	password, err := registerPasswordHelper()
 	if err != nil {
@@ -46,46 +41,25 @@ func (app *CLIApp) commandRegisterUser(cmd *cobra.Command, args []string) error
 		Email:    email,
 	}
 
-	res, err := app.Config.MakeBasicRequest("POST", registerURL, "", reqData)
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer res.Body.Close()
-
-	err = checkResponseStatus(res)
+	user, err := app.Config.Client.Register(context.Background(), reqData)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	var user models.User
-
-	if err = json.NewDecoder(res.Body).Decode(&user); err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
-		return err
-	}
-
 	emailData := models.EmailVerification{
 		UserID: user.ID,
 		Email:  user.Email,
 	}
 
-	emailRes, err := app.Config.MakeBasicRequest("POST", sendURL, "", emailData)
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer emailRes.Body.Close()
-
-	err = checkResponseStatus(emailRes)
+	err = app.Config.Client.SendEmailCode(context.Background(), emailData)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
 	// Email verification flow
-	verified, err := verifyEmailHelper(app, sendURL, verifyURL, user, emailData)
+	verified, err := verifyEmailHelper(app, user, emailData)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error encountered verifying email")
 		return err
@@ -120,18 +94,15 @@ func (app *CLIApp) commandRegisterUser(cmd *cobra.Command, args []string) error
 func (app *CLIApp) commandUserLogin(cmd *cobra.Command, args []string) error {
 	username := args[0]
 
-	loginURL := app.Config.Client.BaseURL + "/api/auth/login"
-	itemsURL := app.Config.Client.BaseURL + "/api/items"
-
 	// Get user credentials
-	login, err := userLoginHelper(app, username, loginURL)
+	login, err := userLoginHelper(app, username)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error logging in")
 		return err
 	}
 
 	// Check user for existing items
-	items, err := userCheckItemsHelper(app, login, itemsURL)
+	items, err := userCheckItemsHelper(app, login)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error logging in")
 		return err
@@ -148,7 +119,7 @@ func (app *CLIApp) commandUserLogin(cmd *cobra.Command, args []string) error {
 
 	// If no items for user found, this is determined to be first time login
 	// Go through first time Plaid Link flow
-	linked, err := userFirstTimePlaidLinkHelper(app, login, itemsURL)
+	linked, err := userFirstTimePlaidLinkHelper(app, login, models.LinkTokenRequest{})
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error linking financial institution")
 		return err
@@ -157,7 +128,7 @@ func (app *CLIApp) commandUserLogin(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	err = auth.StoreTokens(login, app.Config.ConfigFP)
+	err = app.storeTokens(login)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error logging in")
 		return err
@@ -168,8 +139,6 @@ func (app *CLIApp) commandUserLogin(cmd *cobra.Command, args []string) error {
 
 // Logs a user out, by deleting their local credentials file, and expiring their session delegation server side
 func (app *CLIApp) commandUserLogout(cmd *cobra.Command) error {
-	logoutURL := app.Config.Client.BaseURL + "/api/auth/logout"
-
 	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error reading file, could not log out")
@@ -180,26 +149,31 @@ func (app *CLIApp) commandUserLogout(cmd *cobra.Command) error {
 		RefreshToken: creds.RefreshToken,
 	}
 
-	resp, err := app.Config.MakeBasicRequest("POST", logoutURL, "", request)
+	err = app.Config.Client.Logout(context.Background(), request)
 	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
-	defer resp.Body.Close()
 
-	err = checkResponseStatus(resp)
+	err = app.removeCreds()
 	if err != nil {
-		LogError(app.Config.Db, cmd, err, "Error contacting server")
+		LogError(app.Config.Db, cmd, err, "Error logging out")
 		return err
 	}
 
-	err = auth.RemoveCreds(app.Config.ConfigFP)
+	fmt.Println("Logged out successfully!")
+	return nil
+}
+
+// Forgets the credential passphrase cached for the current shell session
+func (app *CLIApp) commandLock(cmd *cobra.Command) error {
+	err := auth.LockAgent(app.Config.ConfigFP)
 	if err != nil {
-		LogError(app.Config.Db, cmd, err, "Error logging out")
+		LogError(app.Config.Db, cmd, err, "Error locking credentials")
 		return err
 	}
 
-	fmt.Println("Logged out successfully!")
+	fmt.Println("Credentials locked")
 	return nil
 }
 
@@ -208,8 +182,6 @@ func (app *CLIApp) commandUserLogout(cmd *cobra.Command) error {
 func (app *CLIApp) commandDeleteUser(args []string) error {
 	username := args[0]
 
-	deleteURL := app.Config.Client.BaseURL + "/api/users/me"
-
 	pw, err := auth.ReadPassword("Please enter your password > ")
 	if err != nil {
 		return fmt.Errorf("error getting password: %w", err)
@@ -247,25 +219,17 @@ func (app *CLIApp) commandDeleteUser(args []string) error {
 		return nil
 	}
 
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("DELETE", deleteURL, token, nil)
-	})
+	err = app.Config.Client.DeleteCurrentUser(context.Background())
 	if err != nil {
 		return fmt.Errorf("error making request: %w", err)
 	}
-	defer resp.Body.Close()
-
-	err = checkResponseStatus(resp)
-	if err != nil {
-		return err
-	}
 
 	err = app.Config.Db.DeleteUser(context.Background(), username)
 	if err != nil {
 		return fmt.Errorf("error deleting local user record: %w", err)
 	}
 
-	err = auth.RemoveCreds(app.Config.ConfigFP)
+	err = app.removeCreds()
 	if err != nil {
 		fmt.Printf("Error removing credentials - %s\n", err)
 		return nil
@@ -277,9 +241,6 @@ func (app *CLIApp) commandDeleteUser(args []string) error {
 */
 // Verifies a user's email address
 func (app *CLIApp) commandVerifyEmail(cmd *cobra.Command) error {
-	sendURL := app.Config.Client.BaseURL + "/api/auth/email/send"
-	verifyURL := app.Config.Client.BaseURL + "/api/auth/email/verify"
-
 	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error getting credentials")
@@ -302,20 +263,13 @@ func (app *CLIApp) commandVerifyEmail(cmd *cobra.Command) error {
 		Email:  user.Email,
 	}
 
-	sendResp, err := app.Config.MakeBasicRequest("POST", sendURL, "", sendReq)
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer sendResp.Body.Close()
-
-	err = checkResponseStatus(sendResp)
+	err = app.Config.Client.SendEmailCode(context.Background(), sendReq)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	code, err := getEmailCodeHelper(app, creds.User.Email, sendURL, sendReq)
+	code, err := getEmailCodeHelper(app, creds.User.Email, sendReq)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
@@ -329,14 +283,7 @@ func (app *CLIApp) commandVerifyEmail(cmd *cobra.Command) error {
 		Code:   code,
 	}
 
-	verifyRes, err := app.Config.MakeBasicRequest("POST", verifyURL, "", verifyData)
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer verifyRes.Body.Close()
-
-	err = checkResponseStatus(verifyRes)
+	err = app.Config.Client.VerifyEmail(context.Background(), verifyData)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
@@ -361,45 +308,52 @@ func (app *CLIApp) commandVerifyEmail(cmd *cobra.Command) error {
 
 // Lists all items attached to a specific user
 func (app *CLIApp) commandUserItems(cmd *cobra.Command) error {
-	itemsURL := app.Config.Client.BaseURL + "/api/items"
-
 	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error getting credentials")
 		return err
 	}
 
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("GET", itemsURL, token, nil)
-	})
+	items, err := app.Config.Client.GetItems(context.Background())
 	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
+		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
-	defer resp.Body.Close()
+	cacheItems(app, items)
 
-	err = checkResponseStatus(resp)
-	if err != nil {
-		LogError(app.Config.Db, cmd, err, "Error contacting server")
-		return err
+	if app.Output.IsMachine() {
+		return app.writeOutput(cmd, items)
 	}
 
-	if resp.StatusCode == 200 {
-		var itemsResponse struct {
-			Items []models.ItemName `json:"items"`
-		}
-		if err = json.NewDecoder(resp.Body).Decode(&itemsResponse); err != nil {
-			LogError(app.Config.Db, cmd, err, "Error contacting server")
-			return err
+	if len(items) == 0 {
+		return nil
+	}
+
+	fmt.Printf(" > Available items for user: %s\n\n", creds.User.Name)
+	tables.MakeTableForItems(items, time.Now()).Print()
+	fmt.Println("")
+
+	unhealthy := []models.ItemName{}
+	for _, i := range items {
+		if i.Health == models.ItemHealthNeedsReauth || i.Health == models.ItemHealthExpiring {
+			unhealthy = append(unhealthy, i)
 		}
+	}
+	if len(unhealthy) == 0 {
+		return nil
+	}
 
-		if len(itemsResponse.Items) != 0 {
-			fmt.Printf(" > Available items for user: %s\n", creds.User.Name)
-			fmt.Println(" ~~~~~")
-			for _, i := range itemsResponse.Items {
-				fmt.Printf(" Institution: %s || Item Name: %s || ItemID: %s\n", i.InstitutionName, i.Nickname, i.ItemId)
-			}
-			return nil
+	fix, _ := cmd.Flags().GetBool("fix")
+	if !fix {
+		fmt.Printf(" < %d items need to be reconnected, run `greed items --fix` to log in to them again > \n", len(unhealthy))
+		return nil
+	}
+
+	// Reconnects each item through Link update mode, then syncs it
+	for _, i := range unhealthy {
+		fmt.Printf(" > Reconnecting item: %s\n", i.Nickname)
+		if err := app.commandUpdate(&cobra.Command{Use: "items-fix"}, []string{i.Nickname}); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -407,9 +361,6 @@ func (app *CLIApp) commandUserItems(cmd *cobra.Command) error {
 
 // Updates a user's password in record. Requires verified email address to send code to
 func (app *CLIApp) commandChangePassword(cmd *cobra.Command) error {
-	sendURL := app.Config.Client.BaseURL + "/api/auth/email/send"
-	updateURL := app.Config.Client.BaseURL + "/api/users/update-password"
-
 	creds, err := auth.GetCreds(app.Config.ConfigFP)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error getting credentials")
@@ -438,20 +389,13 @@ func (app *CLIApp) commandChangePassword(cmd *cobra.Command) error {
 		Email:  creds.User.Email,
 	}
 
-	emailResp, err := app.Config.MakeBasicRequest("POST", sendURL, "", request)
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer emailResp.Body.Close()
-
-	err = checkResponseStatus(emailResp)
+	err = app.Config.Client.SendEmailCode(context.Background(), request)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	code, err := getEmailCodeHelper(app, creds.User.Email, sendURL, request)
+	code, err := getEmailCodeHelper(app, creds.User.Email, request)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
@@ -465,28 +409,12 @@ func (app *CLIApp) commandChangePassword(cmd *cobra.Command) error {
 		Code:        code,
 	}
 
-	resp, err := DoWithAutoRefresh(app, func(token string) (*http.Response, error) {
-		return app.Config.MakeBasicRequest("PUT", updateURL, token, updateReq)
-	})
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer resp.Body.Close()
-
-	err = checkResponseStatus(resp)
+	updated, err := app.Config.Client.UpdatePassword(context.Background(), updateReq)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	var updated models.UpdatedPassword
-
-	if err = json.NewDecoder(resp.Body).Decode(&updated); err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
-		return err
-	}
-
 	params := database.UpdatePasswordParams{
 		HashedPassword: updated.HashPassword,
 		UpdatedAt:      time.Now().Format("2006-01-02"),
@@ -507,9 +435,6 @@ func (app *CLIApp) commandChangePassword(cmd *cobra.Command) error {
 func (app *CLIApp) commandResetPassword(cmd *cobra.Command, args []string) error {
 	email := args[0]
 
-	sendURL := app.Config.Client.BaseURL + "/api/auth/email/send"
-	resetURL := app.Config.Client.BaseURL + "/api/auth/reset-password"
-
 	user, err := app.Config.Db.GetUserByEmail(context.Background(), email)
 	if err != nil {
 		LogError(app.Config.Db, cmd, fmt.Errorf("error getting user record: %w", err), "Local database error")
@@ -527,20 +452,13 @@ func (app *CLIApp) commandResetPassword(cmd *cobra.Command, args []string) error
 		Email:  user.Email,
 	}
 
-	emailResp, err := app.Config.MakeBasicRequest("POST", sendURL, "", request)
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer emailResp.Body.Close()
-
-	err = checkResponseStatus(emailResp)
+	err = app.Config.Client.SendEmailCode(context.Background(), request)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	code, err := getEmailCodeHelper(app, email, sendURL, request)
+	code, err := getEmailCodeHelper(app, email, request)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
@@ -555,26 +473,12 @@ func (app *CLIApp) commandResetPassword(cmd *cobra.Command, args []string) error
 		NewPassword: password,
 	}
 
-	resetResp, err := app.Config.MakeBasicRequest("POST", resetURL, "", resetReq)
-	if err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("error making http req: %w", err), "Error contacting server")
-		return err
-	}
-	defer resetResp.Body.Close()
-
-	err = checkResponseStatus(resetResp)
+	updated, err := app.Config.Client.ResetPassword(context.Background(), resetReq)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error contacting server")
 		return err
 	}
 
-	var updated models.UpdatedPassword
-
-	if err = json.NewDecoder(resetResp.Body).Decode(&updated); err != nil {
-		LogError(app.Config.Db, cmd, fmt.Errorf("decoding err: %w", err), "Error contacting server")
-		return err
-	}
-
 	params := database.UpdatePasswordParams{
 		HashedPassword: updated.HashPassword,
 		UpdatedAt:      time.Now().Format("2006-01-02"),
@@ -595,28 +499,19 @@ func (app *CLIApp) commandResetPassword(cmd *cobra.Command, args []string) error
 func (app *CLIApp) commandTestUserLogin(cmd *cobra.Command, args []string) error {
 	username := args[0]
 
-	loginURL := app.Config.Client.BaseURL + "/api/auth/login"
-
 	// Get user credentials
-	login, err := userLoginHelper(app, username, loginURL)
+	login, err := userLoginHelper(app, username)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error logging in")
 		return err
 	}
 
-	sandboxURL := app.Config.Client.BaseURL + "/admin/sandbox"
-	sandboxRes, err := app.Config.MakeBasicRequest("POST", sandboxURL, login.AccessToken, nil)
+	err = app.Config.Client.WithToken(login.AccessToken).CreateSandboxItem(context.Background())
 	if err != nil {
 		return fmt.Errorf("error making request: %w", err)
 	}
-	defer sandboxRes.Body.Close()
-
-	err = checkResponseStatus(sandboxRes)
-	if err != nil {
-		return err
-	}
 
-	err = auth.StoreTokens(login, app.Config.ConfigFP)
+	err = app.storeTokens(login)
 	if err != nil {
 		LogError(app.Config.Db, cmd, err, "Error logging in")
 		return err
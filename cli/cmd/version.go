@@ -0,0 +1,19 @@
+package cmd
+
+import "runtime/debug"
+
+// CLI version, set at build time with -ldflags "-X github.com/jms-guy/greed/cli/cmd.Version=<version>"
+var Version = "dev"
+
+// Returns the CLI version, falling back to the module version for builds installed with go install
+func cliVersion() string {
+	if Version != "dev" {
+		return Version
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	return Version
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// The agent exits after this long without being used, or once its shell exits
+const agentIdleTimeout = 30 * time.Minute
+
+// Request and response exchanged with the agent, one JSON line each
+type agentRequest struct {
+	Op  string `json:"op"` // get | set | lock
+	Key []byte `json:"key,omitempty"`
+}
+
+type agentResponse struct {
+	Key   []byte `json:"key,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Agents are per shell session: each command run from the same shell shares its parent's agent
+func agentSocketPath(base string, shellPid int) string {
+	return filepath.Join(base, "agent", strconv.Itoa(shellPid)+".sock")
+}
+
+// Sends a single request to the shell session's agent
+func agentCall(base string, req agentRequest) (agentResponse, error) {
+	var resp agentResponse
+
+	conn, err := net.DialTimeout("unix", agentSocketPath(base, os.Getppid()), time.Second)
+	if err != nil {
+		return resp, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return resp, err
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return resp, err
+	}
+	if resp.Error != "" {
+		return resp, fmt.Errorf("%s", resp.Error)
+	}
+
+	return resp, nil
+}
+
+// Gets the vault key cached by the shell session's agent
+func agentKey(base string) ([]byte, error) {
+	resp, err := agentCall(base, agentRequest{Op: "get"})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Key == nil {
+		return nil, fmt.Errorf("agent has no key")
+	}
+	return resp.Key, nil
+}
+
+// Caches the vault key in the shell session's agent, starting the agent if it isn't running.
+// Caching is best effort, a failure only means the passphrase is asked for again next command
+func cacheAgentKey(base string, key []byte) {
+	req := agentRequest{Op: "set", Key: key}
+	if _, err := agentCall(base, req); err == nil {
+		return
+	}
+
+	if err := startAgent(base); err != nil {
+		return
+	}
+
+	for range 20 {
+		time.Sleep(50 * time.Millisecond)
+		if _, err := agentCall(base, req); err == nil {
+			return
+		}
+	}
+}
+
+// Stops the shell session's agent, forgetting the cached key
+func LockAgent(configPath string) error {
+	base, err := GetBaseConfigPath(configPath)
+	if err != nil {
+		return fmt.Errorf("error getting config directory: %w", err)
+	}
+
+	lockAgent(base)
+	setCachedKey(nil)
+	return nil
+}
+
+func lockAgent(base string) {
+	_, _ = agentCall(base, agentRequest{Op: "lock"})
+}
+
+// Starts an agent in the background for the config directory, bound to the current shell
+func startAgent(base string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// #nosec G204 - runs this same executable
+	cmd := exec.Command(exe, "agent", "--shell", strconv.Itoa(os.Getppid()), "--dir", base)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	return cmd.Process.Release()
+}
+
+// Holds the cached key in memory for the agent
+type agent struct {
+	mu       sync.Mutex
+	key      []byte
+	lastUsed time.Time
+	listener net.Listener
+}
+
+// Runs the passphrase agent, serving the cached vault key over a unix socket in the config directory (base)
+// until it is locked, sits idle, or the shell it belongs to exits
+func RunAgent(base string, shellPid int) error {
+	if err := os.MkdirAll(filepath.Join(base, "agent"), 0o700); err != nil {
+		return fmt.Errorf("error creating agent directory: %w", err)
+	}
+
+	// The agent is detached from the command that started it, and outlives terminal signals sent to it
+	signal.Ignore(syscall.SIGINT, syscall.SIGHUP)
+
+	path := agentSocketPath(base, shellPid)
+	_ = os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("error listening on agent socket: %w", err)
+	}
+	defer os.Remove(path)
+
+	a := &agent{lastUsed: time.Now(), listener: listener}
+	go a.watch(shellPid)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			// Listener closed by lock, idle timeout or shell exit
+			return nil
+		}
+		go a.serve(conn)
+	}
+}
+
+// Closes the agent once idle, or once its shell exits
+func (a *agent) watch(shellPid int) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		a.mu.Lock()
+		idle := time.Since(a.lastUsed) > agentIdleTimeout
+		a.mu.Unlock()
+
+		if idle || !processAlive(shellPid) {
+			a.listener.Close()
+			return
+		}
+	}
+}
+
+func (a *agent) serve(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	var req agentRequest
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	a.lastUsed = time.Now()
+	var resp agentResponse
+	switch req.Op {
+	case "get":
+		resp.Key = a.key
+	case "set":
+		a.key = req.Key
+	case "lock":
+		a.key = nil
+		a.listener.Close()
+	default:
+		resp.Error = "unknown operation " + req.Op
+	}
+	a.mu.Unlock()
+
+	_ = json.NewEncoder(conn).Encode(resp)
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// Service name credentials are saved under in the OS keyring
+const keyringService = "greed"
+
+// Credential store backed by the OS keyring: the Secret Service on Linux, and the login keychain on macOS
+type keyringStore struct {
+	account string
+}
+
+// Keyring entries are keyed by config directory, so separate config directories don't share credentials
+func newKeyringStore(base string) *keyringStore {
+	return &keyringStore{account: base}
+}
+
+func (k *keyringStore) Name() string {
+	return StoreKeyring
+}
+
+func (k *keyringStore) Load() ([]byte, error) {
+	secret, found, err := keyringGet(keyringService, k.account)
+	if err != nil {
+		return nil, fmt.Errorf("error reading from keyring: %w", err)
+	}
+	if !found {
+		return nil, ErrNoCredentials
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding keyring entry: %w", err)
+	}
+
+	return payload, nil
+}
+
+// Payloads are base64 encoded, as keyring tools handle secrets as single lines of text
+func (k *keyringStore) Save(payload []byte) error {
+	return keyringSet(keyringService, k.account, base64.StdEncoding.EncodeToString(payload))
+}
+
+func (k *keyringStore) Delete() error {
+	return keyringDelete(keyringService, k.account)
+}
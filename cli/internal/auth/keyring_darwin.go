@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Exit status of the security tool when a keychain item doesn't exist
+const securityItemNotFound = 44
+
+// The login keychain is reached through the security tool, which ships with macOS
+func keyringAvailable() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+func keyringGet(service, account string) (string, bool, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("security find-generic-password: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), true, nil
+}
+
+// The command is read by security in interactive mode from stdin, keeping the secret out of the process list.
+// Interactive mode reports a failed command on stderr, without always exiting with an error
+func keyringSet(service, account, secret string) error {
+	var stderr bytes.Buffer
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -l %s -w %s\n",
+		securityQuote(service), securityQuote(account), securityQuote("Greed credentials"), securityQuote(secret))
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(command)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil || stderr.Len() != 0 {
+		return fmt.Errorf("security add-generic-password: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// Quotes an argument for a command line read by security in interactive mode
+func securityQuote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+func keyringDelete(service, account string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("security", "delete-generic-password", "-s", service, "-a", account)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+			return nil
+		}
+		return fmt.Errorf("security delete-generic-password: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
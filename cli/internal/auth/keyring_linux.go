@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// The Secret Service is reached through libsecret's secret-tool, and needs a D-Bus session
+func keyringAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func keyringGet(service, account string) (string, bool, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// secret-tool exits with 1 and no output when the entry doesn't exist
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("secret-tool lookup: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), true, nil
+}
+
+// The secret is passed on stdin, keeping it out of the process list
+func keyringSet(service, account, secret string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "store", "--label=Greed credentials", "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("secret-tool store: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+func keyringDelete(service, account string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "clear", "service", service, "account", account)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil && stderr.Len() != 0 {
+		return fmt.Errorf("secret-tool clear: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build !linux && !darwin
+
+package auth
+
+import "errors"
+
+var errKeyringUnsupported = errors.New("OS keyring is not supported on this platform")
+
+// No keyring backend on other platforms, credentials fall back to the encrypted file
+func keyringAvailable() bool {
+	return false
+}
+
+func keyringGet(service, account string) (string, bool, error) {
+	return "", false, errKeyringUnsupported
+}
+
+func keyringSet(service, account, secret string) error {
+	return errKeyringUnsupported
+}
+
+func keyringDelete(service, account string) error {
+	return errKeyringUnsupported
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package auth
+
+import (
+	"os"
+	"syscall"
+)
+
+// Reports whether a process is still running, by sending it the null signal
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package auth
+
+import "os"
+
+// Reports whether a process is still running. Finding a process fails on Windows once it has exited
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
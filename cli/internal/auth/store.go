@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variable used to force a credential store backend
+const credentialStoreEnv = "GREED_CREDENTIAL_STORE"
+
+// Credential store backend names
+const (
+	StoreKeyring = "keyring"
+	StoreFile    = "file"
+)
+
+// Returned when no credentials are stored, meaning the user is not logged in
+var ErrNoCredentials = errors.New("no stored credentials, please log in")
+
+// A backend that credentials are saved in. Payloads are the serialized credentials
+type CredentialStore interface {
+	Name() string
+	Load() ([]byte, error)
+	Save(payload []byte) error
+	Delete() error
+}
+
+// Non-secret record of the logged in session, kept beside the credentials so the
+// current user and the store holding their credentials are known without unlocking it
+type session struct {
+	UserID string `json:"user_id"`
+	Store  string `json:"store"`
+}
+
+// Opens the credential store for the config directory. The store recorded in the session file
+// is used if present, otherwise the store is chosen from the GREED_CREDENTIAL_STORE environment variable,
+// falling back to the OS keyring when available, and to a passphrase encrypted file when it isn't
+func openStore(base string) (CredentialStore, error) {
+	name := ""
+	if s, err := readSession(base); err == nil {
+		name = s.Store
+	}
+	if name == "" {
+		name = strings.ToLower(os.Getenv(credentialStoreEnv))
+	}
+
+	switch name {
+	case StoreKeyring:
+		if !keyringAvailable() {
+			return nil, fmt.Errorf("OS keyring is not available on this system, set %s=%s to use an encrypted file", credentialStoreEnv, StoreFile)
+		}
+		return newKeyringStore(base), nil
+	case StoreFile:
+		return newVaultStore(base), nil
+	case "", "auto":
+		if keyringAvailable() {
+			return newKeyringStore(base), nil
+		}
+		return newVaultStore(base), nil
+	default:
+		return nil, fmt.Errorf("unknown credential store %q, expected %s or %s", name, StoreKeyring, StoreFile)
+	}
+}
+
+// Reads the session file in the config directory
+func readSession(base string) (session, error) {
+	var s session
+
+	// #nosec G304 - base is the trusted config directory
+	data, err := os.ReadFile(filepath.Join(base, "session.json"))
+	if err != nil {
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("error unmarshalling session file: %w", err)
+	}
+
+	return s, nil
+}
+
+// Writes the session file in the config directory
+func writeSession(base string, s session) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling session file: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(base, "session.json"), data, 0o600)
+}
+
+// Returns the ID of the logged in user, without unlocking their credentials.
+// Returns an empty string if no user is logged in
+func CurrentUserID(configPath string) (string, error) {
+	base, err := GetBaseConfigPath(configPath)
+	if err != nil {
+		return "", fmt.Errorf("error getting config directory: %w", err)
+	}
+
+	s, err := readSession(base)
+	if err == nil {
+		return s.UserID, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	// Credentials written before the credential store existed, not migrated yet
+	creds, err := readPlaintextCreds(base)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return creds.User.ID.String(), nil
+}
+
+// Returns the name of the store holding the logged in user's credentials
+func CurrentStore(configPath string) (string, error) {
+	base, err := GetBaseConfigPath(configPath)
+	if err != nil {
+		return "", fmt.Errorf("error getting config directory: %w", err)
+	}
+
+	store, err := openStore(base)
+	if err != nil {
+		return "", err
+	}
+
+	return store.Name(), nil
+}
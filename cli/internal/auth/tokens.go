@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 
@@ -18,17 +17,27 @@ func RemoveCreds(configPath string) error {
 		return fmt.Errorf("error getting config directory: %w", err)
 	}
 
-	credFile := filepath.Join(base, "credentials.json")
-
-	err = os.Remove(credFile)
+	store, err := openStore(base)
 	if err != nil {
-		return fmt.Errorf("error removing credentials file: %w", err)
+		return fmt.Errorf("error opening credential store: %w", err)
+	}
+
+	if err := store.Delete(); err != nil {
+		return fmt.Errorf("error removing credentials: %w", err)
+	}
+
+	err = os.Remove(filepath.Join(base, "session.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing session file: %w", err)
 	}
 
+	// Forget the passphrase cached for this shell session
+	lockAgent(base)
+
 	return nil
 }
 
-// Get credentials from JSON file
+// Get credentials from the credential store, migrating a plaintext credentials file if one exists
 func GetCreds(configPath string) (models.Credentials, error) {
 	var creds models.Credentials
 
@@ -37,25 +46,28 @@ func GetCreds(configPath string) (models.Credentials, error) {
 		return creds, fmt.Errorf("error getting config directory: %w", err)
 	}
 
-	credFile := filepath.Join(base, "credentials.json")
+	if err := migratePlaintextCreds(base); err != nil {
+		return creds, err
+	}
 
-	// #nosec G304 - configPath is trusted input
-	file, err := os.Open(credFile)
-	if err != nil {
+	if _, err := readSession(base); err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return creds, fmt.Errorf("file does not exist")
-		} else {
-			return creds, fmt.Errorf("error opening cred file: %w", err)
+			return creds, ErrNoCredentials
 		}
+		return creds, err
+	}
+
+	store, err := openStore(base)
+	if err != nil {
+		return creds, fmt.Errorf("error opening credential store: %w", err)
 	}
-	defer file.Close()
 
-	byteVal, err := io.ReadAll(file)
+	payload, err := store.Load()
 	if err != nil {
-		return creds, fmt.Errorf("error reading credentials.json: %w", err)
+		return creds, err
 	}
 
-	err = json.Unmarshal(byteVal, &creds)
+	err = json.Unmarshal(payload, &creds)
 	if err != nil {
 		return creds, fmt.Errorf("error unmarshalling data: %w", err)
 	}
@@ -63,7 +75,7 @@ func GetCreds(configPath string) (models.Credentials, error) {
 	return creds, nil
 }
 
-// Store auth tokens in a credentials file
+// Store auth tokens in the credential store
 func StoreTokens(data models.Credentials, configPath string) error {
 	base, err := GetBaseConfigPath(configPath)
 	if err != nil {
@@ -74,18 +86,66 @@ func StoreTokens(data models.Credentials, configPath string) error {
 		return fmt.Errorf("error creating config dir: %w", err)
 	}
 
-	creds := filepath.Join(base, "credentials.json")
+	return storeCreds(base, data)
+}
+
+// Saves credentials to the store, and records the session
+func storeCreds(base string, data models.Credentials) error {
+	store, err := openStore(base)
+	if err != nil {
+		return fmt.Errorf("error opening credential store: %w", err)
+	}
 
-	// #nosec G304 - configPath is trusted input
-	f, err := os.OpenFile(filepath.Clean(creds), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	payload, err := json.Marshal(data)
 	if err != nil {
+		return fmt.Errorf("error marshalling credentials: %w", err)
+	}
+
+	if err := store.Save(payload); err != nil {
+		return fmt.Errorf("error saving credentials to %s store: %w", store.Name(), err)
+	}
+
+	return writeSession(base, session{UserID: data.User.ID.String(), Store: store.Name()})
+}
+
+// Moves credentials stored as plaintext by earlier versions into the credential store
+func migratePlaintextCreds(base string) error {
+	creds, err := readPlaintextCreds(base)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
 		return err
 	}
-	defer f.Close()
 
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(data)
+	if err := storeCreds(base, creds); err != nil {
+		return fmt.Errorf("error migrating plaintext credentials: %w", err)
+	}
+
+	if err := os.Remove(filepath.Join(base, "credentials.json")); err != nil {
+		return fmt.Errorf("error removing plaintext credentials file: %w", err)
+	}
+
+	s, _ := readSession(base)
+	fmt.Fprintf(os.Stderr, " < Credentials moved from credentials.json into the %s credential store > \n", s.Store)
+	return nil
+}
+
+// Reads the plaintext credentials file written by earlier versions
+func readPlaintextCreds(base string) (models.Credentials, error) {
+	var creds models.Credentials
+
+	// #nosec G304 - base is the trusted config directory
+	data, err := os.ReadFile(filepath.Join(base, "credentials.json"))
+	if err != nil {
+		return creds, err
+	}
+
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return creds, fmt.Errorf("error unmarshalling credentials.json: %w", err)
+	}
+
+	return creds, nil
 }
 
 func GetBaseConfigPath(path string) (string, error) {
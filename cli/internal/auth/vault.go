@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Environment variable holding the vault passphrase, for non-interactive use
+const passphraseEnv = "GREED_PASSPHRASE"
+
+// scrypt cost parameters for deriving the vault key from the passphrase
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	vaultKeySize = 32
+)
+
+// Key of the unlocked vault, kept for the life of the process so the passphrase is asked for once per command.
+// Guarded by keyMu, as credentials can be read from several goroutines
+var (
+	keyMu       sync.Mutex
+	unlockedKey []byte
+)
+
+// Credential store backed by an AES-256-GCM encrypted file, with the key derived from a passphrase.
+// The derived key is cached by an agent process for the rest of the shell session
+type vaultStore struct {
+	base string
+	path string
+}
+
+// On-disk layout of the encrypted credentials file
+type vaultFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func newVaultStore(base string) *vaultStore {
+	return &vaultStore{
+		base: base,
+		path: filepath.Join(base, "credentials.enc"),
+	}
+}
+
+func (v *vaultStore) Name() string {
+	return StoreFile
+}
+
+func (v *vaultStore) Load() ([]byte, error) {
+	vf, err := v.read()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNoCredentials
+		}
+		return nil, err
+	}
+
+	_, payload, err := v.unlock(vf)
+	return payload, err
+}
+
+// Re-encrypts with the existing passphrase, or asks for a new passphrase if there is no vault yet
+func (v *vaultStore) Save(payload []byte) error {
+	var key, salt []byte
+
+	vf, err := v.read()
+	switch {
+	case err == nil:
+		key, _, err = v.unlock(vf)
+		if err != nil {
+			return err
+		}
+		salt = vf.Salt
+	case errors.Is(err, os.ErrNotExist):
+		passphrase, err := newPassphrase()
+		if err != nil {
+			return err
+		}
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("error generating salt: %w", err)
+		}
+		key, err = deriveKey(passphrase, salt, scryptN, scryptR, scryptP)
+		if err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	out := vaultFile{
+		Version:    1,
+		KDF:        "scrypt",
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, payload, nil),
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling vault: %w", err)
+	}
+
+	// Write to a temporary file first, so an interrupted write can't corrupt the vault
+	tmp := v.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("error writing vault: %w", err)
+	}
+	if err := os.Rename(tmp, v.path); err != nil {
+		return fmt.Errorf("error writing vault: %w", err)
+	}
+
+	setCachedKey(key)
+	cacheAgentKey(v.base, key)
+	return nil
+}
+
+func (v *vaultStore) Delete() error {
+	setCachedKey(nil)
+
+	err := os.Remove(v.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (v *vaultStore) read() (vaultFile, error) {
+	var vf vaultFile
+
+	data, err := os.ReadFile(v.path)
+	if err != nil {
+		return vf, err
+	}
+
+	if err := json.Unmarshal(data, &vf); err != nil {
+		return vf, fmt.Errorf("error unmarshalling vault: %w", err)
+	}
+	if vf.Version != 1 || vf.KDF != "scrypt" {
+		return vf, fmt.Errorf("unsupported vault version %d (%s)", vf.Version, vf.KDF)
+	}
+
+	return vf, nil
+}
+
+// Finds the key for the vault, trying the key unlocked by this process, then the
+// session agent's key, then asking for the passphrase. Returns the key and decrypted payload
+func (v *vaultStore) unlock(vf vaultFile) ([]byte, []byte, error) {
+	candidates := [][]byte{cachedKey()}
+	if key, err := agentKey(v.base); err == nil {
+		candidates = append(candidates, key)
+	}
+
+	for _, key := range candidates {
+		if key == nil {
+			continue
+		}
+		if payload, err := decrypt(vf, key); err == nil {
+			setCachedKey(key)
+			return key, payload, nil
+		}
+	}
+
+	passphrase := os.Getenv(passphraseEnv)
+	if passphrase == "" {
+		var err error
+		passphrase, err = ReadPassword("Enter passphrase to unlock credentials > ")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	key, err := deriveKey(passphrase, vf.Salt, vf.N, vf.R, vf.P)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload, err := decrypt(vf, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("incorrect passphrase")
+	}
+
+	setCachedKey(key)
+	cacheAgentKey(v.base, key)
+	return key, payload, nil
+}
+
+func cachedKey() []byte {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	return unlockedKey
+}
+
+func setCachedKey(key []byte) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	unlockedKey = key
+}
+
+// Asks for a passphrase for a new vault, confirming it
+func newPassphrase() (string, error) {
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fmt.Println(" < Credentials will be stored in an encrypted file. Choose a passphrase to protect them > ")
+	for {
+		passphrase, err := ReadPassword("Enter new passphrase > ")
+		if err != nil {
+			return "", err
+		}
+		if len(passphrase) < 8 {
+			fmt.Println("Passphrase must be at least 8 characters")
+			continue
+		}
+
+		confirm, err := ReadPassword("Confirm passphrase > ")
+		if err != nil {
+			return "", err
+		}
+		if confirm != passphrase {
+			fmt.Println("Passphrases do not match")
+			continue
+		}
+
+		return passphrase, nil
+	}
+}
+
+func deriveKey(passphrase string, salt []byte, n, r, p int) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, vaultKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving vault key: %w", err)
+	}
+	return key, nil
+}
+
+func decrypt(vf vaultFile, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(vf.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid vault nonce")
+	}
+	return gcm.Open(nil, vf.Nonce, vf.Ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,14 +12,20 @@ import (
 	"github.com/jms-guy/greed/cli/internal/auth"
 	"github.com/jms-guy/greed/cli/internal/database"
 	mySQL "github.com/jms-guy/greed/cli/sql"
+	"github.com/jms-guy/greed/client"
 	"github.com/jms-guy/greed/models"
 )
 
+// Config directory, relative to the user's home directory
+const configPath = ".config/greed"
+
 // CLI config struct
 type Config struct {
-	Client          *Client           // Http client for handling server requests
+	Client          *client.Client    // Typed client for handling server requests
 	Db              *database.Queries // Local database queries
+	Conn            *sql.DB           // Local database connection, for grouping queries into transactions
 	ConfigFP        string            // Config file path
+	Profile         Profile           // Profile in use, selecting the server and config directory
 	OperatingSystem string            // Local operating system
 	SettingsFP      string            // Settings filepath
 	Settings        Settings          // Holds settings loaded from config file
@@ -30,27 +37,38 @@ type Settings struct {
 	DefaultAccount database.Account `json:"default_account"`
 }
 
-// Initializes configuration struct
-func LoadConfig() (*Config, error) {
-	serverAddress := "https://greed-614554014047.us-central1.run.app"
-	// serverAddress := "http://localhost:3333"
-
-	client := NewClient(serverAddress)
-
+// Initializes configuration struct for a profile. An empty profile name selects the current profile
+func LoadConfig(profileName string) (*Config, error) {
 	// Get user's home directory
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("error finding home directory: %w", err)
 	}
 
-	// Config directory
-	configDir := filepath.Join(homeDir, ".config", "greed")
+	if err := os.MkdirAll(filepath.Join(homeDir, configPath), 0o750); err != nil {
+		return nil, fmt.Errorf("error creating config directory: %w", err)
+	}
+
+	profiles, err := LoadProfiles()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := profiles.Resolve(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient := client.New(profile.URL)
+
+	// Config directory of the profile
+	profilePath := ProfileConfigPath(profile.Name)
+	configDir := filepath.Join(homeDir, profilePath)
 	if err := os.MkdirAll(configDir, 0o750); err != nil {
 		return nil, fmt.Errorf("error creating config directory: %w", err)
 	}
 
 	// Settings
-	settingsFile, err := getSettingsFilePath(configDir)
+	settingsFile, err := getSettingsFilePath(profilePath, configDir)
 	if err != nil {
 		return nil, fmt.Errorf("error determining settings file: %w", err)
 	}
@@ -69,7 +87,7 @@ func LoadConfig() (*Config, error) {
 	// Local database file
 	localDb := filepath.Join(configDir, "greed.db")
 
-	queries, err := mySQL.OpenLocalDatabase(localDb)
+	conn, queries, err := mySQL.OpenLocalDatabase(localDb)
 	if err != nil {
 		return nil, fmt.Errorf("error opening local database connection: %w", err)
 	}
@@ -77,9 +95,11 @@ func LoadConfig() (*Config, error) {
 	os := runtime.GOOS
 
 	config := Config{
-		Client:          client,
+		Client:          apiClient,
 		Db:              queries,
-		ConfigFP:        ".config/greed",
+		Conn:            conn,
+		ConfigFP:        profilePath,
+		Profile:         profile,
 		OperatingSystem: os,
 		SettingsFP:      settingsFile,
 		Settings:        settings,
@@ -88,23 +108,20 @@ func LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
-// Checks for user credentials file, to obtain correct settings file
-func getSettingsFilePath(configPath string) (string, error) {
-	credentialsFile := filepath.Join(configPath, "credentials.json")
-
-	// Check if user is logged in
-	if _, err := os.Stat(credentialsFile); os.IsNotExist(err) {
-		return filepath.Join(configPath, "settings.json"), nil
+// Checks for a logged in user, to obtain correct settings file
+func getSettingsFilePath(profilePath, configDir string) (string, error) {
+	userID, err := auth.CurrentUserID(profilePath)
+	if err != nil {
+		return "", fmt.Errorf("error reading session: %w", err)
 	}
 
-	creds, err := auth.GetCreds(configPath)
-	if err != nil {
-		return "", fmt.Errorf("error reading credentials: %w", err)
+	// Check if user is logged in
+	if userID == "" {
+		return filepath.Join(configDir, "settings.json"), nil
 	}
 
-	userID := creds.User.ID
 	settingsFileName := fmt.Sprintf("%s_settings.json", userID)
-	return filepath.Join(configPath, settingsFileName), nil
+	return filepath.Join(configDir, settingsFileName), nil
 }
 
 // Function to load settings from file
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// Environment variable overriding the current profile for a single command
+const ProfileEnv = "GREED_PROFILE"
+
+// Profile used when none has been added, pointing at the hosted server
+const (
+	DefaultProfile   = "default"
+	DefaultServerURL = "https://greed-614554014047.us-central1.run.app"
+)
+
+var profileNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+// A named server, with its own credentials, settings and local database
+type Profile struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Profiles saved in the profiles file, and the one in use
+type Profiles struct {
+	Current  string             `json:"current"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Path of the profiles file, relative to the user's home directory
+func profilesFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error finding home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, configPath, "profiles.json"), nil
+}
+
+// Loads the profiles file. If it doesn't exist, only the default profile is returned
+func LoadProfiles() (Profiles, error) {
+	profiles := Profiles{
+		Current: DefaultProfile,
+		Profiles: map[string]Profile{
+			DefaultProfile: {Name: DefaultProfile, URL: DefaultServerURL},
+		},
+	}
+
+	path, err := profilesFilePath()
+	if err != nil {
+		return profiles, err
+	}
+
+	// #nosec G304 - file variables are controlled, no user input
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return profiles, nil
+		}
+		return profiles, fmt.Errorf("error reading profiles file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return profiles, fmt.Errorf("error decoding profiles file: %w", err)
+	}
+
+	if _, ok := profiles.Profiles[DefaultProfile]; !ok {
+		profiles.Profiles[DefaultProfile] = Profile{Name: DefaultProfile, URL: DefaultServerURL}
+	}
+
+	return profiles, nil
+}
+
+// Saves the profiles file
+func SaveProfiles(profiles Profiles) error {
+	path, err := profilesFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(profiles, "", "    ")
+	if err != nil {
+		return fmt.Errorf("error encoding profiles file: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Returns the profiles sorted by name
+func (p Profiles) List() []Profile {
+	list := make([]Profile, 0, len(p.Profiles))
+	for _, profile := range p.Profiles {
+		list = append(list, profile)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Finds the profile to use. An explicit name (the --profile flag) comes first, then the
+// GREED_PROFILE environment variable, then the current profile set with `profile use`
+func (p Profiles) Resolve(name string) (Profile, error) {
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+	if name == "" {
+		name = p.Current
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	profile, ok := p.Profiles[name]
+	if !ok {
+		return profile, fmt.Errorf("no profile named %q, see `greed profile list`", name)
+	}
+
+	return profile, nil
+}
+
+// Validates the name of a new profile
+func ValidateProfileName(name string) error {
+	if !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("profile names may only contain letters, numbers, '-' and '_', up to 32 characters")
+	}
+	return nil
+}
+
+// Config directory for a profile, relative to the user's home directory. The default profile keeps
+// using the top-level config directory, so installs from before profiles existed carry on working
+func ProfileConfigPath(name string) string {
+	if name == DefaultProfile {
+		return configPath
+	}
+	return filepath.Join(configPath, "profiles", name)
+}
@@ -27,9 +27,11 @@ func (q *Queries) DeleteAccounts(ctx context.Context, arg DeleteAccountsParams)
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, institution_name, user_id FROM accounts
-WHERE name = ?
-AND user_id = ?
+SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, institution_name, user_id, nickname, hidden, archived, include_in_net_worth, sort_order FROM accounts
+WHERE (name = ?1 OR nickname = ?1)
+AND user_id = ?2
+ORDER BY nickname = ?1 DESC
+LIMIT 1
 `
 
 type GetAccountParams struct {
@@ -54,13 +56,19 @@ func (q *Queries) GetAccount(ctx context.Context, arg GetAccountParams) (Account
 		&i.IsoCurrencyCode,
 		&i.InstitutionName,
 		&i.UserID,
+		&i.Nickname,
+		&i.Hidden,
+		&i.Archived,
+		&i.IncludeInNetWorth,
+		&i.SortOrder,
 	)
 	return i, err
 }
 
 const getAllAccounts = `-- name: GetAllAccounts :many
-SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, institution_name, user_id FROM accounts
+SELECT id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, institution_name, user_id, nickname, hidden, archived, include_in_net_worth, sort_order FROM accounts
 WHERE user_id = ?
+ORDER BY sort_order, COALESCE(nickname, name) COLLATE NOCASE
 `
 
 func (q *Queries) GetAllAccounts(ctx context.Context, userID string) ([]Account, error) {
@@ -86,6 +94,11 @@ func (q *Queries) GetAllAccounts(ctx context.Context, userID string) ([]Account,
 			&i.IsoCurrencyCode,
 			&i.InstitutionName,
 			&i.UserID,
+			&i.Nickname,
+			&i.Hidden,
+			&i.Archived,
+			&i.IncludeInNetWorth,
+			&i.SortOrder,
 		); err != nil {
 			return nil, err
 		}
@@ -100,6 +113,36 @@ func (q *Queries) GetAllAccounts(ctx context.Context, userID string) ([]Account,
 	return items, nil
 }
 
+const setAccountOverrides = `-- name: SetAccountOverrides :exec
+UPDATE accounts
+SET nickname = ?, hidden = ?, archived = ?, include_in_net_worth = ?, sort_order = ?
+WHERE id = ?
+AND user_id = ?
+`
+
+type SetAccountOverridesParams struct {
+	Nickname          sql.NullString
+	Hidden            bool
+	Archived          bool
+	IncludeInNetWorth bool
+	SortOrder         int64
+	ID                string
+	UserID            string
+}
+
+func (q *Queries) SetAccountOverrides(ctx context.Context, arg SetAccountOverridesParams) error {
+	_, err := q.db.ExecContext(ctx, setAccountOverrides,
+		arg.Nickname,
+		arg.Hidden,
+		arg.Archived,
+		arg.IncludeInNetWorth,
+		arg.SortOrder,
+		arg.ID,
+		arg.UserID,
+	)
+	return err
+}
+
 const upsertAccount = `-- name: UpsertAccount :one
 INSERT INTO accounts(
     id,
@@ -134,7 +177,7 @@ ON CONFLICT (id) DO UPDATE SET
     available_balance = EXCLUDED.available_balance,
     current_balance = EXCLUDED.current_balance,
     updated_at = DATETIME('now')
-RETURNING id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, institution_name, user_id
+RETURNING id, created_at, updated_at, name, type, subtype, mask, official_name, available_balance, current_balance, iso_currency_code, institution_name, user_id, nickname, hidden, archived, include_in_net_worth, sort_order
 `
 
 type UpsertAccountParams struct {
@@ -184,6 +227,11 @@ func (q *Queries) UpsertAccount(ctx context.Context, arg UpsertAccountParams) (A
 		&i.IsoCurrencyCode,
 		&i.InstitutionName,
 		&i.UserID,
+		&i.Nickname,
+		&i.Hidden,
+		&i.Archived,
+		&i.IncludeInNetWorth,
+		&i.SortOrder,
 	)
 	return i, err
 }
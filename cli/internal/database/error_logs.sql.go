@@ -10,30 +10,71 @@ import (
 )
 
 const createErrorLog = `-- name: CreateErrorLog :exec
-INSERT INTO error_logs (timestamp, command, error_message)
-VALUES (?, ?, ?)
-RETURNING id, timestamp, command, error_message
+INSERT INTO error_logs (timestamp, command, error_message, command_line, user_message, request_id, http_status, plaid_request_id, cli_version)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, timestamp, command, error_message, command_line, user_message, request_id, http_status, plaid_request_id, cli_version
 `
 
 type CreateErrorLogParams struct {
-	Timestamp    string
-	Command      string
-	ErrorMessage string
+	Timestamp      string
+	Command        string
+	ErrorMessage   string
+	CommandLine    string
+	UserMessage    string
+	RequestID      string
+	HttpStatus     int64
+	PlaidRequestID string
+	CliVersion     string
 }
 
 func (q *Queries) CreateErrorLog(ctx context.Context, arg CreateErrorLogParams) error {
-	_, err := q.db.ExecContext(ctx, createErrorLog, arg.Timestamp, arg.Command, arg.ErrorMessage)
+	_, err := q.db.ExecContext(ctx, createErrorLog,
+		arg.Timestamp,
+		arg.Command,
+		arg.ErrorMessage,
+		arg.CommandLine,
+		arg.UserMessage,
+		arg.RequestID,
+		arg.HttpStatus,
+		arg.PlaidRequestID,
+		arg.CliVersion,
+	)
 	return err
 }
 
-const getLogs = `-- name: GetLogs :many
-SELECT id, timestamp, command, error_message FROM error_logs
-ORDER BY timestamp DESC
-LIMIT 5
+const deleteErrorLogsBefore = `-- name: DeleteErrorLogsBefore :exec
+DELETE FROM error_logs
+WHERE timestamp < ?
 `
 
-func (q *Queries) GetLogs(ctx context.Context) ([]ErrorLog, error) {
-	rows, err := q.db.QueryContext(ctx, getLogs)
+func (q *Queries) DeleteErrorLogsBefore(ctx context.Context, timestamp string) error {
+	_, err := q.db.ExecContext(ctx, deleteErrorLogsBefore, timestamp)
+	return err
+}
+
+const listErrorLogs = `-- name: ListErrorLogs :many
+SELECT id, timestamp, command, error_message, command_line, user_message, request_id, http_status, plaid_request_id, cli_version FROM error_logs
+WHERE timestamp >= ?1
+AND (?2 = '' OR command = ?2 OR command LIKE ?2 || ' %')
+AND (?3 = '' OR error_message LIKE '%' || ?3 || '%' OR user_message LIKE '%' || ?3 || '%' OR command_line LIKE '%' || ?3 || '%')
+ORDER BY timestamp DESC, id DESC
+LIMIT ?4
+`
+
+type ListErrorLogsParams struct {
+	Since   string
+	Command interface{}
+	Pattern interface{}
+	Limit   int64
+}
+
+func (q *Queries) ListErrorLogs(ctx context.Context, arg ListErrorLogsParams) ([]ErrorLog, error) {
+	rows, err := q.db.QueryContext(ctx, listErrorLogs,
+		arg.Since,
+		arg.Command,
+		arg.Pattern,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -46,6 +87,12 @@ func (q *Queries) GetLogs(ctx context.Context) ([]ErrorLog, error) {
 			&i.Timestamp,
 			&i.Command,
 			&i.ErrorMessage,
+			&i.CommandLine,
+			&i.UserMessage,
+			&i.RequestID,
+			&i.HttpStatus,
+			&i.PlaidRequestID,
+			&i.CliVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -59,3 +106,17 @@ func (q *Queries) GetLogs(ctx context.Context) ([]ErrorLog, error) {
 	}
 	return items, nil
 }
+
+const trimErrorLogs = `-- name: TrimErrorLogs :exec
+DELETE FROM error_logs
+WHERE id NOT IN (
+    SELECT id FROM error_logs
+    ORDER BY id DESC
+    LIMIT ?
+)
+`
+
+func (q *Queries) TrimErrorLogs(ctx context.Context, limit int64) error {
+	_, err := q.db.ExecContext(ctx, trimErrorLogs, limit)
+	return err
+}
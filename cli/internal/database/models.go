@@ -9,26 +9,51 @@ import (
 )
 
 type Account struct {
-	ID               string
-	CreatedAt        string
-	UpdatedAt        string
-	Name             string
-	Type             string
-	Subtype          sql.NullString
-	Mask             sql.NullString
-	OfficialName     sql.NullString
-	AvailableBalance sql.NullFloat64
-	CurrentBalance   sql.NullFloat64
-	IsoCurrencyCode  sql.NullString
-	InstitutionName  sql.NullString
-	UserID           string
+	ID                string
+	CreatedAt         string
+	UpdatedAt         string
+	Name              string
+	Type              string
+	Subtype           sql.NullString
+	Mask              sql.NullString
+	OfficialName      sql.NullString
+	AvailableBalance  sql.NullFloat64
+	CurrentBalance    sql.NullFloat64
+	IsoCurrencyCode   sql.NullString
+	InstitutionName   sql.NullString
+	UserID            string
+	Nickname          sql.NullString
+	Hidden            bool
+	Archived          bool
+	IncludeInNetWorth bool
+	SortOrder         int64
 }
 
 type ErrorLog struct {
-	ID           int64
-	Timestamp    string
-	Command      string
-	ErrorMessage string
+	ID             int64
+	Timestamp      string
+	Command        string
+	ErrorMessage   string
+	CommandLine    string
+	UserMessage    string
+	RequestID      string
+	HttpStatus     int64
+	PlaidRequestID string
+	CliVersion     string
+}
+
+type Item struct {
+	ID              string
+	UserID          string
+	Nickname        string
+	InstitutionName string
+	UpdatedAt       string
+}
+
+type SyncCursor struct {
+	UserID    string
+	Cursor    string
+	UpdatedAt string
 }
 
 type Transaction struct {
@@ -70,6 +70,16 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 	return i, err
 }
 
+const deleteTransaction = `-- name: DeleteTransaction :exec
+DELETE FROM transactions
+WHERE id = ?
+`
+
+func (q *Queries) DeleteTransaction(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteTransaction, id)
+	return err
+}
+
 const deleteTransactions = `-- name: DeleteTransactions :exec
 DELETE FROM transactions
 WHERE account_id IN (
@@ -119,3 +129,59 @@ func (q *Queries) GetTransactions(ctx context.Context, accountID string) ([]Tran
 	}
 	return items, nil
 }
+
+const upsertTransaction = `-- name: UpsertTransaction :exec
+INSERT INTO transactions(
+    id, 
+    account_id, 
+    amount, 
+    iso_currency_code, 
+    date, 
+    merchant_name, 
+    payment_channel, 
+    personal_finance_category
+    )
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT(id) DO UPDATE SET
+    account_id = excluded.account_id,
+    amount = excluded.amount,
+    iso_currency_code = excluded.iso_currency_code,
+    date = excluded.date,
+    merchant_name = excluded.merchant_name,
+    payment_channel = excluded.payment_channel,
+    personal_finance_category = excluded.personal_finance_category
+`
+
+type UpsertTransactionParams struct {
+	ID                      string
+	AccountID               string
+	Amount                  float64
+	IsoCurrencyCode         sql.NullString
+	Date                    sql.NullString
+	MerchantName            sql.NullString
+	PaymentChannel          string
+	PersonalFinanceCategory string
+}
+
+func (q *Queries) UpsertTransaction(ctx context.Context, arg UpsertTransactionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTransaction,
+		arg.ID,
+		arg.AccountID,
+		arg.Amount,
+		arg.IsoCurrencyCode,
+		arg.Date,
+		arg.MerchantName,
+		arg.PaymentChannel,
+		arg.PersonalFinanceCategory,
+	)
+	return err
+}
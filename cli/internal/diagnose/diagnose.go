@@ -0,0 +1,79 @@
+// Package diagnose builds redacted diagnostics bundles for attaching to bug reports
+package diagnose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Patterns of secrets and personal data removed from bundle contents, with their replacements
+var redactions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), "[REDACTED_JWT]"},
+	{regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`), "Bearer [REDACTED]"},
+	{regexp.MustCompile(`(access|public|link)-(sandbox|development|production)-[A-Za-z0-9-]+`), "[REDACTED_PLAID_TOKEN]"},
+	{regexp.MustCompile(`\$2[aby]\$\d{2}\$[./A-Za-z0-9]{53}`), "[REDACTED_HASH]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[REDACTED_EMAIL]"},
+	{regexp.MustCompile(`(https?://)[^/@\s]+@`), "${1}[REDACTED]@"},
+}
+
+// Removes tokens, password hashes, emails and url credentials from text
+func Redact(text string) string {
+	for _, r := range redactions {
+		text = r.pattern.ReplaceAllString(text, r.replacement)
+	}
+	return text
+}
+
+// Writes files into a gzipped tarball at path, redacting their contents. Files are written in name order
+func WriteBundle(path string, files map[string][]byte) error {
+	// #nosec G304 - path is chosen by the user running the command
+	f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating bundle: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	for _, name := range names {
+		content := []byte(Redact(string(files[name])))
+
+		header := &tar.Header{
+			Name:    filepath.ToSlash(filepath.Join("greed-diagnose", name)),
+			Mode:    0o600,
+			Size:    int64(len(content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("error writing bundle: %w", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return fmt.Errorf("error writing bundle: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+/*
+	This package writes command results in machine-readable formats, for use in scripts.
+	Data is written as the underlying models structs - JSON output uses the structs' json tags directly,
+	and CSV/TSV output uses one row per struct, with the json tags as column headers.
+*/
+
+// Output format selected with the global --output flag
+type Format string
+
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	CSV   Format = "csv"
+	TSV   Format = "tsv"
+)
+
+// Parses an --output flag value
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Table, JSON, CSV, TSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid output format %q, expected one of [table | json | csv | tsv]", s)
+}
+
+// Returns true if the format is a machine-readable one, rather than the default table output
+func (f Format) IsMachine() bool {
+	return f == JSON || f == CSV || f == TSV
+}
+
+// Returns true if stdout is an interactive terminal. Interactive pagers are only used when it is
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd())) // #nosec G115 - file descriptors fit in an int
+}
+
+// Writes data to w in the given format. Data should be a struct, or a slice of structs
+func Write(w io.Writer, format Format, data any) error {
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	case CSV:
+		return writeDelimited(w, ',', data)
+	case TSV:
+		return writeDelimited(w, '\t', data)
+	}
+	return fmt.Errorf("format %q is not a machine-readable format", format)
+}
+
+// Writes a struct, or slice of structs, as delimited rows with a header row
+func writeDelimited(w io.Writer, delim rune, data any) error {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		v = reflect.Append(reflect.MakeSlice(reflect.SliceOf(v.Type()), 0, 1), v)
+	}
+
+	elemType := v.Type().Elem()
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot write %s as delimited rows", elemType)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = delim
+
+	var fields []int
+	var header []string
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, i)
+		header = append(header, strings.TrimSuffix(name, ":"))
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		row := make([]string, 0, len(fields))
+		for _, f := range fields {
+			row = append(row, formatValue(v.Index(i).Field(f)))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Formats a single field value for a delimited row
+func formatValue(v reflect.Value) string {
+	switch val := v.Interface().(type) {
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format(time.RFC3339)
+	case fmt.Stringer:
+		return val.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		dat, err := json.Marshal(v.Interface())
+		if err != nil {
+			return ""
+		}
+		return string(dat)
+	}
+
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,203 @@
+package payoff
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Order in which money left over after minimum payments is put towards debts
+type Strategy string
+
+const (
+	Avalanche Strategy = "avalanche" // Highest interest rate first, paying the least interest overall
+	Snowball  Strategy = "snowball"  // Smallest balance first, clearing individual debts soonest
+)
+
+// Longest plan simulated, after which debts are treated as never being paid off
+const MaxMonths = 600
+
+// Balances below half a cent are treated as paid off
+const paidOff = 0.005
+
+// A debt to simulate, with its interest rate as an annual percentage
+type Debt struct {
+	ID             string
+	Name           string
+	Balance        float64
+	APR            float64
+	MinimumPayment float64
+}
+
+// When a debt is paid off within a plan, and the interest paid on it until then
+type DebtPayoff struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Month    int     `json:"month"` // Months from now, 0 if the debt is never paid off
+	Interest float64 `json:"interest"`
+}
+
+// The result of paying off a set of debts with a strategy
+type Plan struct {
+	Strategy      Strategy     `json:"strategy"`
+	MonthlyBudget float64      `json:"monthly_budget"`
+	Months        int          `json:"months"`
+	TotalInterest float64      `json:"total_interest"`
+	TotalPaid     float64      `json:"total_paid"`
+	Complete      bool         `json:"complete"` // False if the budget doesn't cover the debts' interest within MaxMonths
+	Payoffs       []DebtPayoff `json:"payoffs"`  // In the order debts are paid off, unpaid debts last
+}
+
+// Parses a strategy name, an empty name returning both strategies
+func ParseStrategies(name string) ([]Strategy, error) {
+	switch Strategy(name) {
+	case "":
+		return []Strategy{Avalanche, Snowball}, nil
+	case Avalanche, Snowball:
+		return []Strategy{Strategy(name)}, nil
+	default:
+		return nil, fmt.Errorf("invalid strategy: %s, must be avalanche or snowball", name)
+	}
+}
+
+// Simulates paying off debts month by month. Each month interest is added at a twelfth of the APR,
+// every debt gets its minimum payment, and the rest of the budget goes to debts in strategy order.
+// The budget is the sum of the minimum payments plus extra, so a paid off debt's minimum rolls on to the next.
+// Debts already paid off, such as a credit card with no balance, are left out of the plan
+func Simulate(debts []Debt, extra float64, strategy Strategy) Plan {
+	debts = slices.DeleteFunc(slices.Clone(debts), func(d Debt) bool { return d.Balance <= paidOff })
+
+	balances := make([]float64, len(debts))
+	interest := make([]float64, len(debts))
+	payoffMonth := make([]int, len(debts))
+	budget := extra
+	for i, d := range debts {
+		balances[i] = d.Balance
+		budget += d.MinimumPayment
+	}
+
+	plan := Plan{Strategy: strategy, MonthlyBudget: round(budget)}
+	order := strategyOrder(debts, strategy)
+
+	remaining := len(debts)
+	month := 0
+	for remaining > 0 && month < MaxMonths {
+		month++
+
+		for i, d := range debts {
+			if balances[i] <= paidOff {
+				continue
+			}
+			charge := balances[i] * d.APR / 100 / 12
+			balances[i] += charge
+			interest[i] += charge
+		}
+
+		available := budget
+		for i, d := range debts {
+			if balances[i] <= paidOff {
+				continue
+			}
+			payment := math.Min(d.MinimumPayment, balances[i])
+			balances[i] -= payment
+			available -= payment
+			plan.TotalPaid += payment
+		}
+
+		for _, i := range order {
+			if available <= 0 {
+				break
+			}
+			if balances[i] <= paidOff {
+				continue
+			}
+			payment := math.Min(available, balances[i])
+			balances[i] -= payment
+			available -= payment
+			plan.TotalPaid += payment
+		}
+
+		for i := range debts {
+			if payoffMonth[i] == 0 && balances[i] <= paidOff {
+				payoffMonth[i] = month
+				remaining--
+			}
+		}
+	}
+
+	plan.Complete = remaining == 0
+	plan.Months = month
+	plan.TotalPaid = round(plan.TotalPaid)
+
+	for i, d := range debts {
+		plan.TotalInterest += interest[i]
+		plan.Payoffs = append(plan.Payoffs, DebtPayoff{
+			ID:       d.ID,
+			Name:     d.Name,
+			Month:    payoffMonth[i],
+			Interest: round(interest[i]),
+		})
+	}
+	plan.TotalInterest = round(plan.TotalInterest)
+
+	slices.SortStableFunc(plan.Payoffs, func(a, b DebtPayoff) int {
+		switch {
+		case a.Month == b.Month:
+			return 0
+		case a.Month == 0:
+			return 1
+		case b.Month == 0:
+			return -1
+		default:
+			return a.Month - b.Month
+		}
+	})
+
+	return plan
+}
+
+// Returns debt indexes in the order a strategy targets them. Ties fall back to the other strategy's rule
+func strategyOrder(debts []Debt, strategy Strategy) []int {
+	order := make([]int, len(debts))
+	for i := range debts {
+		order[i] = i
+	}
+
+	byRate := func(a, b Debt) int {
+		switch {
+		case a.APR > b.APR:
+			return -1
+		case a.APR < b.APR:
+			return 1
+		}
+		return 0
+	}
+	byBalance := func(a, b Debt) int {
+		switch {
+		case a.Balance < b.Balance:
+			return -1
+		case a.Balance > b.Balance:
+			return 1
+		}
+		return 0
+	}
+
+	slices.SortStableFunc(order, func(i, j int) int {
+		if strategy == Snowball {
+			if c := byBalance(debts[i], debts[j]); c != 0 {
+				return c
+			}
+			return byRate(debts[i], debts[j])
+		}
+		if c := byRate(debts[i], debts[j]); c != 0 {
+			return c
+		}
+		return byBalance(debts[i], debts[j])
+	})
+
+	return order
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,146 @@
+package payoff_test
+
+import (
+	"testing"
+
+	"github.com/jms-guy/greed/cli/internal/payoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name          string
+		debts         []payoff.Debt
+		extra         float64
+		strategy      payoff.Strategy
+		wantMonths    int
+		wantComplete  bool
+		wantOrder     []string
+		wantUnpaidIDs []string
+	}{
+		{
+			name:         "no debts",
+			strategy:     payoff.Avalanche,
+			wantMonths:   0,
+			wantComplete: true,
+			wantOrder:    []string{},
+		},
+		{
+			name: "interest free debt paid by its minimum",
+			debts: []payoff.Debt{
+				{ID: "loan", Balance: 1000, MinimumPayment: 100},
+			},
+			strategy:     payoff.Avalanche,
+			wantMonths:   10,
+			wantComplete: true,
+			wantOrder:    []string{"loan"},
+		},
+		{
+			name: "paid off debt is left out",
+			debts: []payoff.Debt{
+				{ID: "card", Balance: 0, MinimumPayment: 25},
+				{ID: "loan", Balance: 5000, APR: 20, MinimumPayment: 100},
+			},
+			strategy:     payoff.Avalanche,
+			wantMonths:   109,
+			wantComplete: true,
+			wantOrder:    []string{"loan"},
+		},
+		{
+			name: "credit balance is left out",
+			debts: []payoff.Debt{
+				{ID: "card", Balance: -40, MinimumPayment: 0},
+				{ID: "loan", Balance: 300, MinimumPayment: 100},
+			},
+			strategy:     payoff.Snowball,
+			wantMonths:   3,
+			wantComplete: true,
+			wantOrder:    []string{"loan"},
+		},
+		{
+			name: "avalanche pays highest rate first",
+			debts: []payoff.Debt{
+				{ID: "small", Balance: 500, APR: 5, MinimumPayment: 10},
+				{ID: "costly", Balance: 2000, APR: 25, MinimumPayment: 10},
+			},
+			extra:        500,
+			strategy:     payoff.Avalanche,
+			wantMonths:   6,
+			wantComplete: true,
+			wantOrder:    []string{"costly", "small"},
+		},
+		{
+			name: "snowball pays smallest balance first",
+			debts: []payoff.Debt{
+				{ID: "costly", Balance: 2000, APR: 25, MinimumPayment: 10},
+				{ID: "small", Balance: 500, APR: 5, MinimumPayment: 10},
+			},
+			extra:        500,
+			strategy:     payoff.Snowball,
+			wantMonths:   6,
+			wantComplete: true,
+			wantOrder:    []string{"small", "costly"},
+		},
+		{
+			name: "payments below interest never finish",
+			debts: []payoff.Debt{
+				{ID: "loan", Balance: 10000, APR: 30, MinimumPayment: 100},
+			},
+			strategy:      payoff.Avalanche,
+			wantMonths:    payoff.MaxMonths,
+			wantComplete:  false,
+			wantOrder:     []string{"loan"},
+			wantUnpaidIDs: []string{"loan"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := payoff.Simulate(tc.debts, tc.extra, tc.strategy)
+
+			assert.Equal(t, tc.wantMonths, plan.Months)
+			assert.Equal(t, tc.wantComplete, plan.Complete)
+
+			order := []string{}
+			unpaid := []string{}
+			for _, p := range plan.Payoffs {
+				order = append(order, p.ID)
+				if p.Month == 0 {
+					unpaid = append(unpaid, p.ID)
+				}
+			}
+			assert.Equal(t, tc.wantOrder, order)
+			if tc.wantUnpaidIDs == nil {
+				assert.Empty(t, unpaid)
+			} else {
+				assert.Equal(t, tc.wantUnpaidIDs, unpaid)
+			}
+		})
+	}
+}
+
+func TestParseStrategies(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []payoff.Strategy
+		wantErr bool
+	}{
+		{name: "empty returns both", input: "", want: []payoff.Strategy{payoff.Avalanche, payoff.Snowball}},
+		{name: "avalanche", input: "avalanche", want: []payoff.Strategy{payoff.Avalanche}},
+		{name: "snowball", input: "snowball", want: []payoff.Strategy{payoff.Snowball}},
+		{name: "unknown", input: "hailstorm", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := payoff.ParseStrategies(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// How often the spinner of running tasks advances
+const tickInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+type taskState int
+
+const (
+	pending taskState = iota
+	running
+	done
+	failed
+)
+
+type task struct {
+	name   string
+	state  taskState
+	status string
+}
+
+// Progress display for tasks running side by side, one line per task. On a terminal the lines are redrawn
+// in place as tasks report progress. Elsewhere, each update is printed as a line of its own, so output stays
+// readable when piped or logged. Safe for use from multiple goroutines
+type Board struct {
+	mu    sync.Mutex
+	w     io.Writer
+	live  bool
+	tasks []task
+	width int // Width of the longest task name, for aligning statuses
+	frame int
+	drawn int // Lines drawn by the last redraw, for moving back over them
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// Creates a board for the named tasks, all pending. A live board redraws itself until Stop is called
+func NewBoard(w io.Writer, live bool, names []string) *Board {
+	b := &Board{w: w, live: live, stop: make(chan struct{})}
+	for _, name := range names {
+		b.tasks = append(b.tasks, task{name: name, status: "waiting"})
+		b.width = max(b.width, utf8.RuneCountInString(name))
+	}
+
+	if live {
+		b.redraw()
+		b.wg.Add(1)
+		go b.tick()
+	}
+
+	return b
+}
+
+// Marks a task as running, with a message describing its current step
+func (b *Board) Update(i int, status string) {
+	b.set(i, running, status)
+}
+
+// Marks a task as finished
+func (b *Board) Done(i int, status string) {
+	b.set(i, done, status)
+}
+
+// Marks a task as failed, with a message describing the failure
+func (b *Board) Fail(i int, status string) {
+	b.set(i, failed, status)
+}
+
+// Stops redrawing a live board, leaving its final state on screen
+func (b *Board) Stop() {
+	if !b.live {
+		return
+	}
+	close(b.stop)
+	b.wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.redraw()
+}
+
+func (b *Board) set(i int, state taskState, status string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tasks[i].state = state
+	b.tasks[i].status = status
+
+	if b.live {
+		b.redraw()
+		return
+	}
+	fmt.Fprintln(b.w, b.line(b.tasks[i]))
+}
+
+func (b *Board) tick() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			b.frame = (b.frame + 1) % len(spinnerFrames)
+			b.redraw()
+			b.mu.Unlock()
+		}
+	}
+}
+
+// Moves the cursor back over the lines last drawn and draws every task again. Callers hold the lock
+func (b *Board) redraw() {
+	var sb strings.Builder
+	if b.drawn > 0 {
+		fmt.Fprintf(&sb, "\x1b[%dA", b.drawn)
+	}
+	for _, t := range b.tasks {
+		sb.WriteString("\r\x1b[2K")
+		sb.WriteString(b.line(t))
+		sb.WriteString("\n")
+	}
+	fmt.Fprint(b.w, sb.String())
+	b.drawn = len(b.tasks)
+}
+
+func (b *Board) line(t task) string {
+	name := t.name + strings.Repeat(" ", b.width-utf8.RuneCountInString(t.name))
+
+	switch t.state {
+	case running:
+		icon := ">"
+		if b.live {
+			icon = color.CyanString(spinnerFrames[b.frame])
+		}
+		return fmt.Sprintf(" %s %s  %s", icon, name, t.status)
+	case done:
+		return fmt.Sprintf(" %s %s  %s", color.GreenString("✓"), name, t.status)
+	case failed:
+		return fmt.Sprintf(" %s %s  %s", color.RedString("✗"), name, color.RedString(t.status))
+	default:
+		return fmt.Sprintf(" %s %s  %s", "·", name, t.status)
+	}
+}
@@ -0,0 +1,124 @@
+package resolve
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Returned when nothing matches a query
+var ErrNoMatch = errors.New("no match")
+
+// Returned for an empty or blank query, which would otherwise be the start of every name
+var ErrEmptyQuery = errors.New("empty query")
+
+// Something a command argument can name, such as an account or an item
+type Candidate struct {
+	ID    string
+	Names []string // Names it can be given by, such as a nickname and an institution's name
+	Mask  string   // Last digits of an account number, matched by queries like *1234
+	Label string   // How it is described when asking the user to choose between matches
+}
+
+// Returned when a query matches more than one candidate equally well
+type AmbiguousError struct {
+	Query   string
+	Matches []Candidate
+}
+
+func (e *AmbiguousError) Error() string {
+	labels := make([]string, len(e.Matches))
+	for i, m := range e.Matches {
+		labels[i] = m.Label
+	}
+	return fmt.Sprintf("%q matches more than one: %s", e.Query, strings.Join(labels, ", "))
+}
+
+// Finds the candidate a query refers to. In order, a query matches an exact ID, an exact name ignoring case,
+// a mask given as *1234, or the start of a name ignoring case. The first of these to match anything decides
+// the result, so an exact name is never ambiguous with a longer name it is the start of. Blank queries match nothing
+func Match(query string, candidates []Candidate) (Candidate, error) {
+	if strings.TrimSpace(query) == "" {
+		return Candidate{}, ErrEmptyQuery
+	}
+
+	for _, c := range candidates {
+		if c.ID == query {
+			return c, nil
+		}
+	}
+
+	matchers := []func(Candidate) bool{
+		func(c Candidate) bool {
+			for _, name := range c.Names {
+				if strings.EqualFold(name, query) {
+					return true
+				}
+			}
+			return false
+		},
+		func(c Candidate) bool {
+			mask, ok := strings.CutPrefix(query, "*")
+			return ok && mask != "" && c.Mask == mask
+		},
+		func(c Candidate) bool {
+			for _, name := range c.Names {
+				if HasPrefixFold(name, query) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+
+	for _, matches := range matchers {
+		var found []Candidate
+		for _, c := range candidates {
+			if matches(c) {
+				found = append(found, c)
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			return Candidate{}, &AmbiguousError{Query: query, Matches: found}
+		}
+	}
+
+	return Candidate{}, fmt.Errorf("%w for %q", ErrNoMatch, query)
+}
+
+// Asks the user to choose between ambiguous matches by number, reading the choice from in
+func Choose(in io.Reader, out io.Writer, amb *AmbiguousError) (Candidate, error) {
+	fmt.Fprintf(out, "%q matches more than one:\n", amb.Query)
+	for i, m := range amb.Matches {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, m.Label)
+	}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "Choose 1-%d: ", len(amb.Matches))
+		if !scanner.Scan() {
+			return Candidate{}, amb
+		}
+
+		choice, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+		if err != nil || choice < 1 || choice > len(amb.Matches) {
+			fmt.Fprintln(out, "Invalid choice")
+			continue
+		}
+
+		return amb.Matches[choice-1], nil
+	}
+}
+
+// Reports whether s starts with prefix, ignoring case
+func HasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,115 @@
+package resolve_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jms-guy/greed/cli/internal/resolve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var candidates = []resolve.Candidate{
+	{ID: "acc-1", Names: []string{"Chequing"}, Mask: "1234", Label: "Chequing *1234"},
+	{ID: "acc-2", Names: []string{"Everyday", "Chequing Plus"}, Mask: "5678", Label: "Everyday [Chequing Plus *5678]"},
+	{ID: "acc-3", Names: []string{"Savings"}, Mask: "9012", Label: "Savings *9012"},
+	{ID: "acc-4", Names: []string{"Savings Goal"}, Mask: "3456", Label: "Savings Goal *3456"},
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		wantID        string
+		wantAmbiguous []string
+		wantErr       error
+	}{
+		{name: "exact ID", query: "acc-3", wantID: "acc-3"},
+		{name: "exact name", query: "Everyday", wantID: "acc-2"},
+		{name: "exact name ignoring case", query: "cHEQUING", wantID: "acc-1"},
+		{name: "exact name over longer names it starts", query: "savings", wantID: "acc-3"},
+		{name: "second name", query: "chequing plus", wantID: "acc-2"},
+		{name: "mask", query: "*5678", wantID: "acc-2"},
+		{name: "unique prefix", query: "every", wantID: "acc-2"},
+		{name: "unique prefix ignoring case", query: "SAVINGS G", wantID: "acc-4"},
+		{name: "ambiguous prefix", query: "cheq", wantAmbiguous: []string{"acc-1", "acc-2"}},
+		{name: "unknown mask", query: "*0000", wantErr: resolve.ErrNoMatch},
+		{name: "bare mask marker", query: "*", wantErr: resolve.ErrNoMatch},
+		{name: "no match", query: "Mortgage", wantErr: resolve.ErrNoMatch},
+		{name: "empty", query: "", wantErr: resolve.ErrEmptyQuery},
+		{name: "blank", query: "   ", wantErr: resolve.ErrEmptyQuery},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			match, err := resolve.Match(tc.query, candidates)
+
+			if tc.wantAmbiguous != nil {
+				var amb *resolve.AmbiguousError
+				require.True(t, errors.As(err, &amb), "expected ambiguous error, got %v", err)
+				ids := []string{}
+				for _, m := range amb.Matches {
+					ids = append(ids, m.ID)
+				}
+				assert.Equal(t, tc.wantAmbiguous, ids)
+				return
+			}
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantID, match.ID)
+		})
+	}
+}
+
+func TestChoose(t *testing.T) {
+	amb := &resolve.AmbiguousError{Query: "cheq", Matches: candidates[:2]}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantID  string
+		wantErr bool
+	}{
+		{name: "valid choice", input: "2\n", wantID: "acc-2"},
+		{name: "invalid choices are asked again", input: "0\nthree\n1\n", wantID: "acc-1"},
+		{name: "no choice", input: "", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			match, err := resolve.Choose(strings.NewReader(tc.input), out, amb)
+
+			if tc.wantErr {
+				assert.ErrorAs(t, err, &amb)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantID, match.ID)
+			assert.Contains(t, out.String(), "1) Chequing *1234")
+		})
+	}
+}
+
+func TestHasPrefixFold(t *testing.T) {
+	tests := []struct {
+		s, prefix string
+		want      bool
+	}{
+		{"Chequing", "cheq", true},
+		{"Chequing", "CHEQUING", true},
+		{"Chequing", "chequings", false},
+		{"Chequing", "sav", false},
+		{"Chequing", "", true},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, resolve.HasPrefixFold(tc.s, tc.prefix), "HasPrefixFold(%q, %q)", tc.s, tc.prefix)
+	}
+}
@@ -2,6 +2,7 @@ package tables
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/jms-guy/greed/cli/internal/database"
@@ -9,6 +10,30 @@ import (
 	"github.com/rodaine/table"
 )
 
+// Name an account is shown by, its nickname if it has one
+func AccountDisplayName(acc database.Account) string {
+	if acc.Nickname.Valid && acc.Nickname.String != "" {
+		return acc.Nickname.String
+	}
+	return acc.Name
+}
+
+// Sums the current balances of the accounts counted towards net worth, with credit and loan balances owed
+func NetWorth(accounts []database.Account) float64 {
+	total := 0.0
+	for _, acc := range accounts {
+		if !acc.IncludeInNetWorth {
+			continue
+		}
+		if acc.Type == "credit" || acc.Type == "loan" {
+			total -= acc.CurrentBalance.Float64
+			continue
+		}
+		total += acc.CurrentBalance.Float64
+	}
+	return total
+}
+
 // Format a table for a single account record
 func MakeSingleAccountTable(acc database.Account) table.Table {
 	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
@@ -21,6 +46,8 @@ func MakeSingleAccountTable(acc database.Account) table.Table {
 		"  |  ",
 		"Name",
 		"  |  ",
+		"Nickname",
+		"  |  ",
 		"Type",
 		"  |  ",
 		"Subtype",
@@ -35,6 +62,8 @@ func MakeSingleAccountTable(acc database.Account) table.Table {
 		"  |  ",
 		"Currency Code",
 		"  |  ",
+		"Status",
+		"  |  ",
 		"Created at",
 		"  |  ",
 		"Updated at",
@@ -48,6 +77,8 @@ func MakeSingleAccountTable(acc database.Account) table.Table {
 		"  |  ",
 		acc.Name,
 		"  |  ",
+		orDash(acc.Nickname.String),
+		"  |  ",
 		acc.Type,
 		"  |  ",
 		acc.Subtype.String,
@@ -62,6 +93,8 @@ func MakeSingleAccountTable(acc database.Account) table.Table {
 		"  |  ",
 		acc.IsoCurrencyCode.String,
 		"  |  ",
+		accountStatus(acc.Hidden, acc.Archived, acc.IncludeInNetWorth),
+		"  |  ",
 		acc.CreatedAt,
 		"  |  ",
 		acc.UpdatedAt,
@@ -95,7 +128,7 @@ func MakeAccountsTable(accounts []models.Account, institutionName string) table.
 		tbl.AddRow(
 			fmt.Sprintf("|%s", institutionName),
 			"  |  ",
-			acc.Name,
+			acc.DisplayName,
 			"  |  ",
 			acc.Type,
 			"  |  ",
@@ -136,7 +169,7 @@ func MakeAccountsTableAllItems(accounts []database.Account) table.Table {
 		tbl.AddRow(
 			fmt.Sprintf("|%s", acc.InstitutionName.String),
 			"  |  ",
-			acc.Name,
+			AccountDisplayName(acc),
 			"  |  ",
 			acc.Type,
 			"  |  ",
@@ -151,3 +184,18 @@ func MakeAccountsTableAllItems(accounts []database.Account) table.Table {
 
 	return tbl
 }
+
+// Describes how an account is shown, such as "hidden, archived", or "-" for a visible account counted in net worth
+func accountStatus(hidden, archived, inNetWorth bool) string {
+	var status []string
+	if hidden {
+		status = append(status, "hidden")
+	}
+	if archived {
+		status = append(status, "archived")
+	}
+	if !inNetWorth {
+		status = append(status, "excluded from net worth")
+	}
+	return orDash(strings.Join(status, ", "))
+}
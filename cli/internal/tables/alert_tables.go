@@ -0,0 +1,62 @@
+package tables
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of flagged anomalies. IDs are shortened to their first 8 characters, enough to
+// acknowledge an alert with. Account names are looked up by account ID
+func MakeAlertsTable(anomalies []models.Anomaly, accountNames map[string]string) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|ID",
+		"  |  ",
+		"Date",
+		"  |  ",
+		"Account",
+		"  |  ",
+		"Merchant",
+		"  |  ",
+		"Amount",
+		"  |  ",
+		"Kind",
+		"  |  ",
+		"Reason",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, a := range anomalies {
+		account, ok := accountNames[a.AccountID]
+		if !ok {
+			account = a.AccountID
+		}
+		kind := a.Kind
+		if a.AcknowledgedAt != nil {
+			kind += " (ack)"
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", a.ID.String()[:8]),
+			"  |  ",
+			a.Date.Format("2006-01-02"),
+			"  |  ",
+			account,
+			"  |  ",
+			a.MerchantName,
+			"  |  ",
+			fmt.Sprintf("%s %s", a.Amount, a.IsoCurrencyCode),
+			"  |  ",
+			kind,
+			"  |  ",
+			a.Reason,
+		)
+	}
+
+	return tbl
+}
@@ -0,0 +1,57 @@
+package tables
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of API keys. IDs are shortened to their first 8 characters, enough to revoke a key with
+func MakeAPIKeysTable(keys []models.APIKey) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|ID",
+		"  |  ",
+		"Name",
+		"  |  ",
+		"Key",
+		"  |  ",
+		"Scope",
+		"  |  ",
+		"Expires",
+		"  |  ",
+		"Last Used",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, k := range keys {
+		expires := "never"
+		if k.ExpiresAt != nil {
+			expires = k.ExpiresAt.Local().Format("2006-01-02 15:04")
+		}
+		lastUsed := "never"
+		if k.LastUsedAt != nil {
+			lastUsed = k.LastUsedAt.Local().Format("2006-01-02 15:04")
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", k.ID.String()[:8]),
+			"  |  ",
+			k.Name,
+			"  |  ",
+			k.Prefix+"...",
+			"  |  ",
+			k.Scope,
+			"  |  ",
+			expires,
+			"  |  ",
+			lastUsed,
+		)
+	}
+
+	return tbl
+}
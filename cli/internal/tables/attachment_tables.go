@@ -0,0 +1,56 @@
+package tables
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of a transaction's attachments. IDs are shortened to their first 8 characters, enough to download an attachment with
+func MakeAttachmentsTable(attachments []models.TransactionAttachment) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|ID",
+		"  |  ",
+		"File",
+		"  |  ",
+		"Type",
+		"  |  ",
+		"Size",
+		"  |  ",
+		"Added",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, a := range attachments {
+		tbl.AddRow(
+			fmt.Sprintf("|%s", a.ID[:8]),
+			"  |  ",
+			a.FileName,
+			"  |  ",
+			a.ContentType,
+			"  |  ",
+			formatFileSize(a.SizeBytes),
+			"  |  ",
+			a.CreatedAt.Local().Format("2006-01-02 15:04"),
+		)
+	}
+
+	return tbl
+}
+
+// Formats a number of bytes in the largest unit it fills
+func formatFileSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,97 @@
+package tables
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Matches ANSI colour codes, so coloured cells don't widen their columns
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Make table comparing spending between two periods. Rows are limited to the given number, 0 showing all.
+// Spending going up is shown in red and going down in green
+func MakeTableForComparison(rows []models.ComparisonRow, total models.ComparisonRow, keyName, period, against string, limit int) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|"+keyName,
+		"  |  ",
+		period,
+		"  |  ",
+		against,
+		"  |  ",
+		"Change",
+		"  |  ",
+		"Change %",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	tbl.WithWidthFunc(func(s string) int {
+		return utf8.RuneCountInString(ansiRegex.ReplaceAllString(s, ""))
+	})
+
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	for _, r := range append(rows, total) {
+		tbl.AddRow(
+			fmt.Sprintf("|%s", r.Key),
+			"  |  ",
+			r.Current,
+			"  |  ",
+			r.Previous,
+			"  |  ",
+			colorDelta(r.Delta, signedAmount(r.Delta)),
+			"  |  ",
+			colorDelta(r.Delta, formatPct(r)),
+		)
+	}
+
+	return tbl
+}
+
+// Adds a plus sign to increases
+func signedAmount(amount string) string {
+	if strings.HasPrefix(amount, "-") || isZero(amount) {
+		return amount
+	}
+	return "+" + amount
+}
+
+// Formats a row's percentage change, "new" when there was nothing to compare against
+func formatPct(r models.ComparisonRow) string {
+	switch {
+	case r.DeltaPct != nil && *r.DeltaPct > 0:
+		return fmt.Sprintf("+%.1f%%", *r.DeltaPct)
+	case r.DeltaPct != nil:
+		return fmt.Sprintf("%.1f%%", *r.DeltaPct)
+	case isZero(r.Delta):
+		return "-"
+	default:
+		return "new"
+	}
+}
+
+// Reports whether a formatted amount is zero
+func isZero(amount string) bool {
+	return strings.Trim(amount, "-0.") == ""
+}
+
+// Colours a cell by the direction of the change
+func colorDelta(delta, cell string) string {
+	switch {
+	case isZero(delta):
+		return cell
+	case strings.HasPrefix(delta, "-"):
+		return color.GreenString(cell)
+	default:
+		return color.RedString(cell)
+	}
+}
@@ -0,0 +1,138 @@
+package tables
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of event endpoints. IDs are shortened to their first 8 characters, enough to remove
+// an endpoint with
+func MakeEventEndpointsTable(endpoints []models.EventEndpoint) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|ID",
+		"  |  ",
+		"URL",
+		"  |  ",
+		"Events",
+		"  |  ",
+		"Created",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, e := range endpoints {
+		eventTypes := "all"
+		if len(e.EventTypes) > 0 {
+			eventTypes = strings.Join(e.EventTypes, ", ")
+		}
+		url := e.URL
+		if !e.Enabled {
+			url += " (disabled)"
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", e.ID.String()[:8]),
+			"  |  ",
+			url,
+			"  |  ",
+			eventTypes,
+			"  |  ",
+			e.CreatedAt.Local().Format("2006-01-02 15:04"),
+		)
+	}
+
+	return tbl
+}
+
+// Make table of events, oldest first
+func MakeEventsTable(events []models.Event) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|Seq",
+		"  |  ",
+		"Created",
+		"  |  ",
+		"Type",
+		"  |  ",
+		"Data",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, e := range events {
+		data := string(e.Data)
+		if utf8.RuneCountInString(data) > 80 {
+			data = string([]rune(data)[:77]) + "..."
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%d", e.Seq),
+			"  |  ",
+			e.CreatedAt.Local().Format("2006-01-02 15:04"),
+			"  |  ",
+			e.Type,
+			"  |  ",
+			data,
+		)
+	}
+
+	return tbl
+}
+
+// Make table of deliveries to an event endpoint, newest first
+func MakeEventDeliveriesTable(deliveries []models.EventDelivery) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|Seq",
+		"  |  ",
+		"Created",
+		"  |  ",
+		"Type",
+		"  |  ",
+		"Status",
+		"  |  ",
+		"Attempts",
+		"  |  ",
+		"Last Error",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	tbl.WithWidthFunc(func(s string) int {
+		return utf8.RuneCountInString(ansiRegex.ReplaceAllString(s, ""))
+	})
+
+	for _, d := range deliveries {
+		status := d.Status
+		switch d.Status {
+		case "delivered":
+			status = color.GreenString(d.Status)
+		case "failed":
+			status = color.RedString(d.Status)
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%d", d.Seq),
+			"  |  ",
+			d.CreatedAt.Local().Format("2006-01-02 15:04"),
+			"  |  ",
+			d.Type,
+			"  |  ",
+			status,
+			"  |  ",
+			d.Attempts,
+			"  |  ",
+			d.LastError,
+		)
+	}
+
+	return tbl
+}
@@ -0,0 +1,141 @@
+package tables
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of an account's holdings, with a totals row. Gains are shown in green and losses in red.
+// Holdings without a cost basis show dashes in place of cost basis and gain
+func MakeTableForHoldings(holdings models.Holdings) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|Security",
+		"  |  ",
+		"Quantity",
+		"  |  ",
+		"Price",
+		"  |  ",
+		"Value",
+		"  |  ",
+		"Cost Basis",
+		"  |  ",
+		"Unrealized Gain",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	tbl.WithWidthFunc(func(s string) int {
+		return utf8.RuneCountInString(ansiRegex.ReplaceAllString(s, ""))
+	})
+
+	for _, h := range holdings.Holdings {
+		security := h.TickerSymbol
+		if security == "" {
+			security = h.Name
+		}
+		costBasis, gain := "-", "-"
+		if h.CostBasis != "" {
+			costBasis = h.CostBasis
+			gain = colorGain(h.UnrealizedGain)
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", security),
+			"  |  ",
+			trimQuantity(h.Quantity),
+			"  |  ",
+			h.Price,
+			"  |  ",
+			fmt.Sprintf("%s %s", h.Value, h.IsoCurrencyCode),
+			"  |  ",
+			costBasis,
+			"  |  ",
+			gain,
+		)
+	}
+
+	tbl.AddRow(
+		"|Total",
+		"  |  ",
+		"",
+		"  |  ",
+		"",
+		"  |  ",
+		holdings.TotalValue,
+		"  |  ",
+		holdings.TotalCostBasis,
+		"  |  ",
+		colorGain(holdings.UnrealizedGain),
+	)
+
+	return tbl
+}
+
+// Make table of an account's investment transactions
+func MakeTableForInvestmentTransactions(txns []models.InvestmentTransaction) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|Date",
+		"  |  ",
+		"Type",
+		"  |  ",
+		"Security",
+		"  |  ",
+		"Quantity",
+		"  |  ",
+		"Price",
+		"  |  ",
+		"Amount",
+		"  |  ",
+		"Description",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, t := range txns {
+		tbl.AddRow(
+			fmt.Sprintf("|%s", t.Date.Format("2006-01-02")),
+			"  |  ",
+			t.Subtype,
+			"  |  ",
+			t.TickerSymbol,
+			"  |  ",
+			trimQuantity(t.Quantity),
+			"  |  ",
+			t.Price,
+			"  |  ",
+			fmt.Sprintf("%s %s", t.Amount, t.IsoCurrencyCode),
+			"  |  ",
+			t.Name,
+		)
+	}
+
+	return tbl
+}
+
+// Drops trailing zeros from a quantity, which the server returns to 8 decimal places
+func trimQuantity(quantity string) string {
+	if !strings.Contains(quantity, ".") {
+		return quantity
+	}
+	return strings.TrimRight(strings.TrimRight(quantity, "0"), ".")
+}
+
+// Colours a gain green and a loss red
+func colorGain(gain string) string {
+	switch {
+	case isZero(gain):
+		return gain
+	case strings.HasPrefix(gain, "-"):
+		return color.RedString(gain)
+	default:
+		return color.GreenString("+" + gain)
+	}
+}
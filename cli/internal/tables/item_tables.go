@@ -0,0 +1,89 @@
+package tables
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of a user's items with their health, coloured green when healthy, yellow when the item's
+// consent runs out soon and red when it needs attention
+func MakeTableForItems(items []models.ItemName, now time.Time) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|Item",
+		"  |  ",
+		"Institution",
+		"  |  ",
+		"Health",
+		"  |  ",
+		"Last Synced",
+		"  |  ",
+		"Details",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	tbl.WithWidthFunc(func(s string) int {
+		return utf8.RuneCountInString(ansiRegex.ReplaceAllString(s, ""))
+	})
+
+	for _, i := range items {
+		lastSynced := "never"
+		if i.LastSyncedAt != nil {
+			lastSynced = i.LastSyncedAt.Local().Format("2006-01-02 15:04")
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", i.Nickname),
+			"  |  ",
+			i.InstitutionName,
+			"  |  ",
+			formatItemHealth(i.Health),
+			"  |  ",
+			lastSynced,
+			"  |  ",
+			itemHealthDetails(i, now),
+		)
+	}
+
+	return tbl
+}
+
+func formatItemHealth(health string) string {
+	switch health {
+	case models.ItemHealthOK:
+		return color.GreenString("ok")
+	case models.ItemHealthExpiring:
+		return color.YellowString("expiring")
+	case models.ItemHealthNeedsReauth:
+		return color.RedString("needs re-auth")
+	case models.ItemHealthError:
+		return color.RedString("error")
+	default:
+		return "unknown"
+	}
+}
+
+// Describes the last error of an item and when its consent runs out
+func itemHealthDetails(item models.ItemName, now time.Time) string {
+	details := ""
+	if item.LastErrorCode != "" {
+		details = item.LastErrorCode
+	}
+	if item.ConsentExpiresAt != nil {
+		if details != "" {
+			details += ", "
+		}
+		if item.ConsentExpiresAt.Before(now) {
+			details += "consent expired " + item.ConsentExpiresAt.Local().Format("2006-01-02")
+		} else {
+			details += "consent expires " + item.ConsentExpiresAt.Local().Format("2006-01-02")
+		}
+	}
+	return orDash(details)
+}
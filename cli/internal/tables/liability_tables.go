@@ -0,0 +1,123 @@
+package tables
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/cli/internal/payoff"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of a user's debts, keyed to account names by account ID. Overdue payments are shown in red
+func MakeTableForDebts(liabilities []models.Liability, names map[string]string) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|Account",
+		"  |  ",
+		"Type",
+		"  |  ",
+		"Balance",
+		"  |  ",
+		"APR",
+		"  |  ",
+		"Minimum Payment",
+		"  |  ",
+		"Next Due",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	tbl.WithWidthFunc(func(s string) int {
+		return utf8.RuneCountInString(ansiRegex.ReplaceAllString(s, ""))
+	})
+
+	for _, l := range liabilities {
+		apr := "-"
+		if l.InterestRate != "" {
+			apr = trimQuantity(l.InterestRate) + "%"
+		}
+		due := orDash(l.NextPaymentDueDate)
+		if l.IsOverdue {
+			due = color.RedString(due + " (overdue)")
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", names[l.AccountID]),
+			"  |  ",
+			l.Type,
+			"  |  ",
+			orDash(l.Balance),
+			"  |  ",
+			apr,
+			"  |  ",
+			orDash(l.MinimumPayment),
+			"  |  ",
+			due,
+		)
+	}
+
+	return tbl
+}
+
+// Make table of a payoff plan's schedule, with the month each debt is paid off counted from start.
+// Debts the plan never pays off are shown in red
+func MakeTableForPayoffPlan(plan payoff.Plan, start time.Time) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|#",
+		"  |  ",
+		"Account",
+		"  |  ",
+		"Paid Off",
+		"  |  ",
+		"Interest Paid",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	tbl.WithWidthFunc(func(s string) int {
+		return utf8.RuneCountInString(ansiRegex.ReplaceAllString(s, ""))
+	})
+
+	for i, p := range plan.Payoffs {
+		paidOff := color.RedString("never")
+		if p.Month > 0 {
+			paidOff = fmt.Sprintf("%s (%s)", start.AddDate(0, p.Month, 0).Format("Jan 2006"), formatMonths(p.Month))
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%d", i+1),
+			"  |  ",
+			p.Name,
+			"  |  ",
+			paidOff,
+			"  |  ",
+			fmt.Sprintf("%.2f", p.Interest),
+		)
+	}
+
+	return tbl
+}
+
+// Formats a number of months as years and months, such as "2y 3m"
+func formatMonths(months int) string {
+	parts := []string{}
+	if months >= 12 {
+		parts = append(parts, fmt.Sprintf("%dy", months/12))
+	}
+	if months%12 != 0 {
+		parts = append(parts, fmt.Sprintf("%dm", months%12))
+	}
+	return strings.Join(parts, " ")
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,68 @@
+package tables
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of the canonical merchants of a user's transactions, with the raw names normalized to each
+func MakeMerchantsTable(merchants []models.Merchant) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|Merchant",
+		"  |  ",
+		"Transactions",
+		"  |  ",
+		"Reported As",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, m := range merchants {
+		tbl.AddRow(
+			fmt.Sprintf("|%s", m.Name),
+			"  |  ",
+			m.TransactionCount,
+			"  |  ",
+			strings.Join(m.RawNames, ", "),
+		)
+	}
+
+	return tbl
+}
+
+// Make table of a user's merchant aliases. IDs are shortened to their first 8 characters, enough to remove an alias with
+func MakeMerchantAliasesTable(aliases []models.MerchantAlias) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|ID",
+		"  |  ",
+		"Raw Name",
+		"  |  ",
+		"Merchant",
+		"  |  ",
+		"Added",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, a := range aliases {
+		tbl.AddRow(
+			fmt.Sprintf("|%s", a.ID.String()[:8]),
+			"  |  ",
+			a.RawName,
+			"  |  ",
+			a.Merchant,
+			"  |  ",
+			a.CreatedAt.Local().Format("2006-01-02"),
+		)
+	}
+
+	return tbl
+}
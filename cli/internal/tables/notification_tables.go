@@ -0,0 +1,119 @@
+package tables
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make table of notification rules. IDs are shortened to their first 8 characters, enough to remove
+// a rule with. Account names are looked up by account ID
+func MakeNotificationRulesTable(rules []models.NotificationRule, accountNames map[string]string) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|ID",
+		"  |  ",
+		"Kind",
+		"  |  ",
+		"Threshold",
+		"  |  ",
+		"Category",
+		"  |  ",
+		"Account",
+		"  |  ",
+		"Channel",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for _, r := range rules {
+		account := "all"
+		if r.AccountID != "" {
+			account = r.AccountID
+			if name, ok := accountNames[r.AccountID]; ok {
+				account = name
+			}
+		}
+		channel := r.Channel
+		if r.WebhookURL != "" {
+			channel = fmt.Sprintf("%s (%s)", r.Channel, r.WebhookURL)
+		}
+		if !r.Enabled {
+			channel += " (disabled)"
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", r.ID.String()[:8]),
+			"  |  ",
+			r.Kind,
+			"  |  ",
+			r.Threshold,
+			"  |  ",
+			r.Category,
+			"  |  ",
+			account,
+			"  |  ",
+			channel,
+		)
+	}
+
+	return tbl
+}
+
+// Make table of notification deliveries, newest first
+func MakeNotificationDeliveriesTable(deliveries []models.NotificationDelivery) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|ID",
+		"  |  ",
+		"Created",
+		"  |  ",
+		"Channel",
+		"  |  ",
+		"Subject",
+		"  |  ",
+		"Status",
+		"  |  ",
+		"Attempts",
+		"  |  ",
+		"Last Error",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	tbl.WithWidthFunc(func(s string) int {
+		return utf8.RuneCountInString(ansiRegex.ReplaceAllString(s, ""))
+	})
+
+	for _, d := range deliveries {
+		status := d.Status
+		switch d.Status {
+		case "delivered":
+			status = color.GreenString(d.Status)
+		case "failed":
+			status = color.RedString(d.Status)
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", d.ID.String()[:8]),
+			"  |  ",
+			d.CreatedAt.Local().Format("2006-01-02 15:04"),
+			"  |  ",
+			d.Channel,
+			"  |  ",
+			d.Subject,
+			"  |  ",
+			status,
+			"  |  ",
+			d.Attempts,
+			"  |  ",
+			d.LastError,
+		)
+	}
+
+	return tbl
+}
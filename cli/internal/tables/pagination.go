@@ -9,244 +9,6 @@ import (
 	"github.com/jms-guy/greed/models"
 )
 
-// Takes slice of transaction records, and paginates the results into a table, displaying a base number of 20 transaction records at a time.
-// Listens for pgUp/pgDown key presses to view through record pages
-func PaginateTransactionsTable(txns []models.Transaction, accountName string, balances []float64, pageSize int, isFiltered bool, recurring models.RecurringData) error {
-	if len(txns) == 0 {
-		return fmt.Errorf("no results to display")
-	}
-
-	screen, err := tcell.NewScreen()
-	if err != nil {
-		return fmt.Errorf("error creating terminal screen: %w", err)
-	}
-	defer screen.Fini()
-
-	err = screen.Init()
-	if err != nil {
-		return fmt.Errorf("error initializing terminal screen: %w", err)
-	}
-
-	currentPage := 1
-	endIndex := 0
-
-	for {
-		screen.Clear()
-
-		// Determine indexes of transaction items to display
-		startIndex := (currentPage - 1) * pageSize
-		var displayItems []models.Transaction
-
-		if startIndex >= len(txns) {
-			displayItems = []models.Transaction{}
-		} else {
-
-			endIndex = min(startIndex+pageSize, len(txns))
-			displayItems = txns[startIndex:endIndex]
-		}
-
-		var displayBalances []float64
-
-		if !(startIndex >= len(balances)) {
-			endIndex = min(startIndex+pageSize, len(balances))
-			displayBalances = balances[startIndex:endIndex]
-		}
-
-		CreateTable(screen, displayItems, accountName, displayBalances, isFiltered, recurring)
-		screen.Show()
-
-		event := screen.PollEvent()
-
-		switch event := event.(type) {
-		case *tcell.EventKey:
-			switch event.Key() {
-			case tcell.KeyPgDn:
-				if endIndex < len(txns) {
-					currentPage++
-					continue
-				}
-			case tcell.KeyPgUp:
-				if currentPage > 1 {
-					currentPage--
-					continue
-				}
-			case tcell.KeyEscape:
-				return nil
-			}
-		}
-	}
-}
-
-// Draws a table of transaction data onto the tcell screen
-func CreateTable(screen tcell.Screen, displayItems []models.Transaction, accountName string, balances []float64, isFiltered bool, recurring models.RecurringData) {
-	// Maps for recurring lookup
-	connectionMap := make(map[string]string)
-	for _, c := range recurring.Connections {
-		connectionMap[c.TransactionID] = c.StreamID
-	}
-
-	streamMap := make(map[string]models.RecurringStream)
-	for _, s := range recurring.Streams {
-		streamMap[s.ID] = s
-	}
-
-	// Define tcell screen styles and variables to create table
-	headerStyle := tcell.StyleDefault.Foreground(tcell.ColorGreen).Underline(true)
-	columnStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow)
-
-	var columnHeaders []string
-	var columnWidths []int
-
-	if isFiltered {
-		columnHeaders = []string{"Account", "Date", "Amount", "Merchant Name", "Payment Channel", "Category", "Currency Code", "Recurring"}
-		columnWidths = []int{10, 12, 10, 20, 15, 15, 10, 15}
-	} else {
-		columnHeaders = []string{"Account", "Date", "Balance", "Amount", "Merchant Name", "Payment Channel", "Category", "Currency Code", "Recurring"}
-		columnWidths = []int{10, 12, 10, 10, 20, 15, 15, 10, 15}
-	}
-	columnPadding := 5
-
-	currentX, currentY := 10, 0
-
-	// Draw table headers
-	for i, header := range columnHeaders {
-		for _, r := range header {
-			screen.SetContent(currentX, currentY, r, nil, headerStyle)
-			currentX++
-		}
-
-		currentX += columnWidths[i] - len(header) + columnPadding
-	}
-	currentY += 2
-
-	// Draw transaction rows
-	for i, txn := range displayItems {
-		currentX = 10
-
-		accountStr := accountName
-		if len(accountStr) > 10 {
-			accountStr = accountStr[:10]
-		}
-		for _, r := range fmt.Sprintf("%-*s", 10, accountStr) {
-			screen.SetContent(currentX, currentY, r, nil, columnStyle)
-			currentX++
-		}
-		currentX += columnPadding
-
-		dateStr := txn.Date.Format("2006-01-02")
-		if len(dateStr) > 12 {
-			dateStr = dateStr[:12]
-		}
-		for _, r := range fmt.Sprintf("%-*s", 12, dateStr) {
-			screen.SetContent(currentX, currentY, r, nil, columnStyle)
-			currentX++
-		}
-		currentX += columnPadding
-
-		if !isFiltered {
-			balanceStr := strconv.FormatFloat(balances[i], 'f', 2, 64)
-			if len(balanceStr) > 10 {
-				balanceStr = balanceStr[:10]
-			}
-			for _, r := range fmt.Sprintf("%-*s", 10, balanceStr) {
-				screen.SetContent(currentX, currentY, r, nil, columnStyle)
-				currentX++
-			}
-			currentX += columnPadding
-		}
-
-		amountStr := txn.Amount
-		if len(amountStr) > 10 {
-			amountStr = amountStr[:10]
-		}
-		for _, r := range fmt.Sprintf("%-*s", 10, amountStr) {
-			screen.SetContent(currentX, currentY, r, nil, columnStyle)
-			currentX++
-		}
-		currentX += columnPadding
-
-		merchantStr := txn.MerchantName
-		if len(merchantStr) > 20 {
-			merchantStr = merchantStr[:20]
-		}
-		for _, r := range fmt.Sprintf("%-*s", 20, merchantStr) {
-			screen.SetContent(currentX, currentY, r, nil, columnStyle)
-			currentX++
-		}
-		currentX += columnPadding
-
-		channelStr := txn.PaymentChannel
-		if len(channelStr) > 15 {
-			channelStr = channelStr[:15]
-		}
-		for _, r := range fmt.Sprintf("%-*s", 15, channelStr) {
-			screen.SetContent(currentX, currentY, r, nil, columnStyle)
-			currentX++
-		}
-		currentX += columnPadding
-
-		categoryStr := txn.PersonalFinanceCategory
-		if len(categoryStr) > 15 {
-			categoryStr = categoryStr[:15]
-		}
-		for _, r := range fmt.Sprintf("%-*s", 15, categoryStr) {
-			screen.SetContent(currentX, currentY, r, nil, columnStyle)
-			currentX++
-		}
-		currentX += columnPadding
-
-		currencyStr := txn.IsoCurrencyCode
-		if len(currencyStr) > 10 {
-			currencyStr = currencyStr[:10]
-		}
-		for _, r := range fmt.Sprintf("%-*s", 10, currencyStr) {
-			screen.SetContent(currentX, currentY, r, nil, columnStyle)
-			currentX++
-		}
-		currentX += columnPadding
-
-		// Recurring transaction lookup
-		if streamID, exists := connectionMap[txn.Id]; exists {
-			if stream, streamExists := streamMap[streamID]; streamExists {
-				recString := "recurring " + stream.StreamType
-				for _, r := range fmt.Sprintf("%-*s", 15, recString) {
-					screen.SetContent(currentX, currentY, r, nil, columnStyle)
-					currentX++
-				}
-			} else {
-				recString := ""
-				for _, r := range fmt.Sprintf("%-*s", 15, recString) {
-					screen.SetContent(currentX, currentY, r, nil, columnStyle)
-					currentX++
-				}
-			}
-		} else {
-			recString := ""
-			for _, r := range fmt.Sprintf("%-*s", 15, recString) {
-				screen.SetContent(currentX, currentY, r, nil, columnStyle)
-				currentX++
-			}
-		}
-
-		currentY++
-	}
-
-	currentX = 10
-	currentY += 2
-	exitStr := "Press the 'esc' key to close table."
-	instructions := "Use the 'pageUp' and 'pageDown' keys to scroll table."
-	for _, r := range exitStr {
-		screen.SetContent(currentX, currentY, r, nil, headerStyle)
-		currentX++
-	}
-	currentY++
-	currentX = 10
-	for _, r := range instructions {
-		screen.SetContent(currentX, currentY, r, nil, headerStyle)
-		currentX++
-	}
-}
-
 func PaginateSummariesTable(summaries []models.MerchantSummary, accountName, merchant string, pageSize int) error {
 	if len(summaries) == 0 {
 		return fmt.Errorf("no results to display")
@@ -0,0 +1,69 @@
+package tables
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+)
+
+// Outcome of syncing one item, for the sync summary table
+type SyncResult struct {
+	Item     string
+	Err      error
+	Added    int
+	Modified int
+	Removed  int
+}
+
+// Make table summarizing the transactions changed by syncing several items, with a total row.
+// Items that failed to sync are shown in red
+func MakeTableForSyncSummary(results []SyncResult) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New(
+		"|Item",
+		"  |  ",
+		"Status",
+		"  |  ",
+		"Added",
+		"  |  ",
+		"Modified",
+		"  |  ",
+		"Removed",
+	)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	tbl.WithWidthFunc(func(s string) int {
+		return utf8.RuneCountInString(ansiRegex.ReplaceAllString(s, ""))
+	})
+
+	var total SyncResult
+	for _, r := range results {
+		status := color.GreenString("synced")
+		if r.Err != nil {
+			status = color.RedString("failed")
+		}
+
+		tbl.AddRow(
+			fmt.Sprintf("|%s", r.Item),
+			"  |  ",
+			status,
+			"  |  ",
+			r.Added,
+			"  |  ",
+			r.Modified,
+			"  |  ",
+			r.Removed,
+		)
+
+		total.Added += r.Added
+		total.Modified += r.Modified
+		total.Removed += r.Removed
+	}
+
+	tbl.AddRow("|Total", "  |  ", "", "  |  ", total.Added, "  |  ", total.Modified, "  |  ", total.Removed)
+
+	return tbl
+}
@@ -0,0 +1,690 @@
+package tables
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/jms-guy/greed/models"
+)
+
+// Optional actions of the transactions pager, provided by the calling command
+type PagerActions struct {
+	Tags   func(txnID string) ([]string, error)            // Fetches a transaction's tags, shown in the detail pop-up
+	Export func(txns []models.Transaction) (string, error) // Exports transactions, returning the path of the file written
+}
+
+// Pager screen styles
+var (
+	pagerHeaderStyle   = tcell.StyleDefault.Foreground(tcell.ColorGreen).Underline(true)
+	pagerColumnStyle   = tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	pagerSelectedStyle = tcell.StyleDefault.Reverse(true)
+	pagerMatchStyle    = tcell.StyleDefault.Foreground(tcell.ColorAqua)
+	pagerInfoStyle     = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	pagerMutedStyle    = tcell.StyleDefault.Foreground(tcell.ColorGray)
+)
+
+const (
+	pagerMargin    = 2
+	pagerColumnGap = 3
+	pagerTopRows   = 3 // Title line, blank line and headers
+	pagerBottom    = 4 // Blank line, status line and two help lines
+)
+
+// A transaction with the values the pager displays for it
+type pagerRow struct {
+	txn       models.Transaction
+	amount    float64
+	balance   string
+	recurring string
+	stream    *models.RecurringStream
+}
+
+// Column of the transactions pager
+type pagerColumn struct {
+	header   string
+	maxWidth int  // Widest the column is drawn when the terminal is narrow
+	flex     bool // Flexible columns absorb spare width, and are shrunk first when space runs out
+	value    func(r pagerRow) string
+	less     func(a, b pagerRow) bool
+}
+
+// State of the interactive transactions pager
+type txnPager struct {
+	screen      tcell.Screen
+	accountName string
+	rows        []pagerRow
+	columns     []pagerColumn
+	order       []int // Indexes into rows, in display order
+	pageSize    int
+	actions     PagerActions
+
+	cursor int // Selected position within order
+	offset int // First position within order drawn on screen
+
+	sortCol  int // Column sorted on, -1 for the order transactions were given in
+	sortDesc bool
+
+	searching   bool
+	query       []rune
+	searchStart int
+
+	detail bool
+	tags   map[string][]string
+	status string
+}
+
+// Takes slice of transaction records, and displays them in an interactive pager. Rows can be sorted by any column,
+// searched, opened in a detail pop-up, copied to the clipboard or exported. pageSize caps the number of rows shown at once,
+// fewer are shown if the terminal is too short
+func PaginateTransactionsTable(txns []models.Transaction, accountName string, balances []float64, pageSize int, isFiltered bool, recurring models.RecurringData, actions PagerActions) error {
+	if len(txns) == 0 {
+		return fmt.Errorf("no results to display")
+	}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return fmt.Errorf("error creating terminal screen: %w", err)
+	}
+	defer screen.Fini()
+
+	err = screen.Init()
+	if err != nil {
+		return fmt.Errorf("error initializing terminal screen: %w", err)
+	}
+
+	p := newTxnPager(screen, txns, accountName, balances, pageSize, isFiltered, recurring, actions)
+
+	for {
+		p.draw()
+
+		switch event := screen.PollEvent().(type) {
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			if quit := p.handleKey(event); quit {
+				return nil
+			}
+		}
+	}
+}
+
+func newTxnPager(screen tcell.Screen, txns []models.Transaction, accountName string, balances []float64, pageSize int, isFiltered bool, recurring models.RecurringData, actions PagerActions) *txnPager {
+	connectionMap := make(map[string]string)
+	for _, c := range recurring.Connections {
+		connectionMap[c.TransactionID] = c.StreamID
+	}
+
+	streamMap := make(map[string]models.RecurringStream)
+	for _, s := range recurring.Streams {
+		streamMap[s.ID] = s
+	}
+
+	rows := make([]pagerRow, len(txns))
+	order := make([]int, len(txns))
+	for i, txn := range txns {
+		row := pagerRow{txn: txn}
+		row.amount, _ = strconv.ParseFloat(txn.Amount, 64)
+		if i < len(balances) {
+			row.balance = strconv.FormatFloat(balances[i], 'f', 2, 64)
+		}
+		if stream, exists := streamMap[connectionMap[txn.Id]]; exists {
+			row.stream = &stream
+			row.recurring = "recurring " + stream.StreamType
+		}
+
+		rows[i] = row
+		order[i] = i
+	}
+
+	return &txnPager{
+		screen:      screen,
+		accountName: accountName,
+		rows:        rows,
+		columns:     transactionColumns(accountName, isFiltered),
+		order:       order,
+		pageSize:    max(pageSize, 1),
+		actions:     actions,
+		sortCol:     -1,
+		tags:        make(map[string][]string),
+	}
+}
+
+// Columns of the pager. The balance column is left out of filtered results, as running balances are only valid for full history
+func transactionColumns(accountName string, isFiltered bool) []pagerColumn {
+	byString := func(value func(r pagerRow) string) func(a, b pagerRow) bool {
+		return func(a, b pagerRow) bool {
+			return strings.ToLower(value(a)) < strings.ToLower(value(b))
+		}
+	}
+
+	account := func(r pagerRow) string { return accountName }
+	date := func(r pagerRow) string { return r.txn.Date.Format("2006-01-02") }
+	balance := func(r pagerRow) string { return r.balance }
+	amount := func(r pagerRow) string { return r.txn.Amount }
+	merchant := func(r pagerRow) string { return r.txn.MerchantName }
+	channel := func(r pagerRow) string { return r.txn.PaymentChannel }
+	category := func(r pagerRow) string { return r.txn.PersonalFinanceCategory }
+	currency := func(r pagerRow) string { return r.txn.IsoCurrencyCode }
+	recurring := func(r pagerRow) string { return r.recurring }
+
+	columns := []pagerColumn{
+		{header: "Account", maxWidth: 10, value: account, less: byString(account)},
+		{header: "Date", maxWidth: 10, value: date, less: func(a, b pagerRow) bool { return a.txn.Date.Before(b.txn.Date) }},
+	}
+	if !isFiltered {
+		columns = append(columns, pagerColumn{header: "Balance", maxWidth: 12, value: balance, less: func(a, b pagerRow) bool {
+			x, _ := strconv.ParseFloat(a.balance, 64)
+			y, _ := strconv.ParseFloat(b.balance, 64)
+			return x < y
+		}})
+	}
+	columns = append(columns,
+		pagerColumn{header: "Amount", maxWidth: 12, value: amount, less: func(a, b pagerRow) bool { return a.amount < b.amount }},
+		pagerColumn{header: "Merchant Name", maxWidth: 20, flex: true, value: merchant, less: byString(merchant)},
+		pagerColumn{header: "Payment Channel", maxWidth: 15, value: channel, less: byString(channel)},
+		pagerColumn{header: "Category", maxWidth: 15, flex: true, value: category, less: byString(category)},
+		pagerColumn{header: "Currency Code", maxWidth: 10, value: currency, less: byString(currency)},
+		pagerColumn{header: "Recurring", maxWidth: 18, value: recurring, less: byString(recurring)},
+	)
+
+	return columns
+}
+
+// Handles a key press, returning true when the pager should close
+func (p *txnPager) handleKey(ev *tcell.EventKey) bool {
+	if p.searching {
+		p.handleSearchKey(ev)
+		return false
+	}
+
+	if p.detail {
+		switch ev.Key() {
+		case tcell.KeyEscape, tcell.KeyEnter:
+			p.detail = false
+		case tcell.KeyRune:
+			if ev.Rune() == 'q' {
+				p.detail = false
+			} else if ev.Rune() == 'y' {
+				p.copySelected()
+			}
+		}
+		return false
+	}
+
+	p.status = ""
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return true
+	case tcell.KeyUp:
+		p.move(-1)
+	case tcell.KeyDown:
+		p.move(1)
+	case tcell.KeyPgUp:
+		p.move(-p.visibleRows())
+	case tcell.KeyPgDn:
+		p.move(p.visibleRows())
+	case tcell.KeyHome:
+		p.cursor = 0
+	case tcell.KeyEnd:
+		p.cursor = len(p.order) - 1
+	case tcell.KeyEnter:
+		p.openDetail()
+	case tcell.KeyRune:
+		switch r := ev.Rune(); {
+		case r == 'q':
+			return true
+		case r == 'k':
+			p.move(-1)
+		case r == 'j':
+			p.move(1)
+		case r == 'g':
+			p.cursor = 0
+		case r == 'G':
+			p.cursor = len(p.order) - 1
+		case r == '/':
+			p.searching = true
+			p.query = p.query[:0]
+			p.searchStart = p.cursor
+		case r == 'n':
+			p.jumpToMatch(1)
+		case r == 'N':
+			p.jumpToMatch(-1)
+		case r == '0':
+			p.sortBy(-1)
+		case r >= '1' && r <= '9':
+			p.sortBy(int(r - '1'))
+		case r == 'y':
+			p.copySelected()
+		case r == 'e':
+			p.exportVisible()
+		}
+	}
+
+	return false
+}
+
+// Incremental search: the selection jumps to the first match as the query is typed
+func (p *txnPager) handleSearchKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		p.searching = false
+		p.query = p.query[:0]
+		p.cursor = p.searchStart
+		return
+	case tcell.KeyEnter:
+		p.searching = false
+		if len(p.query) != 0 {
+			p.status = fmt.Sprintf("%d matches, n/N: next/previous match", p.matchCount())
+		}
+		return
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(p.query) > 0 {
+			p.query = p.query[:len(p.query)-1]
+		}
+	case tcell.KeyRune:
+		p.query = append(p.query, ev.Rune())
+	default:
+		return
+	}
+
+	p.cursor = p.searchStart
+	if len(p.query) != 0 && !p.matches(p.order[p.cursor]) {
+		p.jumpToMatch(1)
+	}
+}
+
+func (p *txnPager) move(delta int) {
+	p.cursor = min(max(p.cursor+delta, 0), len(p.order)-1)
+}
+
+// Sorts rows by a column, toggling direction when the column is already sorted on. -1 restores the original order
+func (p *txnPager) sortBy(col int) {
+	if col >= len(p.columns) {
+		return
+	}
+
+	selected := p.order[p.cursor]
+
+	switch {
+	case col < 0:
+		p.sortCol, p.sortDesc = -1, false
+	case col == p.sortCol:
+		p.sortDesc = !p.sortDesc
+	default:
+		p.sortCol, p.sortDesc = col, false
+	}
+
+	for i := range p.order {
+		p.order[i] = i
+	}
+	if p.sortCol >= 0 {
+		less := p.columns[p.sortCol].less
+		slices.SortStableFunc(p.order, func(a, b int) int {
+			x, y := p.rows[a], p.rows[b]
+			if p.sortDesc {
+				x, y = y, x
+			}
+			switch {
+			case less(x, y):
+				return -1
+			case less(y, x):
+				return 1
+			}
+			return 0
+		})
+	}
+
+	// Keep the same transaction selected
+	p.cursor = slices.Index(p.order, selected)
+}
+
+// Reports whether a row contains the search query in any column
+func (p *txnPager) matches(rowIdx int) bool {
+	if len(p.query) == 0 {
+		return false
+	}
+
+	query := strings.ToLower(string(p.query))
+	for _, c := range p.columns {
+		if strings.Contains(strings.ToLower(c.value(p.rows[rowIdx])), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *txnPager) matchCount() int {
+	count := 0
+	for _, idx := range p.order {
+		if p.matches(idx) {
+			count++
+		}
+	}
+	return count
+}
+
+// Moves the selection to the next (dir 1) or previous (dir -1) match, wrapping around
+func (p *txnPager) jumpToMatch(dir int) {
+	if len(p.query) == 0 {
+		return
+	}
+
+	n := len(p.order)
+	for step := 1; step <= n; step++ {
+		pos := ((p.cursor+dir*step)%n + n) % n
+		if p.matches(p.order[pos]) {
+			p.cursor = pos
+			return
+		}
+	}
+	p.status = fmt.Sprintf("No matches for '%s'", string(p.query))
+}
+
+// Opens the detail pop-up for the selected row, fetching its tags the first time
+func (p *txnPager) openDetail() {
+	p.detail = true
+
+	txn := p.rows[p.order[p.cursor]].txn
+	if _, fetched := p.tags[txn.Id]; fetched || p.actions.Tags == nil {
+		return
+	}
+
+	tags, err := p.actions.Tags(txn.Id)
+	if err != nil {
+		p.status = "Could not load tags: " + err.Error()
+		return
+	}
+	p.tags[txn.Id] = tags
+}
+
+// Copies the selected row to the system clipboard as tab-separated values
+func (p *txnPager) copySelected() {
+	row := p.rows[p.order[p.cursor]]
+
+	values := make([]string, len(p.columns))
+	for i, c := range p.columns {
+		values[i] = c.value(row)
+	}
+
+	p.screen.SetClipboard([]byte(strings.Join(values, "\t")))
+	p.status = "Row copied to clipboard"
+}
+
+// Exports the transactions in their current order
+func (p *txnPager) exportVisible() {
+	if p.actions.Export == nil {
+		p.status = "Export is not available here"
+		return
+	}
+
+	txns := make([]models.Transaction, len(p.order))
+	for i, idx := range p.order {
+		txns[i] = p.rows[idx].txn
+	}
+
+	path, err := p.actions.Export(txns)
+	if err != nil {
+		p.status = "Export failed: " + err.Error()
+		return
+	}
+	p.status = fmt.Sprintf("Exported %d transactions to %s", len(txns), path)
+}
+
+// Number of rows drawn at once, limited by the page size and the terminal's height
+func (p *txnPager) visibleRows() int {
+	_, h := p.screen.Size()
+	return max(min(p.pageSize, h-pagerTopRows-pagerBottom), 1)
+}
+
+// Fits column widths to the terminal width. Columns start wide enough for their header and values, with values capped
+// at the column's maximum width. Flexible columns grow to fit their longest value when there is room. When there isn't,
+// headers are cut to the width of their values first, then flexible columns shrink, then all columns
+func (p *txnPager) columnWidths(available int) []int {
+	n := len(p.columns)
+	widths := make([]int, n)
+	longest := make([]int, n)
+	valueFloor := make([]int, n)
+	total := pagerColumnGap * (n - 1)
+
+	for i, c := range p.columns {
+		for _, row := range p.rows {
+			longest[i] = max(longest[i], len([]rune(c.value(row))))
+		}
+		valueFloor[i] = max(min(longest[i], c.maxWidth), 4)
+		widths[i] = max(valueFloor[i], len([]rune(p.headerText(i))))
+		total += widths[i]
+	}
+
+	for i, c := range p.columns {
+		if c.flex && total < available && longest[i] > widths[i] {
+			grow := min(longest[i]-widths[i], available-total)
+			widths[i] += grow
+			total += grow
+		}
+	}
+
+	floors := []func(i int) int{
+		func(i int) int { return valueFloor[i] },
+		func(i int) int {
+			if p.columns[i].flex {
+				return min(valueFloor[i], 8)
+			}
+			return valueFloor[i]
+		},
+		func(i int) int { return 4 },
+	}
+
+	// Shrink one cell at a time across columns, so no single column takes the whole cut
+	for _, floor := range floors {
+		for total > available {
+			shrunk := false
+			for i := n - 1; i >= 0 && total > available; i-- {
+				if widths[i] > floor(i) {
+					widths[i]--
+					total--
+					shrunk = true
+				}
+			}
+			if !shrunk {
+				break
+			}
+		}
+	}
+
+	return widths
+}
+
+// Column header, numbered with its sort key and marked with the sort direction
+func (p *txnPager) headerText(col int) string {
+	text := fmt.Sprintf("%d %s", col+1, p.columns[col].header)
+	if col == p.sortCol {
+		if p.sortDesc {
+			text += " ▼"
+		} else {
+			text += " ▲"
+		}
+	}
+	return text
+}
+
+// Draws the pager
+func (p *txnPager) draw() {
+	p.screen.Clear()
+	w, h := p.screen.Size()
+
+	rows := p.visibleRows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+rows {
+		p.offset = p.cursor - rows + 1
+	}
+
+	sortText := "given order"
+	if p.sortCol >= 0 {
+		sortText = p.columns[p.sortCol].header
+		if p.sortDesc {
+			sortText += " descending"
+		}
+	}
+	title := fmt.Sprintf("%s - %d transactions - sorted by %s - row %d/%d", p.accountName, len(p.order), sortText, p.cursor+1, len(p.order))
+	p.drawText(pagerMargin, 0, w-pagerMargin, pagerInfoStyle, title)
+
+	widths := p.columnWidths(w - 2*pagerMargin)
+
+	x := pagerMargin
+	for i := range p.columns {
+		p.drawText(x, 2, min(widths[i], w-x), pagerHeaderStyle, p.headerText(i))
+		x += widths[i] + pagerColumnGap
+	}
+
+	for i := 0; i < rows && p.offset+i < len(p.order); i++ {
+		pos := p.offset + i
+		row := p.rows[p.order[pos]]
+
+		style := pagerColumnStyle
+		if p.matches(p.order[pos]) {
+			style = pagerMatchStyle
+		}
+		if pos == p.cursor {
+			style = pagerSelectedStyle
+		}
+
+		line := ""
+		for j, c := range p.columns {
+			line += fmt.Sprintf("%-*s", widths[j]+pagerColumnGap, cut(c.value(row), widths[j]))
+		}
+		p.drawText(pagerMargin, pagerTopRows+i, w-2*pagerMargin, style, strings.TrimRight(line, " "))
+	}
+
+	statusY := h - pagerBottom + 1
+	switch {
+	case p.searching:
+		p.drawText(pagerMargin, statusY, w-pagerMargin, pagerInfoStyle, "/"+string(p.query)+"_")
+	case p.status != "":
+		p.drawText(pagerMargin, statusY, w-pagerMargin, pagerInfoStyle, p.status)
+	}
+
+	p.drawText(pagerMargin, h-2, w-pagerMargin, pagerHeaderStyle, "↑/↓ j/k: move  pgUp/pgDown: page  1-9: sort by column (again to reverse)  0: original order")
+	p.drawText(pagerMargin, h-1, w-pagerMargin, pagerHeaderStyle, "/: search  n/N: next/previous match  enter: details  y: copy row  e: export  esc/q: close")
+
+	if p.detail {
+		p.drawDetail(w, h)
+	}
+
+	p.screen.Show()
+}
+
+// Draws the detail pop-up for the selected transaction
+func (p *txnPager) drawDetail(w, h int) {
+	row := p.rows[p.order[p.cursor]]
+	txn := row.txn
+
+	lines := [][2]string{
+		{"Date", txn.Date.Format("2006-01-02")},
+		{"Amount", txn.Amount + " " + txn.IsoCurrencyCode},
+		{"Merchant", txn.MerchantName},
+		{"Category", txn.PersonalFinanceCategory},
+		{"Payment channel", txn.PaymentChannel},
+	}
+	if row.balance != "" {
+		lines = append(lines, [2]string{"Balance", row.balance})
+	}
+	lines = append(lines, [2]string{"Transaction ID", txn.Id})
+
+	if s := row.stream; s != nil {
+		status := "inactive"
+		if s.IsActive {
+			status = "active"
+		}
+		lines = append(lines,
+			[2]string{"Recurring", fmt.Sprintf("%s %s stream, %s", strings.ToLower(s.Frequency), s.StreamType, status)},
+			[2]string{"Stream", s.Description},
+			[2]string{"Next expected", s.PredictedNextDate},
+		)
+	} else {
+		lines = append(lines, [2]string{"Recurring", "no"})
+	}
+
+	tags, fetched := p.tags[txn.Id]
+	switch {
+	case len(tags) != 0:
+		lines = append(lines, [2]string{"Tags", "#" + strings.Join(tags, " #")})
+	case fetched:
+		lines = append(lines, [2]string{"Tags", "none"})
+	}
+
+	boxW := min(max(w*2/3, 40), w-2)
+	labelW := 17
+	valueW := max(boxW-labelW-4, 1)
+
+	// Wrap long values onto extra lines
+	var rendered []string
+	for _, l := range lines {
+		value := []rune(l[1])
+		label := l[0] + ":"
+		for first := true; first || len(value) > 0; first = false {
+			chunk := value[:min(valueW, len(value))]
+			value = []rune(strings.TrimLeft(string(value[len(chunk):]), " "))
+			rendered = append(rendered, fmt.Sprintf("%-*s%s", labelW, label, string(chunk)))
+			label = ""
+		}
+	}
+	rendered = append(rendered, "", "esc/enter: close  y: copy row")
+
+	boxH := min(len(rendered)+2, h)
+	x0 := (w - boxW) / 2
+	y0 := max((h-boxH)/2, 0)
+
+	for y := y0; y < y0+boxH; y++ {
+		for x := x0; x < x0+boxW; x++ {
+			p.screen.SetContent(x, y, ' ', nil, tcell.StyleDefault)
+		}
+	}
+	for x := x0 + 1; x < x0+boxW-1; x++ {
+		p.screen.SetContent(x, y0, tcell.RuneHLine, nil, pagerInfoStyle)
+		p.screen.SetContent(x, y0+boxH-1, tcell.RuneHLine, nil, pagerInfoStyle)
+	}
+	for y := y0 + 1; y < y0+boxH-1; y++ {
+		p.screen.SetContent(x0, y, tcell.RuneVLine, nil, pagerInfoStyle)
+		p.screen.SetContent(x0+boxW-1, y, tcell.RuneVLine, nil, pagerInfoStyle)
+	}
+	p.screen.SetContent(x0, y0, tcell.RuneULCorner, nil, pagerInfoStyle)
+	p.screen.SetContent(x0+boxW-1, y0, tcell.RuneURCorner, nil, pagerInfoStyle)
+	p.screen.SetContent(x0, y0+boxH-1, tcell.RuneLLCorner, nil, pagerInfoStyle)
+	p.screen.SetContent(x0+boxW-1, y0+boxH-1, tcell.RuneLRCorner, nil, pagerInfoStyle)
+	p.drawText(x0+2, y0, boxW-4, pagerInfoStyle, " Transaction details ")
+
+	for i, line := range rendered {
+		if i >= boxH-2 {
+			break
+		}
+		style := pagerColumnStyle
+		if i == len(rendered)-1 {
+			style = pagerMutedStyle
+		}
+		p.drawText(x0+2, y0+1+i, boxW-4, style, line)
+	}
+}
+
+// Draws text on a single line, cut off at maxWidth cells
+func (p *txnPager) drawText(x, y, maxWidth int, style tcell.Style, text string) {
+	for i, r := range []rune(text) {
+		if i >= maxWidth {
+			return
+		}
+		p.screen.SetContent(x+i, y, r, nil, style)
+	}
+}
+
+// Cuts a string down to at most n runes
+func cut(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,66 @@
+package tables
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/jms-guy/greed/models"
+	"github.com/rodaine/table"
+)
+
+// Make a static table of transaction records, used in place of the paginated table when output is not a terminal
+func MakeTransactionsTable(txns []models.Transaction, accountName string, balances []float64, isFiltered bool, recurring models.RecurringData) table.Table {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	connectionMap := make(map[string]string)
+	for _, c := range recurring.Connections {
+		connectionMap[c.TransactionID] = c.StreamID
+	}
+
+	streamMap := make(map[string]models.RecurringStream)
+	for _, s := range recurring.Streams {
+		streamMap[s.ID] = s
+	}
+
+	headers := []any{"|Account", "  |  ", "Date", "  |  "}
+	if !isFiltered {
+		headers = append(headers, "Balance", "  |  ")
+	}
+	headers = append(headers, "Amount", "  |  ", "Merchant Name", "  |  ", "Payment Channel", "  |  ", "Category", "  |  ", "Currency Code", "  |  ", "Recurring")
+
+	tbl := table.New(headers...)
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+	for i, txn := range txns {
+		recString := ""
+		if stream, exists := streamMap[connectionMap[txn.Id]]; exists {
+			recString = "recurring " + stream.StreamType
+		}
+
+		row := []any{fmt.Sprintf("|%s", accountName), "  |  ", txn.Date.Format("2006-01-02"), "  |  "}
+		if !isFiltered && i < len(balances) {
+			row = append(row, strconv.FormatFloat(balances[i], 'f', 2, 64), "  |  ")
+		} else if !isFiltered {
+			row = append(row, "", "  |  ")
+		}
+		row = append(row,
+			txn.Amount,
+			"  |  ",
+			txn.MerchantName,
+			"  |  ",
+			txn.PaymentChannel,
+			"  |  ",
+			txn.PersonalFinanceCategory,
+			"  |  ",
+			txn.IsoCurrencyCode,
+			"  |  ",
+			recString,
+		)
+
+		tbl.AddRow(row...)
+	}
+
+	return tbl
+}
@@ -0,0 +1,473 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/jms-guy/greed/models"
+)
+
+/*
+	This package draws the `greed ui` dashboard: a full-screen tcell application with an items/accounts sidebar,
+	a filterable transaction list, a monthly income/expense chart and a recurring streams panel.
+	It does not talk to the server itself - all data and actions are supplied by the caller through a Source.
+*/
+
+// Data and actions used by the dashboard
+type Source struct {
+	Items        func() ([]models.ItemName, error)
+	Accounts     func(itemID string) ([]models.Account, error)
+	Transactions func(accountID string) ([]models.Transaction, error)
+	Monetary     func(accountID string) ([]models.MonetaryData, error)
+	Recurring    func(accountID string) (models.RecurringData, error)
+	SetCategory  func(accountID, transactionID, category string) (models.Transaction, error)
+	AddTag       func(accountID, transactionID, tag string) (models.TransactionTags, error)
+	Sync         func(item models.ItemName) error // Run with the screen suspended, so it may print to the terminal
+}
+
+// Pane with keyboard focus
+type pane int
+
+const (
+	paneSidebar pane = iota
+	paneTransactions
+)
+
+// Input mode of the dashboard
+type mode int
+
+const (
+	modeNormal mode = iota
+	modeFilter      // Typing into the transaction filter
+	modePrompt      // Typing into an action prompt
+)
+
+// Single selectable line in the sidebar, either an item or one of its accounts
+type sidebarRow struct {
+	item    models.ItemName
+	account *models.Account
+}
+
+// Dashboard state
+type dashboard struct {
+	screen tcell.Screen
+	src    Source
+
+	sidebar    []sidebarRow
+	sidebarIdx int
+
+	account   *models.Account
+	txns      []models.Transaction
+	visible   []int // Indexes into txns matching the current filter
+	txnIdx    int   // Index into visible of the selected transaction
+	txnOffset int   // First visible transaction drawn on screen
+	monetary  []models.MonetaryData
+	recurring models.RecurringData
+	streams   map[string]models.RecurringStream // Recurring stream for each transaction ID
+	tags      map[string][]string               // Tags added to transactions during this session
+
+	focus  pane
+	mode   mode
+	filter []rune
+
+	prompt       string
+	promptInput  []rune
+	promptAction func(input string)
+
+	status string
+}
+
+// Runs the dashboard until the user quits
+func Run(src Source) error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return fmt.Errorf("error creating terminal screen: %w", err)
+	}
+
+	err = screen.Init()
+	if err != nil {
+		return fmt.Errorf("error initializing terminal screen: %w", err)
+	}
+	defer screen.Fini()
+
+	d := &dashboard{
+		screen:  screen,
+		src:     src,
+		streams: map[string]models.RecurringStream{},
+		tags:    map[string][]string{},
+	}
+
+	if err := d.loadSidebar(); err != nil {
+		return err
+	}
+	d.selectFirstAccount()
+
+	for {
+		d.draw()
+
+		switch event := screen.PollEvent().(type) {
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			if quit := d.handleKey(event); quit {
+				return nil
+			}
+		}
+	}
+}
+
+// Loads items and their accounts into the sidebar
+func (d *dashboard) loadSidebar() error {
+	items, err := d.src.Items()
+	if err != nil {
+		return fmt.Errorf("error getting items: %w", err)
+	}
+
+	d.sidebar = nil
+	for _, item := range items {
+		d.sidebar = append(d.sidebar, sidebarRow{item: item})
+
+		accounts, err := d.src.Accounts(item.ItemId)
+		if err != nil {
+			return fmt.Errorf("error getting accounts for item %s: %w", item.Nickname, err)
+		}
+		for i := range accounts {
+			if accounts[i].Hidden || accounts[i].Archived {
+				continue
+			}
+			d.sidebar = append(d.sidebar, sidebarRow{item: item, account: &accounts[i]})
+		}
+	}
+
+	if d.sidebarIdx >= len(d.sidebar) {
+		d.sidebarIdx = max(len(d.sidebar)-1, 0)
+	}
+
+	return nil
+}
+
+// Selects and loads the first account in the sidebar, if there is one
+func (d *dashboard) selectFirstAccount() {
+	for i, row := range d.sidebar {
+		if row.account != nil {
+			d.sidebarIdx = i
+			d.loadAccount(row.account)
+			return
+		}
+	}
+	d.status = "No accounts found, use 'greed add-item' and 'greed fetch' to add accounts"
+}
+
+// Loads transactions, monetary and recurring data for an account
+func (d *dashboard) loadAccount(acc *models.Account) {
+	txns, err := d.src.Transactions(acc.Id)
+	if err != nil {
+		d.status = fmt.Sprintf("Error getting transactions: %s", err)
+		return
+	}
+
+	monetary, err := d.src.Monetary(acc.Id)
+	if err != nil {
+		d.status = fmt.Sprintf("Error getting income data: %s", err)
+		return
+	}
+
+	recurring, err := d.src.Recurring(acc.Id)
+	if err != nil {
+		d.status = fmt.Sprintf("Error getting recurring data: %s", err)
+		return
+	}
+
+	streamsByID := map[string]models.RecurringStream{}
+	for _, s := range recurring.Streams {
+		streamsByID[s.ID] = s
+	}
+	d.streams = map[string]models.RecurringStream{}
+	for _, c := range recurring.Connections {
+		if s, ok := streamsByID[c.StreamID]; ok {
+			d.streams[c.TransactionID] = s
+		}
+	}
+
+	d.account = acc
+	d.txns = txns
+	d.monetary = monetary
+	d.recurring = recurring
+	d.txnIdx, d.txnOffset = 0, 0
+	d.applyFilter()
+	d.status = fmt.Sprintf("Loaded %d transactions for %s", len(txns), acc.DisplayName)
+}
+
+// Recomputes the visible transactions from the current filter. Matches merchant, category, channel, amount and date
+func (d *dashboard) applyFilter() {
+	filter := strings.ToLower(string(d.filter))
+
+	d.visible = d.visible[:0]
+	for i, txn := range d.txns {
+		if filter == "" || strings.Contains(strings.ToLower(txnSearchText(txn)), filter) {
+			d.visible = append(d.visible, i)
+		}
+	}
+
+	if d.txnIdx >= len(d.visible) {
+		d.txnIdx = max(len(d.visible)-1, 0)
+	}
+	if d.txnOffset > d.txnIdx {
+		d.txnOffset = d.txnIdx
+	}
+}
+
+// Text a transaction is matched against when filtering
+func txnSearchText(txn models.Transaction) string {
+	return strings.Join([]string{
+		txn.MerchantName,
+		txn.PersonalFinanceCategory,
+		txn.PaymentChannel,
+		txn.Amount,
+		txn.Date.Format("2006-01-02"),
+	}, " ")
+}
+
+// Returns the selected transaction, if there is one
+func (d *dashboard) selectedTxn() (*models.Transaction, bool) {
+	if len(d.visible) == 0 {
+		return nil, false
+	}
+	return &d.txns[d.visible[d.txnIdx]], true
+}
+
+// Handles a key press, returning true if the dashboard should exit
+func (d *dashboard) handleKey(event *tcell.EventKey) bool {
+	if event.Key() == tcell.KeyCtrlC {
+		return true
+	}
+
+	switch d.mode {
+	case modeFilter:
+		d.handleFilterKey(event)
+		return false
+	case modePrompt:
+		d.handlePromptKey(event)
+		return false
+	}
+
+	switch event.Key() {
+	case tcell.KeyEscape:
+		if len(d.filter) > 0 {
+			d.filter = nil
+			d.applyFilter()
+			return false
+		}
+		return true
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if d.focus == paneSidebar {
+			d.focus = paneTransactions
+		} else {
+			d.focus = paneSidebar
+		}
+	case tcell.KeyUp:
+		d.move(-1)
+	case tcell.KeyDown:
+		d.move(1)
+	case tcell.KeyPgUp:
+		d.move(-d.pageSize())
+	case tcell.KeyPgDn:
+		d.move(d.pageSize())
+	case tcell.KeyHome:
+		d.move(-len(d.txns) - len(d.sidebar))
+	case tcell.KeyEnd:
+		d.move(len(d.txns) + len(d.sidebar))
+	case tcell.KeyEnter:
+		if d.focus == paneSidebar {
+			d.openSidebarRow()
+		}
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'q':
+			return true
+		case 'k':
+			d.move(-1)
+		case 'j':
+			d.move(1)
+		case '/':
+			d.focus = paneTransactions
+			d.mode = modeFilter
+		case 'c':
+			d.startCategorize()
+		case 't':
+			d.startTag()
+		case 's':
+			d.syncSelectedItem()
+		}
+	}
+
+	return false
+}
+
+// Moves the selection in the focused pane by delta rows
+func (d *dashboard) move(delta int) {
+	if d.focus == paneSidebar {
+		if len(d.sidebar) == 0 {
+			return
+		}
+		d.sidebarIdx = min(max(d.sidebarIdx+delta, 0), len(d.sidebar)-1)
+		return
+	}
+
+	if len(d.visible) == 0 {
+		return
+	}
+	d.txnIdx = min(max(d.txnIdx+delta, 0), len(d.visible)-1)
+}
+
+// Loads the account on the selected sidebar row
+func (d *dashboard) openSidebarRow() {
+	if len(d.sidebar) == 0 {
+		return
+	}
+	row := d.sidebar[d.sidebarIdx]
+	if row.account == nil {
+		d.status = fmt.Sprintf("Item %s - select an account to view it, or press 's' to sync the item", row.item.Nickname)
+		return
+	}
+	d.loadAccount(row.account)
+	d.focus = paneTransactions
+}
+
+// Handles a key press while typing into the filter
+func (d *dashboard) handleFilterKey(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		d.filter = nil
+		d.mode = modeNormal
+	case tcell.KeyEnter:
+		d.mode = modeNormal
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(d.filter) > 0 {
+			d.filter = d.filter[:len(d.filter)-1]
+		}
+	case tcell.KeyRune:
+		d.filter = append(d.filter, event.Rune())
+	}
+	d.applyFilter()
+}
+
+// Opens a single-line prompt in the status bar, calling action with the input when the user presses enter
+func (d *dashboard) startPrompt(prompt string, action func(input string)) {
+	d.mode = modePrompt
+	d.prompt = prompt
+	d.promptInput = nil
+	d.promptAction = action
+}
+
+// Handles a key press while typing into a prompt
+func (d *dashboard) handlePromptKey(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		d.mode = modeNormal
+		d.status = "Cancelled"
+	case tcell.KeyEnter:
+		d.mode = modeNormal
+		input := strings.TrimSpace(string(d.promptInput))
+		if input == "" {
+			d.status = "Cancelled"
+			return
+		}
+		d.promptAction(input)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(d.promptInput) > 0 {
+			d.promptInput = d.promptInput[:len(d.promptInput)-1]
+		}
+	case tcell.KeyRune:
+		d.promptInput = append(d.promptInput, event.Rune())
+	}
+}
+
+// Prompts for a new category for the selected transaction
+func (d *dashboard) startCategorize() {
+	txn, ok := d.selectedTxn()
+	if !ok || d.focus != paneTransactions {
+		d.status = "Select a transaction to re-categorize"
+		return
+	}
+
+	d.startPrompt(fmt.Sprintf("New category for %s > ", txn.MerchantName), func(category string) {
+		updated, err := d.src.SetCategory(d.account.Id, txn.Id, category)
+		if err != nil {
+			d.status = fmt.Sprintf("Error updating category: %s", err)
+			return
+		}
+		txn.PersonalFinanceCategory = updated.PersonalFinanceCategory
+		d.applyFilter()
+		d.status = fmt.Sprintf("Category set to %s", updated.PersonalFinanceCategory)
+	})
+}
+
+// Prompts for a tag to attach to the selected transaction
+func (d *dashboard) startTag() {
+	txn, ok := d.selectedTxn()
+	if !ok || d.focus != paneTransactions {
+		d.status = "Select a transaction to tag"
+		return
+	}
+
+	d.startPrompt(fmt.Sprintf("Tag for %s > ", txn.MerchantName), func(tag string) {
+		tags, err := d.src.AddTag(d.account.Id, txn.Id, tag)
+		if err != nil {
+			d.status = fmt.Sprintf("Error tagging transaction: %s", err)
+			return
+		}
+		d.tags[txn.Id] = tags.Tags
+		d.status = fmt.Sprintf("Tags: %s", strings.Join(tags.Tags, ", "))
+	})
+}
+
+// Syncs the item of the selected sidebar row, or of the open account, then reloads the dashboard
+func (d *dashboard) syncSelectedItem() {
+	var item models.ItemName
+	switch {
+	case d.focus == paneSidebar && len(d.sidebar) > 0:
+		item = d.sidebar[d.sidebarIdx].item
+	case d.account != nil:
+		for _, row := range d.sidebar {
+			if row.account != nil && row.account.Id == d.account.Id {
+				item = row.item
+			}
+		}
+	}
+	if item.ItemId == "" {
+		d.status = "Select an item to sync"
+		return
+	}
+
+	if err := d.screen.Suspend(); err != nil {
+		d.status = fmt.Sprintf("Error suspending screen: %s", err)
+		return
+	}
+	syncErr := d.src.Sync(item)
+	if err := d.screen.Resume(); err != nil {
+		d.status = fmt.Sprintf("Error resuming screen: %s", err)
+		return
+	}
+
+	if syncErr != nil {
+		d.status = fmt.Sprintf("Error syncing %s: %s", item.Nickname, syncErr)
+		return
+	}
+
+	openID := ""
+	if d.account != nil {
+		openID = d.account.Id
+	}
+	if err := d.loadSidebar(); err != nil {
+		d.status = err.Error()
+		return
+	}
+	for _, row := range d.sidebar {
+		if row.account != nil && row.account.Id == openID {
+			d.loadAccount(row.account)
+		}
+	}
+	d.status = fmt.Sprintf("Synced %s", item.Nickname)
+}
@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Screen styles, following the colors of the CLI's tables
+var (
+	styleHeader   = tcell.StyleDefault.Foreground(tcell.ColorGreen).Underline(true)
+	styleColumn   = tcell.StyleDefault.Foreground(tcell.ColorYellow)
+	styleBorder   = tcell.StyleDefault.Foreground(tcell.ColorGray)
+	styleFocused  = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	styleSelected = tcell.StyleDefault.Reverse(true)
+	styleIncome   = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	styleExpense  = tcell.StyleDefault.Foreground(tcell.ColorRed)
+	styleMuted    = tcell.StyleDefault.Foreground(tcell.ColorGray)
+)
+
+const (
+	sidebarWidth = 32
+	bottomHeight = 12
+)
+
+// Screen area of a single pane
+type rect struct {
+	x, y, w, h int
+}
+
+// Splits the screen into the dashboard's panes
+func (d *dashboard) layout() (sidebar, txns, chart, recurring rect) {
+	w, h := d.screen.Size()
+	h-- // Status bar
+
+	sw := min(sidebarWidth, w/3)
+	bh := min(bottomHeight, h/3)
+
+	sidebar = rect{0, 0, sw, h}
+	txns = rect{sw, 0, w - sw, h - bh}
+	chart = rect{sw, h - bh, (w - sw) / 2, bh}
+	recurring = rect{sw + chart.w, h - bh, w - sw - chart.w, bh}
+	return
+}
+
+// Number of transaction rows visible at once
+func (d *dashboard) pageSize() int {
+	_, txns, _, _ := d.layout()
+	return max(txns.h-4, 1)
+}
+
+// Draws the full dashboard
+func (d *dashboard) draw() {
+	d.screen.Clear()
+
+	sidebar, txns, chart, recurring := d.layout()
+	d.drawSidebar(sidebar)
+	d.drawTransactions(txns)
+	d.drawChart(chart)
+	d.drawRecurring(recurring)
+	d.drawStatus()
+
+	d.screen.Show()
+}
+
+// Draws the items and accounts sidebar
+func (d *dashboard) drawSidebar(r rect) {
+	d.drawBox(r, "Items", d.focus == paneSidebar)
+
+	for i, row := range d.sidebar {
+		y := r.y + 1 + i
+		if y >= r.y+r.h-1 {
+			break
+		}
+
+		style := styleHeader
+		text := row.item.Nickname
+		if row.account != nil {
+			style = styleColumn
+			text = "  " + row.account.DisplayName
+			if d.account != nil && d.account.Id == row.account.Id {
+				text = "> " + row.account.DisplayName
+			}
+		}
+		if i == d.sidebarIdx && d.focus == paneSidebar {
+			style = styleSelected
+		}
+		d.drawText(r.x+1, y, r.w-2, style, fmt.Sprintf("%-*s", r.w-2, text))
+	}
+}
+
+// Draws the transaction list, with the filter line above it
+func (d *dashboard) drawTransactions(r rect) {
+	title := "Transactions"
+	if d.account != nil {
+		title = fmt.Sprintf("Transactions - %s (%d/%d)", d.account.DisplayName, len(d.visible), len(d.txns))
+	}
+	d.drawBox(r, title, d.focus == paneTransactions)
+
+	filterStyle := styleMuted
+	if d.mode == modeFilter {
+		filterStyle = styleFocused
+	}
+	filterText := "Filter: " + string(d.filter)
+	if len(d.filter) == 0 && d.mode != modeFilter {
+		filterText = "Press '/' to filter"
+	}
+	d.drawText(r.x+1, r.y+1, r.w-2, filterStyle, filterText)
+
+	columns := []struct {
+		header string
+		width  int
+	}{
+		{"Date", 12}, {"Amount", 11}, {"Merchant Name", 24}, {"Category", 22}, {"Channel", 10}, {"Recurring / Tags", 18},
+	}
+
+	x := r.x + 1
+	for _, c := range columns {
+		d.drawText(x, r.y+2, min(c.width, r.x+r.w-1-x), styleHeader, c.header)
+		x += c.width + 1
+	}
+
+	rows := d.pageSize()
+	if d.txnIdx < d.txnOffset {
+		d.txnOffset = d.txnIdx
+	}
+	if d.txnIdx >= d.txnOffset+rows {
+		d.txnOffset = d.txnIdx - rows + 1
+	}
+
+	for i := 0; i < rows && d.txnOffset+i < len(d.visible); i++ {
+		idx := d.txnOffset + i
+		txn := d.txns[d.visible[idx]]
+
+		recurring := ""
+		if stream, ok := d.streams[txn.Id]; ok {
+			recurring = "recurring " + stream.StreamType
+		}
+		if tags, ok := d.tags[txn.Id]; ok {
+			recurring = "#" + strings.Join(tags, " #")
+		}
+
+		values := []string{txn.Date.Format("2006-01-02"), txn.Amount, txn.MerchantName, txn.PersonalFinanceCategory, txn.PaymentChannel, recurring}
+
+		style := styleColumn
+		if idx == d.txnIdx && d.focus == paneTransactions {
+			style = styleSelected
+		}
+
+		line := ""
+		for j, c := range columns {
+			line += fmt.Sprintf("%-*s ", c.width, truncate(values[j], c.width))
+		}
+		d.drawText(r.x+1, r.y+3+i, r.w-2, style, line)
+	}
+
+	if d.account != nil && len(d.visible) == 0 {
+		d.drawText(r.x+1, r.y+3, r.w-2, styleMuted, "No transactions match the filter")
+	}
+}
+
+// Draws a bar chart of monthly income and expenses, most recent month on the right
+func (d *dashboard) drawChart(r rect) {
+	d.drawBox(r, "Income / Expenses", false)
+
+	barHeight := r.h - 3
+	months := min(len(d.monetary), (r.w-2)/3)
+	if months == 0 || barHeight < 1 {
+		d.drawText(r.x+1, r.y+1, r.w-2, styleMuted, "No data")
+		return
+	}
+
+	// Monetary data is ordered most recent first
+	data := d.monetary[:months]
+
+	maxVal := 0.0
+	income := make([]float64, months)
+	expenses := make([]float64, months)
+	for i, m := range data {
+		income[i], _ = strconv.ParseFloat(strings.TrimPrefix(m.Income, "-"), 64)
+		expenses[i], _ = strconv.ParseFloat(strings.TrimPrefix(m.Expenses, "-"), 64)
+		maxVal = max(maxVal, income[i], expenses[i])
+	}
+	if maxVal == 0 {
+		maxVal = 1
+	}
+
+	baseY := r.y + 1 + barHeight
+	for i := range data {
+		x := r.x + 1 + (months-1-i)*3
+
+		incH := int(income[i] / maxVal * float64(barHeight))
+		expH := int(expenses[i] / maxVal * float64(barHeight))
+		for h := 0; h < incH; h++ {
+			d.screen.SetContent(x, baseY-1-h, '█', nil, styleIncome)
+		}
+		for h := 0; h < expH; h++ {
+			d.screen.SetContent(x+1, baseY-1-h, '█', nil, styleExpense)
+		}
+
+		month := data[i].Date
+		if parts := strings.Split(month, "-"); len(parts) == 2 {
+			month = parts[1]
+			if len(month) == 1 {
+				month = "0" + month
+			}
+		}
+		d.drawText(x, baseY, 2, styleMuted, month)
+	}
+}
+
+// Draws the account's recurring transaction streams
+func (d *dashboard) drawRecurring(r rect) {
+	summary := d.recurring.Summary
+	d.drawBox(r, fmt.Sprintf("Recurring (%d active)", summary.ActiveStreams), false)
+
+	if len(d.recurring.Streams) == 0 {
+		d.drawText(r.x+1, r.y+1, r.w-2, styleMuted, "No recurring streams")
+		return
+	}
+
+	for i, s := range d.recurring.Streams {
+		y := r.y + 1 + i
+		if y >= r.y+r.h-1 {
+			break
+		}
+
+		name := s.MerchantName
+		if name == "" {
+			name = s.Description
+		}
+
+		style := styleColumn
+		if !s.IsActive {
+			style = styleMuted
+		}
+
+		line := fmt.Sprintf("%-20s %-14s %-8s next %s", truncate(name, 20), truncate(strings.ToLower(s.Frequency), 14), strings.ToLower(s.StreamType), s.PredictedNextDate)
+		d.drawText(r.x+1, y, r.w-2, style, line)
+	}
+}
+
+// Draws the status bar, or the active prompt
+func (d *dashboard) drawStatus() {
+	w, h := d.screen.Size()
+	y := h - 1
+
+	if d.mode == modePrompt {
+		d.drawText(0, y, w, styleFocused, d.prompt+string(d.promptInput)+"_")
+		return
+	}
+
+	help := "tab: switch pane  ↑/↓: move  enter: open  /: filter  c: categorize  t: tag  s: sync  q: quit"
+	text := help
+	if d.status != "" {
+		text = d.status + "  |  " + help
+	}
+	d.drawText(0, y, w, styleMuted, text)
+}
+
+// Draws a bordered box with a title
+func (d *dashboard) drawBox(r rect, title string, focused bool) {
+	if r.w < 2 || r.h < 2 {
+		return
+	}
+
+	style := styleBorder
+	if focused {
+		style = styleFocused
+	}
+
+	for x := r.x + 1; x < r.x+r.w-1; x++ {
+		d.screen.SetContent(x, r.y, tcell.RuneHLine, nil, style)
+		d.screen.SetContent(x, r.y+r.h-1, tcell.RuneHLine, nil, style)
+	}
+	for y := r.y + 1; y < r.y+r.h-1; y++ {
+		d.screen.SetContent(r.x, y, tcell.RuneVLine, nil, style)
+		d.screen.SetContent(r.x+r.w-1, y, tcell.RuneVLine, nil, style)
+	}
+	d.screen.SetContent(r.x, r.y, tcell.RuneULCorner, nil, style)
+	d.screen.SetContent(r.x+r.w-1, r.y, tcell.RuneURCorner, nil, style)
+	d.screen.SetContent(r.x, r.y+r.h-1, tcell.RuneLLCorner, nil, style)
+	d.screen.SetContent(r.x+r.w-1, r.y+r.h-1, tcell.RuneLRCorner, nil, style)
+
+	d.drawText(r.x+2, r.y, r.w-4, style, " "+title+" ")
+}
+
+// Draws text on a single line, cut off at maxWidth cells
+func (d *dashboard) drawText(x, y, maxWidth int, style tcell.Style, text string) {
+	for i, r := range []rune(text) {
+		if i >= maxWidth {
+			return
+		}
+		d.screen.SetContent(x+i, y, r, nil, style)
+	}
+}
+
+// Cuts a string down to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -6,8 +6,8 @@ import (
 	"strconv"
 )
 
-// Builds query string for URL
-func BuildQueries(merchant, category, channel, date, start, end string, min, max, limit int, summary bool) string {
+// Builds URL query parameters for transaction requests
+func BuildQueries(merchant, category, channel, date, start, end string, min, max, limit int, summary bool) url.Values {
 	queries := map[string]string{
 		"merchant": merchant,
 		"category": category,
@@ -36,5 +36,5 @@ func BuildQueries(merchant, category, channel, date, start, end string, min, max
 		}
 	}
 
-	return "?" + q.Encode()
+	return q
 }
@@ -13,24 +13,26 @@ import (
 //go:embed schema/*.sql
 var embedMigrations embed.FS
 
-func OpenLocalDatabase(dbPath string) (*database.Queries, error) {
+// Opens the local database, applying any pending migrations. The connection is returned alongside its
+// queries, for commands that group writes into a database transaction
+func OpenLocalDatabase(dbPath string) (*sql.DB, *database.Queries, error) {
 	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	goose.SetBaseFS(embedMigrations)
 	goose.SetLogger(log.New(io.Discard, "", 0))
 
 	if err = goose.SetDialect("sqlite"); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err = goose.Up(db, "schema"); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	queries := database.New(db)
 
-	return queries, nil
+	return db, queries, nil
 }
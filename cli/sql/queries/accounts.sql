@@ -36,12 +36,21 @@ RETURNING *;
 
 -- name: GetAccount :one
 SELECT * FROM accounts
-WHERE name = ?
-AND user_id = ?;
+WHERE (name = sqlc.arg(name) OR nickname = sqlc.arg(name))
+AND user_id = sqlc.arg(user_id)
+ORDER BY nickname = sqlc.arg(name) DESC
+LIMIT 1;
 
 -- name: GetAllAccounts :many
 SELECT * FROM accounts
-WHERE user_id = ?;
+WHERE user_id = ?
+ORDER BY sort_order, COALESCE(nickname, name) COLLATE NOCASE;
+
+-- name: SetAccountOverrides :exec
+UPDATE accounts
+SET nickname = ?, hidden = ?, archived = ?, include_in_net_worth = ?, sort_order = ?
+WHERE id = ?
+AND user_id = ?;
 
 -- name: DeleteAccounts :exec
 DELETE FROM accounts
@@ -1,9 +1,24 @@
 -- name: CreateErrorLog :exec
-INSERT INTO error_logs (timestamp, command, error_message)
-VALUES (?, ?, ?)
+INSERT INTO error_logs (timestamp, command, error_message, command_line, user_message, request_id, http_status, plaid_request_id, cli_version)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
--- name: GetLogs :many
+-- name: ListErrorLogs :many
 SELECT * FROM error_logs
-ORDER BY timestamp DESC
-LIMIT 5;
\ No newline at end of file
+WHERE timestamp >= sqlc.arg(since)
+AND (sqlc.arg(command) = '' OR command = sqlc.arg(command) OR command LIKE sqlc.arg(command) || ' %')
+AND (sqlc.arg(pattern) = '' OR error_message LIKE '%' || sqlc.arg(pattern) || '%' OR user_message LIKE '%' || sqlc.arg(pattern) || '%' OR command_line LIKE '%' || sqlc.arg(pattern) || '%')
+ORDER BY timestamp DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: DeleteErrorLogsBefore :exec
+DELETE FROM error_logs
+WHERE timestamp < ?;
+
+-- name: TrimErrorLogs :exec
+DELETE FROM error_logs
+WHERE id NOT IN (
+    SELECT id FROM error_logs
+    ORDER BY id DESC
+    LIMIT ?
+);
@@ -0,0 +1,28 @@
+-- name: DeleteItemsForUser :exec
+DELETE FROM items
+WHERE user_id = ?;
+
+-- name: GetItems :many
+SELECT id, user_id, nickname, institution_name, updated_at FROM items
+WHERE user_id = ?
+ORDER BY nickname COLLATE NOCASE;
+
+-- name: UpsertItem :exec
+INSERT INTO items(
+    id,
+    user_id,
+    nickname,
+    institution_name,
+    updated_at
+    )
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT(id) DO UPDATE SET
+    nickname = excluded.nickname,
+    institution_name = excluded.institution_name,
+    updated_at = excluded.updated_at;
@@ -0,0 +1,22 @@
+-- name: DeleteSyncCursor :exec
+DELETE FROM sync_cursors
+WHERE user_id = ?;
+
+-- name: GetSyncCursor :one
+SELECT cursor FROM sync_cursors
+WHERE user_id = ?;
+
+-- name: UpsertSyncCursor :exec
+INSERT INTO sync_cursors(
+    user_id,
+    cursor,
+    updated_at
+    )
+VALUES (
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT(user_id) DO UPDATE SET
+    cursor = excluded.cursor,
+    updated_at = excluded.updated_at;
@@ -30,4 +30,38 @@ DELETE FROM transactions
 WHERE account_id IN (
     SELECT id FROM accounts
     WHERE user_id = ?
-);
\ No newline at end of file
+);
+
+-- name: UpsertTransaction :exec
+INSERT INTO transactions(
+    id, 
+    account_id, 
+    amount, 
+    iso_currency_code, 
+    date, 
+    merchant_name, 
+    payment_channel, 
+    personal_finance_category
+    )
+VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+)
+ON CONFLICT(id) DO UPDATE SET
+    account_id = excluded.account_id,
+    amount = excluded.amount,
+    iso_currency_code = excluded.iso_currency_code,
+    date = excluded.date,
+    merchant_name = excluded.merchant_name,
+    payment_channel = excluded.payment_channel,
+    personal_finance_category = excluded.personal_finance_category;
+
+-- name: DeleteTransaction :exec
+DELETE FROM transactions
+WHERE id = ?;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE error_logs ADD COLUMN command_line TEXT NOT NULL DEFAULT '';
+ALTER TABLE error_logs ADD COLUMN user_message TEXT NOT NULL DEFAULT '';
+ALTER TABLE error_logs ADD COLUMN request_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE error_logs ADD COLUMN http_status INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE error_logs ADD COLUMN plaid_request_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE error_logs ADD COLUMN cli_version TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_error_logs_timestamp ON error_logs(timestamp);
+
+-- +goose Down
+DROP INDEX idx_error_logs_timestamp;
+ALTER TABLE error_logs DROP COLUMN cli_version;
+ALTER TABLE error_logs DROP COLUMN plaid_request_id;
+ALTER TABLE error_logs DROP COLUMN http_status;
+ALTER TABLE error_logs DROP COLUMN request_id;
+ALTER TABLE error_logs DROP COLUMN user_message;
+ALTER TABLE error_logs DROP COLUMN command_line;
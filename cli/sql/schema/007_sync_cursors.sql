@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE sync_cursors (
+    user_id TEXT PRIMARY KEY,
+    cursor TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE sync_cursors;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE accounts ADD nickname TEXT;
+ALTER TABLE accounts ADD hidden BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE accounts ADD archived BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE accounts ADD include_in_net_worth BOOLEAN NOT NULL DEFAULT 1;
+ALTER TABLE accounts ADD sort_order INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE accounts DROP COLUMN sort_order;
+ALTER TABLE accounts DROP COLUMN include_in_net_worth;
+ALTER TABLE accounts DROP COLUMN archived;
+ALTER TABLE accounts DROP COLUMN hidden;
+ALTER TABLE accounts DROP COLUMN nickname;
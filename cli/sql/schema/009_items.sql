@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE items (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id)
+    ON DELETE CASCADE,
+    nickname TEXT NOT NULL,
+    institution_name TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE items;
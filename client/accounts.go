@@ -0,0 +1,279 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jms-guy/greed/models"
+)
+
+// Returns the user's accounts, leaving out hidden and archived ones
+func (c *Client) GetAccounts(ctx context.Context) ([]models.Account, error) {
+	var accounts []models.Account
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/accounts", auth: true}, &accounts)
+	return accounts, err
+}
+
+// Returns all accounts for the user, including hidden and archived ones
+func (c *Client) GetAllAccounts(ctx context.Context) ([]models.Account, error) {
+	var accounts []models.Account
+	query := url.Values{"include_hidden": {"true"}}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/accounts", query: query, auth: true}, &accounts)
+	return accounts, err
+}
+
+// Returns a single account record
+func (c *Client) GetAccount(ctx context.Context, accountID string) (models.Account, error) {
+	var account models.Account
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/data", auth: true}, &account)
+	return account, err
+}
+
+// Compares spending on an account between two periods. The query holds either "period" and "against",
+// or "start", "end", "against_start" and "against_end"
+func (c *Client) CompareAccount(ctx context.Context, accountID string, query url.Values) (models.ComparisonReport, error) {
+	var report models.ComparisonReport
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/compare", query: query, auth: true}, &report)
+	return report, err
+}
+
+// Compares spending across all of the user's credit and debit accounts between two periods
+func (c *Client) CompareAccounts(ctx context.Context, query url.Values) (models.ComparisonReport, error) {
+	var report models.ComparisonReport
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/accounts/compare", query: query, auth: true}, &report)
+	return report, err
+}
+
+// Creates a manual account, tracked by hand with no Plaid item
+func (c *Client) CreateManualAccount(ctx context.Context, req models.CreateManualAccount) (models.Account, error) {
+	var account models.Account
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/accounts", body: req, auth: true}, &account)
+	return account, err
+}
+
+// Updates how an account is shown, returning the account with its overrides applied
+func (c *Client) UpdateAccountOverrides(ctx context.Context, accountID string, req models.UpdateAccountOverrides) (models.Account, error) {
+	var account models.Account
+	err := c.do(ctx, request{method: http.MethodPut, path: accountPath(accountID) + "/overrides", body: req, auth: true}, &account)
+	return account, err
+}
+
+// Deletes an account record
+func (c *Client) DeleteAccount(ctx context.Context, accountID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: accountPath(accountID), auth: true}, nil)
+}
+
+// Returns transaction records for an account, filtered by the given query parameters
+func (c *Client) GetTransactions(ctx context.Context, accountID string, query url.Values) ([]models.Transaction, error) {
+	var txns []models.Transaction
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/transactions", query: query, auth: true}, &txns)
+	return txns, err
+}
+
+// Returns merchant summaries for an account's transactions. The "summary" query parameter is set automatically
+func (c *Client) GetMerchantSummaries(ctx context.Context, accountID string, query url.Values) ([]models.MerchantSummary, error) {
+	q := url.Values{}
+	for key, vals := range query {
+		q[key] = vals
+	}
+	q.Set("summary", "true")
+
+	var summaries []models.MerchantSummary
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/transactions", query: q, auth: true}, &summaries)
+	return summaries, err
+}
+
+// Deletes all transaction records for an account
+func (c *Client) DeleteTransactions(ctx context.Context, accountID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: accountPath(accountID) + "/transactions", auth: true}, nil)
+}
+
+// Adds a transaction to a manual account, returning the created record
+func (c *Client) CreateManualTransaction(ctx context.Context, accountID string, req models.CreateManualTransaction) (models.Transaction, error) {
+	var txn models.Transaction
+	err := c.do(ctx, request{method: http.MethodPost, path: accountPath(accountID) + "/transactions", body: req, auth: true}, &txn)
+	return txn, err
+}
+
+// Changes a manual account's transaction, returning the updated record
+func (c *Client) UpdateManualTransaction(ctx context.Context, accountID, transactionID string, req models.UpdateManualTransaction) (models.Transaction, error) {
+	var txn models.Transaction
+	err := c.do(ctx, request{method: http.MethodPut, path: transactionPath(accountID, transactionID), body: req, auth: true}, &txn)
+	return txn, err
+}
+
+// Deletes a manual account's transaction
+func (c *Client) DeleteManualTransaction(ctx context.Context, accountID, transactionID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: transactionPath(accountID, transactionID), auth: true}, nil)
+}
+
+// Returns monthly income and expense data for the history of an account
+func (c *Client) GetMonetaryData(ctx context.Context, accountID string) ([]models.MonetaryData, error) {
+	var data []models.MonetaryData
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/transactions/monetary", auth: true}, &data)
+	return data, err
+}
+
+// Returns income and expense data for an account for a single month
+func (c *Client) GetMonetaryDataForMonth(ctx context.Context, accountID string, year, month int) (models.MonetaryData, error) {
+	var data models.MonetaryData
+	path := fmt.Sprintf("%s/transactions/monetary/%d-%d", accountPath(accountID), year, month)
+	err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &data)
+	return data, err
+}
+
+// Returns an account's recurring transaction streams
+func (c *Client) GetRecurringData(ctx context.Context, accountID string) (models.RecurringData, error) {
+	var data models.RecurringData
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/transactions/recurring", auth: true}, &data)
+	return data, err
+}
+
+// Returns an investment account's holdings, with their cost basis and unrealized gain
+func (c *Client) GetHoldings(ctx context.Context, accountID string) (models.Holdings, error) {
+	var holdings models.Holdings
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/holdings", auth: true}, &holdings)
+	return holdings, err
+}
+
+// Returns an investment account's investment transactions, most recent first
+func (c *Client) GetInvestmentTransactions(ctx context.Context, accountID string) ([]models.InvestmentTransaction, error) {
+	var txns []models.InvestmentTransaction
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/investments/transactions", auth: true}, &txns)
+	return txns, err
+}
+
+// Returns a credit card or loan account's APRs, payments and loan terms. Accounts without liability
+// data return a not_found API error
+func (c *Client) GetLiability(ctx context.Context, accountID string) (models.Liability, error) {
+	var liability models.Liability
+	err := c.do(ctx, request{method: http.MethodGet, path: accountPath(accountID) + "/liabilities", auth: true}, &liability)
+	return liability, err
+}
+
+// Re-categorizes a single transaction, returning the updated record
+func (c *Client) UpdateTransactionCategory(ctx context.Context, accountID, transactionID, category string) (models.Transaction, error) {
+	var txn models.Transaction
+	body := models.UpdateTransactionCategory{Category: category}
+	err := c.do(ctx, request{method: http.MethodPut, path: transactionPath(accountID, transactionID) + "/category", body: body, auth: true}, &txn)
+	return txn, err
+}
+
+// Returns the tags attached to a single transaction
+func (c *Client) GetTransactionTags(ctx context.Context, accountID, transactionID string) (models.TransactionTags, error) {
+	var tags models.TransactionTags
+	err := c.do(ctx, request{method: http.MethodGet, path: transactionPath(accountID, transactionID) + "/tags", auth: true}, &tags)
+	return tags, err
+}
+
+// Attaches a tag to a single transaction, returning all of the transaction's tags
+func (c *Client) AddTransactionTag(ctx context.Context, accountID, transactionID, tag string) (models.TransactionTags, error) {
+	var tags models.TransactionTags
+	body := models.AddTransactionTag{Tag: tag}
+	err := c.do(ctx, request{method: http.MethodPost, path: transactionPath(accountID, transactionID) + "/tags", body: body, auth: true}, &tags)
+	return tags, err
+}
+
+// Returns a transaction's note, which is empty if none has been written
+func (c *Client) GetTransactionNote(ctx context.Context, accountID, transactionID string) (models.TransactionNote, error) {
+	var note models.TransactionNote
+	err := c.do(ctx, request{method: http.MethodGet, path: transactionPath(accountID, transactionID) + "/note", auth: true}, &note)
+	return note, err
+}
+
+// Sets a transaction's note, an empty note removing it
+func (c *Client) UpdateTransactionNote(ctx context.Context, accountID, transactionID, note string) (models.TransactionNote, error) {
+	var updated models.TransactionNote
+	body := models.UpdateTransactionNote{Note: note}
+	err := c.do(ctx, request{method: http.MethodPut, path: transactionPath(accountID, transactionID) + "/note", body: body, auth: true}, &updated)
+	return updated, err
+}
+
+// Returns the files attached to a transaction
+func (c *Client) GetTransactionAttachments(ctx context.Context, accountID, transactionID string) ([]models.TransactionAttachment, error) {
+	var attachments []models.TransactionAttachment
+	err := c.do(ctx, request{method: http.MethodGet, path: transactionPath(accountID, transactionID) + "/attachments", auth: true}, &attachments)
+	return attachments, err
+}
+
+// Attaches the contents of file to a transaction, stored under the given file name
+func (c *Client) AddTransactionAttachment(ctx context.Context, accountID, transactionID, fileName string, file io.Reader) (models.TransactionAttachment, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return models.TransactionAttachment{}, fmt.Errorf("error creating upload: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return models.TransactionAttachment{}, fmt.Errorf("error reading file: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return models.TransactionAttachment{}, fmt.Errorf("error creating upload: %w", err)
+	}
+
+	var attachment models.TransactionAttachment
+	req := request{
+		method:      http.MethodPost,
+		path:        transactionPath(accountID, transactionID) + "/attachments",
+		raw:         body.Bytes(),
+		contentType: writer.FormDataContentType(),
+		auth:        true,
+	}
+	err = c.do(ctx, req, &attachment)
+	return attachment, err
+}
+
+// Writes the contents of a transaction's attachment to w, returning the number of bytes written
+func (c *Client) DownloadTransactionAttachment(ctx context.Context, accountID, transactionID, attachmentID string, w io.Writer) (int64, error) {
+	res, err := c.open(ctx, request{method: http.MethodGet, path: attachmentPath(accountID, transactionID, attachmentID), auth: true})
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	n, err := io.Copy(w, res.Body)
+	if err != nil {
+		return n, fmt.Errorf("error downloading attachment: %w", err)
+	}
+
+	return n, nil
+}
+
+// Deletes a transaction's attachment
+func (c *Client) DeleteTransactionAttachment(ctx context.Context, accountID, transactionID, attachmentID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: attachmentPath(accountID, transactionID, attachmentID), auth: true}, nil)
+}
+
+// Returns a page of changes to the user's transactions since cursor, an empty cursor returning every change.
+// A count of 0 uses the server's default page size
+func (c *Client) GetTransactionChanges(ctx context.Context, cursor string, count int) (models.TransactionChanges, error) {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if count > 0 {
+		query.Set("count", strconv.Itoa(count))
+	}
+
+	var changes models.TransactionChanges
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/transactions/changes", query: query, auth: true}, &changes)
+	return changes, err
+}
+
+func accountPath(accountID string) string {
+	return "/api/accounts/" + url.PathEscape(accountID)
+}
+
+func transactionPath(accountID, transactionID string) string {
+	return accountPath(accountID) + "/transactions/" + url.PathEscape(transactionID)
+}
+
+func attachmentPath(accountID, transactionID, attachmentID string) string {
+	return transactionPath(accountID, transactionID) + "/attachments/" + url.PathEscape(attachmentID)
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/models"
+)
+
+// Returns unusual transactions flagged for the user. Acknowledged anomalies are included when all is true
+func (c *Client) GetAnomalies(ctx context.Context, all bool) ([]models.Anomaly, error) {
+	query := url.Values{}
+	if all {
+		query.Set("all", "true")
+	}
+
+	var anomalies []models.Anomaly
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/anomalies", query: query, auth: true}, &anomalies)
+	return anomalies, err
+}
+
+// Acknowledges a single anomaly
+func (c *Client) AcknowledgeAnomaly(ctx context.Context, anomalyID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodPut, path: "/api/anomalies/" + anomalyID.String() + "/acknowledge", auth: true}, nil)
+}
+
+// Acknowledges all of the user's anomalies
+func (c *Client) AcknowledgeAllAnomalies(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodPut, path: "/api/anomalies/acknowledge", auth: true}, nil)
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/models"
+)
+
+// Returns the user's API keys
+func (c *Client) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/keys", auth: true}, &keys)
+	return keys, err
+}
+
+// Creates an API key. The key is only returned here, and is not shown again
+func (c *Client) CreateAPIKey(ctx context.Context, key models.CreateAPIKey) (models.APIKey, error) {
+	var created models.APIKey
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/keys", body: key, auth: true}, &created)
+	return created, err
+}
+
+// Revokes an API key
+func (c *Client) RevokeAPIKey(ctx context.Context, keyID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/keys/" + keyID.String(), auth: true}, nil)
+}
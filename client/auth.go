@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jms-guy/greed/models"
+)
+
+// Pings the server health endpoint
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodGet, path: "/api/health"}, nil)
+}
+
+// Creates a new user record
+func (c *Client) Register(ctx context.Context, details models.UserDetails) (models.User, error) {
+	var user models.User
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/auth/register", body: details}, &user)
+	return user, err
+}
+
+// Creates a session for a user, returning their credentials
+func (c *Client) Login(ctx context.Context, details models.UserDetails) (models.Credentials, error) {
+	var creds models.Credentials
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/auth/login", body: details}, &creds)
+	return creds, err
+}
+
+// Revokes a user's session tokens
+func (c *Client) Logout(ctx context.Context, req models.RefreshRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/auth/logout", body: req}, nil)
+}
+
+// Generates a new JWT and refresh token
+func (c *Client) Refresh(ctx context.Context, req models.RefreshRequest) (models.RefreshResponse, error) {
+	var tokens models.RefreshResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/auth/refresh", body: req}, &tokens)
+	return tokens, err
+}
+
+// Resets a user's forgotten password
+func (c *Client) ResetPassword(ctx context.Context, req models.ResetPassword) (models.UpdatedPassword, error) {
+	var updated models.UpdatedPassword
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/auth/reset-password", body: req}, &updated)
+	return updated, err
+}
+
+// Sends a verification code to a user's email
+func (c *Client) SendEmailCode(ctx context.Context, req models.EmailVerification) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/auth/email/send", body: req}, nil)
+}
+
+// Verifies a user's email with a code sent to them
+func (c *Client) VerifyEmail(ctx context.Context, req models.EmailVerificationWithCode) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/auth/email/verify", body: req}, nil)
+}
+
+// Creates a Plaid sandbox item for the user. Only available on servers running in the dev environment
+func (c *Client) CreateSandboxItem(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/admin/sandbox", auth: true}, nil)
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jms-guy/greed/models"
+)
+
+/*
+	This package is a typed client for the Greed server API, as described in docs/openapi.yaml.
+	Each exported method maps to a single operation in the spec, and decodes its response into the
+	shared models package, so changes to the API surface break callers at compile time.
+*/
+
+// Http client for the Greed server
+type Client struct {
+	HttpClient     *http.Client
+	BaseURL        string
+	Token          func() string // Returns the JWT sent with authenticated requests
+	OnUnauthorized func() error  // Called when an authenticated request is rejected with a 401, before it is retried once
+}
+
+// Error returned for any response from the server with a 400+ status code
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string // Error message returned by the server, or the raw response body if it could not be decoded
+	Code       string // Stable error code from the server's problem details, one of the models.ErrCode constants
+	RequestID  string // Server request ID, used to find the request in server logs
+
+	PlaidRequestID string // Request ID of the failed Plaid API call, for Plaid errors
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if e.RequestID != "" {
+		msg = fmt.Sprintf("%s (request id: %s)", msg, e.RequestID)
+	}
+	if e.StatusCode >= 500 {
+		return fmt.Sprintf("server error (%s): %s", e.Status, msg)
+	}
+	return fmt.Sprintf("bad request (%s): %s", e.Status, msg)
+}
+
+// Initializes a new Client struct
+func New(baseURL string) *Client {
+	return &Client{
+		HttpClient: &http.Client{
+			Timeout: 3 * time.Minute,
+		},
+		BaseURL: baseURL,
+	}
+}
+
+// Returns a copy of the client that authenticates with the given JWT or API key. Used before a user's
+// credentials have been stored, and by scripts using an API key, so no refresh is attempted on a 401
+func (c *Client) WithToken(token string) *Client {
+	return &Client{
+		HttpClient: c.HttpClient,
+		BaseURL:    c.BaseURL,
+		Token:      func() string { return token },
+	}
+}
+
+// Describes a single API operation
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        any
+	raw         []byte // Body sent as is instead of encoding body as JSON, such as a file upload
+	contentType string // Content type of raw, requests with a JSON body are always sent as application/json
+	auth        bool   // Whether the request is sent with the client's JWT
+}
+
+// Sends a request to the server, decoding a successful response into out if it is non-nil
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	res, err := c.open(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding err: %w", err)
+	}
+
+	return nil
+}
+
+// Sends a request to the server, returning a successful response for the caller to read and close.
+// Authenticated requests rejected with a 401 are retried once after OnUnauthorized
+func (c *Client) open(ctx context.Context, req request) (*http.Response, error) {
+	payload := req.raw
+	if req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling JSON: %w", err)
+		}
+		payload = data
+	}
+
+	res, err := c.send(ctx, req, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusUnauthorized && req.auth && c.OnUnauthorized != nil {
+		_ = res.Body.Close()
+		if err = c.OnUnauthorized(); err != nil {
+			return nil, err
+		}
+		res, err = c.send(ctx, req, payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if res.StatusCode >= 400 {
+		defer res.Body.Close()
+		return nil, newAPIError(res)
+	}
+
+	return res, nil
+}
+
+// Builds and sends a single http request
+func (c *Client) send(ctx context.Context, req request, payload []byte) (*http.Response, error) {
+	endpoint := c.BaseURL + req.path
+	if len(req.query) != 0 {
+		endpoint += "?" + req.query.Encode()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	contentType := "application/json"
+	if req.raw != nil && req.contentType != "" {
+		contentType = req.contentType
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+
+	if req.auth && c.Token != nil {
+		if token := c.Token(); token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	res, err := c.HttpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+
+	return res, nil
+}
+
+// Reads an error response body into an APIError
+func newAPIError(res *http.Response) error {
+	body, _ := io.ReadAll(res.Body)
+
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		Message:    string(body),
+		RequestID:  res.Header.Get("X-Request-Id"),
+
+		PlaidRequestID: res.Header.Get("X-Plaid-Request-Id"),
+	}
+
+	var problem models.Problem
+	if err := json.Unmarshal(body, &problem); err != nil {
+		return apiErr
+	}
+
+	switch {
+	case problem.Detail != "":
+		apiErr.Message = problem.Detail
+	case problem.Error != "":
+		apiErr.Message = problem.Error
+	}
+	apiErr.Code = problem.Code
+	if problem.RequestID != "" {
+		apiErr.RequestID = problem.RequestID
+	}
+	if problem.PlaidRequestID != "" {
+		apiErr.PlaidRequestID = problem.PlaidRequestID
+	}
+
+	return apiErr
+}
+
+// Returns the APIError within err, if there is one
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
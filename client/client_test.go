@@ -0,0 +1,208 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jms-guy/greed/client"
+	"github.com/jms-guy/greed/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientAuthRefresh(t *testing.T) {
+	tests := []struct {
+		name             string
+		withToken        bool
+		refreshedToken   string
+		expectedRefresh  int
+		expectedErr      bool
+		expectedStatus   int
+		expectedMessage  string
+		expectedRequests int
+	}{
+		{
+			name:             "retries with refreshed token after 401",
+			refreshedToken:   "fresh",
+			expectedRefresh:  1,
+			expectedRequests: 2,
+		},
+		{
+			name:             "returns API error when refreshed token is rejected",
+			refreshedToken:   "still-stale",
+			expectedRefresh:  1,
+			expectedErr:      true,
+			expectedStatus:   http.StatusUnauthorized,
+			expectedMessage:  "Token is expired",
+			expectedRequests: 2,
+		},
+		{
+			name:             "fixed token client does not refresh",
+			withToken:        true,
+			expectedRefresh:  0,
+			expectedErr:      true,
+			expectedStatus:   http.StatusUnauthorized,
+			expectedMessage:  "Token is expired",
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.Header.Get("Authorization") != "Bearer fresh" {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"error":"Token is expired"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"items":[{"nickname":"bank","item_id":"1234"}]}`))
+			}))
+			defer server.Close()
+
+			token := "stale"
+			refreshes := 0
+
+			c := client.New(server.URL)
+			c.Token = func() string { return token }
+			c.OnUnauthorized = func() error {
+				refreshes++
+				token = tt.refreshedToken
+				return nil
+			}
+			if tt.withToken {
+				c = c.WithToken("stale")
+			}
+
+			items, err := c.GetItems(context.Background())
+
+			assert.Equal(t, tt.expectedRefresh, refreshes)
+			assert.Equal(t, tt.expectedRequests, requests)
+			if tt.expectedErr {
+				apiErr, ok := client.AsAPIError(err)
+				assert.True(t, ok, "expected an APIError, got %v", err)
+				if ok {
+					assert.Equal(t, tt.expectedStatus, apiErr.StatusCode)
+					assert.Equal(t, tt.expectedMessage, apiErr.Message)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, items, 1)
+			assert.Equal(t, "1234", items[0].ItemId)
+		})
+	}
+}
+
+func TestAPIErrorProblemDetails(t *testing.T) {
+	tests := []struct {
+		name              string
+		body              string
+		expectedMessage   string
+		expectedCode      string
+		expectedRequestID string
+		expectedPlaidID   string
+	}{
+		{
+			name:              "decodes problem details",
+			body:              `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Financial institution requires re-authentication","code":"item_login_required","request_id":"req-1","error":"Financial institution requires re-authentication","plaid_request_id":"plaid-1"}`,
+			expectedMessage:   "Financial institution requires re-authentication",
+			expectedCode:      models.ErrCodeItemLoginRequired,
+			expectedRequestID: "req-1",
+			expectedPlaidID:   "plaid-1",
+		},
+		{
+			name:              "falls back to legacy error body",
+			body:              `{"error":"Item not found"}`,
+			expectedMessage:   "Item not found",
+			expectedRequestID: "req-header",
+		},
+		{
+			name:              "falls back to raw body",
+			body:              "Not found",
+			expectedMessage:   "Not found",
+			expectedRequestID: "req-header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/problem+json")
+				w.Header().Set("X-Request-Id", "req-header")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := client.New(server.URL).Health(context.Background())
+
+			apiErr, ok := client.AsAPIError(err)
+			if !assert.True(t, ok, "expected an APIError, got %v", err) {
+				return
+			}
+			assert.Equal(t, tt.expectedMessage, apiErr.Message)
+			assert.Equal(t, tt.expectedCode, apiErr.Code)
+			assert.Equal(t, tt.expectedRequestID, apiErr.RequestID)
+			assert.Equal(t, tt.expectedPlaidID, apiErr.PlaidRequestID)
+		})
+	}
+}
+
+func TestTransactionAttachmentTransfer(t *testing.T) {
+	stored := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			file, header, err := r.FormFile("file")
+			if !assert.NoError(t, err) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			stored["a1"] = string(data)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"a1","file_name":"` + header.Filename + `"}`))
+		case http.MethodGet:
+			contents, ok := stored[path.Base(r.URL.Path)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"Attachment not found"}`))
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(contents))
+		}
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL).WithToken("token")
+	ctx := context.Background()
+
+	attachment, err := c.AddTransactionAttachment(ctx, "acc", "txn", "receipt.txt", strings.NewReader("Coffee 4.50"))
+	assert.NoError(t, err)
+	assert.Equal(t, "receipt.txt", attachment.FileName)
+
+	var buf bytes.Buffer
+	n, err := c.DownloadTransactionAttachment(ctx, "acc", "txn", attachment.ID, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+	assert.Equal(t, "Coffee 4.50", buf.String())
+
+	buf.Reset()
+	_, err = c.DownloadTransactionAttachment(ctx, "acc", "txn", "missing", &buf)
+	apiErr, ok := client.AsAPIError(err)
+	if assert.True(t, ok, "expected an APIError, got %v", err) {
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "Attachment not found", apiErr.Message)
+	}
+	assert.Zero(t, buf.Len(), "error bodies should not be written as the attachment")
+}
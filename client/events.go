@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/models"
+)
+
+// Returns the user's events after the given sequence number, oldest first. An empty eventType returns
+// events of every type
+func (c *Client) GetEvents(ctx context.Context, after int64, eventType string, limit int) ([]models.Event, error) {
+	query := url.Values{}
+	if after > 0 {
+		query.Set("after", strconv.FormatInt(after, 10))
+	}
+	if eventType != "" {
+		query.Set("type", eventType)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var events []models.Event
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/events", query: query, auth: true}, &events)
+	return events, err
+}
+
+// Returns the user's event endpoints
+func (c *Client) GetEventEndpoints(ctx context.Context) ([]models.EventEndpoint, error) {
+	var endpoints []models.EventEndpoint
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/events/endpoints", auth: true}, &endpoints)
+	return endpoints, err
+}
+
+// Registers an endpoint to receive events. It is returned with its signing secret, which is not shown again
+func (c *Client) CreateEventEndpoint(ctx context.Context, endpoint models.CreateEventEndpoint) (models.EventEndpoint, error) {
+	var created models.EventEndpoint
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/events/endpoints", body: endpoint, auth: true}, &created)
+	return created, err
+}
+
+// Deletes an event endpoint
+func (c *Client) DeleteEventEndpoint(ctx context.Context, endpointID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/events/endpoints/" + endpointID.String(), auth: true}, nil)
+}
+
+// Returns the most recent deliveries to an event endpoint, newest first
+func (c *Client) GetEventDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]models.EventDelivery, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var deliveries []models.EventDelivery
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/events/endpoints/" + endpointID.String() + "/deliveries", query: query, auth: true}, &deliveries)
+	return deliveries, err
+}
+
+// Queues events recorded in a time window to be sent to an endpoint again. Returns the server's message
+// with the number of events queued
+func (c *Client) ReplayEvents(ctx context.Context, endpointID uuid.UUID, replay models.ReplayEvents) (string, error) {
+	var message string
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/events/endpoints/" + endpointID.String() + "/replay", body: replay, auth: true}, &message)
+	return message, err
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/jms-guy/greed/models"
+)
+
+// Returns the user's Plaid items
+func (c *Client) GetItems(ctx context.Context) ([]models.ItemName, error) {
+	var items models.Items
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/items", auth: true}, &items)
+	return items.Items, err
+}
+
+// Returns records of Plaid webhook alerts for the user's items
+func (c *Client) GetWebhookRecords(ctx context.Context) ([]models.WebhookRecord, error) {
+	var records []models.WebhookRecord
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/items/webhook-records", auth: true}, &records)
+	return records, err
+}
+
+// Processes webhook records of a given type, after the user has taken action on them
+func (c *Client) ProcessWebhookRecords(ctx context.Context, req models.ProcessWebhook) error {
+	return c.do(ctx, request{method: http.MethodPut, path: "/api/items/webhook-records", body: req, auth: true}, nil)
+}
+
+// Updates an item's name
+func (c *Client) UpdateItemName(ctx context.Context, itemID string, req models.UpdateItemName) error {
+	return c.do(ctx, request{method: http.MethodPut, path: itemPath(itemID) + "/name", body: req, auth: true}, nil)
+}
+
+// Deletes an item, removing it from Plaid
+func (c *Client) DeleteItem(ctx context.Context, itemID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: itemPath(itemID), auth: true}, nil)
+}
+
+// Returns the accounts belonging to an item
+func (c *Client) GetAccountsForItem(ctx context.Context, itemID string) ([]models.Account, error) {
+	var accounts []models.Account
+	err := c.do(ctx, request{method: http.MethodGet, path: itemPath(itemID) + "/accounts", auth: true}, &accounts)
+	return accounts, err
+}
+
+// Creates account records for an item from Plaid
+func (c *Client) CreateAccounts(ctx context.Context, itemID string) (models.Accounts, error) {
+	var accounts models.Accounts
+	err := c.do(ctx, request{method: http.MethodPost, path: itemPath(itemID) + "/access/accounts", auth: true}, &accounts)
+	return accounts, err
+}
+
+// Updates an item's account records with real-time balances from Plaid
+func (c *Client) UpdateBalances(ctx context.Context, itemID string) (models.Accounts, error) {
+	var accounts models.Accounts
+	err := c.do(ctx, request{method: http.MethodPut, path: itemPath(itemID) + "/access/balances", auth: true}, &accounts)
+	return accounts, err
+}
+
+// Syncs an item's transaction records with Plaid, returning all of the user's transactions
+func (c *Client) SyncTransactions(ctx context.Context, itemID string) ([]models.Transaction, error) {
+	var txns []models.Transaction
+	err := c.do(ctx, request{method: http.MethodPost, path: itemPath(itemID) + "/access/transactions", auth: true}, &txns)
+	return txns, err
+}
+
+// Syncs an item's holdings and investment transactions with Plaid, returning counts of the records written
+func (c *Client) SyncInvestments(ctx context.Context, itemID string) (models.InvestmentSync, error) {
+	var result models.InvestmentSync
+	err := c.do(ctx, request{method: http.MethodPost, path: itemPath(itemID) + "/access/investments", auth: true}, &result)
+	return result, err
+}
+
+// Syncs an item's credit card, student loan and mortgage details with Plaid, returning counts of the liabilities written
+func (c *Client) SyncLiabilities(ctx context.Context, itemID string) (models.LiabilitySync, error) {
+	var result models.LiabilitySync
+	err := c.do(ctx, request{method: http.MethodPost, path: itemPath(itemID) + "/access/liabilities", auth: true}, &result)
+	return result, err
+}
+
+func itemPath(itemID string) string {
+	return "/api/items/" + url.PathEscape(itemID)
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/models"
+)
+
+// Returns the canonical merchants of the user's transactions, with the raw names normalized to each
+func (c *Client) GetMerchants(ctx context.Context) ([]models.Merchant, error) {
+	var merchants []models.Merchant
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/merchants", auth: true}, &merchants)
+	return merchants, err
+}
+
+// Returns the user's merchant aliases
+func (c *Client) GetMerchantAliases(ctx context.Context) ([]models.MerchantAlias, error) {
+	var aliases []models.MerchantAlias
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/merchants/aliases", auth: true}, &aliases)
+	return aliases, err
+}
+
+// Counts transactions with a raw merchant name under merchant, replacing any alias for the same name
+func (c *Client) CreateMerchantAlias(ctx context.Context, rawName, merchant string) (models.MerchantAlias, error) {
+	var alias models.MerchantAlias
+	body := models.CreateMerchantAlias{RawName: rawName, Merchant: merchant}
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/merchants/aliases", body: body, auth: true}, &alias)
+	return alias, err
+}
+
+// Deletes a merchant alias
+func (c *Client) DeleteMerchantAlias(ctx context.Context, aliasID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/merchants/aliases/" + aliasID.String(), auth: true}, nil)
+}
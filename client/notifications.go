@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jms-guy/greed/models"
+)
+
+// Returns the user's notification rules
+func (c *Client) GetNotificationRules(ctx context.Context) ([]models.NotificationRule, error) {
+	var rules []models.NotificationRule
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/notifications/rules", auth: true}, &rules)
+	return rules, err
+}
+
+// Creates a notification rule. Webhook rules are returned with their signing secret, which is not shown again
+func (c *Client) CreateNotificationRule(ctx context.Context, rule models.CreateNotificationRule) (models.NotificationRule, error) {
+	var created models.NotificationRule
+	err := c.do(ctx, request{method: http.MethodPost, path: "/api/notifications/rules", body: rule, auth: true}, &created)
+	return created, err
+}
+
+// Deletes a notification rule
+func (c *Client) DeleteNotificationRule(ctx context.Context, ruleID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/notifications/rules/" + ruleID.String(), auth: true}, nil)
+}
+
+// Returns the user's most recent notification deliveries, newest first
+func (c *Client) GetNotificationDeliveries(ctx context.Context, limit int) ([]models.NotificationDelivery, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var deliveries []models.NotificationDelivery
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/notifications/deliveries", query: query, auth: true}, &deliveries)
+	return deliveries, err
+}
+
+// Queues a failed notification delivery to be sent again
+func (c *Client) RetryNotificationDelivery(ctx context.Context, deliveryID uuid.UUID) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/notifications/deliveries/" + deliveryID.String() + "/retry", auth: true}, nil)
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/jms-guy/greed/models"
+)
+
+// Gets a Link token from Plaid, for connecting a new institution. Empty request fields use the server's defaults
+func (c *Client) GetLinkToken(ctx context.Context, req models.LinkTokenRequest) (models.LinkResponse, error) {
+	var link models.LinkResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/plaid/get-link-token", body: req, auth: true}, &link)
+	return link, err
+}
+
+// Gets a Link token for re-authenticating an item through Link update mode
+func (c *Client) GetLinkTokenForUpdateMode(ctx context.Context, itemID string) (models.LinkResponse, error) {
+	var link models.LinkResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/plaid/get-link-token-update/" + url.PathEscape(itemID), auth: true}, &link)
+	return link, err
+}
+
+// Exchanges a public token received through Plaid Link for an access token, creating a new item
+func (c *Client) GetAccessToken(ctx context.Context, req models.AccessTokenRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/plaid/get-access-token", body: req, auth: true}, nil)
+}
+
+// Returns the URL of the server page that runs the Plaid Link flow for the given Link token
+func (c *Client) LinkURL(linkToken string) string {
+	return c.BaseURL + "/link?token=" + url.QueryEscape(linkToken)
+}
+
+// Returns the URL of the server page that runs Plaid Link in update mode for the given Link token
+func (c *Client) LinkUpdateModeURL(linkToken string) string {
+	return c.BaseURL + "/link-update-mode?token=" + url.QueryEscape(linkToken)
+}
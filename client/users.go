@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jms-guy/greed/models"
+)
+
+// Returns the current user's record
+func (c *Client) GetCurrentUser(ctx context.Context) (models.User, error) {
+	var user models.User
+	err := c.do(ctx, request{method: http.MethodGet, path: "/api/users/me", auth: true}, &user)
+	return user, err
+}
+
+// Deletes the current user, and all of their records
+func (c *Client) DeleteCurrentUser(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/users/me", auth: true}, nil)
+}
+
+// Updates the current user's password. Requires a code sent to the user's email
+func (c *Client) UpdatePassword(ctx context.Context, req models.UpdatePassword) (models.UpdatedPassword, error) {
+	var updated models.UpdatedPassword
+	err := c.do(ctx, request{method: http.MethodPut, path: "/api/users/update-password", body: req, auth: true}, &updated)
+	return updated, err
+}
@@ -0,0 +1,37 @@
+package models
+
+// Stable, machine-readable error codes returned in Problem.Code. Clients should switch on these,
+// rather than on the human-readable detail message
+const (
+	ErrCodeBadRequest             = "bad_request"
+	ErrCodeUnauthorized           = "unauthorized"
+	ErrCodeTokenExpired           = "token_expired"
+	ErrCodeForbidden              = "forbidden"
+	ErrCodeNotFound               = "not_found"
+	ErrCodeRateLimited            = "rate_limited"
+	ErrCodeInternal               = "internal_error"
+	ErrCodeFreeCallsExhausted     = "free_calls_exhausted"
+	ErrCodeItemLoginRequired      = "item_login_required"
+	ErrCodePlaidRateLimited       = "plaid_rate_limited"
+	ErrCodePlaidError             = "plaid_error"
+	ErrCodeAPIKeyExpired          = "api_key_expired"
+	ErrCodeInsufficientScope      = "insufficient_scope"
+	ErrCodeInvestmentsUnavailable = "investments_unavailable"
+	ErrCodeLiabilitiesUnavailable = "liabilities_unavailable"
+	ErrCodeNotManualAccount       = "not_manual_account"
+	ErrCodePayloadTooLarge        = "payload_too_large"
+	ErrCodeUnsupportedMediaType   = "unsupported_media_type"
+)
+
+// RFC 7807 problem details, returned by the server as application/problem+json for every error response
+type Problem struct {
+	Type      string `json:"type"`                 // URI identifying the problem type, documented in docs/errors.md
+	Title     string `json:"title"`                // Short summary of the problem type
+	Status    int    `json:"status"`               // Http status code
+	Detail    string `json:"detail"`               // Explanation specific to this occurrence of the problem
+	Code      string `json:"code"`                 // Stable error code, one of the ErrCode constants
+	RequestID string `json:"request_id,omitempty"` // Server request ID, also sent in the X-Request-Id header
+	Error     string `json:"error"`                // Same as Detail. Kept for clients predating problem details
+
+	PlaidRequestID string `json:"plaid_request_id,omitempty"` // Request ID of the failed Plaid API call, for Plaid errors
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -10,8 +12,15 @@ type UpdateItemName struct {
 }
 
 type AccessTokenRequest struct {
-	PublicToken string `json:"public_token"`
-	Nickname    string `json:"nickname"`
+	PublicToken  string   `json:"public_token"`
+	Nickname     string   `json:"nickname"`
+	CountryCodes []string `json:"country_codes,omitempty"` // Countries the item was linked under, used to look up its institution
+}
+
+// Optional narrowing of the server's Link configuration, empty fields keep the server defaults
+type LinkTokenRequest struct {
+	CountryCodes []string `json:"country_codes,omitempty"`
+	Products     []string `json:"products,omitempty"`
 }
 
 type RefreshRequest struct {
@@ -50,3 +59,86 @@ type ProcessWebhook struct {
 	WebhookCode string `json:"webhook_code"`
 	WebhookType string `json:"webhook_type"`
 }
+
+type UpdateTransactionCategory struct {
+	Category string `json:"category"`
+}
+
+type AddTransactionTag struct {
+	Tag string `json:"tag"`
+}
+
+// Sets a transaction's note, an empty note removes it
+type UpdateTransactionNote struct {
+	Note string `json:"note"`
+}
+
+type CreateNotificationRule struct {
+	Kind       string `json:"kind"`
+	Threshold  string `json:"threshold,omitempty"`
+	Category   string `json:"category,omitempty"`
+	AccountID  string `json:"account_id,omitempty"`
+	Channel    string `json:"channel"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+type CreateEventEndpoint struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+type ReplayEvents struct {
+	Since time.Time  `json:"since"`
+	Until *time.Time `json:"until,omitempty"`
+}
+
+type CreateAPIKey struct {
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`                // read or write, read when left out
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Never expires when left out
+}
+
+// Changes to how an account is shown, fields left out are kept as they are
+type UpdateAccountOverrides struct {
+	Nickname          *string `json:"nickname,omitempty"` // Empty to go back to the account's name
+	Hidden            *bool   `json:"hidden,omitempty"`
+	Archived          *bool   `json:"archived,omitempty"`
+	IncludeInNetWorth *bool   `json:"include_in_net_worth,omitempty"`
+	SortOrder         *int    `json:"sort_order,omitempty"`
+}
+
+// An account tracked by hand, without a linked institution. Balance is the account's balance before
+// any of its transactions, defaulting to 0
+type CreateManualAccount struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"` // depository, credit, loan, investment or other
+	Subtype         string `json:"subtype,omitempty"`
+	IsoCurrencyCode string `json:"iso_currency_code,omitempty"`
+	Balance         string `json:"balance,omitempty"`
+}
+
+// A transaction entered by hand on a manual account. Amounts follow Plaid's convention, positive
+// for money leaving the account and negative for money coming in. Date defaults to today
+type CreateManualTransaction struct {
+	Amount          string `json:"amount"`
+	Date            string `json:"date,omitempty"` // YYYY-MM-DD
+	MerchantName    string `json:"merchant_name,omitempty"`
+	Category        string `json:"category,omitempty"`
+	PaymentChannel  string `json:"payment_channel,omitempty"`
+	IsoCurrencyCode string `json:"iso_currency_code,omitempty"`
+}
+
+// Changes to a manual account's transaction, fields left out are kept as they are
+type UpdateManualTransaction struct {
+	Amount         *string `json:"amount,omitempty"`
+	Date           *string `json:"date,omitempty"`
+	MerchantName   *string `json:"merchant_name,omitempty"` // Empty to remove the merchant
+	Category       *string `json:"category,omitempty"`
+	PaymentChannel *string `json:"payment_channel,omitempty"`
+}
+
+// Counts transactions with a raw merchant name, or any name cleaning up the same, under merchant
+type CreateMerchantAlias struct {
+	RawName  string `json:"raw_name"`
+	Merchant string `json:"merchant"`
+}
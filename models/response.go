@@ -1,15 +1,34 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Health of an item's connection to its institution
+const (
+	ItemHealthOK          = "ok"           // Synced, with no error since
+	ItemHealthExpiring    = "expiring"     // The user's consent for the item runs out soon
+	ItemHealthNeedsReauth = "needs_reauth" // The user has to log in to their institution again
+	ItemHealthError       = "error"        // The last sync failed for another reason
+	ItemHealthUnknown     = "unknown"      // Not synced yet
+)
+
 type ItemName struct {
-	Nickname        string `json:"nickname"`
-	ItemId          string `json:"item_id"`
-	InstitutionName string `json:"institution_name"`
+	Nickname         string     `json:"nickname"`
+	ItemId           string     `json:"item_id"`
+	InstitutionName  string     `json:"institution_name"`
+	Health           string     `json:"health"` // One of the ItemHealth constants
+	LastSyncedAt     *time.Time `json:"last_synced_at,omitempty"`
+	LastErrorCode    string     `json:"last_error_code,omitempty"` // Plaid error or webhook code, such as ITEM_LOGIN_REQUIRED
+	LastErrorAt      *time.Time `json:"last_error_at,omitempty"`
+	ConsentExpiresAt *time.Time `json:"consent_expires_at,omitempty"`
+}
+
+type Items struct {
+	Items []ItemName `json:"items"`
 }
 
 type Accounts struct {
@@ -29,7 +48,16 @@ type Account struct {
 	AvailableBalance string    `json:"available_balance"`
 	CurrentBalance   string    `json:"current_balance"`
 	IsoCurrencyCode  string    `json:"iso_currency_code"`
-	ItemId           string    `json:"item_id"`
+	ItemId           string    `json:"item_id"` // Empty for manual accounts
+	Manual           bool      `json:"manual"`  // Tracked by hand rather than through a linked institution
+
+	// User overrides of how the account is shown
+	Nickname          string `json:"nickname,omitempty"`
+	DisplayName       string `json:"display_name"` // Nickname if the account has one, otherwise its name
+	Hidden            bool   `json:"hidden"`
+	Archived          bool   `json:"archived"`
+	IncludeInNetWorth bool   `json:"include_in_net_worth"`
+	SortOrder         int    `json:"sort_order"`
 }
 
 type Transaction struct {
@@ -43,6 +71,61 @@ type Transaction struct {
 	PersonalFinanceCategory string    `json:"personal_finance_category"`
 }
 
+// A page of changes to a user's transactions since a cursor. Each transaction appears once per page,
+// with its latest change
+type TransactionChanges struct {
+	Added      []Transaction        `json:"added"`
+	Modified   []Transaction        `json:"modified"`
+	Removed    []RemovedTransaction `json:"removed"`
+	NextCursor string               `json:"next_cursor"` // Passed back to get the changes after this page
+	HasMore    bool                 `json:"has_more"`
+}
+
+type RemovedTransaction struct {
+	Id        string `json:"id"`
+	AccountId string `json:"account_id"`
+}
+
+type TransactionTags struct {
+	TransactionID string   `json:"transaction_id"`
+	Tags          []string `json:"tags"`
+}
+
+// A transaction's note. Transactions without one have an empty note
+type TransactionNote struct {
+	TransactionID string    `json:"transaction_id"`
+	Note          string    `json:"note"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// A file attached to a transaction, such as a receipt. The file's contents are downloaded separately
+type TransactionAttachment struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transaction_id"`
+	FileName      string    `json:"file_name"`
+	ContentType   string    `json:"content_type"`
+	SizeBytes     int64     `json:"size_bytes"`
+	SHA256        string    `json:"sha256"` // Hex encoded SHA-256 checksum of the file's contents
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// A canonical merchant transactions are summarized under, with the raw names banks reported that were
+// normalized to it
+type Merchant struct {
+	Name             string   `json:"name"`
+	RawNames         []string `json:"raw_names"`
+	TransactionCount int64    `json:"transaction_count"`
+}
+
+// A user's mapping of a raw merchant name to the merchant it's counted under. It applies to every raw name
+// that cleans up to the same name, ignoring case
+type MerchantAlias struct {
+	ID        uuid.UUID `json:"id"`
+	RawName   string    `json:"raw_name"`
+	Merchant  string    `json:"merchant"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type UpdatedBalance struct {
 	Id               string `json:"id"`
 	AvailableBalance string `json:"available_balance"`
@@ -55,6 +138,7 @@ type AccessResponse struct {
 	AccessToken     string `json:"access_token"`
 	ItemID          string `json:"item_id"`
 	InstitutionName string `json:"institution_name"`
+	CountryCode     string `json:"country_code"`
 	RequestID       string `json:"request_id"` // Request ID returned from Plaid API call
 }
 
@@ -107,6 +191,208 @@ type MerchantSummary struct {
 	Month       string `json:"month"`
 }
 
+// Date range of a comparison report, both dates inclusive
+type ComparisonPeriod struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Net spending for a category or merchant in both compared periods. Percentage is left out
+// when nothing was spent in the period being compared against
+type ComparisonRow struct {
+	Key      string   `json:"key"`
+	Current  string   `json:"current"`
+	Previous string   `json:"previous"`
+	Delta    string   `json:"delta"`
+	DeltaPct *float64 `json:"delta_pct,omitempty"`
+}
+
+// Spending in one period compared against another, by category and by merchant. Account ID
+// is empty for reports covering all of a user's accounts
+type ComparisonReport struct {
+	AccountID  string           `json:"account_id,omitempty"`
+	Period     ComparisonPeriod `json:"period"`
+	Against    ComparisonPeriod `json:"against"`
+	Total      ComparisonRow    `json:"total"`
+	Categories []ComparisonRow  `json:"categories"`
+	Merchants  []ComparisonRow  `json:"merchants"`
+}
+
+// Unusual transaction flagged after a sync. Kind is one of unusual_amount, new_merchant,
+// duplicate_charge or foreign_currency
+type Anomaly struct {
+	ID              uuid.UUID  `json:"id"`
+	TransactionID   string     `json:"transaction_id"`
+	AccountID       string     `json:"account_id"`
+	Kind            string     `json:"kind"`
+	Reason          string     `json:"reason"`
+	Score           float64    `json:"score"`
+	Amount          string     `json:"amount"`
+	IsoCurrencyCode string     `json:"iso_currency_code"`
+	Date            time.Time  `json:"date"`
+	MerchantName    string     `json:"merchant_name"`
+	CreatedAt       time.Time  `json:"created_at"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+type NotificationRule struct {
+	ID            uuid.UUID `json:"id"`
+	Kind          string    `json:"kind"`
+	Threshold     string    `json:"threshold,omitempty"`
+	Category      string    `json:"category,omitempty"`
+	AccountID     string    `json:"account_id,omitempty"`
+	Channel       string    `json:"channel"`
+	WebhookURL    string    `json:"webhook_url,omitempty"`
+	WebhookSecret string    `json:"webhook_secret,omitempty"` // Only returned when the rule is created
+	Enabled       bool      `json:"enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type NotificationDelivery struct {
+	ID            uuid.UUID  `json:"id"`
+	RuleID        uuid.UUID  `json:"rule_id"`
+	Kind          string     `json:"kind"`
+	Channel       string     `json:"channel"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+type Event struct {
+	ID        uuid.UUID       `json:"id"`
+	Seq       int64           `json:"seq"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type EventEndpoint struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"` // Only returned when the endpoint is created
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type EventDelivery struct {
+	ID            uuid.UUID  `json:"id"`
+	EventID       uuid.UUID  `json:"event_id"`
+	Seq           int64      `json:"seq"`
+	Type          string     `json:"type"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	Key        string     `json:"key,omitempty"` // Only returned when the key is created
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Position in a security held by an investment account. Cost basis and unrealized gain are
+// left out when the institution does not report a cost basis
+type Holding struct {
+	SecurityID      string    `json:"security_id"`
+	Name            string    `json:"name"`
+	TickerSymbol    string    `json:"ticker_symbol,omitempty"`
+	Type            string    `json:"type,omitempty"`
+	Quantity        string    `json:"quantity"`
+	Price           string    `json:"price"`
+	Value           string    `json:"value"`
+	CostBasis       string    `json:"cost_basis,omitempty"`
+	UnrealizedGain  string    `json:"unrealized_gain,omitempty"`
+	IsoCurrencyCode string    `json:"iso_currency_code"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Holdings of an investment account with their totals. Cost basis and unrealized gain totals
+// only cover holdings that report a cost basis
+type Holdings struct {
+	AccountID      string    `json:"account_id"`
+	Holdings       []Holding `json:"holdings"`
+	TotalValue     string    `json:"total_value"`
+	TotalCostBasis string    `json:"total_cost_basis"`
+	UnrealizedGain string    `json:"unrealized_gain"`
+}
+
+// Buy, sell, dividend, fee or transfer in an investment account. Security is empty for
+// transactions like cash transfers that do not involve one
+type InvestmentTransaction struct {
+	ID              string    `json:"id"`
+	AccountID       string    `json:"account_id"`
+	SecurityID      string    `json:"security_id,omitempty"`
+	TickerSymbol    string    `json:"ticker_symbol,omitempty"`
+	Date            time.Time `json:"date"`
+	Name            string    `json:"name"`
+	Type            string    `json:"type"`
+	Subtype         string    `json:"subtype"`
+	Quantity        string    `json:"quantity"`
+	Price           string    `json:"price"`
+	Amount          string    `json:"amount"`
+	Fees            string    `json:"fees,omitempty"`
+	IsoCurrencyCode string    `json:"iso_currency_code"`
+}
+
+// Counts of records written by an item's investments sync
+type InvestmentSync struct {
+	Securities   int `json:"securities"`
+	Holdings     int `json:"holdings"`
+	Transactions int `json:"transactions"`
+}
+
+// Interest rate charged on part of a credit card balance, such as purchases or cash advances
+type LiabilityAPR struct {
+	Type                string `json:"type"`
+	Percentage          string `json:"percentage"`
+	BalanceSubjectToAPR string `json:"balance_subject_to_apr,omitempty"`
+	InterestCharged     string `json:"interest_charged,omitempty"`
+}
+
+// Payment and interest details of a credit card, student loan or mortgage account. Optional fields
+// are empty when the institution doesn't report them, and dates are formatted YYYY-MM-DD
+type Liability struct {
+	AccountID            string         `json:"account_id"`
+	Type                 string         `json:"type"`          // credit, student or mortgage
+	Balance              string         `json:"balance"`       // Account's current balance
+	InterestRate         string         `json:"interest_rate"` // Annual percentage, the purchase APR for credit cards
+	InterestRateType     string         `json:"interest_rate_type,omitempty"`
+	MinimumPayment       string         `json:"minimum_payment,omitempty"`
+	NextPaymentDueDate   string         `json:"next_payment_due_date,omitempty"`
+	LastStatementBalance string         `json:"last_statement_balance,omitempty"`
+	LastStatementDate    string         `json:"last_statement_date,omitempty"`
+	LastPaymentAmount    string         `json:"last_payment_amount,omitempty"`
+	LastPaymentDate      string         `json:"last_payment_date,omitempty"`
+	IsOverdue            bool           `json:"is_overdue"`
+	OriginationPrincipal string         `json:"origination_principal,omitempty"`
+	OriginationDate      string         `json:"origination_date,omitempty"`
+	MaturityDate         string         `json:"maturity_date,omitempty"`
+	LoanTerm             string         `json:"loan_term,omitempty"`
+	LoanName             string         `json:"loan_name,omitempty"`
+	APRs                 []LiabilityAPR `json:"aprs"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+}
+
+// Counts of records written by an item's liabilities sync
+type LiabilitySync struct {
+	Credit   int `json:"credit"`
+	Student  int `json:"student"`
+	Mortgage int `json:"mortgage"`
+}
+
 type WebhookRecord struct {
 	WebhookType string    `json:"webhook_type"`
 	WebhookCode string    `json:"webhook_code"`